@@ -2,15 +2,20 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 )
 
@@ -19,45 +24,99 @@ type WorkspaceCredentials struct {
 	Site  string // e.g., "https://eso.atlassian.net/wiki"
 	Email string // e.g., "service@eso.com"
 	Token string // Atlassian API token
+
+	// AccessToken is the caller's OAuth access token, which replaces Email and Token. Site is
+	// then the site's API gateway URL, e.g. https://api.atlassian.com/ex/confluence/{cloudId}/wiki.
+	AccessToken string
+
+	ProxyURL    string // Proxy for this workspace's calls; empty follows HTTPS_PROXY
+	WorkspaceID string // Workspace whose rate limit budget the calls share; empty is untracked
 }
 
 // Client wraps HTTP client with Atlassian auth
 type Client struct {
 	creds      WorkspaceCredentials
 	httpClient *http.Client
+	usage      *atlassian.CountingTransport // Bytes exchanged with Atlassian, for usage metering
+	ctx        context.Context              // Cancels in-flight calls once the caller's deadline passes
+	dryRun     *atlassian.DryRunTransport   // Set by WithDryRun: changes are recorded, not sent
 }
 
-// Shared HTTP client with connection pooling
-var sharedHTTPClient = &http.Client{
-	Timeout: 30 * time.Second,
-	Transport: &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-		DisableKeepAlives:   false,
-	},
-}
+// defaultTimeout applies when NewClient is given no timeout
+const defaultTimeout = 30 * time.Second
 
 // NewClient creates an authenticated Confluence client
 func NewClient(creds WorkspaceCredentials, timeout time.Duration) *Client {
-	// Use a dedicated client if a specific timeout is requested, 
-	// otherwise use the shared one.
-	client := sharedHTTPClient
-	if timeout > 0 && timeout != sharedHTTPClient.Timeout {
-		client = &http.Client{
-			Timeout:   timeout,
-			Transport: sharedHTTPClient.Transport,
-		}
+	if timeout <= 0 {
+		timeout = defaultTimeout
 	}
 
+	// Each client counts its own traffic but shares the pooled transport of its proxy, and
+	// the rate limit budget of its workspace
+	usage := atlassian.NewCountingTransport(atlassian.NewRateLimitTransport(creds.WorkspaceID, atlassian.Transport(creds.ProxyURL)), creds.Site)
+
 	return &Client{
-		creds:      creds,
-		httpClient: client,
+		creds: creds,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: usage,
+		},
+		usage: usage,
+	}
+}
+
+// WithContext makes every call made by the client stop when ctx is done
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c.ctx = ctx
+	return c
+}
+
+// WithDryRun makes the client record the changes it would make instead of sending them.
+// Reads still reach Atlassian, so lookups the changes depend on are real.
+func (c *Client) WithDryRun() *Client {
+	c.dryRun = atlassian.NewDryRunTransport(c.usage)
+	c.httpClient.Transport = c.dryRun
+	return c
+}
+
+// DryRunRequests returns the changes a dry-run client recorded
+func (c *Client) DryRunRequests() []atlassian.DryRunRequest {
+	if c.dryRun == nil {
+		return nil
 	}
+	return c.dryRun.Requests()
 }
 
-// authHeader returns the Basic auth header value
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// BytesTransferred returns the request and response bytes exchanged with Atlassian by this client
+func (c *Client) BytesTransferred() int64 {
+	return c.usage.Bytes()
+}
+
+// CredentialsRejected reports whether the site answered any of this client's requests with
+// 401 Unauthorized, i.e. the workspace's API token is invalid, expired or revoked. A
+// caller's own OAuth token being rejected says nothing about the workspace's.
+func (c *Client) CredentialsRejected() bool {
+	return c.creds.AccessToken == "" && c.usage.Unauthorized()
+}
+
+// FailedStatus returns the HTTP status of the last request Atlassian rejected or failed,
+// or 0 when all succeeded
+func (c *Client) FailedStatus() int {
+	return c.usage.FailedStatus()
+}
+
+// authHeader returns the Basic auth header value, or the Bearer one for an OAuth token
 func (c *Client) authHeader() string {
+	if c.creds.AccessToken != "" {
+		return "Bearer " + c.creds.AccessToken
+	}
 	credentials := fmt.Sprintf("%s:%s", c.creds.Email, c.creds.Token)
 	encoded := base64.StdEncoding.EncodeToString([]byte(credentials))
 	return "Basic " + encoded
@@ -68,7 +127,100 @@ func (c *Client) GetPage(pageID string) (*models.ConfluencePage, error) {
 	url := fmt.Sprintf("%s/rest/api/content/%s?expand=body.storage,version",
 		c.creds.Site, pageID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get page %s: %s", pageID, string(body))
+	}
+
+	var page models.ConfluencePage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// GetPageVersion returns a page's current version number without downloading its body
+func (c *Client) GetPageVersion(pageID string) (int, error) {
+	url := fmt.Sprintf("%s/rest/api/content/%s?expand=version",
+		c.creds.Site, pageID)
+
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("failed to get version of page %s: %s", pageID, string(body))
+	}
+
+	var page models.ConfluencePage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return 0, err
+	}
+
+	return page.Version.Number, nil
+}
+
+// GetPageSpaceKey returns the key of the space that contains a page
+func (c *Client) GetPageSpaceKey(pageID string) (string, error) {
+	url := fmt.Sprintf("%s/rest/api/content/%s?expand=space",
+		c.creds.Site, pageID)
+
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get space of page %s: %s", pageID, string(body))
+	}
+
+	var page models.ConfluencePage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return "", err
+	}
+
+	return page.Space.Key, nil
+}
+
+// GetPageLocation fetches a page with its space, ancestors and links, without its body
+func (c *Client) GetPageLocation(pageID string) (*models.ConfluencePage, error) {
+	url := fmt.Sprintf("%s/rest/api/content/%s?expand=space,ancestors",
+		c.creds.Site, pageID)
+
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -94,12 +246,44 @@ func (c *Client) GetPage(pageID string) (*models.ConfluencePage, error) {
 	return &page, nil
 }
 
+// GetReadRestriction returns the users and groups allowed to view a page by its own
+// view restriction; restrictions of its ancestors are not included
+func (c *Client) GetReadRestriction(pageID string) (*models.ReadRestriction, error) {
+	url := fmt.Sprintf("%s/rest/api/content/%s/restriction/byOperation/read?expand=restrictions.user,restrictions.group",
+		c.creds.Site, pageID)
+
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get view restrictions of page %s: %s", pageID, string(body))
+	}
+
+	var restriction models.ReadRestriction
+	if err := json.NewDecoder(resp.Body).Decode(&restriction); err != nil {
+		return nil, err
+	}
+
+	return &restriction, nil
+}
+
 // GetChildren returns all direct child pages of a parent page
 func (c *Client) GetChildren(pageID string) ([]models.ConfluencePage, error) {
 	url := fmt.Sprintf("%s/rest/api/content/%s/child/page?expand=version",
 		c.creds.Site, pageID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +336,7 @@ func (c *Client) CreatePage(spaceKey, title, body string, parentID *string) (*mo
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -179,12 +363,19 @@ func (c *Client) CreatePage(spaceKey, title, body string, parentID *string) (*mo
 	return &page, nil
 }
 
-// SearchPages searches for pages using CQL
-func (c *Client) SearchPages(cql string, limit int) (*models.SearchResults, error) {
+// SearchPages searches for pages using CQL, starting at the start'th result and
+// expanding the given properties (e.g. space, version) of each
+func (c *Client) SearchPages(cql string, limit, start int, expand []string) (*models.SearchResults, error) {
 	url := fmt.Sprintf("%s/rest/api/content/search?cql=%s&limit=%d",
-		c.creds.Site, cql, limit)
+		c.creds.Site, url.QueryEscape(cql), limit)
+	if start > 0 {
+		url += fmt.Sprintf("&start=%d", start)
+	}
+	if len(expand) > 0 {
+		url += "&expand=" + strings.Join(expand, ",")
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -214,7 +405,7 @@ func (c *Client) SearchPages(cql string, limit int) (*models.SearchResults, erro
 func (c *Client) ListSpaces(limit int) ([]models.ConfluenceSpace, error) {
 	url := fmt.Sprintf("%s/rest/api/space?limit=%d", c.creds.Site, limit)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +437,7 @@ func (c *Client) ListSpaces(limit int) ([]models.ConfluenceSpace, error) {
 func (c *Client) GetSpace(spaceKey string) (*models.ConfluenceSpace, error) {
 	url := fmt.Sprintf("%s/rest/api/space/%s", c.creds.Site, spaceKey)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -272,6 +463,40 @@ func (c *Client) GetSpace(spaceKey string) (*models.ConfluenceSpace, error) {
 	return &space, nil
 }
 
+// GetSpaceHomepageID returns the ID of a space's home page, or "" if it has none
+func (c *Client) GetSpaceHomepageID(spaceKey string) (string, error) {
+	url := fmt.Sprintf("%s/rest/api/space/%s?expand=homepage", c.creds.Site, spaceKey)
+
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get space %s: %s", spaceKey, string(body))
+	}
+
+	var space struct {
+		Homepage *models.AncestorRef `json:"homepage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&space); err != nil {
+		return "", err
+	}
+	if space.Homepage == nil {
+		return "", nil
+	}
+	return space.Homepage.ID, nil
+}
+
 // UpdatePage updates an existing page
 func (c *Client) UpdatePage(pageID, title, body string, version int) (*models.ConfluencePage, error) {
 	url := fmt.Sprintf("%s/rest/api/content/%s", c.creds.Site, pageID)
@@ -295,7 +520,7 @@ func (c *Client) UpdatePage(pageID, title, body string, version int) (*models.Co
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -326,7 +551,7 @@ func (c *Client) UpdatePage(pageID, title, body string, version int) (*models.Co
 func (c *Client) DeletePage(pageID string) error {
 	url := fmt.Sprintf("%s/rest/api/content/%s", c.creds.Site, pageID)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -346,12 +571,78 @@ func (c *Client) DeletePage(pageID string) error {
 	return nil
 }
 
+// RestorePage restores a deleted page from the space's trash, as a new version
+func (c *Client) RestorePage(pageID string) (*models.ConfluencePage, error) {
+	var trashed models.ConfluencePage
+	url := fmt.Sprintf("%s/rest/api/content/%s?status=trashed&expand=version", c.creds.Site, pageID)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("page %s is not in the trash: %s", pageID, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&trashed); err != nil {
+		return nil, err
+	}
+
+	// Updating a trashed page to status current takes it out of the trash
+	payload := map[string]interface{}{
+		"version": map[string]interface{}{
+			"number": trashed.Version.Number + 1,
+		},
+		"title":  trashed.Title,
+		"type":   "page",
+		"status": "current",
+	}
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	url = fmt.Sprintf("%s/rest/api/content/%s", c.creds.Site, pageID)
+	req, err = http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewReader(jsonPayload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	restoreResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer restoreResp.Body.Close()
+
+	if restoreResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(restoreResp.Body)
+		return nil, fmt.Errorf("failed to restore page %s: %s", pageID, string(body))
+	}
+
+	var page models.ConfluencePage
+	if err := json.NewDecoder(restoreResp.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
 // GetPageChildren gets child pages (wrapper around GetChildren for consistency)
 func (c *Client) GetPageChildren(pageID string, limit int) ([]models.ConfluencePage, error) {
 	url := fmt.Sprintf("%s/rest/api/content/%s/child/page?limit=%d&expand=version",
 		c.creds.Site, pageID, limit)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -402,7 +693,7 @@ func (c *Client) AddComment(pageID, body string) (map[string]interface{}, error)
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -434,7 +725,7 @@ func (c *Client) GetComments(pageID string, limit int) ([]map[string]interface{}
 	url := fmt.Sprintf("%s/rest/api/content/%s/child/comment?limit=%d&expand=body.storage",
 		c.creds.Site, pageID, limit)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -478,7 +769,7 @@ func (c *Client) AddLabel(pageID, label string) (map[string]interface{}, error)
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -509,7 +800,7 @@ func (c *Client) AddLabel(pageID, label string) (map[string]interface{}, error)
 func (c *Client) GetLabels(pageID string) ([]map[string]interface{}, error) {
 	url := fmt.Sprintf("%s/rest/api/content/%s/label", c.creds.Site, pageID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -548,9 +839,9 @@ func (c *Client) SearchUser(query string) ([]models.ConfluenceUser, error) {
 	url := fmt.Sprintf("%s/rest/api/search/user?cql=%s", 
 		baseURL, url.QueryEscape(cql))
 
-	fmt.Printf("DEBUG: Confluence SearchUser URL: %s\n", url)
+	slog.Debug("confluence user search", "url", url)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -566,12 +857,10 @@ func (c *Client) SearchUser(query string) ([]models.ConfluenceUser, error) {
 	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("DEBUG: Confluence SearchUser Error Response: %s\n", string(body))
+		slog.Debug("confluence user search failed", "status", resp.StatusCode, "bytes", len(body))
 		return nil, fmt.Errorf("failed to search user: %s", string(body))
 	}
 
-	fmt.Printf("DEBUG: Confluence SearchUser Success Response: %s\n", string(body))
-
 	var searchResp models.UserSearchResults
 	if err := json.Unmarshal(body, &searchResp); err != nil {
 		return nil, err
@@ -590,7 +879,7 @@ func (c *Client) GetAttachments(pageID string, limit int) ([]map[string]interfac
 	url := fmt.Sprintf("%s/rest/api/content/%s/child/attachment?limit=%d",
 		c.creds.Site, pageID, limit)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -618,3 +907,61 @@ func (c *Client) GetAttachments(pageID string, limit int) ([]map[string]interfac
 	return result.Results, nil
 }
 
+// UploadAttachment attaches a file to a page, adding a new version of the attachment
+// when the page already has one with the same file name
+func (c *Client) UploadAttachment(pageID, fileName, contentType string, data []byte) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/rest/api/content/%s/child/attachment", c.creds.Site, pageID)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, fileName))
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+	// Minor edits do not notify the page's watchers
+	if err := form.WriteField("minorEdit", "true"); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	// PUT creates the attachment or updates the one with the same name
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to upload attachment %s: %s", fileName, string(body))
+	}
+
+	var result struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.Results) == 0 {
+		return nil, fmt.Errorf("failed to upload attachment %s: no attachment returned", fileName)
+	}
+
+	return result.Results[0], nil
+}
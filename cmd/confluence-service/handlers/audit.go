@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	defaultStaleMonths     = 12
+	maxStaleMonths         = 120
+	defaultTitleSimilarity = 0.85
+	defaultAuditLimit      = 100
+	maxAuditLimit          = 500
+	// maxAuditPages bounds how many pages of a space are audited
+	maxAuditPages = 2000
+	// auditListPageSize is how many pages each listing request returns
+	auditListPageSize = 100
+	// auditBodyBatchSize is how many page bodies each request fetches; Confluence returns
+	// fewer results per request when their bodies are expanded
+	auditBodyBatchSize = 25
+	// auditConcurrency is how many body requests run at once
+	auditConcurrency = 4
+)
+
+var (
+	// spaceKeyPattern is what a space key may look like; personal spaces start with ~
+	spaceKeyPattern = regexp.MustCompile(`^~?[A-Za-z0-9_]+$`)
+	// auditPageLinkPattern matches links to pages by title (<ri:page ri:content-title="...">)
+	auditPageLinkPattern = regexp.MustCompile(`<ri:page\s([^>]*)>`)
+	// auditAttributePattern matches the attributes of a storage format element
+	auditAttributePattern = regexp.MustCompile(`([a-z:-]+)="([^"]*)"`)
+	// auditHrefPattern matches links to pages by ID, e.g. /wiki/spaces/DOCS/pages/123 or ?pageId=123
+	auditHrefPattern = regexp.MustCompile(`href="[^"]*(?:/pages/|pageId=)(\d+)`)
+)
+
+// auditPage is one page in an audit report
+type auditPage struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	URL         string `json:"url,omitempty"`
+	LastUpdated string `json:"last_updated,omitempty"`
+	// MonthsSinceUpdate is set on stale pages
+	MonthsSinceUpdate int `json:"months_since_update,omitempty"`
+}
+
+// auditTitleGroup is a set of pages whose titles are nearly the same
+type auditTitleGroup struct {
+	Similarity float64     `json:"similarity"` // Of the most similar pair
+	Pages      []auditPage `json:"pages"`
+}
+
+// handleContentAudit reports a space's pages that may need cleaning up: pages not updated
+// in stale_months, pages with nearly the same title, and orphaned pages, which are not
+// children of another page and that no other page in the space links to
+func (s *Service) handleContentAudit(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+	spaceKey, _ := req.Params["space_key"].(string)
+	if spaceKey == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing space_key", req.RequestID)
+	}
+	if !spaceKeyPattern.MatchString(spaceKey) {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, fmt.Sprintf("invalid space_key: %s", spaceKey), req.RequestID)
+	}
+
+	staleMonths := defaultStaleMonths
+	if m, ok := req.Params["stale_months"].(float64); ok && m >= 1 {
+		staleMonths = min(int(m), maxStaleMonths)
+	}
+	threshold := defaultTitleSimilarity
+	if t, ok := req.Params["title_similarity"].(float64); ok && t > 0 && t <= 1 {
+		threshold = t
+	}
+	limit := defaultAuditLimit
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = min(int(l), maxAuditLimit)
+	}
+
+	pages, base, truncated, err := listSpacePages(client, spaceKey)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	homepageID, err := client.GetSpaceHomepageID(spaceKey)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	bodies, err := fetchPageBodies(client, pages)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	now := time.Now().UTC()
+	staleBefore := now.AddDate(0, -staleMonths, 0)
+	entry := func(page models.ConfluencePage) auditPage {
+		p := auditPage{ID: page.ID, Title: page.Title, LastUpdated: page.Version.When}
+		if page.Links.WebUI != "" && base != "" {
+			p.URL = base + page.Links.WebUI
+		}
+		return p
+	}
+
+	// Stale pages, oldest first
+	type stalePage struct {
+		page    auditPage
+		updated time.Time
+	}
+	var stale []stalePage
+	for _, page := range pages {
+		updated, err := time.Parse(time.RFC3339, page.Version.When)
+		if err != nil || !updated.Before(staleBefore) {
+			continue
+		}
+		p := entry(page)
+		p.MonthsSinceUpdate = monthsBetween(updated, now)
+		stale = append(stale, stalePage{page: p, updated: updated})
+	}
+	sort.SliceStable(stale, func(i, j int) bool { return stale[i].updated.Before(stale[j].updated) })
+	stalePages := []auditPage{}
+	for _, p := range stale {
+		stalePages = append(stalePages, p.page)
+	}
+
+	// Orphaned pages: top-level pages, other than the home page, without incoming links
+	incoming := countIncomingLinks(pages, bodies, spaceKey)
+	orphaned := []auditPage{}
+	for _, page := range pages {
+		if len(page.Ancestors) == 0 && page.ID != homepageID && incoming[page.ID] == 0 {
+			orphaned = append(orphaned, entry(page))
+		}
+	}
+
+	titleGroups := similarTitleGroups(pages, threshold)
+	duplicateGroups := len(titleGroups)
+	groups := []auditTitleGroup{}
+	for _, group := range titleGroups {
+		if len(groups) == limit {
+			break
+		}
+		g := auditTitleGroup{Similarity: group.similarity}
+		for _, i := range group.members {
+			g.Pages = append(g.Pages, entry(pages[i]))
+		}
+		groups = append(groups, g)
+	}
+
+	return models.SuccessResponse(map[string]interface{}{
+		"space_key":     spaceKey,
+		"pages_audited": len(pages),
+		"truncated":     truncated,
+		"stale": map[string]interface{}{
+			"months": staleMonths,
+			"before": staleBefore.Format("2006-01-02"),
+			"total":  len(stalePages),
+			"pages":  firstAuditPages(stalePages, limit),
+		},
+		"duplicate_titles": map[string]interface{}{
+			"threshold": threshold,
+			"total":     duplicateGroups,
+			"groups":    groups,
+		},
+		"orphaned": map[string]interface{}{
+			"total": len(orphaned),
+			"pages": firstAuditPages(orphaned, limit),
+		},
+	}, req.RequestID)
+}
+
+// listSpacePages lists the pages of a space with their version and ancestors, and returns
+// the site URL their links are relative to. truncated is set when the space has more than
+// maxAuditPages pages.
+func listSpacePages(client *api.Client, spaceKey string) ([]models.ConfluencePage, string, bool, error) {
+	cql := fmt.Sprintf("space = %q AND type = page ORDER BY created ASC", spaceKey)
+	var pages []models.ConfluencePage
+	base := ""
+	for start := 0; ; {
+		results, err := client.SearchPages(cql, auditListPageSize, start, []string{"version", "ancestors"})
+		if err != nil {
+			return nil, "", false, err
+		}
+		if results.Links.Base != "" {
+			base = results.Links.Base
+		}
+		pages = append(pages, results.Results...)
+		if len(pages) >= maxAuditPages {
+			return pages[:maxAuditPages], base, len(pages) > maxAuditPages || results.Links.Next != "", nil
+		}
+		if results.Links.Next == "" || len(results.Results) == 0 {
+			return pages, base, false, nil
+		}
+		start += len(results.Results)
+	}
+}
+
+// fetchPageBodies fetches the storage format of the pages, several batches at a time, and
+// returns them by page ID
+func fetchPageBodies(client *api.Client, pages []models.ConfluencePage) (map[string]string, error) {
+	batches := make(chan []string)
+	go func() {
+		defer close(batches)
+		for i := 0; i < len(pages); i += auditBodyBatchSize {
+			end := min(i+auditBodyBatchSize, len(pages))
+			ids := make([]string, 0, end-i)
+			for _, page := range pages[i:end] {
+				ids = append(ids, page.ID)
+			}
+			batches <- ids
+		}
+	}()
+
+	bodies := make(map[string]string, len(pages))
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	for w := 0; w < auditConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ids := range batches {
+				mu.Lock()
+				failed := firstErr != nil
+				mu.Unlock()
+				if failed {
+					continue // Drain the remaining batches
+				}
+
+				cql := fmt.Sprintf("id in (%s)", strings.Join(ids, ","))
+				results, err := client.SearchPages(cql, len(ids), 0, []string{"body.storage"})
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					for _, page := range results.Results {
+						bodies[page.ID] = page.Body.Storage.Value
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return bodies, firstErr
+}
+
+// countIncomingLinks counts, for each page, the other pages of the space that link to it
+// by title or by URL
+func countIncomingLinks(pages []models.ConfluencePage, bodies map[string]string, spaceKey string) map[string]int {
+	byTitle := make(map[string]string, len(pages))
+	for _, page := range pages {
+		byTitle[page.Title] = page.ID
+	}
+
+	incoming := make(map[string]int)
+	for _, page := range pages {
+		body := bodies[page.ID]
+		targets := make(map[string]bool)
+		for _, link := range parsePageLinks(body) {
+			if link.ID != "" {
+				targets[link.ID] = true
+				continue
+			}
+			// Links without a space key point into the linking page's space
+			if link.SpaceKey != "" && !strings.EqualFold(link.SpaceKey, spaceKey) {
+				continue
+			}
+			if id, ok := byTitle[link.Title]; ok {
+				targets[id] = true
+			}
+		}
+		for id := range targets {
+			if id != page.ID {
+				incoming[id]++
+			}
+		}
+	}
+	return incoming
+}
+
+// titleGroup is a set of pages, by index, whose titles are nearly the same
+type titleGroup struct {
+	members    []int
+	similarity float64
+}
+
+// similarTitleGroups groups pages whose normalized titles have a bigram similarity of at
+// least threshold. Similarity is transitive within a group, so a group may hold titles
+// that are only similar through a third one.
+func similarTitleGroups(pages []models.ConfluencePage, threshold float64) []titleGroup {
+	bigrams := make([]map[string]int, len(pages))
+	for i, page := range pages {
+		bigrams[i] = titleBigrams(normalizeTitle(page.Title))
+	}
+
+	parent := make([]int, len(pages))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	best := make(map[int]float64)
+	for i := range pages {
+		for j := i + 1; j < len(pages); j++ {
+			similarity := bigramSimilarity(bigrams[i], bigrams[j])
+			if similarity < threshold {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			parent[rj] = ri
+			best[ri] = math.Max(math.Max(best[ri], best[rj]), similarity)
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range pages {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+	var groups []titleGroup
+	for _, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		// best was recorded under whichever root the group had at the time
+		similarity := 0.0
+		for _, i := range group {
+			similarity = math.Max(similarity, best[i])
+		}
+		groups = append(groups, titleGroup{members: group, similarity: math.Round(similarity*100) / 100})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].similarity != groups[j].similarity {
+			return groups[i].similarity > groups[j].similarity
+		}
+		return groups[i].members[0] < groups[j].members[0]
+	})
+	return groups
+}
+
+// normalizeTitle lowercases a title and reduces it to words, dropping a "Copy of" prefix
+func normalizeTitle(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 2 && words[0] == "copy" && words[1] == "of" {
+		words = words[2:]
+	}
+	return strings.Join(words, " ")
+}
+
+// titleBigrams counts the character pairs of a title
+func titleBigrams(title string) map[string]int {
+	runes := []rune(title)
+	bigrams := make(map[string]int)
+	for i := 0; i+1 < len(runes); i++ {
+		bigrams[string(runes[i:i+2])]++
+	}
+	return bigrams
+}
+
+// bigramSimilarity is the Dice coefficient of two bigram multisets, from 0 to 1
+func bigramSimilarity(a, b map[string]int) float64 {
+	total := 0
+	for _, n := range a {
+		total += n
+	}
+	for _, n := range b {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	shared := 0
+	for bigram, n := range a {
+		shared += min(n, b[bigram])
+	}
+	return 2 * float64(shared) / float64(total)
+}
+
+// monthsBetween counts the whole months from one time to a later one
+func monthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	return months
+}
+
+func firstAuditPages(pages []auditPage, limit int) []auditPage {
+	if len(pages) > limit {
+		return pages[:limit]
+	}
+	return pages
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	// maxDiagramSource bounds the diagram source sent for rendering
+	maxDiagramSource = 100 * 1024
+	// maxDiagramImage bounds the rendered image attached to the page
+	maxDiagramImage = 10 * 1024 * 1024
+)
+
+// diagramLanguages maps the languages insert_diagram accepts to Kroki diagram types
+var diagramLanguages = map[string]string{
+	"mermaid":  "mermaid",
+	"plantuml": "plantuml",
+}
+
+// diagramContentTypes maps the image formats insert_diagram renders to MIME types
+var diagramContentTypes = map[string]string{
+	"svg": "image/svg+xml",
+	"png": "image/png",
+}
+
+// diagramFileNamePattern keeps attachment names to characters that need no escaping in
+// storage format or URLs
+var diagramFileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,99}$`)
+
+// krokiURL is the Kroki server that renders diagrams, or "" when KROKI_URL is not set.
+// The diagram source is sent to it, so rendering is off until an operator picks a server.
+func krokiURL() string {
+	return strings.TrimSuffix(os.Getenv("KROKI_URL"), "/")
+}
+
+// renderDiagram renders diagram source to an image with Kroki
+func (s *Service) renderDiagram(ctx context.Context, kroki, language, format, source string) ([]byte, error) {
+	url := fmt.Sprintf("%s/%s/%s", kroki, diagramLanguages[language], format)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(source))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Accept", diagramContentTypes[format])
+
+	client := &http.Client{Timeout: s.apiTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render diagram: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("failed to render diagram: %s", strings.TrimSpace(string(body)))
+	}
+
+	image, err := io.ReadAll(io.LimitReader(resp.Body, maxDiagramImage+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(image) > maxDiagramImage {
+		return nil, fmt.Errorf("rendered diagram is larger than %d bytes", maxDiagramImage)
+	}
+	return image, nil
+}
+
+// handleInsertDiagram renders Mermaid or PlantUML source, attaches the image to a page
+// and embeds it in the page body. Inserting a diagram under a file name the page already
+// shows replaces the image without editing the body again.
+func (s *Service) handleInsertDiagram(ctx context.Context, client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+	kroki := krokiURL()
+	if kroki == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			"diagram rendering is not configured; set KROKI_URL on the Confluence service", req.RequestID)
+	}
+
+	pageID, ok := req.Params["page_id"].(string)
+	if !ok || pageID == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing page_id", req.RequestID)
+	}
+
+	source, ok := req.Params["source"].(string)
+	if !ok || strings.TrimSpace(source) == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing source", req.RequestID)
+	}
+	if len(source) > maxDiagramSource {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("source must be at most %d bytes", maxDiagramSource), req.RequestID)
+	}
+
+	language, _ := req.Params["language"].(string)
+	if _, ok := diagramLanguages[language]; !ok {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "language must be mermaid or plantuml", req.RequestID)
+	}
+
+	format := "svg"
+	if f, ok := req.Params["format"].(string); ok && f != "" {
+		format = f
+	}
+	contentType, ok := diagramContentTypes[format]
+	if !ok {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "format must be svg or png", req.RequestID)
+	}
+
+	// Unnamed diagrams are named after their source, so re-inserting one is a no-op
+	sum := sha256.Sum256([]byte(language + "\n" + source))
+	fileName := "diagram-" + hex.EncodeToString(sum[:])[:12] + "." + format
+	if name, ok := req.Params["file_name"].(string); ok && name != "" {
+		if !strings.HasSuffix(strings.ToLower(name), "."+format) {
+			name += "." + format
+		}
+		if !diagramFileNamePattern.MatchString(name) {
+			return models.ErrorResponse(models.ErrCodeInvalidRequest,
+				"file_name may only contain letters, digits, dots, dashes and underscores", req.RequestID)
+		}
+		fileName = name
+	}
+
+	position := "end"
+	if p, ok := req.Params["position"].(string); ok && p != "" {
+		position = p
+	}
+	if position != "start" && position != "end" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "position must be start or end", req.RequestID)
+	}
+
+	image, err := s.renderDiagram(ctx, kroki, language, format, source)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	if violation := s.uploads.Check(ctx, fileName, contentType, image); violation != nil {
+		return models.ErrorResponse(violation.Code, violation.Message, req.RequestID)
+	}
+
+	currentPage, err := client.GetPage(pageID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	attachment, err := client.UploadAttachment(pageID, fileName, contentType, image)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	result := map[string]interface{}{
+		"page_id":    pageID,
+		"file_name":  fileName,
+		"attachment": attachment,
+		"size_bytes": len(image),
+		"embedded":   false,
+	}
+
+	body := currentPage.Body.Storage.Value
+	if strings.Contains(body, fmt.Sprintf(`ri:filename="%s"`, fileName)) {
+		result["version"] = currentPage.Version.Number
+		return models.SuccessResponse(result, req.RequestID)
+	}
+
+	macro := diagramImageMacro(fileName, req.Params)
+	if position == "start" {
+		body = macro + body
+	} else {
+		body += macro
+	}
+	updatedPage, err := client.UpdatePage(pageID, currentPage.Title, body, currentPage.Version.Number+1)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError,
+			fmt.Sprintf("attached %s but failed to embed it: %v", fileName, err), req.RequestID)
+	}
+
+	result["embedded"] = true
+	result["version"] = updatedPage.Version.Number
+	return models.SuccessResponse(result, req.RequestID)
+}
+
+// diagramImageMacro is the storage format that shows an attached image, with the
+// optional alt text and width from the request
+func diagramImageMacro(fileName string, params map[string]interface{}) string {
+	var attrs strings.Builder
+	if alt, ok := params["alt"].(string); ok && alt != "" {
+		fmt.Fprintf(&attrs, ` ac:alt="%s"`, html.EscapeString(alt))
+	}
+	if width, ok := params["width"].(float64); ok && width > 0 {
+		fmt.Fprintf(&attrs, ` ac:width="%d"`, int(width))
+	}
+	return fmt.Sprintf(`<p><ac:image%s><ri:attachment ri:filename="%s" /></ac:image></p>`, attrs.String(), fileName)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// maxSuggestedSpaces bounds how many space keys one suggestion lists
+const maxSuggestedSpaces = 20
+
+// suggestFixes describes a failed Atlassian call in its error (see atlassian.EnrichError)
+// and adds what Confluence itself cannot say: which spaces exist when a space key was
+// wrong, that a conflicting edit can simply be retried, and how CQL is written.
+func (s *Service) suggestFixes(client *api.Client, req models.ConfluenceRequest, policy *models.WorkspacePolicy, info *models.ErrorInfo) {
+	atlassian.EnrichError(info, client.FailedStatus())
+
+	var suggestions []string
+	switch {
+	case info.Code == models.ErrCodeConflict:
+		_, pinned := req.Params["expected_version"].(float64)
+		edit := req.Action == "update_page" || req.Action == "append_to_page" || req.Action == "replace_section"
+		if edit && pinned {
+			suggestions = append(suggestions, "the page changed since the expected version; get it again, reapply the change and pass its current version as expected_version")
+		} else if edit || req.Action == "insert_diagram" {
+			// The page's version is read again on every update, so the edit only lost a race
+			info.Retriable = true
+			suggestions = append(suggestions, "the page was edited by someone else at the same time; retry to apply the change to the new version")
+		} else if req.Action == "create_page" || req.Action == "copy_page" || req.Action == "import_url" {
+			suggestions = append(suggestions, "a page with this title already exists in the space; choose another title or update that page")
+		}
+	case info.HTTPStatus == http.StatusBadRequest || info.HTTPStatus == http.StatusNotFound:
+		spaceKey, _ := req.Params["space_key"].(string)
+		if spaceKey != "" && (info.HTTPStatus == http.StatusNotFound || strings.Contains(strings.ToLower(info.Message), "space")) {
+			if suggestion := validSpaces(client, policy, spaceKey); suggestion != "" {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+		if req.Action == "search" && info.HTTPStatus == http.StatusBadRequest {
+			suggestions = append(suggestions, `check the CQL syntax, e.g. type = page AND space = "DOCS" AND text ~ "release notes"; quote values that contain spaces`)
+		}
+	}
+
+	info.Suggestions = append(suggestions, info.Suggestions...)
+}
+
+// validSpaces suggests the spaces the workspace may use in place of spaceKey
+func validSpaces(client *api.Client, policy *models.WorkspacePolicy, spaceKey string) string {
+	spaces, err := client.ListSpaces(100)
+	if err != nil {
+		return ""
+	}
+	spaces = filterSpaces(policy, spaces)
+	if len(spaces) == 0 {
+		return ""
+	}
+	keys := make([]string, len(spaces))
+	for i, space := range spaces {
+		keys[i] = space.Key
+	}
+	list := strings.Join(keys, ", ")
+	if len(keys) > maxSuggestedSpaces {
+		list = fmt.Sprintf("%s and %d more", strings.Join(keys[:maxSuggestedSpaces], ", "), len(keys)-maxSuggestedSpaces)
+	}
+	return fmt.Sprintf("space %q was not found; valid spaces: %s", spaceKey, list)
+}
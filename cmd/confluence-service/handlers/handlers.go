@@ -1,23 +1,42 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/cache"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/upload"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// pageBodyCacheTTL is how long a page read by get_page is kept; re-reads within it
+// download the body again only if the page has a new version
+const pageBodyCacheTTL = 30 * time.Minute
+
 // Service handles Confluence service requests
 type Service struct {
 	credStore  storage.CredentialStoreInterface
 	apiTimeout time.Duration
-	cache      *cache.SimpleCache
+
+	idempotency    storage.IdempotencyStoreInterface // nil disables idempotency keys
+	idempotencyTTL time.Duration
+	cache      cache.Backend
+	uploads    upload.Policy // Applied to every file attached to a page
+	redaction  *models.RedactionPolicy // nil returns results unchanged
+
+	customTools map[string]config.ToolDefinition // tools.yaml passthrough tools, by action
+	userTokens  *atlassian.UserTokens            // Callers' own OAuth tokens, for user OAuth workspaces
 }
 
 // NewService creates a new Confluence service
@@ -25,62 +44,177 @@ func NewService(credStore storage.CredentialStoreInterface, timeout time.Duratio
 	return &Service{
 		credStore:  credStore,
 		apiTimeout: timeout,
-		cache:      cache.NewSimpleCache(),
+		cache:      cache.NewMemoryBackend(),
+		uploads:    upload.PolicyFromEnv(),
 	}
 }
 
+// WithIdempotency remembers the results of mutating requests sent with an
+// idempotency_key for ttl, and replays them instead of repeating the request
+func (s *Service) WithIdempotency(store storage.IdempotencyStoreInterface, ttl time.Duration) *Service {
+	s.idempotency = store
+	s.idempotencyTTL = ttl
+	return s
+}
+
+// WithRedaction hides what policy names in every result before it is returned
+func (s *Service) WithRedaction(policy models.RedactionPolicy) *Service {
+	if policy.Enabled() {
+		s.redaction = &policy
+	}
+	return s
+}
+
+// WithCache caches in backend instead of this process's memory, e.g. to share the
+// cache between replicas
+func (s *Service) WithCache(backend cache.Backend) *Service {
+	s.cache = backend
+	return s
+}
+
+// WithCustomTools serves the confluence_* passthrough tools among defs
+func (s *Service) WithCustomTools(defs []config.ToolDefinition) *Service {
+	s.customTools = customTools(defs, "confluence")
+	return s
+}
+
+// WithUserTokens calls Atlassian with the caller's own OAuth token in workspaces whose
+// policy has user_oauth
+func (s *Service) WithUserTokens(tokens *atlassian.UserTokens) *Service {
+	s.userTokens = tokens
+	return s
+}
+
 // HandleRequest processes incoming RabbitMQ messages
 func (s *Service) HandleRequest(d amqp.Delivery) []byte {
+	requestID, _ := d.Headers[logging.RequestIDHeader].(string)
+	return s.HandleMessage(d.Body, requestID)
+}
+
+// HandleMessage processes a request received over any message bus and returns the
+// JSON reply. requestID is the correlation ID sent alongside the body, if any.
+func (s *Service) HandleMessage(body []byte, requestID string) []byte {
 	var req models.ConfluenceRequest
-	if err := json.Unmarshal(d.Body, &req); err != nil {
-		response := models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	if err := json.Unmarshal(body, &req); err != nil {
+		response := models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), requestID)
 		responseBytes, _ := json.Marshal(response)
 		return responseBytes
 	}
 
+	// The MCP server sends the HTTP request's correlation ID in both the header and
+	// the body; prefer the header so malformed bodies can still be traced
+	if requestID != "" {
+		req.RequestID = requestID
+	}
+	ctx := logging.WithRequestID(context.Background(), req.RequestID)
+
+	// Stop working on the request once the caller has given up on it
+	if req.Deadline > 0 {
+		deadline := time.UnixMilli(req.Deadline)
+		if time.Now().After(deadline) {
+			slog.WarnContext(ctx, "request expired before processing", "action", req.Action, "workspace_id", req.WorkspaceID)
+			responseBytes, _ := json.Marshal(models.ErrorResponse(models.ErrCodeTimeout, "request expired before it was processed", req.RequestID))
+			return responseBytes
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	start := time.Now()
+	var response map[string]interface{}
+	if key := storage.IdempotencyKey(req.Params); key != "" && s.idempotency != nil && models.ConfluenceMutatingActions[req.Action] && !atlassian.IsDryRun(req.Params) {
+		response = storage.RunIdempotent(s.idempotency, s.idempotencyTTL, req.UserID, key, req.Action, req.WorkspaceID,
+			req.Params, req.RequestID, func() map[string]interface{} { return s.dispatch(ctx, req) })
+	} else {
+		response = s.dispatch(ctx, req)
+	}
+	succeeded, _ := response["success"].(bool)
+	metrics.ToolCalls.Inc(req.Action, req.WorkspaceID, metrics.Status(!succeeded))
+	metrics.ToolCallDuration.ObserveSince(start, req.Action, req.WorkspaceID)
+	// Work the deadline did not stop in time (e.g. between Atlassian calls) is an overrun;
+	// the caller has already given up on the reply
+	if req.Deadline > 0 && time.Now().After(time.UnixMilli(req.Deadline)) {
+		metrics.ToolCallOverruns.Inc(req.Action)
+		slog.WarnContext(ctx, "request finished after its deadline", "action", req.Action, "workspace_id", req.WorkspaceID,
+			"overrun", time.Since(time.UnixMilli(req.Deadline)).String())
+	}
+	if !succeeded {
+		attrs := []any{"action", req.Action, "workspace_id", req.WorkspaceID, "user_id", req.UserID}
+		if info, ok := response["error"].(*models.ErrorInfo); ok {
+			attrs = append(attrs, "code", info.Code, "error", info.Message)
+		}
+		slog.WarnContext(ctx, "Confluence request failed", attrs...)
+	}
+
+	responseBytes, _ := json.Marshal(response)
+	return responseBytes
+}
+
+// dispatch loads the workspace credentials and routes the request to its action handler
+func (s *Service) dispatch(ctx context.Context, req models.ConfluenceRequest) map[string]interface{} {
+	if req.Action == atlassian.RecordingsAction {
+		return s.handleRecordings(req)
+	}
+	if req.Action == "search_all_workspaces" {
+		return s.searchAllWorkspaces(ctx, req)
+	}
+
 	// Get credentials for the workspace
-	creds, err := s.credStore.GetCredentials(req.UserID, req.WorkspaceID)
+	creds, err := storage.GetMemberCredentials(s.credStore, req.UserID, req.OrgID, req.WorkspaceID)
 	if err != nil {
-		response := models.ErrorResponse(models.ErrCodeAuthFailed,
+		return models.ErrorResponse(models.ErrCodeAuthFailed,
 			fmt.Sprintf("workspace not found: %s", req.WorkspaceID), req.RequestID)
-		responseBytes, _ := json.Marshal(response)
-		return responseBytes
 	}
 
-	// Ensure Site URL includes /wiki for Confluence API
-	site := creds.Site
-	if site != "" && !strings.HasSuffix(site, "/wiki") {
-		// Remove trailing slash if present, then add /wiki
-		site = strings.TrimSuffix(site, "/")
-		site += "/wiki"
+	// The rate limit budget is this service's own record, so only membership is checked
+	if req.Action == atlassian.RateStatusAction {
+		return models.SuccessResponse(atlassian.RateStatus(req.WorkspaceID), req.RequestID)
 	}
 
 	// Create API client
-	client := api.NewClient(api.WorkspaceCredentials{
-		Site:  site,
-		Email: creds.Email,
-		Token: creds.Token,
-	}, s.apiTimeout)
+	clientCreds, err := s.clientCredentials(ctx, req.UserID, creds)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAuthFailed, err.Error(), req.RequestID)
+	}
+	clientCreds.WorkspaceID = req.WorkspaceID
+	client := api.NewClient(clientCreds, s.apiTimeout).WithContext(ctx)
+	// copy_page makes its own clients and dry runs them itself
+	dryRun := atlassian.IsDryRun(req.Params) && models.ConfluenceMutatingActions[req.Action] && req.Action != "copy_page"
+	if dryRun {
+		client.WithDryRun()
+	}
+
+	// Enforce the workspace's tool permission policy
+	if response := s.enforcePolicy(client, creds.Policy, &req); response != nil {
+		return response
+	}
 
 	// Route to appropriate handler
 	var response map[string]interface{}
 	switch req.Action {
 	case "get_page":
-		response = s.handleGetPage(client, req)
+		response = s.handleGetPage(client, creds.ConfluenceSite(), req)
 	case "create_page":
 		response = s.handleCreatePage(client, req)
 	case "update_page":
 		response = s.handleUpdatePage(client, req)
+	case "append_to_page":
+		response = s.handleAppendToPage(client, req)
+	case "replace_section":
+		response = s.handleReplaceSection(client, req)
 	case "delete_page":
 		response = s.handleDeletePage(client, req)
+	case "restore_page":
+		response = s.handleRestorePage(client, req)
 	case "search":
 		response = s.handleSearch(client, req)
 	case "list_spaces":
-		response = s.handleListSpaces(client, req)
+		response = s.handleListSpaces(client, req, creds.Policy)
 	case "get_space":
 		response = s.handleGetSpace(client, req)
 	case "copy_page":
-		response = s.handleCopyPage(req)
+		response = s.handleCopyPage(ctx, req)
 	case "get_page_children":
 		response = s.handleGetPageChildren(client, req)
 	case "add_comment":
@@ -95,25 +229,76 @@ func (s *Service) HandleRequest(d amqp.Delivery) []byte {
 		response = s.handleSearchUser(client, req)
 	case "get_attachments":
 		response = s.handleGetAttachments(client, req)
+	case "insert_diagram":
+		response = s.handleInsertDiagram(ctx, client, req)
+	case "import_url":
+		response = s.handleImportURL(ctx, client, req)
+	case "content_audit":
+		response = s.handleContentAudit(client, req)
+	case "get_page_links":
+		response = s.handleGetPageLinks(client, req, creds.Policy)
+	case "get_label_taxonomy":
+		response = s.handleGetLabelTaxonomy(client, req)
+	case "get_share_link":
+		response = s.handleGetShareLink(client, req)
 	default:
-		response = models.ErrorResponse(models.ErrCodeInvalidRequest,
-			fmt.Sprintf("unknown action: %s", req.Action), req.RequestID)
+		if def, ok := s.customTools[req.Action]; ok {
+			response = s.handleCustomTool(client, def, req)
+		} else {
+			response = models.ErrorResponse(models.ErrCodeInvalidRequest,
+				fmt.Sprintf("unknown action: %s", req.Action), req.RequestID)
+		}
 	}
 
-	responseBytes, _ := json.Marshal(response)
-	return responseBytes
+	// A dry run answers with the changes it would have made
+	if dryRun {
+		response = atlassian.DryRunResponse(client.DryRunRequests(), response, req.RequestID)
+	}
+
+	// Failed Atlassian calls say how to correct them; the lookups this takes are metered too
+	if info, ok := response["error"].(*models.ErrorInfo); ok {
+		s.suggestFixes(client, req, creds.Policy, info)
+	}
+
+	// Hide what the deployment's redaction policy keeps from the model
+	response = s.redact(response, req.RequestID)
+
+	// Report Atlassian API volume for usage metering
+	if _, ok := response["usage"]; !ok {
+		response["usage"] = &models.UsageInfo{APIBytes: client.BytesTransferred()}
+	}
+
+	// Whatever the action, a rejected API token is an authentication failure. Only a 401
+	// from the site itself means the workspace's credentials no longer work.
+	if info, ok := response["error"].(*models.ErrorInfo); ok && client.CredentialsRejected() {
+		info.Code = models.ErrCodeAuthFailed
+		info.CredentialsRejected = true
+	}
+
+	return response
 }
 
-func (s *Service) handleGetPage(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+func (s *Service) handleGetPage(client *api.Client, site string, req models.ConfluenceRequest) map[string]interface{} {
 	pageID, ok := req.Params["page_id"].(string)
 	if !ok {
 		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing page_id", req.RequestID)
 	}
 
+	// A page read before is only downloaded again when its version has changed. The
+	// version lookup runs with the caller's credentials, so it also checks their access.
+	cacheKey := fmt.Sprintf("page:%s:%s", site, pageID)
+	var cached models.ConfluencePage
+	if s.cache.Get(cacheKey, &cached) {
+		if version, err := client.GetPageVersion(pageID); err == nil && version == cached.Version.Number {
+			return models.SuccessResponse(&cached, req.RequestID)
+		}
+	}
+
 	page, err := client.GetPage(pageID)
 	if err != nil {
 		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
 	}
+	s.cache.Set(cacheKey, page, pageBodyCacheTTL)
 
 	return models.SuccessResponse(page, req.RequestID)
 }
@@ -158,21 +343,43 @@ func (s *Service) handleSearch(client *api.Client, req models.ConfluenceRequest)
 		limit = int(l)
 	}
 
-	results, err := client.SearchPages(query, limit)
+	start := 0
+	if s, ok := req.Params["start"].(float64); ok {
+		start = int(s)
+	}
+
+	var expand []string
+	if e, ok := req.Params["expand"].([]interface{}); ok {
+		for _, v := range e {
+			if s, ok := v.(string); ok {
+				expand = append(expand, s)
+			}
+		}
+	}
+
+	// Overviews need only one line per page, and only the properties that line shows
+	summarize, _ := req.Params["summarize"].(bool)
+	if summarize {
+		expand = summaryExpand
+	}
+
+	results, err := client.SearchPages(query, limit, start, expand)
 	if err != nil {
 		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
 	}
 
+	if summarize {
+		return models.SuccessResponse(summarizePages(results), req.RequestID)
+	}
 	return models.SuccessResponse(results, req.RequestID)
 }
 
-func (s *Service) handleListSpaces(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+func (s *Service) handleListSpaces(client *api.Client, req models.ConfluenceRequest, policy *models.WorkspacePolicy) map[string]interface{} {
 	// Check cache first
 	cacheKey := fmt.Sprintf("spaces:%s:%s", req.UserID, req.WorkspaceID)
-	if cached, found := s.cache.Get(cacheKey); found {
-		if cachedData, ok := cached.(map[string]interface{}); ok {
-			return cachedData
-		}
+	var cached []models.ConfluenceSpace
+	if s.cache.Get(cacheKey, &cached) {
+		return models.SuccessResponse(filterSpaces(policy, cached), req.RequestID)
 	}
 
 	// Cache miss - fetch from API
@@ -186,12 +393,10 @@ func (s *Service) handleListSpaces(client *api.Client, req models.ConfluenceRequ
 		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
 	}
 
-	response := models.SuccessResponse(spaces, req.RequestID)
-
-	// Cache for 2 minutes
-	s.cache.Set(cacheKey, response, 2*time.Minute)
+	// Cache for 2 minutes (unfiltered, so policy changes apply immediately)
+	s.cache.Set(cacheKey, spaces, 2*time.Minute)
 
-	return response
+	return models.SuccessResponse(filterSpaces(policy, spaces), req.RequestID)
 }
 
 func (s *Service) handleGetSpace(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
@@ -208,7 +413,7 @@ func (s *Service) handleGetSpace(client *api.Client, req models.ConfluenceReques
 	return models.SuccessResponse(space, req.RequestID)
 }
 
-func (s *Service) handleCopyPage(req models.ConfluenceRequest) map[string]interface{} {
+func (s *Service) handleCopyPage(ctx context.Context, req models.ConfluenceRequest) map[string]interface{} {
 	srcWorkspace, ok := req.Params["src_workspace"].(string)
 	if !ok {
 		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing src_workspace", req.RequestID)
@@ -235,30 +440,52 @@ func (s *Service) handleCopyPage(req models.ConfluenceRequest) map[string]interf
 	}
 
 	// Get credentials for both workspaces
-	srcCreds, err := s.credStore.GetCredentials(req.UserID, srcWorkspace)
+	srcCreds, err := storage.GetMemberCredentials(s.credStore, req.UserID, req.OrgID, srcWorkspace)
 	if err != nil {
 		return models.ErrorResponse(models.ErrCodeAuthFailed,
 			fmt.Sprintf("source workspace not found: %s", srcWorkspace), req.RequestID)
 	}
 
-	dstCreds, err := s.credStore.GetCredentials(req.UserID, dstWorkspace)
+	dstCreds, err := storage.GetMemberCredentials(s.credStore, req.UserID, req.OrgID, dstWorkspace)
 	if err != nil {
 		return models.ErrorResponse(models.ErrCodeAuthFailed,
 			fmt.Sprintf("destination workspace not found: %s", dstWorkspace), req.RequestID)
 	}
 
 	// Create clients for both workspaces
-	srcClient := api.NewClient(api.WorkspaceCredentials{
-		Site:  srcCreds.Site,
-		Email: srcCreds.Email,
-		Token: srcCreds.Token,
-	}, s.apiTimeout)
-
-	dstClient := api.NewClient(api.WorkspaceCredentials{
-		Site:  dstCreds.Site,
-		Email: dstCreds.Email,
-		Token: dstCreds.Token,
-	}, s.apiTimeout)
+	srcClientCreds, err := s.clientCredentials(ctx, req.UserID, srcCreds)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAuthFailed, err.Error(), req.RequestID)
+	}
+	srcClientCreds.WorkspaceID = srcWorkspace
+	srcClient := api.NewClient(srcClientCreds, s.apiTimeout).WithContext(ctx)
+
+	dstClientCreds, err := s.clientCredentials(ctx, req.UserID, dstCreds)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAuthFailed, err.Error(), req.RequestID)
+	}
+	dstClientCreds.WorkspaceID = dstWorkspace
+	dstClient := api.NewClient(dstClientCreds, s.apiTimeout).WithContext(ctx)
+	if atlassian.IsDryRun(req.Params) {
+		dstClient.WithDryRun()
+	}
+
+	// Both workspaces' policies apply: the source must allow reading the page,
+	// the destination must allow writing into the target space
+	if !srcCreds.Policy.AllowsTool("confluence_copy_page") || !dstCreds.Policy.AllowsTool("confluence_copy_page") {
+		return models.ErrorResponse(models.ErrCodeForbidden, "tool confluence_copy_page is not allowed for this workspace", req.RequestID)
+	}
+	if dstCreds.Policy != nil && dstCreds.Policy.ReadOnly {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("destination workspace %s is read-only", dstWorkspace), req.RequestID)
+	}
+	if !dstCreds.Policy.AllowsSpace(dstSpaceKey) {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("space %s is not allowed for workspace %s", dstSpaceKey, dstWorkspace), req.RequestID)
+	}
+	if response := checkPageSpace(srcClient, srcCreds.Policy, srcPageID, req.RequestID); response != nil {
+		return response
+	}
 
 	// Read from source
 	page, err := srcClient.GetPage(srcPageID)
@@ -272,7 +499,12 @@ func (s *Service) handleCopyPage(req models.ConfluenceRequest) map[string]interf
 		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
 	}
 
-	return models.SuccessResponse(newPage, req.RequestID)
+	response := models.SuccessResponse(newPage, req.RequestID)
+	if atlassian.IsDryRun(req.Params) {
+		response = atlassian.DryRunResponse(dstClient.DryRunRequests(), response, req.RequestID)
+	}
+	response["usage"] = &models.UsageInfo{APIBytes: srcClient.BytesTransferred() + dstClient.BytesTransferred()}
+	return response
 }
 
 func (s *Service) handleUpdatePage(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
@@ -286,24 +518,24 @@ func (s *Service) handleUpdatePage(client *api.Client, req models.ConfluenceRequ
 		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing body", req.RequestID)
 	}
 
-	// Get current page to retrieve version
-	currentPage, err := client.GetPage(pageID)
-	if err != nil {
-		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	mode := "replace"
+	if m, ok := req.Params["mode"].(string); ok && m != "" {
+		mode = m
 	}
-
-	// Use provided title or keep existing
-	title := currentPage.Title
-	if t, ok := req.Params["title"].(string); ok && t != "" {
-		title = t
+	if !updateModes[mode] {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "mode must be replace, append_section or replace_section", req.RequestID)
 	}
-
-	updatedPage, err := client.UpdatePage(pageID, title, body, currentPage.Version.Number+1)
-	if err != nil {
-		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	section, _ := req.Params["section"].(string)
+	if mode != "replace" && strings.TrimSpace(section) == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, fmt.Sprintf("missing section (required with mode %s)", mode), req.RequestID)
 	}
 
-	return models.SuccessResponse(updatedPage, req.RequestID)
+	return s.editPage(client, req, pageID, func(current string) (string, error) {
+		if mode == "replace" {
+			return body, nil
+		}
+		return patchSection(current, section, body, mode)
+	})
 }
 
 func (s *Service) handleDeletePage(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
@@ -317,10 +549,32 @@ func (s *Service) handleDeletePage(client *api.Client, req models.ConfluenceRequ
 		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
 	}
 
-	return models.SuccessResponse(map[string]interface{}{
+	response := models.SuccessResponse(map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Page %s deleted successfully", pageID),
 	}, req.RequestID)
+	// Deleted pages stay in the space's trash until it is emptied
+	response["undo"] = &models.UndoAction{
+		Action:      "restore_page",
+		Params:      map[string]interface{}{"page_id": pageID},
+		Description: fmt.Sprintf("restore page %s from the trash", pageID),
+	}
+	return response
+}
+
+// handleRestorePage restores a deleted page from the trash; undo_last_action sends it
+func (s *Service) handleRestorePage(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+	pageID, ok := req.Params["page_id"].(string)
+	if !ok || pageID == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing page_id", req.RequestID)
+	}
+
+	page, err := client.RestorePage(pageID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	return models.SuccessResponse(page, req.RequestID)
 }
 
 func (s *Service) handleGetPageChildren(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	// maxImportPage bounds the web page import_url reads
+	maxImportPage = 5 * 1024 * 1024
+	// maxImportImages bounds how many of a page's images are downloaded; the rest are linked
+	maxImportImages = 25
+	// maxImportImage bounds each downloaded image
+	maxImportImage = 10 * 1024 * 1024
+)
+
+// importImageExtensions maps the image types import_url attaches to file extensions
+var importImageExtensions = map[string]string{
+	"image/png":     ".png",
+	"image/jpeg":    ".jpg",
+	"image/gif":     ".gif",
+	"image/webp":    ".webp",
+	"image/svg+xml": ".svg",
+	"image/bmp":     ".bmp",
+}
+
+// importAllowsPrivate reports whether IMPORT_URL_ALLOW_PRIVATE lets import_url fetch from
+// private, loopback and link-local addresses, e.g. to archive an intranet page
+func importAllowsPrivate() bool {
+	return strings.EqualFold(os.Getenv("IMPORT_URL_ALLOW_PRIVATE"), "true")
+}
+
+// importDeniedPrefixes are the address ranges import_url does not connect to: private,
+// shared (CGNAT, which some clouds use for metadata endpoints), loopback, link-local,
+// documentation, benchmarking, multicast and reserved ranges
+var importDeniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.88.99.0/24"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/128"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("fc00::/7"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("fec0::/10"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+// importEmbeddingPrefixes are IPv6 ranges that reach an IPv4 address embedded in them, and
+// where it starts: NAT64 in the last 32 bits, 6to4 after the first 16
+var importEmbeddingPrefixes = []struct {
+	prefix netip.Prefix
+	offset int
+}{
+	{netip.MustParsePrefix("64:ff9b::/96"), 12},
+	{netip.MustParsePrefix("2002::/16"), 2},
+}
+
+// importAddressAllowed reports whether import_url may connect to an address. IPv4-mapped
+// addresses are checked as IPv4, and NAT64 and 6to4 addresses by the IPv4 address they reach.
+func importAddressAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, embedding := range importEmbeddingPrefixes {
+		if embedding.prefix.Contains(addr) {
+			bytes := addr.As16()
+			addr = netip.AddrFrom4([4]byte(bytes[embedding.offset : embedding.offset+4]))
+			break
+		}
+	}
+	for _, prefix := range importDeniedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// importDialControl refuses connections to addresses that are not public, so that
+// import_url cannot be used to read the services and metadata endpoints on the network
+// the Confluence service runs in. It is checked on the address actually dialed, which
+// covers redirects and host names that resolve to private addresses.
+func importDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !importAddressAllowed(addr.WithZone("")) {
+		return fmt.Errorf("%s is not a public address; set IMPORT_URL_ALLOW_PRIVATE=true to import from it", host)
+	}
+	return nil
+}
+
+// importHTTPClient fetches the pages and images import_url reads. Pages are fetched
+// directly rather than through a proxy, so the address check sees the server they come from.
+func (s *Service) importHTTPClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !importAllowsPrivate() {
+		dialer.Control = importDialControl
+	}
+	return &http.Client{
+		Timeout:   s.apiTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 10 * time.Second},
+	}
+}
+
+// importFetch reads a web resource of at most limit bytes, returning its content and
+// media type and the URL it was finally read from
+func importFetch(ctx context.Context, client *http.Client, source string, limit int64) ([]byte, string, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", source, nil)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	req.Header.Set("User-Agent", "Trilix-Confluence-Import/1.0")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/markdown,text/plain;q=0.9,image/*;q=0.8,*/*;q=0.5")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", nil, fmt.Errorf("%s answered %s", source, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, "", nil, fmt.Errorf("%s is larger than %d bytes", source, limit)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	return data, strings.ToLower(mediaType), resp.Request.URL, nil
+}
+
+// importFormat is how import_url reads a page: as html or markdown. auto goes by the
+// page's media type and the extension of its path.
+func importFormat(format, mediaType string, source *url.URL) (string, error) {
+	if format != "" && format != "auto" {
+		return format, nil
+	}
+	ext := strings.ToLower(path.Ext(source.Path))
+	switch {
+	case strings.Contains(mediaType, "html"):
+		return "html", nil
+	case strings.Contains(mediaType, "markdown") || ext == ".md" || ext == ".markdown":
+		return "markdown", nil
+	case strings.HasPrefix(mediaType, "text/"):
+		return "markdown", nil
+	}
+	return "", fmt.Errorf("%s is %s, not a web page or Markdown document", source, mediaType)
+}
+
+// importedImage is what became of one of an imported page's images
+type importedImage struct {
+	Source    string `json:"source"`
+	FileName  string `json:"file_name,omitempty"` // Attached under this name
+	SizeBytes int    `json:"size_bytes,omitempty"`
+	Reason    string `json:"reason,omitempty"` // Why it is linked rather than attached
+
+	data        []byte
+	contentType string
+}
+
+// handleImportURL creates a page from a web page or Markdown document: the source is
+// fetched, sanitized and converted to storage format, its images are downloaded and
+// attached to the new page, and a line saying where the page came from is added at the top.
+// Images that cannot be downloaded, or that the upload policy refuses, are linked from
+// their original address instead.
+func (s *Service) handleImportURL(ctx context.Context, client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+	spaceKey, _ := req.Params["space_key"].(string)
+	if spaceKey == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing space_key", req.RequestID)
+	}
+	sourceURL, _ := req.Params["source_url"].(string)
+	source, err := url.Parse(strings.TrimSpace(sourceURL))
+	if err != nil || (source.Scheme != "http" && source.Scheme != "https") || source.Host == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "source_url must be an http or https URL", req.RequestID)
+	}
+	format, _ := req.Params["format"].(string)
+	if format != "" && format != "auto" && format != "html" && format != "markdown" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "format must be auto, html or markdown", req.RequestID)
+	}
+	downloadImages := true
+	if d, ok := req.Params["download_images"].(bool); ok {
+		downloadImages = d
+	}
+
+	httpClient := s.importHTTPClient()
+	data, mediaType, final, err := importFetch(ctx, httpClient, source.String(), maxImportPage)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, fmt.Sprintf("failed to fetch %s: %v", source, err), req.RequestID)
+	}
+	format, err = importFormat(format, mediaType, final)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	}
+
+	document := string(data)
+	if format == "markdown" {
+		document = markdownToHTML(document)
+	}
+	body, images := htmlToStorage(document, final)
+	if body == "" && len(images) == 0 {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, fmt.Sprintf("%s has no content to import", source), req.RequestID)
+	}
+
+	title, _ := req.Params["title"].(string)
+	if title = strings.TrimSpace(title); title == "" {
+		if title = importTitle(document); title == "" {
+			title = final.Host + final.Path
+		}
+	}
+
+	imported := make([]*importedImage, len(images))
+	byFile := make(map[string]bool)
+	for i, image := range images {
+		imported[i] = &importedImage{Source: image.Source}
+		switch {
+		case !downloadImages:
+			imported[i].Reason = "download_images is false"
+		case i >= maxImportImages:
+			imported[i].Reason = fmt.Sprintf("only the first %d images are attached", maxImportImages)
+		default:
+			s.downloadImportImage(ctx, httpClient, imported[i])
+			if imported[i].FileName != "" && byFile[imported[i].FileName] {
+				imported[i].data = nil // The same image again; it is attached once
+			}
+			byFile[imported[i].FileName] = true
+		}
+	}
+
+	attribution := fmt.Sprintf(`<p><em>Imported from <a href="%s">%s</a> on %s.</em></p>`,
+		html.EscapeString(final.String()), html.EscapeString(final.String()), time.Now().UTC().Format("2006-01-02"))
+	render := func() string {
+		content := body
+		for i, image := range imported {
+			content = strings.Replace(content, importImagePlaceholder(i), importImageMacro(image, images[i].Alt), 1)
+		}
+		return attribution + content
+	}
+
+	var parentID *string
+	if pid, ok := req.Params["parent_id"].(string); ok && pid != "" {
+		parentID = &pid
+	}
+	page, err := client.CreatePage(spaceKey, title, render(), parentID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	// Images are attached to the page once it exists; any that fail are linked instead
+	attached, linked := []*importedImage{}, []*importedImage{}
+	failed := make(map[string]string) // Reasons by file name, for repeats of the same image
+	for _, image := range imported {
+		if image.data != nil {
+			if _, err := client.UploadAttachment(page.ID, image.FileName, image.contentType, image.data); err != nil {
+				failed[image.FileName] = fmt.Sprintf("failed to attach: %v", err)
+			}
+		}
+		if reason, ok := failed[image.FileName]; ok && image.FileName != "" {
+			image.FileName, image.SizeBytes, image.Reason = "", 0, reason
+		}
+		if image.FileName != "" {
+			attached = append(attached, image)
+		} else {
+			linked = append(linked, image)
+		}
+	}
+	if len(failed) > 0 {
+		if updated, err := client.UpdatePage(page.ID, page.Title, render(), page.Version.Number+1); err == nil {
+			page = updated
+		}
+	}
+
+	return models.SuccessResponse(map[string]interface{}{
+		"page":       page,
+		"source_url": final.String(),
+		"format":     format,
+		"images": map[string]interface{}{
+			"attached": attached,
+			"linked":   linked,
+		},
+	}, req.RequestID)
+}
+
+// downloadImportImage downloads an image of an imported page and checks it against the
+// upload policy, naming it after its content. An image that cannot be attached is given
+// the reason.
+func (s *Service) downloadImportImage(ctx context.Context, client *http.Client, image *importedImage) {
+	var data []byte
+	var contentType string
+	if strings.HasPrefix(image.Source, "data:") {
+		header, payload, _ := strings.Cut(strings.TrimPrefix(image.Source, "data:"), ",")
+		contentType, _, _ = mime.ParseMediaType(strings.TrimSuffix(header, ";base64"))
+		if !strings.HasSuffix(header, ";base64") {
+			image.Reason = "inline images that are not base64 encoded are not imported"
+			return
+		}
+		decoded, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			image.Reason = "the inline image is not valid base64"
+			return
+		}
+		data = decoded
+	} else {
+		fetched, mediaType, _, err := importFetch(ctx, client, image.Source, maxImportImage)
+		if err != nil {
+			image.Reason = fmt.Sprintf("failed to download: %v", err)
+			return
+		}
+		data, contentType = fetched, mediaType
+	}
+	if len(data) > maxImportImage {
+		image.Reason = fmt.Sprintf("larger than %d bytes", maxImportImage)
+		return
+	}
+
+	ext, ok := importImageExtensions[contentType]
+	if !ok {
+		image.Reason = fmt.Sprintf("%s is not an image type that is attached", contentType)
+		return
+	}
+	sum := sha256.Sum256(data)
+	fileName := "image-" + hex.EncodeToString(sum[:])[:12] + ext
+	if violation := s.uploads.Check(ctx, fileName, contentType, data); violation != nil {
+		image.Reason = violation.Message
+		return
+	}
+	image.FileName, image.SizeBytes, image.data, image.contentType = fileName, len(data), data, contentType
+}
+
+// importImageMacro is the storage format that shows an imported image: the attachment if
+// it was downloaded, otherwise the image at its original address. Inline images that were
+// not attached are left out, keeping their alt text.
+func importImageMacro(image *importedImage, alt string) string {
+	var attrs string
+	if alt != "" {
+		attrs = fmt.Sprintf(` ac:alt="%s"`, html.EscapeString(alt))
+	}
+	switch {
+	case image.FileName != "":
+		return fmt.Sprintf(`<ac:image%s><ri:attachment ri:filename="%s" /></ac:image>`, attrs, image.FileName)
+	case strings.HasPrefix(image.Source, "data:"):
+		return html.EscapeString(alt)
+	default:
+		return fmt.Sprintf(`<ac:image%s><ri:url ri:value="%s" /></ac:image>`, attrs, html.EscapeString(image.Source))
+	}
+}
@@ -0,0 +1,557 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// importTitlePattern finds a web page's title
+	importTitlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title\s*>`)
+	// importH1Pattern finds a page's first top-level heading, the title of pages without one
+	importH1Pattern = regexp.MustCompile(`(?is)<h1[^>]*>(.*?)</h1\s*>`)
+	// importCommentPattern finds comments, doctypes and processing instructions
+	importCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->|<![^>]*>|<\?[^>]*>`)
+	// importTokenPattern finds the tags of an HTML document; everything between them is text
+	importTokenPattern = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:[^>"']|"[^"]*"|'[^']*')*)>`)
+	// importAttrPattern finds the attributes of a tag
+	importAttrPattern = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	// importSpacePattern finds runs of whitespace, which HTML shows as one space
+	importSpacePattern = regexp.MustCompile(`\s+`)
+	// importEmptyParagraphPattern finds paragraphs left with nothing in them
+	importEmptyParagraphPattern = regexp.MustCompile(`<p>\s*</p>`)
+)
+
+// importDroppedElements are removed from imported pages with everything in them: code,
+// styling, forms and the navigation around a page's content
+var importDroppedElements = []string{
+	"head", "script", "style", "noscript", "template", "svg", "math", "iframe", "object",
+	"embed", "canvas", "video", "audio", "form", "button", "select", "textarea", "nav", "footer", "aside",
+}
+
+// importDroppedPatterns find the importDroppedElements
+var importDroppedPatterns = func() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, 0, len(importDroppedElements))
+	for _, name := range importDroppedElements {
+		patterns = append(patterns, regexp.MustCompile(fmt.Sprintf(`(?is)<%[1]s\b[^>]*>.*?</%[1]s\s*>`, name)))
+	}
+	return patterns
+}()
+
+// importContentPatterns find the part of a web page that holds its content, most specific first
+var importContentPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?is)<article\b[^>]*>(.*)</article\s*>`),
+	regexp.MustCompile(`(?is)<main\b[^>]*>(.*)</main\s*>`),
+	regexp.MustCompile(`(?is)<body\b[^>]*>(.*)</body\s*>`),
+}
+
+// importTagNames maps the HTML elements kept in imported pages to the storage format
+// elements they become. Other elements are dropped, keeping their text.
+var importTagNames = map[string]string{
+	"p": "p", "h1": "h1", "h2": "h2", "h3": "h3", "h4": "h4", "h5": "h5", "h6": "h6",
+	"ul": "ul", "ol": "ol", "li": "li", "blockquote": "blockquote", "pre": "pre", "hr": "hr",
+	"table": "table", "thead": "thead", "tbody": "tbody", "tfoot": "tbody", "tr": "tr", "th": "th", "td": "td",
+	"a": "a", "strong": "strong", "b": "strong", "em": "em", "i": "em", "u": "u",
+	"code": "code", "kbd": "code", "tt": "code", "sub": "sub", "sup": "sup", "br": "br", "img": "img",
+}
+
+// importBoundaryTags are dropped elements that still end the paragraph they appear in
+var importBoundaryTags = map[string]bool{
+	"div": true, "section": true, "article": true, "main": true, "header": true, "figure": true,
+	"figcaption": true, "dl": true, "dt": true, "dd": true, "details": true, "summary": true,
+	"center": true, "address": true,
+}
+
+// importInlineTags are the kept elements that sit inside a paragraph
+var importInlineTags = map[string]bool{
+	"a": true, "strong": true, "em": true, "u": true, "code": true, "sub": true, "sup": true,
+}
+
+// importTableParts are the elements of a table that may not hold text themselves
+var importTableParts = map[string]bool{"table": true, "thead": true, "tbody": true, "tr": true}
+
+// importImage is an image of an imported page, shown in the converted body by its placeholder
+type importImage struct {
+	Source string // Absolute URL, or a data: URI
+	Alt    string
+}
+
+// importImagePlaceholder marks where an imported page shows its nth image, until the
+// image is downloaded or linked. NUL bytes are removed from imported pages, so it cannot
+// appear in their text.
+func importImagePlaceholder(n int) string {
+	return fmt.Sprintf("\x00image:%d\x00", n)
+}
+
+// importTitle is a web page's title, or its first top-level heading
+func importTitle(document string) string {
+	for _, pattern := range []*regexp.Regexp{importTitlePattern, importH1Pattern} {
+		if match := pattern.FindStringSubmatch(document); match != nil {
+			if title := headingText(match[1]); title != "" {
+				return title
+			}
+		}
+	}
+	return ""
+}
+
+// htmlToStorage converts a web page to storage format. Only the page's content (its
+// article, main or body element) is kept, as a sanitized allowlist of elements without
+// their attributes, apart from link targets, image sources and table cell spans. Links
+// and images are resolved against base; images are replaced with placeholders, in the
+// order they are returned.
+func htmlToStorage(document string, base *url.URL) (string, []importImage) {
+	document = strings.ReplaceAll(strings.ToValidUTF8(document, ""), "\x00", "")
+	document = importCommentPattern.ReplaceAllString(document, "")
+	for _, pattern := range importDroppedPatterns {
+		document = pattern.ReplaceAllString(document, "")
+	}
+	for _, pattern := range importContentPatterns {
+		if match := pattern.FindStringSubmatch(document); match != nil {
+			document = match[1]
+			break
+		}
+	}
+
+	w := &storageWriter{base: base}
+	last := 0
+	for _, match := range importTokenPattern.FindAllStringSubmatchIndex(document, -1) {
+		w.text(document[last:match[0]])
+		last = match[1]
+		name := strings.ToLower(document[match[4]:match[5]])
+		if document[match[2]:match[3]] == "/" {
+			w.close(name)
+		} else {
+			w.open(name, document[match[6]:match[7]])
+		}
+	}
+	w.text(document[last:])
+	w.closeTo(0)
+
+	body := importEmptyParagraphPattern.ReplaceAllString(w.out.String(), "")
+	return strings.TrimSpace(body), w.images
+}
+
+// storageWriter writes sanitized HTML as storage format, keeping the elements it opens
+// balanced so the body is well-formed XML whatever the source's markup
+type storageWriter struct {
+	base   *url.URL
+	out    strings.Builder
+	stack  []string // Open storage format elements, outermost first
+	images []importImage
+}
+
+// top is the innermost open element, or "" at the top level
+func (w *storageWriter) top() string {
+	if len(w.stack) == 0 {
+		return ""
+	}
+	return w.stack[len(w.stack)-1]
+}
+
+// inside reports whether an element is open, returning the index of the innermost one
+func (w *storageWriter) inside(name string) (int, bool) {
+	for i := len(w.stack) - 1; i >= 0; i-- {
+		if w.stack[i] == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// closeTo closes the open elements until only depth of them are left
+func (w *storageWriter) closeTo(depth int) {
+	for len(w.stack) > depth {
+		fmt.Fprintf(&w.out, "</%s>", w.top())
+		w.stack = w.stack[:len(w.stack)-1]
+	}
+}
+
+// closeWhile closes open elements for as long as closes reports true for the innermost
+func (w *storageWriter) closeWhile(closes func(name string) bool) {
+	for len(w.stack) > 0 && closes(w.top()) {
+		w.closeTo(len(w.stack) - 1)
+	}
+}
+
+// endParagraph closes the paragraph or heading being written, with the elements in it
+func (w *storageWriter) endParagraph() {
+	if i, inPre := w.inside("pre"); inPre {
+		w.closeTo(i)
+	}
+	w.closeWhile(func(name string) bool {
+		return importInlineTags[name] || name == "p" || (len(name) == 2 && name[0] == 'h')
+	})
+}
+
+// inline makes sure inline content can be written, opening the element it needs. It
+// reports false where inline content is not allowed, e.g. between table rows.
+func (w *storageWriter) inline() bool {
+	switch top := w.top(); {
+	case top == "" || top == "blockquote":
+		w.push("p", "")
+	case top == "ul" || top == "ol":
+		w.push("li", "")
+	case importTableParts[top]:
+		return false
+	}
+	return true
+}
+
+// push opens an element
+func (w *storageWriter) push(name, attrs string) {
+	fmt.Fprintf(&w.out, "<%s%s>", name, attrs)
+	w.stack = append(w.stack, name)
+}
+
+// text writes the text between two tags
+func (w *storageWriter) text(raw string) {
+	if raw == "" {
+		return
+	}
+	text := html.UnescapeString(raw)
+	if _, inPre := w.inside("pre"); !inPre {
+		text = importSpacePattern.ReplaceAllString(text, " ")
+		if strings.TrimSpace(text) == "" {
+			// Whitespace between blocks means nothing, and inside a paragraph one space
+			if top := w.top(); top != "" && top != "blockquote" && top != "ul" && top != "ol" && !importTableParts[top] {
+				w.out.WriteString(" ")
+			}
+			return
+		}
+	}
+	if w.inline() {
+		w.out.WriteString(html.EscapeString(text))
+	}
+}
+
+// open writes the start of an element, if it is kept
+func (w *storageWriter) open(tag, rawAttrs string) {
+	if importBoundaryTags[tag] {
+		w.endParagraph()
+		return
+	}
+	name, ok := importTagNames[tag]
+	if !ok {
+		return
+	}
+	attrs := importAttrs(rawAttrs)
+
+	// Preformatted text keeps its line breaks but none of its markup
+	if _, inPre := w.inside("pre"); inPre {
+		if name == "br" {
+			w.out.WriteString("\n")
+		}
+		return
+	}
+
+	switch name {
+	case "br":
+		if w.top() != "" && !importTableParts[w.top()] && w.top() != "ul" && w.top() != "ol" && w.top() != "blockquote" {
+			w.out.WriteString("<br />")
+		}
+	case "hr":
+		w.endParagraph()
+		w.out.WriteString("<hr />")
+	case "img":
+		source := attrs["src"]
+		if source == "" || (strings.HasPrefix(source, "data:") && attrs["data-src"] != "") {
+			// Lazily loaded images keep their address in data-src
+			source = attrs["data-src"]
+		}
+		if source = w.resolve(source, "http", "https", "data"); source == "" || !w.inline() {
+			return
+		}
+		w.out.WriteString(importImagePlaceholder(len(w.images)))
+		w.images = append(w.images, importImage{Source: source, Alt: strings.TrimSpace(attrs["alt"])})
+	case "a":
+		href := w.resolve(attrs["href"], "http", "https", "mailto")
+		if href == "" {
+			return
+		}
+		if i, inLink := w.inside("a"); inLink {
+			w.closeTo(i)
+		}
+		if w.inline() {
+			w.push("a", fmt.Sprintf(` href="%s"`, html.EscapeString(href)))
+		}
+	case "strong", "em", "u", "code", "sub", "sup":
+		if w.inline() {
+			w.push(name, "")
+		}
+	case "li":
+		list := -1
+		for i := len(w.stack) - 1; i >= 0; i-- {
+			if w.stack[i] == "ul" || w.stack[i] == "ol" {
+				list = i
+				break
+			}
+		}
+		if list < 0 {
+			w.endParagraph()
+			w.push("ul", "")
+		} else {
+			w.closeTo(list + 1)
+		}
+		w.push("li", "")
+	case "thead", "tbody":
+		if i, ok := w.inside("table"); ok {
+			w.closeTo(i + 1)
+			w.push(name, "")
+		}
+	case "tr":
+		for i := len(w.stack) - 1; i >= 0; i-- {
+			if part := w.stack[i]; part == "table" || part == "thead" || part == "tbody" {
+				w.closeTo(i + 1)
+				w.push("tr", "")
+				return
+			}
+		}
+	case "th", "td":
+		if i, ok := w.inside("tr"); ok {
+			w.closeTo(i + 1)
+			var spans strings.Builder
+			for _, span := range []string{"colspan", "rowspan"} {
+				if n := attrs[span]; n != "" && strings.Trim(n, "0123456789") == "" {
+					fmt.Fprintf(&spans, ` %s="%s"`, span, n)
+				}
+			}
+			w.push(name, spans.String())
+		}
+	default:
+		// p, headings, lists, blockquote, pre and table start a new block
+		w.endParagraph()
+		w.push(name, "")
+	}
+}
+
+// close writes the end of an element, closing any left open inside it
+func (w *storageWriter) close(tag string) {
+	if importBoundaryTags[tag] {
+		w.endParagraph()
+		return
+	}
+	name, ok := importTagNames[tag]
+	if !ok {
+		return
+	}
+	if i, open := w.inside(name); open {
+		w.closeTo(i)
+	}
+}
+
+// resolve makes a link or image address absolute, returning "" unless it uses one of schemes
+func (w *storageWriter) resolve(ref string, schemes ...string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return ""
+	}
+	if strings.HasPrefix(ref, "data:") {
+		if slices.Contains(schemes, "data") {
+			return ref
+		}
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if w.base != nil {
+		u = w.base.ResolveReference(u)
+	}
+	if !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+		return ""
+	}
+	return u.String()
+}
+
+// importAttrs reads the attributes of a tag, by lower case name
+func importAttrs(raw string) map[string]string {
+	attrs := make(map[string]string)
+	for _, match := range importAttrPattern.FindAllStringSubmatch(raw, -1) {
+		attrs[strings.ToLower(match[1])] = html.UnescapeString(match[2] + match[3] + match[4])
+	}
+	return attrs
+}
+
+var (
+	// markdownHeadingPattern finds ATX headings
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	// markdownRulePattern finds horizontal rules
+	markdownRulePattern = regexp.MustCompile(`^\s*(?:(?:\*\s*){3,}|(?:-\s*){3,}|(?:_\s*){3,})$`)
+	// markdownListPattern finds list items, with their indentation and marker
+	markdownListPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	// markdownFencePattern finds the start of a fenced code block
+	markdownFencePattern = regexp.MustCompile("^\\s*(```+|~~~+)")
+	// markdownTableRulePattern finds the line under a table's header
+	markdownTableRulePattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+
+	markdownCodePattern     = regexp.MustCompile("`([^`]+)`")
+	markdownImagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	markdownLinkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	markdownAutoLinkPattern = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	markdownStrongPattern   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownEmPattern       = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+)
+
+// markdownToHTML converts Markdown to HTML, for htmlToStorage to sanitize like any other
+// page. It covers what documents commonly use: headings, paragraphs, lists, block quotes,
+// fenced code, tables, rules, links, images and emphasis. HTML in the Markdown is passed on.
+func markdownToHTML(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	var out strings.Builder
+	var paragraph []string
+	var lists []int // Indentation of each open list
+	var listTags []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			fmt.Fprintf(&out, "<p>%s</p>\n", markdownInline(strings.Join(paragraph, "\n")))
+			paragraph = nil
+		}
+	}
+	closeLists := func(indent int) {
+		for len(lists) > 0 && lists[len(lists)-1] >= indent {
+			fmt.Fprintf(&out, "</li></%s>\n", listTags[len(listTags)-1])
+			lists, listTags = lists[:len(lists)-1], listTags[:len(listTags)-1]
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if fence := markdownFencePattern.FindStringSubmatch(line); fence != nil {
+			flush()
+			closeLists(0)
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence[1]); i++ {
+				code = append(code, lines[i])
+			}
+			fmt.Fprintf(&out, "<pre>%s</pre>\n", html.EscapeString(strings.Join(code, "\n")))
+			continue
+		}
+
+		if item := markdownListPattern.FindStringSubmatch(line); item != nil && !markdownRulePattern.MatchString(line) {
+			flush()
+			indent := len(item[1])
+			tag := "ul"
+			if item[2][0] >= '0' && item[2][0] <= '9' {
+				tag = "ol"
+			}
+			switch {
+			case len(lists) > 0 && indent > lists[len(lists)-1]:
+				fmt.Fprintf(&out, "<%s>", tag)
+				lists, listTags = append(lists, indent), append(listTags, tag)
+			default:
+				closeLists(indent + 1)
+				if len(lists) > 0 && listTags[len(listTags)-1] == tag {
+					out.WriteString("</li>")
+				} else {
+					closeLists(indent)
+					fmt.Fprintf(&out, "<%s>", tag)
+					lists, listTags = append(lists, indent), append(listTags, tag)
+				}
+			}
+			fmt.Fprintf(&out, "<li>%s", markdownInline(item[3]))
+			continue
+		}
+		if len(lists) > 0 && trimmed != "" && line != trimmed {
+			// An indented line continues the list item above it
+			fmt.Fprintf(&out, " %s", markdownInline(trimmed))
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+			closeLists(0)
+		case markdownRulePattern.MatchString(line):
+			flush()
+			closeLists(0)
+			out.WriteString("<hr>\n")
+		case markdownHeadingPattern.MatchString(trimmed):
+			flush()
+			closeLists(0)
+			heading := markdownHeadingPattern.FindStringSubmatch(trimmed)
+			fmt.Fprintf(&out, "<h%d>%s</h%[1]d>\n", len(heading[1]), markdownInline(heading[2]))
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			closeLists(0)
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			i--
+			fmt.Fprintf(&out, "<blockquote>%s</blockquote>\n", markdownToHTML(strings.Join(quote, "\n")))
+		case strings.Contains(trimmed, "|") && i+1 < len(lines) && markdownTableRulePattern.MatchString(lines[i+1]):
+			flush()
+			closeLists(0)
+			out.WriteString("<table><tr>")
+			for _, cell := range markdownCells(trimmed) {
+				fmt.Fprintf(&out, "<th>%s</th>", markdownInline(cell))
+			}
+			out.WriteString("</tr>")
+			for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+				out.WriteString("<tr>")
+				for _, cell := range markdownCells(strings.TrimSpace(lines[i])) {
+					fmt.Fprintf(&out, "<td>%s</td>", markdownInline(cell))
+				}
+				out.WriteString("</tr>")
+			}
+			i--
+			out.WriteString("</table>\n")
+		default:
+			closeLists(0)
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	closeLists(0)
+	return out.String()
+}
+
+// markdownCells splits a table row into its cells
+func markdownCells(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// markdownInline converts the inline markup of a line of Markdown to HTML. Code spans
+// are converted first, so markup inside them is shown as it is.
+func markdownInline(text string) string {
+	var spans []string
+	text = markdownCodePattern.ReplaceAllStringFunc(text, func(span string) string {
+		spans = append(spans, "<code>"+html.EscapeString(span[1:len(span)-1])+"</code>")
+		return fmt.Sprintf("\x00code:%d\x00", len(spans)-1)
+	})
+
+	text = markdownImagePattern.ReplaceAllStringFunc(text, func(image string) string {
+		match := markdownImagePattern.FindStringSubmatch(image)
+		return fmt.Sprintf(`<img src="%s" alt="%s">`, html.EscapeString(match[2]), html.EscapeString(match[1]))
+	})
+	text = markdownLinkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		match := markdownLinkPattern.FindStringSubmatch(link)
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(match[2]), match[1])
+	})
+	text = markdownAutoLinkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		address := html.EscapeString(link[1 : len(link)-1])
+		return fmt.Sprintf(`<a href="%s">%s</a>`, address, address)
+	})
+	text = markdownStrongPattern.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = markdownEmPattern.ReplaceAllString(text, "<em>$1$2</em>")
+	text = strings.ReplaceAll(text, "\n", " ")
+
+	for i, span := range spans {
+		text = strings.Replace(text, fmt.Sprintf("\x00code:%d\x00", i), span, 1)
+	}
+	return text
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// maxTaxonomyPages bounds how many pages of a space are read for their labels
+const maxTaxonomyPages = 2000
+
+// labelUsage is a label and how many pages have it
+type labelUsage struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// handleGetLabelTaxonomy lists the global labels used in a space, with how many pages and
+// blog posts have each. Confluence only keeps labels on content, so they are collected
+// from the space's pages.
+func (s *Service) handleGetLabelTaxonomy(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+	spaceKey, _ := req.Params["space_key"].(string)
+	if !spaceKeyPattern.MatchString(spaceKey) {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing or invalid space_key", req.RequestID)
+	}
+
+	cql := fmt.Sprintf("space = %s AND type IN (page, blogpost)", cqlString(spaceKey))
+	counts := make(map[string]int)
+	scanned := 0
+	truncated := false
+	for {
+		results, err := client.SearchPages(cql, auditListPageSize, scanned, []string{"metadata.labels"})
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		for _, page := range results.Results {
+			if page.Metadata == nil {
+				continue
+			}
+			for _, label := range page.Metadata.Labels.Results {
+				if label.Prefix == "global" {
+					counts[label.Name]++
+				}
+			}
+		}
+		scanned += len(results.Results)
+		if results.Links.Next == "" || len(results.Results) == 0 {
+			break
+		}
+		if scanned >= maxTaxonomyPages {
+			truncated = true
+			break
+		}
+	}
+
+	return models.SuccessResponse(map[string]interface{}{
+		"space_key":     spaceKey,
+		"labels":        sortedLabelUsage(counts),
+		"pages_scanned": scanned,
+		"truncated":     truncated,
+	}, req.RequestID)
+}
+
+// sortedLabelUsage lists labels by name
+func sortedLabelUsage(counts map[string]int) []labelUsage {
+	labels := make([]labelUsage, 0, len(counts))
+	for name, count := range counts {
+		labels = append(labels, labelUsage{Name: name, Count: count})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
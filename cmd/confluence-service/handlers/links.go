@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	// maxPageLinks bounds how many linked pages and external URLs are listed each way
+	maxPageLinks = 200
+	// defaultIncomingCandidates and maxIncomingCandidates bound how many pages mentioning
+	// the title are checked for incoming links
+	defaultIncomingCandidates = 50
+	maxIncomingCandidates     = 200
+	// linkLookupBatchSize is how many linked pages each CQL lookup resolves
+	linkLookupBatchSize = 20
+)
+
+// linkExternalPattern matches links to other sites
+var linkExternalPattern = regexp.MustCompile(`href="(https?://[^"]+)"`)
+
+// storagePageLink is a link to a page in a storage format body, by title (and space, when
+// it points into another space) or by ID
+type storagePageLink struct {
+	Title    string
+	SpaceKey string
+	ID       string
+}
+
+// parsePageLinks returns the page links of a storage format body, in order
+func parsePageLinks(body string) []storagePageLink {
+	var links []storagePageLink
+	for _, match := range auditPageLinkPattern.FindAllStringSubmatch(body, -1) {
+		attrs := make(map[string]string)
+		for _, attr := range auditAttributePattern.FindAllStringSubmatch(match[1], -1) {
+			attrs[attr[1]] = html.UnescapeString(attr[2])
+		}
+		if title := attrs["ri:content-title"]; title != "" {
+			links = append(links, storagePageLink{Title: title, SpaceKey: attrs["ri:space-key"]})
+		}
+	}
+	for _, match := range auditHrefPattern.FindAllStringSubmatch(body, -1) {
+		links = append(links, storagePageLink{ID: match[1]})
+	}
+	return links
+}
+
+// linksTo reports whether a link in a page of linkingSpace points to the target page
+func (l storagePageLink) linksTo(linkingSpace string, target models.ConfluencePage) bool {
+	if l.ID != "" {
+		return l.ID == target.ID
+	}
+	space := l.SpaceKey
+	if space == "" {
+		space = linkingSpace
+	}
+	return l.Title == target.Title && strings.EqualFold(space, target.Space.Key)
+}
+
+// linkNode is a page in a link graph
+type linkNode struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	SpaceKey string `json:"space_key,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// linkEdge is a link from one page to another, by page ID
+type linkEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// brokenLink is a link to a page that was not found
+type brokenLink struct {
+	Title    string `json:"title,omitempty"`
+	SpaceKey string `json:"space_key,omitempty"`
+	ID       string `json:"id,omitempty"`
+}
+
+// handleGetPageLinks returns the links of a page as a graph: the pages it links to, found
+// in its storage format, and the pages linking to it. Confluence cannot search for links,
+// so incoming links are found among the pages whose text mentions the page's title, and
+// checked in their storage format.
+func (s *Service) handleGetPageLinks(client *api.Client, req models.ConfluenceRequest, policy *models.WorkspacePolicy) map[string]interface{} {
+	pageID, _ := req.Params["page_id"].(string)
+	if pageID == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing page_id", req.RequestID)
+	}
+	direction, _ := req.Params["direction"].(string)
+	if direction == "" {
+		direction = "both"
+	}
+	if direction != "outgoing" && direction != "incoming" && direction != "both" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("invalid direction %q; use outgoing, incoming or both", direction), req.RequestID)
+	}
+	limit := defaultIncomingCandidates
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = min(int(l), maxIncomingCandidates)
+	}
+
+	results, err := client.SearchPages(fmt.Sprintf("id = %s", pageID), 1, 0, []string{"body.storage", "space"})
+	if err == nil && len(results.Results) == 0 {
+		err = fmt.Errorf("page %s not found", pageID)
+	}
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	page := results.Results[0]
+	base := results.Links.Base
+
+	nodes := map[string]linkNode{page.ID: pageNode(page, base)}
+	edges := []linkEdge{}
+	result := map[string]interface{}{
+		"page_id":   page.ID,
+		"direction": direction,
+	}
+
+	if direction != "incoming" {
+		linked, broken, err := resolvePageLinks(client, policy, page)
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		for _, target := range linked {
+			nodes[target.ID] = pageNode(target, base)
+			edges = append(edges, linkEdge{From: page.ID, To: target.ID})
+		}
+		result["broken_links"] = broken
+		result["external_links"] = externalLinks(page.Body.Storage.Value)
+	}
+
+	if direction != "outgoing" {
+		linking, checked, truncated, err := findIncomingLinks(client, policy, page, limit)
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		for _, source := range linking {
+			nodes[source.ID] = pageNode(source, base)
+			edges = append(edges, linkEdge{From: source.ID, To: page.ID})
+		}
+		result["incoming_checked"] = checked
+		result["incoming_truncated"] = truncated
+	}
+
+	nodeList := make([]linkNode, 0, len(nodes))
+	for _, node := range nodes {
+		nodeList = append(nodeList, node)
+	}
+	sort.Slice(nodeList, func(i, j int) bool {
+		if (nodeList[i].ID == page.ID) != (nodeList[j].ID == page.ID) {
+			return nodeList[i].ID == page.ID
+		}
+		return nodeList[i].Title < nodeList[j].Title
+	})
+	result["nodes"] = nodeList
+	result["edges"] = edges
+	return models.SuccessResponse(result, req.RequestID)
+}
+
+// resolvePageLinks looks up the pages a page links to, in the order of their first link.
+// Links to pages that do not exist, or that the user cannot see, are returned as broken;
+// links into spaces the workspace policy does not allow are left out.
+func resolvePageLinks(client *api.Client, policy *models.WorkspacePolicy, page models.ConfluencePage) ([]models.ConfluencePage, []brokenLink, error) {
+	var links []storagePageLink
+	seen := make(map[storagePageLink]bool)
+	for _, link := range parsePageLinks(page.Body.Storage.Value) {
+		if link.ID == "" && link.SpaceKey == "" {
+			link.SpaceKey = page.Space.Key
+		}
+		if link.ID == page.ID || (link.Title == page.Title && strings.EqualFold(link.SpaceKey, page.Space.Key)) || seen[link] {
+			continue
+		}
+		if link.SpaceKey != "" && !policy.AllowsSpace(link.SpaceKey) {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+		if len(links) == maxPageLinks {
+			break
+		}
+	}
+
+	found := make(map[storagePageLink]models.ConfluencePage)
+	for i := 0; i < len(links); i += linkLookupBatchSize {
+		batch := links[i:min(i+linkLookupBatchSize, len(links))]
+		var clauses []string
+		for _, link := range batch {
+			if link.ID != "" {
+				clauses = append(clauses, "id = "+link.ID)
+			} else {
+				clauses = append(clauses, fmt.Sprintf("(space = %s AND title = %s)", cqlString(link.SpaceKey), cqlString(link.Title)))
+			}
+		}
+		results, err := client.SearchPages(strings.Join(clauses, " OR "), len(batch), 0, []string{"space"})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, target := range results.Results {
+			for _, link := range batch {
+				if link.linksTo(link.SpaceKey, target) {
+					found[link] = target
+				}
+			}
+		}
+	}
+
+	linked := []models.ConfluencePage{}
+	broken := []brokenLink{}
+	listed := make(map[string]bool)
+	for _, link := range links {
+		target, ok := found[link]
+		if !ok {
+			broken = append(broken, brokenLink{Title: link.Title, SpaceKey: link.SpaceKey, ID: link.ID})
+			continue
+		}
+		// Pages linked by ID may be in any space
+		if listed[target.ID] || !policy.AllowsSpace(target.Space.Key) {
+			continue
+		}
+		listed[target.ID] = true
+		linked = append(linked, target)
+	}
+	return linked, broken, nil
+}
+
+// findIncomingLinks checks up to limit pages that mention the page's title, most recently
+// modified first, and returns those that link to it, how many were checked, and whether
+// there were more to check
+func findIncomingLinks(client *api.Client, policy *models.WorkspacePolicy, page models.ConfluencePage, limit int) ([]models.ConfluencePage, int, bool, error) {
+	phrase := strings.NewReplacer(`"`, " ", `\`, " ").Replace(page.Title)
+	cql := fmt.Sprintf(`type = page AND id != %s AND text ~ %s ORDER BY lastmodified DESC`, page.ID, cqlString(`"`+phrase+`"`))
+	if policy.RestrictsSpaces() {
+		cql = atlassian.ScopeQuery(cql, "space", policy.SpaceAllowlist)
+	}
+
+	linking := []models.ConfluencePage{}
+	checked := 0
+	for checked < limit {
+		// Confluence returns fewer results per request when their bodies are expanded
+		results, err := client.SearchPages(cql, min(auditBodyBatchSize, limit-checked), checked, []string{"body.storage", "space"})
+		if err != nil {
+			return nil, 0, false, err
+		}
+		for _, candidate := range results.Results {
+			for _, link := range parsePageLinks(candidate.Body.Storage.Value) {
+				if link.linksTo(candidate.Space.Key, page) {
+					linking = append(linking, candidate)
+					break
+				}
+			}
+		}
+		checked += len(results.Results)
+		if results.Links.Next == "" || len(results.Results) == 0 {
+			return linking, checked, false, nil
+		}
+	}
+	return linking, checked, true, nil
+}
+
+// externalLinks returns the distinct links of a storage format body to other sites, other
+// than links to Confluence pages by URL
+func externalLinks(body string) []string {
+	links := []string{}
+	seen := make(map[string]bool)
+	for _, match := range linkExternalPattern.FindAllStringSubmatch(body, -1) {
+		link := html.UnescapeString(match[1])
+		if seen[link] || auditHrefPattern.MatchString(match[0]) {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+		if len(links) == maxPageLinks {
+			break
+		}
+	}
+	return links
+}
+
+// pageNode describes a page in a link graph
+func pageNode(page models.ConfluencePage, base string) linkNode {
+	node := linkNode{ID: page.ID, Title: page.Title, SpaceKey: page.Space.Key}
+	if page.Links.WebUI != "" && base != "" {
+		node.URL = base + page.Links.WebUI
+	}
+	return node
+}
+
+// cqlString quotes a value for CQL
+func cqlString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// enforcePolicy checks a request against the workspace policy. It returns an error
+// response when the request is not allowed, and rewrites the CQL of searches so that
+// they only match allowlisted spaces.
+func (s *Service) enforcePolicy(client *api.Client, policy *models.WorkspacePolicy, req *models.ConfluenceRequest) map[string]interface{} {
+	if policy == nil {
+		return nil
+	}
+
+	toolName := "confluence_" + req.Action
+	// Undoing a deletion (see handleDeletePage) is allowed wherever the deletion was
+	if req.Action == "restore_page" {
+		toolName = "confluence_delete_page"
+	}
+	if !policy.AllowsTool(toolName) {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("tool %s is not allowed for this workspace", toolName), req.RequestID)
+	}
+
+	if policy.ReadOnly && models.ConfluenceMutatingActions[req.Action] {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("workspace is read-only; %s is not allowed", toolName), req.RequestID)
+	}
+
+	// copy_page spans two workspaces and is checked in handleCopyPage; trashed pages
+	// were checked when they were deleted and cannot be looked up
+	if !policy.RestrictsSpaces() || req.Action == "copy_page" || req.Action == "restore_page" {
+		return nil
+	}
+
+	// tools.yaml tools can call any endpoint, so they cannot be limited to spaces
+	if _, custom := s.customTools[req.Action]; custom {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("%s is not available for space-restricted workspaces", toolName), req.RequestID)
+	}
+
+	if key, ok := req.Params["space_key"].(string); ok && key != "" && !policy.AllowsSpace(key) {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("space %s is not allowed for this workspace", key), req.RequestID)
+	}
+
+	if pageID, ok := req.Params["page_id"].(string); ok && pageID != "" {
+		if response := checkPageSpace(client, policy, pageID, req.RequestID); response != nil {
+			return response
+		}
+	}
+
+	if req.Action == "search" {
+		if query, ok := req.Params["query"].(string); ok {
+			req.Params["query"] = atlassian.ScopeQuery(query, "space", policy.SpaceAllowlist)
+		}
+	}
+
+	return nil
+}
+
+// checkPageSpace rejects pages that live outside the policy's space allowlist
+func checkPageSpace(client *api.Client, policy *models.WorkspacePolicy, pageID, requestID string) map[string]interface{} {
+	if !policy.RestrictsSpaces() {
+		return nil
+	}
+	spaceKey, err := client.GetPageSpaceKey(pageID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), requestID)
+	}
+	if !policy.AllowsSpace(spaceKey) {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("page %s is in space %s, which is not allowed for this workspace", pageID, spaceKey), requestID)
+	}
+	return nil
+}
+
+// filterSpaces drops spaces that the workspace policy does not allow
+func filterSpaces(policy *models.WorkspacePolicy, spaces []models.ConfluenceSpace) []models.ConfluenceSpace {
+	if !policy.RestrictsSpaces() {
+		return spaces
+	}
+	filtered := []models.ConfluenceSpace{}
+	for _, space := range spaces {
+		if policy.AllowsSpace(space.Key) {
+			filtered = append(filtered, space)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// redact applies the redaction policy to a successful response
+func (s *Service) redact(response map[string]interface{}, requestID string) map[string]interface{} {
+	if succeeded, _ := response["success"].(bool); !succeeded || s.redaction == nil {
+		return response
+	}
+	data, err := atlassian.ApplyRedaction(s.redaction, response["data"], nil)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInternal, fmt.Sprintf("failed to redact the result: %v", err), requestID)
+	}
+	response["data"] = data
+	return response
+}
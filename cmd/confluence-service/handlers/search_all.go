@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// allWorkspacesConcurrency is how many workspaces are searched at once
+const allWorkspacesConcurrency = 4
+
+// workspaceSearchResult is a search result tagged with the workspace it came from
+type workspaceSearchResult struct {
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceName string `json:"workspace_name"`
+	URL           string `json:"url,omitempty"`
+	models.ConfluencePage
+}
+
+// searchAllWorkspaces runs a CQL search in every workspace the user owns or shares through
+// their organization, several at a time, and merges the results. Each workspace search is
+// an ordinary search, subject to that workspace's policy. A workspace that fails is
+// reported rather than failing the whole request.
+func (s *Service) searchAllWorkspaces(ctx context.Context, req models.ConfluenceRequest) map[string]interface{} {
+	query, _ := req.Params["query"].(string)
+	if query == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing query", req.RequestID)
+	}
+
+	workspaces, err := s.credStore.ListWorkspaces(req.UserID)
+	if err == nil && req.OrgID != "" {
+		var shared []models.AtlassianCredential
+		shared, err = s.credStore.ListWorkspaces(req.OrgID)
+		workspaces = append(workspaces, shared...)
+	}
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInternal, fmt.Sprintf("failed to list workspaces: %v", err), req.RequestID)
+	}
+	seen := make(map[string]bool)
+	unique := workspaces[:0]
+	for _, ws := range workspaces {
+		if !seen[ws.WorkspaceID] {
+			seen[ws.WorkspaceID] = true
+			unique = append(unique, ws)
+		}
+	}
+	workspaces = unique
+	if len(workspaces) == 0 {
+		return models.ErrorResponse(models.ErrCodeNotFound, "no workspaces configured", req.RequestID)
+	}
+
+	responses := make([]map[string]interface{}, len(workspaces))
+	sem := make(chan struct{}, allWorkspacesConcurrency)
+	var wg sync.WaitGroup
+	for i, ws := range workspaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// The policy rewrites the query, so each workspace gets its own parameters
+			params := make(map[string]interface{}, len(req.Params))
+			for k, v := range req.Params {
+				params[k] = v
+			}
+			workspaceReq := req
+			workspaceReq.Action = "search"
+			workspaceReq.WorkspaceID = ws.WorkspaceID
+			workspaceReq.Params = params
+			responses[i] = s.dispatch(ctx, workspaceReq)
+		}()
+	}
+	wg.Wait()
+
+	var apiBytes int64
+	summaries := make([]map[string]interface{}, 0, len(workspaces))
+	ranked := make([][]workspaceSearchResult, len(workspaces))
+	for i, ws := range workspaces {
+		response := responses[i]
+		if usage, ok := response["usage"].(*models.UsageInfo); ok {
+			apiBytes += usage.APIBytes
+		}
+
+		summary := map[string]interface{}{
+			"workspace_id":   ws.WorkspaceID,
+			"workspace_name": ws.WorkspaceName,
+			"site":           ws.AtlassianURL,
+		}
+		if ws.ConfluenceURL != "" {
+			summary["site"] = ws.ConfluenceURL
+		}
+		if results, ok := response["data"].(*models.SearchResults); ok {
+			for _, page := range results.Results {
+				result := workspaceSearchResult{WorkspaceID: ws.WorkspaceID, WorkspaceName: ws.WorkspaceName, ConfluencePage: page}
+				if page.Links.WebUI != "" && results.Links.Base != "" {
+					result.URL = results.Links.Base + page.Links.WebUI
+				}
+				ranked[i] = append(ranked[i], result)
+			}
+			summary["count"] = len(results.Results)
+		} else if info, ok := response["error"].(*models.ErrorInfo); ok {
+			summary["error"] = info.Message
+		}
+		summaries = append(summaries, summary)
+	}
+
+	// Results are interleaved by rank, so every workspace's best matches come first
+	merged := []workspaceSearchResult{}
+	for rank := 0; ; rank++ {
+		added := false
+		for _, results := range ranked {
+			if rank < len(results) {
+				merged = append(merged, results[rank])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+
+	response := models.SuccessResponse(map[string]interface{}{
+		"query":      query,
+		"results":    merged,
+		"workspaces": summaries,
+	}, req.RequestID)
+	response["usage"] = &models.UsageInfo{APIBytes: apiBytes}
+	return response
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+var (
+	// sectionHeadingPattern finds the headings of a page body in storage format
+	sectionHeadingPattern = regexp.MustCompile(`(?is)<h([1-6])(?:\s[^>]*)?>(.*?)</h[1-6]>`)
+	// sectionTagPattern strips the markup inside a heading, e.g. links and emphasis
+	sectionTagPattern = regexp.MustCompile(`<[^>]*>`)
+)
+
+// updateModes are the ways update_page may change a page's body
+var updateModes = map[string]bool{
+	"replace":         true, // The whole body
+	"append_section":  true, // Add to the end of one section
+	"replace_section": true, // Replace one section's content, keeping its heading
+}
+
+// patchSection changes the section of a storage format body that starts at the heading
+// titled section (compared without markup or case). A section runs to the next heading
+// of the same or a higher level, so it includes its subsections. append_section adds
+// content at its end and replace_section replaces everything under its heading.
+func patchSection(body, section, content, mode string) (string, error) {
+	headings := sectionHeadingPattern.FindAllStringSubmatchIndex(body, -1)
+	want := strings.ToLower(strings.TrimSpace(section))
+
+	var titles []string
+	for i, match := range headings {
+		title := headingText(body[match[4]:match[5]])
+		titles = append(titles, title)
+		if strings.ToLower(title) != want {
+			continue
+		}
+
+		level, _ := strconv.Atoi(body[match[2]:match[3]])
+		start, end := match[1], len(body)
+		for _, next := range headings[i+1:] {
+			if nextLevel, _ := strconv.Atoi(body[next[2]:next[3]]); nextLevel <= level {
+				end = next[0]
+				break
+			}
+		}
+
+		if mode == "append_section" {
+			return body[:end] + content + body[end:], nil
+		}
+		return body[:start] + content + body[end:], nil
+	}
+
+	if len(titles) == 0 {
+		return "", fmt.Errorf("section %q not found: the page has no headings", section)
+	}
+	return "", fmt.Errorf("section %q not found; the page's headings are: %s", section, strings.Join(titles, ", "))
+}
+
+// headingText is a heading's text without markup or entities
+func headingText(inner string) string {
+	text := html.UnescapeString(sectionTagPattern.ReplaceAllString(inner, ""))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// editPage updates a page's body to what edit makes of the current one, keeping its title
+// unless the request gives one. An expected_version param makes it fail with CONFLICT when
+// the page has a newer version than the edit was based on. Errors from edit are the caller's.
+func (s *Service) editPage(client *api.Client, req models.ConfluenceRequest, pageID string, edit func(current string) (string, error)) map[string]interface{} {
+	// Get current page to retrieve version
+	currentPage, err := client.GetPage(pageID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	// The caller's edit was based on expected_version; don't overwrite anything newer
+	version := currentPage.Version.Number
+	if expected, ok := req.Params["expected_version"].(float64); ok && expected > 0 {
+		if int(expected) != version {
+			return models.ErrorResponse(models.ErrCodeConflict,
+				fmt.Sprintf("page %s is at version %d, not the expected version %d", pageID, version, int(expected)), req.RequestID)
+		}
+	}
+
+	body, err := edit(currentPage.Body.Storage.Value)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	}
+
+	// Use provided title or keep existing
+	title := currentPage.Title
+	if t, ok := req.Params["title"].(string); ok && t != "" {
+		title = t
+	}
+
+	// Confluence rejects the new version with 409 Conflict if the page changed since it was read
+	updatedPage, err := client.UpdatePage(pageID, title, body, version+1)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	response := models.SuccessResponse(updatedPage, req.RequestID)
+	// Undoing restores the previous title and body, unless the page changed again since
+	response["undo"] = &models.UndoAction{
+		Action: "update_page",
+		Params: map[string]interface{}{
+			"page_id":          pageID,
+			"title":            currentPage.Title,
+			"body":             currentPage.Body.Storage.Value,
+			"expected_version": updatedPage.Version.Number,
+		},
+		Description: fmt.Sprintf("restore page %s (%s) to version %d", pageID, currentPage.Title, version),
+	}
+	return response
+}
+
+// handleAppendToPage adds content to the end of a page, or of the section under heading
+func (s *Service) handleAppendToPage(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+	pageID, ok := req.Params["page_id"].(string)
+	if !ok || pageID == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing page_id", req.RequestID)
+	}
+	content, ok := req.Params["content"].(string)
+	if !ok || strings.TrimSpace(content) == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing content", req.RequestID)
+	}
+	heading, _ := req.Params["heading"].(string)
+
+	return s.editPage(client, req, pageID, func(current string) (string, error) {
+		if strings.TrimSpace(heading) == "" {
+			return current + content, nil
+		}
+		return patchSection(current, heading, content, "append_section")
+	})
+}
+
+// handleReplaceSection replaces the content under one heading of a page
+func (s *Service) handleReplaceSection(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+	pageID, ok := req.Params["page_id"].(string)
+	if !ok || pageID == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing page_id", req.RequestID)
+	}
+	heading, ok := req.Params["heading"].(string)
+	if !ok || strings.TrimSpace(heading) == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing heading", req.RequestID)
+	}
+	// Empty content clears the section
+	content, ok := req.Params["content"].(string)
+	if !ok {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing content", req.RequestID)
+	}
+
+	return s.editPage(client, req, pageID, func(current string) (string, error) {
+		return patchSection(current, heading, content, "replace_section")
+	})
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// maxShareAncestors bounds how many ancestors' view restrictions are checked; a page
+// deeper than this reports its audience as unknown rather than guessing
+const maxShareAncestors = 25
+
+// Audiences of a shared page
+const (
+	shareAudienceSpace      = "space"      // Everyone who can view the space
+	shareAudienceRestricted = "restricted" // Only the users and groups its view restrictions name
+	shareAudienceDraft      = "draft"      // Only the author and the people they invited
+	shareAudienceUnknown    = "unknown"    // Too deep to check every ancestor
+)
+
+// shareRestriction is a view restriction that limits who can open a shared page
+type shareRestriction struct {
+	PageID    string   `json:"page_id"`
+	Title     string   `json:"title,omitempty"`
+	Inherited bool     `json:"inherited"` // Set on an ancestor, so it applies to the page too
+	Users     []string `json:"users,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+}
+
+// handleGetShareLink returns a page's links and who can open them. A page is restricted when
+// it or any ancestor has a view restriction; a recipient must pass all of them.
+func (s *Service) handleGetShareLink(client *api.Client, req models.ConfluenceRequest) map[string]interface{} {
+	pageID, _ := req.Params["page_id"].(string)
+	if pageID == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing page_id", req.RequestID)
+	}
+
+	page, err := client.GetPageLocation(pageID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	result := map[string]interface{}{
+		"page_id":   page.ID,
+		"title":     page.Title,
+		"space_key": page.Space.Key,
+	}
+	if page.Links.Base != "" {
+		if page.Links.WebUI != "" {
+			result["url"] = page.Links.Base + page.Links.WebUI
+		}
+		if page.Links.TinyUI != "" {
+			result["tiny_url"] = page.Links.Base + page.Links.TinyUI
+		}
+	}
+
+	if page.Status == "draft" {
+		result["audience"] = shareAudienceDraft
+		result["warning"] = "This page is an unpublished draft; only its author and the people they invited can open it"
+		return models.SuccessResponse(result, req.RequestID)
+	}
+
+	// The page's own restriction comes first, then its ancestors' from the nearest up
+	chain := []models.AncestorRef{{ID: page.ID, Title: page.Title}}
+	for i := len(page.Ancestors) - 1; i >= 0; i-- {
+		chain = append(chain, page.Ancestors[i])
+	}
+	truncated := len(chain) > maxShareAncestors+1
+	if truncated {
+		chain = chain[:maxShareAncestors+1]
+	}
+
+	restrictions := []shareRestriction{}
+	for _, ref := range chain {
+		restriction, err := client.GetReadRestriction(ref.ID)
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		if !restriction.Restricted() {
+			continue
+		}
+		entry := shareRestriction{PageID: ref.ID, Title: ref.Title, Inherited: ref.ID != page.ID}
+		for _, user := range restriction.Restrictions.User.Results {
+			name := user.DisplayName
+			if name == "" {
+				name = user.AccountID
+			}
+			entry.Users = append(entry.Users, name)
+		}
+		for _, group := range restriction.Restrictions.Group.Results {
+			entry.Groups = append(entry.Groups, group.Name)
+		}
+		restrictions = append(restrictions, entry)
+	}
+	result["restrictions"] = restrictions
+
+	switch {
+	case len(restrictions) > 0:
+		result["audience"] = shareAudienceRestricted
+		if len(restrictions) == 1 {
+			result["warning"] = fmt.Sprintf("Only the users and groups listed in restrictions can open this page; other recipients will see a permission error (restricted on %q)", restrictions[0].Title)
+		} else {
+			result["warning"] = fmt.Sprintf("Only recipients allowed by all %d view restrictions listed can open this page; others will see a permission error", len(restrictions))
+		}
+	case truncated:
+		result["audience"] = shareAudienceUnknown
+		result["warning"] = fmt.Sprintf("Only the nearest %d ancestors were checked for view restrictions; a restriction further up could still hide the page", maxShareAncestors)
+	default:
+		result["audience"] = shareAudienceSpace
+		result["message"] = fmt.Sprintf("Anyone who can view space %s can open this page", page.Space.Key)
+	}
+	return models.SuccessResponse(result, req.RequestID)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// summaryExpand are the page properties a summarized search asks Confluence for
+var summaryExpand = []string{"space", "version"}
+
+// summarizePages reduces search results to one line per page:
+// "ID | title | space | status | last editor | updated"
+func summarizePages(results *models.SearchResults) map[string]interface{} {
+	lines := make([]string, len(results.Results))
+	for i, page := range results.Results {
+		editor := ""
+		if page.Version.By != nil {
+			editor = page.Version.By.DisplayName
+		}
+		title := strings.Join(strings.Fields(page.Title), " ")
+		lines[i] = strings.Join([]string{page.ID, title, page.Space.Key, page.Status, editor, summaryTime(page.Version.When)}, " | ")
+	}
+
+	summary := map[string]interface{}{
+		"start":   results.Start,
+		"results": lines,
+	}
+	if results.Links.Next != "" {
+		summary["next_start"] = results.Start + results.Size
+	}
+	return summary
+}
+
+// summaryTime shortens an Atlassian timestamp such as "2026-01-15T09:30:00.000Z" to the
+// minute, "2026-01-15 09:30"
+func summaryTime(s string) string {
+	if len(s) < 16 {
+		return s
+	}
+	return strings.Replace(s[:16], "T", " ", 1)
+}
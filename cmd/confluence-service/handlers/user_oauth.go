@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// clientCredentials returns what a workspace's client calls Confluence with: the
+// workspace's API token, or in user OAuth workspaces the caller's own access token, so
+// searches and page reads respect the caller's space and page restrictions
+func (s *Service) clientCredentials(ctx context.Context, userID string, creds *models.WorkspaceCredentials) (api.WorkspaceCredentials, error) {
+	clientCreds := api.WorkspaceCredentials{
+		Site:  creds.ConfluenceSite(),
+		Email: creds.Email,
+		Token: creds.Token,
+
+		ProxyURL: creds.ProxyURL,
+	}
+	if !creds.Policy.UsesUserOAuth() {
+		return clientCreds, nil
+	}
+
+	accessToken, err := s.userTokens.AccessToken(ctx, userID)
+	if err != nil {
+		return api.WorkspaceCredentials{}, err
+	}
+	site, err := atlassian.GatewayURL(ctx, "confluence", creds.Site, creds.ProxyURL)
+	if err != nil {
+		return api.WorkspaceCredentials{}, err
+	}
+	// Confluence's APIs sit under /wiki on the gateway as on the site
+	clientCreds.Site = site + "/wiki"
+	clientCreds.Email, clientCreds.Token = "", ""
+	clientCreds.AccessToken = accessToken
+	return clientCreds, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,72 +11,64 @@ import (
 	"time"
 
 	"github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/handlers"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/broker"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/bus"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/cache"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/events"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/health"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
 	"github.com/providentiaww/twistygo"
 	amqp "github.com/rabbitmq/amqp091-go"
-	"gopkg.in/yaml.v3"
 )
 
 const ServiceVersion = "v1.0.0"
 
 var rconn *twistygo.AmqpConnection_t
 
-type AppConfig struct {
-	Atlassian struct {
-		Timeout string `yaml:"timeout"`
-	} `yaml:"atlassian"`
-}
-
 func init() {
-	
 	config.LoadEnv("../../.env")
+	logging.Setup("confluence-service")
 }
 
 func main() {
 	// Initialize TwistyGo
 	twistygo.LogStartService("ConfluenceService", ServiceVersion)
 
-	// Initialize RabbitMQ with retries
-	maxRetries := 5
-	var err error
-	for i := 0; i < maxRetries; i++ {
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					err = fmt.Errorf("panic: %v", r)
-				}
-			}()
-			rconn = twistygo.AmqpConnect()
-			if rconn != nil {
-				err = nil
-			}
-		}()
-		if err == nil && rconn != nil {
-			break
-		}
-		if i < maxRetries-1 {
-			fmt.Printf("⚠️ Failed to connect to RabbitMQ (attempt %d/%d): %v. Retrying in 5s...\n", i+1, maxRetries, err)
-			time.Sleep(5 * time.Second)
-		}
+	// Select the transport to the MCP server
+	busKind, err := bus.KindFromEnv()
+	if err != nil {
+		panic(fmt.Sprintf("❌ %v", err))
 	}
-	if rconn == nil {
-		panic(fmt.Sprintf("❌ Failed to connect to RabbitMQ after %d attempts: %v", maxRetries, err))
+	if busKind == bus.KindMemory {
+		panic("❌ MESSAGE_BUS=memory runs the Confluence service inside mcp-server; this process is not needed")
 	}
+	maxRetries := 5
 
-	// Load queue and service definitions
-	rconn.AmqpLoadQueues("ConfluenceRequests")
-	rconn.AmqpLoadServices("ConfluenceService")
+	// Load config.yaml (Atlassian timeout), refusing to start on invalid values
+	settings, err := config.LoadSettings("config.yaml")
+	if err != nil {
+		panic(fmt.Sprintf("❌ %v", err))
+	}
+	timeout := settings.AtlassianTimeout
 
-	// Load custom config for timeout
-	var appConfig AppConfig
-	if configData, err := os.ReadFile("config.yaml"); err == nil {
-		yaml.Unmarshal(configData, &appConfig)
+	// Passthrough tools operators define in tools.yaml (TOOLS_FILE)
+	customTools, err := config.ToolDefinitionsFromEnv()
+	if err != nil {
+		panic(fmt.Sprintf("❌ %v", err))
 	}
-	timeout := 30 * time.Second
-	if appConfig.Atlassian.Timeout != "" {
-		if d, err := time.ParseDuration(appConfig.Atlassian.Timeout); err == nil {
-			timeout = d
+
+	// The Atlassian OAuth app that user OAuth workspaces refresh their callers' tokens with
+	oauthApp, err := atlassian.OAuthConfigFromEnv()
+	if err != nil {
+		panic(fmt.Sprintf("❌ %v", err))
+	}
+	if settings.AtlassianCABundle != "" {
+		if err := atlassian.SetCABundle(settings.AtlassianCABundle); err != nil {
+			panic(fmt.Sprintf("❌ %v", err))
 		}
 	}
 
@@ -87,7 +80,7 @@ func main() {
 			break
 		}
 		if i < maxRetries-1 {
-			fmt.Printf("⚠️ Failed to initialize credential store (attempt %d/%d): %v. Retrying in 5s...\n", i+1, maxRetries, err)
+			slog.Warn("failed to initialize credential store, retrying in 5s", "attempt", i+1, "max_attempts", maxRetries, "error", err)
 			time.Sleep(5 * time.Second)
 		}
 	}
@@ -96,103 +89,191 @@ func main() {
 	}
 	defer credStore.Close()
 
-	// Create service handler
-	service := handlers.NewService(credStore, timeout)
+	// Cache credentials in memory so tool calls don't hit the database every time
+	cachedStore := storage.NewCachedCredentialStore(credStore, storage.CredentialCacheTTLFromEnv())
 
-	// Get service handle
-	svc := rconn.AmqpConnectService("ConfluenceService")
-	if svc == nil {
-		panic("Failed to connect to ConfluenceService queue")
-	}
+	// Create service handler; results of mutating requests with an idempotency_key are
+	// stored next to the credentials so retries do not repeat them
+	idempotencyStore := storage.NewIdempotencyStoreFromEnv(credStore)
+	storage.StartIdempotencyPurgeLoop(idempotencyStore)
+	service := handlers.NewService(cachedStore, timeout).
+		WithIdempotency(idempotencyStore, storage.IdempotencyTTLFromEnv()).
+		WithRedaction(settings.Redaction).
+		WithCache(cache.NewBackendFromEnv("confluence")).
+		WithCustomTools(customTools).
+		WithUserTokens(atlassian.NewUserTokens(storage.NewUserTokenStoreFromEnv(credStore), oauthApp))
 
-	// Manual multi-threaded service loop to avoid twistygo single-threaded bottleneck
-	msgs, err := svc.Amqp.Channel.Consume(
-		svc.Queue.Name,      // queue
-		"",                  // consumer
-		svc.Queue.AutoAck,   // auto-ack
-		svc.Queue.Exclusive, // exclusive
-		false,               // no-local
-		svc.Queue.NoWait,    // no-wait
-		nil,                 // args
-	)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to start consumer: %v", err))
-	}
-
-	go func() {
-		for d := range msgs {
-			go func(delivery amqp.Delivery) {
-				// Process in goroutine
+	var brokerStatus func() (bool, error)
+	if busKind == bus.KindNATS {
+		// Replicas share a NATS queue group; each request goes to one of them. Credential
+		// events are only broadcast over RabbitMQ, so cached credentials expire by TTL.
+		var natsBus *bus.NATS
+		for i := 0; i < maxRetries; i++ {
+			if natsBus, err = bus.NewNATS(bus.NATSURLFromEnv(), "confluence-service"); err == nil {
+				break
+			}
+			if i < maxRetries-1 {
+				slog.Warn("failed to connect to NATS, retrying in 5s", "attempt", i+1, "max_attempts", maxRetries, "error", err)
+				time.Sleep(5 * time.Second)
+			}
+		}
+		if err != nil {
+			panic(fmt.Sprintf("❌ Failed to connect to NATS after %d attempts: %v", maxRetries, err))
+		}
+		defer natsBus.Close()
+		natsBus.Serve(bus.ConfluenceService, func(body []byte, headers bus.Headers) []byte {
+			return service.HandleMessage(body, headers[logging.RequestIDHeader])
+		})
+		brokerStatus = natsBus.Status
+	} else {
+		// Initialize RabbitMQ with retries
+		for i := 0; i < maxRetries; i++ {
+			func() {
 				defer func() {
 					if r := recover(); r != nil {
-						fmt.Printf("❌ Consumer panic recovered: %v\n", r)
-						// Nack the message so it might be retried or dead-lettered
-						// Requeue=false to avoid infinite loop of death if it's deterministic
-						delivery.Nack(false, false)
+						err = fmt.Errorf("panic: %v", r)
 					}
 				}()
-
-				responseBytes := service.HandleRequest(delivery)
-
-				// Use twistygo's global channel to publish reply
-				// We need to set the ReplyTo and the response data
-				err := svc.Amqp.Channel.Publish(
-					"",               // exchange
-					delivery.ReplyTo, // routing key (the reply queue)
-					false,            // mandatory
-					false,            // immediate
-					amqp.Publishing{
-						ContentType:   "application/json",
-						CorrelationId: delivery.CorrelationId,
-						Body:          responseBytes,
-					},
-				)
-				if err != nil {
-					fmt.Printf("Error publishing reply: %v\n", err)
+				rconn = twistygo.AmqpConnect()
+				if rconn != nil {
+					err = nil
 				}
+			}()
+			if err == nil && rconn != nil {
+				break
+			}
+			if i < maxRetries-1 {
+				slog.Warn("failed to connect to RabbitMQ, retrying in 5s", "attempt", i+1, "max_attempts", maxRetries, "error", err)
+				time.Sleep(5 * time.Second)
+			}
+		}
+		if rconn == nil {
+			panic(fmt.Sprintf("❌ Failed to connect to RabbitMQ after %d attempts: %v", maxRetries, err))
+		}
 
-				// Manually acknowledge the message after processing (since autoack is now false)
-				if err := delivery.Ack(false); err != nil {
-					fmt.Printf("Error acknowledging message: %v\n", err)
-				}
-			}(d)
+		// Load queue and service definitions
+		rconn.AmqpLoadQueues("ConfluenceRequests")
+		rconn.AmqpLoadServices("ConfluenceService")
+
+		// Get service handle
+		svc := rconn.AmqpConnectService("ConfluenceService")
+		if svc == nil {
+			panic("Failed to connect to ConfluenceService queue")
 		}
-	}()
 
-	// Start a simple health check server for Kubernetes
+		// Manual multi-threaded service loop (up to AMQP_WORKERS requests at once) to avoid
+		// the twistygo single-threaded bottleneck.
+		// The consumer reconnects and resumes when the broker restarts, and retries or
+		// dead-letters requests whose processing panics.
+		consumer := broker.NewConsumer("ConfluenceService", []string{"ConfluenceRequests"}, func(ch *amqp.Channel, queue string, delivery amqp.Delivery) {
+			// Panics are recovered by the consumer, which retries and then dead-letters the request
+			metrics.AMQPMessages.Inc(queue, "consumed")
+			responseBytes := service.HandleRequest(delivery)
+
+			// Publish the reply on the channel the request arrived on
+			err := ch.Publish(
+				"",               // exchange
+				delivery.ReplyTo, // routing key (the reply queue)
+				false,            // mandatory
+				false,            // immediate
+				amqp.Publishing{
+					ContentType:   "application/json",
+					CorrelationId: delivery.CorrelationId,
+					Headers:       replyHeaders(delivery),
+					Body:          responseBytes,
+				},
+			)
+			if err != nil {
+				slog.Error("failed to publish reply", "error", err)
+			} else {
+				// Replies go to per-caller temporary queues; count them under the request queue
+				metrics.AMQPMessages.Inc(queue, "published")
+			}
+
+			// Manually acknowledge the message after processing (since autoack is now false)
+			if err := delivery.Ack(false); err != nil {
+				slog.Error("failed to acknowledge message", "error", err)
+			}
+		})
+
+		// Drop cached credentials when workspaces are updated or deleted via the HTTP API;
+		// the subscription is renewed on every reconnect
+		consumer.OnConnect(func(ch *amqp.Channel) {
+			err := events.SubscribeCredentialEvents(ch, func(event events.CredentialEvent) {
+				cachedStore.Invalidate(event.UserID, event.WorkspaceID)
+			})
+			if err != nil {
+				slog.Warn("credential event subscription failed, relying on cache TTL", "error", err)
+			}
+		})
+		go consumer.Run(svc)
+		brokerStatus = consumer.Status
+	}
+
+	// Start a simple health check server for Kubernetes, which also serves Prometheus metrics
 	healthMux := http.NewServeMux()
+	healthMux.Handle("/metrics", metrics.Handler())
 	healthMux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		if err := credStore.Ping(); err != nil {
 			http.Error(w, "Database down", http.StatusServiceUnavailable)
 			return
 		}
+		if connected, err := brokerStatus(); !connected {
+			http.Error(w, fmt.Sprintf("Broker down: %v", err), http.StatusServiceUnavailable)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprint(w, "OK")
 	})
 
+	// Readiness additionally requires Atlassian Cloud to be reachable, and turns
+	// false on shutdown so Kubernetes stops routing before the process exits
+	readiness := health.NewReadiness(ServiceVersion)
+	readiness.SetStorage(storage.Backend(credStore))
+	readiness.Add("database", credStore.Ping)
+	readiness.Add("broker", func() error {
+		if connected, err := brokerStatus(); !connected {
+			return err
+		}
+		return nil
+	})
+	readiness.Add("atlassian", health.AtlassianProbeFromEnv())
+	healthMux.Handle("/ready", readiness.Handler())
+	healthMux.Handle("/live", health.LiveHandler(ServiceVersion))
+
 	healthSrv := &http.Server{
 		Addr:    ":8080",
 		Handler: healthMux,
 	}
 
 	go func() {
-		fmt.Println("🏥 Health check server running on :8080")
+		slog.Info("health check server running", "addr", ":8080")
 		if err := healthSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Printf("Health check server error: %v\n", err)
+			slog.Error("health check server error", "error", err)
 		}
 	}()
 
-	fmt.Printf("Confluence Service v%s is running (Multi-threaded). To exit press CTRL+C\n", ServiceVersion)
+	slog.Info("Confluence service running", "version", ServiceVersion)
+	readiness.MarkReady()
 
 	// Wait for termination signal
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	<-stop
 
-	fmt.Println("🛑 Shutting down Confluence Service...")
+	slog.Info("shutting down Confluence service")
+	readiness.MarkNotReady()
 
 	// Graceful shutdown for health server
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	healthSrv.Shutdown(ctx)
 }
+
+// replyHeaders echoes the request's correlation ID on the reply
+func replyHeaders(delivery amqp.Delivery) amqp.Table {
+	if requestID, ok := delivery.Headers[logging.RequestIDHeader].(string); ok && requestID != "" {
+		return amqp.Table{logging.RequestIDHeader: requestID}
+	}
+	return nil
+}
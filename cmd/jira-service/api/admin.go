@@ -0,0 +1,70 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ListUsers lists the site's users, or searches them by name or email when query is set
+func (c *Client) ListUsers(query string, limit int) ([]map[string]interface{}, error) {
+	params := url.Values{"maxResults": {strconv.Itoa(limit)}}
+	path := "/rest/api/3/users/search"
+	if query != "" {
+		params.Set("query", query)
+		path = "/rest/api/3/user/search"
+	}
+
+	var users []map[string]interface{}
+	if err := c.getJSON(c.creds.Site+path+"?"+params.Encode(), &users, "users"); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetUserAccess gets a user with their groups and application roles (product access)
+func (c *Client) GetUserAccess(accountID string) (map[string]interface{}, error) {
+	params := url.Values{"accountId": {accountID}, "expand": {"groups,applicationRoles"}}
+
+	var user map[string]interface{}
+	if err := c.getJSON(c.creds.Site+"/rest/api/3/user?"+params.Encode(), &user, "user"); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ListGroups lists the site's groups, or those whose name contains query
+func (c *Client) ListGroups(query string, limit int) ([]map[string]interface{}, error) {
+	params := url.Values{"maxResults": {strconv.Itoa(limit)}}
+	if query == "" {
+		var page struct {
+			Values []map[string]interface{} `json:"values"`
+		}
+		if err := c.getJSON(c.creds.Site+"/rest/api/3/group/bulk?"+params.Encode(), &page, "groups"); err != nil {
+			return nil, err
+		}
+		return page.Values, nil
+	}
+
+	params.Set("query", query)
+	var found struct {
+		Groups []map[string]interface{} `json:"groups"`
+	}
+	if err := c.getJSON(c.creds.Site+"/rest/api/3/groups/picker?"+params.Encode(), &found, "groups"); err != nil {
+		return nil, err
+	}
+	return found.Groups, nil
+}
+
+// GetGroupMembers lists the active members of a group
+func (c *Client) GetGroupMembers(groupID string, limit int) ([]map[string]interface{}, error) {
+	params := url.Values{"groupId": {groupID}, "maxResults": {strconv.Itoa(limit)}}
+
+	var page struct {
+		Values []map[string]interface{} `json:"values"`
+	}
+	if err := c.getJSON(c.creds.Site+"/rest/api/3/group/member?"+params.Encode(), &page, fmt.Sprintf("members of group %s", groupID)); err != nil {
+		return nil, err
+	}
+	return page.Values, nil
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// changelogPageSize is the most changelog entries Jira returns per request
+const changelogPageSize = 100
+
+// GetSprint gets a sprint with its dates
+func (c *Client) GetSprint(sprintID string) (*models.Sprint, error) {
+	var sprint models.Sprint
+	endpoint := fmt.Sprintf("%s/rest/agile/1.0/sprint/%s", c.creds.Site, url.PathEscape(sprintID))
+	if err := c.getJSON(endpoint, &sprint, fmt.Sprintf("sprint %s", sprintID)); err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+// GetBoard gets a board's name and type
+func (c *Client) GetBoard(boardID string) (*models.Board, error) {
+	var board models.Board
+	endpoint := fmt.Sprintf("%s/rest/agile/1.0/board/%s", c.creds.Site, url.PathEscape(boardID))
+	if err := c.getJSON(endpoint, &board, fmt.Sprintf("board %s", boardID)); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// GetBoardConfiguration gets a board's columns and estimation statistic
+func (c *Client) GetBoardConfiguration(boardID string) (*models.BoardConfiguration, error) {
+	var config models.BoardConfiguration
+	endpoint := fmt.Sprintf("%s/rest/agile/1.0/board/%s/configuration", c.creds.Site, url.PathEscape(boardID))
+	if err := c.getJSON(endpoint, &config, fmt.Sprintf("configuration of board %s", boardID)); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SearchBoardIssues gets up to limit of the issues on a board, from the start'th, with the
+// given fields. jql narrows the board's own filter and may be empty. It also returns how
+// many issues there are in all.
+func (c *Client) SearchBoardIssues(boardID, jql string, fields []string, start, limit int) ([]models.JiraIssue, int, error) {
+	params := url.Values{
+		"startAt":    {strconv.Itoa(start)},
+		"maxResults": {strconv.Itoa(limit)},
+		"fields":     {strings.Join(fields, ",")},
+	}
+	if jql != "" {
+		params.Set("jql", jql)
+	}
+	var page struct {
+		Total  int                `json:"total"`
+		Issues []models.JiraIssue `json:"issues"`
+	}
+	endpoint := fmt.Sprintf("%s/rest/agile/1.0/board/%s/issue?%s", c.creds.Site, url.PathEscape(boardID), params.Encode())
+	if err := c.getJSON(endpoint, &page, fmt.Sprintf("issues of board %s", boardID)); err != nil {
+		return nil, 0, err
+	}
+	return page.Issues, page.Total, nil
+}
+
+// GetIssueChangelog gets the whole change history of an issue, oldest first
+func (c *Client) GetIssueChangelog(issueKey string) ([]models.ChangelogEntry, error) {
+	var entries []models.ChangelogEntry
+	for {
+		params := url.Values{
+			"startAt":    {strconv.Itoa(len(entries))},
+			"maxResults": {strconv.Itoa(changelogPageSize)},
+		}
+		var page struct {
+			Values []models.ChangelogEntry `json:"values"`
+			IsLast bool                    `json:"isLast"`
+		}
+		endpoint := fmt.Sprintf("%s/rest/api/3/issue/%s/changelog?%s", c.creds.Site, url.PathEscape(issueKey), params.Encode())
+		if err := c.getJSON(endpoint, &page, fmt.Sprintf("changelog of issue %s", issueKey)); err != nil {
+			return nil, err
+		}
+		entries = append(entries, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return entries, nil
+		}
+	}
+}
+
+// GetIssueComments gets up to limit of an issue's comments, newest first
+func (c *Client) GetIssueComments(issueKey string, limit int) ([]models.IssueComment, error) {
+	params := url.Values{
+		"orderBy":    {"-created"},
+		"maxResults": {strconv.Itoa(limit)},
+	}
+	var page struct {
+		Comments []models.IssueComment `json:"comments"`
+	}
+	endpoint := fmt.Sprintf("%s/rest/api/3/issue/%s/comment?%s", c.creds.Site, url.PathEscape(issueKey), params.Encode())
+	if err := c.getJSON(endpoint, &page, fmt.Sprintf("comments of issue %s", issueKey)); err != nil {
+		return nil, err
+	}
+	return page.Comments, nil
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// BitbucketAPIURL is the Bitbucket Cloud REST API. It accepts the same Atlassian
+// account email and API token as Jira, provided the token has Bitbucket scopes.
+const BitbucketAPIURL = "https://api.bitbucket.org/2.0"
+
+// bitbucketGet fetches a Bitbucket resource and decodes it into v
+func (c *Client) bitbucketGet(path string, query url.Values, v interface{}, what string) error {
+	endpoint := BitbucketAPIURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return c.getJSON(endpoint, v, what)
+}
+
+// bitbucketList fetches the first page of a paginated Bitbucket collection
+func (c *Client) bitbucketList(path string, query url.Values, limit int, what string) ([]map[string]interface{}, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("pagelen", strconv.Itoa(limit))
+
+	var page struct {
+		Values []map[string]interface{} `json:"values"`
+	}
+	if err := c.bitbucketGet(path, query, &page, what); err != nil {
+		return nil, err
+	}
+	return page.Values, nil
+}
+
+// ListRepositories lists the repositories of a Bitbucket workspace, most recently updated first
+func (c *Client) ListRepositories(workspace, nameFilter string, limit int) ([]map[string]interface{}, error) {
+	query := url.Values{"sort": {"-updated_on"}}
+	if nameFilter != "" {
+		query.Set("q", fmt.Sprintf("name ~ %s", strconv.Quote(nameFilter)))
+	}
+	return c.bitbucketList(fmt.Sprintf("/repositories/%s", url.PathEscape(workspace)), query, limit, "repositories")
+}
+
+// ListPullRequests lists a repository's pull requests in the given state (OPEN, MERGED, DECLINED or SUPERSEDED)
+func (c *Client) ListPullRequests(workspace, repoSlug, state string, limit int) ([]map[string]interface{}, error) {
+	query := url.Values{}
+	if state != "" {
+		query.Set("state", state)
+	}
+	return c.bitbucketList(fmt.Sprintf("/repositories/%s/%s/pullrequests",
+		url.PathEscape(workspace), url.PathEscape(repoSlug)), query, limit, "pull requests")
+}
+
+// GetPullRequest gets a single pull request
+func (c *Client) GetPullRequest(workspace, repoSlug, pullRequestID string) (map[string]interface{}, error) {
+	var pr map[string]interface{}
+	err := c.bitbucketGet(fmt.Sprintf("/repositories/%s/%s/pullrequests/%s",
+		url.PathEscape(workspace), url.PathEscape(repoSlug), url.PathEscape(pullRequestID)), nil, &pr, "pull request")
+	if err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// GetPullRequestComments lists the comments on a pull request, oldest first
+func (c *Client) GetPullRequestComments(workspace, repoSlug, pullRequestID string, limit int) ([]map[string]interface{}, error) {
+	return c.bitbucketList(fmt.Sprintf("/repositories/%s/%s/pullrequests/%s/comments",
+		url.PathEscape(workspace), url.PathEscape(repoSlug), url.PathEscape(pullRequestID)), nil, limit, "pull request comments")
+}
+
+// ListPipelines lists a repository's pipeline runs, newest first
+func (c *Client) ListPipelines(workspace, repoSlug string, limit int) ([]map[string]interface{}, error) {
+	query := url.Values{"sort": {"-created_on"}}
+	return c.bitbucketList(fmt.Sprintf("/repositories/%s/%s/pipelines",
+		url.PathEscape(workspace), url.PathEscape(repoSlug)), query, limit, "pipelines")
+}
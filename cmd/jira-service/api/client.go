@@ -2,13 +2,18 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 )
 
@@ -17,58 +22,140 @@ type WorkspaceCredentials struct {
 	Site  string // e.g., "https://eso.atlassian.net"
 	Email string // e.g., "service@eso.com"
 	Token string // Atlassian API token
+
+	// AccessToken is the caller's OAuth access token, which replaces Email and Token. Site
+	// is then the site's API gateway URL, e.g. https://api.atlassian.com/ex/jira/{cloudId}.
+	AccessToken string
+
+	OpsgenieKey string // Optional Opsgenie API key for the alert methods
+	ProxyURL    string // Proxy for this workspace's calls; empty follows HTTPS_PROXY
+	WorkspaceID string // Workspace whose rate limit budget the calls share; empty is untracked
 }
 
 // Client wraps HTTP client with Atlassian auth
 type Client struct {
 	creds      WorkspaceCredentials
 	httpClient *http.Client
+	usage      *atlassian.CountingTransport // Bytes exchanged with Atlassian, for usage metering
+	ctx        context.Context              // Cancels in-flight calls once the caller's deadline passes
+	dryRun     *atlassian.DryRunTransport   // Set by WithDryRun: changes are recorded, not sent
 }
 
-// Shared HTTP client with connection pooling
-var sharedHTTPClient = &http.Client{
-	Timeout: 30 * time.Second,
-	Transport: &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-		DisableKeepAlives:   false,
-	},
-}
+// defaultTimeout applies when NewClient is given no timeout
+const defaultTimeout = 30 * time.Second
 
 // NewClient creates an authenticated Jira client
 func NewClient(creds WorkspaceCredentials, timeout time.Duration) *Client {
-	// Use a dedicated client if a specific timeout is requested, 
-	// otherwise use the shared one.
-	client := sharedHTTPClient
-	if timeout > 0 && timeout != sharedHTTPClient.Timeout {
-		client = &http.Client{
-			Timeout:   timeout,
-			Transport: sharedHTTPClient.Transport,
-		}
+	if timeout <= 0 {
+		timeout = defaultTimeout
 	}
 
+	// Each client counts its own traffic but shares the pooled transport of its proxy, and
+	// the rate limit budget of its workspace
+	usage := atlassian.NewCountingTransport(atlassian.NewRateLimitTransport(creds.WorkspaceID, atlassian.Transport(creds.ProxyURL)), creds.Site)
+
 	return &Client{
-		creds:      creds,
-		httpClient: client,
+		creds: creds,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: usage,
+		},
+		usage: usage,
+	}
+}
+
+// WithContext makes every call made by the client stop when ctx is done
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c.ctx = ctx
+	return c
+}
+
+// WithDryRun makes the client record the changes it would make instead of sending them.
+// Reads still reach Atlassian, so lookups the changes depend on are real.
+func (c *Client) WithDryRun() *Client {
+	c.dryRun = atlassian.NewDryRunTransport(c.usage)
+	c.httpClient.Transport = c.dryRun
+	return c
+}
+
+// DryRunRequests returns the changes a dry-run client recorded
+func (c *Client) DryRunRequests() []atlassian.DryRunRequest {
+	if c.dryRun == nil {
+		return nil
 	}
+	return c.dryRun.Requests()
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// BytesTransferred returns the request and response bytes exchanged with Atlassian by this client
+func (c *Client) BytesTransferred() int64 {
+	return c.usage.Bytes()
+}
+
+// CredentialsRejected reports whether the site answered any of this client's requests with
+// 401 Unauthorized, i.e. the workspace's API token is invalid, expired or revoked. A
+// caller's own OAuth token being rejected says nothing about the workspace's.
+func (c *Client) CredentialsRejected() bool {
+	return c.creds.AccessToken == "" && c.usage.Unauthorized()
 }
 
-// authHeader returns the Basic auth header value
+// FailedStatus returns the HTTP status of the last request Atlassian rejected or failed,
+// or 0 when all succeeded
+func (c *Client) FailedStatus() int {
+	return c.usage.FailedStatus()
+}
+
+// authHeader returns the Basic auth header value, or the Bearer one for an OAuth token
 func (c *Client) authHeader() string {
+	if c.creds.AccessToken != "" {
+		return "Bearer " + c.creds.AccessToken
+	}
 	credentials := fmt.Sprintf("%s:%s", c.creds.Email, c.creds.Token)
 	encoded := base64.StdEncoding.EncodeToString([]byte(credentials))
 	return "Basic " + encoded
 }
 
-// SearchIssues searches for issues using JQL
-func (c *Client) SearchIssues(jql string, fields []string, limit int) (*models.SearchResponse, error) {
+// getJSON fetches an endpoint with the workspace credentials and decodes the response into v
+func (c *Client) getJSON(endpoint string, v interface{}, what string) error {
+	req, err := http.NewRequestWithContext(c.context(), "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to get %s: %s", what, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// SearchIssues searches for issues using JQL. pageToken is the previous page's
+// nextPageToken, or "" for the first page.
+func (c *Client) SearchIssues(jql string, fields []string, limit int, pageToken string) (*models.SearchResponse, error) {
 	url := fmt.Sprintf("%s/rest/api/3/search/jql", c.creds.Site)
 
 	payload := map[string]interface{}{
 		"jql":        jql,
 		"maxResults": limit,
 	}
+	if pageToken != "" {
+		payload["nextPageToken"] = pageToken
+	}
 
 	if len(fields) > 0 {
 		payload["fields"] = fields
@@ -82,7 +169,7 @@ func (c *Client) SearchIssues(jql string, fields []string, limit int) (*models.S
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +211,7 @@ func (c *Client) GetIssue(issueKey string, expand []string) (*models.JiraIssue,
 		url += "?expand=" + expandStr
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -179,7 +266,7 @@ func (c *Client) CreateIssue(projectKey, issueType, summary, description string,
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +293,91 @@ func (c *Client) CreateIssue(projectKey, issueType, summary, description string,
 	return &issue, nil
 }
 
+// BulkCreateResult is what became of one issue of a bulk create: the issue Jira created,
+// or why it refused to
+type BulkCreateResult struct {
+	Issue *models.JiraIssue
+	Error string
+}
+
+// BulkCreateIssues creates up to 50 issues, each given as the fields to create it with, in
+// one request. Jira creates those it can; the results are in the order of issues.
+func (c *Client) BulkCreateIssues(issues []map[string]interface{}) ([]BulkCreateResult, error) {
+	url := fmt.Sprintf("%s/rest/api/3/issue/bulk", c.creds.Site)
+
+	updates := make([]models.CreateIssueRequest, len(issues))
+	for i, fields := range issues {
+		updates[i] = models.CreateIssueRequest{Fields: fields}
+	}
+	jsonPayload, err := json.Marshal(map[string]interface{}{"issueUpdates": updates})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// A bulk create that fails for every issue answers 400 with the same report
+	var report struct {
+		Issues []models.JiraIssue `json:"issues"`
+		Errors []struct {
+			FailedElementNumber int `json:"failedElementNumber"`
+			ElementErrors       struct {
+				ErrorMessages []string          `json:"errorMessages"`
+				Errors        map[string]string `json:"errors"`
+			} `json:"elementErrors"`
+		} `json:"errors"`
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		if json.Unmarshal(body, &report) != nil || len(report.Errors) == 0 {
+			return nil, fmt.Errorf("failed to create issues: %s", string(body))
+		}
+	} else if err := json.Unmarshal(body, &report); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkCreateResult, len(issues))
+	for _, failure := range report.Errors {
+		if failure.FailedElementNumber < 0 || failure.FailedElementNumber >= len(issues) {
+			continue
+		}
+		messages := failure.ElementErrors.ErrorMessages
+		for field, message := range failure.ElementErrors.Errors {
+			messages = append(messages, fmt.Sprintf("%s: %s", field, message))
+		}
+		sort.Strings(messages)
+		results[failure.FailedElementNumber].Error = strings.Join(messages, "; ")
+		if results[failure.FailedElementNumber].Error == "" {
+			results[failure.FailedElementNumber].Error = "Jira did not create the issue"
+		}
+	}
+	// Created issues are listed in order, skipping the failed ones
+	created := report.Issues
+	for i := range results {
+		if results[i].Error != "" || len(created) == 0 {
+			continue
+		}
+		issue := created[0]
+		results[i].Issue, created = &issue, created[1:]
+	}
+	return results, nil
+}
+
 // UpdateIssue updates an existing issue
 func (c *Client) UpdateIssue(issueKey string, fields map[string]interface{}) error {
 	url := fmt.Sprintf("%s/rest/api/3/issue/%s", c.creds.Site, issueKey)
@@ -219,7 +391,7 @@ func (c *Client) UpdateIssue(issueKey string, fields map[string]interface{}) err
 		return err
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return err
 	}
@@ -254,7 +426,7 @@ func (c *Client) AddComment(issueKey, body string) (*models.Comment, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -283,6 +455,12 @@ func (c *Client) AddComment(issueKey, body string) (*models.Comment, error) {
 
 // TransitionIssue transitions an issue to a different status
 func (c *Client) TransitionIssue(issueKey, transitionID string) error {
+	return c.TransitionIssueWithComment(issueKey, transitionID, nil)
+}
+
+// TransitionIssueWithComment transitions an issue and, unless comment is empty, adds the
+// comment's Atlassian Document Format nodes as a comment in the same request
+func (c *Client) TransitionIssueWithComment(issueKey, transitionID string, comment []interface{}) error {
 	url := fmt.Sprintf("%s/rest/api/3/issue/%s/transitions", c.creds.Site, issueKey)
 
 	payload := map[string]interface{}{
@@ -290,13 +468,24 @@ func (c *Client) TransitionIssue(issueKey, transitionID string) error {
 			"id": transitionID,
 		},
 	}
+	if len(comment) > 0 {
+		payload["update"] = map[string]interface{}{
+			"comment": []interface{}{
+				map[string]interface{}{
+					"add": map[string]interface{}{
+						"body": map[string]interface{}{"type": "doc", "version": 1, "content": comment},
+					},
+				},
+			},
+		}
+	}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return err
 	}
@@ -322,7 +511,7 @@ func (c *Client) TransitionIssue(issueKey, transitionID string) error {
 func (c *Client) ListProjects() ([]models.ProjectRef, error) {
 	url := fmt.Sprintf("%s/rest/api/3/project", c.creds.Site)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -366,7 +555,7 @@ func (c *Client) GetAgileBoards(projectKey, boardType string) ([]map[string]inte
 	}
 	url += params
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -399,7 +588,7 @@ func (c *Client) GetBoardIssues(boardID string, limit int) ([]map[string]interfa
 	url := fmt.Sprintf("%s/rest/agile/1.0/board/%s/issue?maxResults=%d", 
 		c.creds.Site, boardID, limit)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -435,7 +624,7 @@ func (c *Client) GetSprintsFromBoard(boardID, state string) ([]map[string]interf
 		url += "?state=" + state
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -468,7 +657,7 @@ func (c *Client) GetSprintIssues(sprintID string, limit int) ([]map[string]inter
 	url := fmt.Sprintf("%s/rest/agile/1.0/sprint/%s/issue?maxResults=%d", 
 		c.creds.Site, sprintID, limit)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -517,7 +706,7 @@ func (c *Client) CreateSprint(boardID, name, startDate, endDate string) (map[str
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -568,7 +757,7 @@ func (c *Client) UpdateSprint(sprintID, name, state, startDate, endDate string)
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -599,7 +788,7 @@ func (c *Client) UpdateSprint(sprintID, name, state, startDate, endDate string)
 func (c *Client) GetWorklog(issueKey string) ([]map[string]interface{}, error) {
 	url := fmt.Sprintf("%s/rest/api/2/issue/%s/worklog", c.creds.Site, issueKey)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -647,7 +836,7 @@ func (c *Client) AddWorklog(issueKey, timeSpent, comment, started string) (map[s
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -678,7 +867,7 @@ func (c *Client) AddWorklog(issueKey, timeSpent, comment, started string) (map[s
 func (c *Client) GetTransitions(issueKey string) ([]map[string]interface{}, error) {
 	url := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", c.creds.Site, issueKey)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -710,7 +899,7 @@ func (c *Client) GetTransitions(issueKey string) ([]map[string]interface{}, erro
 func (c *Client) DeleteIssue(issueKey string) error {
 	url := fmt.Sprintf("%s/rest/api/2/issue/%s", c.creds.Site, issueKey)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -733,14 +922,14 @@ func (c *Client) DeleteIssue(issueKey string) error {
 // GetProjectIssues gets all issues in a project
 func (c *Client) GetProjectIssues(projectKey string, limit int) (*models.SearchResponse, error) {
 	jql := fmt.Sprintf("project=%s ORDER BY created DESC", projectKey)
-	return c.SearchIssues(jql, nil, limit)
+	return c.SearchIssues(jql, nil, limit, "")
 }
 
 // GetProjectVersions lists versions for a project
 func (c *Client) GetProjectVersions(projectKey string) ([]map[string]interface{}, error) {
 	url := fmt.Sprintf("%s/rest/api/2/project/%s/versions", c.creds.Site, projectKey)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -768,9 +957,9 @@ func (c *Client) GetProjectVersions(projectKey string) ([]map[string]interface{}
 
 // SearchUsers searches for Jira users
 func (c *Client) SearchUsers(query string) ([]models.User, error) {
-	url := fmt.Sprintf("%s/rest/api/3/user/search?query=%s", c.creds.Site, query)
+	endpoint := fmt.Sprintf("%s/rest/api/3/user/search?query=%s", c.creds.Site, url.QueryEscape(query))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -800,7 +989,7 @@ func (c *Client) SearchUsers(query string) ([]models.User, error) {
 func (c *Client) GetUserProfile(accountID string) (*models.User, error) {
 	url := fmt.Sprintf("%s/rest/api/3/user?accountId=%s", c.creds.Site, accountID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -830,7 +1019,7 @@ func (c *Client) GetUserProfile(accountID string) (*models.User, error) {
 func (c *Client) SearchFields() ([]map[string]interface{}, error) {
 	url := fmt.Sprintf("%s/rest/api/3/field", c.creds.Site)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -877,7 +1066,7 @@ func (c *Client) CreateIssueLink(type_name, inward_key, outward_key string) erro
 		return err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonPayload))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewReader(jsonPayload))
 	if err != nil {
 		return err
 	}
@@ -903,7 +1092,7 @@ func (c *Client) CreateIssueLink(type_name, inward_key, outward_key string) erro
 func (c *Client) RemoveIssueLink(linkID string) error {
 	url := fmt.Sprintf("%s/rest/api/3/issueLink/%s", c.creds.Site, linkID)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
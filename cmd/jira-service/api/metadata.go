@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// GetProjectIssueTypes gets the issue types that can be created in a project
+func (c *Client) GetProjectIssueTypes(projectKey string) ([]models.IssueType, error) {
+	var result struct {
+		IssueTypes []models.IssueType `json:"issueTypes"`
+		Values     []models.IssueType `json:"values"` // Older sites page the types as values
+	}
+	endpoint := fmt.Sprintf("%s/rest/api/3/issue/createmeta/%s/issuetypes", c.creds.Site, url.PathEscape(projectKey))
+	if err := c.getJSON(endpoint, &result, fmt.Sprintf("issue types of project %s", projectKey)); err != nil {
+		return nil, err
+	}
+	return append(result.IssueTypes, result.Values...), nil
+}
+
+// GetPriorities gets the site's issue priorities
+func (c *Client) GetPriorities() ([]models.Priority, error) {
+	var priorities []models.Priority
+	if err := c.getJSON(c.creds.Site+"/rest/api/3/priority", &priorities, "priorities"); err != nil {
+		return nil, err
+	}
+	return priorities, nil
+}
+
+// GetProjectComponents gets a project's components
+func (c *Client) GetProjectComponents(projectKey string) ([]models.Component, error) {
+	var components []models.Component
+	endpoint := fmt.Sprintf("%s/rest/api/3/project/%s/components", c.creds.Site, url.PathEscape(projectKey))
+	if err := c.getJSON(endpoint, &components, fmt.Sprintf("components of project %s", projectKey)); err != nil {
+		return nil, err
+	}
+	return components, nil
+}
+
+// CreateComponent creates a component in a project
+func (c *Client) CreateComponent(projectKey string, component models.Component) (*models.Component, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"project":     projectKey,
+		"name":        component.Name,
+		"description": component.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(c.context(), "POST", c.creds.Site+"/rest/api/3/component", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create component: %s", string(body))
+	}
+
+	var created models.Component
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetAssignableUsers gets up to limit active people who can be assigned issues in a
+// project. App and customer accounts are left out.
+func (c *Client) GetAssignableUsers(projectKey string, limit int) ([]models.User, error) {
+	var accounts []struct {
+		models.User
+		AccountType string `json:"accountType"`
+		Active      bool   `json:"active"`
+	}
+	endpoint := fmt.Sprintf("%s/rest/api/3/user/assignable/search?project=%s&maxResults=%d",
+		c.creds.Site, url.QueryEscape(projectKey), limit)
+	if err := c.getJSON(endpoint, &accounts, fmt.Sprintf("assignable users of project %s", projectKey)); err != nil {
+		return nil, err
+	}
+	users := make([]models.User, 0, len(accounts))
+	for _, account := range accounts {
+		if account.Active && account.AccountType == "atlassian" {
+			users = append(users, account.User)
+		}
+	}
+	return users, nil
+}
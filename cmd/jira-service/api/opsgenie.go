@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Alerts are read from Opsgenie when the workspace has an Opsgenie API key, and from
+// Jira Service Management Operations (which replaces Opsgenie on Atlassian cloud) with
+// the workspace's Atlassian credentials otherwise. Both expose the same alert API.
+const (
+	defaultOpsgenieAPIURL = "https://api.opsgenie.com"
+	jsmOpsAPIURL          = "https://api.atlassian.com/jsm/ops/api"
+)
+
+// opsgenieAPIURL returns the Opsgenie API; set OPSGENIE_API_URL to https://api.eu.opsgenie.com for EU accounts
+func opsgenieAPIURL() string {
+	if u := os.Getenv("OPSGENIE_API_URL"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return defaultOpsgenieAPIURL
+}
+
+// cloudIDs caches the Atlassian cloud ID of each site, which never changes
+var cloudIDs sync.Map
+
+// cloudID looks up the cloud ID that JSM Operations URLs are keyed by
+func (c *Client) cloudID() (string, error) {
+	// The API gateway URL of OAuth clients ends with it
+	if c.creds.AccessToken != "" {
+		return path.Base(c.creds.Site), nil
+	}
+	if id, ok := cloudIDs.Load(c.creds.Site); ok {
+		return id.(string), nil
+	}
+
+	req, err := http.NewRequestWithContext(c.context(), "GET", c.creds.Site+"/_edge/tenant_info", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get cloud ID: %s", string(body))
+	}
+
+	var tenant struct {
+		CloudID string `json:"cloudId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tenant); err != nil {
+		return "", err
+	}
+	if tenant.CloudID == "" {
+		return "", fmt.Errorf("failed to get cloud ID: %s did not return one", c.creds.Site)
+	}
+	cloudIDs.Store(c.creds.Site, tenant.CloudID)
+	return tenant.CloudID, nil
+}
+
+// UsesOpsgenie reports whether alerts go to Opsgenie rather than JSM Operations
+func (c *Client) UsesOpsgenie() bool {
+	return c.creds.OpsgenieKey != ""
+}
+
+// alertsRequest calls the alert API at path (e.g. "/alerts") and decodes the response into v
+func (c *Client) alertsRequest(method, path string, query url.Values, payload interface{}, v interface{}, what string) error {
+	var endpoint, auth string
+	if c.UsesOpsgenie() {
+		endpoint = opsgenieAPIURL() + "/v2" + path
+		auth = "GenieKey " + c.creds.OpsgenieKey
+	} else {
+		cloudID, err := c.cloudID()
+		if err != nil {
+			return err
+		}
+		endpoint = fmt.Sprintf("%s/%s/v1%s", jsmOpsAPIURL, cloudID, path)
+		auth = c.authHeader()
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		jsonPayload, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(jsonPayload)
+	}
+
+	req, err := http.NewRequestWithContext(c.context(), method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Changes are accepted with 202 and processed asynchronously
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to %s: %s", what, string(respBody))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// ListAlerts searches alerts with an Opsgenie query such as "status:open", newest first
+func (c *Client) ListAlerts(query string, limit int) ([]map[string]interface{}, error) {
+	params := url.Values{"query": {query}, "sort": {"createdAt"}, "order": {"desc"}}
+	if c.UsesOpsgenie() {
+		params.Set("limit", strconv.Itoa(limit))
+	} else {
+		params.Set("size", strconv.Itoa(limit))
+	}
+
+	var result struct {
+		Data   []map[string]interface{} `json:"data"`   // Opsgenie
+		Values []map[string]interface{} `json:"values"` // JSM Operations
+	}
+	if err := c.alertsRequest("GET", "/alerts", params, nil, &result, "list alerts"); err != nil {
+		return nil, err
+	}
+	if result.Data != nil {
+		return result.Data, nil
+	}
+	if result.Values == nil {
+		return []map[string]interface{}{}, nil
+	}
+	return result.Values, nil
+}
+
+// alertAction acknowledges or closes an alert by its ID
+func (c *Client) alertAction(alertID, action, note string) (map[string]interface{}, error) {
+	var query url.Values
+	if c.UsesOpsgenie() {
+		query = url.Values{"identifierType": {"id"}}
+	}
+	payload := map[string]interface{}{"source": "Trilix"}
+	if note != "" {
+		payload["note"] = note
+	}
+
+	var result map[string]interface{}
+	err := c.alertsRequest("POST", fmt.Sprintf("/alerts/%s/%s", url.PathEscape(alertID), action), query, payload, &result, action+" alert")
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AcknowledgeAlert acknowledges an alert
+func (c *Client) AcknowledgeAlert(alertID, note string) (map[string]interface{}, error) {
+	return c.alertAction(alertID, "acknowledge", note)
+}
+
+// CloseAlert closes an alert
+func (c *Client) CloseAlert(alertID, note string) (map[string]interface{}, error) {
+	return c.alertAction(alertID, "close", note)
+}
+
+// CreateAlert creates an alert. alert holds the API's fields: message, alias,
+// description, priority, tags, details, entity and source.
+func (c *Client) CreateAlert(alert map[string]interface{}) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := c.alertsRequest("POST", "/alerts", nil, alert, &result, "create alert"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
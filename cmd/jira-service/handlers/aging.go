@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	defaultAgingLimit = 200
+	// maxAgingIssues bounds how many issues are analyzed, since each needs its changelog
+	maxAgingIssues = 1000
+	// agingPageSize is how many issues each search returns
+	agingPageSize = 100
+)
+
+// agingFields are the issue fields time in status is computed from
+var agingFields = []string{"summary", "status", "created"}
+
+// agingPercentiles are reported for every status
+var agingPercentiles = []struct {
+	name string
+	p    float64
+}{{"p50_days", 50}, {"p75_days", 75}, {"p90_days", 90}, {"p95_days", 95}}
+
+// statusSpan is a stretch of time an issue spent in one status
+type statusSpan struct {
+	status   string
+	from, to time.Time
+}
+
+// handleIssueAging measures how long the issues matching a JQL query spent in each status,
+// from their changelogs, and aggregates the times per status with percentiles so that
+// bottlenecks stand out
+func (s *Service) handleIssueAging(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	jql, _ := req.Params["jql"].(string)
+	if strings.TrimSpace(jql) == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing jql", req.RequestID)
+	}
+	limit := defaultAgingLimit
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = min(int(l), maxAgingIssues)
+	}
+	includeIssues := true
+	if i, ok := req.Params["include_issues"].(bool); ok {
+		includeIssues = i
+	}
+	// Only these statuses are reported, compared case-insensitively
+	var only map[string]bool
+	if statuses, ok := req.Params["statuses"].([]interface{}); ok && len(statuses) > 0 {
+		only = make(map[string]bool)
+		for _, status := range statuses {
+			if name, ok := status.(string); ok {
+				only[strings.ToLower(name)] = true
+			}
+		}
+	}
+
+	issues, truncated, err := searchAgingIssues(client, jql, limit)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	changelogs, err := fetchChangelogs(client, issues)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	now := time.Now().UTC()
+	durations := make(map[string][]float64) // Days per issue that was in the status
+	current := make(map[string]int)
+	issueResults := []map[string]interface{}{}
+	for _, issue := range issues {
+		spans, transitions := statusSpans(issue, changelogs[issue.Key], now)
+		inStatus := make(map[string]float64)
+		var order []string
+		for _, span := range spans {
+			if only != nil && !only[strings.ToLower(span.status)] {
+				continue
+			}
+			if _, seen := inStatus[span.status]; !seen {
+				order = append(order, span.status)
+			}
+			inStatus[span.status] += span.to.Sub(span.from).Hours() / 24
+		}
+		for _, status := range order {
+			durations[status] = append(durations[status], inStatus[status])
+		}
+
+		statusName := ""
+		if status, ok := issue.Fields["status"].(map[string]interface{}); ok {
+			statusName, _ = status["name"].(string)
+		}
+		if only == nil || only[strings.ToLower(statusName)] {
+			current[statusName]++
+		}
+		if !includeIssues {
+			continue
+		}
+
+		timeInStatus := make(map[string]float64, len(inStatus))
+		for status, days := range inStatus {
+			timeInStatus[status] = roundDays(days)
+		}
+		result := map[string]interface{}{
+			"key":            issue.Key,
+			"summary":        issue.Fields["summary"],
+			"status":         statusName,
+			"time_in_status": timeInStatus,
+			"transitions":    transitions,
+		}
+		if len(spans) > 0 {
+			last := spans[len(spans)-1]
+			result["days_in_current_status"] = roundDays(last.to.Sub(last.from).Hours() / 24)
+		}
+		issueResults = append(issueResults, result)
+	}
+
+	statuses := []map[string]interface{}{}
+	for status, days := range durations {
+		sort.Float64s(days)
+		total := 0.0
+		for _, d := range days {
+			total += d
+		}
+		stats := map[string]interface{}{
+			"status":     status,
+			"issues":     len(days),
+			"current":    current[status],
+			"total_days": roundDays(total),
+			"mean_days":  roundDays(total / float64(len(days))),
+			"max_days":   roundDays(days[len(days)-1]),
+		}
+		for _, p := range agingPercentiles {
+			stats[p.name] = roundDays(percentile(days, p.p))
+		}
+		statuses = append(statuses, stats)
+	}
+	// The statuses where the issues spent the most time together come first
+	sort.Slice(statuses, func(i, j int) bool {
+		ti, tj := statuses[i]["total_days"].(float64), statuses[j]["total_days"].(float64)
+		if ti != tj {
+			return ti > tj
+		}
+		return statuses[i]["status"].(string) < statuses[j]["status"].(string)
+	})
+
+	response := map[string]interface{}{
+		"jql":       jql,
+		"analyzed":  len(issues),
+		"truncated": truncated,
+		"statuses":  statuses,
+	}
+	if includeIssues {
+		response["issues"] = issueResults
+	}
+	return models.SuccessResponse(response, req.RequestID)
+}
+
+// searchAgingIssues returns up to limit issues matching the query. truncated is set when
+// more issues match.
+func searchAgingIssues(client *api.Client, jql string, limit int) ([]models.JiraIssue, bool, error) {
+	var issues []models.JiraIssue
+	pageToken := ""
+	for {
+		results, err := client.SearchIssues(jql, agingFields, min(agingPageSize, limit-len(issues)), pageToken)
+		if err != nil {
+			return nil, false, err
+		}
+		issues = append(issues, results.Issues...)
+		if len(issues) >= limit {
+			return issues[:limit], len(issues) > limit || results.NextPageToken != "", nil
+		}
+		if results.NextPageToken == "" || len(results.Issues) == 0 {
+			return issues, false, nil
+		}
+		pageToken = results.NextPageToken
+	}
+}
+
+// statusSpans splits an issue's life, from its creation until now, into the stretches it
+// spent in each status, and counts its status changes. Without a creation date, time
+// before the first status change is not counted.
+func statusSpans(issue models.JiraIssue, changelog []models.ChangelogEntry, now time.Time) ([]statusSpan, int) {
+	type statusChange struct {
+		at       time.Time
+		from, to string
+	}
+	var changes []statusChange
+	for _, entry := range changelog {
+		at, err := parseJiraTime(entry.Created)
+		if err != nil {
+			continue
+		}
+		for _, item := range entry.Items {
+			if item.Field == "status" {
+				changes = append(changes, statusChange{at: at, from: item.FromString, to: item.ToString})
+			}
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].at.Before(changes[j].at) })
+
+	status := ""
+	if s, ok := issue.Fields["status"].(map[string]interface{}); ok {
+		status, _ = s["name"].(string)
+	}
+	if len(changes) > 0 {
+		status = changes[0].from
+	}
+	var since time.Time
+	if created, ok := issue.Fields["created"].(string); ok {
+		since, _ = parseJiraTime(created)
+	}
+
+	var spans []statusSpan
+	for _, change := range changes {
+		if !since.IsZero() && change.at.After(since) {
+			spans = append(spans, statusSpan{status: status, from: since, to: change.at})
+		}
+		status, since = change.to, change.at
+	}
+	if !since.IsZero() && now.After(since) {
+		spans = append(spans, statusSpan{status: status, from: since, to: now})
+	}
+	return spans, len(changes)
+}
+
+func roundDays(days float64) float64 {
+	return math.Round(days*100) / 100
+}
+
+// percentile interpolates the p-th percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
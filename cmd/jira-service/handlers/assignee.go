@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	defaultAssigneeSuggestions = 10
+	maxAssigneeSuggestions     = 50
+	// maxAssignableUsers bounds how many of a project's assignable users are ranked
+	maxAssignableUsers = 200
+	// maxLoadIssues bounds how many open issues are read to count each person's load
+	maxLoadIssues = 2000
+	// loadPageSize is how many issues each search returns
+	loadPageSize = 100
+)
+
+// assigneeLoad is a person who can be assigned issues and how many open ones they have
+type assigneeLoad struct {
+	AccountID   string `json:"account_id"`
+	DisplayName string `json:"display_name"`
+	OpenIssues  int    `json:"open_issues"`
+}
+
+// handleSuggestAssignee ranks the people who can be assigned issues in a project by how
+// many open issues they have there, fewest first, for triage. With issue_type, only open
+// issues of that type count. Jira cannot count issues per assignee, so the open issues
+// are read and counted here.
+func (s *Service) handleSuggestAssignee(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	projectKey, _ := req.Params["project_key"].(string)
+	if projectKey == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing project_key", req.RequestID)
+	}
+	issueType, _ := req.Params["issue_type"].(string)
+	issueType = strings.TrimSpace(issueType)
+	limit := defaultAssigneeSuggestions
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = min(int(l), maxAssigneeSuggestions)
+	}
+
+	users, err := client.GetAssignableUsers(projectKey, maxAssignableUsers)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	if len(users) == 0 {
+		return models.ErrorResponse(models.ErrCodeNotFound,
+			fmt.Sprintf("no one can be assigned issues in project %s", projectKey), req.RequestID)
+	}
+
+	jql := fmt.Sprintf(`project = "%s" AND statusCategory != Done AND assignee IS NOT EMPTY`, strings.ReplaceAll(projectKey, `"`, ""))
+	if issueType != "" {
+		jql += fmt.Sprintf(` AND issuetype = "%s"`, strings.ReplaceAll(issueType, `"`, ""))
+	}
+	counts := make(map[string]int)
+	counted := 0
+	truncated := false
+	pageToken := ""
+	for {
+		results, err := client.SearchIssues(jql, []string{"assignee"}, loadPageSize, pageToken)
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		for _, issue := range results.Issues {
+			if assignee, ok := issue.Fields["assignee"].(map[string]interface{}); ok {
+				if id, _ := assignee["accountId"].(string); id != "" {
+					counts[id]++
+				}
+			}
+		}
+		counted += len(results.Issues)
+		pageToken = results.NextPageToken
+		if pageToken == "" || len(results.Issues) == 0 {
+			break
+		}
+		if counted >= maxLoadIssues {
+			truncated = true
+			break
+		}
+	}
+
+	suggestions := make([]assigneeLoad, 0, len(users))
+	for _, user := range users {
+		suggestions = append(suggestions, assigneeLoad{AccountID: user.AccountID, DisplayName: user.DisplayName, OpenIssues: counts[user.AccountID]})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].OpenIssues != suggestions[j].OpenIssues {
+			return suggestions[i].OpenIssues < suggestions[j].OpenIssues
+		}
+		return suggestions[i].DisplayName < suggestions[j].DisplayName
+	})
+
+	response := map[string]interface{}{
+		"project_key":    projectKey,
+		"suggestions":    suggestions[:min(limit, len(suggestions))],
+		"candidates":     len(suggestions),
+		"issues_counted": counted,
+		"truncated":      truncated,
+	}
+	if issueType != "" {
+		response["issue_type"] = issueType
+	}
+	return models.SuccessResponse(response, req.RequestID)
+}
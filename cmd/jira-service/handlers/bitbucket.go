@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// Bitbucket page sizes are capped by the API (50 for pull requests, 100 elsewhere)
+const (
+	maxBitbucketPageLen            = 100
+	maxBitbucketPullRequestPageLen = 50
+)
+
+// jiraIssueKeyPattern matches Jira issue keys such as OPS-123 in PR titles and branch names
+var jiraIssueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[0-9]+\b`)
+
+// bitbucketWorkspace returns the Bitbucket workspace slug of a request. It defaults to
+// the Atlassian site name, e.g. "acme" for https://acme.atlassian.net.
+func bitbucketWorkspace(req models.JiraRequest, site string) (string, bool) {
+	if workspace, ok := req.Params["bitbucket_workspace"].(string); ok && workspace != "" {
+		return workspace, true
+	}
+	u, err := url.Parse(site)
+	if err != nil || u.Hostname() == "" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(u.Hostname(), ".")
+	return name, name != ""
+}
+
+// bitbucketLimit reads the limit parameter, clamped to the API's page size
+func bitbucketLimit(req models.JiraRequest, max int) int {
+	limit := 25
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	if limit > max {
+		limit = max
+	}
+	return limit
+}
+
+// bitbucketRepo reads the workspace and repo_slug parameters shared by the repository tools
+func bitbucketRepo(req models.JiraRequest, site string) (string, string, map[string]interface{}) {
+	workspace, ok := bitbucketWorkspace(req, site)
+	if !ok {
+		return "", "", models.ErrorResponse(models.ErrCodeInvalidRequest, "missing bitbucket_workspace", req.RequestID)
+	}
+	repoSlug, ok := req.Params["repo_slug"].(string)
+	if !ok || repoSlug == "" {
+		return "", "", models.ErrorResponse(models.ErrCodeInvalidRequest, "missing repo_slug", req.RequestID)
+	}
+	return workspace, repoSlug, nil
+}
+
+// pullRequestID accepts the pull request ID as a number or a string
+func pullRequestID(req models.JiraRequest) (string, bool) {
+	switch id := req.Params["pull_request_id"].(type) {
+	case float64:
+		return fmt.Sprintf("%d", int64(id)), true
+	case string:
+		return id, id != ""
+	default:
+		return "", false
+	}
+}
+
+// addJiraIssueKeys records the Jira issue keys mentioned in a pull request's title,
+// description and source branch, so that agents can correlate PRs with issues
+func addJiraIssueKeys(pr map[string]interface{}) {
+	title, _ := pr["title"].(string)
+	description, _ := pr["description"].(string)
+	var branch string
+	if source, ok := pr["source"].(map[string]interface{}); ok {
+		if b, ok := source["branch"].(map[string]interface{}); ok {
+			branch, _ = b["name"].(string)
+		}
+	}
+
+	keys := []string{}
+	seen := make(map[string]bool)
+	for _, text := range []string{title, branch, description} {
+		for _, key := range jiraIssueKeyPattern.FindAllString(text, -1) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	pr["jira_issue_keys"] = keys
+}
+
+func (s *Service) handleBitbucketListRepositories(client *api.Client, req models.JiraRequest, site string) map[string]interface{} {
+	workspace, ok := bitbucketWorkspace(req, site)
+	if !ok {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing bitbucket_workspace", req.RequestID)
+	}
+	query, _ := req.Params["query"].(string)
+
+	repos, err := client.ListRepositories(workspace, query, bitbucketLimit(req, maxBitbucketPageLen))
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	return models.SuccessResponse(repos, req.RequestID)
+}
+
+func (s *Service) handleBitbucketListPullRequests(client *api.Client, req models.JiraRequest, site string) map[string]interface{} {
+	workspace, repoSlug, errResponse := bitbucketRepo(req, site)
+	if errResponse != nil {
+		return errResponse
+	}
+	state := "OPEN"
+	if st, ok := req.Params["state"].(string); ok && st != "" {
+		state = strings.ToUpper(st)
+	}
+
+	prs, err := client.ListPullRequests(workspace, repoSlug, state, bitbucketLimit(req, maxBitbucketPullRequestPageLen))
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	for _, pr := range prs {
+		addJiraIssueKeys(pr)
+	}
+
+	return models.SuccessResponse(prs, req.RequestID)
+}
+
+func (s *Service) handleBitbucketGetPullRequest(client *api.Client, req models.JiraRequest, site string) map[string]interface{} {
+	workspace, repoSlug, errResponse := bitbucketRepo(req, site)
+	if errResponse != nil {
+		return errResponse
+	}
+	prID, ok := pullRequestID(req)
+	if !ok {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing pull_request_id", req.RequestID)
+	}
+
+	pr, err := client.GetPullRequest(workspace, repoSlug, prID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	addJiraIssueKeys(pr)
+
+	return models.SuccessResponse(pr, req.RequestID)
+}
+
+func (s *Service) handleBitbucketGetPullRequestComments(client *api.Client, req models.JiraRequest, site string) map[string]interface{} {
+	workspace, repoSlug, errResponse := bitbucketRepo(req, site)
+	if errResponse != nil {
+		return errResponse
+	}
+	prID, ok := pullRequestID(req)
+	if !ok {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing pull_request_id", req.RequestID)
+	}
+
+	comments, err := client.GetPullRequestComments(workspace, repoSlug, prID, bitbucketLimit(req, maxBitbucketPageLen))
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	return models.SuccessResponse(comments, req.RequestID)
+}
+
+func (s *Service) handleBitbucketListPipelines(client *api.Client, req models.JiraRequest, site string) map[string]interface{} {
+	workspace, repoSlug, errResponse := bitbucketRepo(req, site)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	pipelines, err := client.ListPipelines(workspace, repoSlug, bitbucketLimit(req, maxBitbucketPageLen))
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	return models.SuccessResponse(pipelines, req.RequestID)
+}
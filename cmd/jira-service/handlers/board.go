@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	defaultSnapshotIssues = 200
+	// maxSnapshotIssues bounds how many board issues are placed, since each needs its changelog
+	maxSnapshotIssues = 500
+	// snapshotPageSize is how many board issues each request returns
+	snapshotPageSize = 100
+)
+
+// snapshotFields are the issue fields a board snapshot lists
+var snapshotFields = []string{"summary", "status", "assignee", "created"}
+
+// snapshotIssue is an issue in a board column
+type snapshotIssue struct {
+	Key          string  `json:"key"`
+	Summary      string  `json:"summary"`
+	Status       string  `json:"status"`
+	Assignee     string  `json:"assignee"`
+	DaysInColumn float64 `json:"days_in_column"`
+	URL          string  `json:"url,omitempty"`
+}
+
+// snapshotColumn is a board column with its issues, longest in the column first
+type snapshotColumn struct {
+	Name      string          `json:"name"`
+	WIPLimit  *int            `json:"wip_limit,omitempty"`
+	OverLimit bool            `json:"over_limit,omitempty"`
+	Count     int             `json:"issue_count"`
+	Issues    []snapshotIssue `json:"issues"`
+}
+
+// handleBoardSnapshot returns a board's columns with the issues in each, their assignees
+// and how long they have been in the column, from the board's configuration, its issues
+// and their changelogs. A scrum board shows the issues of its active sprints.
+func (s *Service) handleBoardSnapshot(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	boardID, _ := req.Params["board_id"].(string)
+	if !numericIDPattern.MatchString(boardID) {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing or non-numeric board_id", req.RequestID)
+	}
+	limit := defaultSnapshotIssues
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = min(int(l), maxSnapshotIssues)
+	}
+
+	board, err := client.GetBoard(boardID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	config, err := client.GetBoardConfiguration(boardID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	if len(config.ColumnConfig.Columns) == 0 {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("board %s has no columns", boardID), req.RequestID)
+	}
+
+	jql := ""
+	sprints := []map[string]interface{}{}
+	if board.Type == "scrum" {
+		active, err := client.GetSprintsFromBoard(boardID, "active")
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		if len(active) == 0 {
+			return models.ErrorResponse(models.ErrCodeInvalidRequest,
+				fmt.Sprintf("board %s has no active sprint", boardID), req.RequestID)
+		}
+		var ids []string
+		for _, sprint := range active {
+			if id, ok := sprint["id"].(float64); ok {
+				ids = append(ids, fmt.Sprintf("%.0f", id))
+				sprints = append(sprints, map[string]interface{}{"id": sprint["id"], "name": sprint["name"]})
+			}
+		}
+		jql = fmt.Sprintf("sprint in (%s)", strings.Join(ids, ", "))
+	}
+
+	var issues []models.JiraIssue
+	total := 0
+	for len(issues) < limit {
+		page, t, err := client.SearchBoardIssues(boardID, jql, snapshotFields, len(issues), min(snapshotPageSize, limit-len(issues)))
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		issues, total = append(issues, page...), t
+		if len(page) == 0 || len(issues) >= total {
+			break
+		}
+	}
+	changelogs, err := fetchChangelogs(client, issues)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	columnOf := make(map[string]int)
+	columns := make([]snapshotColumn, len(config.ColumnConfig.Columns))
+	for i, column := range config.ColumnConfig.Columns {
+		columns[i] = snapshotColumn{Name: column.Name, WIPLimit: column.Max, Issues: []snapshotIssue{}}
+		for _, status := range column.Statuses {
+			columnOf[status.ID] = i
+		}
+	}
+
+	now := time.Now().UTC()
+	unmapped := 0
+	for _, issue := range issues {
+		status, _ := issue.Fields["status"].(map[string]interface{})
+		statusID, _ := status["id"].(string)
+		col, ok := columnOf[statusID]
+		if !ok {
+			// Issues in statuses without a column are not shown on the board
+			unmapped++
+			continue
+		}
+
+		entry := snapshotIssue{Key: issue.Key, Assignee: "Unassigned", URL: issueURL(issue)}
+		entry.Summary, _ = issue.Fields["summary"].(string)
+		entry.Status, _ = status["name"].(string)
+		if assignee, ok := issue.Fields["assignee"].(map[string]interface{}); ok {
+			if name, _ := assignee["displayName"].(string); name != "" {
+				entry.Assignee = name
+			}
+		}
+		if since, ok := enteredColumn(issue, changelogs[issue.Key], columnOf, col); ok {
+			entry.DaysInColumn = roundDays(now.Sub(since).Hours() / 24)
+		}
+		columns[col].Issues = append(columns[col].Issues, entry)
+	}
+	for i := range columns {
+		column := &columns[i]
+		sort.SliceStable(column.Issues, func(a, b int) bool {
+			return column.Issues[a].DaysInColumn > column.Issues[b].DaysInColumn
+		})
+		column.Count = len(column.Issues)
+		column.OverLimit = column.WIPLimit != nil && column.Count > *column.WIPLimit
+	}
+
+	response := map[string]interface{}{
+		"board": map[string]interface{}{
+			"id":   board.ID,
+			"name": board.Name,
+			"type": board.Type,
+		},
+		"columns":   columns,
+		"issues":    len(issues),
+		"unmapped":  unmapped,
+		"truncated": len(issues) < total,
+	}
+	if board.Type == "scrum" {
+		response["sprints"] = sprints
+	}
+	return models.SuccessResponse(response, req.RequestID)
+}
+
+// enteredColumn returns when an issue last moved into a column from another one, or when
+// it was created if it never moved columns. Moves between statuses of the same column do
+// not count.
+func enteredColumn(issue models.JiraIssue, changelog []models.ChangelogEntry, columnOf map[string]int, col int) (time.Time, bool) {
+	var entered time.Time
+	for _, entry := range changelog {
+		at, err := parseJiraTime(entry.Created)
+		if err != nil {
+			continue
+		}
+		for _, item := range entry.Items {
+			if item.Field != "status" {
+				continue
+			}
+			from, fromOK := columnOf[item.From]
+			to, toOK := columnOf[item.To]
+			if toOK && to == col && (!fromOK || from != col) && at.After(entered) {
+				entered = at
+			}
+		}
+	}
+	if !entered.IsZero() {
+		return entered, true
+	}
+	created, _ := issue.Fields["created"].(string)
+	t, err := parseJiraTime(created)
+	return t, err == nil
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	defaultBulkTransitionIssues = 50
+	// maxBulkTransitionIssues is the hard cap on how many issues one bulk transition moves.
+	// A query matching more is refused rather than partly applied.
+	maxBulkTransitionIssues = 100
+	// bulkPageSize is how many issues each search returns
+	bulkPageSize = 100
+	// bulkConcurrency is how many issues are transitioned at once
+	bulkConcurrency = 5
+)
+
+// bulkIssue is an issue a bulk transition moves, or would move
+type bulkIssue struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	URL     string `json:"url,omitempty"`
+}
+
+// bulkSkip is an issue a bulk transition leaves where it is
+type bulkSkip struct {
+	Key       string   `json:"key"`
+	Status    string   `json:"status"`
+	Reason    string   `json:"reason"`
+	Available []string `json:"available_transitions,omitempty"`
+}
+
+// bulkOutcome is what happened to one issue: it moved (or would move), was skipped, or failed
+type bulkOutcome struct {
+	moved   *bulkIssue
+	skipped *bulkSkip
+	err     error
+}
+
+// handleBulkTransition moves the issues matching a JQL query through the transition with a
+// given name, optionally commenting on each. The transition is looked up per issue, since
+// its ID depends on the issue's workflow; issues without it are skipped. A query matching
+// more issues than the cap is refused. A dry run lists the issues it would move.
+func (s *Service) handleBulkTransition(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	jql, _ := req.Params["jql"].(string)
+	transitionName, _ := req.Params["transition_name"].(string)
+	jql, transitionName = strings.TrimSpace(jql), strings.TrimSpace(transitionName)
+	if jql == "" || transitionName == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "jql and transition_name are required", req.RequestID)
+	}
+	limit := defaultBulkTransitionIssues
+	if l, ok := req.Params["max_issues"].(float64); ok && l > 0 {
+		limit = min(int(l), maxBulkTransitionIssues)
+	}
+	var comment []interface{}
+	if text, _ := req.Params["comment"].(string); strings.TrimSpace(text) != "" {
+		comment, _ = descriptionContent(map[string]interface{}{"content": text})
+	}
+	dryRun := atlassian.IsDryRun(req.Params)
+
+	// One more than the cap shows whether the query matches too many issues
+	var issues []models.JiraIssue
+	pageToken := ""
+	for len(issues) <= limit {
+		results, err := client.SearchIssues(jql, []string{"summary", "status"}, min(bulkPageSize, limit+1-len(issues)), pageToken)
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		issues = append(issues, results.Issues...)
+		pageToken = results.NextPageToken
+		if pageToken == "" || len(results.Issues) == 0 {
+			break
+		}
+	}
+	if len(issues) > limit {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("the query matches more than %d issues; narrow the JQL or raise max_issues (at most %d)", limit, maxBulkTransitionIssues),
+			req.RequestID)
+	}
+
+	outcomes := make([]bulkOutcome, len(issues))
+	sem := make(chan struct{}, bulkConcurrency)
+	var wg sync.WaitGroup
+	for i, issue := range issues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			outcomes[i] = transitionOne(client, issue, transitionName, comment, dryRun)
+		}()
+	}
+	wg.Wait()
+
+	moved := []bulkIssue{}
+	skipped := []bulkSkip{}
+	failed := []hydrateError{}
+	for i, outcome := range outcomes {
+		switch {
+		case outcome.err != nil:
+			failed = append(failed, hydrateError{IssueKey: issues[i].Key, Error: outcome.err.Error()})
+		case outcome.skipped != nil:
+			skipped = append(skipped, *outcome.skipped)
+		default:
+			moved = append(moved, *outcome.moved)
+		}
+	}
+	sort.SliceStable(moved, func(i, j int) bool { return moved[i].Key < moved[j].Key })
+
+	response := map[string]interface{}{
+		"jql":        jql,
+		"transition": transitionName,
+		"matched":    len(issues),
+		"skipped":    skipped,
+		"errors":     failed,
+	}
+	if dryRun {
+		response["dry_run"] = true
+		response["would_transition"] = moved
+		response["message"] = fmt.Sprintf("Nothing was changed. Without dry_run %d issue(s) would be transitioned.", len(moved))
+		return models.SuccessResponse(response, req.RequestID)
+	}
+	if len(failed) > 0 && len(failed) == len(issues) {
+		return models.ErrorResponse(models.ErrCodeAPIError, failed[0].Error, req.RequestID)
+	}
+	response["transitioned"] = moved
+	return models.SuccessResponse(response, req.RequestID)
+}
+
+// transitionOne finds an issue's transition with a name, matching either the transition or
+// the status it leads to regardless of case, and unless it is a dry run, takes it
+func transitionOne(client *api.Client, issue models.JiraIssue, name string, comment []interface{}, dryRun bool) bulkOutcome {
+	summary, _ := issue.Fields["summary"].(string)
+	status := nameOf(issue.Fields["status"])
+
+	transitions, err := client.GetTransitions(issue.Key)
+	if err != nil {
+		return bulkOutcome{err: err}
+	}
+	var available []string
+	for _, transition := range transitions {
+		id, _ := transition["id"].(string)
+		transitionName, _ := transition["name"].(string)
+		to := nameOf(transition["to"])
+		if !strings.EqualFold(transitionName, name) && !strings.EqualFold(to, name) {
+			available = append(available, transitionName)
+			continue
+		}
+		if !dryRun {
+			if err := client.TransitionIssueWithComment(issue.Key, id, comment); err != nil {
+				return bulkOutcome{err: err}
+			}
+		}
+		return bulkOutcome{moved: &bulkIssue{Key: issue.Key, Summary: summary, From: status, To: to, URL: issueURL(issue)}}
+	}
+
+	reason := fmt.Sprintf("no %q transition from %s", name, status)
+	if strings.EqualFold(status, name) {
+		reason = "already in " + status
+	}
+	return bulkOutcome{skipped: &bulkSkip{Key: issue.Key, Status: status, Reason: reason, Available: available}}
+}
+
+// nameOf returns the name of a field such as an issue's status or a transition's target
+func nameOf(field interface{}) string {
+	m, _ := field.(map[string]interface{})
+	name, _ := m["name"].(string)
+	return name
+}
@@ -0,0 +1,392 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	// issueCountEstimate counts issues instead of summing an estimate field
+	issueCountEstimate = "issue_count"
+	// maxBurndownIssues bounds how many sprint issues are replayed
+	maxBurndownIssues = 1000
+	// burndownPageSize is how many sprint issues each search returns
+	burndownPageSize = 100
+	// changelogConcurrency is how many changelogs are fetched at once
+	changelogConcurrency = 4
+	// maxBurndownDays bounds the chart of a sprint that overran its end date
+	maxBurndownDays = 90
+)
+
+// numericIDPattern is what board and sprint IDs look like
+var numericIDPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// jiraTimeLayouts are the timestamp formats of Jira fields and of the agile API
+var jiraTimeLayouts = []string{"2006-01-02T15:04:05.000-0700", time.RFC3339}
+
+// step is a value that changed at a point in time
+type step[T any] struct {
+	at    time.Time
+	value T
+}
+
+// history is a value over time: initial until the first change, then the value of the
+// latest change
+type history[T any] struct {
+	initial T
+	changes []step[T] // Oldest first
+}
+
+func (h history[T]) at(t time.Time) T {
+	value := h.initial
+	for _, change := range h.changes {
+		if change.at.After(t) {
+			break
+		}
+		value = change.value
+	}
+	return value
+}
+
+// issueHistory is what a burndown needs to know about an issue at any point in time
+type issueHistory struct {
+	created  time.Time
+	inSprint history[bool]
+	estimate history[float64]
+	done     history[bool]
+}
+
+// burndownTotals are the sprint's figures at a point in time
+type burndownTotals struct {
+	scope     float64 // Estimates of the issues in the sprint
+	remaining float64 // Estimates of the issues in the sprint that are not done
+}
+
+// handleSprintBurndown reconstructs a sprint's burndown chart: the remaining estimate at
+// the end of each day of the sprint. Jira's own chart comes from an internal API, so the
+// chart is rebuilt from the sprint's dates, the board's estimation statistic and done
+// column, and the changelog of every issue in the sprint.
+func (s *Service) handleSprintBurndown(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	boardID, _ := req.Params["board_id"].(string)
+	sprintID, _ := req.Params["sprint_id"].(string)
+	if boardID == "" || sprintID == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing board_id or sprint_id", req.RequestID)
+	}
+	if !numericIDPattern.MatchString(boardID) || !numericIDPattern.MatchString(sprintID) {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "board_id and sprint_id must be numeric", req.RequestID)
+	}
+
+	sprint, err := client.GetSprint(sprintID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	start, startErr := parseJiraTime(sprint.StartDate)
+	end, endErr := parseJiraTime(sprint.EndDate)
+	if startErr != nil || endErr != nil || sprint.State == "future" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("sprint %s has not started", sprintID), req.RequestID)
+	}
+	// Data stops when the sprint was completed, or now for an active sprint
+	dataEnd := time.Now().UTC()
+	if completed, err := parseJiraTime(sprint.CompleteDate); err == nil {
+		dataEnd = completed
+	}
+
+	config, err := client.GetBoardConfiguration(boardID)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	columns := config.ColumnConfig.Columns
+	if len(columns) == 0 {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("board %s has no columns", boardID), req.RequestID)
+	}
+	// Like Jira's chart, issues count as done in the board's last column
+	doneStatuses := make(map[string]bool)
+	for _, status := range columns[len(columns)-1].Statuses {
+		doneStatuses[status.ID] = true
+	}
+
+	estimateField, estimateName := issueCountEstimate, "Issue count"
+	if field, ok := req.Params["estimate_field"].(string); ok && field != "" {
+		if field != issueCountEstimate {
+			estimateField, estimateName = field, field
+		}
+	} else if config.Estimation.Type == "field" && config.Estimation.Field.FieldID != "" {
+		estimateField, estimateName = config.Estimation.Field.FieldID, config.Estimation.Field.DisplayName
+	}
+
+	issues, truncated, err := sprintIssues(client, sprintID, estimateField)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	changelogs, err := fetchChangelogs(client, issues)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	histories := make([]issueHistory, len(issues))
+	for i, issue := range issues {
+		histories[i] = replayIssue(issue, changelogs[issue.Key], sprintID, estimateField, estimateName, doneStatuses)
+	}
+	totalsAt := func(t time.Time) burndownTotals {
+		var totals burndownTotals
+		for _, h := range histories {
+			if t.Before(h.created) || !h.inSprint.at(t) {
+				continue
+			}
+			estimate := h.estimate.at(t)
+			totals.scope += estimate
+			if !h.done.at(t) {
+				totals.remaining += estimate
+			}
+		}
+		return totals
+	}
+
+	// Time tracking fields are in seconds; hours chart better
+	unit, scale := "points", 1.0
+	switch {
+	case estimateField == issueCountEstimate:
+		unit = "issues"
+	case estimateField == "timeestimate" || estimateField == "timeoriginalestimate":
+		unit, scale = "hours", 1.0/3600
+	}
+
+	committed := totalsAt(start)
+	doneAtStart := committed.scope - committed.remaining
+	lastDay := end
+	if dataEnd.After(lastDay) {
+		lastDay = dataEnd
+	}
+	if limit := start.AddDate(0, 0, maxBurndownDays-1); lastDay.After(limit) {
+		lastDay = limit
+	}
+	var datapoints []map[string]interface{}
+	for day := startOfDay(start); !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		t := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		point := map[string]interface{}{
+			"date":  day.Format("2006-01-02"),
+			"ideal": roundEstimate(idealRemaining(committed.remaining, start, end, t) * scale),
+		}
+		// Days after the data ends are left for the ideal line only
+		if !day.After(dataEnd) {
+			if t.After(dataEnd) {
+				t = dataEnd
+			}
+			totals := totalsAt(t)
+			point["remaining"] = roundEstimate(totals.remaining * scale)
+			point["completed"] = roundEstimate((totals.scope - totals.remaining - doneAtStart) * scale)
+			point["scope"] = roundEstimate(totals.scope * scale)
+		}
+		datapoints = append(datapoints, point)
+	}
+	final := totalsAt(dataEnd)
+
+	return models.SuccessResponse(map[string]interface{}{
+		"sprint": map[string]interface{}{
+			"id":            sprint.ID,
+			"name":          sprint.Name,
+			"state":         sprint.State,
+			"start_date":    sprint.StartDate,
+			"end_date":      sprint.EndDate,
+			"complete_date": sprint.CompleteDate,
+		},
+		"estimate": map[string]interface{}{
+			"field": estimateField,
+			"name":  estimateName,
+			"unit":  unit,
+		},
+		"issues":       len(issues),
+		"truncated":    truncated,
+		"committed":    roundEstimate(committed.remaining * scale),
+		"scope_change": roundEstimate((final.scope - committed.scope) * scale),
+		"completed":    roundEstimate((final.scope - final.remaining - doneAtStart) * scale),
+		"remaining":    roundEstimate(final.remaining * scale),
+		"datapoints":   datapoints,
+	}, req.RequestID)
+}
+
+// sprintIssues returns the issues that are or were in a sprint with the fields a burndown
+// replays. truncated is set when the sprint has more than maxBurndownIssues issues.
+func sprintIssues(client *api.Client, sprintID, estimateField string) ([]models.JiraIssue, bool, error) {
+	fields := []string{"summary", "status", "created"}
+	if estimateField != issueCountEstimate {
+		fields = append(fields, estimateField)
+	}
+	jql := fmt.Sprintf("sprint = %s ORDER BY key ASC", sprintID)
+
+	var issues []models.JiraIssue
+	pageToken := ""
+	for {
+		results, err := client.SearchIssues(jql, fields, burndownPageSize, pageToken)
+		if err != nil {
+			return nil, false, err
+		}
+		issues = append(issues, results.Issues...)
+		if len(issues) >= maxBurndownIssues {
+			return issues[:maxBurndownIssues], len(issues) > maxBurndownIssues || results.NextPageToken != "", nil
+		}
+		if results.NextPageToken == "" || len(results.Issues) == 0 {
+			return issues, false, nil
+		}
+		pageToken = results.NextPageToken
+	}
+}
+
+// fetchChangelogs fetches the changelogs of the issues, several at a time, by issue key
+func fetchChangelogs(client *api.Client, issues []models.JiraIssue) (map[string][]models.ChangelogEntry, error) {
+	keys := make(chan string)
+	go func() {
+		defer close(keys)
+		for _, issue := range issues {
+			keys <- issue.Key
+		}
+	}()
+
+	changelogs := make(map[string][]models.ChangelogEntry, len(issues))
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	for w := 0; w < changelogConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range keys {
+				mu.Lock()
+				failed := firstErr != nil
+				mu.Unlock()
+				if failed {
+					continue // Drain the remaining keys
+				}
+
+				entries, err := client.GetIssueChangelog(key)
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					changelogs[key] = entries
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return changelogs, firstErr
+}
+
+// replayIssue builds an issue's sprint membership, estimate and done state over time from
+// its changelog. Each value starts as it was before its first change, or as it is now if
+// it never changed.
+func replayIssue(issue models.JiraIssue, changelog []models.ChangelogEntry, sprintID, estimateField, estimateName string, doneStatuses map[string]bool) issueHistory {
+	var h issueHistory
+	if created, ok := issue.Fields["created"].(string); ok {
+		h.created, _ = parseJiraTime(created)
+	}
+
+	statusID := ""
+	if status, ok := issue.Fields["status"].(map[string]interface{}); ok {
+		statusID, _ = status["id"].(string)
+	}
+	// The search matched the issue, so it is in the sprint unless the changelog says otherwise
+	h.inSprint.initial = true
+	h.done.initial = doneStatuses[statusID]
+	h.estimate.initial = 1
+	if estimateField != issueCountEstimate {
+		h.estimate.initial, _ = issue.Fields[estimateField].(float64)
+	}
+
+	entries := append([]models.ChangelogEntry{}, changelog...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Created < entries[j].Created })
+	var sprintSeen, statusSeen, estimateSeen bool
+	for _, entry := range entries {
+		at, err := parseJiraTime(entry.Created)
+		if err != nil {
+			continue
+		}
+		for _, item := range entry.Items {
+			switch {
+			case item.Field == "Sprint":
+				if !sprintSeen {
+					h.inSprint.initial = containsSprint(item.From, sprintID)
+					sprintSeen = true
+				}
+				h.inSprint.changes = append(h.inSprint.changes, step[bool]{at, containsSprint(item.To, sprintID)})
+			case item.Field == "status":
+				if !statusSeen {
+					h.done.initial = doneStatuses[item.From]
+					statusSeen = true
+				}
+				h.done.changes = append(h.done.changes, step[bool]{at, doneStatuses[item.To]})
+			case estimateField != issueCountEstimate && (item.FieldID == estimateField || item.FieldID == "" && item.Field == estimateName):
+				if !estimateSeen {
+					h.estimate.initial = estimateValue(item.FromString, item.From)
+					estimateSeen = true
+				}
+				h.estimate.changes = append(h.estimate.changes, step[float64]{at, estimateValue(item.ToString, item.To)})
+			}
+		}
+	}
+	return h
+}
+
+// containsSprint reports whether a changelog's Sprint value, a comma-separated list of
+// sprint IDs, includes the sprint
+func containsSprint(ids, sprintID string) bool {
+	for _, id := range strings.Split(ids, ",") {
+		if strings.TrimSpace(id) == sprintID {
+			return true
+		}
+	}
+	return false
+}
+
+// estimateValue reads a changelog estimate. Story points are only in the display string;
+// time tracking fields have seconds in both.
+func estimateValue(display, raw string) float64 {
+	for _, s := range []string{display, raw} {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return v
+		}
+	}
+	return 0
+}
+
+// idealRemaining is the guideline from the committed estimate at the start of the sprint
+// to nothing at its planned end
+func idealRemaining(committed float64, start, end, t time.Time) float64 {
+	if !end.After(start) || !t.Before(end) {
+		return 0
+	}
+	return committed * (1 - float64(t.Sub(start))/float64(end.Sub(start)))
+}
+
+func parseJiraTime(s string) (time.Time, error) {
+	var err error
+	for _, layout := range jiraTimeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, err
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func roundEstimate(v float64) float64 {
+	return math.Round(v*100) / 100
+}
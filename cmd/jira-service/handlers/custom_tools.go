@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// customTools indexes the tools.yaml tools of a service by action
+func customTools(defs []config.ToolDefinition, service string) map[string]config.ToolDefinition {
+	tools := make(map[string]config.ToolDefinition)
+	for _, def := range defs {
+		if def.Service() == service {
+			tools[def.Action()] = def
+		}
+	}
+	return tools
+}
+
+// handleCustomTool requests the endpoint of a tools.yaml tool and returns its JSON as is
+func (s *Service) handleCustomTool(client *api.Client, def config.ToolDefinition, req models.JiraRequest) map[string]interface{} {
+	path, query, body, err := def.Request(req.Params)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	}
+
+	result, err := client.Passthrough(def.Method, path, query, body)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	return models.SuccessResponse(result, req.RequestID)
+}
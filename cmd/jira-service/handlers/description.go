@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// handleAppendToDescription adds content to the end of an issue's description, or to the
+// end of the section under heading, keeping everything else as written
+func (s *Service) handleAppendToDescription(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	heading, _ := req.Params["heading"].(string)
+	mode := "append"
+	if strings.TrimSpace(heading) != "" {
+		mode = "append_section"
+	}
+	return s.patchDescription(client, req, heading, mode)
+}
+
+// handleUpdateDescriptionSection replaces the content under one heading of an issue's
+// description, keeping the heading and the rest of the description
+func (s *Service) handleUpdateDescriptionSection(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	heading, ok := req.Params["heading"].(string)
+	if !ok || strings.TrimSpace(heading) == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing heading", req.RequestID)
+	}
+	return s.patchDescription(client, req, heading, "replace_section")
+}
+
+// patchDescription fetches an issue's description, applies the request's content to it
+// and writes the merged document back
+func (s *Service) patchDescription(client *api.Client, req models.JiraRequest, heading, mode string) map[string]interface{} {
+	issueKey, ok := req.Params["issue_key"].(string)
+	if !ok || issueKey == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing issue_key", req.RequestID)
+	}
+	nodes, err := descriptionContent(req.Params)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	}
+	if len(nodes) == 0 && mode != "replace_section" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing content", req.RequestID)
+	}
+
+	issue, err := client.GetIssue(issueKey, nil)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	doc := map[string]interface{}{"type": "doc", "version": 1, "content": []interface{}{}}
+	switch d := issue.Fields["description"].(type) {
+	case nil:
+	case map[string]interface{}:
+		doc = d
+	default:
+		return models.ErrorResponse(models.ErrCodeInternal,
+			fmt.Sprintf("description of %s is not in Atlassian Document Format", issueKey), req.RequestID)
+	}
+	existing, _ := doc["content"].([]interface{})
+	undo := undoFieldUpdate(issueKey, issue, []string{"description"})
+
+	merged, err := patchADFSection(existing, heading, nodes, mode)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	}
+	doc["content"] = merged
+
+	if err := client.UpdateIssue(issueKey, map[string]interface{}{"description": doc}); err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	response := models.SuccessResponse(map[string]interface{}{
+		"issue_key": issueKey,
+		"status":    "updated",
+		"nodes":     len(merged),
+	}, req.RequestID)
+	if undo != nil {
+		response["undo"] = undo
+	}
+	return response
+}
+
+// descriptionContent is the ADF nodes a patch adds: the adf param (a document, a node or a
+// list of nodes) or else the content param as plain text, one paragraph per blank-line
+// separated block
+func descriptionContent(params map[string]interface{}) ([]interface{}, error) {
+	switch adf := params["adf"].(type) {
+	case nil:
+	case []interface{}:
+		return adf, nil
+	case map[string]interface{}:
+		if adf["type"] == "doc" {
+			nodes, _ := adf["content"].([]interface{})
+			return nodes, nil
+		}
+		if _, ok := adf["type"].(string); !ok {
+			return nil, fmt.Errorf("adf must be an Atlassian Document Format document or node")
+		}
+		return []interface{}{adf}, nil
+	default:
+		return nil, fmt.Errorf("adf must be an Atlassian Document Format document or node")
+	}
+
+	text, _ := params["content"].(string)
+	var nodes []interface{}
+	for _, block := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		var inline []interface{}
+		for i, line := range strings.Split(strings.Trim(block, "\n"), "\n") {
+			if i > 0 {
+				inline = append(inline, map[string]interface{}{"type": "hardBreak"})
+			}
+			if line != "" {
+				inline = append(inline, map[string]interface{}{"type": "text", "text": line})
+			}
+		}
+		nodes = append(nodes, map[string]interface{}{"type": "paragraph", "content": inline})
+	}
+	return nodes, nil
+}
+
+// patchADFSection changes a document's top-level nodes. append adds nodes at the end;
+// append_section and replace_section work on the section under the heading titled heading
+// (compared without case), which runs to the next heading of the same or a higher level.
+func patchADFSection(content []interface{}, heading string, nodes []interface{}, mode string) ([]interface{}, error) {
+	merged := make([]interface{}, 0, len(content)+len(nodes))
+	if mode == "append" {
+		return append(append(merged, content...), nodes...), nil
+	}
+
+	want := strings.ToLower(strings.TrimSpace(heading))
+	var titles []string
+	for i, node := range content {
+		level, title, ok := adfHeading(node)
+		if !ok {
+			continue
+		}
+		titles = append(titles, title)
+		if strings.ToLower(title) != want {
+			continue
+		}
+
+		end := len(content)
+		for j := i + 1; j < len(content); j++ {
+			if nextLevel, _, ok := adfHeading(content[j]); ok && nextLevel <= level {
+				end = j
+				break
+			}
+		}
+
+		if mode == "append_section" {
+			merged = append(merged, content[:end]...)
+		} else {
+			merged = append(merged, content[:i+1]...)
+		}
+		merged = append(merged, nodes...)
+		return append(merged, content[end:]...), nil
+	}
+
+	if len(titles) == 0 {
+		return nil, fmt.Errorf("section %q not found: the description has no headings", heading)
+	}
+	return nil, fmt.Errorf("section %q not found; the description's headings are: %s", heading, strings.Join(titles, ", "))
+}
+
+// adfHeading returns the level and text of an ADF heading node
+func adfHeading(node interface{}) (int, string, bool) {
+	n, ok := node.(map[string]interface{})
+	if !ok || n["type"] != "heading" {
+		return 0, "", false
+	}
+	level := 1
+	if attrs, ok := n["attrs"].(map[string]interface{}); ok {
+		if l, ok := attrs["level"].(float64); ok {
+			level = int(l)
+		}
+	}
+	var text strings.Builder
+	if inline, ok := n["content"].([]interface{}); ok {
+		for _, item := range inline {
+			if t, ok := item.(map[string]interface{}); ok {
+				if s, ok := t["text"].(string); ok {
+					text.WriteString(s)
+				}
+			}
+		}
+	}
+	return level, strings.Join(strings.Fields(text.String()), " "), true
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	defaultDigestDays = 7
+	maxDigestDays     = 90
+	// defaultDigestIssues and maxDigestIssues bound how many issues are checked for comments
+	defaultDigestIssues = 20
+	maxDigestIssues     = 50
+	// defaultDigestComments and maxDigestComments bound the comments listed per issue
+	defaultDigestComments = 10
+	maxDigestComments     = 50
+	// digestExcerptLength is how much of each comment is kept, in characters
+	digestExcerptLength = 300
+	// digestConcurrency is how many issues' comments are fetched at once
+	digestConcurrency = 5
+)
+
+// digestFields are the issue fields a comment digest lists
+var digestFields = []string{"summary", "status", "updated"}
+
+// digestComment is a comment, shortened
+type digestComment struct {
+	Author    string `json:"author"`
+	Created   string `json:"created"`
+	Text      string `json:"text"`
+	Truncated bool   `json:"truncated,omitempty"` // The text was cut short
+}
+
+// digestIssue is an issue's recent comments, oldest first
+type digestIssue struct {
+	Key          string          `json:"key"`
+	Summary      string          `json:"summary"`
+	Status       string          `json:"status,omitempty"`
+	URL          string          `json:"url,omitempty"`
+	Comments     []digestComment `json:"comments"`
+	MoreComments bool            `json:"more_comments,omitempty"` // Older comments in the window were left out
+	latest       time.Time
+}
+
+// handleCommentDigest collects the recent comments of one issue, or of the issues matching
+// a JQL query, grouped by issue and shortened, so that a conversation can be caught up on
+// in one call. Issues without comments in the window are left out; the others come most
+// recently commented first.
+func (s *Service) handleCommentDigest(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	issueKey, _ := req.Params["issue_key"].(string)
+	jql, _ := req.Params["jql"].(string)
+	issueKey, jql = strings.TrimSpace(issueKey), strings.TrimSpace(jql)
+	if (issueKey == "") == (jql == "") {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "pass either issue_key or jql", req.RequestID)
+	}
+	days := defaultDigestDays
+	if d, ok := req.Params["days"].(float64); ok && d > 0 {
+		days = min(int(d), maxDigestDays)
+	}
+	limit := defaultDigestIssues
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = min(int(l), maxDigestIssues)
+	}
+	perIssue := defaultDigestComments
+	if c, ok := req.Params["max_comments"].(float64); ok && c > 0 {
+		perIssue = min(int(c), maxDigestComments)
+	}
+
+	// Commenting updates an issue, so only recently updated issues can have new comments
+	search := fmt.Sprintf(`key = "%s"`, strings.ReplaceAll(issueKey, `"`, ""))
+	if jql != "" {
+		base, _ := atlassian.SplitOrderBy(jql)
+		search = fmt.Sprintf("updated >= -%dd ORDER BY updated DESC", days)
+		if base != "" {
+			search = fmt.Sprintf("(%s) AND %s", base, search)
+		}
+	}
+	results, err := client.SearchIssues(search, digestFields, limit, "")
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	if issueKey != "" && len(results.Issues) == 0 {
+		return models.ErrorResponse(models.ErrCodeNotFound, fmt.Sprintf("issue %s not found", issueKey), req.RequestID)
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	digests := make([]*digestIssue, len(results.Issues))
+	failures := make([]error, len(results.Issues))
+	sem := make(chan struct{}, digestConcurrency)
+	var wg sync.WaitGroup
+	for i, issue := range results.Issues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			// One more than is listed shows whether older comments were left out
+			comments, err := client.GetIssueComments(issue.Key, perIssue+1)
+			if err != nil {
+				failures[i] = err
+				return
+			}
+			digests[i] = digestOf(issue, comments, since, perIssue)
+		}()
+	}
+	wg.Wait()
+
+	issues := []*digestIssue{}
+	failed := []hydrateError{}
+	commentCount := 0
+	for i, digest := range digests {
+		if failures[i] != nil {
+			failed = append(failed, hydrateError{IssueKey: results.Issues[i].Key, Error: failures[i].Error()})
+			continue
+		}
+		if len(digest.Comments) > 0 {
+			issues = append(issues, digest)
+			commentCount += len(digest.Comments)
+		}
+	}
+	if len(failed) > 0 && len(failed) == len(results.Issues) {
+		return models.ErrorResponse(models.ErrCodeAPIError, failed[0].Error, req.RequestID)
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].latest.After(issues[j].latest) })
+
+	response := map[string]interface{}{
+		"since":          since.Format(time.RFC3339),
+		"issues_checked": len(results.Issues),
+		"comment_count":  commentCount,
+		"issues":         issues,
+		"errors":         failed,
+	}
+	if jql != "" {
+		response["jql"] = jql
+		response["truncated"] = results.NextPageToken != ""
+	}
+	return models.SuccessResponse(response, req.RequestID)
+}
+
+// digestOf shortens an issue's comments made since a time, given newest first, keeping up
+// to limit of them in the order they were made
+func digestOf(issue models.JiraIssue, comments []models.IssueComment, since time.Time, limit int) *digestIssue {
+	digest := &digestIssue{
+		Key:      issue.Key,
+		URL:      issueURL(issue),
+		Comments: []digestComment{},
+	}
+	digest.Summary, _ = issue.Fields["summary"].(string)
+	if status, ok := issue.Fields["status"].(map[string]interface{}); ok {
+		digest.Status, _ = status["name"].(string)
+	}
+
+	for _, comment := range comments {
+		created, err := parseJiraTime(comment.Created)
+		if err != nil || created.Before(since) {
+			break
+		}
+		if len(digest.Comments) == limit {
+			digest.MoreComments = true
+			break
+		}
+		if digest.latest.IsZero() {
+			digest.latest = created
+		}
+
+		entry := digestComment{Author: "Unknown", Created: summaryTime(comment.Created)}
+		if comment.Author != nil && comment.Author.DisplayName != "" {
+			entry.Author = comment.Author.DisplayName
+		}
+		text := []rune(strings.Join(strings.Fields(fieldText(comment.Body)), " "))
+		if len(text) > digestExcerptLength {
+			text, entry.Truncated = text[:digestExcerptLength], true
+		}
+		entry.Text = string(text)
+		digest.Comments = append(digest.Comments, entry)
+	}
+
+	// Oldest first, so the issue's comments read as a conversation
+	for i, j := 0, len(digest.Comments)-1; i < j; i, j = i+1, j-1 {
+		digest.Comments[i], digest.Comments[j] = digest.Comments[j], digest.Comments[i]
+	}
+	return digest
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// directoryActions answer user and group directory questions. Sent without a
+// workspace_id, they are answered by every workspace the user can use.
+var directoryActions = map[string]bool{
+	"admin_list_users":  true,
+	"admin_list_groups": true,
+}
+
+// Each user lookup with access details is a separate Atlassian call, so pages stay small
+const (
+	defaultDirectoryLimit = 25
+	maxDirectoryLimit     = 100
+)
+
+func directoryLimit(req models.JiraRequest) int {
+	limit := defaultDirectoryLimit
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	if limit > maxDirectoryLimit {
+		limit = maxDirectoryLimit
+	}
+	return limit
+}
+
+// itemNames returns the names in an expanded Jira collection such as {"items": [{"name": ...}]}
+func itemNames(collection interface{}) []string {
+	names := []string{}
+	c, _ := collection.(map[string]interface{})
+	items, _ := c["items"].([]interface{})
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			if name, ok := m["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// directoryUser summarizes a Jira user; products lists the Jira products the user has access to
+func directoryUser(user map[string]interface{}) map[string]interface{} {
+	summary := map[string]interface{}{
+		"account_id":   user["accountId"],
+		"display_name": user["displayName"],
+		"active":       user["active"],
+		"account_type": user["accountType"],
+	}
+	if email, ok := user["emailAddress"].(string); ok && email != "" {
+		summary["email"] = email
+	}
+	if _, ok := user["applicationRoles"]; ok {
+		summary["products"] = itemNames(user["applicationRoles"])
+	}
+	if _, ok := user["groups"]; ok {
+		summary["groups"] = itemNames(user["groups"])
+	}
+	return summary
+}
+
+func (s *Service) handleAdminListUsers(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	query, _ := req.Params["query"].(string)
+	includeAccess := true
+	if v, ok := req.Params["include_access"].(bool); ok {
+		includeAccess = v
+	}
+
+	users, err := client.ListUsers(query, directoryLimit(req))
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	results := make([]map[string]interface{}, 0, len(users))
+	for _, user := range users {
+		// App and customer accounts cannot hold product licenses
+		if accountType, _ := user["accountType"].(string); accountType != "" && accountType != "atlassian" {
+			continue
+		}
+		if accountID, _ := user["accountId"].(string); includeAccess && accountID != "" {
+			detailed, err := client.GetUserAccess(accountID)
+			if err != nil {
+				return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+			}
+			user = detailed
+		}
+		results = append(results, directoryUser(user))
+	}
+
+	return models.SuccessResponse(results, req.RequestID)
+}
+
+func (s *Service) handleAdminListGroups(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	query, _ := req.Params["query"].(string)
+	includeMembers, _ := req.Params["include_members"].(bool)
+	limit := directoryLimit(req)
+
+	groups, err := client.ListGroups(query, limit)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	if includeMembers {
+		for _, group := range groups {
+			groupID, _ := group["groupId"].(string)
+			if groupID == "" {
+				continue
+			}
+			members, err := client.GetGroupMembers(groupID, limit)
+			if err != nil {
+				return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+			}
+			summaries := make([]map[string]interface{}, 0, len(members))
+			for _, member := range members {
+				summaries = append(summaries, directoryUser(member))
+			}
+			group["members"] = summaries
+		}
+	}
+
+	return models.SuccessResponse(groups, req.RequestID)
+}
+
+// dispatchAllWorkspaces runs a directory action against each workspace the user owns or
+// shares through their organization, and returns one entry per workspace. A workspace
+// that fails is reported in its entry rather than failing the whole request.
+func (s *Service) dispatchAllWorkspaces(ctx context.Context, req models.JiraRequest) map[string]interface{} {
+	workspaces, err := s.credStore.ListWorkspaces(req.UserID)
+	if err == nil && req.OrgID != "" {
+		var shared []models.AtlassianCredential
+		shared, err = s.credStore.ListWorkspaces(req.OrgID)
+		workspaces = append(workspaces, shared...)
+	}
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInternal, fmt.Sprintf("failed to list workspaces: %v", err), req.RequestID)
+	}
+	if len(workspaces) == 0 {
+		return models.ErrorResponse(models.ErrCodeNotFound, "no workspaces configured", req.RequestID)
+	}
+
+	var apiBytes int64
+	results := make([]map[string]interface{}, 0, len(workspaces))
+	seen := make(map[string]bool)
+	for _, ws := range workspaces {
+		if seen[ws.WorkspaceID] {
+			continue
+		}
+		seen[ws.WorkspaceID] = true
+
+		workspaceReq := req
+		workspaceReq.WorkspaceID = ws.WorkspaceID
+		response := s.dispatch(ctx, workspaceReq)
+		if usage, ok := response["usage"].(*models.UsageInfo); ok {
+			apiBytes += usage.APIBytes
+		}
+
+		result := map[string]interface{}{
+			"workspace_id":   ws.WorkspaceID,
+			"workspace_name": ws.WorkspaceName,
+			"site":           ws.AtlassianURL,
+		}
+		if ws.JiraURL != "" {
+			result["site"] = ws.JiraURL
+		}
+		if succeeded, _ := response["success"].(bool); succeeded {
+			result["results"] = response["data"]
+		} else if info, ok := response["error"].(*models.ErrorInfo); ok {
+			result["error"] = info.Message
+		}
+		results = append(results, result)
+	}
+
+	response := models.SuccessResponse(results, req.RequestID)
+	response["usage"] = &models.UsageInfo{APIBytes: apiBytes}
+	return response
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// maxSuggestedValues bounds how many valid values one suggestion lists
+const maxSuggestedValues = 20
+
+// jqlProjectValuePattern finds the project JQL complains does not exist
+var jqlProjectValuePattern = regexp.MustCompile(`The value '([^']*)' does not exist for the field 'project'`)
+
+// suggestFixes describes a failed Atlassian call in its error (see atlassian.EnrichError)
+// and, when Jira rejected a field's value, looks up the values it would accept, so that
+// the caller can correct the call instead of giving up. Lookups that fail add nothing.
+func (s *Service) suggestFixes(client *api.Client, req models.JiraRequest, policy *models.WorkspacePolicy, info *models.ErrorInfo) {
+	atlassian.EnrichError(info, client.FailedStatus())
+	if info.HTTPStatus != http.StatusBadRequest && info.HTTPStatus != http.StatusNotFound {
+		return
+	}
+	body, _ := info.Details.(atlassian.ErrorBody)
+	projectKey, _ := req.Params["project_key"].(string)
+
+	var suggestions []string
+	for _, field := range body.FieldNames() {
+		switch {
+		case field == "issuetype" && projectKey != "":
+			if types, err := client.GetProjectIssueTypes(projectKey); err == nil && len(types) > 0 {
+				names := make([]string, len(types))
+				for i, t := range types {
+					names[i] = t.Name
+				}
+				issueType, _ := req.Params["issue_type"].(string)
+				suggestions = append(suggestions, fmt.Sprintf("issue type %q is not available in %s; valid types: %s",
+					issueType, projectKey, listValues(names)))
+			}
+		case field == "project" && projectKey != "":
+			if suggestion := validProjects(client, policy, projectKey); suggestion != "" {
+				suggestions = append(suggestions, suggestion)
+			}
+		case field == "priority":
+			if priorities, err := client.GetPriorities(); err == nil && len(priorities) > 0 {
+				names := make([]string, len(priorities))
+				for i, p := range priorities {
+					names[i] = p.Name
+				}
+				suggestions = append(suggestions, "valid priorities: "+listValues(names))
+			}
+		case strings.Contains(body.Fields[field], "cannot be set"):
+			suggestions = append(suggestions, fmt.Sprintf("field %s is unknown or not on the project's screen; use jira_search_fields to find the right field ID", field))
+		}
+	}
+
+	issueKey, _ := req.Params["issue_key"].(string)
+	if req.Action == "transition_issue" && issueKey != "" && info.HTTPStatus == http.StatusBadRequest {
+		if transitions, err := client.GetTransitions(issueKey); err == nil && len(transitions) > 0 {
+			valid := make([]string, 0, len(transitions))
+			for _, t := range transitions {
+				valid = append(valid, fmt.Sprintf("%v (%v)", t["id"], t["name"]))
+			}
+			transitionID, _ := req.Params["transition_id"].(string)
+			suggestions = append(suggestions, fmt.Sprintf("transition %s is not available for %s; valid transitions: %s",
+				transitionID, issueKey, listValues(valid)))
+		}
+	}
+
+	if jql, ok := req.Params["jql"].(string); ok && jql != "" && info.HTTPStatus == http.StatusBadRequest {
+		for _, message := range body.Messages {
+			if match := jqlProjectValuePattern.FindStringSubmatch(message); match != nil {
+				if suggestion := validProjects(client, policy, match[1]); suggestion != "" {
+					suggestions = append(suggestions, suggestion)
+				}
+			} else if strings.Contains(message, "does not exist or you do not have permission to view it") {
+				suggestions = append(suggestions, "use jira_search_fields to find field names, and quote values that contain spaces")
+			}
+		}
+	}
+
+	info.Suggestions = append(suggestions, info.Suggestions...)
+}
+
+// validProjects suggests the projects the workspace may use in place of projectKey
+func validProjects(client *api.Client, policy *models.WorkspacePolicy, projectKey string) string {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return ""
+	}
+	projects = filterProjects(policy, projects)
+	if len(projects) == 0 {
+		return ""
+	}
+	keys := make([]string, len(projects))
+	for i, p := range projects {
+		keys[i] = p.Key
+	}
+	return fmt.Sprintf("project %q was not found; valid projects: %s", projectKey, listValues(keys))
+}
+
+// listValues joins values for a suggestion, noting how many were left out
+func listValues(values []string) string {
+	if len(values) <= maxSuggestedValues {
+		return strings.Join(values, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(values[:maxSuggestedValues], ", "), len(values)-maxSuggestedValues)
+}
@@ -1,10 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 
 	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/cache"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -15,6 +22,15 @@ import (
 type Service struct {
 	credStore  storage.CredentialStoreInterface
 	apiTimeout time.Duration
+
+	idempotency    storage.IdempotencyStoreInterface // nil disables idempotency keys
+	idempotencyTTL time.Duration
+
+	redaction *models.RedactionPolicy // nil returns results unchanged
+	cache     cache.Backend           // Custom field names for redaction, by site
+
+	customTools map[string]config.ToolDefinition // tools.yaml passthrough tools, by action
+	userTokens  *atlassian.UserTokens            // Callers' own OAuth tokens, for user OAuth workspaces
 }
 
 // NewService creates a new Jira service
@@ -22,33 +38,149 @@ func NewService(credStore storage.CredentialStoreInterface, timeout time.Duratio
 	return &Service{
 		credStore:  credStore,
 		apiTimeout: timeout,
+		cache:      cache.NewMemoryBackend(),
+	}
+}
+
+// WithIdempotency remembers the results of mutating requests sent with an
+// idempotency_key for ttl, and replays them instead of repeating the request
+func (s *Service) WithIdempotency(store storage.IdempotencyStoreInterface, ttl time.Duration) *Service {
+	s.idempotency = store
+	s.idempotencyTTL = ttl
+	return s
+}
+
+// WithRedaction hides what policy names in every result before it is returned
+func (s *Service) WithRedaction(policy models.RedactionPolicy) *Service {
+	if policy.Enabled() {
+		s.redaction = &policy
 	}
+	return s
+}
+
+// WithCache caches in backend instead of this process's memory, e.g. to share the
+// cache between replicas
+func (s *Service) WithCache(backend cache.Backend) *Service {
+	s.cache = backend
+	return s
+}
+
+// WithCustomTools serves the jira_* passthrough tools among defs
+func (s *Service) WithCustomTools(defs []config.ToolDefinition) *Service {
+	s.customTools = customTools(defs, "jira")
+	return s
+}
+
+// WithUserTokens calls Atlassian with the caller's own OAuth token in workspaces whose
+// policy has user_oauth
+func (s *Service) WithUserTokens(tokens *atlassian.UserTokens) *Service {
+	s.userTokens = tokens
+	return s
 }
 
 // HandleRequest processes incoming RabbitMQ messages
 func (s *Service) HandleRequest(d amqp.Delivery) []byte {
+	requestID, _ := d.Headers[logging.RequestIDHeader].(string)
+	return s.HandleMessage(d.Body, requestID)
+}
+
+// HandleMessage processes a request received over any message bus and returns the
+// JSON reply. requestID is the correlation ID sent alongside the body, if any.
+func (s *Service) HandleMessage(body []byte, requestID string) []byte {
 	var req models.JiraRequest
-	if err := json.Unmarshal(d.Body, &req); err != nil {
-		response := models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	if err := json.Unmarshal(body, &req); err != nil {
+		response := models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), requestID)
 		responseBytes, _ := json.Marshal(response)
 		return responseBytes
 	}
 
+	// The MCP server sends the HTTP request's correlation ID in both the header and
+	// the body; prefer the header so malformed bodies can still be traced
+	if requestID != "" {
+		req.RequestID = requestID
+	}
+	ctx := logging.WithRequestID(context.Background(), req.RequestID)
+
+	// Stop working on the request once the caller has given up on it
+	if req.Deadline > 0 {
+		deadline := time.UnixMilli(req.Deadline)
+		if time.Now().After(deadline) {
+			slog.WarnContext(ctx, "request expired before processing", "action", req.Action, "workspace_id", req.WorkspaceID)
+			responseBytes, _ := json.Marshal(models.ErrorResponse(models.ErrCodeTimeout, "request expired before it was processed", req.RequestID))
+			return responseBytes
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	start := time.Now()
+	var response map[string]interface{}
+	if key := storage.IdempotencyKey(req.Params); key != "" && s.idempotency != nil && models.JiraMutatingActions[req.Action] && !atlassian.IsDryRun(req.Params) {
+		response = storage.RunIdempotent(s.idempotency, s.idempotencyTTL, req.UserID, key, req.Action, req.WorkspaceID,
+			req.Params, req.RequestID, func() map[string]interface{} { return s.dispatch(ctx, req) })
+	} else {
+		response = s.dispatch(ctx, req)
+	}
+	succeeded, _ := response["success"].(bool)
+	metrics.ToolCalls.Inc(req.Action, req.WorkspaceID, metrics.Status(!succeeded))
+	metrics.ToolCallDuration.ObserveSince(start, req.Action, req.WorkspaceID)
+	// Work the deadline did not stop in time (e.g. between Atlassian calls) is an overrun;
+	// the caller has already given up on the reply
+	if req.Deadline > 0 && time.Now().After(time.UnixMilli(req.Deadline)) {
+		metrics.ToolCallOverruns.Inc(req.Action)
+		slog.WarnContext(ctx, "request finished after its deadline", "action", req.Action, "workspace_id", req.WorkspaceID,
+			"overrun", time.Since(time.UnixMilli(req.Deadline)).String())
+	}
+	if !succeeded {
+		attrs := []any{"action", req.Action, "workspace_id", req.WorkspaceID, "user_id", req.UserID}
+		if info, ok := response["error"].(*models.ErrorInfo); ok {
+			attrs = append(attrs, "code", info.Code, "error", info.Message)
+		}
+		slog.WarnContext(ctx, "Jira request failed", attrs...)
+	}
+
+	responseBytes, _ := json.Marshal(response)
+	return responseBytes
+}
+
+// dispatch loads the workspace credentials and routes the request to its action handler
+func (s *Service) dispatch(ctx context.Context, req models.JiraRequest) map[string]interface{} {
+	if req.Action == atlassian.RecordingsAction {
+		return s.handleRecordings(req)
+	}
+	if req.WorkspaceID == "" && directoryActions[req.Action] {
+		return s.dispatchAllWorkspaces(ctx, req)
+	}
+
 	// Get credentials for the workspace
-	creds, err := s.credStore.GetCredentials(req.UserID, req.WorkspaceID)
+	creds, err := storage.GetMemberCredentials(s.credStore, req.UserID, req.OrgID, req.WorkspaceID)
 	if err != nil {
-		response := models.ErrorResponse(models.ErrCodeAuthFailed,
+		return models.ErrorResponse(models.ErrCodeAuthFailed,
 			fmt.Sprintf("workspace not found: %s", req.WorkspaceID), req.RequestID)
-		responseBytes, _ := json.Marshal(response)
-		return responseBytes
+	}
+
+	// Enforce the workspace's tool permission policy
+	if response := s.enforcePolicy(creds.Policy, &req); response != nil {
+		return response
+	}
+
+	// The rate limit budget is this service's own record, so only membership is checked
+	if req.Action == atlassian.RateStatusAction {
+		return models.SuccessResponse(atlassian.RateStatus(req.WorkspaceID), req.RequestID)
 	}
 
 	// Create API client
-	client := api.NewClient(api.WorkspaceCredentials{
-		Site:  creds.Site,
-		Email: creds.Email,
-		Token: creds.Token,
-	}, s.apiTimeout)
+	clientCreds, err := s.clientCredentials(ctx, req.UserID, creds)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAuthFailed, err.Error(), req.RequestID)
+	}
+	clientCreds.WorkspaceID = req.WorkspaceID
+	client := api.NewClient(clientCreds, s.apiTimeout).WithContext(ctx)
+	dryRun := atlassian.IsDryRun(req.Params) && models.JiraMutatingActions[req.Action]
+	if dryRun {
+		client.WithDryRun()
+	}
 
 	// Route to appropriate handler
 	var response map[string]interface{}
@@ -57,16 +189,30 @@ func (s *Service) HandleRequest(d amqp.Delivery) []byte {
 		response = s.handleListIssues(client, req)
 	case "get_issue":
 		response = s.handleGetIssue(client, req)
+	case "hydrate_issues":
+		response = s.handleHydrateIssues(client, req)
+	case "get_label_taxonomy":
+		response = s.handleGetLabelTaxonomy(client, req)
+	case "create_component":
+		response = s.handleCreateComponent(client, req)
 	case "create_issue":
 		response = s.handleCreateIssue(client, req)
 	case "update_issue":
 		response = s.handleUpdateIssue(client, req)
 	case "add_comment":
 		response = s.handleAddComment(client, req)
+	case "append_to_description":
+		response = s.handleAppendToDescription(client, req)
+	case "update_description_section":
+		response = s.handleUpdateDescriptionSection(client, req)
 	case "transition_issue":
 		response = s.handleTransitionIssue(client, req)
+	case "import_issues":
+		response = s.handleImportIssues(client, req)
+	case "bulk_transition":
+		response = s.handleBulkTransition(client, req)
 	case "list_projects":
-		response = s.handleListProjects(client, req)
+		response = s.handleListProjects(client, req, creds.Policy)
 	case "get_agile_boards":
 		response = s.handleGetAgileBoards(client, req)
 	case "get_board_issues":
@@ -101,13 +247,79 @@ func (s *Service) HandleRequest(d amqp.Delivery) []byte {
 		response = s.handleCreateIssueLink(client, req)
 	case "remove_issue_link":
 		response = s.handleRemoveIssueLink(client, req)
+	case "find_similar_issues":
+		response = s.handleFindSimilarIssues(client, req)
+	case "sprint_burndown":
+		response = s.handleSprintBurndown(client, req)
+	case "issue_aging":
+		response = s.handleIssueAging(client, req)
+	case "comment_digest":
+		response = s.handleCommentDigest(client, req)
+	case "render_issue":
+		response = s.handleRenderIssue(client, req, creds.JiraSite())
+	case "board_snapshot":
+		response = s.handleBoardSnapshot(client, req)
+	case "suggest_assignee":
+		response = s.handleSuggestAssignee(client, req)
+	case "bitbucket_list_repositories":
+		response = s.handleBitbucketListRepositories(client, req, creds.Site)
+	case "bitbucket_list_pull_requests":
+		response = s.handleBitbucketListPullRequests(client, req, creds.Site)
+	case "bitbucket_get_pull_request":
+		response = s.handleBitbucketGetPullRequest(client, req, creds.Site)
+	case "bitbucket_get_pull_request_comments":
+		response = s.handleBitbucketGetPullRequestComments(client, req, creds.Site)
+	case "bitbucket_list_pipelines":
+		response = s.handleBitbucketListPipelines(client, req, creds.Site)
+	case "opsgenie_list_alerts":
+		response = s.handleOpsgenieListAlerts(client, req)
+	case "opsgenie_acknowledge_alert":
+		response = s.handleOpsgenieAlertAction(client, req, client.AcknowledgeAlert)
+	case "opsgenie_close_alert":
+		response = s.handleOpsgenieAlertAction(client, req, client.CloseAlert)
+	case "opsgenie_create_alert":
+		response = s.handleOpsgenieCreateAlert(client, req, creds.JiraSite())
+	case "admin_list_users":
+		response = s.handleAdminListUsers(client, req)
+	case "admin_list_groups":
+		response = s.handleAdminListGroups(client, req)
 	default:
-		response = models.ErrorResponse(models.ErrCodeInvalidRequest,
-			fmt.Sprintf("unknown action: %s", req.Action), req.RequestID)
+		if def, ok := s.customTools[req.Action]; ok {
+			response = s.handleCustomTool(client, def, req)
+		} else {
+			response = models.ErrorResponse(models.ErrCodeInvalidRequest,
+				fmt.Sprintf("unknown action: %s", req.Action), req.RequestID)
+		}
 	}
 
-	responseBytes, _ := json.Marshal(response)
-	return responseBytes
+	// A dry run answers with the changes it would have made. Bulk transitions and imports
+	// list the issues they would move or create themselves, which says more than their
+	// requests would.
+	if dryRun && req.Action != "bulk_transition" && req.Action != "import_issues" {
+		response = atlassian.DryRunResponse(client.DryRunRequests(), response, req.RequestID)
+	}
+
+	// Failed Atlassian calls say how to correct them; the lookups this takes are metered too
+	if info, ok := response["error"].(*models.ErrorInfo); ok {
+		s.suggestFixes(client, req, creds.Policy, info)
+	}
+
+	// Hide what the deployment's redaction policy keeps from the model
+	response = s.redact(client, creds.JiraSite(), response, req.RequestID)
+
+	// Report Atlassian API volume for usage metering
+	if _, ok := response["usage"]; !ok {
+		response["usage"] = &models.UsageInfo{APIBytes: client.BytesTransferred()}
+	}
+
+	// Whatever the action, a rejected API token is an authentication failure. Only a 401
+	// from the site itself means the workspace's credentials no longer work.
+	if info, ok := response["error"].(*models.ErrorInfo); ok && client.CredentialsRejected() {
+		info.Code = models.ErrCodeAuthFailed
+		info.CredentialsRejected = true
+	}
+
+	return response
 }
 
 func (s *Service) handleListIssues(client *api.Client, req models.JiraRequest) map[string]interface{} {
@@ -129,11 +341,22 @@ func (s *Service) handleListIssues(client *api.Client, req models.JiraRequest) m
 		}
 	}
 
-	results, err := client.SearchIssues(jql, fields, limit)
+	pageToken, _ := req.Params["next_page_token"].(string)
+
+	// Overviews need only one line per issue, and only the fields that line shows
+	summarize, _ := req.Params["summarize"].(bool)
+	if summarize {
+		fields = summaryFields
+	}
+
+	results, err := client.SearchIssues(jql, fields, limit, pageToken)
 	if err != nil {
 		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
 	}
 
+	if summarize {
+		return models.SuccessResponse(summarizeIssues(results), req.RequestID)
+	}
 	return models.SuccessResponse(results, req.RequestID)
 }
 
@@ -205,12 +428,26 @@ func (s *Service) handleUpdateIssue(client *api.Client, req models.JiraRequest)
 		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing fields", req.RequestID)
 	}
 
-	err := client.UpdateIssue(issueKey, fields)
+	// The fields' current values are what undo_last_action restores
+	previous, err := client.GetIssue(issueKey, nil)
 	if err != nil {
 		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
 	}
+	fieldIDs := make([]string, 0, len(fields))
+	for id := range fields {
+		fieldIDs = append(fieldIDs, id)
+	}
 
-	return models.SuccessResponse(map[string]string{"status": "updated"}, req.RequestID)
+	err = client.UpdateIssue(issueKey, fields)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	response := models.SuccessResponse(map[string]string{"status": "updated"}, req.RequestID)
+	if undo := undoFieldUpdate(issueKey, previous, fieldIDs); undo != nil {
+		response["undo"] = undo
+	}
+	return response
 }
 
 func (s *Service) handleAddComment(client *api.Client, req models.JiraRequest) map[string]interface{} {
@@ -251,13 +488,13 @@ func (s *Service) handleTransitionIssue(client *api.Client, req models.JiraReque
 	return models.SuccessResponse(map[string]string{"status": "transitioned"}, req.RequestID)
 }
 
-func (s *Service) handleListProjects(client *api.Client, req models.JiraRequest) map[string]interface{} {
+func (s *Service) handleListProjects(client *api.Client, req models.JiraRequest, policy *models.WorkspacePolicy) map[string]interface{} {
 	projects, err := client.ListProjects()
 	if err != nil {
 		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
 	}
 
-	return models.SuccessResponse(projects, req.RequestID)
+	return models.SuccessResponse(filterProjects(policy, projects), req.RequestID)
 }
 
 func (s *Service) handleGetAgileBoards(client *api.Client, req models.JiraRequest) map[string]interface{} {
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	// maxHydrateIssues bounds how many issues one jira_hydrate_issues call fetches
+	maxHydrateIssues = 50
+	// hydrateConcurrency is how many issues are fetched at once
+	hydrateConcurrency = 5
+)
+
+// hydrateError reports an issue that could not be fetched
+type hydrateError struct {
+	IssueKey string `json:"issue_key"`
+	Error    string `json:"error"`
+}
+
+// handleHydrateIssues fetches several issues by key, a few at a time, and returns them in
+// the order they were asked for. An issue that cannot be fetched is reported in errors
+// rather than failing the whole request.
+func (s *Service) handleHydrateIssues(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	keys, err := hydrateIssueKeys(req.Params)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	}
+
+	var expand []string
+	if e, ok := req.Params["expand"].([]interface{}); ok {
+		for _, v := range e {
+			if field, ok := v.(string); ok {
+				expand = append(expand, field)
+			}
+		}
+	}
+
+	issues := make([]*models.JiraIssue, len(keys))
+	failures := make([]error, len(keys))
+	sem := make(chan struct{}, hydrateConcurrency)
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			issues[i], failures[i] = client.GetIssue(key, expand)
+		}()
+	}
+	wg.Wait()
+
+	found := make([]*models.JiraIssue, 0, len(keys))
+	failed := []hydrateError{}
+	for i, key := range keys {
+		if failures[i] != nil {
+			failed = append(failed, hydrateError{IssueKey: key, Error: failures[i].Error()})
+			continue
+		}
+		found = append(found, issues[i])
+	}
+
+	// Every issue failing is most likely a problem with the workspace, not the keys
+	if len(found) == 0 {
+		return models.ErrorResponse(models.ErrCodeAPIError, failed[0].Error, req.RequestID)
+	}
+	return models.SuccessResponse(map[string]interface{}{
+		"issues": found,
+		"errors": failed,
+	}, req.RequestID)
+}
+
+// hydrateIssueKeys reads the issue_keys argument, dropping blanks and repeats
+func hydrateIssueKeys(params map[string]interface{}) ([]string, error) {
+	raw, _ := params["issue_keys"].([]interface{})
+	seen := make(map[string]bool, len(raw))
+	keys := make([]string, 0, len(raw))
+	for _, v := range raw {
+		key, _ := v.(string)
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("missing issue_keys")
+	}
+	if len(keys) > maxHydrateIssues {
+		return nil, fmt.Errorf("at most %d issue_keys can be fetched at once, got %d", maxHydrateIssues, len(keys))
+	}
+	return keys, nil
+}
@@ -0,0 +1,489 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	// maxImportRows is the most rows one import creates issues from. Larger imports are
+	// refused rather than partly applied.
+	maxImportRows = 200
+	// importBatchSize is how many issues each bulk create sends, Jira's limit
+	importBatchSize = 50
+	// defaultImportIssueType is the type of issues whose row does not give one
+	defaultImportIssueType = "Task"
+)
+
+// importFieldAliases are other names rows commonly give Jira's fields, normalized
+var importFieldAliases = map[string]string{
+	"type":       "issuetype",
+	"component":  "components",
+	"label":      "labels",
+	"fixversion": "fixversions",
+	"version":    "fixversions",
+	"due":        "duedate",
+	"title":      "summary",
+}
+
+// importRowResult is what became of one row of an import
+type importRowResult struct {
+	Row     int                    `json:"row"`    // Position among the data rows, from 1
+	Status  string                 `json:"status"` // created, failed, or would_create in a dry run
+	Key     string                 `json:"key,omitempty"`
+	URL     string                 `json:"url,omitempty"`
+	Summary string                 `json:"summary,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"` // What a dry run would send
+	Error   string                 `json:"error,omitempty"`
+}
+
+// handleImportIssues creates issues in a project from the rows of a CSV or JSON payload.
+// Each column is mapped to a Jira field by the mapping param, or else by its name, and
+// its values are converted to what the field takes; unmapped columns are ignored. Issues
+// are created in bulk, in row order, and each row is reported as created or failed. A
+// dry run reports the fields each row would be created with.
+func (s *Service) handleImportIssues(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	projectKey, _ := req.Params["project_key"].(string)
+	if projectKey == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing project_key", req.RequestID)
+	}
+	format, _ := req.Params["format"].(string)
+	rows, columns, err := importRows(format, req.Params["data"])
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	}
+	if len(rows) == 0 {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "data has no rows", req.RequestID)
+	}
+	if len(rows) > maxImportRows {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("data has %d rows; import at most %d at a time", len(rows), maxImportRows), req.RequestID)
+	}
+	issueType, _ := req.Params["issue_type"].(string)
+	if issueType = strings.TrimSpace(issueType); issueType == "" {
+		issueType = defaultImportIssueType
+	}
+	mapping, _ := req.Params["mapping"].(map[string]interface{})
+
+	fields, err := client.SearchFields()
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	columnFields, ignored, err := importColumns(columns, mapping, fields)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, err.Error(), req.RequestID)
+	}
+	if !mapsToField(columnFields, "summary") {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("no column maps to summary; columns are %s, and mapping can name the one that holds it", strings.Join(columns, ", ")),
+			req.RequestID)
+	}
+
+	converter := &importConverter{client: client, accounts: make(map[string]string)}
+	results := make([]importRowResult, len(rows))
+	var batch []map[string]interface{}
+	var batchRows []int
+	for i, row := range rows {
+		results[i] = importRowResult{Row: i + 1}
+		issue, err := converter.issueFields(row, columnFields, projectKey, issueType)
+		summary, _ := issue["summary"].(string)
+		results[i].Summary = summary
+		if err == nil && strings.TrimSpace(summary) == "" {
+			err = fmt.Errorf("summary is empty")
+		}
+		if err != nil {
+			results[i].Status, results[i].Error = "failed", err.Error()
+			continue
+		}
+		batch, batchRows = append(batch, issue), append(batchRows, i)
+	}
+
+	dryRun := atlassian.IsDryRun(req.Params)
+	for start := 0; start < len(batch); start += importBatchSize {
+		end := min(start+importBatchSize, len(batch))
+		if dryRun {
+			for j := start; j < end; j++ {
+				results[batchRows[j]].Status, results[batchRows[j]].Fields = "would_create", batch[j]
+			}
+			continue
+		}
+		created, err := client.BulkCreateIssues(batch[start:end])
+		for j := start; j < end; j++ {
+			result := &results[batchRows[j]]
+			switch {
+			case err != nil:
+				result.Status, result.Error = "failed", err.Error()
+			case created[j-start].Issue == nil:
+				result.Status, result.Error = "failed", created[j-start].Error
+			default:
+				issue := created[j-start].Issue
+				result.Status, result.Key, result.URL = "created", issue.Key, issueURL(*issue)
+			}
+		}
+	}
+
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+	response := map[string]interface{}{
+		"project_key":     projectKey,
+		"rows":            len(rows),
+		"failed":          counts["failed"],
+		"mapping":         columnFields,
+		"ignored_columns": ignored,
+		"results":         results,
+	}
+	if dryRun {
+		response["dry_run"] = true
+		response["would_create"] = counts["would_create"]
+		response["message"] = fmt.Sprintf("Nothing was changed. Without dry_run %d issue(s) would be created.", counts["would_create"])
+	} else {
+		response["created"] = counts["created"]
+	}
+	return models.SuccessResponse(response, req.RequestID)
+}
+
+// importRows reads the rows of a CSV or JSON payload, with the columns they use in the
+// order they first appear. JSON data is an array of objects, given as text or as the
+// array itself; CSV data has a header row. Without a format, data is read as JSON when
+// it looks like it.
+func importRows(format string, data interface{}) ([]map[string]interface{}, []string, error) {
+	text, isText := data.(string)
+	if format == "" {
+		format = "csv"
+		if !isText || strings.HasPrefix(strings.TrimSpace(text), "[") {
+			format = "json"
+		}
+	}
+
+	var rows []map[string]interface{}
+	var columns []string
+	seen := make(map[string]bool)
+	switch format {
+	case "json":
+		items, ok := data.([]interface{})
+		if isText {
+			if err := json.Unmarshal([]byte(text), &items); err != nil {
+				return nil, nil, fmt.Errorf("data is not a JSON array of objects: %v", err)
+			}
+		} else if !ok {
+			return nil, nil, fmt.Errorf("data must be a JSON array of objects, or CSV text")
+		}
+		for i, item := range items {
+			row, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf("row %d is not a JSON object", i+1)
+			}
+			keys := make([]string, 0, len(row))
+			for key := range row {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if !seen[key] {
+					seen[key] = true
+					columns = append(columns, key)
+				}
+			}
+			rows = append(rows, row)
+		}
+	case "csv":
+		if !isText {
+			return nil, nil, fmt.Errorf("CSV data must be text")
+		}
+		reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(text, "\ufeff")))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, nil, fmt.Errorf("data is not valid CSV: %v", err)
+		}
+		if len(records) == 0 {
+			return nil, nil, nil
+		}
+		for _, header := range records[0] {
+			header = strings.TrimSpace(header)
+			if seen[header] {
+				return nil, nil, fmt.Errorf("the CSV header has column %q twice", header)
+			}
+			seen[header] = true
+			columns = append(columns, header)
+		}
+		for _, record := range records[1:] {
+			row := make(map[string]interface{})
+			blank := true
+			for i, value := range record {
+				if i < len(columns) && columns[i] != "" {
+					row[columns[i]] = value
+					blank = blank && strings.TrimSpace(value) == ""
+				}
+			}
+			if !blank {
+				rows = append(rows, row)
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("format must be csv or json")
+	}
+	return rows, columns, nil
+}
+
+// importField is a Jira field a column is imported into
+type importField struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	schema map[string]interface{}
+}
+
+// importColumns maps columns to the fields they are imported into: the field mapping names,
+// matched by ID or name regardless of case, spaces and underscores, or else the field the
+// column is named after. mapping may name "" to ignore a column. Columns without a field
+// are returned as ignored; a mapping to an unknown field is an error.
+func importColumns(columns []string, mapping map[string]interface{}, fields []map[string]interface{}) (map[string]importField, []string, error) {
+	byName := make(map[string]importField)
+	for _, field := range fields {
+		id, _ := field["id"].(string)
+		name, _ := field["name"].(string)
+		schema, _ := field["schema"].(map[string]interface{})
+		f := importField{ID: id, Name: name, schema: schema}
+		// Field IDs win over other fields' names, then the first field with a name
+		byName[importFieldKey(id)] = f
+		if _, taken := byName[importFieldKey(name)]; !taken {
+			byName[importFieldKey(name)] = f
+		}
+	}
+	lookup := func(name string) (importField, bool) {
+		key := importFieldKey(name)
+		if alias, ok := importFieldAliases[key]; ok {
+			key = alias
+		}
+		f, ok := byName[key]
+		return f, ok
+	}
+
+	for column := range mapping {
+		if !slices.Contains(columns, column) {
+			return nil, nil, fmt.Errorf("mapping names column %q, which the data does not have", column)
+		}
+	}
+
+	mapped := make(map[string]importField)
+	ignored := []string{}
+	for _, column := range columns {
+		target, explicit := mapping[column].(string)
+		if !explicit {
+			target = column
+		}
+		if strings.TrimSpace(target) == "" {
+			ignored = append(ignored, column)
+			continue
+		}
+		f, ok := lookup(target)
+		if !ok || f.ID == "project" {
+			if explicit {
+				return nil, nil, fmt.Errorf("column %q is mapped to %q, which is not a Jira field (or is the project, which project_key sets)", column, target)
+			}
+			ignored = append(ignored, column)
+			continue
+		}
+		mapped[column] = f
+	}
+	return mapped, ignored, nil
+}
+
+// importFieldKey is a field or column name compared regardless of case, spaces, dashes
+// and underscores
+func importFieldKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, strings.TrimSpace(name))
+}
+
+// mapsToField reports whether a column is imported into the field with an ID
+func mapsToField(columns map[string]importField, id string) bool {
+	for _, f := range columns {
+		if f.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// importConverter turns rows into the fields of the issues they create, remembering the
+// people it has looked up
+type importConverter struct {
+	client   *api.Client
+	accounts map[string]string // Account IDs by the email or name a row gave
+}
+
+// issueFields converts a row to the fields of an issue, reporting every value it cannot convert
+func (c *importConverter) issueFields(row map[string]interface{}, columns map[string]importField, projectKey, issueType string) (map[string]interface{}, error) {
+	fields := map[string]interface{}{
+		"project":   map[string]interface{}{"key": projectKey},
+		"issuetype": map[string]interface{}{"name": issueType},
+	}
+	names := make([]string, 0, len(columns))
+	for column := range columns {
+		names = append(names, column)
+	}
+	sort.Strings(names)
+	var problems []string
+	for _, column := range names {
+		f := columns[column]
+		value, err := c.value(f, row[column])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", column, err))
+			continue
+		}
+		if value != nil {
+			fields[f.ID] = value
+		}
+	}
+	if len(problems) > 0 {
+		return fields, errors.New(strings.Join(problems, "; "))
+	}
+	return fields, nil
+}
+
+// value converts a row's value to what a field takes, going by the field's schema. Lists
+// may be given as JSON arrays or as text separated by commas or semicolons. Empty values
+// are left out, and JSON objects are passed on as they are.
+func (c *importConverter) value(f importField, raw interface{}) (interface{}, error) {
+	switch raw.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		// JSON rows may give a field's value as Jira takes it, e.g. {"accountId": "..."}
+		return raw, nil
+	}
+	text := strings.TrimSpace(fmt.Sprint(raw))
+	if _, isList := raw.([]interface{}); !isList && text == "" {
+		return nil, nil
+	}
+	fieldType, _ := f.schema["type"].(string)
+	system, _ := f.schema["system"].(string)
+	custom, _ := f.schema["custom"].(string)
+
+	if fieldType == "array" {
+		items, _ := f.schema["items"].(string)
+		var values []string
+		if list, ok := raw.([]interface{}); ok {
+			for _, item := range list {
+				values = append(values, strings.TrimSpace(fmt.Sprint(item)))
+			}
+		} else {
+			values = strings.FieldsFunc(text, func(r rune) bool {
+				// Labels cannot contain spaces, so they may also be separated by them
+				return r == ',' || r == ';' || (system == "labels" && unicode.IsSpace(r))
+			})
+		}
+		converted := []interface{}{}
+		for _, value := range values {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			item, err := c.scalar(items, "", "", value)
+			if err != nil {
+				return nil, err
+			}
+			converted = append(converted, item)
+		}
+		if len(converted) == 0 {
+			return nil, nil
+		}
+		return converted, nil
+	}
+	if number, ok := raw.(float64); ok && fieldType == "number" {
+		return number, nil
+	}
+	return c.scalar(fieldType, system, custom, text)
+}
+
+// scalar converts one value to what a field of a schema type takes
+func (c *importConverter) scalar(fieldType, system, custom, value string) (interface{}, error) {
+	switch {
+	case system == "description" || system == "environment" || strings.HasSuffix(custom, ":textarea"):
+		nodes, _ := descriptionContent(map[string]interface{}{"content": value})
+		return map[string]interface{}{"type": "doc", "version": 1, "content": nodes}, nil
+	case fieldType == "number":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return number, nil
+	case fieldType == "user":
+		accountID, err := c.accountID(value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"accountId": accountID}, nil
+	case fieldType == "option":
+		return map[string]interface{}{"value": value}, nil
+	case fieldType == "issuelink" || system == "parent":
+		return map[string]interface{}{"key": value}, nil
+	case fieldType == "priority" || fieldType == "issuetype" || fieldType == "component" || fieldType == "version" || fieldType == "resolution":
+		return map[string]interface{}{"name": value}, nil
+	}
+	return value, nil
+}
+
+// accountID finds the account of a person a row names by email or name. Only a user whose
+// email, or else display name, is the only exact match is taken, so that a row never goes
+// to whichever of several namesakes Jira lists first. A value matching no one is taken to
+// be an account ID already.
+func (c *importConverter) accountID(value string) (string, error) {
+	if id, ok := c.accounts[value]; ok {
+		return id, nil
+	}
+	users, err := c.client.SearchUsers(value)
+	if err != nil {
+		return "", err
+	}
+	var byID, byEmail, byName []models.User
+	for _, user := range users {
+		switch {
+		case user.AccountID == value:
+			byID = append(byID, user)
+		case user.Email != "" && strings.EqualFold(user.Email, value):
+			byEmail = append(byEmail, user)
+		case strings.EqualFold(user.DisplayName, value):
+			byName = append(byName, user)
+		}
+	}
+	id := ""
+	switch {
+	case len(byID) > 0:
+		id = value
+	case len(byEmail) == 1:
+		id = byEmail[0].AccountID
+	case len(byEmail) > 1:
+		return "", fmt.Errorf("ambiguous user %q (%d matches); use their account ID", value, len(byEmail))
+	case len(byName) == 1:
+		id = byName[0].AccountID
+	case len(byName) > 1:
+		return "", fmt.Errorf("ambiguous user %q (%d matches); use their email or account ID", value, len(byName))
+	case len(users) > 0:
+		return "", fmt.Errorf("ambiguous user %q (%d matches, none exact); use their email or account ID", value, len(users))
+	case !strings.ContainsAny(value, "@ "):
+		id = value
+	default:
+		return "", fmt.Errorf("no user matches %q", value)
+	}
+	c.accounts[value] = id
+	return id, nil
+}
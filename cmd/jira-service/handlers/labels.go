@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	// maxTaxonomyIssues bounds how many labelled issues of a project are read for their labels
+	maxTaxonomyIssues = 2000
+	// taxonomyPageSize is how many issues each search returns
+	taxonomyPageSize = 100
+)
+
+// labelUsage is a label and how many issues have it
+type labelUsage struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// handleGetLabelTaxonomy lists a project's components, and the labels used in it with how
+// many issues have each. Jira only keeps labels on issues, so they are collected from the
+// project's labelled issues, most recently updated first.
+func (s *Service) handleGetLabelTaxonomy(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	projectKey, _ := req.Params["project_key"].(string)
+	if projectKey == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing project_key", req.RequestID)
+	}
+
+	components, err := client.GetProjectComponents(projectKey)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	jql := fmt.Sprintf(`project = "%s" AND labels IS NOT EMPTY ORDER BY updated DESC`, strings.ReplaceAll(projectKey, `"`, ""))
+	counts := make(map[string]int)
+	scanned := 0
+	truncated := false
+	pageToken := ""
+	for {
+		results, err := client.SearchIssues(jql, []string{"labels"}, taxonomyPageSize, pageToken)
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		for _, issue := range results.Issues {
+			labels, _ := issue.Fields["labels"].([]interface{})
+			for _, label := range labels {
+				if name, ok := label.(string); ok {
+					counts[name]++
+				}
+			}
+		}
+		scanned += len(results.Issues)
+		pageToken = results.NextPageToken
+		if pageToken == "" || len(results.Issues) == 0 {
+			break
+		}
+		if scanned >= maxTaxonomyIssues {
+			truncated = true
+			break
+		}
+	}
+
+	labels := make([]labelUsage, 0, len(counts))
+	for name, count := range counts {
+		labels = append(labels, labelUsage{Name: name, Count: count})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	sort.Slice(components, func(i, j int) bool { return components[i].Name < components[j].Name })
+
+	return models.SuccessResponse(map[string]interface{}{
+		"project_key":    projectKey,
+		"labels":         labels,
+		"components":     components,
+		"issues_scanned": scanned,
+		"truncated":      truncated,
+	}, req.RequestID)
+}
+
+// handleCreateComponent creates a component in a project
+func (s *Service) handleCreateComponent(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	projectKey, _ := req.Params["project_key"].(string)
+	name, _ := req.Params["name"].(string)
+	if projectKey == "" || strings.TrimSpace(name) == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "project_key and name are required", req.RequestID)
+	}
+	description, _ := req.Params["description"].(string)
+
+	component, err := client.CreateComponent(projectKey, models.Component{Name: strings.TrimSpace(name), Description: description})
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	return models.SuccessResponse(component, req.RequestID)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// maxAlertsPageLen is the most alerts the alert API returns per page
+const maxAlertsPageLen = 100
+
+func (s *Service) handleOpsgenieListAlerts(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	query := "status:open"
+	if q, ok := req.Params["query"].(string); ok && q != "" {
+		query = q
+	}
+
+	limit := 25
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	if limit > maxAlertsPageLen {
+		limit = maxAlertsPageLen
+	}
+
+	alerts, err := client.ListAlerts(query, limit)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	return models.SuccessResponse(alerts, req.RequestID)
+}
+
+// handleOpsgenieAlertAction acknowledges or closes an alert
+func (s *Service) handleOpsgenieAlertAction(client *api.Client, req models.JiraRequest, action func(alertID, note string) (map[string]interface{}, error)) map[string]interface{} {
+	alertID, ok := req.Params["alert_id"].(string)
+	if !ok || alertID == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing alert_id", req.RequestID)
+	}
+	note, _ := req.Params["note"].(string)
+
+	result, err := action(alertID, note)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	return models.SuccessResponse(result, req.RequestID)
+}
+
+// handleOpsgenieCreateAlert creates an alert. With an issue_key the alert is linked to
+// that Jira issue: the key and URL go into its details and tags, and the issue summary
+// becomes the message when none is given.
+func (s *Service) handleOpsgenieCreateAlert(client *api.Client, req models.JiraRequest, site string) map[string]interface{} {
+	message, _ := req.Params["message"].(string)
+	issueKey, _ := req.Params["issue_key"].(string)
+	if message == "" && issueKey == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing message or issue_key", req.RequestID)
+	}
+
+	alert := map[string]interface{}{"source": "Trilix"}
+	for _, param := range []string{"description", "priority", "alias"} {
+		if value, ok := req.Params[param].(string); ok && value != "" {
+			alert[param] = value
+		}
+	}
+	var tags []string
+	if t, ok := req.Params["tags"].([]interface{}); ok {
+		for _, v := range t {
+			if tag, ok := v.(string); ok {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	if issueKey != "" {
+		issue, err := client.GetIssue(issueKey, nil)
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		if message == "" {
+			summary, _ := issue.Fields["summary"].(string)
+			message = fmt.Sprintf("%s: %s", issue.Key, summary)
+		}
+		issueURL := fmt.Sprintf("%s/browse/%s", strings.TrimRight(site, "/"), issue.Key)
+		alert["entity"] = issue.Key
+		alert["details"] = map[string]string{"jira_issue": issue.Key, "jira_url": issueURL}
+		tags = append(tags, issue.Key)
+		if _, ok := alert["description"]; !ok {
+			alert["description"] = "Jira issue: " + issueURL
+		}
+	}
+
+	// The API limits messages to 130 characters
+	if runes := []rune(message); len(runes) > 130 {
+		message = string(runes[:127]) + "..."
+	}
+	alert["message"] = message
+	if len(tags) > 0 {
+		alert["tags"] = tags
+	}
+
+	result, err := client.CreateAlert(alert)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+
+	return models.SuccessResponse(result, req.RequestID)
+}
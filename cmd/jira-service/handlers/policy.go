@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// projectUnscopedActions cannot be tied to a project key, so they are rejected
+// when the workspace restricts which projects may be accessed
+var projectUnscopedActions = map[string]bool{
+	"get_board_issues":       true,
+	"get_sprints_from_board": true,
+	"get_sprint_issues":      true,
+	"create_sprint":          true,
+	"update_sprint":          true,
+	"sprint_burndown":        true,
+	"board_snapshot":         true,
+	"remove_issue_link":      true,
+}
+
+// enforcePolicy checks a request against the workspace policy. It returns an error
+// response when the request is not allowed, and rewrites the JQL of searches so that
+// they only match allowlisted projects.
+func (s *Service) enforcePolicy(policy *models.WorkspacePolicy, req *models.JiraRequest) map[string]interface{} {
+	if policy == nil {
+		return nil
+	}
+
+	toolName := "jira_" + req.Action
+	bitbucket := strings.HasPrefix(req.Action, "bitbucket_")
+	if bitbucket || strings.HasPrefix(req.Action, "opsgenie_") || directoryActions[req.Action] {
+		// Bitbucket, Opsgenie and directory actions are named after their tools
+		toolName = req.Action
+	}
+	if !policy.AllowsTool(toolName) {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("tool %s is not allowed for this workspace", toolName), req.RequestID)
+	}
+
+	if policy.ReadOnly && models.JiraMutatingActions[req.Action] {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("workspace is read-only; %s is not allowed", toolName), req.RequestID)
+	}
+
+	// Atlassian OAuth tokens are not accepted by Bitbucket
+	if bitbucket && policy.UsesUserOAuth() {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("%s is not available in workspaces that call Atlassian as each user", toolName), req.RequestID)
+	}
+
+	// The project allowlist names Jira projects; it does not apply to repositories or
+	// the user directory
+	if !policy.RestrictsProjects() || bitbucket || directoryActions[req.Action] {
+		return nil
+	}
+
+	// tools.yaml tools can call any endpoint, so they cannot be limited to projects either
+	if _, custom := s.customTools[req.Action]; projectUnscopedActions[req.Action] || custom {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("%s is not available for project-restricted workspaces", toolName), req.RequestID)
+	}
+
+	if req.Action == "get_agile_boards" {
+		if key, _ := req.Params["project_key"].(string); key == "" {
+			return models.ErrorResponse(models.ErrCodeForbidden,
+				"project_key is required for project-restricted workspaces", req.RequestID)
+		}
+	}
+
+	if key, ok := req.Params["project_key"].(string); ok && key != "" && !policy.AllowsProject(key) {
+		return models.ErrorResponse(models.ErrCodeForbidden,
+			fmt.Sprintf("project %s is not allowed for this workspace", key), req.RequestID)
+	}
+
+	for _, param := range []string{"issue_key", "inward_key", "outward_key"} {
+		issueKey, ok := req.Params[param].(string)
+		if !ok || issueKey == "" {
+			continue
+		}
+		if !policy.AllowsProject(projectKeyFromIssueKey(issueKey)) {
+			return models.ErrorResponse(models.ErrCodeForbidden,
+				fmt.Sprintf("issue %s is not in an allowed project", issueKey), req.RequestID)
+		}
+	}
+
+	if keys, ok := req.Params["issue_keys"].([]interface{}); ok {
+		for _, v := range keys {
+			if issueKey, _ := v.(string); issueKey != "" && !policy.AllowsProject(projectKeyFromIssueKey(issueKey)) {
+				return models.ErrorResponse(models.ErrCodeForbidden,
+					fmt.Sprintf("issue %s is not in an allowed project", issueKey), req.RequestID)
+			}
+		}
+	}
+
+	if jql, ok := req.Params["jql"].(string); ok {
+		req.Params["jql"] = atlassian.ScopeQuery(jql, "project", policy.JQLProjectAllowlist)
+	}
+
+	return nil
+}
+
+// filterProjects drops projects that the workspace policy does not allow
+func filterProjects(policy *models.WorkspacePolicy, projects []models.ProjectRef) []models.ProjectRef {
+	if !policy.RestrictsProjects() {
+		return projects
+	}
+	filtered := []models.ProjectRef{}
+	for _, p := range projects {
+		if policy.AllowsProject(p.Key) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// projectKeyFromIssueKey returns the project part of an issue key (e.g., "PROJ" for "PROJ-123")
+func projectKeyFromIssueKey(issueKey string) string {
+	if idx := strings.LastIndex(issueKey, "-"); idx > 0 {
+		return issueKey[:idx]
+	}
+	return issueKey
+}
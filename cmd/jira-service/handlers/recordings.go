@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// handleRecordings returns the Atlassian calls this service recorded while DEBUG_RECORD
+// is on, for the MCP server's /api/admin/recordings
+func (s *Service) handleRecordings(req models.JiraRequest) map[string]interface{} {
+	limit := 0
+	if l, ok := req.Params["limit"].(float64); ok {
+		limit = int(l)
+	}
+	calls := atlassian.Recordings(limit)
+	return models.SuccessResponse(map[string]interface{}{
+		"calls": calls,
+		"count": len(calls),
+	}, req.RequestID)
+}
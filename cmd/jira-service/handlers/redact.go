@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// fieldNamesTTL is how long a site's custom field names are kept for redaction
+const fieldNamesTTL = 10 * time.Minute
+
+// redact applies the redaction policy to a successful response. Custom fields are matched
+// by name as well as ID, which costs one lookup of the site's fields every fieldNamesTTL.
+func (s *Service) redact(client *api.Client, site string, response map[string]interface{}, requestID string) map[string]interface{} {
+	if succeeded, _ := response["success"].(bool); !succeeded || s.redaction == nil {
+		return response
+	}
+
+	var names map[string]string
+	fieldName := func(id string) (string, error) {
+		if names == nil {
+			var err error
+			if names, err = s.customFieldNames(client, site); err != nil {
+				return "", err
+			}
+		}
+		return names[id], nil
+	}
+	data, err := atlassian.ApplyRedaction(s.redaction, response["data"], fieldName)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeInternal, fmt.Sprintf("failed to redact the result: %v", err), requestID)
+	}
+	response["data"] = data
+	return response
+}
+
+// customFieldNames returns the names of a site's fields by ID
+func (s *Service) customFieldNames(client *api.Client, site string) (map[string]string, error) {
+	cacheKey := "fieldnames:" + site
+	var cached map[string]string
+	if s.cache.Get(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	fields, err := client.SearchFields()
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up custom field names: %v", err)
+	}
+	names := make(map[string]string, len(fields))
+	for _, field := range fields {
+		id, _ := field["id"].(string)
+		name, _ := field["name"].(string)
+		names[id] = name
+	}
+	s.cache.Set(cacheKey, names, fieldNamesTTL)
+	return names, nil
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	// defaultRenderComments and maxRenderComments bound how many of the latest comments a
+	// rendering includes
+	defaultRenderComments = 5
+	maxRenderComments     = 50
+)
+
+// renderFields are the issue fields a rendering lists, in order, with their labels
+var renderFields = []struct{ id, label string }{
+	{"issuetype", "Type"},
+	{"status", "Status"},
+	{"priority", "Priority"},
+	{"resolution", "Resolution"},
+	{"assignee", "Assignee"},
+	{"reporter", "Reporter"},
+	{"parent", "Parent"},
+	{"labels", "Labels"},
+	{"components", "Components"},
+	{"fixVersions", "Fix versions"},
+	{"duedate", "Due"},
+	{"created", "Created"},
+	{"updated", "Updated"},
+}
+
+// renderedIssue is what a rendering shows of an issue
+type renderedIssue struct {
+	Key          string
+	Summary      string
+	URL          string
+	Fields       [][2]string // Label and value of the fields that are set
+	Description  interface{} // Atlassian Document Format, or a plain string
+	Links        []string    // e.g. "blocks PROJ-2: Fix login (In Progress)"
+	Comments     []renderedComment
+	CommentTotal int
+}
+
+// renderedComment is a comment in a rendering
+type renderedComment struct {
+	Author  string
+	Created string
+	Body    interface{}
+}
+
+// handleRenderIssue composes an issue's fields, description, links and latest comments into
+// one Markdown or HTML document, for a full brief on an issue in one call. The workspace's
+// redaction policy is applied before rendering.
+func (s *Service) handleRenderIssue(client *api.Client, req models.JiraRequest, site string) map[string]interface{} {
+	issueKey, _ := req.Params["issue_key"].(string)
+	issueKey = strings.TrimSpace(issueKey)
+	if issueKey == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing issue_key", req.RequestID)
+	}
+	format, _ := req.Params["format"].(string)
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "markdown" && format != "html" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("invalid format %q; use markdown or html", format), req.RequestID)
+	}
+	limit := defaultRenderComments
+	if l, ok := req.Params["comments"].(float64); ok && l >= 0 {
+		limit = min(int(l), maxRenderComments)
+	}
+
+	issue, err := client.GetIssue(issueKey, nil)
+	if err != nil {
+		return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+	}
+	comments := []models.IssueComment{}
+	if limit > 0 {
+		if comments, err = client.GetIssueComments(issue.Key, limit); err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+	}
+
+	// Redact before rendering, as the document hides the keys the policy matches
+	redacted := s.redact(client, site, models.SuccessResponse(map[string]interface{}{
+		"issue":    issue,
+		"comments": comments,
+	}, req.RequestID), req.RequestID)
+	if succeeded, _ := redacted["success"].(bool); !succeeded {
+		return redacted
+	}
+	var data struct {
+		Issue    models.JiraIssue         `json:"issue"`
+		Comments []map[string]interface{} `json:"comments"`
+	}
+	raw, _ := json.Marshal(redacted["data"])
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return models.ErrorResponse(models.ErrCodeInternal, fmt.Sprintf("failed to read the redacted issue: %v", err), req.RequestID)
+	}
+
+	rendered := renderedIssueOf(data.Issue, data.Comments)
+	document := renderIssueMarkdown(rendered)
+	if format == "html" {
+		document = renderIssueHTML(rendered)
+	}
+	return models.SuccessResponse(map[string]interface{}{
+		"issue_key":         rendered.Key,
+		"format":            format,
+		"url":               rendered.URL,
+		"comments_included": len(rendered.Comments),
+		"comments_total":    rendered.CommentTotal,
+		"document":          document,
+	}, req.RequestID)
+}
+
+// renderedIssueOf collects what a rendering shows of an issue and its comments, given
+// newest first
+func renderedIssueOf(issue models.JiraIssue, comments []map[string]interface{}) renderedIssue {
+	rendered := renderedIssue{
+		Key:         issue.Key,
+		URL:         issueURL(issue),
+		Description: issue.Fields["description"],
+	}
+	rendered.Summary, _ = issue.Fields["summary"].(string)
+
+	for _, field := range renderFields {
+		value := renderValue(issue.Fields[field.id])
+		if field.id == "created" || field.id == "updated" {
+			value = summaryTime(value)
+		}
+		if value != "" {
+			rendered.Fields = append(rendered.Fields, [2]string{field.label, value})
+		}
+	}
+
+	links, _ := issue.Fields["issuelinks"].([]interface{})
+	for _, l := range links {
+		link, _ := l.(map[string]interface{})
+		linkType, _ := link["type"].(map[string]interface{})
+		if other, ok := link["outwardIssue"]; ok {
+			relation, _ := linkType["outward"].(string)
+			rendered.Links = append(rendered.Links, relation+" "+renderIssueRef(other))
+		} else if other, ok := link["inwardIssue"]; ok {
+			relation, _ := linkType["inward"].(string)
+			rendered.Links = append(rendered.Links, relation+" "+renderIssueRef(other))
+		}
+	}
+	subtasks, _ := issue.Fields["subtasks"].([]interface{})
+	for _, subtask := range subtasks {
+		rendered.Links = append(rendered.Links, "has subtask "+renderIssueRef(subtask))
+	}
+
+	rendered.CommentTotal = len(comments)
+	if all, ok := issue.Fields["comment"].(map[string]interface{}); ok {
+		if total, ok := all["total"].(float64); ok {
+			rendered.CommentTotal = max(int(total), len(comments))
+		}
+	}
+	// Oldest first, so the comments read as a conversation
+	for i := len(comments) - 1; i >= 0; i-- {
+		comment := comments[i]
+		author := renderValue(comment["author"])
+		if author == "" {
+			author = "Unknown"
+		}
+		created, _ := comment["created"].(string)
+		rendered.Comments = append(rendered.Comments, renderedComment{
+			Author:  author,
+			Created: summaryTime(created),
+			Body:    comment["body"],
+		})
+	}
+	return rendered
+}
+
+// renderValue is the display text of a field value: a user's or option's name, a list of
+// them, or a plain value
+func renderValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := renderValue(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		if _, ok := v["key"].(string); ok {
+			if _, ok := v["fields"]; ok {
+				return renderIssueRef(v)
+			}
+		}
+		for _, name := range []string{"displayName", "name", "value", "key"} {
+			if text, ok := v[name].(string); ok && text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// renderIssueRef describes a linked issue as "KEY: summary (status)"
+func renderIssueRef(value interface{}) string {
+	issue, _ := value.(map[string]interface{})
+	key, _ := issue["key"].(string)
+	fields, _ := issue["fields"].(map[string]interface{})
+	ref := key
+	if summary, _ := fields["summary"].(string); summary != "" {
+		ref += ": " + summaryText(summary)
+	}
+	if status := renderValue(fields["status"]); status != "" {
+		ref += " (" + status + ")"
+	}
+	return ref
+}
+
+// renderIssueMarkdown renders an issue as a Markdown document
+func renderIssueMarkdown(issue renderedIssue) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: %s\n\n", issue.Key, summaryText(issue.Summary))
+	if issue.URL != "" {
+		fmt.Fprintf(&b, "<%s>\n\n", issue.URL)
+	}
+	for _, field := range issue.Fields {
+		fmt.Fprintf(&b, "- **%s:** %s\n", field[0], field[1])
+	}
+
+	b.WriteString("\n## Description\n\n")
+	if description := adfToMarkdown(issue.Description); description != "" {
+		b.WriteString(description + "\n")
+	} else {
+		b.WriteString("_No description._\n")
+	}
+
+	if len(issue.Links) > 0 {
+		b.WriteString("\n## Links\n\n")
+		for _, link := range issue.Links {
+			b.WriteString("- " + link + "\n")
+		}
+	}
+
+	if issue.CommentTotal > 0 {
+		fmt.Fprintf(&b, "\n## Comments (%s)\n", renderCommentCount(issue))
+		for _, comment := range issue.Comments {
+			fmt.Fprintf(&b, "\n### %s, %s\n\n%s\n", comment.Author, comment.Created, adfToMarkdown(comment.Body))
+		}
+	}
+	return b.String()
+}
+
+// renderIssueHTML renders an issue as a standalone HTML document
+func renderIssueHTML(issue renderedIssue) string {
+	var b strings.Builder
+	title := html.EscapeString(issue.Key + ": " + summaryText(issue.Summary))
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n", title)
+	if issue.URL != "" {
+		fmt.Fprintf(&b, "<h1><a href=\"%s\">%s</a>: %s</h1>\n", htmlHref(issue.URL), html.EscapeString(issue.Key), html.EscapeString(summaryText(issue.Summary)))
+	} else {
+		fmt.Fprintf(&b, "<h1>%s</h1>\n", title)
+	}
+	if len(issue.Fields) > 0 {
+		b.WriteString("<table>\n")
+		for _, field := range issue.Fields {
+			fmt.Fprintf(&b, "<tr><th>%s</th><td>%s</td></tr>\n", html.EscapeString(field[0]), html.EscapeString(field[1]))
+		}
+		b.WriteString("</table>\n")
+	}
+
+	b.WriteString("<h2>Description</h2>\n")
+	if description := adfToHTML(issue.Description); description != "" {
+		b.WriteString(description + "\n")
+	} else {
+		b.WriteString("<p><em>No description.</em></p>\n")
+	}
+
+	if len(issue.Links) > 0 {
+		b.WriteString("<h2>Links</h2>\n<ul>\n")
+		for _, link := range issue.Links {
+			b.WriteString("<li>" + html.EscapeString(link) + "</li>\n")
+		}
+		b.WriteString("</ul>\n")
+	}
+
+	if issue.CommentTotal > 0 {
+		fmt.Fprintf(&b, "<h2>Comments (%s)</h2>\n", html.EscapeString(renderCommentCount(issue)))
+		for _, comment := range issue.Comments {
+			fmt.Fprintf(&b, "<h3>%s, %s</h3>\n%s\n", html.EscapeString(comment.Author), html.EscapeString(comment.Created), adfToHTML(comment.Body))
+		}
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// renderCommentCount says how many of the issue's comments are shown, e.g. "latest 5 of 12"
+func renderCommentCount(issue renderedIssue) string {
+	if len(issue.Comments) == issue.CommentTotal {
+		return strconv.Itoa(issue.CommentTotal)
+	}
+	return fmt.Sprintf("latest %d of %d", len(issue.Comments), issue.CommentTotal)
+}
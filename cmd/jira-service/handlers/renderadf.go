@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// adfNodes returns the child nodes of an Atlassian Document Format node
+func adfNodes(node interface{}) []interface{} {
+	m, _ := node.(map[string]interface{})
+	content, _ := m["content"].([]interface{})
+	return content
+}
+
+// adfAttr returns a node's attribute as a string
+func adfAttr(node map[string]interface{}, name string) string {
+	attrs, _ := node["attrs"].(map[string]interface{})
+	switch v := attrs[name].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// adfAtomText is the text of an inline node that has no text of its own, e.g. a mention
+func adfAtomText(node map[string]interface{}) string {
+	switch node["type"] {
+	case "mention", "status":
+		return adfAttr(node, "text")
+	case "emoji":
+		if text := adfAttr(node, "text"); text != "" {
+			return text
+		}
+		return adfAttr(node, "shortName")
+	case "inlineCard", "blockCard":
+		return adfAttr(node, "url")
+	case "date":
+		if ms, err := strconv.ParseInt(adfAttr(node, "timestamp"), 10, 64); err == nil {
+			return time.UnixMilli(ms).UTC().Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+// adfHeadingLevel returns a heading's level, 1 to 6
+func adfHeadingLevel(node map[string]interface{}) int {
+	level, _ := strconv.Atoi(adfAttr(node, "level"))
+	return min(max(level, 1), 6)
+}
+
+// adfToMarkdown renders an Atlassian Document Format tree as Markdown. Plain strings, such
+// as v2 descriptions or redacted values, are returned as they are.
+func adfToMarkdown(doc interface{}) string {
+	if text, ok := doc.(string); ok {
+		return text
+	}
+	return markdownBlocks(adfNodes(doc), "\n\n")
+}
+
+// markdownBlocks renders block nodes, separated by sep
+func markdownBlocks(nodes []interface{}, sep string) string {
+	var blocks []string
+	for _, n := range nodes {
+		node, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if block := markdownBlock(node); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return strings.Join(blocks, sep)
+}
+
+func markdownBlock(node map[string]interface{}) string {
+	content := adfNodes(node)
+	switch node["type"] {
+	case "paragraph":
+		return markdownInline(content)
+	case "heading":
+		return strings.Repeat("#", adfHeadingLevel(node)) + " " + markdownInline(content)
+	case "bulletList", "orderedList":
+		start, _ := strconv.Atoi(adfAttr(node, "order"))
+		start = max(start, 1)
+		items := make([]string, 0, len(content))
+		for i, item := range content {
+			marker := "- "
+			if node["type"] == "orderedList" {
+				marker = fmt.Sprintf("%d. ", start+i)
+			}
+			body := markdownBlocks(adfNodes(item), "\n")
+			items = append(items, marker+strings.ReplaceAll(body, "\n", "\n"+strings.Repeat(" ", len(marker))))
+		}
+		return strings.Join(items, "\n")
+	case "codeBlock":
+		return "```" + adfAttr(node, "language") + "\n" + fieldText(content) + "\n```"
+	case "blockquote", "panel":
+		return "> " + strings.ReplaceAll(markdownBlocks(content, "\n\n"), "\n", "\n> ")
+	case "rule":
+		return "---"
+	case "table":
+		var rows []string
+		for i, row := range content {
+			var cells []string
+			for _, cell := range adfNodes(row) {
+				text := strings.ReplaceAll(markdownBlocks(adfNodes(cell), " "), "\n", " ")
+				cells = append(cells, strings.ReplaceAll(text, "|", "\\|"))
+			}
+			rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+			if i == 0 {
+				rows = append(rows, strings.Repeat("| --- ", len(cells))+"|")
+			}
+		}
+		return strings.Join(rows, "\n")
+	case "mediaSingle", "mediaGroup", "media":
+		return "[attachment]"
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "status", "date":
+		return markdownInline([]interface{}{node})
+	default:
+		return markdownBlocks(content, "\n\n")
+	}
+}
+
+// markdownInline renders inline nodes, applying text marks
+func markdownInline(nodes []interface{}) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		node, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch node["type"] {
+		case "text":
+			text, _ := node["text"].(string)
+			marks, _ := node["marks"].([]interface{})
+			for _, m := range marks {
+				mark, _ := m.(map[string]interface{})
+				switch mark["type"] {
+				case "code":
+					text = "`" + text + "`"
+				case "strong":
+					text = "**" + text + "**"
+				case "em":
+					text = "_" + text + "_"
+				case "strike":
+					text = "~~" + text + "~~"
+				case "link":
+					text = "[" + text + "](" + adfAttr(mark, "href") + ")"
+				}
+			}
+			b.WriteString(text)
+		case "hardBreak":
+			b.WriteString("  \n")
+		case "status":
+			b.WriteString("[" + adfAtomText(node) + "]")
+		default:
+			if text := adfAtomText(node); text != "" {
+				b.WriteString(text)
+			} else {
+				b.WriteString(markdownInline(adfNodes(node)))
+			}
+		}
+	}
+	return b.String()
+}
+
+// adfToHTML renders an Atlassian Document Format tree as HTML. Plain strings are escaped
+// into a paragraph.
+func adfToHTML(doc interface{}) string {
+	if text, ok := doc.(string); ok {
+		return "<p>" + strings.ReplaceAll(html.EscapeString(text), "\n", "<br>") + "</p>"
+	}
+	return htmlBlocks(adfNodes(doc))
+}
+
+// htmlBlocks renders block nodes, one per line
+func htmlBlocks(nodes []interface{}) string {
+	var blocks []string
+	for _, n := range nodes {
+		node, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if block := htmlBlock(node); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return strings.Join(blocks, "\n")
+}
+
+func htmlBlock(node map[string]interface{}) string {
+	content := adfNodes(node)
+	switch node["type"] {
+	case "paragraph":
+		return "<p>" + htmlInline(content) + "</p>"
+	case "heading":
+		level := adfHeadingLevel(node)
+		return fmt.Sprintf("<h%d>%s</h%d>", level, htmlInline(content), level)
+	case "bulletList", "orderedList":
+		tag := "ul"
+		if node["type"] == "orderedList" {
+			tag = "ol"
+		}
+		var b strings.Builder
+		b.WriteString("<" + tag + ">")
+		for _, item := range content {
+			b.WriteString("<li>" + htmlBlocks(adfNodes(item)) + "</li>")
+		}
+		b.WriteString("</" + tag + ">")
+		return b.String()
+	case "codeBlock":
+		return "<pre><code>" + html.EscapeString(fieldText(content)) + "</code></pre>"
+	case "blockquote", "panel":
+		return "<blockquote>" + htmlBlocks(content) + "</blockquote>"
+	case "rule":
+		return "<hr>"
+	case "table":
+		var b strings.Builder
+		b.WriteString("<table>")
+		for _, row := range adfNodes(node) {
+			b.WriteString("<tr>")
+			for _, c := range adfNodes(row) {
+				cell, _ := c.(map[string]interface{})
+				tag := "td"
+				if cell["type"] == "tableHeader" {
+					tag = "th"
+				}
+				b.WriteString("<" + tag + ">" + htmlBlocks(adfNodes(cell)) + "</" + tag + ">")
+			}
+			b.WriteString("</tr>")
+		}
+		b.WriteString("</table>")
+		return b.String()
+	case "mediaSingle", "mediaGroup", "media":
+		return "<p>[attachment]</p>"
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "status", "date":
+		return htmlInline([]interface{}{node})
+	default:
+		return htmlBlocks(content)
+	}
+}
+
+// htmlInline renders inline nodes, applying text marks
+func htmlInline(nodes []interface{}) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		node, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch node["type"] {
+		case "text":
+			text, _ := node["text"].(string)
+			text = html.EscapeString(text)
+			marks, _ := node["marks"].([]interface{})
+			for _, m := range marks {
+				mark, _ := m.(map[string]interface{})
+				switch mark["type"] {
+				case "code":
+					text = "<code>" + text + "</code>"
+				case "strong":
+					text = "<strong>" + text + "</strong>"
+				case "em":
+					text = "<em>" + text + "</em>"
+				case "strike":
+					text = "<s>" + text + "</s>"
+				case "link":
+					text = `<a href="` + htmlHref(adfAttr(mark, "href")) + `">` + text + "</a>"
+				}
+			}
+			b.WriteString(text)
+		case "hardBreak":
+			b.WriteString("<br>")
+		case "inlineCard", "blockCard":
+			url := adfAtomText(node)
+			b.WriteString(`<a href="` + htmlHref(url) + `">` + html.EscapeString(url) + "</a>")
+		default:
+			if text := adfAtomText(node); text != "" {
+				b.WriteString(html.EscapeString(text))
+			} else {
+				b.WriteString(htmlInline(adfNodes(node)))
+			}
+		}
+	}
+	return b.String()
+}
+
+// htmlHref escapes a link target, dropping those that are not web or mail links (such as
+// javascript: URLs)
+func htmlHref(href string) string {
+	lower := strings.ToLower(strings.TrimSpace(href))
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "mailto:") {
+		return "#"
+	}
+	return html.EscapeString(href)
+}
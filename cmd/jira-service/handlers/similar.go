@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	defaultSimilarLimit = 10
+	maxSimilarLimit     = 50
+	defaultSimilarScore = 0.2
+	// similarKeywords is how many of the most distinctive terms each search uses
+	similarKeywords = 6
+	// similarCandidatesPerSearch is how many issues each search fetches
+	similarCandidatesPerSearch = 25
+)
+
+// projectKeyPattern is what a Jira project key may look like
+var projectKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// similarStopWords are too common in issue text to tell issues apart
+var similarStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "any": true, "can": true, "had": true, "has": true, "have": true, "was": true,
+	"were": true, "this": true, "that": true, "with": true, "from": true, "into": true,
+	"when": true, "then": true, "than": true, "there": true, "their": true, "they": true,
+	"them": true, "will": true, "would": true, "should": true, "could": true, "does": true,
+	"doesn": true, "don": true, "isn": true, "its": true, "our": true, "out": true, "off": true,
+	"some": true, "what": true, "which": true, "while": true, "who": true, "why": true,
+	"how": true, "also": true, "been": true, "being": true, "only": true, "other": true,
+	"after": true, "before": true, "about": true, "again": true, "still": true, "just": true,
+	"more": true, "most": true, "very": true, "each": true, "every": true, "such": true,
+	"here": true, "where": true, "these": true, "those": true, "because": true, "via": true,
+	"get": true, "gets": true, "got": true, "use": true, "used": true, "using": true,
+}
+
+// similarFields are the issue fields candidates are fetched with
+var similarFields = []string{"summary", "description", "status", "issuetype", "resolution", "created", "updated"}
+
+// handleFindSimilarIssues looks for issues that may duplicate a new one. It runs a few
+// text searches in the project, then scores every candidate by how many of the new
+// issue's terms it shares, weighting the summary most.
+func (s *Service) handleFindSimilarIssues(client *api.Client, req models.JiraRequest) map[string]interface{} {
+	projectKey, _ := req.Params["project_key"].(string)
+	summary, _ := req.Params["summary"].(string)
+	if projectKey == "" || strings.TrimSpace(summary) == "" {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "missing project_key or summary", req.RequestID)
+	}
+	if !projectKeyPattern.MatchString(projectKey) {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, fmt.Sprintf("invalid project_key: %s", projectKey), req.RequestID)
+	}
+	description, _ := req.Params["description"].(string)
+
+	limit := defaultSimilarLimit
+	if l, ok := req.Params["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	if limit > maxSimilarLimit {
+		limit = maxSimilarLimit
+	}
+	minScore := defaultSimilarScore
+	if m, ok := req.Params["min_score"].(float64); ok && m >= 0 && m <= 1 {
+		minScore = m
+	}
+	includeResolved := true
+	if i, ok := req.Params["include_resolved"].(bool); ok {
+		includeResolved = i
+	}
+
+	summaryTerms := similarTerms(summary)
+	if len(summaryTerms) == 0 {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest, "summary has no searchable words", req.RequestID)
+	}
+	// Description terms the summary does not already have
+	var descriptionTerms []string
+	for _, term := range similarTerms(description) {
+		if !containsTerm(summaryTerms, term) {
+			descriptionTerms = append(descriptionTerms, term)
+		}
+	}
+
+	// From narrow to broad: every summary term, any distinctive summary term, then any
+	// distinctive description term anywhere in the issue
+	clauses := []string{fmt.Sprintf(`summary ~ "%s"`, strings.Join(summaryTerms, " "))}
+	if keywords := distinctiveTerms(summaryTerms, similarKeywords); len(keywords) > 1 {
+		clauses = append(clauses, anyTermClause("summary", keywords))
+	}
+	if keywords := distinctiveTerms(descriptionTerms, similarKeywords); len(keywords) > 0 {
+		clauses = append(clauses, anyTermClause("text", keywords))
+	}
+	scope := fmt.Sprintf(`project = "%s"`, strings.ToUpper(projectKey))
+	if !includeResolved {
+		scope += " AND statusCategory != Done"
+	}
+
+	candidates := make(map[string]models.JiraIssue)
+	var order []string
+	for _, clause := range clauses {
+		jql := fmt.Sprintf("%s AND (%s) ORDER BY updated DESC", scope, clause)
+		results, err := client.SearchIssues(jql, similarFields, similarCandidatesPerSearch, "")
+		if err != nil {
+			return models.ErrorResponse(models.ErrCodeAPIError, err.Error(), req.RequestID)
+		}
+		for _, issue := range results.Issues {
+			if _, seen := candidates[issue.Key]; !seen {
+				candidates[issue.Key] = issue
+				order = append(order, issue.Key)
+			}
+		}
+	}
+
+	queryTerms := append(append([]string{}, summaryTerms...), descriptionTerms...)
+	matches := []map[string]interface{}{}
+	for _, key := range order {
+		issue := candidates[key]
+		candidateSummary, _ := issue.Fields["summary"].(string)
+		candidateSummaryTerms := similarTerms(candidateSummary)
+		candidateTerms := similarTerms(candidateSummary + " " + fieldText(issue.Fields["description"]))
+
+		// Summaries are compared both ways, so a long summary does not match everything;
+		// the full text only needs to cover the new issue's terms
+		score := 0.7*diceCoefficient(summaryTerms, candidateSummaryTerms) + 0.3*coverage(queryTerms, candidateTerms)
+		score = math.Round(score*100) / 100
+		if score < minScore {
+			continue
+		}
+
+		var matched []string
+		for _, term := range queryTerms {
+			if containsTerm(candidateTerms, term) {
+				matched = append(matched, term)
+			}
+		}
+		match := map[string]interface{}{
+			"key":           issue.Key,
+			"summary":       candidateSummary,
+			"score":         score,
+			"matched_terms": matched,
+			"url":           issueURL(issue),
+			"updated":       issue.Fields["updated"],
+		}
+		if status, ok := issue.Fields["status"].(map[string]interface{}); ok {
+			match["status"] = status["name"]
+		}
+		if issueType, ok := issue.Fields["issuetype"].(map[string]interface{}); ok {
+			match["issue_type"] = issueType["name"]
+		}
+		if resolution, ok := issue.Fields["resolution"].(map[string]interface{}); ok {
+			match["resolution"] = resolution["name"]
+		}
+		matches = append(matches, match)
+	}
+	// Ties keep the search order, which puts recently updated issues first
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i]["score"].(float64) > matches[j]["score"].(float64)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return models.SuccessResponse(map[string]interface{}{
+		"project_key":          strings.ToUpper(projectKey),
+		"terms":                queryTerms,
+		"searches":             len(clauses),
+		"candidates_evaluated": len(order),
+		"issues":               matches,
+	}, req.RequestID)
+}
+
+// similarTerms splits text into distinct lowercase words, dropping stop words and words
+// shorter than three letters unless they contain a digit (e.g. "v2" or "500")
+func similarTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if seen[word] || similarStopWords[word] {
+			continue
+		}
+		if len([]rune(word)) < 3 && !strings.ContainsAny(word, "0123456789") {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// distinctiveTerms returns up to n terms, longest first; longer words are rarer
+func distinctiveTerms(terms []string, n int) []string {
+	sorted := append([]string{}, terms...)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// anyTermClause matches issues whose field contains any of the terms. Terms are letters
+// and digits only, so they need no escaping.
+func anyTermClause(field string, terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = fmt.Sprintf(`%s ~ "%s"`, field, term)
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// diceCoefficient measures the overlap of two term sets from 0 to 1
+func diceCoefficient(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	return 2 * float64(sharedTerms(a, b)) / float64(len(a)+len(b))
+}
+
+// coverage is the share of the query terms the candidate has
+func coverage(query, candidate []string) float64 {
+	if len(query) == 0 {
+		return 0
+	}
+	return float64(sharedTerms(query, candidate)) / float64(len(query))
+}
+
+func sharedTerms(a, b []string) int {
+	shared := 0
+	for _, term := range a {
+		if containsTerm(b, term) {
+			shared++
+		}
+	}
+	return shared
+}
+
+func containsTerm(terms []string, term string) bool {
+	for _, t := range terms {
+		if t == term {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldText collects the text of a field value: a string, or an Atlassian Document
+// Format tree such as a v3 description
+func fieldText(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if text, ok := v["text"].(string); ok {
+			return text
+		}
+		return fieldText(v["content"])
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := fieldText(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, " ")
+	default:
+		return ""
+	}
+}
+
+// issueURL returns the browser link of an issue, derived from its REST or agile API URL
+func issueURL(issue models.JiraIssue) string {
+	site, _, ok := strings.Cut(issue.Self, "/rest/")
+	if !ok || site == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/browse/%s", site, issue.Key)
+}
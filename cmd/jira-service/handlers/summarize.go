@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// summaryFields are the issue fields a summarized search asks Jira for
+var summaryFields = []string{"summary", "status", "assignee", "updated"}
+
+// summarizeIssues reduces search results to one line per issue:
+// "KEY | summary | status | assignee | updated"
+func summarizeIssues(results *models.SearchResponse) map[string]interface{} {
+	lines := make([]string, len(results.Issues))
+	for i, issue := range results.Issues {
+		status, assignee := "", "Unassigned"
+		if s, ok := issue.Fields["status"].(map[string]interface{}); ok {
+			status, _ = s["name"].(string)
+		}
+		if a, ok := issue.Fields["assignee"].(map[string]interface{}); ok {
+			if name, _ := a["displayName"].(string); name != "" {
+				assignee = name
+			}
+		}
+		summary, _ := issue.Fields["summary"].(string)
+		updated, _ := issue.Fields["updated"].(string)
+		lines[i] = strings.Join([]string{issue.Key, summaryText(summary), status, assignee, summaryTime(updated)}, " | ")
+	}
+
+	summary := map[string]interface{}{
+		"total":  results.Total,
+		"issues": lines,
+	}
+	if results.NextPageToken != "" {
+		summary["next_page_token"] = results.NextPageToken
+	}
+	return summary
+}
+
+// summaryText keeps a title to one line
+func summaryText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// summaryTime shortens an Atlassian timestamp such as "2026-01-15T09:30:00.000+0000" to
+// the minute, "2026-01-15 09:30"
+func summaryTime(s string) string {
+	if len(s) < 16 {
+		return s
+	}
+	return strings.Replace(s[:16], "T", " ", 1)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// undoFieldUpdate is the update that restores fields of an issue to their values before a
+// change, as read in previous. Fields the issue did not return cannot be restored and are
+// left out; it returns nil when none can.
+func undoFieldUpdate(issueKey string, previous *models.JiraIssue, fieldIDs []string) *models.UndoAction {
+	fields := make(map[string]interface{}, len(fieldIDs))
+	var restored []string
+	for _, id := range fieldIDs {
+		value, ok := previous.Fields[id]
+		if !ok {
+			continue
+		}
+		fields[id] = editableValue(value)
+		restored = append(restored, id)
+	}
+	if len(restored) == 0 {
+		return nil
+	}
+	sort.Strings(restored)
+	return &models.UndoAction{
+		Action:      "update_issue",
+		Params:      map[string]interface{}{"issue_key": issueKey, "fields": fields},
+		Description: fmt.Sprintf("restore the previous %s of %s", strings.Join(restored, ", "), issueKey),
+	}
+}
+
+// editableValue turns a field value as Jira returns it into one an update accepts: users
+// are set by accountId, and options, priorities, versions and components by id
+func editableValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if id, ok := v["accountId"]; ok {
+			return map[string]interface{}{"accountId": id}
+		}
+		if id, ok := v["id"]; ok && v["type"] == nil {
+			return map[string]interface{}{"id": id}
+		}
+		// Documents (ADF) and other values are set as returned; copied, because
+		// callers may go on to change the original
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = value
+		}
+		return copied
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			values[i] = editableValue(value)
+		}
+		return values
+	default:
+		return value
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/api"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// clientCredentials returns what a workspace's client calls Jira with: the workspace's API
+// token, or in user OAuth workspaces the caller's own access token, so results respect the
+// caller's Jira permissions
+func (s *Service) clientCredentials(ctx context.Context, userID string, creds *models.WorkspaceCredentials) (api.WorkspaceCredentials, error) {
+	clientCreds := api.WorkspaceCredentials{
+		Site:  creds.JiraSite(),
+		Email: creds.Email,
+		Token: creds.Token,
+
+		OpsgenieKey: creds.OpsgenieKey,
+		ProxyURL:    creds.ProxyURL,
+	}
+	if !creds.Policy.UsesUserOAuth() {
+		return clientCreds, nil
+	}
+
+	accessToken, err := s.userTokens.AccessToken(ctx, userID)
+	if err != nil {
+		return api.WorkspaceCredentials{}, err
+	}
+	site, err := atlassian.GatewayURL(ctx, "jira", creds.Site, creds.ProxyURL)
+	if err != nil {
+		return api.WorkspaceCredentials{}, err
+	}
+	clientCreds.Site = site
+	clientCreds.Email, clientCreds.Token = "", ""
+	clientCreds.AccessToken = accessToken
+	return clientCreds, nil
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+)
+
+// APIKeyPrefix marks bearer tokens that are API keys rather than JWTs
+const APIKeyPrefix = "trx_"
+
+// GenerateAPIKey creates a new random API key. It returns the secret (shown to the user
+// once), the hash to store and a short display prefix.
+func GenerateAPIKey() (secret, hash, prefix string, err error) {
+	return generateSecret(APIKeyPrefix)
+}
+
+// generateSecret creates a random bearer secret with the given type prefix
+func generateSecret(typePrefix string) (secret, hash, prefix string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+	secret = typePrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return secret, HashAPIKey(secret), secret[:len(typePrefix)+6], nil
+}
+
+// HashAPIKey returns the SHA-256 hex digest stored for a key. Keys are high-entropy,
+// so a fast unsalted hash is sufficient and allows lookup by hash.
+func HashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey reports whether a bearer token looks like an API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// verifyAPIKey resolves an API key to the user it belongs to
+func verifyAPIKey(store storage.APIKeyStoreInterface, token string) (*UserContext, error) {
+	if store == nil {
+		return nil, fmt.Errorf("API keys are not enabled")
+	}
+
+	key, err := store.GetAPIKeyByHash(HashAPIKey(token))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, fmt.Errorf("invalid API key")
+		}
+		return nil, err
+	}
+	if !key.Active(time.Now()) {
+		return nil, fmt.Errorf("API key is revoked or expired")
+	}
+
+	// Recording usage must not slow down or fail the request
+	go func() {
+		if err := store.TouchAPIKey(key.ID); err != nil {
+			slog.Warn("failed to update last use of API key", "key_id", key.ID, "error", err)
+		}
+	}()
+
+	userCtx := &UserContext{UserID: key.UserID}
+	if len(key.Scopes) > 0 {
+		userCtx.Scopes = key.Scopes
+	}
+	return userCtx, nil
+}
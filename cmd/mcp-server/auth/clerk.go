@@ -2,16 +2,10 @@ package auth
 
 import (
 	"context"
-	"crypto/rsa"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"math/big"
 	"net/http"
 	"os"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -26,9 +20,7 @@ const (
 // ClerkAuth handles Clerk authentication
 type ClerkAuth struct {
 	secretKey string
-	jwksURL   string
-	publicKeys map[string]*rsa.PublicKey
-	keysMutex  sync.RWMutex
+	keys      *jwksCache
 }
 
 // UserContext represents authenticated user information
@@ -36,6 +28,9 @@ type UserContext struct {
 	UserID    string
 	Email     string
 	SessionID string
+	Scopes    []string // Granted scopes; nil when the token carries no scope claim (unrestricted)
+	OrgID     string   // Active Clerk organization; empty for personal sessions
+	OrgRole   string   // Role in the active organization, e.g. "org:admin"
 }
 
 // ClerkClaims represents the JWT claims from Clerk
@@ -43,6 +38,10 @@ type ClerkClaims struct {
 	jwt.RegisteredClaims
 	SessionID string `json:"sid"`
 	Email     string `json:"email"`
+	Scope     string `json:"scope,omitempty"` // Space-delimited scopes, e.g. "jira:read confluence:read"
+	OrgID     string `json:"org_id,omitempty"`
+	OrgRole   string `json:"org_role,omitempty"`
+	OrgSlug   string `json:"org_slug,omitempty"`
 }
 
 // NewClerkAuth creates a new Clerk auth handler
@@ -58,44 +57,29 @@ func NewClerkAuth() *ClerkAuth {
 	}
 
 	auth := &ClerkAuth{
-		secretKey:  secretKey,
-		jwksURL:    jwksURL,
-		publicKeys: make(map[string]*rsa.PublicKey),
+		secretKey: secretKey,
+		keys:      newJWKSCache(jwksURL, secretKey),
 	}
 
 	// Fetch public keys on initialization
-	go auth.refreshPublicKeys()
+	go auth.keys.refresh()
 
 	return auth
 }
 
+// Name identifies the provider in logs
+func (c *ClerkAuth) Name() string {
+	return "clerk"
+}
+
 // VerifyToken verifies a Clerk JWT token
 func (c *ClerkAuth) VerifyToken(tokenString string) (*UserContext, error) {
 	if c == nil {
 		return nil, fmt.Errorf("Clerk authentication not configured")
 	}
 
-	// Parse token without verification first to get the kid
-	token, err := jwt.ParseWithClaims(tokenString, &ClerkClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-
-		// Get key ID from header
-		kid, ok := token.Header["kid"].(string)
-		if !ok {
-			return nil, fmt.Errorf("missing kid in token header")
-		}
-
-		// Get public key
-		publicKey, err := c.getPublicKey(kid)
-		if err != nil {
-			return nil, err
-		}
-
-		return publicKey, nil
-	})
+	// Verify the signature with the key named by the token's kid
+	token, err := jwt.ParseWithClaims(tokenString, &ClerkClaims{}, c.keys.keyFunc)
 
 	if err != nil {
 		return nil, fmt.Errorf("token verification failed: %w", err)
@@ -110,108 +94,22 @@ func (c *ClerkAuth) VerifyToken(tokenString string) (*UserContext, error) {
 		return nil, fmt.Errorf("invalid claims type")
 	}
 
-	return &UserContext{
+	userCtx := &UserContext{
 		UserID:    claims.Subject,
 		Email:     claims.Email,
 		SessionID: claims.SessionID,
-	}, nil
-}
-
-// getPublicKey retrieves a public key by kid
-func (c *ClerkAuth) getPublicKey(kid string) (*rsa.PublicKey, error) {
-	c.keysMutex.RLock()
-	key, exists := c.publicKeys[kid]
-	c.keysMutex.RUnlock()
-
-	if exists {
-		return key, nil
-	}
-
-	// Refresh keys and try again
-	if err := c.refreshPublicKeys(); err != nil {
-		return nil, err
-	}
-
-	c.keysMutex.RLock()
-	key, exists = c.publicKeys[kid]
-	c.keysMutex.RUnlock()
-
-	if !exists {
-		return nil, fmt.Errorf("public key not found for kid: %s", kid)
-	}
-
-	return key, nil
-}
-
-// refreshPublicKeys fetches the latest public keys from Clerk's JWKS endpoint
-func (c *ClerkAuth) refreshPublicKeys() error {
-	req, err := http.NewRequest("GET", c.jwksURL, nil)
-	if err != nil {
-		return err
+		OrgID:     claims.OrgID,
+		OrgRole:   claims.OrgRole,
 	}
-
-	req.Header.Set("Authorization", "Bearer "+c.secretKey)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
-	}
-
-	var jwks struct {
-		Keys []struct {
-			Kid string `json:"kid"`
-			Kty string `json:"kty"`
-			Use string `json:"use"`
-			N   string `json:"n"`
-			E   string `json:"e"`
-		} `json:"keys"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
-		return err
-	}
-
-	c.keysMutex.Lock()
-	defer c.keysMutex.Unlock()
-
-	for _, key := range jwks.Keys {
-		if key.Kty != "RSA" {
-			continue
-		}
-
-		// Decode modulus
-		nBytes, err := base64.RawURLEncoding.DecodeString(key.N)
-		if err != nil {
-			continue
-		}
-
-		// Decode exponent
-		eBytes, err := base64.RawURLEncoding.DecodeString(key.E)
+	if claims.Scope != "" {
+		scopes, err := ParseScopes(claims.Scope)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("invalid token scope: %w", err)
 		}
-
-		// Convert exponent to int
-		var eInt int
-		for _, b := range eBytes {
-			eInt = eInt<<8 + int(b)
-		}
-
-		publicKey := &rsa.PublicKey{
-			N: new(big.Int).SetBytes(nBytes),
-			E: eInt,
-		}
-
-		c.publicKeys[key.Kid] = publicKey
+		userCtx.Scopes = scopes
 	}
 
-	return nil
+	return userCtx, nil
 }
 
 // ExtractUserFromContext extracts user context from request context
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksCache holds the RSA signing keys published at a JWKS endpoint, keyed by kid
+type jwksCache struct {
+	url        string
+	bearer     string // Optional Authorization bearer for the JWKS request (Clerk Backend API)
+	publicKeys map[string]*rsa.PublicKey
+	keysMutex  sync.RWMutex
+}
+
+// newJWKSCache creates a key cache for a JWKS URL
+func newJWKSCache(url, bearer string) *jwksCache {
+	return &jwksCache{
+		url:        url,
+		bearer:     bearer,
+		publicKeys: make(map[string]*rsa.PublicKey),
+	}
+}
+
+// getPublicKey retrieves a public key by kid, refreshing the set once if it is unknown
+func (c *jwksCache) getPublicKey(kid string) (*rsa.PublicKey, error) {
+	c.keysMutex.RLock()
+	key, exists := c.publicKeys[kid]
+	c.keysMutex.RUnlock()
+
+	if exists {
+		return key, nil
+	}
+
+	// Refresh keys and try again
+	if err := c.refresh(); err != nil {
+		return nil, err
+	}
+
+	c.keysMutex.RLock()
+	key, exists = c.publicKeys[kid]
+	c.keysMutex.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("public key not found for kid: %s", kid)
+	}
+
+	return key, nil
+}
+
+// refresh fetches the latest public keys from the JWKS endpoint
+func (c *jwksCache) refresh() error {
+	req, err := http.NewRequest("GET", c.url, nil)
+	if err != nil {
+		return err
+	}
+
+	if c.bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearer)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return err
+	}
+
+	c.keysMutex.Lock()
+	defer c.keysMutex.Unlock()
+
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+
+		// Decode modulus
+		nBytes, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			continue
+		}
+
+		// Decode exponent
+		eBytes, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			continue
+		}
+
+		// Convert exponent to int
+		var eInt int
+		for _, b := range eBytes {
+			eInt = eInt<<8 + int(b)
+		}
+
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(nBytes),
+			E: eInt,
+		}
+
+		c.publicKeys[key.Kid] = publicKey
+	}
+
+	return nil
+}
+
+// keyFunc verifies the token is RSA-signed and returns the key named by its kid header
+func (c *jwksCache) keyFunc(token *jwt.Token) (interface{}, error) {
+	// Verify signing method
+	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	// Get key ID from header
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing kid in token header")
+	}
+
+	return c.getPublicKey(kid)
+}
@@ -5,22 +5,39 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
 )
 
 // AuthMiddleware creates HTTP middleware for authentication
 type AuthMiddleware struct {
-	clerkAuth *ClerkAuth
-	optional  bool
+	identity IdentityProvider                   // Verifies user JWTs (Clerk or generic OIDC)
+	apiKeys  storage.APIKeyStoreInterface       // nil disables API key authentication
+	services storage.ServiceTokenStoreInterface // nil disables stored service tokens
+	optional bool
 }
 
 // NewAuthMiddleware creates a new authentication middleware
-func NewAuthMiddleware(clerkAuth *ClerkAuth, optional bool) *AuthMiddleware {
+func NewAuthMiddleware(identity IdentityProvider, optional bool) *AuthMiddleware {
 	return &AuthMiddleware{
-		clerkAuth: clerkAuth,
-		optional:  optional,
+		identity: identity,
+		optional: optional,
 	}
 }
 
+// WithAPIKeys also accepts API keys (trx_...) issued from the given store
+func (m *AuthMiddleware) WithAPIKeys(apiKeys storage.APIKeyStoreInterface) *AuthMiddleware {
+	m.apiKeys = apiKeys
+	return m
+}
+
+// WithServiceTokens also accepts service tokens (trs_...) issued from the given store
+func (m *AuthMiddleware) WithServiceTokens(services storage.ServiceTokenStoreInterface) *AuthMiddleware {
+	m.services = services
+	return m
+}
+
 // Handler wraps an HTTP handler with authentication
 func (m *AuthMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -41,7 +58,7 @@ func (m *AuthMiddleware) Handler(next http.Handler) http.Handler {
 		// If no token and auth is required, return 401
 		if token == "" {
 			if !m.optional {
-				http.Error(w, "Unauthorized: missing authentication token", http.StatusUnauthorized)
+				writeUnauthorized(w, r, "Unauthorized: missing authentication token")
 				return
 			}
 			// Optional auth - continue without user context
@@ -50,9 +67,17 @@ func (m *AuthMiddleware) Handler(next http.Handler) http.Handler {
 		}
 
 
-		// Check for Service Token (Static Trust)
-		serviceToken := os.Getenv("MCP_SERVICE_TOKEN")
-		if serviceToken != "" && token == serviceToken {
+		// Check for service tokens (static MCP_SERVICE_TOKEN or stored trs_ tokens)
+		caller, err := verifyServiceToken(m.services, token)
+		if err != nil {
+			if !m.optional {
+				writeUnauthorized(w, r, fmt.Sprintf("Unauthorized: %v", err))
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		if caller != nil {
 			// Create a "Service" user context
 			// This is a PLACEHOLDER identity to satisfy the non-nil requirement of the context.
 			// It is effectively ignored because we check for "user_id" overrides below.
@@ -62,25 +87,38 @@ func (m *AuthMiddleware) Handler(next http.Handler) http.Handler {
 			}
 
 			// Trusted Service Override: Extract user_id from query params or (if possible) the body
-			// to impersonate a specific Clerk user.
+			// to impersonate a specific Clerk user on the token's allowlist.
 			if injectedUser := r.URL.Query().Get("user_id"); injectedUser != "" {
+				allowed := caller.CanImpersonate(injectedUser)
+				AuditImpersonation(r, caller, injectedUser, allowed)
+				if !allowed {
+					http.Error(w, fmt.Sprintf("Forbidden: service token %q may not act as %s", caller.Name, injectedUser), http.StatusForbidden)
+					return
+				}
 				serviceUserCtx.UserID = injectedUser
-				fmt.Printf("🔒 Service Override (Query): Using user_id=%s\n", injectedUser)
 			}
 			// Note: We don't parse the body here to avoid draining it for downstream handlers.
 			// Downstream handlers (like RestToolHandler) will also check the body.
 
 			ctx := context.WithValue(r.Context(), UserContextKey, serviceUserCtx)
 			ctx = context.WithValue(ctx, "IsServiceCall", true)
+			ctx = context.WithValue(ctx, ServiceContextKey, caller)
 			next.ServeHTTP(w, r.WithContext(ctx))
 			return
 		}
 
-		// Verify Clerk token
-		userCtx, err := m.clerkAuth.VerifyToken(token)
+		// Verify API key or identity provider token
+		var userCtx *UserContext
+		if IsAPIKey(token) {
+			userCtx, err = verifyAPIKey(m.apiKeys, token)
+		} else if m.identity != nil {
+			userCtx, err = m.identity.VerifyToken(token)
+		} else {
+			err = fmt.Errorf("no identity provider configured")
+		}
 		if err != nil {
 			if !m.optional {
-				http.Error(w, fmt.Sprintf("Unauthorized: %v", err), http.StatusUnauthorized)
+				writeUnauthorized(w, r, fmt.Sprintf("Unauthorized: %v", err))
 				return
 			}
 			// Optional auth - continue without user context
@@ -101,12 +139,71 @@ func (m *AuthMiddleware) HandlerFunc(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// RequireScope rejects requests whose token does not grant the scope with 403
+func RequireScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if userCtx, ok := ExtractUserFromContext(r.Context()); ok && !userCtx.HasScope(scope) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, scope))
+			http.Error(w, fmt.Sprintf("Forbidden: the %s scope is required", scope), http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequireOrgAdmin rejects requests from users who are not admins of their active
+// Clerk organization with 403. Service calls are trusted and pass through.
+func RequireOrgAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if isService, _ := r.Context().Value("IsServiceCall").(bool); !isService {
+			userCtx, ok := ExtractUserFromContext(r.Context())
+			if !ok || !userCtx.IsOrgAdmin() {
+				http.Error(w, "Forbidden: organization admin role required", http.StatusForbidden)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// RequireAdmin restricts deployment-wide endpoints to the user IDs listed in
+// MCP_ADMIN_USER_IDS (comma-separated). Service tokens are never admins.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !IsAdmin(r.Context()) {
+			http.Error(w, "Forbidden: administrator access required", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// IsAdmin reports whether a request was made by a user listed in MCP_ADMIN_USER_IDS.
+// Service tokens are never admins, even when acting as an admin user.
+func IsAdmin(ctx context.Context) bool {
+	userCtx, ok := ExtractUserFromContext(ctx)
+	if _, isService := ServiceCallerFromContext(ctx); !ok || isService {
+		return false
+	}
+	return isAdmin(userCtx.UserID)
+}
+
+// isAdmin reports whether a user is listed in MCP_ADMIN_USER_IDS
+func isAdmin(userID string) bool {
+	for _, admin := range strings.Split(os.Getenv("MCP_ADMIN_USER_IDS"), ",") {
+		if admin = strings.TrimSpace(admin); admin != "" && admin == userID {
+			return true
+		}
+	}
+	return false
+}
+
 // RequireAuth creates middleware that requires authentication
-func RequireAuth(clerkAuth *ClerkAuth) *AuthMiddleware {
-	return NewAuthMiddleware(clerkAuth, false)
+func RequireAuth(identity IdentityProvider) *AuthMiddleware {
+	return NewAuthMiddleware(identity, false)
 }
 
 // OptionalAuth creates middleware that allows optional authentication
-func OptionalAuth(clerkAuth *ClerkAuth) *AuthMiddleware {
-	return NewAuthMiddleware(clerkAuth, true)
+func OptionalAuth(identity IdentityProvider) *AuthMiddleware {
+	return NewAuthMiddleware(identity, true)
 }
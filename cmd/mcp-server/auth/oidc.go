@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OIDCAuth verifies access tokens from a generic OpenID Connect provider
+// (Auth0, Keycloak, Azure AD, ...)
+type OIDCAuth struct {
+	issuer   string
+	audience string
+	keys     *jwksCache
+}
+
+// OIDCClaims represents the JWT claims used from an OIDC access token
+type OIDCClaims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Scope     string `json:"scope,omitempty"` // Space-delimited scopes (Auth0, Keycloak)
+	Scp       string `json:"scp,omitempty"`   // Space-delimited scopes (Azure AD)
+}
+
+// NewOIDCAuthFromEnv creates an OIDC verifier from OIDC_ISSUER, OIDC_AUDIENCE and the
+// optional OIDC_JWKS_URL. Without OIDC_JWKS_URL the key set is located through the
+// issuer's discovery document. It returns nil when OIDC_ISSUER is not set.
+func NewOIDCAuthFromEnv() (*OIDCAuth, error) {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil, nil
+	}
+
+	audience := os.Getenv("OIDC_AUDIENCE")
+	if audience == "" {
+		return nil, fmt.Errorf("OIDC_AUDIENCE is required when OIDC_ISSUER is set")
+	}
+
+	jwksURL := os.Getenv("OIDC_JWKS_URL")
+	if jwksURL == "" {
+		discovered, err := discoverJWKSURL(issuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover JWKS URL for %s: %w", issuer, err)
+		}
+		jwksURL = discovered
+	}
+
+	auth := &OIDCAuth{
+		issuer:   issuer,
+		audience: audience,
+		keys:     newJWKSCache(jwksURL, ""),
+	}
+
+	// Fetch public keys on initialization
+	go auth.keys.refresh()
+
+	return auth, nil
+}
+
+// Name identifies the provider in logs
+func (o *OIDCAuth) Name() string {
+	return "oidc"
+}
+
+// VerifyToken verifies an OIDC access token, including its issuer and audience
+func (o *OIDCAuth) VerifyToken(tokenString string) (*UserContext, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &OIDCClaims{}, o.keys.keyFunc,
+		jwt.WithIssuer(o.issuer),
+		jwt.WithAudience(o.audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("token verification failed: %w", err)
+	}
+
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	claims, ok := token.Claims.(*OIDCClaims)
+	if !ok {
+		return nil, fmt.Errorf("invalid claims type")
+	}
+
+	userCtx := &UserContext{
+		UserID:    claims.Subject,
+		Email:     claims.Email,
+		SessionID: claims.SessionID,
+	}
+
+	// Providers put their own scopes (openid, profile, ...) in the claim too; only ours count
+	scope := claims.Scope
+	if scope == "" {
+		scope = claims.Scp
+	}
+	if ours := knownScopes(scope); ours != "" {
+		scopes, err := ParseScopes(ours)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token scope: %w", err)
+		}
+		userCtx.Scopes = scopes
+	}
+
+	return userCtx, nil
+}
+
+// knownScopes drops scopes this server does not define from a space-delimited list
+func knownScopes(scope string) string {
+	var ours []string
+	for _, s := range strings.Fields(scope) {
+		for _, known := range AllScopes {
+			if s == known {
+				ours = append(ours, s)
+				break
+			}
+		}
+	}
+	return strings.Join(ours, " ")
+}
+
+// discoverJWKSURL reads jwks_uri from the issuer's OpenID configuration
+func discoverJWKSURL(issuer string) (string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch OpenID configuration: status %d", resp.StatusCode)
+	}
+
+	var config struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return "", err
+	}
+	if config.JWKSURI == "" {
+		return "", fmt.Errorf("OpenID configuration has no jwks_uri")
+	}
+	return config.JWKSURI, nil
+}
@@ -0,0 +1,18 @@
+package auth
+
+// Clerk organization roles. Older Clerk instances issue roles without the "org:" prefix.
+const (
+	OrgRoleAdmin       = "org:admin"
+	OrgRoleMember      = "org:member"
+	legacyOrgRoleAdmin = "admin"
+)
+
+// InOrg reports whether the user's session has an active organization
+func (u *UserContext) InOrg() bool {
+	return u != nil && u.OrgID != ""
+}
+
+// IsOrgAdmin reports whether the user is an admin of their active organization
+func (u *UserContext) IsOrgAdmin() bool {
+	return u.InOrg() && (u.OrgRole == OrgRoleAdmin || u.OrgRole == legacyOrgRoleAdmin)
+}
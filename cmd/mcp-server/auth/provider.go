@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// IdentityProvider verifies bearer tokens issued by an external identity provider
+type IdentityProvider interface {
+	// VerifyToken validates a JWT and returns the user it was issued to
+	VerifyToken(tokenString string) (*UserContext, error)
+	// Name identifies the provider in logs
+	Name() string
+}
+
+// NewIdentityProviderFromEnv selects the identity provider from AUTH_PROVIDER ("clerk" or
+// "oidc"). When AUTH_PROVIDER is unset, OIDC is used if OIDC_ISSUER is set and Clerk
+// otherwise. It returns nil when the selected provider is not configured.
+func NewIdentityProviderFromEnv() (IdentityProvider, error) {
+	provider := strings.ToLower(os.Getenv("AUTH_PROVIDER"))
+	if provider == "" {
+		provider = "clerk"
+		if os.Getenv("OIDC_ISSUER") != "" {
+			provider = "oidc"
+		}
+	}
+
+	switch provider {
+	case "clerk":
+		// Avoid returning a typed nil inside the interface
+		if clerkAuth := NewClerkAuth(); clerkAuth != nil {
+			return clerkAuth, nil
+		}
+		return nil, nil
+	case "oidc":
+		oidcAuth, err := NewOIDCAuthFromEnv()
+		if err != nil || oidcAuth == nil {
+			return nil, err
+		}
+		return oidcAuth, nil
+	default:
+		return nil, fmt.Errorf("unknown AUTH_PROVIDER: %s", provider)
+	}
+}
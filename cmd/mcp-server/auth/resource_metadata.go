@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ProtectedResourceMetadataPath is where MCP clients discover how to authenticate (RFC 9728)
+const ProtectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// ProtectedResourceMetadata describes this server as an OAuth protected resource
+type ProtectedResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers,omitempty"`
+	ScopesSupported        []string `json:"scopes_supported"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported"`
+	ResourceName           string   `json:"resource_name"`
+}
+
+// HandleProtectedResourceMetadata handles GET /.well-known/oauth-protected-resource
+func HandleProtectedResourceMetadata(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	metadata := ProtectedResourceMetadata{
+		Resource:               PublicURL(r),
+		AuthorizationServers:   authorizationServers(),
+		ScopesSupported:        AllScopes,
+		BearerMethodsSupported: []string{"header", "query"},
+		ResourceName:           "Trilix Atlassian MCP Server",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(metadata)
+}
+
+// writeUnauthorized sends a 401 with a WWW-Authenticate header pointing at the
+// resource metadata, so MCP clients can discover the authorization server
+func writeUnauthorized(w http.ResponseWriter, r *http.Request, message string) {
+	w.Header().Set("WWW-Authenticate",
+		fmt.Sprintf(`Bearer resource_metadata="%s%s"`, PublicURL(r), ProtectedResourceMetadataPath))
+	http.Error(w, message, http.StatusUnauthorized)
+}
+
+// PublicURL is the public base URL of this server. MCP_PUBLIC_URL wins; otherwise it
+// is derived from the request, honouring X-Forwarded-Proto from the ingress.
+func PublicURL(r *http.Request) string {
+	if publicURL := os.Getenv("MCP_PUBLIC_URL"); publicURL != "" {
+		return strings.TrimSuffix(publicURL, "/")
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+	return scheme + "://" + r.Host
+}
+
+// authorizationServers reads OAUTH_AUTHORIZATION_SERVERS (comma-separated issuer URLs,
+// e.g. the Clerk instance's Frontend API URL), defaulting to OIDC_ISSUER
+func authorizationServers() []string {
+	var servers []string
+	for _, s := range strings.Split(os.Getenv("OAUTH_AUTHORIZATION_SERVERS"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			servers = append(servers, s)
+		}
+	}
+	if len(servers) == 0 && os.Getenv("OIDC_ISSUER") != "" {
+		servers = append(servers, os.Getenv("OIDC_ISSUER"))
+	}
+	return servers
+}
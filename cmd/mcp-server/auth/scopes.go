@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// Scopes that can be granted to an access token
+const (
+	ScopeJiraRead         = "jira:read"
+	ScopeJiraWrite        = "jira:write"
+	ScopeConfluenceRead   = "confluence:read"
+	ScopeConfluenceWrite  = "confluence:write"
+	ScopeBitbucketRead    = "bitbucket:read"
+	ScopeOpsgenieRead     = "opsgenie:read"
+	ScopeOpsgenieWrite    = "opsgenie:write"
+	ScopeAdminRead        = "admin:read"
+	ScopeWorkspacesManage = "workspaces:manage"
+)
+
+// AllScopes lists every supported scope
+var AllScopes = []string{
+	ScopeJiraRead,
+	ScopeJiraWrite,
+	ScopeConfluenceRead,
+	ScopeConfluenceWrite,
+	ScopeBitbucketRead,
+	ScopeOpsgenieRead,
+	ScopeOpsgenieWrite,
+	ScopeAdminRead,
+	ScopeWorkspacesManage,
+}
+
+// ParseScopes splits a space-delimited scope string (RFC 6749 section 3.3) and
+// rejects unknown scopes. Write scopes imply the matching read scope.
+func ParseScopes(scope string) ([]string, error) {
+	var scopes []string
+	seen := make(map[string]bool)
+	add := func(s string) {
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+
+	for _, s := range strings.Fields(scope) {
+		switch s {
+		case ScopeJiraWrite:
+			add(ScopeJiraRead)
+		case ScopeConfluenceWrite:
+			add(ScopeConfluenceRead)
+		case ScopeOpsgenieWrite:
+			add(ScopeOpsgenieRead)
+		case ScopeJiraRead, ScopeConfluenceRead, ScopeBitbucketRead, ScopeOpsgenieRead, ScopeAdminRead, ScopeWorkspacesManage:
+		default:
+			return nil, fmt.Errorf("unknown scope: %s", s)
+		}
+		add(s)
+	}
+	return scopes, nil
+}
+
+// RequiredScope returns the scope needed to call an MCP tool ("" = any authenticated caller)
+func RequiredScope(toolName string) string {
+	// Every version of a tool (e.g. jira_list_issues@v2) needs the same scope
+	toolName, _, _ = strings.Cut(toolName, "@")
+	switch {
+	case strings.HasPrefix(toolName, "jira_"):
+		if models.JiraMutatingActions[strings.TrimPrefix(toolName, "jira_")] {
+			return ScopeJiraWrite
+		}
+		return ScopeJiraRead
+	case strings.HasPrefix(toolName, "confluence_"):
+		if models.ConfluenceMutatingActions[strings.TrimPrefix(toolName, "confluence_")] {
+			return ScopeConfluenceWrite
+		}
+		return ScopeConfluenceRead
+	case strings.HasPrefix(toolName, "bitbucket_"):
+		// Every Bitbucket tool is read-only
+		return ScopeBitbucketRead
+	case strings.HasPrefix(toolName, "opsgenie_"):
+		// Opsgenie actions keep their prefix in JiraMutatingActions
+		if models.JiraMutatingActions[toolName] {
+			return ScopeOpsgenieWrite
+		}
+		return ScopeOpsgenieRead
+	case strings.HasPrefix(toolName, "admin_"):
+		// User and group directory lookups
+		return ScopeAdminRead
+	case toolName == "subscribe_issue_updates", toolName == "list_issue_subscriptions", toolName == "unsubscribe_issue_updates":
+		// Subscriptions deliver Jira search results
+		return ScopeJiraRead
+	case toolName == "pin_issue", toolName == "unpin_issue", toolName == "list_pinned_issues":
+		// Pins are listed with the issues' current fields
+		return ScopeJiraRead
+	case toolName == "connect_workspace":
+		// Like POST /api/workspaces
+		return ScopeWorkspacesManage
+	default:
+		// list_workspaces, workspace_status and workspace_rate_status only expose the caller's
+		// own workspaces, notify_channel only posts to the caller's own channels, run_playbook
+		// checks the scope of each step's tool, and sync_labels that of the product it syncs
+		return ""
+	}
+}
+
+// HasScope reports whether the user's token grants a scope. Tokens without a scope
+// claim (Clerk session tokens, the service token) are unrestricted.
+func (u *UserContext) HasScope(scope string) bool {
+	if u == nil || u.Scopes == nil || scope == "" {
+		return true
+	}
+	for _, s := range u.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// crossProductScopes lists the scopes needed by the tools that use both Jira and Confluence
+var crossProductScopes = map[string][]string{
+	"search_atlassian":        {ScopeJiraRead, ScopeConfluenceRead},
+	"generate_release_notes":  {ScopeJiraRead, ScopeConfluenceWrite},
+	"create_issues_from_page": {ScopeJiraWrite, ScopeConfluenceWrite},
+}
+
+// CheckToolScope returns an error when the user's token may not call the tool
+func (u *UserContext) CheckToolScope(toolName string) error {
+	scopes, ok := crossProductScopes[toolName]
+	if !ok {
+		scopes = []string{RequiredScope(toolName)}
+	}
+	for _, scope := range scopes {
+		if !u.HasScope(scope) {
+			return fmt.Errorf("insufficient_scope: %s requires the %s scope", toolName, scope)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+)
+
+// ServiceTokenPrefix marks bearer tokens that are service tokens
+const ServiceTokenPrefix = "trs_"
+
+// ServiceContextKey holds the *ServiceCaller for requests authenticated with a service token
+const ServiceContextKey contextKey = "service"
+
+// legacyServiceTokenName identifies the static MCP_SERVICE_TOKEN in audit logs
+const legacyServiceTokenName = "MCP_SERVICE_TOKEN"
+
+// ServiceCaller describes the service token a request was authenticated with
+type ServiceCaller struct {
+	TokenID      string // Empty for the static MCP_SERVICE_TOKEN
+	Name         string
+	AllowedUsers []string // User IDs the caller may act as; nil allows any user (legacy token only)
+}
+
+// GenerateServiceToken creates a new random service token. It returns the secret
+// (shown to the admin once), the hash to store and a short display prefix.
+func GenerateServiceToken() (secret, hash, prefix string, err error) {
+	return generateSecret(ServiceTokenPrefix)
+}
+
+// IsServiceToken reports whether a bearer token looks like a service token
+func IsServiceToken(token string) bool {
+	return strings.HasPrefix(token, ServiceTokenPrefix)
+}
+
+// CanImpersonate reports whether the caller may act on behalf of the user
+func (s *ServiceCaller) CanImpersonate(userID string) bool {
+	if s.AllowedUsers == nil {
+		return true
+	}
+	for _, allowed := range s.AllowedUsers {
+		if allowed == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceCallerFromContext returns the service token a request was authenticated with
+func ServiceCallerFromContext(ctx context.Context) (*ServiceCaller, bool) {
+	caller, ok := ctx.Value(ServiceContextKey).(*ServiceCaller)
+	return caller, ok
+}
+
+// AuditImpersonation logs an attempt by a service token to act on behalf of a user
+func AuditImpersonation(r *http.Request, caller *ServiceCaller, userID string, allowed bool) {
+	outcome := "allowed"
+	if !allowed {
+		outcome = "denied"
+	}
+	slog.InfoContext(r.Context(), "AUDIT impersonation", "outcome", outcome, "service", caller.Name, "token_id", caller.TokenID,
+		"user_id", userID, "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+}
+
+// verifyServiceToken resolves a bearer token to a service caller. It returns nil
+// without an error when the token is not a service token at all.
+func verifyServiceToken(store storage.ServiceTokenStoreInterface, token string) (*ServiceCaller, error) {
+	if legacy := os.Getenv("MCP_SERVICE_TOKEN"); legacy != "" && subtle.ConstantTimeCompare([]byte(token), []byte(legacy)) == 1 {
+		return &ServiceCaller{Name: legacyServiceTokenName, AllowedUsers: legacyAllowedUsers()}, nil
+	}
+	if !IsServiceToken(token) {
+		return nil, nil
+	}
+	if store == nil {
+		return nil, fmt.Errorf("service tokens are not enabled")
+	}
+
+	st, err := store.GetServiceTokenByHash(HashAPIKey(token))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, fmt.Errorf("invalid service token")
+		}
+		return nil, err
+	}
+	if st.RevokedAt != nil {
+		return nil, fmt.Errorf("service token is revoked")
+	}
+
+	// Recording usage must not slow down or fail the request
+	go func() {
+		if err := store.TouchServiceToken(st.ID); err != nil {
+			slog.Warn("failed to update last use of service token", "token_id", st.ID, "error", err)
+		}
+	}()
+
+	// Stored tokens always have an allowlist, even if empty
+	allowed := st.AllowedUsers
+	if allowed == nil {
+		allowed = []string{}
+	}
+	return &ServiceCaller{TokenID: st.ID, Name: st.Name, AllowedUsers: allowed}, nil
+}
+
+// legacyAllowedUsers reads MCP_SERVICE_TOKEN_ALLOWED_USERS (comma-separated). Unset keeps
+// the historical behaviour of letting the static token act as any user.
+func legacyAllowedUsers() []string {
+	raw := os.Getenv("MCP_SERVICE_TOKEN_ALLOWED_USERS")
+	if raw == "" {
+		return nil
+	}
+	allowed := []string{}
+	for _, userID := range strings.Split(raw, ",") {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			allowed = append(allowed, userID)
+		}
+	}
+	return allowed
+}
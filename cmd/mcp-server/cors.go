@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// corsPolicy controls which browser origins may call the server
+type corsPolicy struct {
+	allowedOrigins   []string // Exact origins, "*" or wildcard subdomains such as "https://*.example.com"
+	allowedMethods   string
+	allowedHeaders   string
+	allowCredentials bool
+	maxAge           int // Seconds browsers may cache a preflight result (0 = browser default)
+}
+
+// corsPolicyFromEnv reads CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS, CORS_ALLOWED_HEADERS,
+// CORS_ALLOW_CREDENTIALS and CORS_MAX_AGE. Without CORS_ALLOWED_ORIGINS every origin is
+// allowed, but without credentials.
+func corsPolicyFromEnv() corsPolicy {
+	policy := corsPolicy{
+		allowedOrigins: splitList(os.Getenv("CORS_ALLOWED_ORIGINS")),
+		allowedMethods: "GET, POST, PUT, DELETE, OPTIONS",
+		allowedHeaders: "Content-Type, Authorization, X-Workspace-Key, X-Request-ID",
+	}
+	if len(policy.allowedOrigins) == 0 {
+		policy.allowedOrigins = []string{"*"}
+	}
+	if methods := splitList(os.Getenv("CORS_ALLOWED_METHODS")); len(methods) > 0 {
+		policy.allowedMethods = strings.Join(methods, ", ")
+	}
+	if headers := splitList(os.Getenv("CORS_ALLOWED_HEADERS")); len(headers) > 0 {
+		policy.allowedHeaders = strings.Join(headers, ", ")
+	}
+	// Credentials default to on for explicit origins; browsers never send them to "*"
+	policy.allowCredentials = !policy.allowsAnyOrigin()
+	if v := os.Getenv("CORS_ALLOW_CREDENTIALS"); v != "" {
+		policy.allowCredentials = strings.EqualFold(v, "true") && !policy.allowsAnyOrigin()
+	}
+	if v, err := strconv.Atoi(os.Getenv("CORS_MAX_AGE")); err == nil && v > 0 {
+		policy.maxAge = v
+	}
+	return policy
+}
+
+// allowsAnyOrigin reports whether the policy is the wildcard "*"
+func (p corsPolicy) allowsAnyOrigin() bool {
+	for _, origin := range p.allowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsOrigin reports whether a request Origin matches the policy
+func (p corsPolicy) allowsOrigin(origin string) bool {
+	for _, allowed := range p.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		// "https://*.example.com" matches any subdomain, but not example.com itself
+		if scheme, host, ok := strings.Cut(allowed, "://*."); ok {
+			prefix := scheme + "://"
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(strings.ToLower(origin), "."+strings.ToLower(host)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// corsMiddleware applies the CORS policy to every response and answers preflight requests
+func corsMiddleware(policy corsPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+
+		if policy.allowsAnyOrigin() {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			// The response depends on the Origin, so caches must key on it
+			w.Header().Add("Vary", "Origin")
+			if origin != "" && policy.allowsOrigin(origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if policy.allowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+		}
+
+		// Let browser clients read the correlation ID to quote in bug reports
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, X-PII-Warning")
+
+		if r.Method == "OPTIONS" {
+			w.Header().Set("Access-Control-Allow-Methods", policy.allowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", policy.allowedHeaders)
+			if policy.maxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(policy.maxAge))
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// splitList splits a comma-separated environment value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
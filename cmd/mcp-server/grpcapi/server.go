@@ -0,0 +1,409 @@
+// Package grpcapi serves the tool and workspace APIs over gRPC for internal platform
+// services, as described by proto/trilix/v1/trilix.proto. Each call is answered by running
+// the equivalent REST request through the MCP server's own routes, so authentication,
+// scopes, impersonation, quotas and response limits are exactly those of the REST API.
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/handlers"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+	trilixv1 "github.com/providentiaww/trilix-atlassian-mcp/proto/trilix/v1"
+)
+
+func invalidArgument(format string, args ...interface{}) error {
+	return status.Errorf(codes.InvalidArgument, format, args...)
+}
+
+// Server answers gRPC calls by running the matching REST requests through routes
+type Server struct {
+	routes http.Handler
+	tools  func() []mcp.Tool
+}
+
+// toolsService and workspacesService implement the two services of the .proto on a Server
+type toolsService struct {
+	trilixv1.UnimplementedToolsServer
+	*Server
+}
+
+type workspacesService struct {
+	trilixv1.UnimplementedWorkspacesServer
+	*Server
+}
+
+// NewServer creates a gRPC server on the MCP server's HTTP routes. tools lists the tools
+// the server offers.
+func NewServer(routes http.Handler, tools func() []mcp.Tool) *Server {
+	return &Server{routes: routes, tools: tools}
+}
+
+// ServerFromEnv returns the gRPC server for the API and the address it listens on
+// (GRPC_PORT), or nil when GRPC_PORT is not set. It presents GRPC_TLS_CERT and
+// GRPC_TLS_KEY, and clients must present a certificate signed by a CA in GRPC_CLIENT_CA.
+// interceptors run around every call, outermost first.
+func ServerFromEnv(api *Server, interceptors ...grpc.UnaryServerInterceptor) (*grpc.Server, string, error) {
+	port := os.Getenv("GRPC_PORT")
+	if port == "" {
+		return nil, "", nil
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return nil, "", fmt.Errorf("GRPC_PORT: invalid port %q", port)
+	}
+	certFile, keyFile, caFile := os.Getenv("GRPC_TLS_CERT"), os.Getenv("GRPC_TLS_KEY"), os.Getenv("GRPC_CLIENT_CA")
+	if certFile == "" || keyFile == "" || caFile == "" {
+		return nil, "", errors.New("GRPC_PORT requires GRPC_TLS_CERT, GRPC_TLS_KEY and GRPC_CLIENT_CA")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("GRPC_TLS_CERT/GRPC_TLS_KEY: %w", err)
+	}
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("GRPC_CLIENT_CA: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, "", fmt.Errorf("GRPC_CLIENT_CA: no PEM certificates in %s", caFile)
+	}
+
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+			MinVersion:   tls.VersionTLS12,
+		})),
+		grpc.ChainUnaryInterceptor(append(interceptors, logCall)...),
+	)
+	trilixv1.RegisterToolsServer(server, toolsService{Server: api})
+	trilixv1.RegisterWorkspacesServer(server, workspacesService{Server: api})
+	return server, ":" + port, nil
+}
+
+// logCall logs every call with its outcome
+func logCall(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	slog.InfoContext(ctx, "gRPC call", "method", info.FullMethod, "client", clientName(ctx),
+		"code", status.Code(err).String(), "duration_ms", time.Since(start).Milliseconds())
+	return resp, err
+}
+
+// clientName identifies the caller by its certificate, for logs
+func clientName(ctx context.Context) string {
+	if state := tlsState(ctx); state != nil && len(state.PeerCertificates) > 0 {
+		return state.PeerCertificates[0].Subject.CommonName
+	}
+	return ""
+}
+
+// tlsState returns the caller's TLS connection state
+func tlsState(ctx context.Context) *tls.ConnectionState {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		return &info.State
+	}
+	return nil
+}
+
+// restResponse is the outcome of a REST request run for a call
+type restResponse struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (rec *restResponse) Header() http.Header {
+	return rec.header
+}
+
+func (rec *restResponse) Write(b []byte) (int, error) {
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	return rec.body.Write(b)
+}
+
+func (rec *restResponse) WriteHeader(code int) {
+	if rec.code == 0 {
+		rec.code = code
+	}
+}
+
+// status returns the call's status for a failed REST response, or nil
+func (rec *restResponse) status() error {
+	if rec.code < 300 {
+		return nil
+	}
+	message := strings.TrimSpace(rec.body.String())
+	var body struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(rec.body.Bytes(), &body) == nil && body.Message != "" {
+		message = body.Message
+	}
+
+	code := codes.Unknown
+	switch rec.code {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		code = codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		code = codes.Unavailable
+	case http.StatusGatewayTimeout:
+		code = codes.DeadlineExceeded
+	default:
+		if rec.code >= 500 {
+			code = codes.Internal
+		}
+	}
+	return status.Error(code, message)
+}
+
+// rest runs a REST request for a call, with the call's authorization metadata and request
+// ID. userID, which service tokens may act as, goes in the query string as on the REST API.
+func (s *Server) rest(ctx context.Context, httpMethod, path, userID string, query url.Values, body interface{}) *restResponse {
+	if query == nil {
+		query = url.Values{}
+	}
+	if userID != "" {
+		query.Set("user_id", userID)
+	}
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		encoded, _ := json.Marshal(body)
+		reader = bytes.NewReader(encoded)
+	}
+
+	target := path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, httpMethod, target, reader)
+	rec := &restResponse{header: make(http.Header)}
+	if err != nil {
+		rec.code = http.StatusBadRequest
+		rec.body.WriteString(err.Error())
+		return rec
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization := metadata.ValueFromIncomingContext(ctx, "authorization"); len(authorization) > 0 {
+		req.Header.Set("Authorization", authorization[0])
+	}
+	if requestID := logging.RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(logging.RequestIDHeader, requestID)
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		req.RemoteAddr = p.Addr.String()
+	}
+	req.TLS = tlsState(ctx)
+
+	s.routes.ServeHTTP(rec, req)
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	return rec
+}
+
+// ListTools lists the tools this server offers
+func (s toolsService) ListTools(ctx context.Context, _ *trilixv1.ListToolsRequest) (*trilixv1.ListToolsResponse, error) {
+	resp := &trilixv1.ListToolsResponse{}
+	for _, t := range s.tools() {
+		schema, _ := json.Marshal(t.InputSchema)
+		resp.Tools = append(resp.Tools, &trilixv1.Tool{Name: t.Name, Description: t.Description, InputSchemaJson: string(schema)})
+	}
+	return resp, nil
+}
+
+// CallTool runs a tool as POST /api/tools/{name}
+func (s toolsService) CallTool(ctx context.Context, req *trilixv1.CallToolRequest) (*trilixv1.CallToolResponse, error) {
+	if req.Name == "" || strings.Contains(req.Name, "/") {
+		return nil, invalidArgument("invalid tool name %q", req.Name)
+	}
+	arguments := map[string]interface{}{}
+	if req.ArgumentsJson != "" {
+		if err := json.Unmarshal([]byte(req.ArgumentsJson), &arguments); err != nil {
+			return nil, invalidArgument("arguments_json is not a JSON object: %v", err)
+		}
+	}
+
+	rec := s.rest(ctx, http.MethodPost, "/api/tools/"+req.Name, req.UserId, nil, arguments)
+	if err := rec.status(); err != nil {
+		return nil, err
+	}
+	return &trilixv1.CallToolResponse{
+		ResultJson: strings.TrimSpace(rec.body.String()),
+		PiiWarning: rec.header.Get("X-PII-Warning"),
+	}, nil
+}
+
+// ListWorkspaces lists the caller's workspaces as GET /api/workspaces
+func (s workspacesService) ListWorkspaces(ctx context.Context, req *trilixv1.ListWorkspacesRequest) (*trilixv1.ListWorkspacesResponse, error) {
+	query := url.Values{}
+	if req.IncludeDeleted {
+		query.Set("include_deleted", "true")
+	}
+
+	rec := s.rest(ctx, http.MethodGet, "/api/workspaces", req.UserId, query, nil)
+	if err := rec.status(); err != nil {
+		return nil, err
+	}
+	var workspaces []handlers.WorkspaceResponse
+	if err := json.Unmarshal(rec.body.Bytes(), &workspaces); err != nil {
+		return nil, status.Errorf(codes.Internal, "unexpected workspace list: %v", err)
+	}
+	resp := &trilixv1.ListWorkspacesResponse{Workspaces: make([]*trilixv1.Workspace, 0, len(workspaces))}
+	for _, ws := range workspaces {
+		resp.Workspaces = append(resp.Workspaces, workspaceMessage(ws))
+	}
+	return resp, nil
+}
+
+// CreateWorkspace adds a workspace as POST /api/workspaces
+func (s workspacesService) CreateWorkspace(ctx context.Context, req *trilixv1.CreateWorkspaceRequest) (*trilixv1.Workspace, error) {
+	create := handlers.CreateWorkspaceRequest{
+		WorkspaceName: req.WorkspaceName,
+		SiteURL:       req.SiteUrl,
+		Email:         req.Email,
+		APIToken:      req.ApiToken,
+		Shared:        req.Shared,
+	}
+	if req.PolicyJson != "" {
+		create.Policy = &models.WorkspacePolicy{}
+		if err := json.Unmarshal([]byte(req.PolicyJson), create.Policy); err != nil {
+			return nil, invalidArgument("policy_json: %v", err)
+		}
+	}
+	for _, field := range []struct {
+		value string
+		dest  **string
+	}{
+		{req.JiraUrl, &create.JiraURL},
+		{req.ConfluenceUrl, &create.ConfluenceURL},
+		{req.OpsgenieApiKey, &create.OpsgenieAPIKey},
+		{req.ProxyUrl, &create.ProxyURL},
+	} {
+		if field.value != "" {
+			value := field.value
+			*field.dest = &value
+		}
+	}
+
+	rec := s.rest(ctx, http.MethodPost, "/api/workspaces", req.UserId, nil, create)
+	if err := rec.status(); err != nil {
+		return nil, err
+	}
+	var created handlers.WorkspaceResponse
+	if err := json.Unmarshal(rec.body.Bytes(), &created); err != nil {
+		return nil, status.Errorf(codes.Internal, "unexpected workspace: %v", err)
+	}
+	return workspaceMessage(created), nil
+}
+
+// DeleteWorkspace soft-deletes a workspace as DELETE /api/workspaces/{id}
+func (s workspacesService) DeleteWorkspace(ctx context.Context, req *trilixv1.WorkspaceRequest) (*trilixv1.DeleteWorkspaceResponse, error) {
+	if err := s.workspaceCall(ctx, req, http.MethodDelete, ""); err != nil {
+		return nil, err
+	}
+	return &trilixv1.DeleteWorkspaceResponse{}, nil
+}
+
+// RestoreWorkspace restores a deleted workspace as POST /api/workspaces/{id}/restore
+func (s workspacesService) RestoreWorkspace(ctx context.Context, req *trilixv1.WorkspaceRequest) (*trilixv1.RestoreWorkspaceResponse, error) {
+	if err := s.workspaceCall(ctx, req, http.MethodPost, "/restore"); err != nil {
+		return nil, err
+	}
+	return &trilixv1.RestoreWorkspaceResponse{}, nil
+}
+
+// workspaceCall runs a REST request on one workspace that answers without a body
+func (s *Server) workspaceCall(ctx context.Context, req *trilixv1.WorkspaceRequest, httpMethod, suffix string) error {
+	if req.WorkspaceId == "" || strings.Contains(req.WorkspaceId, "/") {
+		return invalidArgument("invalid workspace_id %q", req.WorkspaceId)
+	}
+	return s.rest(ctx, httpMethod, "/api/workspaces/"+req.WorkspaceId+suffix, req.UserId, nil, nil).status()
+}
+
+// GetWorkspaceStatus checks a workspace's credentials as GET /api/workspaces/{id}/status
+func (s workspacesService) GetWorkspaceStatus(ctx context.Context, req *trilixv1.WorkspaceRequest) (*trilixv1.WorkspaceStatus, error) {
+	if req.WorkspaceId == "" || strings.Contains(req.WorkspaceId, "/") {
+		return nil, invalidArgument("invalid workspace_id %q", req.WorkspaceId)
+	}
+
+	rec := s.rest(ctx, http.MethodGet, "/api/workspaces/"+req.WorkspaceId+"/status", req.UserId, nil, nil)
+	if err := rec.status(); err != nil {
+		return nil, err
+	}
+	var result struct {
+		WorkspaceID string `json:"workspaceId"`
+		Connected   bool   `json:"connected"`
+		Error       string `json:"error"`
+	}
+	if err := json.Unmarshal(rec.body.Bytes(), &result); err != nil {
+		return nil, status.Errorf(codes.Internal, "unexpected workspace status: %v", err)
+	}
+	return &trilixv1.WorkspaceStatus{WorkspaceId: result.WorkspaceID, Connected: result.Connected, Error: result.Error}, nil
+}
+
+// workspaceMessage converts a REST workspace to its message
+func workspaceMessage(ws handlers.WorkspaceResponse) *trilixv1.Workspace {
+	m := &trilixv1.Workspace{
+		WorkspaceId:   ws.WorkspaceID,
+		WorkspaceName: ws.WorkspaceName,
+		SiteUrl:       ws.SiteURL,
+		JiraUrl:       ws.JiraURL,
+		ConfluenceUrl: ws.ConfluenceURL,
+		Email:         ws.Email,
+		Shared:        ws.Shared,
+		CreatedAt:     ws.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     ws.UpdatedAt.Format(time.RFC3339),
+	}
+	if ws.DeletedAt != nil {
+		m.DeletedAt = ws.DeletedAt.Format(time.RFC3339)
+	}
+	if ws.Policy != nil {
+		policy, _ := json.Marshal(ws.Policy)
+		m.PolicyJson = string(policy)
+	}
+	return m
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// activeWorkspaceKey is the session value holding the active workspace
+const activeWorkspaceKey = "active_workspace"
+
+// ActiveWorkspaceHandler serves set_active_workspace and get_active_workspace, which keep a
+// default workspace for the rest of an MCP session, and fills it in for calls that omit
+// workspace_id
+type ActiveWorkspaceHandler struct {
+	sessions  *mcp.SessionValues
+	credStore storage.CredentialStoreInterface
+}
+
+// NewActiveWorkspaceHandler creates an active workspace handler keeping its state in sessions
+func NewActiveWorkspaceHandler(sessions *mcp.SessionValues, credStore storage.CredentialStoreInterface) *ActiveWorkspaceHandler {
+	return &ActiveWorkspaceHandler{sessions: sessions, credStore: credStore}
+}
+
+// IsActiveWorkspaceTool reports whether a tool is served by the active workspace handler
+func IsActiveWorkspaceTool(name string) bool {
+	return name == "set_active_workspace" || name == "get_active_workspace"
+}
+
+// ListTools returns the active workspace tools
+func (h *ActiveWorkspaceHandler) ListTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "set_active_workspace",
+			Description: "Set the workspace used for the rest of this conversation, so that later tool calls can omit workspace_id. An empty workspace_id clears it.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID (from list_workspaces)",
+					},
+				},
+				"required": []string{"workspace_id"},
+			},
+		},
+		{
+			Name:        "get_active_workspace",
+			Description: "Get the workspace set for this conversation with set_active_workspace, if any",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+	}
+}
+
+// HandleTool handles an active workspace tool call
+func (h *ActiveWorkspaceHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	switch call.Name {
+	case "set_active_workspace":
+		workspaceID, ok := call.Arguments["workspace_id"].(string)
+		if !ok {
+			return fail("workspace_id is required")
+		}
+		if workspaceID != "" {
+			if _, err := storage.GetMemberCredentials(h.credStore, userID, call.OrgID, workspaceID); err != nil {
+				return fail(fmt.Sprintf("workspace not found: %s", workspaceID))
+			}
+		}
+		if err := h.sessions.Set(call.SessionID, userID, activeWorkspaceKey, workspaceID); err != nil {
+			return fail(err.Error())
+		}
+		return jsonResult(map[string]interface{}{"active_workspace": workspaceID})
+	case "get_active_workspace":
+		workspaceID, _ := h.sessions.Get(call.SessionID, userID, activeWorkspaceKey)
+		return jsonResult(map[string]interface{}{"active_workspace": workspaceID})
+	default:
+		return fail(fmt.Sprintf("unknown tool: %s", call.Name))
+	}
+}
+
+// Wrap fills in the session's active workspace for calls that omit workspace_id, in the
+// tools that require one. Those tools' schemas are changed in place so that it is optional.
+func (h *ActiveWorkspaceHandler) Wrap(tools []mcp.Tool, handler func(mcp.ToolCall, string) (mcp.ToolResult, error)) func(mcp.ToolCall, string) (mcp.ToolResult, error) {
+	takesWorkspace := make(map[string]bool)
+	for _, tool := range tools {
+		// Plugin schemas are decoded from JSON, so their lists are []interface{}
+		var required []string
+		switch names := tool.InputSchema["required"].(type) {
+		case []string:
+			required = names
+		case []interface{}:
+			for _, name := range names {
+				if name, ok := name.(string); ok {
+					required = append(required, name)
+				}
+			}
+		}
+
+		optional := make([]string, 0, len(required))
+		for _, name := range required {
+			if name == "workspace_id" {
+				takesWorkspace[tool.Name] = true
+			} else {
+				optional = append(optional, name)
+			}
+		}
+		if takesWorkspace[tool.Name] {
+			tool.InputSchema["required"] = optional
+		}
+	}
+
+	return func(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+		if !takesWorkspace[call.Name] {
+			return handler(call, userID)
+		}
+		if workspaceID, _ := call.Arguments["workspace_id"].(string); workspaceID != "" {
+			return handler(call, userID)
+		}
+		workspaceID, ok := h.sessions.Get(call.SessionID, userID, activeWorkspaceKey)
+		if !ok {
+			message := "workspace_id is required; pass it, or set a default for this conversation with set_active_workspace"
+			return errorResult(requestIDFor(call), message), errors.New(message)
+		}
+
+		// The call's arguments may be shared with the caller, so the call gets its own
+		arguments := make(map[string]interface{}, len(call.Arguments)+1)
+		for k, v := range call.Arguments {
+			arguments[k] = v
+		}
+		arguments["workspace_id"] = workspaceID
+		call.Arguments = arguments
+		return handler(call, userID)
+	}
+}
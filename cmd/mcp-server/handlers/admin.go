@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/broker"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/cache"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AdminHandler serves the admin-only runtime introspection endpoints used by the
+// dashboard's ops panel
+type AdminHandler struct {
+	server    *mcp.Server
+	sseServer *mcp.SSEServer
+	settings  *config.SettingsWatcher
+	busKind   string
+	channel   *amqp.Channel     // nil unless the bus is RabbitMQ
+	queues    map[string]string // "jira"/"confluence" -> request queue name
+	caches    []adminCache
+
+	// Ask the services for their recorded Atlassian calls; nil when they share this process
+	jiraCall       func(models.JiraRequest) (*models.JiraResponse, error)
+	confluenceCall func(models.ConfluenceRequest) (*models.ConfluenceResponse, error)
+}
+
+type adminCache struct {
+	name  string
+	ttl   time.Duration
+	stats func() cache.Stats
+}
+
+// NewAdminHandler creates an admin handler for the given MCP server and its SSE transport
+func NewAdminHandler(server *mcp.Server, sseServer *mcp.SSEServer, settings *config.SettingsWatcher) *AdminHandler {
+	return &AdminHandler{server: server, sseServer: sseServer, settings: settings}
+}
+
+// WithQueues reports the depth of the services' request queues. channel and queues
+// are only used when busKind is amqp.
+func (h *AdminHandler) WithQueues(busKind string, channel *amqp.Channel, queues map[string]string) *AdminHandler {
+	h.busKind = busKind
+	h.channel = channel
+	h.queues = make(map[string]string, len(queues))
+	for name, queue := range queues {
+		if queue != "" {
+			h.queues[name] = queue
+		}
+	}
+	return h
+}
+
+// WithCache reports a cache's size and hit rate under the given name
+func (h *AdminHandler) WithCache(name string, ttl time.Duration, stats func() cache.Stats) *AdminHandler {
+	h.caches = append(h.caches, adminCache{name: name, ttl: ttl, stats: stats})
+	return h
+}
+
+// WithRecordings includes the Atlassian calls recorded by the Jira and Confluence
+// services in /api/admin/recordings. Skip it when the services run in this process
+// (MESSAGE_BUS=memory), whose recordings are already reported.
+func (h *AdminHandler) WithRecordings(jiraCall func(models.JiraRequest) (*models.JiraResponse, error), confluenceCall func(models.ConfluenceRequest) (*models.ConfluenceResponse, error)) *AdminHandler {
+	h.jiraCall = jiraCall
+	h.confluenceCall = confluenceCall
+	return h
+}
+
+// AdminTool is a registered tool as reported by GET /api/admin/tools
+type AdminTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"` // False when excluded by enabled_tools
+}
+
+// AdminQueue is a service queue as reported by GET /api/admin/queues
+type AdminQueue struct {
+	Service string `json:"service"`
+	broker.QueueStats
+	Error string `json:"error,omitempty"`
+}
+
+// AdminCache is a cache as reported by GET /api/admin/caches
+type AdminCache struct {
+	Name string `json:"name"`
+	TTL  string `json:"ttl"`
+	cache.Stats
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// AdminRecordings are the Atlassian calls one process recorded, as reported by
+// GET /api/admin/recordings
+type AdminRecordings struct {
+	Service string                   `json:"service"` // "mcp-server", "jira" or "confluence"
+	Calls   []atlassian.RecordedCall `json:"calls"`
+	Error   string                   `json:"error,omitempty"`
+}
+
+// HandleAdmin handles GET /api/admin/{tools,sessions,queues,caches,errors,recordings}
+func (h *AdminHandler) HandleAdmin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body interface{}
+	switch r.URL.Path {
+	case "/api/admin/tools":
+		body = map[string]interface{}{"tools": h.tools()}
+	case "/api/admin/sessions":
+		sessions := h.sseServer.Sessions()
+		body = map[string]interface{}{"sessions": sessions, "count": len(sessions)}
+	case "/api/admin/queues":
+		body = map[string]interface{}{"bus": h.busKind, "queues": h.queueStats()}
+	case "/api/admin/caches":
+		body = map[string]interface{}{"caches": h.cacheStats()}
+	case "/api/admin/errors":
+		limit := 50
+		if v := r.URL.Query().Get("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 200 {
+				limit = n
+			}
+		}
+		errors := logging.RecentErrors(limit)
+		body = map[string]interface{}{"errors": errors, "count": len(errors)}
+	case "/api/admin/recordings":
+		limit := 50
+		if v := r.URL.Query().Get("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				limit = n
+			}
+		}
+		body = map[string]interface{}{"recordings": h.recordings(r, limit)}
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// tools lists every registered tool, including those disabled by the allowlist
+func (h *AdminHandler) tools() []AdminTool {
+	settings := h.settings.Current()
+	tools := []AdminTool{}
+	for _, tool := range h.server.AllTools() {
+		tools = append(tools, AdminTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Enabled:     settings.ToolEnabled(tool.Name),
+		})
+	}
+	return tools
+}
+
+// queueStats inspects the request queues; empty unless the bus is RabbitMQ
+func (h *AdminHandler) queueStats() []AdminQueue {
+	queues := []AdminQueue{}
+	if h.channel == nil {
+		return queues
+	}
+	for _, service := range []string{"jira", "confluence"} {
+		queue, ok := h.queues[service]
+		if !ok {
+			continue
+		}
+		entry := AdminQueue{Service: service}
+		stats, err := broker.InspectQueue(h.channel, queue)
+		if err != nil {
+			entry.Queue = queue
+			entry.Error = err.Error()
+		} else {
+			entry.QueueStats = stats
+		}
+		queues = append(queues, entry)
+	}
+	return queues
+}
+
+// recordings gathers the calls recorded by this process and by the services. With a
+// load-balanced bus each service answers from whichever replica takes the request.
+func (h *AdminHandler) recordings(r *http.Request, limit int) []AdminRecordings {
+	recordings := []AdminRecordings{{Service: "mcp-server", Calls: atlassian.Recordings(limit)}}
+	params := map[string]interface{}{"limit": limit}
+	requestID := logging.RequestIDFromContext(r.Context())
+
+	if h.jiraCall != nil {
+		entry := AdminRecordings{Service: "jira"}
+		resp, err := h.jiraCall(models.JiraRequest{Action: atlassian.RecordingsAction, Params: params, RequestID: requestID})
+		if err == nil {
+			entry.Calls, entry.Error = decodeRecordings(resp.Success, resp.Data, resp.Error)
+		} else {
+			entry.Error = err.Error()
+		}
+		recordings = append(recordings, entry)
+	}
+	if h.confluenceCall != nil {
+		entry := AdminRecordings{Service: "confluence"}
+		resp, err := h.confluenceCall(models.ConfluenceRequest{Action: atlassian.RecordingsAction, Params: params, RequestID: requestID})
+		if err == nil {
+			entry.Calls, entry.Error = decodeRecordings(resp.Success, resp.Data, resp.Error)
+		} else {
+			entry.Error = err.Error()
+		}
+		recordings = append(recordings, entry)
+	}
+	return recordings
+}
+
+// decodeRecordings reads the calls out of a service's reply to RecordingsAction
+func decodeRecordings(success bool, data any, errInfo *models.ErrorInfo) ([]atlassian.RecordedCall, string) {
+	if !success {
+		if errInfo != nil {
+			return nil, errInfo.Message
+		}
+		return nil, "request failed"
+	}
+	var reply struct {
+		Calls []atlassian.RecordedCall `json:"calls"`
+	}
+	raw, err := json.Marshal(data)
+	if err == nil {
+		err = json.Unmarshal(raw, &reply)
+	}
+	if err != nil {
+		return nil, fmt.Sprintf("invalid reply: %v", err)
+	}
+	return reply.Calls, ""
+}
+
+// cacheStats reports every registered cache
+func (h *AdminHandler) cacheStats() []AdminCache {
+	caches := make([]AdminCache, 0, len(h.caches))
+	for _, c := range h.caches {
+		entry := AdminCache{Name: c.name, TTL: c.ttl.String(), Stats: c.stats()}
+		if lookups := entry.Hits + entry.Misses; lookups > 0 {
+			entry.HitRatio = float64(entry.Hits) / float64(lookups)
+		}
+		caches = append(caches, entry)
+	}
+	return caches
+}
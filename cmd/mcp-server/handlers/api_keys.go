@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+)
+
+// APIKeyHandler handles API key management HTTP endpoints
+type APIKeyHandler struct {
+	keyStore storage.APIKeyStoreInterface
+}
+
+// NewAPIKeyHandler creates a new API key handler. keyStore may be nil when API keys
+// are not supported (file-based storage).
+func NewAPIKeyHandler(keyStore storage.APIKeyStoreInterface) *APIKeyHandler {
+	return &APIKeyHandler{keyStore: keyStore}
+}
+
+// CreateAPIKeyRequest represents the request to create an API key
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes,omitempty"`        // Empty means all scopes the caller has
+	ExpiresInDays int      `json:"expiresInDays,omitempty"` // 0 means the key never expires
+}
+
+// CreateAPIKeyResponse includes the secret, which is only ever returned here
+type CreateAPIKeyResponse struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+// HandleAPIKeys handles /api/keys and /api/keys/{id}
+func (h *APIKeyHandler) HandleAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if h.keyStore == nil {
+		http.Error(w, "API keys require database storage (DATABASE_URL)", http.StatusNotImplemented)
+		return
+	}
+
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	keyID := ""
+	if len(r.URL.Path) > len("/api/keys/") {
+		keyID = r.URL.Path[len("/api/keys/"):]
+	}
+
+	switch {
+	case keyID == "" && r.Method == http.MethodGet:
+		h.handleList(w, userCtx)
+	case keyID == "" && r.Method == http.MethodPost:
+		h.handleCreate(w, r, userCtx)
+	case keyID != "" && r.Method == http.MethodDelete:
+		h.handleRevoke(w, userCtx, keyID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCreate handles POST /api/keys
+func (h *APIKeyHandler) handleCreate(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext) {
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "Missing required field: name", http.StatusBadRequest)
+		return
+	}
+	if req.ExpiresInDays < 0 {
+		http.Error(w, "expiresInDays must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	scopes, err := auth.ParseScopes(strings.Join(req.Scopes, " "))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// A restricted caller cannot mint a key with more access than it has
+	if userCtx.Scopes != nil {
+		if len(scopes) == 0 {
+			scopes = userCtx.Scopes
+		}
+		for _, scope := range scopes {
+			if !userCtx.HasScope(scope) {
+				http.Error(w, fmt.Sprintf("Forbidden: cannot grant the %s scope", scope), http.StatusForbidden)
+				return
+			}
+		}
+	}
+
+	secret, hash, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to generate API key: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	key := models.APIKey{
+		ID:        uuid.New().String(),
+		UserID:    userCtx.UserID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := key.CreatedAt.AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := h.keyStore.CreateAPIKey(&key, hash); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save API key: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateAPIKeyResponse{APIKey: key, Key: secret})
+}
+
+// handleList handles GET /api/keys
+func (h *APIKeyHandler) handleList(w http.ResponseWriter, userCtx *auth.UserContext) {
+	keys, err := h.keyStore.ListAPIKeys(userCtx.UserID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list API keys: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// handleRevoke handles DELETE /api/keys/{id}
+func (h *APIKeyHandler) handleRevoke(w http.ResponseWriter, userCtx *auth.UserContext, keyID string) {
+	if err := h.keyStore.RevokeAPIKey(userCtx.UserID, keyID); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "API key not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to revoke API key: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
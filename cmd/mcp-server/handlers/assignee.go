@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// assigneeSuggestions is what the suggest_assignee action returns
+type assigneeSuggestions struct {
+	Suggestions []struct {
+		AccountID   string `json:"account_id"`
+		DisplayName string `json:"display_name"`
+		OpenIssues  int    `json:"open_issues"`
+	} `json:"suggestions"`
+}
+
+// assignSuggested handles a jira_suggest_assignee call with assign_issue_key: it ranks the
+// project's people as usual, then assigns the issue to the first of them with
+// update_issue, so the assignment is checked against the workspace policy and can be
+// undone like any other update. Ranking only needs jira:read, so jira:write is checked here.
+func (h *JiraHandler) assignSuggested(call mcp.ToolCall, req models.JiraRequest, issueKey string) (mcp.ToolResult, error) {
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(req.RequestID, message), errors.New(message)
+	}
+	caller := &auth.UserContext{UserID: req.UserID, OrgID: call.OrgID, Scopes: call.Scopes}
+	if !caller.HasScope(auth.ScopeJiraWrite) {
+		return fail(fmt.Sprintf("insufficient_scope: assign_issue_key requires the %s scope", auth.ScopeJiraWrite))
+	}
+
+	resp, err := h.callService(req)
+	if err == nil && !resp.Success {
+		err = serviceError(resp.Error)
+	}
+	var ranking assigneeSuggestions
+	if err == nil {
+		err = decodeServiceData(resp.Data, &ranking)
+	}
+	if err != nil {
+		return fail(fmt.Sprintf("failed to suggest an assignee: %v", err))
+	}
+	if len(ranking.Suggestions) == 0 {
+		return fail("no one to assign the issue to")
+	}
+	top := ranking.Suggestions[0]
+
+	params := map[string]interface{}{
+		"issue_key": issueKey,
+		"fields": map[string]interface{}{
+			"assignee": map[string]interface{}{"accountId": top.AccountID},
+		},
+	}
+	if atlassian.IsDryRun(call.Arguments) {
+		params[atlassian.DryRunParam] = true
+	}
+	update, err := h.callService(models.JiraRequest{
+		Action:      "update_issue",
+		WorkspaceID: req.WorkspaceID,
+		UserID:      req.UserID,
+		OrgID:       req.OrgID,
+		Params:      params,
+		RequestID:   req.RequestID,
+	})
+	if err == nil && !update.Success {
+		err = serviceError(update.Error)
+	}
+	if err != nil {
+		return fail(fmt.Sprintf("failed to assign %s to %s: %v", issueKey, top.DisplayName, err))
+	}
+
+	result, ok := resp.Data.(map[string]interface{})
+	if !ok {
+		result = map[string]interface{}{"suggestions": ranking.Suggestions}
+	}
+	assigned := map[string]interface{}{
+		"issue_key":    issueKey,
+		"account_id":   top.AccountID,
+		"display_name": top.DisplayName,
+	}
+	if atlassian.IsDryRun(call.Arguments) {
+		assigned["dry_run"] = update.Data
+	}
+	result["assigned"] = assigned
+	return jsonResult(result)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+)
+
+// AtlassianOAuthHandler handles the /api/atlassian endpoints, where users connect their
+// own Atlassian account for workspaces that call Atlassian as each user (user_oauth)
+type AtlassianOAuthHandler struct {
+	store storage.UserTokenStoreInterface
+	oauth *atlassian.OAuthConfig
+}
+
+// NewAtlassianOAuthHandler creates a new Atlassian OAuth handler. store is nil with
+// file-based storage and oauth is nil without an OAuth app; either way, users cannot connect.
+func NewAtlassianOAuthHandler(store storage.UserTokenStoreInterface, oauth *atlassian.OAuthConfig) *AtlassianOAuthHandler {
+	return &AtlassianOAuthHandler{store: store, oauth: oauth}
+}
+
+// available writes an error and returns false when users cannot connect
+func (h *AtlassianOAuthHandler) available(w http.ResponseWriter) bool {
+	if h.store == nil || h.oauth == nil {
+		http.Error(w, "Connecting Atlassian accounts requires database storage (DATABASE_URL) and an Atlassian OAuth app (ATLASSIAN_OAUTH_CLIENT_ID)", http.StatusNotImplemented)
+		return false
+	}
+	return true
+}
+
+// HandleConnect handles POST /api/atlassian/connect. It returns the Atlassian URL where the
+// caller grants access; Atlassian then sends them back to /api/atlassian/callback.
+func (h *AtlassianOAuthHandler) HandleConnect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.available(w) {
+		return
+	}
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	authorizeURL, err := h.oauth.AuthorizeURL(userCtx.UserID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to start authorization: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"authorize_url": authorizeURL})
+}
+
+// HandleConnection handles /api/atlassian/connection: GET reports whether the caller has
+// connected their account, DELETE forgets their tokens
+func (h *AtlassianOAuthHandler) HandleConnection(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		token, err := h.store.GetUserToken(userCtx.UserID)
+		if err == storage.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"connected": false})
+			return
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to read connection: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"connected":  true,
+			"scope":      token.Scope,
+			"updated_at": token.UpdatedAt,
+		})
+	case http.MethodDelete:
+		if err := h.store.DeleteUserToken(userCtx.UserID); err != nil && err != storage.ErrNotFound {
+			http.Error(w, fmt.Sprintf("Failed to disconnect: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleCallback handles GET /api/atlassian/callback, where Atlassian sends users after
+// they grant access. It needs no authentication: the signed state names the user.
+func (h *AtlassianOAuthHandler) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.available(w) {
+		return
+	}
+
+	query := r.URL.Query()
+	if denied := query.Get("error"); denied != "" {
+		http.Error(w, fmt.Sprintf("Atlassian authorization failed: %s", denied), http.StatusBadRequest)
+		return
+	}
+	userID, err := h.oauth.VerifyState(query.Get("state"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Atlassian authorization failed: %v", err), http.StatusBadRequest)
+		return
+	}
+	code := query.Get("code")
+	if code == "" {
+		http.Error(w, "Atlassian authorization failed: missing code", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.oauth.Exchange(r.Context(), userID, code)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Atlassian token exchange failed", "user_id", userID, "error", err)
+		http.Error(w, fmt.Sprintf("Atlassian authorization failed: %v", err), http.StatusBadGateway)
+		return
+	}
+	if err := h.store.SaveUserToken(token); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save Atlassian tokens: %v", err), http.StatusInternalServerError)
+		return
+	}
+	slog.InfoContext(r.Context(), "Atlassian account connected", "user_id", userID)
+	http.Redirect(w, r, "/workspaces.html?atlassian=connected", http.StatusFound)
+}
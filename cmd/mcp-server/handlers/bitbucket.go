@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// IsBitbucketTool reports whether a tool is a Bitbucket Cloud tool. These are served by
+// the Jira service with the workspace's Atlassian credentials, so JiraHandler handles them.
+func IsBitbucketTool(name string) bool {
+	return strings.HasPrefix(name, "bitbucket_")
+}
+
+// bitbucketWorkspaceProperty is shared by every Bitbucket tool
+var bitbucketWorkspaceProperty = map[string]interface{}{
+	"type":        "string",
+	"description": "Bitbucket workspace slug (defaults to the Atlassian site name, e.g. 'acme' for acme.atlassian.net)",
+}
+
+func bitbucketTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "bitbucket_list_repositories",
+			Description: "List the Bitbucket Cloud repositories of a workspace, most recently updated first",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"bitbucket_workspace": bitbucketWorkspaceProperty,
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Only return repositories whose name contains this text",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of results (up to 100)",
+						"default":     25,
+					},
+				},
+				"required": []string{"workspace_id"},
+			},
+		},
+		{
+			Name:        "bitbucket_list_pull_requests",
+			Description: "List the pull requests of a Bitbucket repository. Each pull request includes jira_issue_keys, the Jira issues mentioned in its title, branch or description.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"bitbucket_workspace": bitbucketWorkspaceProperty,
+					"repo_slug": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository slug",
+					},
+					"state": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"},
+						"description": "Pull request state",
+						"default":     "OPEN",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of results (up to 50)",
+						"default":     25,
+					},
+				},
+				"required": []string{"workspace_id", "repo_slug"},
+			},
+		},
+		{
+			Name:        "bitbucket_get_pull_request",
+			Description: "Get a Bitbucket pull request, including jira_issue_keys, the Jira issues mentioned in its title, branch or description",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"bitbucket_workspace": bitbucketWorkspaceProperty,
+					"repo_slug": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository slug",
+					},
+					"pull_request_id": map[string]interface{}{
+						"type":        "number",
+						"description": "Pull request ID",
+					},
+				},
+				"required": []string{"workspace_id", "repo_slug", "pull_request_id"},
+			},
+		},
+		{
+			Name:        "bitbucket_get_pull_request_comments",
+			Description: "Get the comments on a Bitbucket pull request, including inline code review comments",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"bitbucket_workspace": bitbucketWorkspaceProperty,
+					"repo_slug": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository slug",
+					},
+					"pull_request_id": map[string]interface{}{
+						"type":        "number",
+						"description": "Pull request ID",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of results (up to 100)",
+						"default":     25,
+					},
+				},
+				"required": []string{"workspace_id", "repo_slug", "pull_request_id"},
+			},
+		},
+		{
+			Name:        "bitbucket_list_pipelines",
+			Description: "List the Bitbucket Pipelines runs of a repository, newest first",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"bitbucket_workspace": bitbucketWorkspaceProperty,
+					"repo_slug": map[string]interface{}{
+						"type":        "string",
+						"description": "Repository slug",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of results (up to 100)",
+						"default":     25,
+					},
+				},
+				"required": []string{"workspace_id", "repo_slug"},
+			},
+		},
+	}
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const (
+	defaultChunkSize    = 1500
+	minChunkSize        = 200
+	maxChunkSize        = 8000
+	defaultChunkOverlap = 200
+	defaultChunkPages   = 10
+	maxChunkPages       = 50
+	// chunkSearchPageSize is how many pages each search fetches; Confluence returns
+	// fewer results per request when their bodies are expanded
+	chunkSearchPageSize = 25
+)
+
+// Confluence storage format is XHTML with ac: and ri: elements for macros and resources.
+// These patterns reduce it to plain text.
+var (
+	storageHeadingPattern   = regexp.MustCompile(`(?is)<h([1-6])(?:\s[^>]*)?>(.*?)</h[1-6]>`)
+	storageCDATAPattern     = regexp.MustCompile(`(?s)<!\[CDATA\[(.*?)\]\]>`)
+	storageParameterPattern = regexp.MustCompile(`(?s)<ac:parameter[^>]*>.*?</ac:parameter>`)
+	storageListItemPattern  = regexp.MustCompile(`(?i)<li(?:\s[^>]*)?>(?:\s*<p(?:\s[^>]*)?>)?`)
+	storageCellPattern      = regexp.MustCompile(`(?is)<t[dh](?:\s[^>]*)?>(.*?)</t[dh]>`)
+	storageBlockPattern     = regexp.MustCompile(`(?i)</?(?:p|div|pre|blockquote|table|tbody|thead|tr|ul|ol|li|ac:task|ac:layout-section|ac:layout-cell)(?:\s[^>]*)?>|<br\s*/?>`)
+	storageLineSpacePattern = regexp.MustCompile(`[ \t\x{00a0}]+`)
+)
+
+// chunkSpaceTool is confluence_chunk_space, which splits a space's pages into text
+// chunks for embedding
+func chunkSpaceTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "confluence_chunk_space",
+		Description: "Split the pages of a Confluence space into plain-text chunks for embedding, e.g. to build a vector index of the wiki. Each chunk carries its page ID, title, URL, version and heading path. Returns a batch of pages' chunks with the offset of the next batch, or with export=true writes every chunk to a JSONL file and returns its download link.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"workspace_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Workspace ID",
+				},
+				"space_key": map[string]interface{}{
+					"type":        "string",
+					"description": "Key of the space to chunk",
+				},
+				"chunk_size": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum chunk length in characters (200 to 8000; about 4 characters per token)",
+					"default":     defaultChunkSize,
+				},
+				"overlap": map[string]interface{}{
+					"type":        "number",
+					"description": "Characters each chunk repeats from the end of the previous chunk of the same section (less than half of chunk_size)",
+					"default":     defaultChunkOverlap,
+				},
+				"start": map[string]interface{}{
+					"type":        "number",
+					"description": "Offset of the first page to chunk; pass the previous result's next_start",
+					"default":     0,
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Number of pages to chunk (up to 50); ignored with export",
+					"default":     defaultChunkPages,
+				},
+				"export": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Write the chunks of every page to a JSONL export instead of returning them",
+					"default":     false,
+				},
+			},
+			"required": []string{"workspace_id", "space_key"},
+		},
+	}
+}
+
+// pageChunk is a piece of a page's text with the metadata a vector index needs
+type pageChunk struct {
+	ID          string   `json:"id"` // <page ID>-<index>, stable while the page is unchanged
+	PageID      string   `json:"page_id"`
+	PageTitle   string   `json:"page_title"`
+	PageVersion int      `json:"page_version"`
+	SpaceKey    string   `json:"space_key"`
+	URL         string   `json:"url,omitempty"`
+	Headings    []string `json:"headings,omitempty"` // The headings the chunk is under, outermost first
+	Index       int      `json:"chunk_index"`
+	Text        string   `json:"text"`
+}
+
+// pageSection is the text under one heading of a page
+type pageSection struct {
+	Headings []string
+	Text     string
+}
+
+// handleChunkSpace handles a confluence_chunk_space call
+func (h *ExportHandler) handleChunkSpace(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	spaceKey, _ := call.Arguments["space_key"].(string)
+	if workspaceID == "" || strings.TrimSpace(spaceKey) == "" {
+		return fail("workspace_id and space_key are required")
+	}
+	chunkSize := defaultChunkSize
+	if c, ok := call.Arguments["chunk_size"].(float64); ok && c > 0 {
+		chunkSize = int(c)
+	}
+	if chunkSize < minChunkSize || chunkSize > maxChunkSize {
+		return fail(fmt.Sprintf("chunk_size must be between %d and %d", minChunkSize, maxChunkSize))
+	}
+	overlap := defaultChunkOverlap
+	if o, ok := call.Arguments["overlap"].(float64); ok {
+		overlap = int(o)
+	}
+	if overlap < 0 || overlap*2 >= chunkSize {
+		return fail("overlap must be at least 0 and less than half of chunk_size")
+	}
+	start := 0
+	if s, ok := call.Arguments["start"].(float64); ok && s > 0 {
+		start = int(s)
+	}
+	limit := defaultChunkPages
+	if l, ok := call.Arguments["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	if limit > maxChunkPages {
+		limit = maxChunkPages
+	}
+
+	cql := fmt.Sprintf("space = %s AND type = page ORDER BY created ASC", quoteSearchText(spaceKey))
+	chunksOf := func(search *models.SearchResults, page models.ConfluencePage) []pageChunk {
+		return chunkPage(page, contentURL(search, page), chunkSize, overlap)
+	}
+
+	if export, _ := call.Arguments["export"].(bool); export {
+		return h.writeExport(call, userID, workspaceID, cql, models.ExportFormatJSONL, func(w io.Writer, export *models.Export) error {
+			encoder := json.NewEncoder(w)
+			encoder.SetEscapeHTML(false)
+			for offset := start; ; {
+				search, err := h.searchContent(workspaceID, userID, call.OrgID, requestID, cql, offset, chunkSearchPageSize, "body.storage", "space", "version")
+				if err != nil {
+					return err
+				}
+				for _, page := range search.Results {
+					for _, chunk := range chunksOf(search, page) {
+						if export.Rows == maxExportRows {
+							export.Truncated = true
+							return nil
+						}
+						if err := encoder.Encode(chunk); err != nil {
+							return err
+						}
+						export.Rows++
+					}
+				}
+				offset += len(search.Results)
+				if len(search.Results) == 0 || search.Links.Next == "" {
+					return nil
+				}
+			}
+		})
+	}
+
+	chunks := []pageChunk{}
+	pages := 0
+	more := false
+	for pages < limit {
+		search, err := h.searchContent(workspaceID, userID, call.OrgID, requestID, cql, start+pages,
+			min(chunkSearchPageSize, limit-pages), "body.storage", "space", "version")
+		if err != nil {
+			return fail(fmt.Sprintf("failed to read pages: %v", err))
+		}
+		for _, page := range search.Results {
+			chunks = append(chunks, chunksOf(search, page)...)
+		}
+		pages += len(search.Results)
+		more = search.Links.Next != ""
+		if len(search.Results) == 0 || !more {
+			break
+		}
+	}
+
+	result := map[string]interface{}{
+		"space_key": spaceKey,
+		"start":     start,
+		"pages":     pages,
+		"chunks":    chunks,
+	}
+	if more {
+		result["next_start"] = start + pages
+	}
+	return jsonResult(result)
+}
+
+// chunkPage splits a page into chunks, section by section, so that no chunk spans two
+// headings
+func chunkPage(page models.ConfluencePage, url string, size, overlap int) []pageChunk {
+	chunks := []pageChunk{}
+	for _, section := range pageSections(page.Body.Storage.Value) {
+		for _, text := range splitText(section.Text, size, overlap) {
+			chunks = append(chunks, pageChunk{
+				ID:          page.ID + "-" + strconv.Itoa(len(chunks)),
+				PageID:      page.ID,
+				PageTitle:   page.Title,
+				PageVersion: page.Version.Number,
+				SpaceKey:    page.Space.Key,
+				URL:         url,
+				Headings:    section.Headings,
+				Index:       len(chunks),
+				Text:        text,
+			})
+		}
+	}
+	return chunks
+}
+
+// pageSections splits a page's storage format at its headings and converts each part to
+// plain text. Each section lists the headings it is under, e.g. ["Setup", "Install"] for
+// an h3 "Install" after an h2 "Setup". Sections without text are dropped.
+func pageSections(storage string) []pageSection {
+	sections := []pageSection{}
+	var headings []string
+	var levels []int
+	add := func(fragment string) {
+		if text := storageToText(fragment); text != "" {
+			sections = append(sections, pageSection{Headings: append([]string(nil), headings...), Text: text})
+		}
+	}
+
+	last := 0
+	for _, m := range storageHeadingPattern.FindAllStringSubmatchIndex(storage, -1) {
+		add(storage[last:m[0]])
+		last = m[1]
+
+		level, _ := strconv.Atoi(storage[m[2]:m[3]])
+		for len(levels) > 0 && levels[len(levels)-1] >= level {
+			levels = levels[:len(levels)-1]
+			headings = headings[:len(headings)-1]
+		}
+		title := strings.Join(strings.Fields(storageToText(storage[m[4]:m[5]])), " ")
+		if title != "" {
+			levels = append(levels, level)
+			headings = append(headings, title)
+		}
+	}
+	add(storage[last:])
+	return sections
+}
+
+// storageToText converts storage format to plain text: blocks, list items and table rows
+// become lines, code keeps its text, and macro parameters and markup are dropped
+func storageToText(storage string) string {
+	// Code in CDATA may contain markup-like text, which must survive the tag stripping
+	text := storageCDATAPattern.ReplaceAllStringFunc(storage, func(cdata string) string {
+		return html.EscapeString(storageCDATAPattern.FindStringSubmatch(cdata)[1])
+	})
+	text = storageParameterPattern.ReplaceAllString(text, "")
+	text = storageListItemPattern.ReplaceAllString(text, "\n- ")
+	// A table row becomes one line, its cells separated by " | "
+	text = storageCellPattern.ReplaceAllStringFunc(text, func(cell string) string {
+		return storageBlockPattern.ReplaceAllString(storageCellPattern.FindStringSubmatch(cell)[1], " ") + " | "
+	})
+	text = storageBlockPattern.ReplaceAllString(text, "\n")
+	text = html.UnescapeString(storageTagPattern.ReplaceAllString(text, ""))
+
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(storageLineSpacePattern.ReplaceAllString(line, " "))
+		line = strings.TrimSpace(strings.TrimSuffix(line, " |"))
+		if line != "" && line != "-" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitText splits text into chunks of at most size characters, each starting with the
+// last overlap characters of the one before. Chunks end at a line break, sentence end or
+// space in their second half where possible, and the overlap starts at a word.
+func splitText(text string, size, overlap int) []string {
+	runes := []rune(text)
+	if len(runes) <= size {
+		return []string{text}
+	}
+
+	chunks := []string{}
+	for start := 0; start < len(runes); {
+		end := start + size
+		if end >= len(runes) {
+			chunks = append(chunks, strings.TrimSpace(string(runes[start:])))
+			break
+		}
+
+		cut := end
+		window := string(runes[start+size/2 : end])
+		for _, sep := range []string{"\n", ". ", " "} {
+			if i := strings.LastIndex(window, sep); i >= 0 {
+				cut = start + size/2 + utf8.RuneCountInString(window[:i+len(sep)])
+				break
+			}
+		}
+		if chunk := strings.TrimSpace(string(runes[start:cut])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+
+		next := cut - overlap
+		if overlap > 0 {
+			if i := strings.IndexAny(string(runes[next:cut]), " \n"); i >= 0 {
+				next += utf8.RuneCountInString(string(runes[next:cut])[:i+1])
+			}
+		}
+		start = next
+	}
+	return chunks
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// ConfirmationTokenParam is the argument that confirms a call the server asked to confirm
+const ConfirmationTokenParam = "confirmation_token"
+
+// DefaultConfirmTools need confirmation unless MCP_CONFIRM_TOOLS says otherwise
+var DefaultConfirmTools = []string{"jira_delete_issue", "confluence_delete_page"}
+
+// AlwaysConfirmTools need confirmation whatever MCP_CONFIRM_TOOLS says, since their
+// preview is the only look at what they will change before they change it
+var AlwaysConfirmTools = []string{"jira_bulk_transition"}
+
+// DefaultConfirmationTTL is how long a confirmation token is valid unless
+// MCP_CONFIRMATION_TTL says otherwise
+const DefaultConfirmationTTL = 5 * time.Minute
+
+// confirmationKeyLabel derives the token signing key from API_KEY_ENCRYPTION_KEY
+const confirmationKeyLabel = "trilix confirmation tokens"
+
+// Confirmations makes destructive tools run in two phases: the first call describes what
+// the tool would do and returns a confirmation token, and only a second call with the same
+// arguments and that token runs it. Tokens are signed rather than stored, so any replica
+// sharing the signing key accepts them.
+type Confirmations struct {
+	tools  map[string]bool
+	ttl    time.Duration
+	secret []byte
+}
+
+// NewConfirmations requires confirmation of tools and AlwaysConfirmTools, with tokens valid
+// for ttl and signed with secret
+func NewConfirmations(tools []string, ttl time.Duration, secret []byte) *Confirmations {
+	c := &Confirmations{tools: make(map[string]bool), ttl: ttl, secret: secret}
+	for _, tool := range slices.Concat(tools, AlwaysConfirmTools) {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			c.tools[tool] = true
+		}
+	}
+	return c
+}
+
+// ConfirmationsFromEnv reads MCP_CONFIRM_TOOLS (comma-separated tool names; "none" turns
+// confirmation off) and MCP_CONFIRMATION_TTL (a Go duration such as "5m"). Tokens are
+// signed with a key derived from API_KEY_ENCRYPTION_KEY, so every replica accepts them;
+// without it, only this process does.
+func ConfirmationsFromEnv() *Confirmations {
+	tools := DefaultConfirmTools
+	if v := os.Getenv("MCP_CONFIRM_TOOLS"); v != "" {
+		tools = strings.Split(v, ",")
+		if strings.TrimSpace(v) == "none" {
+			tools = nil
+		}
+	}
+
+	ttl := DefaultConfirmationTTL
+	if v := os.Getenv("MCP_CONFIRMATION_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			ttl = d
+		}
+	}
+
+	if key := os.Getenv("API_KEY_ENCRYPTION_KEY"); key != "" {
+		// The encryption key itself never signs anything a caller sees
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(confirmationKeyLabel))
+		return NewConfirmations(tools, ttl, mac.Sum(nil))
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		slog.Error("failed to create a confirmation signing key; confirmation turned off", "error", err)
+		return nil
+	}
+	return NewConfirmations(tools, ttl, secret)
+}
+
+// Required reports whether calls of a tool, in any version, need confirmation
+func (c *Confirmations) Required(tool string) bool {
+	if c == nil {
+		return false
+	}
+	tool, _, _ = strings.Cut(tool, "@")
+	return c.tools[tool]
+}
+
+// withToken adds the confirmation_token argument to the tools that need confirmation
+func (c *Confirmations) withToken(tools []mcp.Tool) []mcp.Tool {
+	for _, tool := range tools {
+		if !c.Required(tool.Name) {
+			continue
+		}
+		if properties, ok := tool.InputSchema["properties"].(map[string]interface{}); ok {
+			properties[ConfirmationTokenParam] = map[string]interface{}{
+				"type":        "string",
+				"description": "Token from this tool's confirmation request. Call first without it to see what will change, then again with the same arguments and the token to proceed.",
+			}
+		}
+	}
+	return tools
+}
+
+// confirmation is what a confirmation token carries
+type confirmation struct {
+	UserID    string `json:"u"`
+	Tool      string `json:"t"`
+	Arguments string `json:"a"` // Hash of the confirmed arguments
+	ExpiresAt int64  `json:"e"`
+}
+
+// gate answers a call that needs confirmation. Without a token it returns a confirmation
+// request describing the change; with a bad token, an error. It returns nil once the call
+// is confirmed and may run.
+func (c *Confirmations) gate(call mcp.ToolCall, userID string, describe func() (interface{}, error)) (*mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	if token, _ := call.Arguments[ConfirmationTokenParam].(string); token != "" {
+		if err := c.verify(token, call, userID); err != nil {
+			result := errorResult(requestID, err.Error())
+			return &result, err
+		}
+		return nil, nil
+	}
+
+	description, err := describe()
+	if err != nil {
+		result := errorResult(requestID, err.Error())
+		return &result, err
+	}
+	expiresAt := time.Now().Add(c.ttl)
+	token, err := c.sign(confirmation{
+		UserID:    userID,
+		Tool:      call.Name,
+		Arguments: confirmedArguments(call.Arguments),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		result := errorResult(requestID, err.Error())
+		return &result, err
+	}
+
+	result, err := jsonResult(map[string]interface{}{
+		"confirmation_required": true,
+		"confirmation_token":    token,
+		"expires_at":            expiresAt.UTC().Format(time.RFC3339),
+		"tool":                  call.Name,
+		"change":                description,
+		"message": fmt.Sprintf("Nothing was changed yet. To proceed, call %s again with the same arguments and confirmation_token before it expires.",
+			call.Name),
+	})
+	return &result, err
+}
+
+func (c *Confirmations) sign(claims confirmation) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + c.signature(encoded), nil
+}
+
+func (c *Confirmations) verify(token string, call mcp.ToolCall, userID string) error {
+	invalid := errors.New("invalid confirmation_token; call the tool without it to get a new one")
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(c.signature(encoded))) {
+		return invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return invalid
+	}
+	var claims confirmation
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return invalid
+	}
+	if claims.UserID != userID || claims.Tool != call.Name || claims.Arguments != confirmedArguments(call.Arguments) {
+		return errors.New("confirmation_token was issued for a different call; confirm with the same arguments")
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return errors.New("confirmation_token expired; call the tool without it to get a new one")
+	}
+	return nil
+}
+
+func (c *Confirmations) signature(encoded string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// confirmedArguments hashes the arguments a token confirms. The token itself and the
+// idempotency key, which retries may change, are left out.
+func confirmedArguments(arguments map[string]interface{}) string {
+	confirmed := withoutConfirmationToken(arguments)
+	delete(confirmed, storage.IdempotencyKeyParam)
+	data, _ := json.Marshal(confirmed)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// withoutConfirmationToken returns a copy of a call's arguments without the confirmation token
+func withoutConfirmationToken(arguments map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(arguments))
+	for name, value := range arguments {
+		if name != ConfirmationTokenParam {
+			copied[name] = value
+		}
+	}
+	return copied
+}
+
+// describeChange says what a Jira call that needs confirmation would change: the issue a
+// deletion removes, or the requests other tools would send (see withDryRun)
+func (h *JiraHandler) describeChange(req models.JiraRequest) (interface{}, error) {
+	if req.Action != "delete_issue" {
+		preview := req
+		preview.Params = withoutConfirmationToken(req.Params)
+		preview.Params[atlassian.DryRunParam] = true
+		resp, err := h.callService(preview)
+		if err == nil && !resp.Success {
+			err = serviceError(resp.Error)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	}
+
+	lookup := req
+	lookup.Action = "get_issue"
+	lookup.Params = map[string]interface{}{"issue_key": req.Params["issue_key"]}
+	resp, err := h.callService(lookup)
+	if err == nil && !resp.Success {
+		err = serviceError(resp.Error)
+	}
+	var issue models.JiraIssue
+	if err == nil {
+		err = decodeServiceData(resp.Data, &issue)
+	}
+	if err != nil {
+		return nil, err
+	}
+	subtasks, _ := issue.Fields["subtasks"].([]interface{})
+	return map[string]interface{}{
+		"action":     "delete issue",
+		"issue_key":  issue.Key,
+		"summary":    issue.Fields["summary"],
+		"issue_type": fieldName(issue.Fields["issuetype"]),
+		"status":     fieldName(issue.Fields["status"]),
+		"subtasks":   len(subtasks),
+	}, nil
+}
+
+// describeChange says what a Confluence call that needs confirmation would change: the
+// page a deletion removes, or the requests other tools would send (see withDryRun)
+func (h *ConfluenceHandler) describeChange(req models.ConfluenceRequest) (interface{}, error) {
+	if req.Action != "delete_page" {
+		preview := req
+		preview.Params = withoutConfirmationToken(req.Params)
+		preview.Params[atlassian.DryRunParam] = true
+		resp, err := h.callService(preview)
+		if err == nil && !resp.Success {
+			err = serviceError(resp.Error)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	}
+
+	lookup := req
+	lookup.Action = "get_page"
+	lookup.Params = map[string]interface{}{"page_id": req.Params["page_id"]}
+	resp, err := h.callService(lookup)
+	if err == nil && !resp.Success {
+		err = serviceError(resp.Error)
+	}
+	var page models.ConfluencePage
+	if err == nil {
+		err = decodeServiceData(resp.Data, &page)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"action":    "delete page",
+		"page_id":   page.ID,
+		"title":     page.Title,
+		"space_key": page.Space.Key,
+		"version":   page.Version.Number,
+	}, nil
+}
+
+// fieldName returns the name of an issue field such as status or issuetype
+func fieldName(field interface{}) string {
+	if m, ok := field.(map[string]interface{}); ok {
+		name, _ := m["name"].(string)
+		return name
+	}
+	return ""
+}
@@ -5,15 +5,63 @@ import (
 	"fmt"
 	"sync/atomic"
 
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
 )
 
 var requestIDCounter int64
 
+// requestIDFor returns the correlation ID assigned at the HTTP edge, or a
+// process-local one for calls that did not arrive over HTTP (e.g. stdio)
+func requestIDFor(call mcp.ToolCall) string {
+	if call.RequestID != "" {
+		return call.RequestID
+	}
+	return fmt.Sprintf("req_%d", atomic.AddInt64(&requestIDCounter, 1))
+}
+
+// errorResult reports a failed tool call, quoting the request ID so users can
+// reference it when reporting the failure
+func errorResult(requestID, message string) mcp.ToolResult {
+	return mcp.ToolResult{
+		Content: []mcp.ContentBlock{
+			{Type: "text", Text: fmt.Sprintf("Error: %s (request ID: %s)", message, requestID)},
+		},
+		IsError: true,
+	}
+}
+
+// serviceErrorResult reports a tool call the Jira or Confluence service failed. After the
+// message it adds the error's code, HTTP status, whether to retry and how to correct the
+// call as JSON, so that agents can act on them.
+func serviceErrorResult(requestID string, info *models.ErrorInfo) mcp.ToolResult {
+	if info == nil {
+		return errorResult(requestID, "Unknown error")
+	}
+	result := errorResult(requestID, info.Message)
+	detail, err := json.Marshal(struct {
+		Code        string   `json:"code"`
+		HTTPStatus  int      `json:"http_status,omitempty"`
+		Retriable   bool     `json:"retriable"`
+		Suggestions []string `json:"suggestions,omitempty"`
+		Details     any      `json:"details,omitempty"`
+	}{info.Code, info.HTTPStatus, info.Retriable, info.Suggestions, info.Details})
+	if err == nil {
+		result.Content[0].Text += "\n" + string(detail)
+	}
+	return result
+}
+
 // ConfluenceHandler handles Confluence-related MCP tool calls
 type ConfluenceHandler struct {
 	callService func(models.ConfluenceRequest) (*models.ConfluenceResponse, error)
+
+	customTools   []mcp.Tool        // tools.yaml passthrough tools
+	customActions map[string]string // Their actions, by tool name
+
+	confirmations *Confirmations // Destructive tools that run only once confirmed (nil = none)
 }
 
 // NewConfluenceHandler creates a new Confluence handler
@@ -23,8 +71,28 @@ func NewConfluenceHandler(callService func(models.ConfluenceRequest) (*models.Co
 	}
 }
 
+// WithCustomTools offers the confluence_* passthrough tools among defs, which the
+// Confluence service serves from the same tools.yaml
+func (h *ConfluenceHandler) WithCustomTools(defs []config.ToolDefinition) *ConfluenceHandler {
+	h.customTools, h.customActions = customTools(defs, "confluence", h.ListTools())
+	return h
+}
+
+// WithConfirmations makes the tools that need confirmation run only once confirmed
+func (h *ConfluenceHandler) WithConfirmations(confirmations *Confirmations) *ConfluenceHandler {
+	h.confirmations = confirmations
+	return h
+}
+
 // ListTools returns the list of Confluence tools
 func (h *ConfluenceHandler) ListTools() []mcp.Tool {
+	tools := withIdempotencyKey(confluenceTools(), models.ConfluenceMutatingActions, getActionFromToolName)
+	tools = withDryRun(tools, models.ConfluenceMutatingActions, getActionFromToolName)
+	tools = withCacheBypass(tools, models.ConfluenceCacheableActions, getActionFromToolName)
+	return h.confirmations.withToken(append(tools, h.customTools...))
+}
+
+func confluenceTools() []mcp.Tool {
 	return []mcp.Tool{
 		{
 			Name:        "confluence_get_page",
@@ -63,10 +131,34 @@ func (h *ConfluenceHandler) ListTools() []mcp.Tool {
 						"description": "Maximum number of results",
 						"default":     10,
 					},
+					"summarize": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return one compact line per page (ID | title | space | status | last editor | updated) instead of full page objects; use for overviews",
+						"default":     false,
+					},
 				},
 				"required": []string{"workspace_id", "query"},
 			},
 		},
+		{
+			Name:        "confluence_search_all_workspaces",
+			Description: "Run a CQL search in every workspace you can use, several at a time, and merge the results, each tagged with its workspace. Useful for finding content across several client wikis.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "CQL search query",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of results per workspace",
+						"default":     10,
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
 		{
 			Name:        "confluence_create_page",
 			Description: "Create a new page in Confluence",
@@ -148,7 +240,7 @@ func (h *ConfluenceHandler) ListTools() []mcp.Tool {
 		},
 		{
 			Name:        "confluence_update_page",
-			Description: "Update an existing Confluence page",
+			Description: "Update an existing Confluence page: replace its body, or append to or replace one section under a heading. Pass expected_version to fail instead of overwriting edits made since you read the page.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -166,12 +258,86 @@ func (h *ConfluenceHandler) ListTools() []mcp.Tool {
 					},
 					"body": map[string]interface{}{
 						"type":        "string",
-						"description": "New page body content (storage format)",
+						"description": "New page body content (storage format); with a section mode, the section's new or added content",
+					},
+					"mode": map[string]interface{}{
+						"type":        "string",
+						"description": "replace (the whole body), append_section (add body to the end of section) or replace_section (replace the content under section's heading)",
+						"enum":        []string{"replace", "append_section", "replace_section"},
+						"default":     "replace",
+					},
+					"section": map[string]interface{}{
+						"type":        "string",
+						"description": "Heading text of the section to change (required with a section mode); the section includes its subsections",
+					},
+					"expected_version": map[string]interface{}{
+						"type":        "number",
+						"description": "Version the change is based on; the update fails with CONFLICT if the page is at another version",
 					},
 				},
 				"required": []string{"workspace_id", "page_id", "body"},
 			},
 		},
+		{
+			Name:        "confluence_append_to_page",
+			Description: "Add content to the end of a Confluence page, or to the end of the section under a heading, without resending the rest of the page",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"page_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Page ID to update",
+					},
+					"heading": map[string]interface{}{
+						"type":        "string",
+						"description": "Heading text of the section to add to (optional; the end of the page when omitted)",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "Content to add (storage format)",
+					},
+					"expected_version": map[string]interface{}{
+						"type":        "number",
+						"description": "Version the change is based on; the update fails with CONFLICT if the page is at another version",
+					},
+				},
+				"required": []string{"workspace_id", "page_id", "content"},
+			},
+		},
+		{
+			Name:        "confluence_replace_section",
+			Description: "Replace the content under one heading of a Confluence page, keeping the heading and the rest of the page",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"page_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Page ID to update",
+					},
+					"heading": map[string]interface{}{
+						"type":        "string",
+						"description": "Heading text of the section to replace; the section includes its subsections",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "New content of the section (storage format); empty clears it",
+					},
+					"expected_version": map[string]interface{}{
+						"type":        "number",
+						"description": "Version the change is based on; the update fails with CONFLICT if the page is at another version",
+					},
+				},
+				"required": []string{"workspace_id", "page_id", "heading", "content"},
+			},
+		},
 		{
 			Name:        "confluence_delete_page",
 			Description: "Delete a Confluence page",
@@ -357,13 +523,186 @@ func (h *ConfluenceHandler) ListTools() []mcp.Tool {
 				"required": []string{"workspace_id", "page_id"},
 			},
 		},
+		{
+			Name:        "confluence_insert_diagram",
+			Description: "Render a Mermaid or PlantUML diagram to SVG or PNG, attach it to a Confluence page and show it in the page body. Inserting again under the same file name replaces the image. Requires a Kroki server (KROKI_URL).",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"page_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Page ID",
+					},
+					"source": map[string]interface{}{
+						"type":        "string",
+						"description": "Diagram source code",
+					},
+					"language": map[string]interface{}{
+						"type":        "string",
+						"description": "Diagram language",
+						"enum":        []string{"mermaid", "plantuml"},
+					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "Image format",
+						"enum":        []string{"svg", "png"},
+						"default":     "svg",
+					},
+					"file_name": map[string]interface{}{
+						"type":        "string",
+						"description": "Attachment file name; defaults to one derived from the source",
+					},
+					"position": map[string]interface{}{
+						"type":        "string",
+						"description": "Where to add the image in the page body",
+						"enum":        []string{"start", "end"},
+						"default":     "end",
+					},
+					"alt": map[string]interface{}{
+						"type":        "string",
+						"description": "Alternative text for the image",
+					},
+					"width": map[string]interface{}{
+						"type":        "number",
+						"description": "Display width in pixels",
+					},
+				},
+				"required": []string{"workspace_id", "page_id", "source", "language"},
+			},
+		},
+		{
+			Name:        "confluence_import_url",
+			Description: "Create a Confluence page from an external web page or Markdown document, e.g. to archive a doc into the wiki. The page's content is sanitized and converted to storage format, its images are downloaded and attached to the new page, and a line linking to the source is added at the top.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"source_url": map[string]interface{}{
+						"type":        "string",
+						"description": "http or https URL of the page to import",
+					},
+					"space_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Space key",
+					},
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "Page title; defaults to the source's title",
+					},
+					"parent_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Parent page ID (optional)",
+					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "How to read the source; auto goes by its content type",
+						"enum":        []string{"auto", "html", "markdown"},
+						"default":     "auto",
+					},
+					"download_images": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Attach the source's images to the page; when false they are linked from their original address",
+						"default":     true,
+					},
+				},
+				"required": []string{"workspace_id", "source_url", "space_key"},
+			},
+		},
+		{
+			Name:        "confluence_content_audit",
+			Description: "Audit a Confluence space for cleanup: pages not updated in a number of months, pages with nearly the same title, and orphaned top-level pages that no other page links to",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"space_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Space key",
+					},
+					"stale_months": map[string]interface{}{
+						"type":        "number",
+						"description": "Flag pages not updated in this many months",
+						"default":     12,
+					},
+					"title_similarity": map[string]interface{}{
+						"type":        "number",
+						"description": "How similar two titles must be, from 0 to 1, to be flagged as near duplicates",
+						"default":     0.85,
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of pages or groups listed per finding",
+						"default":     100,
+					},
+				},
+				"required": []string{"workspace_id", "space_key"},
+			},
+		},
+		{
+			Name:        "confluence_get_page_links",
+			Description: "Get the links of a Confluence page as a graph of pages and edges: the pages it links to, broken and external links, and the pages that link to it",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"page_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Page ID",
+					},
+					"direction": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"outgoing", "incoming", "both"},
+						"description": "Which links to get",
+						"default":     "both",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of pages mentioning the title to check for incoming links",
+						"default":     50,
+					},
+				},
+				"required": []string{"workspace_id", "page_id"},
+			},
+		},
+		{
+			Name:        "confluence_get_share_link",
+			Description: "Get the links to share a Confluence page (full URL and short tiny link) and who can open them: everyone who can view the space, or only the users and groups named by view restrictions on the page or its ancestors. Check it before posting a link in chat, to warn when recipients will not have access.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"page_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Page ID",
+					},
+				},
+				"required": []string{"workspace_id", "page_id"},
+			},
+		},
 	}
 }
 
 // HandleTool handles a Confluence tool call
 func (h *ConfluenceHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	// confluence_search_all_workspaces runs in every workspace
 	workspaceID, ok := call.Arguments["workspace_id"].(string)
-	if !ok {
+	if !ok && call.Name != "confluence_search_all_workspaces" {
 		return mcp.ToolResult{
 			Content: []mcp.ContentBlock{
 				{Type: "text", Text: "Error: workspace_id is required"},
@@ -376,41 +715,43 @@ func (h *ConfluenceHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.To
 		Action:      getActionFromToolName(call.Name),
 		WorkspaceID: workspaceID,
 		UserID:      userID,
+		OrgID:       call.OrgID,
 		Params:      call.Arguments,
-		RequestID:   fmt.Sprintf("req_%d", atomic.AddInt64(&requestIDCounter, 1)),
+		RequestID:   requestIDFor(call),
+	}
+	if action, ok := h.customActions[call.Name]; ok {
+		req.Action = action
+	}
+
+	// Destructive tools first describe the change and run once the caller confirms it
+	if h.confirmations.Required(call.Name) && !atlassian.IsDryRun(call.Arguments) {
+		if result, err := h.confirmations.gate(call, userID, func() (interface{}, error) { return h.describeChange(req) }); result != nil {
+			return *result, err
+		}
+		req.Params = withoutConfirmationToken(req.Params)
 	}
 
 	resp, err := h.callService(req)
 	if err != nil {
-		return mcp.ToolResult{
-			Content: []mcp.ContentBlock{
-				{Type: "text", Text: fmt.Sprintf("Error: %v", err)},
-			},
-			IsError: true,
-		}, err
+		return errorResult(req.RequestID, err.Error()), err
 	}
 
 	if !resp.Success {
-		errorMsg := "Unknown error"
-		if resp.Error != nil {
-			errorMsg = resp.Error.Message
-		}
-		return mcp.ToolResult{
-			Content: []mcp.ContentBlock{
-				{Type: "text", Text: fmt.Sprintf("Error: %s", errorMsg)},
-			},
-			IsError: true,
-		}, fmt.Errorf(errorMsg)
+		return serviceErrorResult(req.RequestID, resp.Error), serviceError(resp.Error)
 	}
 
 	// Convert response to JSON string
 	resultJSON, _ := json.MarshalIndent(resp.Data, "", "  ")
 
-	return mcp.ToolResult{
+	result := mcp.ToolResult{
 		Content: []mcp.ContentBlock{
 			{Type: "text", Text: string(resultJSON)},
 		},
-	}, nil
+	}
+	if resp.Cached != nil {
+		result.Content = append(result.Content, cacheHint(resp.Cached))
+	}
+	return result, nil
 }
 
 func getActionFromToolName(toolName string) string {
@@ -419,6 +760,8 @@ func getActionFromToolName(toolName string) string {
 		return "get_page"
 	case "confluence_search":
 		return "search"
+	case "confluence_search_all_workspaces":
+		return "search_all_workspaces"
 	case "confluence_create_page":
 		return "create_page"
 	case "confluence_copy_page":
@@ -427,6 +770,10 @@ func getActionFromToolName(toolName string) string {
 		return "list_spaces"
 	case "confluence_update_page":
 		return "update_page"
+	case "confluence_append_to_page":
+		return "append_to_page"
+	case "confluence_replace_section":
+		return "replace_section"
 	case "confluence_delete_page":
 		return "delete_page"
 	case "confluence_get_page_children":
@@ -445,6 +792,16 @@ func getActionFromToolName(toolName string) string {
 		return "get_space"
 	case "confluence_get_attachments":
 		return "get_attachments"
+	case "confluence_insert_diagram":
+		return "insert_diagram"
+	case "confluence_import_url":
+		return "import_url"
+	case "confluence_content_audit":
+		return "content_audit"
+	case "confluence_get_page_links":
+		return "get_page_links"
+	case "confluence_get_share_link":
+		return "get_share_link"
 	default:
 		return ""
 	}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// connectWorkspaceAttempts bounds how often connect_workspace asks for the credentials again
+// after Atlassian rejects them
+const connectWorkspaceAttempts = 3
+
+// connectWorkspaceFields describes the form fields of connect_workspace, in the order they are asked
+var connectWorkspaceFields = []struct {
+	name     string
+	title    string
+	detail   string
+	format   string
+	required bool
+}{
+	{"site_url", "Site URL", "Your Atlassian site, e.g. https://your-company.atlassian.net", "uri", true},
+	{"email", "Email", "The email address of your Atlassian account", "email", true},
+	{"api_token", "API token", "Create one at https://id.atlassian.com/manage-profile/security/api-tokens", "", true},
+	{"workspace_name", "Workspace name", "A name to tell this workspace apart (defaults to the site URL)", "", false},
+}
+
+// connectWorkspaceForm holds the answers collected so far
+type connectWorkspaceForm map[string]string
+
+// fill takes the non-empty string values of arguments or form content
+func (f connectWorkspaceForm) fill(values map[string]interface{}) {
+	for _, field := range connectWorkspaceFields {
+		if value, _ := values[field.name].(string); strings.TrimSpace(value) != "" {
+			f[field.name] = strings.TrimSpace(value)
+		}
+	}
+}
+
+// missing lists the required fields without an answer
+func (f connectWorkspaceForm) missing() []string {
+	var missing []string
+	for _, field := range connectWorkspaceFields {
+		if field.required && f[field.name] == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	return missing
+}
+
+// schema is the elicitation schema asking for fields, with the answers so far as defaults
+func (f connectWorkspaceForm) schema(fields []string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	for _, field := range connectWorkspaceFields {
+		if !slices.Contains(fields, field.name) {
+			continue
+		}
+		property := map[string]interface{}{
+			"type":        "string",
+			"title":       field.title,
+			"description": field.detail,
+		}
+		if field.format != "" {
+			property["format"] = field.format
+		}
+		if value := f[field.name]; value != "" && field.name != "api_token" {
+			property["default"] = value
+		}
+		properties[field.name] = property
+		if field.required {
+			required = append(required, field.name)
+		}
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// normalizeSiteURL adds the https scheme a bare host name lacks and drops trailing slashes
+func normalizeSiteURL(siteURL string) (string, error) {
+	if !strings.Contains(siteURL, "://") {
+		siteURL = "https://" + siteURL
+	}
+	parsed, err := url.Parse(siteURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return "", fmt.Errorf("%q is not a site URL, e.g. https://your-company.atlassian.net", siteURL)
+	}
+	return strings.TrimRight(siteURL, "/"), nil
+}
+
+// handleConnectWorkspace saves a workspace from the arguments, asking the user for the
+// missing ones (and again for rejected credentials) when the client supports elicitation
+func (h *ManagementHandler) handleConnectWorkspace(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	form := connectWorkspaceForm{}
+	form.fill(call.Arguments)
+
+	// Ask for the optional name along with the first form
+	ask := form.missing()
+	if len(ask) > 0 && form["workspace_name"] == "" {
+		ask = append(ask, "workspace_name")
+	}
+	problem := ""
+	for attempt := 0; ; attempt++ {
+		if len(ask) == 0 {
+			siteURL, err := normalizeSiteURL(form["site_url"])
+			if err != nil {
+				problem = err.Error()
+			} else if err := h.validator.ValidateToken(siteURL, form["email"], form["api_token"]); err != nil {
+				problem = "Atlassian rejected the credentials: " + err.Error()
+			} else {
+				form["site_url"] = siteURL
+				break
+			}
+			// Rejected tokens are not offered again
+			delete(form, "api_token")
+			ask = []string{"site_url", "email", "api_token"}
+		}
+
+		if call.Elicit == nil {
+			message := problem
+			if message == "" {
+				message = fmt.Sprintf("missing %s; this client cannot prompt for them, so pass them as arguments or add the workspace in the web UI (workspaces.html)", strings.Join(form.missing(), ", "))
+			}
+			return errorResult(requestID, message), errors.New(message)
+		}
+		if attempt == connectWorkspaceAttempts {
+			message := fmt.Sprintf("workspace not connected after %d attempts: %s", attempt, problem)
+			return errorResult(requestID, message), errors.New(message)
+		}
+
+		message := "Connect an Atlassian workspace. The API token is sent to the Trilix server only, not to the assistant."
+		if problem != "" {
+			message = problem + ". Please check the site URL, email and API token."
+		}
+		answer, err := call.Elicit(message, form.schema(ask))
+		if err != nil {
+			message := fmt.Sprintf("could not ask for the workspace details: %v", err)
+			return errorResult(requestID, message), errors.New(message)
+		}
+		if !answer.Accepted() {
+			return jsonResult(map[string]interface{}{
+				"status":  "cancelled",
+				"message": "No workspace was connected.",
+			})
+		}
+		form.fill(answer.Content)
+		ask = form.missing()
+		if len(ask) > 0 {
+			problem = fmt.Sprintf("Missing %s", strings.Join(ask, ", "))
+		}
+	}
+
+	workspaceName := form["workspace_name"]
+	if workspaceName == "" {
+		workspaceName = form["site_url"]
+	}
+	now := time.Now()
+	cred := &models.AtlassianCredential{
+		UserID:        userID,
+		WorkspaceID:   uuid.New().String(),
+		WorkspaceName: workspaceName,
+		AtlassianURL:  form["site_url"],
+		Email:         form["email"],
+		APIToken:      form["api_token"],
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := h.credStore.SaveCredentials(cred); err != nil {
+		message := fmt.Sprintf("failed to save credentials: %v", err)
+		return errorResult(requestID, message), errors.New(message)
+	}
+	h.InvalidateUser(userID)
+
+	// The token is never returned
+	return jsonResult(WorkspaceResponse{
+		WorkspaceID:   cred.WorkspaceID,
+		WorkspaceName: cred.WorkspaceName,
+		SiteURL:       cred.AtlassianURL,
+		Email:         cred.Email,
+		CreatedAt:     cred.CreatedAt,
+		UpdatedAt:     cred.UpdatedAt,
+	})
+}
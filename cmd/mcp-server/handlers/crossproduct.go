@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// CrossProductHandler handles the tools that combine Jira and Confluence. They call the
+// Jira and Confluence services through their handlers, so each product's workspace
+// policy still applies to its part of the work.
+type CrossProductHandler struct {
+	jira       *JiraHandler
+	confluence *ConfluenceHandler
+}
+
+// NewCrossProductHandler creates a new cross-product handler
+func NewCrossProductHandler(jira *JiraHandler, confluence *ConfluenceHandler) *CrossProductHandler {
+	return &CrossProductHandler{
+		jira:       jira,
+		confluence: confluence,
+	}
+}
+
+// IsCrossProductTool reports whether a tool uses both Jira and Confluence
+func IsCrossProductTool(name string) bool {
+	switch name {
+	case "search_atlassian", "generate_release_notes", "create_issues_from_page", "sync_labels", "workspace_rate_status":
+		return true
+	}
+	return false
+}
+
+// ListTools returns the cross-product tools
+func (h *CrossProductHandler) ListTools() []mcp.Tool {
+	return []mcp.Tool{searchTool(), releaseNotesTool(), createIssuesFromPageTool(), syncLabelsTool(), workspaceRateStatusTool()}
+}
+
+// HandleTool handles a cross-product tool call
+func (h *CrossProductHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	switch call.Name {
+	case "search_atlassian":
+		return h.handleSearch(call, userID)
+	case "generate_release_notes":
+		return h.handleReleaseNotes(call, userID)
+	case "create_issues_from_page":
+		return h.handleCreateIssuesFromPage(call, userID)
+	case "sync_labels":
+		return h.handleSyncLabels(call, userID)
+	case "workspace_rate_status":
+		return h.handleWorkspaceRateStatus(call, userID)
+	default:
+		return mcp.ToolResult{
+			Content: []mcp.ContentBlock{
+				{Type: "text", Text: fmt.Sprintf("Unknown tool: %s", call.Name)},
+			},
+			IsError: true,
+		}, fmt.Errorf("unknown tool: %s", call.Name)
+	}
+}
+
+// jsonResult returns v as an indented JSON tool result
+func jsonResult(v interface{}) (mcp.ToolResult, error) {
+	resultJSON, _ := json.MarshalIndent(v, "", "  ")
+
+	return mcp.ToolResult{
+		Content: []mcp.ContentBlock{
+			{Type: "text", Text: string(resultJSON)},
+		},
+	}, nil
+}
+
+// serviceError turns a failed service response into an error
+func serviceError(info *models.ErrorInfo) error {
+	if info == nil {
+		return errors.New("unknown error")
+	}
+	return errors.New(info.Message)
+}
+
+// decodeServiceData converts a service response's data into a typed result
+func decodeServiceData(data interface{}, v interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// listIssues runs the Jira service's list_issues action
+func (h *CrossProductHandler) listIssues(req models.JiraRequest) ([]models.JiraIssue, error) {
+	req.Action = "list_issues"
+	resp, err := h.jira.callService(req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, serviceError(resp.Error)
+	}
+
+	var search models.SearchResponse
+	if err := decodeServiceData(resp.Data, &search); err != nil {
+		return nil, fmt.Errorf("unexpected Jira search response: %w", err)
+	}
+	return search.Issues, nil
+}
+
+// callConfluence runs a Confluence service action and decodes its result into v
+func (h *CrossProductHandler) callConfluence(req models.ConfluenceRequest, v interface{}) error {
+	resp, err := h.confluence.callService(req)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return serviceError(resp.Error)
+	}
+	if err := decodeServiceData(resp.Data, v); err != nil {
+		return fmt.Errorf("unexpected Confluence %s response: %w", req.Action, err)
+	}
+	return nil
+}
+
+// issueBrowseURL derives an issue's web URL from its REST URL (self), which is on the site
+func issueBrowseURL(issue models.JiraIssue) string {
+	self, err := url.Parse(issue.Self)
+	if err != nil || self.Host == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s/browse/%s", self.Scheme, self.Host, issue.Key)
+}
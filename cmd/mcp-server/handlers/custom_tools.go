@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// customTools returns the tools.yaml tools of a service as MCP tools, and the action each
+// is sent to the service as. Definitions that would replace a built-in tool are skipped.
+func customTools(defs []config.ToolDefinition, service string, builtIn []mcp.Tool) ([]mcp.Tool, map[string]string) {
+	taken := make(map[string]bool, len(builtIn))
+	for _, tool := range builtIn {
+		taken[tool.Name] = true
+	}
+
+	var tools []mcp.Tool
+	actions := make(map[string]string)
+	for _, def := range defs {
+		if def.Service() != service {
+			continue
+		}
+		if taken[def.Name] {
+			slog.Warn("tools.yaml tool ignored: a built-in tool has its name", "tool", def.Name)
+			continue
+		}
+		tools = append(tools, mcp.Tool{
+			Name:        def.Name,
+			Description: def.Description,
+			InputSchema: def.InputSchema(),
+		})
+		actions[def.Name] = def.Action()
+	}
+	return tools, actions
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/broker"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetterHandler handles the admin-only endpoints for inspecting and replaying
+// requests the Jira and Confluence services gave up on
+type DeadLetterHandler struct {
+	channel *amqp.Channel
+	queues  map[string]string // "jira"/"confluence" -> request queue name
+}
+
+// NewDeadLetterHandler creates a dead-letter handler. queues maps the service names
+// accepted in the API to their request queues; channel may be nil without RabbitMQ.
+func NewDeadLetterHandler(channel *amqp.Channel, queues map[string]string) *DeadLetterHandler {
+	known := make(map[string]string, len(queues))
+	for name, queue := range queues {
+		if queue != "" {
+			known[name] = queue
+		}
+	}
+	return &DeadLetterHandler{channel: channel, queues: known}
+}
+
+// ReplayDeadLettersRequest selects dead-lettered requests to replay
+type ReplayDeadLettersRequest struct {
+	Queue string   `json:"queue"`
+	IDs   []string `json:"ids"` // Empty replays every dead-lettered request in the queue
+}
+
+// HandleDeadLetters handles GET /api/admin/dead-letters and POST /api/admin/dead-letters/replay
+func (h *DeadLetterHandler) HandleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if h.channel == nil {
+		http.Error(w, "RabbitMQ is not connected", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/api/admin/dead-letters" && r.Method == http.MethodGet:
+		h.handleList(w, r)
+	case r.URL.Path == "/api/admin/dead-letters/replay" && r.Method == http.MethodPost:
+		h.handleReplay(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleList handles GET /api/admin/dead-letters?queue=jira&limit=50
+func (h *DeadLetterHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	queue, ok := h.queues[r.URL.Query().Get("queue")]
+	if !ok {
+		http.Error(w, "queue must be one of: jira, confluence", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 500 {
+			limit = n
+		}
+	}
+
+	letters, err := broker.ListDeadLetters(h.channel, queue, limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read dead-letter queue: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"dead_letters": letters,
+		"count":        len(letters),
+	})
+}
+
+// handleReplay handles POST /api/admin/dead-letters/replay
+func (h *DeadLetterHandler) handleReplay(w http.ResponseWriter, r *http.Request) {
+	var req ReplayDeadLettersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	queue, ok := h.queues[req.Queue]
+	if !ok {
+		http.Error(w, "queue must be one of: jira, confluence", http.StatusBadRequest)
+		return
+	}
+
+	replayed, err := broker.ReplayDeadLetters(h.channel, queue, req.IDs)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to replay dead letters (%d replayed): %v", replayed, err), http.StatusInternalServerError)
+		return
+	}
+
+	userID := ""
+	if userCtx, ok := auth.ExtractUserFromContext(r.Context()); ok {
+		userID = userCtx.UserID
+	}
+	slog.InfoContext(r.Context(), "AUDIT dead letters replayed", "queue", queue, "replayed", replayed, "requested", len(req.IDs), "user_id", userID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"replayed": replayed})
+}
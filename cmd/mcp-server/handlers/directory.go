@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// IsAdminTool reports whether a tool is a user or group directory tool. They are served
+// by the Jira service; without a workspace_id they query every workspace of the caller.
+func IsAdminTool(name string) bool {
+	return strings.HasPrefix(name, "admin_")
+}
+
+func directoryTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "admin_list_users",
+			Description: "List or search the users of Atlassian sites with the Jira products they can access (products) and their groups, e.g. to check whether someone has a Jira license. Omit workspace_id to search every workspace.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID; omit to query all of your workspaces",
+					},
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Name or email to search for; omit to list users",
+					},
+					"include_access": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Include each user's products and groups (one extra call per user)",
+						"default":     true,
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of users per workspace (up to 100)",
+						"default":     25,
+					},
+				},
+			},
+		},
+		{
+			Name:        "admin_list_groups",
+			Description: "List or search the groups of Atlassian sites, optionally with their members. Omit workspace_id to search every workspace.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID; omit to query all of your workspaces",
+					},
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Text the group name contains; omit to list groups",
+					},
+					"include_members": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Include each group's active members",
+						"default":     false,
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of groups (and members per group) per workspace (up to 100)",
+						"default":     25,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// withDryRun adds the optional dry_run argument to the tools whose actions change data.
+// Tools that already preview without the services, e.g. issue templates, keep their own.
+func withDryRun(tools []mcp.Tool, mutating map[string]bool, actionFor func(string) string) []mcp.Tool {
+	for _, tool := range tools {
+		if !mutating[actionFor(tool.Name)] {
+			continue
+		}
+		properties, ok := tool.InputSchema["properties"].(map[string]interface{})
+		if !ok || properties[atlassian.DryRunParam] != nil {
+			continue
+		}
+		properties[atlassian.DryRunParam] = map[string]interface{}{
+			"type":        "boolean",
+			"description": "Validate the change and return the exact requests it would send to Atlassian, without making it",
+			"default":     false,
+		}
+	}
+	return tools
+}
@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// defaultExportSnapshot names the snapshot of calls that do not name one
+const defaultExportSnapshot = "default"
+
+// How an exported issue or page changed since the last run
+const (
+	exportChangeAdded   = "added"
+	exportChangeUpdated = "updated"
+	exportChangeRemoved = "removed" // No longer matches the query, or was deleted
+)
+
+// exportChangesTools returns the jira_export_changes and confluence_export_changes tools
+func exportChangesTools(formatProperty, maxRowsProperty map[string]interface{}) []mcp.Tool {
+	snapshotProperties := map[string]interface{}{
+		"snapshot": map[string]interface{}{
+			"type":        "string",
+			"description": "Name of the snapshot to compare with, to keep separate snapshots of the same query (e.g. 'backup' and 'rag-index')",
+			"default":     defaultExportSnapshot,
+		},
+		"reset": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Ignore the previous snapshot and export every result, e.g. to rebuild a backup",
+			"default":     false,
+		},
+	}
+	withSnapshot := func(properties map[string]interface{}) map[string]interface{} {
+		for name, property := range snapshotProperties {
+			properties[name] = property
+		}
+		return properties
+	}
+
+	return []mcp.Tool{
+		{
+			Name:        "jira_export_changes",
+			Description: "Export only the Jira issues matching a JQL query that were added, updated or removed since the previous run of the same query, to a CSV or JSONL file, e.g. for incremental backups or refreshing a search index. The first run exports every issue. Returns a download link that expires.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": withSnapshot(map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"jql": map[string]interface{}{
+						"type":        "string",
+						"description": "JQL selecting the issues (e.g., 'project = PROJ ORDER BY key')",
+					},
+					"fields": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Fields to export, as CSV columns after the change and issue key (defaults to those of jira_export_issues)",
+					},
+					"format":   formatProperty,
+					"max_rows": maxRowsProperty,
+				}),
+				"required": []string{"workspace_id", "jql"},
+			},
+		},
+		{
+			Name:        "confluence_export_changes",
+			Description: "Export only the Confluence pages or blog posts matching a CQL query that were added, edited or removed since the previous run of the same query, to a CSV or JSONL file, e.g. to re-embed just the changed pages of a RAG index. The first run exports every page. Returns a download link that expires.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": withSnapshot(map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"cql": map[string]interface{}{
+						"type":        "string",
+						"description": "CQL selecting the content (e.g., 'space = DOCS AND type = page')",
+					},
+					"format":   formatProperty,
+					"max_rows": maxRowsProperty,
+				}),
+				"required": []string{"workspace_id", "cql"},
+			},
+		},
+	}
+}
+
+// exportDiff compares the results of a query with its snapshot from the last run
+type exportDiff struct {
+	previous map[string]string // Fingerprints from the last run
+	current  map[string]string // Fingerprints to save for the next run
+	added    int
+	updated  int
+	removed  int
+	scanned  int
+	complete bool // Every result was compared, so those missing were removed
+}
+
+func newExportDiff(previous map[string]string) *exportDiff {
+	if previous == nil {
+		previous = map[string]string{}
+	}
+	return &exportDiff{previous: previous, current: make(map[string]string, len(previous))}
+}
+
+// observe records a result's fingerprint and returns how it changed, "" when it did not
+func (d *exportDiff) observe(id, fingerprint string) string {
+	d.scanned++
+	old, known := d.previous[id]
+	d.current[id] = fingerprint
+	switch {
+	case !known:
+		d.added++
+		return exportChangeAdded
+	case old != fingerprint:
+		d.updated++
+		return exportChangeUpdated
+	}
+	return ""
+}
+
+// finish writes the results that were removed, while rows allows, and carries over what
+// the next run still has to report: removals not written, and with an incomplete
+// comparison, the fingerprints of the results not compared
+func (d *exportDiff) finish(export *models.Export, maxRows int, writeRemoved func(id string) error) error {
+	for _, id := range slices.Sorted(maps.Keys(d.previous)) {
+		if _, seen := d.current[id]; seen {
+			continue
+		}
+		fingerprint := d.previous[id]
+		if !d.complete || export.Rows == maxRows {
+			if d.complete {
+				export.Truncated = true
+			}
+			d.current[id] = fingerprint
+			continue
+		}
+		if err := writeRemoved(id); err != nil {
+			return err
+		}
+		export.Rows++
+		d.removed++
+	}
+	return nil
+}
+
+// handleExportChanges handles a jira_export_changes or confluence_export_changes call. The
+// results are compared with the snapshot saved by the last run, only the changed ones are
+// exported, and the snapshot is replaced once the export is stored. Changed results left
+// out by max_rows are exported by the next run.
+func (h *ExportHandler) handleExportChanges(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+	if h.store == nil {
+		return fail(errExportsUnavailable.Error())
+	}
+
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	queryArgument := "jql"
+	if call.Name == "confluence_export_changes" {
+		queryArgument = "cql"
+	}
+	query, _ := call.Arguments[queryArgument].(string)
+	if workspaceID == "" || strings.TrimSpace(query) == "" {
+		return fail(fmt.Sprintf("workspace_id and %s are required", queryArgument))
+	}
+	format, _ := call.Arguments["format"].(string)
+	if format == "" {
+		format = models.ExportFormatCSV
+	}
+	if format != models.ExportFormatCSV && format != models.ExportFormatJSONL {
+		return fail("format must be csv or jsonl")
+	}
+	maxRows := defaultExportRows
+	if m, ok := call.Arguments["max_rows"].(float64); ok && m > 0 {
+		maxRows = int(m)
+	}
+	if maxRows > maxExportRows {
+		maxRows = maxExportRows
+	}
+	name, _ := call.Arguments["snapshot"].(string)
+	if name = strings.TrimSpace(name); name == "" {
+		name = defaultExportSnapshot
+	}
+
+	snapshot := &models.ExportSnapshot{UserID: userID, WorkspaceID: workspaceID, Tool: call.Name, Query: query, Name: name}
+	var previous *models.ExportSnapshot
+	if reset, _ := call.Arguments["reset"].(bool); !reset {
+		var err error
+		previous, err = h.store.GetExportSnapshot(snapshot.Key())
+		if err != nil && err != storage.ErrNotFound {
+			return fail(fmt.Sprintf("failed to read snapshot: %v", err))
+		}
+	}
+	diff := newExportDiff(nil)
+	if previous != nil {
+		diff = newExportDiff(previous.Items)
+	}
+
+	result, export, err := h.storeExport(call, userID, workspaceID, query, format, func(w io.Writer, export *models.Export) error {
+		if call.Name == "jira_export_changes" {
+			return h.exportIssueChanges(w, export, diff, call.OrgID, requestID, exportIssueFields(call), maxRows)
+		}
+		return h.exportPageChanges(w, export, diff, call.OrgID, requestID, maxRows)
+	})
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	result["snapshot"] = name
+	result["changes"] = map[string]int{
+		exportChangeAdded:   diff.added,
+		exportChangeUpdated: diff.updated,
+		exportChangeRemoved: diff.removed,
+	}
+	result["compared"] = diff.scanned
+	if previous != nil {
+		result["since"] = previous.UpdatedAt
+	} else {
+		result["baseline"] = true
+	}
+
+	snapshot.Items = diff.current
+	snapshot.ExportID = export.ID
+	snapshot.UpdatedAt = export.CreatedAt
+	if err := h.store.SaveExportSnapshot(snapshot); err != nil {
+		// The next run compares with the older snapshot, so it exports these changes again
+		result["warning"] = fmt.Sprintf("failed to save snapshot, so the next run repeats these changes: %v", err)
+	}
+	return jsonResult(result)
+}
+
+// exportIssueChanges compares the issues of the JQL search with the snapshot by when they
+// were updated, writing the changed ones like exportIssues after a change column
+func (h *ExportHandler) exportIssueChanges(w io.Writer, export *models.Export, diff *exportDiff, orgID, requestID string, fields []string, maxRows int) error {
+	columns := append(append([]string{"change", "key"}, fields...), "url")
+	rows, err := newExportRowWriter(w, export.Format, columns)
+	if err != nil {
+		return err
+	}
+	requestFields := make([]interface{}, 0, len(fields)+1)
+	for _, field := range fields {
+		requestFields = append(requestFields, field)
+	}
+	if !slices.Contains(fields, "updated") {
+		requestFields = append(requestFields, "updated")
+	}
+
+	pageToken := ""
+	for diff.scanned < maxExportRows && !diff.complete {
+		search, err := h.searchIssues(export.WorkspaceID, export.UserID, orgID, requestID, export.Query,
+			pageToken, min(exportPageSize, maxExportRows-diff.scanned), requestFields)
+		if err != nil {
+			return err
+		}
+
+		for _, issue := range search.Issues {
+			if export.Rows == maxRows {
+				export.Truncated = true
+				break
+			}
+			change := diff.observe(issue.Key, exportCell(issue.Fields["updated"]))
+			if change == "" {
+				continue
+			}
+			link := issueBrowseURL(issue)
+			cells := make([]string, 0, len(columns))
+			cells = append(cells, change, issue.Key)
+			for _, field := range fields {
+				cells = append(cells, exportCell(issue.Fields[field]))
+			}
+			cells = append(cells, link)
+			object := map[string]interface{}{"change": change, "key": issue.Key, "id": issue.ID, "url": link, "fields": issue.Fields}
+			if err := rows.write(cells, object); err != nil {
+				return err
+			}
+			export.Rows++
+		}
+		if err := rows.flush(); err != nil {
+			return err
+		}
+		if export.Truncated {
+			break
+		}
+
+		// The last page has no token; a repeated token would loop forever
+		if len(search.Issues) == 0 || search.NextPageToken == "" || search.NextPageToken == pageToken {
+			diff.complete = true
+		}
+		pageToken = search.NextPageToken
+	}
+	if !diff.complete && !export.Truncated {
+		export.Truncated = true
+	}
+
+	err = diff.finish(export, maxRows, func(key string) error {
+		cells := make([]string, len(columns))
+		cells[0], cells[1] = exportChangeRemoved, key
+		return rows.write(cells, map[string]interface{}{"change": exportChangeRemoved, "key": key})
+	})
+	if err != nil {
+		return err
+	}
+	return rows.flush()
+}
+
+// exportPageChanges compares the content of the CQL search with the snapshot by version,
+// writing the changed pages like exportPages after a change column
+func (h *ExportHandler) exportPageChanges(w io.Writer, export *models.Export, diff *exportDiff, orgID, requestID string, maxRows int) error {
+	columns := append([]string{"change"}, exportPageColumns...)
+	rows, err := newExportRowWriter(w, export.Format, columns)
+	if err != nil {
+		return err
+	}
+
+	for diff.scanned < maxExportRows && !diff.complete {
+		search, err := h.searchContent(export.WorkspaceID, export.UserID, orgID, requestID, export.Query,
+			diff.scanned, min(exportPageSize, maxExportRows-diff.scanned), "space", "version")
+		if err != nil {
+			return err
+		}
+
+		for _, page := range search.Results {
+			if export.Rows == maxRows {
+				export.Truncated = true
+				break
+			}
+			change := diff.observe(page.ID, strconv.Itoa(page.Version.Number))
+			if change == "" {
+				continue
+			}
+			cells := []string{change, page.ID, page.Type, page.Title, page.Space.Key, page.Space.Name, strconv.Itoa(page.Version.Number), contentURL(search, page)}
+			object := make(map[string]interface{}, len(cells))
+			for i, column := range columns {
+				object[column] = cells[i]
+			}
+			object["version"] = page.Version.Number
+			if err := rows.write(cells, object); err != nil {
+				return err
+			}
+			export.Rows++
+		}
+		if err := rows.flush(); err != nil {
+			return err
+		}
+		if export.Truncated {
+			break
+		}
+
+		// Confluence may return fewer results than asked for, so only a missing next link
+		// means the results have run out
+		if len(search.Results) == 0 || search.Links.Next == "" {
+			diff.complete = true
+		}
+	}
+	if !diff.complete && !export.Truncated {
+		export.Truncated = true
+	}
+
+	err = diff.finish(export, maxRows, func(id string) error {
+		cells := make([]string, len(columns))
+		cells[0], cells[1] = exportChangeRemoved, id
+		return rows.write(cells, map[string]interface{}{"change": exportChangeRemoved, "id": id})
+	})
+	if err != nil {
+		return err
+	}
+	return rows.flush()
+}
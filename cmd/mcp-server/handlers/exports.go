@@ -0,0 +1,634 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const (
+	defaultExportRows = 5000
+	maxExportRows     = 50000
+	// exportPageSize is how many results each Jira or Confluence search request fetches
+	exportPageSize = 100
+)
+
+// errExportsUnavailable means the export directory could not be created
+var errExportsUnavailable = errors.New("exports are unavailable on this server (EXPORTS_DIR cannot be written)")
+
+// defaultExportIssueFields are the Jira fields exported when the call names none
+var defaultExportIssueFields = []string{"summary", "status", "issuetype", "priority", "assignee", "reporter", "created", "updated"}
+
+// exportPageColumns are the columns of a Confluence page export
+var exportPageColumns = []string{"id", "type", "title", "space_key", "space_name", "version", "url"}
+
+// ExportHandler handles the export tools, which page through a JQL or CQL search and
+// write every result to a CSV or JSONL file, and the /api/exports download endpoints
+type ExportHandler struct {
+	jira       *JiraHandler
+	confluence *ConfluenceHandler
+	store      storage.ExportStoreInterface
+	ttl        time.Duration
+	downloads  bool // Exports are downloaded from /api/exports rather than read locally
+}
+
+// NewExportHandler creates a new export handler whose exports expire after ttl. store may
+// be nil when the export directory is unavailable, which disables the export tools.
+func NewExportHandler(jira *JiraHandler, confluence *ConfluenceHandler, store storage.ExportStoreInterface, ttl time.Duration) *ExportHandler {
+	return &ExportHandler{
+		jira:       jira,
+		confluence: confluence,
+		store:      store,
+		ttl:        ttl,
+	}
+}
+
+// WithDownloads makes the export tools return download links served by HandleExports and
+// HandleDownload, instead of the exported file's local path
+func (h *ExportHandler) WithDownloads() *ExportHandler {
+	h.downloads = true
+	return h
+}
+
+// IsExportTool reports whether a tool is an export tool
+func IsExportTool(name string) bool {
+	switch name {
+	case "jira_export_issues", "confluence_export_pages", "confluence_chunk_space",
+		"jira_export_changes", "confluence_export_changes":
+		return true
+	}
+	return false
+}
+
+// ListTools returns the export tools
+func (h *ExportHandler) ListTools() []mcp.Tool {
+	formatProperty := map[string]interface{}{
+		"type":        "string",
+		"enum":        []string{models.ExportFormatCSV, models.ExportFormatJSONL},
+		"description": "File format: csv (one row per result) or jsonl (one JSON object per line)",
+		"default":     models.ExportFormatCSV,
+	}
+	maxRowsProperty := map[string]interface{}{
+		"type":        "number",
+		"description": "Maximum number of rows to export (up to 50000)",
+		"default":     defaultExportRows,
+	}
+
+	tools := []mcp.Tool{
+		{
+			Name:        "jira_export_issues",
+			Description: "Export every Jira issue matching a JQL query to a CSV or JSONL file, e.g. for a spreadsheet or BI pipeline. Returns a download link that expires, not the issues themselves.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"jql": map[string]interface{}{
+						"type":        "string",
+						"description": "JQL selecting the issues (e.g., 'project = PROJ AND created >= -30d ORDER BY key')",
+					},
+					"fields": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Fields to export, as CSV columns after the issue key (defaults to summary, status, issuetype, priority, assignee, reporter, created and updated)",
+					},
+					"format":   formatProperty,
+					"max_rows": maxRowsProperty,
+				},
+				"required": []string{"workspace_id", "jql"},
+			},
+		},
+		{
+			Name:        "confluence_export_pages",
+			Description: "Export every Confluence page or blog post matching a CQL query to a CSV or JSONL file with its space, version and URL. Returns a download link that expires, not the pages themselves.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"cql": map[string]interface{}{
+						"type":        "string",
+						"description": "CQL selecting the content (e.g., 'space = DOCS AND type = page')",
+					},
+					"format":   formatProperty,
+					"max_rows": maxRowsProperty,
+				},
+				"required": []string{"workspace_id", "cql"},
+			},
+		},
+		chunkSpaceTool(),
+	}
+	return append(tools, exportChangesTools(formatProperty, maxRowsProperty)...)
+}
+
+// HandleTool handles an export tool call
+func (h *ExportHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+	if !IsExportTool(call.Name) {
+		return fail(fmt.Sprintf("unknown tool: %s", call.Name))
+	}
+	switch call.Name {
+	case "confluence_chunk_space":
+		return h.handleChunkSpace(call, userID)
+	case "jira_export_changes", "confluence_export_changes":
+		return h.handleExportChanges(call, userID)
+	}
+
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	queryArgument := "jql"
+	if call.Name == "confluence_export_pages" {
+		queryArgument = "cql"
+	}
+	query, _ := call.Arguments[queryArgument].(string)
+	if workspaceID == "" || strings.TrimSpace(query) == "" {
+		return fail(fmt.Sprintf("workspace_id and %s are required", queryArgument))
+	}
+	format, _ := call.Arguments["format"].(string)
+	if format == "" {
+		format = models.ExportFormatCSV
+	}
+	if format != models.ExportFormatCSV && format != models.ExportFormatJSONL {
+		return fail("format must be csv or jsonl")
+	}
+	maxRows := defaultExportRows
+	if m, ok := call.Arguments["max_rows"].(float64); ok && m > 0 {
+		maxRows = int(m)
+	}
+	if maxRows > maxExportRows {
+		maxRows = maxExportRows
+	}
+
+	return h.writeExport(call, userID, workspaceID, query, format, func(w io.Writer, export *models.Export) error {
+		if call.Name == "jira_export_issues" {
+			return h.exportIssues(w, export, call.OrgID, requestID, exportIssueFields(call), maxRows)
+		}
+		return h.exportPages(w, export, call.OrgID, requestID, maxRows)
+	})
+}
+
+// exportIssueFields returns the fields an issue export call names, or the default ones
+func exportIssueFields(call mcp.ToolCall) []string {
+	f, ok := call.Arguments["fields"].([]interface{})
+	if !ok || len(f) == 0 {
+		return defaultExportIssueFields
+	}
+	fields := make([]string, 0, len(f))
+	for _, v := range f {
+		if s, ok := v.(string); ok && s != "" {
+			fields = append(fields, s)
+		}
+	}
+	return fields
+}
+
+// writeExport stores the file write produces as an export of the call and returns its
+// download link, or its local path without downloads. write counts the rows it writes.
+func (h *ExportHandler) writeExport(call mcp.ToolCall, userID, workspaceID, query, format string, write func(w io.Writer, export *models.Export) error) (mcp.ToolResult, error) {
+	result, _, err := h.storeExport(call, userID, workspaceID, query, format, write)
+	if err != nil {
+		return errorResult(requestIDFor(call), err.Error()), err
+	}
+	return jsonResult(result)
+}
+
+// storeExport stores the file write produces as an export of the call, and returns the
+// export and the result the export tools report
+func (h *ExportHandler) storeExport(call mcp.ToolCall, userID, workspaceID, query, format string, write func(w io.Writer, export *models.Export) error) (map[string]interface{}, *models.Export, error) {
+	if h.store == nil {
+		return nil, nil, errExportsUnavailable
+	}
+
+	token, err := newExportToken()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create download token: %w", err)
+	}
+	now := time.Now().UTC()
+	export := &models.Export{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Tool:        call.Name,
+		WorkspaceID: workspaceID,
+		Query:       query,
+		Format:      format,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(h.ttl),
+		TokenHash:   hashExportToken(token),
+	}
+
+	err = h.store.WriteExport(export, func(w io.Writer) error { return write(w, export) })
+	if err != nil {
+		return nil, nil, fmt.Errorf("export failed: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"export_id":  export.ID,
+		"format":     export.Format,
+		"rows":       export.Rows,
+		"size_bytes": export.SizeBytes,
+		"truncated":  export.Truncated,
+		"expires_at": export.ExpiresAt,
+	}
+	if h.downloads {
+		result["download_url"] = exportDownloadURL(export.ID, token)
+	} else if f, err := h.store.OpenExport(export.ID); err == nil {
+		result["file"] = f.Name()
+		f.Close()
+	}
+	return result, export, nil
+}
+
+// exportRowWriter writes export rows as CSV, after a header of the columns, or as JSONL
+type exportRowWriter struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+func newExportRowWriter(w io.Writer, format string, columns []string) (*exportRowWriter, error) {
+	if format == models.ExportFormatJSONL {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &exportRowWriter{json: encoder}, nil
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &exportRowWriter{csv: writer}, nil
+}
+
+// write writes a row; cells are its CSV cells and object its JSON line
+func (rw *exportRowWriter) write(cells []string, object interface{}) error {
+	if rw.json != nil {
+		return rw.json.Encode(object)
+	}
+	return rw.csv.Write(cells)
+}
+
+// flush ends a page of rows, so they reach the file before the next search request
+func (rw *exportRowWriter) flush() error {
+	if rw.csv == nil {
+		return nil
+	}
+	rw.csv.Flush()
+	return rw.csv.Error()
+}
+
+// exportIssues pages through the JQL search with nextPageToken, writing each page as it
+// arrives. Issues export as their key, the fields and their browse URL.
+func (h *ExportHandler) exportIssues(w io.Writer, export *models.Export, orgID, requestID string, fields []string, maxRows int) error {
+	columns := append(append([]string{"key"}, fields...), "url")
+	rows, err := newExportRowWriter(w, export.Format, columns)
+	if err != nil {
+		return err
+	}
+	requestFields := make([]interface{}, len(fields))
+	for i, field := range fields {
+		requestFields[i] = field
+	}
+
+	pageToken := ""
+	for export.Rows < maxRows {
+		search, err := h.searchIssues(export.WorkspaceID, export.UserID, orgID, requestID, export.Query,
+			pageToken, min(exportPageSize, maxRows-export.Rows), requestFields)
+		if err != nil {
+			return err
+		}
+
+		for _, issue := range search.Issues {
+			link := issueBrowseURL(issue)
+			cells := make([]string, 0, len(columns))
+			cells = append(cells, issue.Key)
+			for _, field := range fields {
+				cells = append(cells, exportCell(issue.Fields[field]))
+			}
+			cells = append(cells, link)
+			object := map[string]interface{}{"key": issue.Key, "id": issue.ID, "url": link, "fields": issue.Fields}
+			if err := rows.write(cells, object); err != nil {
+				return err
+			}
+			export.Rows++
+		}
+		if err := rows.flush(); err != nil {
+			return err
+		}
+
+		// The last page has no token; a repeated token would loop forever
+		if len(search.Issues) == 0 || search.NextPageToken == "" || search.NextPageToken == pageToken {
+			return nil
+		}
+		pageToken = search.NextPageToken
+	}
+	export.Truncated = true
+	return nil
+}
+
+// searchIssues runs a JQL search through the Jira service, from the page pageToken names
+// (the first when empty), fetching the given fields of each issue
+func (h *ExportHandler) searchIssues(workspaceID, userID, orgID, requestID, jql, pageToken string, limit int, fields []interface{}) (*models.SearchResponse, error) {
+	params := map[string]interface{}{
+		"jql":    jql,
+		"limit":  float64(limit),
+		"fields": fields,
+	}
+	if pageToken != "" {
+		params["next_page_token"] = pageToken
+	}
+	resp, err := h.jira.callService(models.JiraRequest{
+		Action:      "list_issues",
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       orgID,
+		Params:      params,
+		RequestID:   requestID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, serviceError(resp.Error)
+	}
+	var search models.SearchResponse
+	if err := decodeServiceData(resp.Data, &search); err != nil {
+		return nil, fmt.Errorf("unexpected Jira search response: %w", err)
+	}
+	return &search, nil
+}
+
+// exportPages pages through the CQL search by offset, writing each page as it arrives
+func (h *ExportHandler) exportPages(w io.Writer, export *models.Export, orgID, requestID string, maxRows int) error {
+	rows, err := newExportRowWriter(w, export.Format, exportPageColumns)
+	if err != nil {
+		return err
+	}
+
+	for export.Rows < maxRows {
+		search, err := h.searchContent(export.WorkspaceID, export.UserID, orgID, requestID, export.Query,
+			export.Rows, min(exportPageSize, maxRows-export.Rows), "space", "version")
+		if err != nil {
+			return err
+		}
+
+		for _, page := range search.Results {
+			cells := []string{page.ID, page.Type, page.Title, page.Space.Key, page.Space.Name, strconv.Itoa(page.Version.Number), contentURL(search, page)}
+			object := make(map[string]interface{}, len(cells))
+			for i, column := range exportPageColumns {
+				object[column] = cells[i]
+			}
+			object["version"] = page.Version.Number
+			if err := rows.write(cells, object); err != nil {
+				return err
+			}
+			export.Rows++
+		}
+		if err := rows.flush(); err != nil {
+			return err
+		}
+
+		// Confluence may return fewer results than asked for, so only a missing next link
+		// means the results have run out
+		if len(search.Results) == 0 || search.Links.Next == "" {
+			return nil
+		}
+	}
+	export.Truncated = true
+	return nil
+}
+
+// searchContent runs a CQL search through the Confluence service, from the start'th
+// result, expanding the given properties of each result
+func (h *ExportHandler) searchContent(workspaceID, userID, orgID, requestID, cql string, start, limit int, expand ...string) (*models.SearchResults, error) {
+	expandParam := make([]interface{}, len(expand))
+	for i, e := range expand {
+		expandParam[i] = e
+	}
+	resp, err := h.confluence.callService(models.ConfluenceRequest{
+		Action:      "search",
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       orgID,
+		Params: map[string]interface{}{
+			"query":  cql,
+			"limit":  float64(limit),
+			"start":  float64(start),
+			"expand": expandParam,
+		},
+		RequestID: requestID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, serviceError(resp.Error)
+	}
+	var search models.SearchResults
+	if err := decodeServiceData(resp.Data, &search); err != nil {
+		return nil, fmt.Errorf("unexpected Confluence search response: %w", err)
+	}
+	return &search, nil
+}
+
+// contentURL is a search result's web URL, which is relative to the search's base URL
+func contentURL(search *models.SearchResults, page models.ConfluencePage) string {
+	if search.Links.Base == "" || page.Links.WebUI == "" {
+		return ""
+	}
+	return strings.TrimRight(search.Links.Base, "/") + page.Links.WebUI
+}
+
+// exportCell renders a Jira field value as a CSV cell: objects by their display name,
+// name or value, lists joined with "; ", and anything else as JSON
+func exportCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}:
+		for _, key := range []string{"displayName", "name", "value", "key"} {
+			if s, ok := v[key].(string); ok {
+				return s
+			}
+		}
+	case []interface{}:
+		cells := make([]string, len(v))
+		for i, item := range v {
+			cells[i] = exportCell(item)
+		}
+		return strings.Join(cells, "; ")
+	}
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+// newExportToken returns a random download token
+func newExportToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashExportToken returns the hex SHA-256 of a download token, which is all that is stored
+func hashExportToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// exportDownloadURL is an export's download link, absolute when MCP_PUBLIC_URL is set
+func exportDownloadURL(id, token string) string {
+	path := "/api/exports/" + id + "/download?token=" + token
+	return strings.TrimSuffix(os.Getenv("MCP_PUBLIC_URL"), "/") + path
+}
+
+// HandleExports handles /api/exports, /api/exports/{id} and /api/exports/{id}/download
+// for signed-in users
+func (h *ExportHandler) HandleExports(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		http.Error(w, "Exports are unavailable on this server", http.StatusNotImplemented)
+		return
+	}
+
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	exportID, sub := "", ""
+	if len(r.URL.Path) > len("/api/exports/") {
+		exportID, sub, _ = strings.Cut(r.URL.Path[len("/api/exports/"):], "/")
+	}
+
+	switch {
+	case exportID == "" && r.Method == http.MethodGet:
+		exports, err := h.store.ListExports(userCtx.UserID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to list exports: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(exports)
+	case exportID != "" && sub == "" && r.Method == http.MethodGet:
+		export, ok := h.getExport(w, exportID)
+		if !ok {
+			return
+		}
+		if export.UserID != userCtx.UserID {
+			http.Error(w, "Export not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(export)
+	case exportID != "" && sub == "" && r.Method == http.MethodDelete:
+		if err := h.store.DeleteExport(userCtx.UserID, exportID); err != nil {
+			if err == storage.ErrNotFound {
+				http.Error(w, "Export not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, fmt.Sprintf("Failed to delete export: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case exportID != "" && sub == "download" && r.Method == http.MethodGet:
+		export, ok := h.getExport(w, exportID)
+		if !ok {
+			return
+		}
+		if export.UserID != userCtx.UserID {
+			http.Error(w, "Export not found", http.StatusNotFound)
+			return
+		}
+		h.serveExport(w, r, export)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleDownload handles GET /api/exports/{id}/download?token=..., the link the export
+// tools return. The token authorizes the download, so BI tools can fetch the file
+// without signing in, until the export expires.
+func (h *ExportHandler) HandleDownload(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		http.Error(w, "Exports are unavailable on this server", http.StatusNotImplemented)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	exportID, sub, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/exports/"), "/")
+	if sub != "download" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	export, ok := h.getExport(w, exportID)
+	if !ok {
+		return
+	}
+	// Expired and unknown exports, and wrong tokens, are indistinguishable
+	token := hashExportToken(r.URL.Query().Get("token"))
+	if subtle.ConstantTimeCompare([]byte(token), []byte(export.TokenHash)) != 1 {
+		http.Error(w, "Export not found", http.StatusNotFound)
+		return
+	}
+	h.serveExport(w, r, export)
+}
+
+// getExport looks up an unexpired export, writing a 404 when there is none
+func (h *ExportHandler) getExport(w http.ResponseWriter, exportID string) (*models.Export, bool) {
+	export, err := h.store.GetExport(exportID)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Export not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, fmt.Sprintf("Failed to get export: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return export, true
+}
+
+// serveExport sends an export's file as an attachment, with support for range requests
+func (h *ExportHandler) serveExport(w http.ResponseWriter, r *http.Request, export *models.Export) {
+	f, err := h.store.OpenExport(export.ID)
+	if err != nil {
+		http.Error(w, "Export not found", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", export.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName()))
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, "", export.CreatedAt, f)
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+)
+
+// FieldPresetHandler handles the /api/field-presets endpoints and resolves the preset
+// argument of jira_list_issues into the fields the search returns
+type FieldPresetHandler struct {
+	store storage.FieldPresetStoreInterface
+
+	mu     sync.RWMutex
+	server map[string][]string // Presets from config.yaml
+}
+
+// NewFieldPresetHandler creates a new field preset handler with the server's presets.
+// store may be nil when users cannot save presets (file-based storage).
+func NewFieldPresetHandler(store storage.FieldPresetStoreInterface, server map[string][]string) *FieldPresetHandler {
+	return &FieldPresetHandler{store: store, server: server}
+}
+
+// SetServerPresets replaces the server's presets, e.g. after config.yaml is reloaded
+func (h *FieldPresetHandler) SetServerPresets(server map[string][]string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.server = server
+}
+
+// WrapJira resolves the preset of list_issues requests before they reach the Jira service.
+// The preset's fields are added to any fields the call names; a call with neither uses the
+// "default" preset, if there is one.
+func (h *FieldPresetHandler) WrapJira(callService func(models.JiraRequest) (*models.JiraResponse, error)) func(models.JiraRequest) (*models.JiraResponse, error) {
+	return func(req models.JiraRequest) (*models.JiraResponse, error) {
+		// Summaries choose their own fields
+		summarize, _ := req.Params["summarize"].(bool)
+		if req.Action != "list_issues" || summarize {
+			return callService(req)
+		}
+
+		name, _ := req.Params["preset"].(string)
+		name = strings.ToLower(strings.TrimSpace(name))
+		fields, _ := req.Params["fields"].([]interface{})
+		if name == "" && len(fields) > 0 {
+			return callService(req)
+		}
+
+		preset, err := h.resolve(req.UserID, name)
+		if err != nil {
+			return &models.JiraResponse{Success: false, Error: &models.ErrorInfo{
+				Code:    models.ErrCodeInvalidRequest,
+				Message: err.Error(),
+			}, RequestID: req.RequestID}, nil
+		}
+
+		// The call's arguments may be shared with the caller, so the request gets its own
+		params := make(map[string]interface{}, len(req.Params))
+		for k, v := range req.Params {
+			params[k] = v
+		}
+		delete(params, "preset")
+		if preset != nil {
+			merged := append([]interface{}{}, fields...)
+			for _, field := range preset {
+				merged = append(merged, field)
+			}
+			params["fields"] = merged
+		}
+		req.Params = params
+		return callService(req)
+	}
+}
+
+// resolve returns the fields of the user's preset called name, or else the server's. An
+// empty name asks for the default preset, which need not exist.
+func (h *FieldPresetHandler) resolve(userID, name string) ([]string, error) {
+	lookup := name
+	if lookup == "" {
+		lookup = models.DefaultFieldPreset
+	}
+
+	if h.store != nil && userID != "" {
+		preset, err := h.store.GetFieldPreset(userID, lookup)
+		if err == nil {
+			return preset.Fields, nil
+		}
+		if err != storage.ErrNotFound {
+			return nil, fmt.Errorf("failed to load field preset %s: %v", lookup, err)
+		}
+	}
+
+	h.mu.RLock()
+	fields, ok := h.server[lookup]
+	h.mu.RUnlock()
+	if ok {
+		return fields, nil
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	names := h.names(userID)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("unknown field preset %q: no presets are defined", name)
+	}
+	return nil, fmt.Errorf("unknown field preset %q; available presets: %s", name, strings.Join(names, ", "))
+}
+
+// names lists the presets available to a user
+func (h *FieldPresetHandler) names(userID string) []string {
+	seen := make(map[string]bool)
+	h.mu.RLock()
+	for name := range h.server {
+		seen[name] = true
+	}
+	h.mu.RUnlock()
+	if h.store != nil && userID != "" {
+		if presets, err := h.store.ListFieldPresets(userID); err == nil {
+			for _, preset := range presets {
+				seen[preset.Name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FieldPresetRequest represents the request to save a field preset
+type FieldPresetRequest struct {
+	Fields []string `json:"fields"`
+}
+
+// HandleFieldPresets handles /api/field-presets and /api/field-presets/{name}
+func (h *FieldPresetHandler) HandleFieldPresets(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/field-presets"), "/")
+	switch {
+	case name == "" && r.Method == http.MethodGet:
+		h.handleList(w, userCtx)
+	case name != "" && (r.Method == http.MethodPut || r.Method == http.MethodDelete) && h.store == nil:
+		http.Error(w, "Saving field presets requires database storage (DATABASE_URL)", http.StatusNotImplemented)
+	case name != "" && r.Method == http.MethodPut:
+		h.handleSave(w, r, userCtx, name)
+	case name != "" && r.Method == http.MethodDelete:
+		h.handleDelete(w, userCtx, name)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleList handles GET /api/field-presets, which returns the server's presets and the
+// user's own
+func (h *FieldPresetHandler) handleList(w http.ResponseWriter, userCtx *auth.UserContext) {
+	h.mu.RLock()
+	server := make([]models.FieldPreset, 0, len(h.server))
+	for name, fields := range h.server {
+		server = append(server, models.FieldPreset{Name: name, Fields: fields})
+	}
+	h.mu.RUnlock()
+	sort.Slice(server, func(i, j int) bool { return server[i].Name < server[j].Name })
+
+	user := []models.FieldPreset{}
+	if h.store != nil {
+		presets, err := h.store.ListFieldPresets(userCtx.UserID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to list field presets: %v", err), http.StatusInternalServerError)
+			return
+		}
+		user = presets
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"server": server,
+		"user":   user,
+	})
+}
+
+// handleSave handles PUT /api/field-presets/{name}, which creates or replaces the preset
+func (h *FieldPresetHandler) handleSave(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext, name string) {
+	var req FieldPresetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	preset, err := models.NormalizeFieldPreset(name, req.Fields)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	preset.UserID = userCtx.UserID
+
+	if err := h.store.SaveFieldPreset(&preset); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save field preset: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preset)
+}
+
+// handleDelete handles DELETE /api/field-presets/{name}
+func (h *FieldPresetHandler) handleDelete(w http.ResponseWriter, userCtx *auth.UserContext, name string) {
+	if err := h.store.DeleteFieldPreset(userCtx.UserID, strings.ToLower(name)); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Field preset not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete field preset: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// withIdempotencyKey adds the optional idempotency_key argument to the tools whose
+// actions change data. The services replay the first result for a repeated key.
+func withIdempotencyKey(tools []mcp.Tool, mutating map[string]bool, actionFor func(string) string) []mcp.Tool {
+	for _, tool := range tools {
+		if !mutating[actionFor(tool.Name)] {
+			continue
+		}
+		if properties, ok := tool.InputSchema["properties"].(map[string]interface{}); ok {
+			properties[storage.IdempotencyKeyParam] = map[string]interface{}{
+				"type":        "string",
+				"description": "Optional unique key for this change. Retrying with the same key returns the original result instead of applying the change twice.",
+			}
+		}
+	}
+	return tools
+}
@@ -0,0 +1,582 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const (
+	maxTemplateVariables = 50
+	maxTemplateTextBytes = 32 * 1024
+)
+
+// templatePlaceholderPattern matches {{variable}} placeholders, allowing spaces inside the braces
+var templatePlaceholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// templateVariableNamePattern is what a declared variable may be called
+var templateVariableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// errIssueTemplatesUnavailable is returned when templates are used without database storage
+var errIssueTemplatesUnavailable = errors.New("issue templates require database storage (DATABASE_URL)")
+
+// IssueTemplateHandler handles the /api/issue-templates endpoints and the tools that
+// create Jira issues from the templates
+type IssueTemplateHandler struct {
+	store storage.IssueTemplateStoreInterface
+	jira  *JiraHandler
+}
+
+// NewIssueTemplateHandler creates a new issue template handler. store may be nil when
+// templates are not supported (file-based storage).
+func NewIssueTemplateHandler(store storage.IssueTemplateStoreInterface, jira *JiraHandler) *IssueTemplateHandler {
+	return &IssueTemplateHandler{store: store, jira: jira}
+}
+
+// IsIssueTemplateTool reports whether a tool is served by the issue template handler
+func IsIssueTemplateTool(name string) bool {
+	return name == "jira_list_issue_templates" || name == "jira_create_issue_from_template"
+}
+
+// ListTools returns the issue template tools
+func (h *IssueTemplateHandler) ListTools() []mcp.Tool {
+	actionFor := func(name string) string { return strings.TrimPrefix(name, "jira_") }
+	return withIdempotencyKey(issueTemplateTools(), models.JiraMutatingActions, actionFor)
+}
+
+func issueTemplateTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "jira_list_issue_templates",
+			Description: "List the Jira issue templates you and your organization have saved, with the variables each one takes. Use them with jira_create_issue_from_template so issues follow team conventions.",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			Name:        "jira_create_issue_from_template",
+			Description: "Create a Jira issue from a saved issue template, filling in its {{variable}} placeholders. Call jira_list_issue_templates to see the templates and their variables.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"template_id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID or name of the template",
+					},
+					"variables": map[string]interface{}{
+						"type":        "object",
+						"description": "Values of the template's variables, keyed by name",
+					},
+					"project_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Project key (required when the template has no default project)",
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return the issue the template produces without creating it",
+						"default":     false,
+					},
+				},
+				"required": []string{"workspace_id", "template_id"},
+			},
+		},
+	}
+}
+
+// HandleTool handles an issue template tool call
+func (h *IssueTemplateHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+	if h.store == nil {
+		return fail(errIssueTemplatesUnavailable.Error())
+	}
+
+	templates, err := h.visibleTemplates(userID, call.OrgID)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to list issue templates: %v", err))
+	}
+
+	switch call.Name {
+	case "jira_list_issue_templates":
+		return jsonResult(map[string]interface{}{"templates": templates})
+	case "jira_create_issue_from_template":
+		return h.createIssueFromTemplate(call, userID, requestID, templates)
+	default:
+		return fail(fmt.Sprintf("unknown tool: %s", call.Name))
+	}
+}
+
+// createIssueFromTemplate handles a jira_create_issue_from_template call
+func (h *IssueTemplateHandler) createIssueFromTemplate(call mcp.ToolCall, userID, requestID string, templates []models.IssueTemplate) (mcp.ToolResult, error) {
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	ref, _ := call.Arguments["template_id"].(string)
+	if workspaceID == "" || ref == "" {
+		return fail("workspace_id and template_id are required")
+	}
+	template, err := findIssueTemplate(templates, ref)
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	values, _ := call.Arguments["variables"].(map[string]interface{})
+	rendered, err := renderIssueTemplate(*template, values)
+	if err != nil {
+		return fail(err.Error())
+	}
+	if projectKey, ok := call.Arguments["project_key"].(string); ok && projectKey != "" {
+		rendered.ProjectKey = projectKey
+	}
+	if rendered.ProjectKey == "" {
+		return fail(fmt.Sprintf("project_key is required; template %q has no default project", template.Name))
+	}
+
+	params := map[string]interface{}{
+		"project_key":       rendered.ProjectKey,
+		"issue_type":        rendered.IssueType,
+		"summary":           rendered.Summary,
+		"description":       rendered.Body,
+		"additional_fields": rendered.Fields,
+	}
+	if dryRun, _ := call.Arguments["dry_run"].(bool); dryRun {
+		return jsonResult(map[string]interface{}{
+			"template_id": template.ID,
+			"dry_run":     true,
+			"issue":       params,
+		})
+	}
+	if key := storage.IdempotencyKey(call.Arguments); key != "" {
+		params[storage.IdempotencyKeyParam] = key
+	}
+
+	resp, err := h.jira.callService(models.JiraRequest{
+		Action:      "create_issue",
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       call.OrgID,
+		Params:      params,
+		RequestID:   requestID,
+	})
+	if err == nil && !resp.Success {
+		err = serviceError(resp.Error)
+	}
+	var issue models.JiraIssue
+	if err == nil {
+		err = decodeServiceData(resp.Data, &issue)
+	}
+	if err != nil {
+		return fail(fmt.Sprintf("failed to create issue: %v", err))
+	}
+
+	return jsonResult(map[string]interface{}{
+		"template_id": template.ID,
+		"key":         issue.Key,
+		"id":          issue.ID,
+		"url":         issueBrowseURL(issue),
+		"summary":     rendered.Summary,
+	})
+}
+
+// visibleTemplates returns the user's templates followed by their organization's shared ones
+func (h *IssueTemplateHandler) visibleTemplates(userID, orgID string) ([]models.IssueTemplate, error) {
+	templates, err := h.store.ListIssueTemplates(userID)
+	if err != nil {
+		return nil, err
+	}
+	if orgID != "" {
+		shared, err := h.store.ListIssueTemplates(orgID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range shared {
+			shared[i].Shared = true
+		}
+		templates = append(templates, shared...)
+	}
+	return templates, nil
+}
+
+// findIssueTemplate picks a template by ID or name. The user's own templates come first,
+// so they shadow shared templates of the same name.
+func findIssueTemplate(templates []models.IssueTemplate, ref string) (*models.IssueTemplate, error) {
+	for i := range templates {
+		if templates[i].ID == ref || strings.EqualFold(templates[i].Name, ref) {
+			return &templates[i], nil
+		}
+	}
+	return nil, fmt.Errorf("issue template not found: %s", ref)
+}
+
+// renderIssueTemplate fills in a template's placeholders. Every required variable must be
+// given, and variables the template does not declare are rejected to catch typos.
+func renderIssueTemplate(template models.IssueTemplate, values map[string]interface{}) (models.IssueTemplate, error) {
+	declared := make(map[string]bool, len(template.Variables))
+	for _, v := range template.Variables {
+		declared[v.Name] = true
+	}
+	var unknown []string
+	for name := range values {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return template, fmt.Errorf("template %q has no variables named %s", template.Name, strings.Join(unknown, ", "))
+	}
+
+	resolved := make(map[string]string, len(template.Variables))
+	var missing []string
+	for _, v := range template.Variables {
+		value, ok := values[v.Name]
+		switch {
+		case ok && value != nil:
+			resolved[v.Name] = templateValueString(value)
+		case v.Required:
+			missing = append(missing, v.Name)
+		default:
+			resolved[v.Name] = v.Default
+		}
+	}
+	if len(missing) > 0 {
+		return template, fmt.Errorf("missing required variables: %s", strings.Join(missing, ", "))
+	}
+
+	fill := func(s string) string {
+		return templatePlaceholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+			return resolved[templatePlaceholderPattern.FindStringSubmatch(placeholder)[1]]
+		})
+	}
+	template.ProjectKey = fill(template.ProjectKey)
+	template.Summary = fill(template.Summary)
+	template.Body = fill(template.Body)
+	fields, _ := fillTemplateValue(template.Fields, fill).(map[string]interface{})
+	template.Fields = fields
+	if strings.TrimSpace(template.Summary) == "" {
+		return template, errors.New("the template's summary is empty once its variables are filled in")
+	}
+	return template, nil
+}
+
+// fillTemplateValue copies a field value, filling in the placeholders of its strings
+func fillTemplateValue(v interface{}, fill func(string) string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = fillTemplateValue(value, fill)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, value := range v {
+			copied[i] = fillTemplateValue(value, fill)
+		}
+		return copied
+	case string:
+		return fill(v)
+	default:
+		return v
+	}
+}
+
+// templateValueString formats a variable's value, which agents may send as a number or boolean
+func templateValueString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// IssueTemplateRequest represents the request to create or replace an issue template
+type IssueTemplateRequest struct {
+	Name        string                         `json:"name"`
+	Description string                         `json:"description,omitempty"`
+	ProjectKey  string                         `json:"project_key,omitempty"`
+	IssueType   string                         `json:"issue_type"`
+	Summary     string                         `json:"summary"`
+	Body        string                         `json:"body,omitempty"`
+	Fields      map[string]interface{}         `json:"fields,omitempty"`
+	Variables   []models.IssueTemplateVariable `json:"variables,omitempty"`
+	Shared      bool                           `json:"shared,omitempty"` // Share with the caller's organization (org admins only)
+}
+
+// HandleTemplates handles /api/issue-templates and /api/issue-templates/{id}
+func (h *IssueTemplateHandler) HandleTemplates(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		http.Error(w, "Issue templates require database storage (DATABASE_URL)", http.StatusNotImplemented)
+		return
+	}
+
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	templateID := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/issue-templates"), "/")
+
+	switch {
+	case templateID == "" && r.Method == http.MethodGet:
+		h.handleList(w, userCtx)
+	case templateID == "" && r.Method == http.MethodPost:
+		h.handleCreate(w, r, userCtx)
+	case templateID != "" && r.Method == http.MethodGet:
+		h.handleGet(w, userCtx, templateID)
+	case templateID != "" && r.Method == http.MethodPut:
+		h.handleUpdate(w, r, userCtx, templateID)
+	case templateID != "" && r.Method == http.MethodDelete:
+		h.handleDelete(w, userCtx, templateID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleList handles GET /api/issue-templates: the caller's templates followed by those
+// shared with their organization
+func (h *IssueTemplateHandler) handleList(w http.ResponseWriter, userCtx *auth.UserContext) {
+	templates, err := h.visibleTemplates(userCtx.UserID, userCtx.OrgID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list issue templates: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+// handleCreate handles POST /api/issue-templates
+func (h *IssueTemplateHandler) handleCreate(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext) {
+	var req IssueTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Shared templates are stored under the organization and managed by its admins
+	ownerID := userCtx.UserID
+	if req.Shared {
+		if !userCtx.InOrg() {
+			http.Error(w, "Shared issue templates require an active organization", http.StatusBadRequest)
+			return
+		}
+		if !userCtx.IsOrgAdmin() {
+			http.Error(w, "Only organization admins can manage shared issue templates", http.StatusForbidden)
+			return
+		}
+		ownerID = userCtx.OrgID
+	}
+
+	template := models.IssueTemplate{
+		ID:        uuid.New().String(),
+		OwnerID:   ownerID,
+		Shared:    req.Shared,
+		CreatedBy: userCtx.UserID,
+	}
+	if err := applyIssueTemplateRequest(&template, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.nameAvailable(w, ownerID, template.Name, "") {
+		return
+	}
+
+	if err := h.store.CreateIssueTemplate(&template); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save issue template: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(template)
+}
+
+// handleGet handles GET /api/issue-templates/{id}
+func (h *IssueTemplateHandler) handleGet(w http.ResponseWriter, userCtx *auth.UserContext, templateID string) {
+	template, ok := h.getTemplate(w, userCtx, templateID, false)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+// handleUpdate handles PUT /api/issue-templates/{id}, which replaces the template. A
+// template stays personal or shared; shared templates can only be changed by org admins.
+func (h *IssueTemplateHandler) handleUpdate(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext, templateID string) {
+	var req IssueTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	template, ok := h.getTemplate(w, userCtx, templateID, true)
+	if !ok {
+		return
+	}
+	if err := applyIssueTemplateRequest(template, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.nameAvailable(w, template.OwnerID, template.Name, template.ID) {
+		return
+	}
+
+	if err := h.store.UpdateIssueTemplate(template); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Issue template not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to update issue template: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+// handleDelete handles DELETE /api/issue-templates/{id}
+func (h *IssueTemplateHandler) handleDelete(w http.ResponseWriter, userCtx *auth.UserContext, templateID string) {
+	template, ok := h.getTemplate(w, userCtx, templateID, true)
+	if !ok {
+		return
+	}
+
+	if err := h.store.DeleteIssueTemplate(template.OwnerID, template.ID); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Issue template not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete issue template: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getTemplate loads one of the caller's templates, or one shared with their organization,
+// writing the error response if it cannot. manage requires the org admin role for shared
+// templates.
+func (h *IssueTemplateHandler) getTemplate(w http.ResponseWriter, userCtx *auth.UserContext, templateID string, manage bool) (*models.IssueTemplate, bool) {
+	template, err := h.store.GetIssueTemplate(userCtx.UserID, templateID)
+	if err == storage.ErrNotFound && userCtx.InOrg() {
+		template, err = h.store.GetIssueTemplate(userCtx.OrgID, templateID)
+		if err == nil {
+			template.Shared = true
+			if manage && !userCtx.IsOrgAdmin() {
+				http.Error(w, "Only organization admins can manage shared issue templates", http.StatusForbidden)
+				return nil, false
+			}
+		}
+	}
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Issue template not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, fmt.Sprintf("Failed to get issue template: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return template, true
+}
+
+// nameAvailable reports whether the owner has no other template with the name, writing a
+// 409 response if it does
+func (h *IssueTemplateHandler) nameAvailable(w http.ResponseWriter, ownerID, name, exceptID string) bool {
+	existing, err := h.store.ListIssueTemplates(ownerID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list issue templates: %v", err), http.StatusInternalServerError)
+		return false
+	}
+	for _, t := range existing {
+		if t.ID != exceptID && strings.EqualFold(t.Name, name) {
+			http.Error(w, fmt.Sprintf("An issue template named %q already exists", t.Name), http.StatusConflict)
+			return false
+		}
+	}
+	return true
+}
+
+// applyIssueTemplateRequest validates a create or replace request and copies it onto the
+// template. Placeholders without a declared variable are added as required variables.
+func applyIssueTemplateRequest(template *models.IssueTemplate, req IssueTemplateRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || strings.TrimSpace(req.IssueType) == "" || strings.TrimSpace(req.Summary) == "" {
+		return errors.New("missing required fields: name, issue_type, summary")
+	}
+	if len(req.Summary)+len(req.Body) > maxTemplateTextBytes {
+		return fmt.Errorf("summary and body must be at most %d bytes together", maxTemplateTextBytes)
+	}
+
+	declared := make(map[string]bool, len(req.Variables))
+	for _, v := range req.Variables {
+		if !templateVariableNamePattern.MatchString(v.Name) {
+			return fmt.Errorf("invalid variable name %q (use letters, digits and underscores)", v.Name)
+		}
+		if declared[v.Name] {
+			return fmt.Errorf("variable %q is declared twice", v.Name)
+		}
+		declared[v.Name] = true
+	}
+
+	variables := append([]models.IssueTemplateVariable{}, req.Variables...)
+	texts := []string{req.ProjectKey, req.Summary, req.Body}
+	if req.Fields != nil {
+		encoded, err := json.Marshal(req.Fields)
+		if err != nil {
+			return fmt.Errorf("invalid fields: %v", err)
+		}
+		// Placeholders cannot contain characters JSON escapes, so the encoding finds them all
+		texts = append(texts, string(encoded))
+	}
+	for _, text := range texts {
+		for _, match := range templatePlaceholderPattern.FindAllStringSubmatch(text, -1) {
+			if !declared[match[1]] {
+				declared[match[1]] = true
+				variables = append(variables, models.IssueTemplateVariable{Name: match[1], Required: true})
+			}
+		}
+	}
+	if len(variables) > maxTemplateVariables {
+		return fmt.Errorf("a template can have at most %d variables", maxTemplateVariables)
+	}
+
+	template.Name = req.Name
+	template.Description = req.Description
+	template.ProjectKey = strings.TrimSpace(req.ProjectKey)
+	template.IssueType = strings.TrimSpace(req.IssueType)
+	template.Summary = req.Summary
+	template.Body = req.Body
+	template.Fields = req.Fields
+	template.Variables = variables
+	return nil
+}
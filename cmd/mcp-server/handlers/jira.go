@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"sync/atomic"
+	"slices"
+	"strings"
 
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
 )
@@ -12,17 +16,53 @@ import (
 // JiraHandler handles Jira-related MCP tool calls
 type JiraHandler struct {
 	callService func(models.JiraRequest) (*models.JiraResponse, error)
+	deprecated  map[string]mcp.Tool // Outdated tool versions, whose results carry a notice
+
+	customTools   []mcp.Tool        // tools.yaml passthrough tools
+	customActions map[string]string // Their actions, by tool name
+
+	confirmations *Confirmations // Destructive tools that run only once confirmed (nil = none)
 }
 
 // NewJiraHandler creates a new Jira handler
 func NewJiraHandler(callService func(models.JiraRequest) (*models.JiraResponse, error)) *JiraHandler {
 	return &JiraHandler{
 		callService: callService,
+		deprecated:  deprecatedTools(jiraTools()),
 	}
 }
 
-// ListTools returns the list of Jira tools
+// WithCustomTools offers the jira_* passthrough tools among defs, which the Jira service
+// serves from the same tools.yaml
+func (h *JiraHandler) WithCustomTools(defs []config.ToolDefinition) *JiraHandler {
+	h.customTools, h.customActions = customTools(defs, "jira", h.ListTools())
+	return h
+}
+
+// IsJiraServiceTool reports whether a tool is served by the Jira service: the Jira tools
+// and the Bitbucket, Opsgenie and directory tools, which use the same Atlassian credentials
+func IsJiraServiceTool(name string) bool {
+	return strings.HasPrefix(name, "jira_") || IsBitbucketTool(name) || IsOpsgenieTool(name) || IsAdminTool(name)
+}
+
+// WithConfirmations makes the tools that need confirmation run only once confirmed
+func (h *JiraHandler) WithConfirmations(confirmations *Confirmations) *JiraHandler {
+	h.confirmations = confirmations
+	return h
+}
+
+// ListTools returns the list of Jira, Bitbucket, Opsgenie and directory tools
 func (h *JiraHandler) ListTools() []mcp.Tool {
+	tools := withIdempotencyKey(jiraTools(), models.JiraMutatingActions, getJiraActionFromToolName)
+	tools = withCacheBypass(tools, models.JiraCacheableActions, getJiraActionFromToolName)
+	tools = append(tools, bitbucketTools()...)
+	tools = append(tools, withIdempotencyKey(opsgenieTools(), models.JiraMutatingActions, getJiraActionFromToolName)...)
+	tools = withDryRun(tools, models.JiraMutatingActions, getJiraActionFromToolName)
+	tools = append(tools, directoryTools()...)
+	return h.confirmations.withToken(append(tools, h.customTools...))
+}
+
+func jiraTools() []mcp.Tool {
 	return []mcp.Tool{
 		{
 			Name:        "jira_list_projects",
@@ -42,6 +82,7 @@ func (h *JiraHandler) ListTools() []mcp.Tool {
 			Name:        "jira_list_issues",
 			Description: "Search for Jira issues using JQL. Supports querying multiple workspaces - specify workspace_id to search a specific organization.",
 			InputType:   "object",
+			Deprecated:  "use jira_list_issues@v2, which returns issues a page at a time with a next_cursor",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -65,6 +106,60 @@ func (h *JiraHandler) ListTools() []mcp.Tool {
 						},
 						"description": "Fields to return",
 					},
+					"preset": map[string]interface{}{
+						"type":        "string",
+						"description": "Named field preset (e.g. 'triage') whose fields are returned in addition to fields; without either, the 'default' preset applies if defined",
+					},
+					"summarize": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return one compact line per issue (key | summary | status | assignee | updated) instead of full issue objects; use for overviews. Ignores fields and preset.",
+						"default":     false,
+					},
+				},
+				"required": []string{"workspace_id", "jql"},
+			},
+		},
+		{
+			Name:        mcp.VersionedName("jira_list_issues", "v2"),
+			Version:     "v2",
+			Description: "Search for Jira issues using JQL, a page at a time. Returns {issues, next_cursor}; pass next_cursor back as cursor for the next page. It is omitted on the last page.",
+			InputType:   "object",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID to query (e.g., 'workspace-1', 'providentia'). Use list_workspaces to see available workspaces.",
+					},
+					"jql": map[string]interface{}{
+						"type":        "string",
+						"description": "JQL query string",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of issues per page",
+						"default":     50,
+					},
+					"cursor": map[string]interface{}{
+						"type":        "string",
+						"description": "next_cursor of the previous page; omit for the first page",
+					},
+					"fields": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+						"description": "Fields to return",
+					},
+					"preset": map[string]interface{}{
+						"type":        "string",
+						"description": "Named field preset (e.g. 'triage') whose fields are returned in addition to fields; without either, the 'default' preset applies if defined",
+					},
+					"summarize": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return one compact line per issue (key | summary | status | assignee | updated) instead of full issue objects; use for overviews. Ignores fields and preset.",
+						"default":     false,
+					},
 				},
 				"required": []string{"workspace_id", "jql"},
 			},
@@ -94,6 +189,30 @@ func (h *JiraHandler) ListTools() []mcp.Tool {
 				"required": []string{"workspace_id", "issue_key"},
 			},
 		},
+		{
+			Name:        "jira_hydrate_issues",
+			Description: "Get up to 50 issues by key in one call, fetched in parallel. Prefer this to several jira_get_issue calls. Issues that cannot be fetched are listed in errors.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"issue_keys": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Issue keys, e.g. [\"PROJ-123\", \"PROJ-124\"] (up to 50)",
+					},
+					"expand": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Fields to expand for every issue",
+					},
+				},
+				"required": []string{"workspace_id", "issue_keys"},
+			},
+		},
 		{
 			Name:        "jira_create_issue",
 			Description: "Create a new issue",
@@ -172,6 +291,66 @@ func (h *JiraHandler) ListTools() []mcp.Tool {
 				"required": []string{"workspace_id", "issue_key", "body"},
 			},
 		},
+		{
+			Name:        "jira_append_to_description",
+			Description: "Add content to the end of an issue's description, or to the end of the section under a heading, keeping the rest of the description as written. Use it to add notes without overwriting what people wrote.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"issue_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Issue key",
+					},
+					"heading": map[string]interface{}{
+						"type":        "string",
+						"description": "Heading text of the section to add to (optional; the end of the description when omitted)",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "Plain text to add; blank lines separate paragraphs",
+					},
+					"adf": map[string]interface{}{
+						"type":        "object",
+						"description": "Content as an Atlassian Document Format document or node, instead of content",
+					},
+				},
+				"required": []string{"workspace_id", "issue_key"},
+			},
+		},
+		{
+			Name:        "jira_update_description_section",
+			Description: "Replace the content under one heading of an issue's description, keeping the heading and the rest of the description",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"issue_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Issue key",
+					},
+					"heading": map[string]interface{}{
+						"type":        "string",
+						"description": "Heading text of the section to replace; the section includes its subsections",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "New content of the section as plain text (empty clears it); blank lines separate paragraphs",
+					},
+					"adf": map[string]interface{}{
+						"type":        "object",
+						"description": "Content as an Atlassian Document Format document or node, instead of content",
+					},
+				},
+				"required": []string{"workspace_id", "issue_key", "heading"},
+			},
+		},
 		{
 			Name:        "jira_transition_issue",
 			Description: "Transition an issue to a different status",
@@ -194,6 +373,83 @@ func (h *JiraHandler) ListTools() []mcp.Tool {
 				"required": []string{"workspace_id", "issue_key", "transition_id"},
 			},
 		},
+		{
+			Name:        "jira_bulk_transition",
+			Description: "Transition every issue matching a JQL query, e.g. close all stale \"Waiting for customer\" issues, optionally commenting on each. Always asks for confirmation first with a preview of the issues it would move; queries matching more than max_issues are refused.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"jql": map[string]interface{}{
+						"type":        "string",
+						"description": "JQL query selecting the issues to transition",
+					},
+					"transition_name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the transition, or of the status it leads to, e.g. \"Done\"; matched regardless of case. Issues without it are skipped.",
+					},
+					"comment": map[string]interface{}{
+						"type":        "string",
+						"description": "Comment to add to each issue as it is transitioned",
+					},
+					"max_issues": map[string]interface{}{
+						"type":        "number",
+						"description": "Refuse to run when the query matches more issues than this (up to 100)",
+						"default":     50,
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "List the issues that would be transitioned, and those skipped, without transitioning them",
+						"default":     false,
+					},
+				},
+				"required": []string{"workspace_id", "jql", "transition_name"},
+			},
+		},
+		{
+			Name:        "jira_import_issues",
+			Description: "Create issues in a project from CSV or JSON rows, e.g. to migrate a backlog from a spreadsheet or another tracker. Columns are mapped to Jira fields by name, or by the mapping argument, and each row is reported as created or failed with the reason.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"project_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Project to create the issues in",
+					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "Format of data; detected when omitted",
+						"enum":        []string{"csv", "json"},
+					},
+					"data": map[string]interface{}{
+						"type":        "string",
+						"description": "CSV text with a header row, or a JSON array of objects, one per issue (at most 200)",
+					},
+					"mapping": map[string]interface{}{
+						"type":        "object",
+						"description": "Jira field (ID or name) to import each column into, e.g. {\"Title\": \"summary\", \"Points\": \"Story Points\"}; map a column to \"\" to ignore it. Columns not named here are matched to fields by their own name.",
+					},
+					"issue_type": map[string]interface{}{
+						"type":        "string",
+						"description": "Issue type for rows without one",
+						"default":     "Task",
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Report the fields each row would be created with, without creating any issues",
+						"default":     false,
+					},
+				},
+				"required": []string{"workspace_id", "project_key", "data"},
+			},
+		},
 		{
 			Name:        "jira_get_agile_boards",
 			Description: "List all agile boards in a workspace",
@@ -567,13 +823,239 @@ func (h *JiraHandler) ListTools() []mcp.Tool {
 				"required": []string{"workspace_id", "link_id"},
 			},
 		},
+		{
+			Name:        "jira_find_similar_issues",
+			Description: "Find existing Jira issues that may duplicate a new one. Runs several text searches in the project and ranks the results by how many of the summary's and description's words they share. Call it before filing a bug.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"project_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Project to search",
+					},
+					"summary": map[string]interface{}{
+						"type":        "string",
+						"description": "Summary of the issue about to be filed",
+					},
+					"description": map[string]interface{}{
+						"type":        "string",
+						"description": "Description of the issue about to be filed",
+					},
+					"include_resolved": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Also match issues that are done",
+						"default":     true,
+					},
+					"min_score": map[string]interface{}{
+						"type":        "number",
+						"description": "Lowest similarity to return, from 0 to 1",
+						"default":     0.2,
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of issues to return (up to 50)",
+						"default":     10,
+					},
+				},
+				"required": []string{"workspace_id", "project_key", "summary"},
+			},
+		},
+		{
+			Name:        "jira_sprint_burndown",
+			Description: "Get a sprint's burndown chart data: the remaining estimate at the end of each day, reconstructed from issue changelogs, with the scope, completed work and an ideal guideline",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"board_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Board ID, whose estimation statistic and done column are used",
+					},
+					"sprint_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Sprint ID",
+					},
+					"estimate_field": map[string]interface{}{
+						"type":        "string",
+						"description": "Field to burn down instead of the board's estimation statistic, e.g. customfield_10016 or timeestimate, or issue_count to count issues",
+					},
+				},
+				"required": []string{"workspace_id", "board_id", "sprint_id"},
+			},
+		},
+		{
+			Name:        "jira_board_snapshot",
+			Description: "Get a snapshot of a board for a standup: each column with its issues, their assignees and days in the column, and the column's WIP limit. Scrum boards show their active sprint.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"board_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Board ID",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of board issues to place (up to 500)",
+						"default":     200,
+					},
+				},
+				"required": []string{"workspace_id", "board_id"},
+			},
+		},
+		{
+			Name:        "jira_suggest_assignee",
+			Description: "Rank the people who can be assigned issues in a project by their open issues there, fewest first, for triage. Optionally assigns an issue to the top suggestion.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"project_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Project key",
+					},
+					"issue_type": map[string]interface{}{
+						"type":        "string",
+						"description": "Only count open issues of this type, e.g. Bug",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of suggestions (up to 50)",
+						"default":     10,
+					},
+					"assign_issue_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Issue to assign to the top suggestion; needs the jira:write scope",
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "With assign_issue_key, return the request that would assign the issue without sending it",
+						"default":     false,
+					},
+				},
+				"required": []string{"workspace_id", "project_key"},
+			},
+		},
+		{
+			Name:        "jira_issue_aging",
+			Description: "Measure how long issues matching a JQL query spent in each status, from their changelogs, with per-status percentiles to find process bottlenecks",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"jql": map[string]interface{}{
+						"type":        "string",
+						"description": "JQL query selecting the issues to analyze",
+					},
+					"statuses": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only report these statuses, e.g. [\"In Review\"]",
+					},
+					"include_issues": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Include each issue's time in status",
+						"default":     true,
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of issues to analyze (up to 1000)",
+						"default":     200,
+					},
+				},
+				"required": []string{"workspace_id", "jql"},
+			},
+		},
+		{
+			Name:        "jira_comment_digest",
+			Description: "Collect the recent comments of an issue, or of the issues matching a JQL query, grouped by issue in the order they were made, with each comment shortened to its author, time and first 300 characters. Useful for catching up on or triaging escalations in one call.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"issue_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Issue key (e.g., 'PROJ-123'); pass this or jql",
+					},
+					"jql": map[string]interface{}{
+						"type":        "string",
+						"description": "JQL query selecting the issues; pass this or issue_key",
+					},
+					"days": map[string]interface{}{
+						"type":        "number",
+						"description": "Only include comments from the last this many days (up to 90)",
+						"default":     7,
+					},
+					"max_comments": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of comments per issue, the most recent (up to 50)",
+						"default":     10,
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of issues to check, most recently updated first (up to 50)",
+						"default":     20,
+					},
+				},
+				"required": []string{"workspace_id"},
+			},
+		},
+		{
+			Name:        "jira_render_issue",
+			Description: "Render an issue as one readable Markdown or HTML document: its key fields, description, links and subtasks, and latest comments. Useful for a full brief on an issue in one call.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"issue_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Issue key (e.g., 'PROJ-123')",
+					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "Document format",
+						"enum":        []string{"markdown", "html"},
+						"default":     "markdown",
+					},
+					"comments": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of latest comments to include (up to 50)",
+						"default":     5,
+					},
+				},
+				"required": []string{"workspace_id", "issue_key"},
+			},
+		},
 	}
 }
 
 // HandleTool handles a Jira tool call
 func (h *JiraHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	// Directory tools without a workspace_id query every workspace
 	workspaceID, ok := call.Arguments["workspace_id"].(string)
-	if !ok {
+	if !ok && !IsAdminTool(call.Name) {
 		return mcp.ToolResult{
 			Content: []mcp.ContentBlock{
 				{Type: "text", Text: "Error: workspace_id is required"},
@@ -586,59 +1068,98 @@ func (h *JiraHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResu
 		Action:      getJiraActionFromToolName(call.Name),
 		WorkspaceID: workspaceID,
 		UserID:      userID,
+		OrgID:       call.OrgID,
 		Params:      call.Arguments,
-		RequestID:   fmt.Sprintf("req_%d", atomic.AddInt64(&requestIDCounter, 1)),
+		RequestID:   requestIDFor(call),
+	}
+	if action, ok := h.customActions[call.Name]; ok {
+		req.Action = action
+	}
+	pagedSearch := call.Name == jiraListIssuesV2
+	if pagedSearch {
+		req.Params = listIssuesCursorParams(call.Arguments)
+	}
+
+	// Destructive tools first describe the change and run once the caller confirms it
+	if h.confirmations.Required(call.Name) && !atlassian.IsDryRun(call.Arguments) {
+		if result, err := h.confirmations.gate(call, userID, func() (interface{}, error) { return h.describeChange(req) }); result != nil {
+			return *result, err
+		}
+		req.Params = withoutConfirmationToken(req.Params)
+	} else if h.confirmations == nil && slices.Contains(AlwaysConfirmTools, call.Name) && !atlassian.IsDryRun(call.Arguments) {
+		message := fmt.Sprintf("%s runs only once confirmed, and confirmation is unavailable; call it with dry_run to preview", call.Name)
+		return errorResult(req.RequestID, message), errors.New(message)
+	}
+
+	// Auto-assignment follows the suggestion with an update of the issue
+	if key, _ := call.Arguments["assign_issue_key"].(string); key != "" && call.Name == "jira_suggest_assignee" {
+		return h.assignSuggested(call, req, key)
 	}
 
 	resp, err := h.callService(req)
 	if err != nil {
-		return mcp.ToolResult{
-			Content: []mcp.ContentBlock{
-				{Type: "text", Text: fmt.Sprintf("Error: %v", err)},
-			},
-			IsError: true,
-		}, err
+		return errorResult(req.RequestID, err.Error()), err
 	}
 
 	if !resp.Success {
-		errorMsg := "Unknown error"
-		if resp.Error != nil {
-			errorMsg = resp.Error.Message
+		return serviceErrorResult(req.RequestID, resp.Error), serviceError(resp.Error)
+	}
+
+	data := resp.Data
+	if pagedSearch {
+		if data, err = listIssuesPage(resp.Data); err != nil {
+			return errorResult(req.RequestID, err.Error()), err
 		}
-		return mcp.ToolResult{
-			Content: []mcp.ContentBlock{
-				{Type: "text", Text: fmt.Sprintf("Error: %s", errorMsg)},
-			},
-			IsError: true,
-		}, fmt.Errorf(errorMsg)
 	}
 
 	// Convert response to JSON string
-	resultJSON, _ := json.MarshalIndent(resp.Data, "", "  ")
+	resultJSON, _ := json.MarshalIndent(data, "", "  ")
 
-	return mcp.ToolResult{
+	result := mcp.ToolResult{
 		Content: []mcp.ContentBlock{
 			{Type: "text", Text: string(resultJSON)},
 		},
-	}, nil
+	}
+	if resp.Cached != nil {
+		result.Content = append(result.Content, cacheHint(resp.Cached))
+	}
+	if tool, ok := h.deprecated[call.Name]; ok {
+		result = mcp.WithDeprecationWarning(result, tool)
+	}
+	return result, nil
+}
+
+// Deprecation returns why a Jira tool is deprecated and what replaces it, or ""
+func (h *JiraHandler) Deprecation(toolName string) string {
+	return h.deprecated[toolName].Deprecated
 }
 
 func getJiraActionFromToolName(toolName string) string {
 	switch toolName {
 	case "jira_list_projects":
 		return "list_projects"
-	case "jira_list_issues":
+	case "jira_list_issues", jiraListIssuesV2:
 		return "list_issues"
 	case "jira_get_issue":
 		return "get_issue"
+	case "jira_hydrate_issues":
+		return "hydrate_issues"
 	case "jira_create_issue":
 		return "create_issue"
 	case "jira_update_issue":
 		return "update_issue"
 	case "jira_add_comment":
 		return "add_comment"
+	case "jira_append_to_description":
+		return "append_to_description"
+	case "jira_update_description_section":
+		return "update_description_section"
 	case "jira_transition_issue":
 		return "transition_issue"
+	case "jira_import_issues":
+		return "import_issues"
+	case "jira_bulk_transition":
+		return "bulk_transition"
 	case "jira_get_agile_boards":
 		return "get_agile_boards"
 	case "jira_get_board_issues":
@@ -673,7 +1194,25 @@ func getJiraActionFromToolName(toolName string) string {
 		return "create_issue_link"
 	case "jira_remove_issue_link":
 		return "remove_issue_link"
+	case "jira_find_similar_issues":
+		return "find_similar_issues"
+	case "jira_sprint_burndown":
+		return "sprint_burndown"
+	case "jira_board_snapshot":
+		return "board_snapshot"
+	case "jira_issue_aging":
+		return "issue_aging"
+	case "jira_comment_digest":
+		return "comment_digest"
+	case "jira_render_issue":
+		return "render_issue"
+	case "jira_suggest_assignee":
+		return "suggest_assignee"
 	default:
+		if IsBitbucketTool(toolName) || IsOpsgenieTool(toolName) || IsAdminTool(toolName) {
+			// The Jira service names these actions after their tools
+			return toolName
+		}
 		return ""
 	}
 }
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// syncLabelsTool is sync_labels, which brings the labels and components of a Jira project,
+// or the labels of a Confluence space, in one workspace into another
+func syncLabelsTool() mcp.Tool {
+	return mcp.Tool{
+		Name: "sync_labels",
+		Description: "Compare the label taxonomy of a Jira project or Confluence space across two workspaces, e.g. mirrored client and internal sites. " +
+			"Only Jira components are ever written: with dry_run false, the components missing from the target project are created. Labels only exist on issues and pages, so missing labels are listed, never applied, " +
+			"and Confluence spaces are only compared (dry_run false is rejected for them).",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"product": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"jira", "confluence"},
+					"description": "Whether to sync a Jira project or a Confluence space",
+				},
+				"source_workspace_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Workspace to copy the taxonomy from",
+				},
+				"target_workspace_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Workspace to copy the taxonomy to",
+				},
+				"scope": map[string]interface{}{
+					"type":        "string",
+					"description": "Project key (Jira) or space key (Confluence) in the source workspace",
+				},
+				"target_scope": map[string]interface{}{
+					"type":        "string",
+					"description": "Project or space key in the target workspace, when it differs from scope",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only report the differences; false creates the missing Jira components",
+					"default":     true,
+				},
+			},
+			"required": []string{"product", "source_workspace_id", "target_workspace_id", "scope"},
+		},
+	}
+}
+
+// labelTaxonomy is what a service's get_label_taxonomy action returns
+type labelTaxonomy struct {
+	Labels []struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	} `json:"labels"`
+	Components []models.Component `json:"components"`
+	Truncated  bool               `json:"truncated"`
+}
+
+// componentFailure is a component that could not be created
+type componentFailure struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// handleSyncLabels handles a sync_labels call. Both taxonomies are read first; unless it is
+// a dry run, the source's components missing from the target are then created there.
+// Nothing else is written: labels are only listed, and Confluence spaces only compared.
+func (h *CrossProductHandler) handleSyncLabels(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	product, _ := call.Arguments["product"].(string)
+	sourceID, _ := call.Arguments["source_workspace_id"].(string)
+	targetID, _ := call.Arguments["target_workspace_id"].(string)
+	scope, _ := call.Arguments["scope"].(string)
+	if sourceID == "" || targetID == "" || scope == "" {
+		return fail("source_workspace_id, target_workspace_id and scope are required")
+	}
+	targetScope, _ := call.Arguments["target_scope"].(string)
+	if targetScope == "" {
+		targetScope = scope
+	}
+	if sourceID == targetID && strings.EqualFold(scope, targetScope) {
+		return fail("the source and target are the same")
+	}
+	dryRun := true
+	if d, ok := call.Arguments["dry_run"].(bool); ok {
+		dryRun = d
+	}
+
+	// The scopes needed depend on the product, so they are checked here
+	scopeParam := "project_key"
+	required := auth.ScopeJiraRead
+	switch product {
+	case "jira":
+		if !dryRun {
+			required = auth.ScopeJiraWrite
+		}
+	case "confluence":
+		if !dryRun {
+			return fail("sync_labels only compares Confluence spaces, as there is nothing it can create in them; labels must be applied to pages with confluence_add_label. Call it with dry_run true")
+		}
+		scopeParam = "space_key"
+		required = auth.ScopeConfluenceRead
+	default:
+		return fail(fmt.Sprintf("invalid product %q; use jira or confluence", product))
+	}
+	caller := &auth.UserContext{UserID: userID, OrgID: call.OrgID, Scopes: call.Scopes}
+	if !caller.HasScope(required) {
+		return fail(fmt.Sprintf("insufficient_scope: sync_labels requires the %s scope for %s", required, product))
+	}
+
+	taxonomy := func(workspaceID, key string) (labelTaxonomy, error) {
+		var result labelTaxonomy
+		params := map[string]interface{}{scopeParam: key}
+		if product == "confluence" {
+			return result, h.callConfluence(models.ConfluenceRequest{
+				Action:      "get_label_taxonomy",
+				WorkspaceID: workspaceID,
+				UserID:      userID,
+				OrgID:       call.OrgID,
+				Params:      params,
+				RequestID:   requestID,
+			}, &result)
+		}
+		resp, err := h.jira.callService(models.JiraRequest{
+			Action:      "get_label_taxonomy",
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+			OrgID:       call.OrgID,
+			Params:      params,
+			RequestID:   requestID,
+		})
+		if err == nil && !resp.Success {
+			err = serviceError(resp.Error)
+		}
+		if err == nil {
+			err = decodeServiceData(resp.Data, &result)
+		}
+		return result, err
+	}
+
+	source, err := taxonomy(sourceID, scope)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to read the source taxonomy: %v", err))
+	}
+	target, err := taxonomy(targetID, targetScope)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to read the target taxonomy: %v", err))
+	}
+
+	// Jira labels are case sensitive; Confluence lowercases them
+	targetLabels := make(map[string]bool, len(target.Labels))
+	for _, label := range target.Labels {
+		targetLabels[label.Name] = true
+	}
+	missingLabels := []map[string]interface{}{}
+	for _, label := range source.Labels {
+		if !targetLabels[label.Name] {
+			missingLabels = append(missingLabels, map[string]interface{}{"name": label.Name, "count": label.Count})
+		}
+	}
+
+	result := map[string]interface{}{
+		"product": product,
+		"dry_run": dryRun,
+		"source":  map[string]interface{}{"workspace_id": sourceID, scopeParam: scope},
+		"target":  map[string]interface{}{"workspace_id": targetID, scopeParam: targetScope},
+		"labels": map[string]interface{}{
+			"source_total": len(source.Labels),
+			"target_total": len(target.Labels),
+			"missing":      missingLabels,
+		},
+		"truncated": source.Truncated || target.Truncated,
+	}
+	if product == "confluence" {
+		return jsonResult(result)
+	}
+
+	// Jira component names are unique in a project regardless of case
+	targetComponents := make(map[string]bool, len(target.Components))
+	for _, component := range target.Components {
+		targetComponents[strings.ToLower(component.Name)] = true
+	}
+	missingComponents := []models.Component{}
+	for _, component := range source.Components {
+		if !targetComponents[strings.ToLower(component.Name)] {
+			missingComponents = append(missingComponents, models.Component{Name: component.Name, Description: component.Description})
+		}
+	}
+	components := map[string]interface{}{
+		"source_total": len(source.Components),
+		"target_total": len(target.Components),
+		"missing":      missingComponents,
+	}
+	result["components"] = components
+	if dryRun {
+		return jsonResult(result)
+	}
+
+	created := []models.Component{}
+	failed := []componentFailure{}
+	for _, component := range missingComponents {
+		resp, err := h.jira.callService(models.JiraRequest{
+			Action:      "create_component",
+			WorkspaceID: targetID,
+			UserID:      userID,
+			OrgID:       call.OrgID,
+			Params: map[string]interface{}{
+				"project_key": targetScope,
+				"name":        component.Name,
+				"description": component.Description,
+			},
+			RequestID: requestID,
+		})
+		if err == nil && !resp.Success {
+			err = serviceError(resp.Error)
+		}
+		var createdComponent models.Component
+		if err == nil {
+			err = decodeServiceData(resp.Data, &createdComponent)
+		}
+		if err != nil {
+			failed = append(failed, componentFailure{Name: component.Name, Error: err.Error()})
+			continue
+		}
+		created = append(created, createdComponent)
+	}
+	components["created"] = created
+	components["failed"] = failed
+	return jsonResult(result)
+}
@@ -5,15 +5,22 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/cache"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
 	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
 )
 
+// workspaceListCacheTTL is how long list_workspaces results are cached per user
+const workspaceListCacheTTL = 5 * time.Minute
+
 // ManagementHandler handles workspace management tools
 type ManagementHandler struct {
 	credStore storage.CredentialStoreInterface
 	cache     *cache.SimpleCache
+	validator *atlassian.Validator
+	health    *WorkspaceHealth // Reports disconnected workspaces in workspace_status (nil = none)
 }
 
 // NewManagementHandler creates a new management handler
@@ -21,9 +28,36 @@ func NewManagementHandler(credStore storage.CredentialStoreInterface) *Managemen
 	return &ManagementHandler{
 		credStore: credStore,
 		cache:     cache.NewSimpleCache(),
+		validator: atlassian.NewValidator(),
 	}
 }
 
+// WithHealth makes workspace_status report workspaces whose credentials were rejected
+func (h *ManagementHandler) WithHealth(health *WorkspaceHealth) *ManagementHandler {
+	h.health = health
+	return h
+}
+
+// IsManagementTool checks if a tool is a workspace management tool
+func IsManagementTool(name string) bool {
+	return name == "list_workspaces" || name == "workspace_status" || name == "connect_workspace"
+}
+
+// InvalidateUser drops the cached workspace listing for a user or organization
+func (h *ManagementHandler) InvalidateUser(userID string) {
+	h.cache.Delete("workspaces:" + userID)
+}
+
+// CacheStats reports the workspace listing cache's size and hit rate
+func (h *ManagementHandler) CacheStats() cache.Stats {
+	return h.cache.Stats()
+}
+
+// CacheTTL returns how long workspace listings are cached
+func (h *ManagementHandler) CacheTTL() time.Duration {
+	return workspaceListCacheTTL
+}
+
 // ListTools returns the list of management tools
 func (h *ManagementHandler) ListTools() []mcp.Tool {
 	return []mcp.Tool{
@@ -51,6 +85,32 @@ func (h *ManagementHandler) ListTools() []mcp.Tool {
 				"required": []string{"workspace_id"},
 			},
 		},
+		{
+			Name:        "connect_workspace",
+			Description: "Connect a new Atlassian workspace from the chat. Clients that support elicitation are asked for the site URL, email and API token in a form (so the token never passes through the conversation); other clients must pass them as arguments. The token is validated against Atlassian before the workspace is saved.",
+			InputType:   "object",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"site_url": map[string]interface{}{
+						"type":        "string",
+						"description": "Atlassian site, e.g. https://your-company.atlassian.net (asked for when omitted)",
+					},
+					"email": map[string]interface{}{
+						"type":        "string",
+						"description": "Email address of the Atlassian account (asked for when omitted)",
+					},
+					"api_token": map[string]interface{}{
+						"type":        "string",
+						"description": "Atlassian API token; prefer leaving it out so the user enters it in a form",
+					},
+					"workspace_name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name for the workspace (defaults to the site URL)",
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -58,9 +118,11 @@ func (h *ManagementHandler) ListTools() []mcp.Tool {
 func (h *ManagementHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
 	switch call.Name {
 	case "list_workspaces":
-		return h.handleListWorkspaces(userID)
+		return h.handleListWorkspaces(userID, call.OrgID)
 	case "workspace_status":
 		return h.handleWorkspaceStatus(call, userID)
+	case "connect_workspace":
+		return h.handleConnectWorkspace(call, userID)
 	default:
 		return mcp.ToolResult{
 			Content: []mcp.ContentBlock{
@@ -71,21 +133,14 @@ func (h *ManagementHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.To
 	}
 }
 
-func (h *ManagementHandler) handleListWorkspaces(userID string) (mcp.ToolResult, error) {
-	// Check cache first
-	cacheKey := "workspaces:" + userID
-	if cached, found := h.cache.Get(cacheKey); found {
-		if resultJSON, ok := cached.(string); ok {
-			return mcp.ToolResult{
-				Content: []mcp.ContentBlock{
-					{Type: "text", Text: resultJSON},
-				},
-			}, nil
-		}
+// handleListWorkspaces lists the user's workspaces followed by their organization's shared ones
+func (h *ManagementHandler) handleListWorkspaces(userID, orgID string) (mcp.ToolResult, error) {
+	workspaces, err := h.listWorkspaces(userID)
+	if err == nil && orgID != "" {
+		var shared []models.AtlassianCredential
+		shared, err = h.listWorkspaces(orgID)
+		workspaces = append(workspaces, shared...)
 	}
-
-	// Cache miss - fetch from storage
-	workspaces, err := h.credStore.ListWorkspaces(userID)
 	if err != nil {
 		return mcp.ToolResult{
 			Content: []mcp.ContentBlock{
@@ -97,16 +152,30 @@ func (h *ManagementHandler) handleListWorkspaces(userID string) (mcp.ToolResult,
 
 	resultJSON, _ := json.MarshalIndent(workspaces, "", "  ")
 
-	// Cache for 5 minutes
-	h.cache.Set(cacheKey, string(resultJSON), 5*time.Minute)
-
 	return mcp.ToolResult{
 		Content: []mcp.ContentBlock{
 			{Type: "text", Text: string(resultJSON)},
-			},
+		},
 	}, nil
 }
 
+// listWorkspaces returns the workspaces owned by a user or organization, cached for 5 minutes
+func (h *ManagementHandler) listWorkspaces(ownerID string) ([]models.AtlassianCredential, error) {
+	cacheKey := "workspaces:" + ownerID
+	if cached, found := h.cache.Get(cacheKey); found {
+		if workspaces, ok := cached.([]models.AtlassianCredential); ok {
+			return workspaces, nil
+		}
+	}
+
+	workspaces, err := h.credStore.ListWorkspaces(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	h.cache.Set(cacheKey, workspaces, workspaceListCacheTTL)
+	return workspaces, nil
+}
+
 func (h *ManagementHandler) handleWorkspaceStatus(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
 	workspaceID, ok := call.Arguments["workspace_id"].(string)
 	if !ok {
@@ -118,7 +187,7 @@ func (h *ManagementHandler) handleWorkspaceStatus(call mcp.ToolCall, userID stri
 		}, fmt.Errorf("workspace_id is required")
 	}
 
-	_, err := h.credStore.GetCredentials(userID, workspaceID)
+	_, err := storage.GetMemberCredentials(h.credStore, userID, call.OrgID, workspaceID)
 	if err != nil {
 		return mcp.ToolResult{
 			Content: []mcp.ContentBlock{
@@ -132,6 +201,12 @@ func (h *ManagementHandler) handleWorkspaceStatus(call mcp.ToolCall, userID stri
 		"workspace_id": workspaceID,
 		"status":       "connected",
 	}
+	if h.health != nil {
+		if reason := h.health.Failure(userID, workspaceID); reason != "" {
+			result["status"] = "disconnected"
+			result["error"] = disconnectedError(workspaceID, reason).Message
+		}
+	}
 
 	resultJSON, _ := json.MarshalIndent(result, "", "  ")
 
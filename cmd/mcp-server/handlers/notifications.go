@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/notify"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// credentialAlertInterval is how often a user hears about the same workspace's rejected credentials
+const credentialAlertInterval = time.Hour
+
+// NotificationHandler manages users' Slack and Teams notification channels, serves the
+// notify_channel tool and reports server events to the channels
+type NotificationHandler struct {
+	store    storage.NotificationChannelStoreInterface
+	notifier *notify.Notifier
+}
+
+// NewNotificationHandler creates a new notification handler. store may be nil when
+// notification channels are not supported (file-based storage).
+func NewNotificationHandler(store storage.NotificationChannelStoreInterface) *NotificationHandler {
+	return &NotificationHandler{
+		store:    store,
+		notifier: notify.NewNotifier(store),
+	}
+}
+
+// Notifier returns the notifier that delivers server events to the users' channels
+func (h *NotificationHandler) Notifier() *notify.Notifier {
+	return h.notifier
+}
+
+// IsNotificationTool reports whether a tool posts to notification channels
+func IsNotificationTool(name string) bool {
+	return name == "notify_channel"
+}
+
+// ListTools returns the notification tools
+func (h *NotificationHandler) ListTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "notify_channel",
+			Description: "Post a message to one of your Slack or Microsoft Teams notification channels, e.g. to share a summary or an alert with your team. Channels are configured in the dashboard.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"message": map[string]interface{}{
+						"type":        "string",
+						"description": "Message text. Slack renders its mrkdwn formatting and Teams renders Markdown.",
+					},
+					"channel": map[string]interface{}{
+						"type":        "string",
+						"description": "Name or ID of the channel (optional when you have only one)",
+					},
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "Heading shown above the message",
+					},
+					"level": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{notify.LevelInfo, notify.LevelSuccess, notify.LevelWarning, notify.LevelError},
+						"description": "Sets the message's color",
+						"default":     notify.LevelInfo,
+					},
+					"url": map[string]interface{}{
+						"type":        "string",
+						"description": "Link to add to the message, e.g. to a Jira issue or Confluence page",
+					},
+				},
+				"required": []string{"message"},
+			},
+		},
+	}
+}
+
+// HandleTool handles a notify_channel call
+func (h *NotificationHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	if call.Name != "notify_channel" {
+		return fail(fmt.Sprintf("unknown tool: %s", call.Name))
+	}
+	if h.store == nil {
+		return fail("notification channels require database storage (DATABASE_URL)")
+	}
+
+	text, _ := call.Arguments["message"].(string)
+	if strings.TrimSpace(text) == "" {
+		return fail("message is required")
+	}
+	msg := notify.Message{Text: text}
+	msg.Title, _ = call.Arguments["title"].(string)
+	msg.Level, _ = call.Arguments["level"].(string)
+	msg.URL, _ = call.Arguments["url"].(string)
+	name, _ := call.Arguments["channel"].(string)
+
+	channels, err := h.store.ListNotificationChannels(userID)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to list notification channels: %v", err))
+	}
+	channel, err := findNotificationChannel(channels, name)
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := notify.Send(ctx, channel, msg); err != nil {
+		return fail(fmt.Sprintf("failed to post to %s: %v", channel.Name, err))
+	}
+
+	return jsonResult(map[string]interface{}{
+		"channel": channel.Name,
+		"kind":    channel.Kind,
+		"sent":    true,
+	})
+}
+
+// findNotificationChannel picks a channel by name or ID, or the only channel when name is empty
+func findNotificationChannel(channels []models.NotificationChannel, name string) (models.NotificationChannel, error) {
+	if len(channels) == 0 {
+		return models.NotificationChannel{}, errors.New("no notification channels configured; add one in the dashboard")
+	}
+	if name == "" {
+		if len(channels) > 1 {
+			names := make([]string, 0, len(channels))
+			for _, c := range channels {
+				names = append(names, c.Name)
+			}
+			return models.NotificationChannel{}, fmt.Errorf("channel is required; choose one of: %s", strings.Join(names, ", "))
+		}
+		return channels[0], nil
+	}
+	for _, c := range channels {
+		if c.ID == name || strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+	return models.NotificationChannel{}, fmt.Errorf("notification channel not found: %s", name)
+}
+
+// WrapJira reports Atlassian rejecting a workspace's credentials to the user's channels
+func (h *NotificationHandler) WrapJira(callService func(models.JiraRequest) (*models.JiraResponse, error)) func(models.JiraRequest) (*models.JiraResponse, error) {
+	return func(req models.JiraRequest) (*models.JiraResponse, error) {
+		resp, err := callService(req)
+		if err == nil && !resp.Success {
+			h.credentialFailure(req.UserID, req.WorkspaceID, resp.Error)
+		}
+		return resp, err
+	}
+}
+
+// WrapConfluence reports Atlassian rejecting a workspace's credentials to the user's channels
+func (h *NotificationHandler) WrapConfluence(callService func(models.ConfluenceRequest) (*models.ConfluenceResponse, error)) func(models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+	return func(req models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+		resp, err := callService(req)
+		if err == nil && !resp.Success {
+			h.credentialFailure(req.UserID, req.WorkspaceID, resp.Error)
+		}
+		return resp, err
+	}
+}
+
+// credentialFailure notifies the user when a service reports their workspace's API token
+// as rejected. Unknown workspaces are reported with the same code and are ignored.
+func (h *NotificationHandler) credentialFailure(userID, workspaceID string, info *models.ErrorInfo) {
+	if info == nil || info.Code != models.ErrCodeAuthFailed || strings.Contains(info.Message, "workspace not found") {
+		return
+	}
+	h.notifier.NotifyOnce("credentials:"+userID+":"+workspaceID, credentialAlertInterval, userID, notify.EventCredentialFailure, notify.Message{
+		Title: "Atlassian rejected your workspace credentials",
+		Text:  "Tool calls against this workspace fail until its API token is replaced in the dashboard. The token may have expired or been revoked.",
+		Level: notify.LevelError,
+		Fields: []notify.Field{
+			{Name: "Workspace", Value: workspaceID},
+			{Name: "Error", Value: info.Message},
+		},
+	})
+}
+
+// CreateNotificationChannelRequest represents the request to add a notification channel
+type CreateNotificationChannelRequest struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"` // slack or teams
+	WebhookURL string   `json:"webhookUrl"`
+	Events     []string `json:"events,omitempty"` // Server events to deliver; empty means all
+}
+
+// HandleChannels handles /api/notifications/channels, /api/notifications/channels/{id}
+// and /api/notifications/channels/{id}/test
+func (h *NotificationHandler) HandleChannels(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		http.Error(w, "Notification channels require database storage (DATABASE_URL)", http.StatusNotImplemented)
+		return
+	}
+
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	channelID, sub := "", ""
+	if len(r.URL.Path) > len("/api/notifications/channels/") {
+		channelID, sub, _ = strings.Cut(r.URL.Path[len("/api/notifications/channels/"):], "/")
+	}
+
+	switch {
+	case channelID == "" && r.Method == http.MethodGet:
+		h.handleListChannels(w, userCtx)
+	case channelID == "" && r.Method == http.MethodPost:
+		h.handleCreateChannel(w, r, userCtx)
+	case sub == "" && r.Method == http.MethodDelete:
+		h.handleDeleteChannel(w, userCtx, channelID)
+	case sub == "test" && r.Method == http.MethodPost:
+		h.handleTestChannel(w, r, userCtx, channelID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCreateChannel handles POST /api/notifications/channels
+func (h *NotificationHandler) handleCreateChannel(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext) {
+	var req CreateNotificationChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || req.WebhookURL == "" {
+		http.Error(w, "Missing required fields: name, webhookUrl", http.StatusBadRequest)
+		return
+	}
+	if req.Kind != models.NotificationKindSlack && req.Kind != models.NotificationKindTeams {
+		http.Error(w, "kind must be slack or teams", http.StatusBadRequest)
+		return
+	}
+	if u, err := url.Parse(req.WebhookURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		http.Error(w, "webhookUrl must be an https URL", http.StatusBadRequest)
+		return
+	}
+	for _, event := range req.Events {
+		if !isNotifyEvent(event) {
+			http.Error(w, fmt.Sprintf("unknown event %q (must be one of: %s)", event, strings.Join(notify.Events, ", ")), http.StatusBadRequest)
+			return
+		}
+	}
+
+	existing, err := h.store.ListNotificationChannels(userCtx.UserID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list notification channels: %v", err), http.StatusInternalServerError)
+		return
+	}
+	for _, c := range existing {
+		if strings.EqualFold(c.Name, req.Name) {
+			http.Error(w, fmt.Sprintf("A notification channel named %q already exists", c.Name), http.StatusConflict)
+			return
+		}
+	}
+
+	channel := models.NotificationChannel{
+		ID:         uuid.New().String(),
+		UserID:     userCtx.UserID,
+		Name:       req.Name,
+		Kind:       req.Kind,
+		WebhookURL: req.WebhookURL,
+		Events:     req.Events,
+		CreatedAt:  time.Now(),
+	}
+	if err := h.store.CreateNotificationChannel(&channel); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save notification channel: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(maskNotificationChannel(channel))
+}
+
+// handleListChannels handles GET /api/notifications/channels
+func (h *NotificationHandler) handleListChannels(w http.ResponseWriter, userCtx *auth.UserContext) {
+	channels, err := h.store.ListNotificationChannels(userCtx.UserID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list notification channels: %v", err), http.StatusInternalServerError)
+		return
+	}
+	for i := range channels {
+		channels[i] = maskNotificationChannel(channels[i])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(channels)
+}
+
+// handleDeleteChannel handles DELETE /api/notifications/channels/{id}
+func (h *NotificationHandler) handleDeleteChannel(w http.ResponseWriter, userCtx *auth.UserContext, channelID string) {
+	if err := h.store.DeleteNotificationChannel(userCtx.UserID, channelID); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Notification channel not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete notification channel: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTestChannel handles POST /api/notifications/channels/{id}/test, which posts a test message
+func (h *NotificationHandler) handleTestChannel(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext, channelID string) {
+	channels, err := h.store.ListNotificationChannels(userCtx.UserID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list notification channels: %v", err), http.StatusInternalServerError)
+		return
+	}
+	for _, channel := range channels {
+		if channel.ID != channelID {
+			continue
+		}
+		err := notify.Send(r.Context(), channel, notify.Message{
+			Title: "Trilix test notification",
+			Text:  "This channel will receive Trilix notifications.",
+			Level: notify.LevelSuccess,
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to send test notification: %v", err), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Error(w, "Notification channel not found", http.StatusNotFound)
+}
+
+func isNotifyEvent(event string) bool {
+	for _, e := range notify.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// maskNotificationChannel hides the secret part of the webhook URL, keeping its host so
+// users can tell channels apart
+func maskNotificationChannel(channel models.NotificationChannel) models.NotificationChannel {
+	if u, err := url.Parse(channel.WebhookURL); err == nil && u.Host != "" {
+		channel.WebhookURL = u.Scheme + "://" + u.Host + "/…"
+	} else {
+		channel.WebhookURL = "…"
+	}
+	if channel.Events == nil {
+		channel.Events = []string{}
+	}
+	return channel
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// IsOpsgenieTool reports whether a tool is an Opsgenie alert tool. Like the Bitbucket
+// tools, these are served by the Jira service and handled by JiraHandler.
+func IsOpsgenieTool(name string) bool {
+	return strings.HasPrefix(name, "opsgenie_")
+}
+
+func opsgenieTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "opsgenie_list_alerts",
+			Description: "List Opsgenie / Jira Service Management alerts, newest first. Uses the workspace's Opsgenie API key if it has one, otherwise Jira Service Management Operations.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Alert search query (e.g., 'status:open AND priority:P1')",
+						"default":     "status:open",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of results (up to 100)",
+						"default":     25,
+					},
+				},
+				"required": []string{"workspace_id"},
+			},
+		},
+		{
+			Name:        "opsgenie_acknowledge_alert",
+			Description: "Acknowledge an alert",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"alert_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Alert ID",
+					},
+					"note": map[string]interface{}{
+						"type":        "string",
+						"description": "Note to add to the alert",
+					},
+				},
+				"required": []string{"workspace_id", "alert_id"},
+			},
+		},
+		{
+			Name:        "opsgenie_close_alert",
+			Description: "Close an alert",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"alert_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Alert ID",
+					},
+					"note": map[string]interface{}{
+						"type":        "string",
+						"description": "Note to add to the alert",
+					},
+				},
+				"required": []string{"workspace_id", "alert_id"},
+			},
+		},
+		{
+			Name:        "opsgenie_create_alert",
+			Description: "Create an alert, optionally linked to a Jira issue. Give a message, an issue_key, or both; with only an issue_key the issue summary becomes the message.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID",
+					},
+					"message": map[string]interface{}{
+						"type":        "string",
+						"description": "Alert message (up to 130 characters)",
+					},
+					"issue_key": map[string]interface{}{
+						"type":        "string",
+						"description": "Jira issue to link the alert to (e.g., OPS-123)",
+					},
+					"description": map[string]interface{}{
+						"type":        "string",
+						"description": "Alert description",
+					},
+					"priority": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"P1", "P2", "P3", "P4", "P5"},
+						"description": "Alert priority",
+						"default":     "P3",
+					},
+					"tags": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+						"description": "Alert tags",
+					},
+					"alias": map[string]interface{}{
+						"type":        "string",
+						"description": "Deduplication key; open alerts with the same alias are merged",
+					},
+				},
+				"required": []string{"workspace_id"},
+			},
+		},
+	}
+}
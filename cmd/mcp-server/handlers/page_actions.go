@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// Confluence stores task lists, including the action items of meeting notes, as
+// <ac:task> elements in the page's storage format
+var (
+	pageTaskPattern     = regexp.MustCompile(`(?s)<ac:task>.*?</ac:task>`)
+	pageTaskStatus      = regexp.MustCompile(`(?s)<ac:task-status>\s*(\w+)\s*</ac:task-status>`)
+	pageTaskBody        = regexp.MustCompile(`(?s)<ac:task-body>(.*?)</ac:task-body>`)
+	pageTaskMention     = regexp.MustCompile(`<ri:user\s[^>]*ri:account-id="([^"]+)"`)
+	pageTaskDueDate     = regexp.MustCompile(`<time\s[^>]*datetime="(\d{4}-\d{2}-\d{2})"`)
+	pageTaskIssueLink   = regexp.MustCompile(`/browse/[A-Z][A-Z0-9_]+-\d+`)
+	storageTagPattern   = regexp.MustCompile(`<[^>]*>`)
+	storageSpacePattern = regexp.MustCompile(`\s+`)
+)
+
+// maxSummaryLength is the longest summary Jira accepts
+const maxSummaryLength = 255
+
+// createIssuesFromPageTool is create_issues_from_page, which turns a page's open tasks
+// into Jira issues and links each task to its issue
+func createIssuesFromPageTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "create_issues_from_page",
+		Description: "Create a Jira issue for each open task (action item) on a Confluence page, e.g. meeting notes, and add the new issue keys to the tasks on the page. Mentioned users become assignees and task dates become due dates. Tasks that already link to an issue are skipped. If any step fails, the issues created so far are deleted.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"workspace_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Workspace ID",
+				},
+				"page_id": map[string]interface{}{
+					"type":        "string",
+					"description": "ID of the Confluence page with the tasks",
+				},
+				"project_key": map[string]interface{}{
+					"type":        "string",
+					"description": "Jira project to create the issues in",
+				},
+				"issue_type": map[string]interface{}{
+					"type":        "string",
+					"description": "Issue type of the new issues",
+					"default":     "Task",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only list the tasks that would become issues",
+					"default":     false,
+				},
+			},
+			"required": []string{"workspace_id", "page_id", "project_key"},
+		},
+	}
+}
+
+// pageTask is an open task found on a page
+type pageTask struct {
+	start, end int    // Position of the <ac:task> element in the storage format
+	Summary    string `json:"summary"`
+	AssigneeID string `json:"assignee_account_id,omitempty"`
+	DueDate    string `json:"due_date,omitempty"`
+	IssueKey   string `json:"issue_key,omitempty"`
+	IssueURL   string `json:"issue_url,omitempty"`
+}
+
+// parsePageTasks returns the page's incomplete tasks that do not link to a Jira issue yet
+func parsePageTasks(storage string) []pageTask {
+	tasks := []pageTask{}
+	for _, loc := range pageTaskPattern.FindAllStringIndex(storage, -1) {
+		element := storage[loc[0]:loc[1]]
+		if status := pageTaskStatus.FindStringSubmatch(element); status == nil || status[1] != "incomplete" {
+			continue
+		}
+		body := pageTaskBody.FindStringSubmatch(element)
+		if body == nil || pageTaskIssueLink.MatchString(body[1]) {
+			continue
+		}
+
+		text := html.UnescapeString(storageTagPattern.ReplaceAllString(body[1], " "))
+		summary := strings.TrimSpace(storageSpacePattern.ReplaceAllString(text, " "))
+		if summary == "" {
+			continue
+		}
+		if runes := []rune(summary); len(runes) > maxSummaryLength {
+			summary = string(runes[:maxSummaryLength-3]) + "..."
+		}
+
+		task := pageTask{start: loc[0], end: loc[1], Summary: summary}
+		if mention := pageTaskMention.FindStringSubmatch(body[1]); mention != nil {
+			task.AssigneeID = mention[1]
+		}
+		if due := pageTaskDueDate.FindStringSubmatch(body[1]); due != nil {
+			task.DueDate = due[1]
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// linkPageTasks adds a link to each task's new issue at the end of the task's body
+func linkPageTasks(storage string, tasks []pageTask) string {
+	var b strings.Builder
+	last := 0
+	for _, task := range tasks {
+		element := storage[task.start:task.end]
+		link := fmt.Sprintf(` <a href="%s">%s</a></ac:task-body>`, html.EscapeString(task.IssueURL), html.EscapeString(task.IssueKey))
+		b.WriteString(storage[last:task.start])
+		b.WriteString(strings.Replace(element, "</ac:task-body>", link, 1))
+		last = task.end
+	}
+	b.WriteString(storage[last:])
+	return b.String()
+}
+
+// pageLinkDescription is an issue description (in Atlassian Document Format, which Jira's
+// v3 API requires) linking back to the page the task came from
+func pageLinkDescription(pageTitle, pageURL string) map[string]interface{} {
+	text := map[string]interface{}{"type": "text", "text": pageTitle}
+	if pageURL != "" {
+		text["marks"] = []interface{}{
+			map[string]interface{}{"type": "link", "attrs": map[string]interface{}{"href": pageURL}},
+		}
+	}
+	return map[string]interface{}{
+		"type":    "doc",
+		"version": 1,
+		"content": []interface{}{
+			map[string]interface{}{
+				"type": "paragraph",
+				"content": []interface{}{
+					map[string]interface{}{"type": "text", "text": "Action item from Confluence page "},
+					text,
+				},
+			},
+		},
+	}
+}
+
+// handleCreateIssuesFromPage handles a create_issues_from_page call. The steps run as a
+// saga: the issues are created one by one, then the page is updated with their keys, and
+// a failure at any step deletes the issues already created.
+func (h *CrossProductHandler) handleCreateIssuesFromPage(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	pageID, _ := call.Arguments["page_id"].(string)
+	projectKey, _ := call.Arguments["project_key"].(string)
+	if workspaceID == "" || pageID == "" || projectKey == "" {
+		return fail("workspace_id, page_id and project_key are required")
+	}
+	issueType, _ := call.Arguments["issue_type"].(string)
+	if issueType == "" {
+		issueType = "Task"
+	}
+	dryRun, _ := call.Arguments["dry_run"].(bool)
+
+	confluenceRequest := func(action string, params map[string]interface{}) models.ConfluenceRequest {
+		return models.ConfluenceRequest{
+			Action:      action,
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+			OrgID:       call.OrgID,
+			Params:      params,
+			RequestID:   requestID,
+		}
+	}
+	jiraRequest := func(action string, params map[string]interface{}) models.JiraRequest {
+		return models.JiraRequest{
+			Action:      action,
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+			OrgID:       call.OrgID,
+			Params:      params,
+			RequestID:   requestID,
+		}
+	}
+
+	var page models.ConfluencePage
+	if err := h.callConfluence(confluenceRequest("get_page", map[string]interface{}{"page_id": pageID}), &page); err != nil {
+		return fail(fmt.Sprintf("failed to get page: %v", err))
+	}
+	tasks := parsePageTasks(page.Body.Storage.Value)
+
+	result := map[string]interface{}{
+		"page_id":    page.ID,
+		"page_title": page.Title,
+		"tasks":      tasks,
+	}
+	if dryRun || len(tasks) == 0 {
+		return jsonResult(result)
+	}
+
+	pageURL := ""
+	if page.Links.Base != "" && page.Links.WebUI != "" {
+		pageURL = strings.TrimRight(page.Links.Base, "/") + page.Links.WebUI
+	}
+
+	// rollback deletes the issues created so far and describes the outcome
+	var created []string
+	rollback := func(cause string) (mcp.ToolResult, error) {
+		var leftover []string
+		for _, key := range created {
+			resp, err := h.jira.callService(jiraRequest("delete_issue", map[string]interface{}{"issue_key": key}))
+			if err != nil || !resp.Success {
+				leftover = append(leftover, key)
+			}
+		}
+		if len(leftover) > 0 {
+			return fail(fmt.Sprintf("%s; could not delete the issues already created: %s", cause, strings.Join(leftover, ", ")))
+		}
+		return fail(cause + "; no issues were kept")
+	}
+
+	for i := range tasks {
+		fields := map[string]interface{}{
+			"description": pageLinkDescription(page.Title, pageURL),
+		}
+		if tasks[i].AssigneeID != "" {
+			fields["assignee"] = map[string]interface{}{"accountId": tasks[i].AssigneeID}
+		}
+		if tasks[i].DueDate != "" {
+			fields["duedate"] = tasks[i].DueDate
+		}
+
+		resp, err := h.jira.callService(jiraRequest("create_issue", map[string]interface{}{
+			"project_key":       projectKey,
+			"issue_type":        issueType,
+			"summary":           tasks[i].Summary,
+			"additional_fields": fields,
+		}))
+		if err == nil && !resp.Success {
+			err = serviceError(resp.Error)
+		}
+		var issue models.JiraIssue
+		if err == nil {
+			err = decodeServiceData(resp.Data, &issue)
+		}
+		if err != nil {
+			return rollback(fmt.Sprintf("failed to create an issue for %q: %v", tasks[i].Summary, err))
+		}
+		created = append(created, issue.Key)
+		tasks[i].IssueKey = issue.Key
+		tasks[i].IssueURL = issueBrowseURL(issue)
+	}
+
+	// Refuse to overwrite edits made to the page while the issues were being created
+	var current models.ConfluencePage
+	if err := h.callConfluence(confluenceRequest("get_page", map[string]interface{}{"page_id": pageID}), &current); err != nil {
+		return rollback(fmt.Sprintf("failed to get page: %v", err))
+	}
+	if current.Version.Number != page.Version.Number {
+		return rollback("the page was edited while the issues were being created; try again")
+	}
+
+	var updated models.ConfluencePage
+	err := h.callConfluence(confluenceRequest("update_page", map[string]interface{}{
+		"page_id": pageID,
+		"body":    linkPageTasks(page.Body.Storage.Value, tasks),
+	}), &updated)
+	if err != nil {
+		return rollback(fmt.Sprintf("failed to add the issue keys to the page: %v", err))
+	}
+
+	result["created"] = created
+	result["page_version"] = updated.Version.Number
+	return jsonResult(result)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/pii"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// ApplyPIIPolicy scans a tool result for likely personal data. In warn mode a warning block
+// is appended to the result; in strict mode the result is withheld and an error returned
+// in its place. Error results are not scanned. The findings are empty when nothing was found.
+func ApplyPIIPolicy(result mcp.ToolResult, mode pii.Mode) (mcp.ToolResult, pii.Findings) {
+	if mode == pii.ModeOff || mode == "" || result.IsError {
+		return result, nil
+	}
+	texts := make([]string, len(result.Content))
+	for i, block := range result.Content {
+		texts[i] = block.Text
+	}
+	findings := pii.Scan(texts...)
+	if len(findings) == 0 {
+		return result, nil
+	}
+
+	if mode == pii.ModeStrict {
+		return mcp.ToolResult{
+			Content: []mcp.ContentBlock{{
+				Type: "text",
+				Text: fmt.Sprintf("Result withheld: it appears to contain personal data (%s). Narrow the request to avoid it.", findings),
+			}},
+			IsError: true,
+		}, findings
+	}
+
+	content := append(append([]mcp.ContentBlock{}, result.Content...), mcp.ContentBlock{
+		Type: "text",
+		Text: fmt.Sprintf("⚠️ This result appears to contain personal data (%s). Do not repeat it unless the user needs it.", findings),
+	})
+	return mcp.ToolResult{Content: content, IsError: result.IsError}, findings
+}
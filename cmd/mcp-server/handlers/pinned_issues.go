@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// maxPinnedIssues bounds how many issues each user may pin; it matches what one
+// hydrate_issues call fetches
+const maxPinnedIssues = 50
+
+// errPinnedIssuesUnavailable is returned when pins are used without database storage
+var errPinnedIssuesUnavailable = errors.New("pinned issues require database storage (DATABASE_URL)")
+
+// PinnedIssueHandler serves each user's watch list of Jira issues. Listing the pins
+// fetches their current state, so recurring conversations need not name the issues again.
+type PinnedIssueHandler struct {
+	store storage.PinnedIssueStoreInterface
+	jira  *JiraHandler
+}
+
+// NewPinnedIssueHandler creates a new pinned issue handler. store may be nil when pins are
+// not supported (file-based storage).
+func NewPinnedIssueHandler(store storage.PinnedIssueStoreInterface, jira *JiraHandler) *PinnedIssueHandler {
+	return &PinnedIssueHandler{store: store, jira: jira}
+}
+
+// IsPinnedIssueTool reports whether a tool is served by the pinned issue handler
+func IsPinnedIssueTool(name string) bool {
+	return name == "pin_issue" || name == "unpin_issue" || name == "list_pinned_issues"
+}
+
+// ListTools returns the pinned issue tools
+func (h *PinnedIssueHandler) ListTools() []mcp.Tool {
+	issueProperties := func() map[string]interface{} {
+		return map[string]interface{}{
+			"workspace_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Workspace ID (from list_workspaces)",
+			},
+			"issue_key": map[string]interface{}{
+				"type":        "string",
+				"description": "Issue key (e.g., 'PROJ-123')",
+			},
+		}
+	}
+
+	return []mcp.Tool{
+		{
+			Name:        "pin_issue",
+			Description: fmt.Sprintf("Add a Jira issue to your pinned issues, a watch list kept across conversations (up to %d issues)", maxPinnedIssues),
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": issueProperties(),
+				"required":   []string{"workspace_id", "issue_key"},
+			},
+		},
+		{
+			Name:        "unpin_issue",
+			Description: "Remove a Jira issue from your pinned issues",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": issueProperties(),
+				"required":   []string{"workspace_id", "issue_key"},
+			},
+		},
+		{
+			Name:        "list_pinned_issues",
+			Description: "List your pinned Jira issues with their current summary, status, assignee and last update, fetched in one call",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Only list the issues pinned in this workspace",
+					},
+				},
+			},
+		},
+	}
+}
+
+// HandleTool handles a pinned issue tool call
+func (h *PinnedIssueHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+	if h.store == nil {
+		return fail(errPinnedIssuesUnavailable.Error())
+	}
+
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	issueKey, _ := call.Arguments["issue_key"].(string)
+	issueKey = strings.ToUpper(strings.TrimSpace(issueKey))
+
+	switch call.Name {
+	case "pin_issue":
+		return h.pin(call, userID, requestID, workspaceID, issueKey)
+	case "unpin_issue":
+		if workspaceID == "" || issueKey == "" {
+			return fail("workspace_id and issue_key are required")
+		}
+		err := h.store.UnpinIssue(userID, workspaceID, issueKey)
+		if errors.Is(err, storage.ErrNotFound) {
+			return fail(fmt.Sprintf("%s is not pinned in workspace %s", issueKey, workspaceID))
+		}
+		if err != nil {
+			return fail(fmt.Sprintf("failed to unpin issue: %v", err))
+		}
+		return jsonResult(map[string]interface{}{"workspace_id": workspaceID, "issue_key": issueKey, "unpinned": true})
+	case "list_pinned_issues":
+		pins, err := h.store.ListPinnedIssues(userID)
+		if err != nil {
+			return fail(fmt.Sprintf("failed to list pinned issues: %v", err))
+		}
+		if workspaceID != "" {
+			var kept []models.PinnedIssue
+			for _, pin := range pins {
+				if pin.WorkspaceID == workspaceID {
+					kept = append(kept, pin)
+				}
+			}
+			pins = kept
+		}
+		return jsonResult(map[string]interface{}{"pinned_issues": h.hydrate(pins, requestID)})
+	default:
+		return fail(fmt.Sprintf("unknown tool: %s", call.Name))
+	}
+}
+
+// pin handles a pin_issue call. The issue is fetched first, so that a wrong key, or an
+// issue the user cannot see, is reported straight away.
+func (h *PinnedIssueHandler) pin(call mcp.ToolCall, userID, requestID, workspaceID, issueKey string) (mcp.ToolResult, error) {
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+	if workspaceID == "" || issueKey == "" {
+		return fail("workspace_id and issue_key are required")
+	}
+
+	pins, err := h.store.ListPinnedIssues(userID)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to list pinned issues: %v", err))
+	}
+	pinned := false
+	for _, pin := range pins {
+		pinned = pinned || (pin.WorkspaceID == workspaceID && pin.IssueKey == issueKey)
+	}
+	if !pinned && len(pins) >= maxPinnedIssues {
+		return fail(fmt.Sprintf("you already have %d pinned issues; unpin one first", maxPinnedIssues))
+	}
+
+	resp, err := h.jira.callService(models.JiraRequest{
+		Action:      "get_issue",
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       call.OrgID,
+		Params:      map[string]interface{}{"issue_key": issueKey},
+		RequestID:   requestID,
+	})
+	if err == nil && !resp.Success {
+		err = serviceError(resp.Error)
+	}
+	var issue models.JiraIssue
+	if err == nil {
+		err = decodeServiceData(resp.Data, &issue)
+	}
+	if err != nil {
+		return fail(fmt.Sprintf("failed to get issue %s: %v", issueKey, err))
+	}
+
+	pin := models.PinnedIssue{
+		UserID:      userID,
+		OrgID:       call.OrgID,
+		WorkspaceID: workspaceID,
+		IssueKey:    issue.Key,
+	}
+	if err := h.store.PinIssue(&pin); err != nil {
+		return fail(fmt.Sprintf("failed to pin issue: %v", err))
+	}
+	return jsonResult(pin)
+}
+
+// pinnedIssue is a pin with the issue's current state
+type pinnedIssue struct {
+	WorkspaceID string    `json:"workspace_id"`
+	IssueKey    string    `json:"issue_key"`
+	PinnedAt    time.Time `json:"pinned_at"`
+	Summary     string    `json:"summary,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Assignee    string    `json:"assignee,omitempty"` // Empty when unassigned
+	Updated     string    `json:"updated,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	Error       string    `json:"error,omitempty"` // Why the issue could not be fetched
+}
+
+// hydrate fetches the pinned issues' current state with one hydrate_issues call per
+// workspace. An issue that cannot be fetched keeps its pin and reports the error.
+func (h *PinnedIssueHandler) hydrate(pins []models.PinnedIssue, requestID string) []pinnedIssue {
+	type group struct {
+		workspaceID, orgID, userID string
+		keys                       []interface{}
+	}
+	var groups []*group
+	byWorkspace := make(map[string]*group)
+	for _, pin := range pins {
+		g, ok := byWorkspace[pin.WorkspaceID]
+		if !ok {
+			g = &group{workspaceID: pin.WorkspaceID, orgID: pin.OrgID, userID: pin.UserID}
+			byWorkspace[pin.WorkspaceID] = g
+			groups = append(groups, g)
+		}
+		g.keys = append(g.keys, pin.IssueKey)
+	}
+
+	issues := make(map[string]models.JiraIssue)
+	failures := make(map[string]string)
+	for _, g := range groups {
+		found, failed, err := h.fetch(g.workspaceID, g.orgID, g.userID, requestID, g.keys)
+		for _, key := range g.keys {
+			id := g.workspaceID + "/" + key.(string)
+			if err != nil {
+				failures[id] = err.Error()
+			} else if issue, ok := found[key.(string)]; ok {
+				issues[id] = issue
+			} else {
+				failures[id] = failed[key.(string)]
+			}
+		}
+	}
+
+	result := make([]pinnedIssue, 0, len(pins))
+	for _, pin := range pins {
+		entry := pinnedIssue{
+			WorkspaceID: pin.WorkspaceID,
+			IssueKey:    pin.IssueKey,
+			PinnedAt:    pin.PinnedAt,
+		}
+		id := pin.WorkspaceID + "/" + pin.IssueKey
+		if issue, ok := issues[id]; ok {
+			entry.Summary, _ = issue.Fields["summary"].(string)
+			entry.Status = issueStatusName(issue)
+			if assignee, ok := issue.Fields["assignee"].(map[string]interface{}); ok {
+				entry.Assignee, _ = assignee["displayName"].(string)
+			}
+			entry.Updated, _ = issue.Fields["updated"].(string)
+			entry.URL = issueBrowseURL(issue)
+		} else {
+			entry.Error = failures[id]
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// fetch gets the issues of one workspace by key. It returns the issues found and why the
+// others could not be fetched, or an error when none could be.
+func (h *PinnedIssueHandler) fetch(workspaceID, orgID, userID, requestID string, keys []interface{}) (map[string]models.JiraIssue, map[string]string, error) {
+	resp, err := h.jira.callService(models.JiraRequest{
+		Action:      "hydrate_issues",
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       orgID,
+		Params:      map[string]interface{}{"issue_keys": keys},
+		RequestID:   requestID,
+	})
+	if err == nil && !resp.Success {
+		err = serviceError(resp.Error)
+	}
+	var hydrated struct {
+		Issues []models.JiraIssue `json:"issues"`
+		Errors []struct {
+			IssueKey string `json:"issue_key"`
+			Error    string `json:"error"`
+		} `json:"errors"`
+	}
+	if err == nil {
+		err = decodeServiceData(resp.Data, &hydrated)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	failed := make(map[string]string, len(hydrated.Errors))
+	for _, e := range hydrated.Errors {
+		failed[e.IssueKey] = e.Error
+	}
+	// The issues come back in the order of the keys, less those that failed. They are
+	// matched by position, since an issue moved to another project has a new key.
+	found := make(map[string]models.JiraIssue, len(hydrated.Issues))
+	next := 0
+	for _, key := range keys {
+		if _, ok := failed[key.(string)]; ok || next >= len(hydrated.Issues) {
+			continue
+		}
+		found[key.(string)] = hydrated.Issues[next]
+		next++
+	}
+	return found, failed, nil
+}
@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// playbookPlaceholderPattern matches {{name}}, {{previous}} and {{steps.<id>.output.<path>}}
+// placeholders, allowing spaces inside the braces
+var playbookPlaceholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z0-9_-]+)*)\s*\}\}`)
+
+// playbookReservedNames cannot be used as variable names
+var playbookReservedNames = map[string]bool{"previous": true, "steps": true}
+
+// playbookConditionOperators are the comparisons a step condition may use, longest first
+var playbookConditionOperators = []string{"==", "!=", ">=", "<=", ">", "<", "contains"}
+
+// playbookState is what placeholders can refer to while a playbook runs
+type playbookState struct {
+	variables map[string]interface{}
+	steps     map[string]models.PlaybookStepResult
+	outputs   map[string]string // Output text by step ID
+	previous  string            // Output text of the last step that ran
+}
+
+// run runs a playbook's steps in order as the caller, who must hold the scopes of every
+// step's tool. The first failing step ends the run unless it continues on error.
+func (h *PlaybookHandler) run(playbook models.Playbook, values map[string]interface{}, caller *auth.UserContext, requestID string) (*models.PlaybookRun, error) {
+	if h.runTool == nil {
+		return nil, errors.New("playbooks are not available on this server")
+	}
+	variables, err := resolvePlaybookVariables(playbook, values)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.PlaybookRun{
+		PlaybookID:   playbook.ID,
+		PlaybookName: playbook.Name,
+		Status:       models.PlaybookSucceeded,
+		Steps:        make([]models.PlaybookStepResult, 0, len(playbook.Steps)),
+		StartedAt:    time.Now().UTC(),
+	}
+	state := &playbookState{
+		variables: variables,
+		steps:     make(map[string]models.PlaybookStepResult),
+		outputs:   make(map[string]string),
+	}
+
+	for i, step := range playbook.Steps {
+		result, output := h.runStep(step, state, caller, requestID)
+		run.Steps = append(run.Steps, result)
+		if step.ID != "" {
+			state.steps[step.ID] = result
+			state.outputs[step.ID] = output
+		}
+		if result.Status == models.PlaybookSkipped {
+			continue
+		}
+		state.previous = output
+
+		if result.Status == models.PlaybookFailed && !step.ContinueOnError {
+			run.Status = models.PlaybookFailed
+			run.Error = fmt.Sprintf("step %d (%s) failed", i+1, step.Tool)
+			break
+		}
+	}
+
+	run.DurationMs = time.Since(run.StartedAt).Milliseconds()
+	slog.Info("playbook run finished", "playbook_id", playbook.ID, "user_id", caller.UserID,
+		"status", run.Status, "steps", len(run.Steps), "request_id", requestID)
+	return run, nil
+}
+
+// runStep evaluates a step's condition, fills in its arguments and calls its tool,
+// returning the result and the tool's output text
+func (h *PlaybookHandler) runStep(step models.PlaybookStep, state *playbookState, caller *auth.UserContext, requestID string) (models.PlaybookStepResult, string) {
+	result := models.PlaybookStepResult{ID: step.ID, Tool: step.Tool}
+	start := time.Now()
+	failed := func(err error) (models.PlaybookStepResult, string) {
+		result.Status = models.PlaybookFailed
+		result.Error = err.Error()
+		result.DurationMs = time.Since(start).Milliseconds()
+		return result, err.Error()
+	}
+
+	if step.If != "" {
+		ok, err := state.evalCondition(step.If)
+		if err != nil {
+			return failed(fmt.Errorf("invalid condition: %v", err))
+		}
+		if !ok {
+			result.Status = models.PlaybookSkipped
+			return result, ""
+		}
+	}
+	// Playbooks are checked when saved, but the caller's token may have fewer scopes than
+	// the one that saved it
+	if err := caller.CheckToolScope(step.Tool); err != nil {
+		return failed(err)
+	}
+
+	arguments, _ := fillPlaybookValue(step.Arguments, state.fill).(map[string]interface{})
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	toolResult, err := h.runTool(mcp.ToolCall{
+		Name:      step.Tool,
+		Arguments: arguments,
+		OrgID:     caller.OrgID,
+		RequestID: requestID,
+		Scopes:    caller.Scopes,
+	}, caller.UserID)
+	output := toolResultText(toolResult)
+	if output == "" && err != nil {
+		output = err.Error()
+	}
+
+	result.Status = models.PlaybookSucceeded
+	if err != nil || toolResult.IsError {
+		result.Status = models.PlaybookFailed
+		result.Error = output
+	} else if raw := json.RawMessage(output); json.Valid(raw) {
+		result.Output = raw
+	} else {
+		result.Output, _ = json.Marshal(output)
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, output
+}
+
+// resolvePlaybookVariables applies defaults to the given variable values. Every required
+// variable must be given, and variables the playbook does not declare are rejected to
+// catch typos.
+func resolvePlaybookVariables(playbook models.Playbook, values map[string]interface{}) (map[string]interface{}, error) {
+	declared := make(map[string]bool, len(playbook.Variables))
+	for _, v := range playbook.Variables {
+		declared[v.Name] = true
+	}
+	var unknown []string
+	for name := range values {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("playbook %q has no variables named %s", playbook.Name, strings.Join(unknown, ", "))
+	}
+
+	resolved := make(map[string]interface{}, len(playbook.Variables))
+	var missing []string
+	for _, v := range playbook.Variables {
+		value, ok := values[v.Name]
+		switch {
+		case ok && value != nil:
+			resolved[v.Name] = value
+		case v.Required:
+			missing = append(missing, v.Name)
+		default:
+			resolved[v.Name] = v.Default
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required variables: %s", strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// playbookStepReferences lists the placeholders in a step's arguments and condition
+func playbookStepReferences(step models.PlaybookStep) ([]string, error) {
+	texts := []string{step.If}
+	if step.Arguments != nil {
+		encoded, err := json.Marshal(step.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arguments: %v", err)
+		}
+		// Placeholders cannot contain characters JSON escapes, so the encoding finds them all
+		texts = append(texts, string(encoded))
+	}
+	var refs []string
+	for _, text := range texts {
+		for _, match := range playbookPlaceholderPattern.FindAllStringSubmatch(text, -1) {
+			refs = append(refs, match[1])
+		}
+	}
+	return refs, nil
+}
+
+// fill fills in a string's placeholders. A string that is a single placeholder takes the
+// referenced value as is, so numbers, lists and objects keep their type.
+func (s *playbookState) fill(text string) interface{} {
+	if match := playbookPlaceholderPattern.FindStringSubmatch(text); match != nil && match[0] == text {
+		return s.resolve(match[1])
+	}
+	return playbookPlaceholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		return playbookValueString(s.resolve(playbookPlaceholderPattern.FindStringSubmatch(placeholder)[1]))
+	})
+}
+
+// resolve returns the value a placeholder refers to, or nil if there is none, e.g. for a
+// skipped step or a field the output does not have
+func (s *playbookState) resolve(ref string) interface{} {
+	parts := strings.Split(ref, ".")
+	switch parts[0] {
+	case "previous":
+		return s.previous
+	case "steps":
+		if len(parts) < 3 {
+			return nil
+		}
+		result, ok := s.steps[parts[1]]
+		if !ok {
+			return nil
+		}
+		if parts[2] == "status" {
+			return result.Status
+		}
+		output := s.outputs[parts[1]]
+		if len(parts) == 3 {
+			return output
+		}
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+			return nil
+		}
+		return lookupPlaybookPath(decoded, parts[3:])
+	default:
+		return lookupPlaybookPath(s.variables[parts[0]], parts[1:])
+	}
+}
+
+// lookupPlaybookPath follows object keys and list indexes into a decoded JSON value
+func lookupPlaybookPath(v interface{}, path []string) interface{} {
+	for _, key := range path {
+		switch current := v.(type) {
+		case map[string]interface{}:
+			v = current[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(current) {
+				return nil
+			}
+			v = current[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// fillPlaybookValue copies an argument value, filling in the placeholders of its strings
+func fillPlaybookValue(v interface{}, fill func(string) interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = fillPlaybookValue(value, fill)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, value := range v {
+			copied[i] = fillPlaybookValue(value, fill)
+		}
+		return copied
+	case string:
+		return fill(v)
+	default:
+		return v
+	}
+}
+
+// playbookValueString formats a value for use inside a string
+func playbookValueString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return templateValueString(v)
+}
+
+// parsePlaybookCondition splits a condition into its operands and operator, e.g.
+// `{{steps.search.output.total}} == 0`. A condition without an operator tests whether its
+// operand is truthy.
+func parsePlaybookCondition(condition string) (string, string, string, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return "", "", "", errors.New("empty condition")
+	}
+
+	// Operators must stand apart, outside quotes and placeholders
+	var quote rune
+	depth := 0
+	for i, r := range condition {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			continue
+		case r == '"' || r == '\'':
+			quote = r
+			continue
+		case strings.HasPrefix(condition[i:], "{{"):
+			depth++
+			continue
+		case strings.HasPrefix(condition[i:], "}}") && depth > 0:
+			depth--
+			continue
+		case depth > 0 || i == 0 || condition[i-1] != ' ':
+			continue
+		}
+		for _, op := range playbookConditionOperators {
+			rest := condition[i+len(op):]
+			if strings.HasPrefix(condition[i:], op) && strings.HasPrefix(rest, " ") {
+				left, right := strings.TrimSpace(condition[:i]), strings.TrimSpace(rest)
+				if right == "" {
+					break
+				}
+				return left, op, right, nil
+			}
+		}
+	}
+	if quote != 0 {
+		return "", "", "", fmt.Errorf("unterminated quote in condition %q", condition)
+	}
+	return condition, "", "", nil
+}
+
+// evalCondition evaluates a step condition. == and != compare numbers as numbers and
+// anything else as text; <, <=, > and >= need numbers; contains tests for a substring, or
+// an element of a list.
+func (s *playbookState) evalCondition(condition string) (bool, error) {
+	leftText, op, rightText, err := parsePlaybookCondition(condition)
+	if err != nil {
+		return false, err
+	}
+	left := s.operand(leftText)
+	if op == "" {
+		return playbookTruthy(left), nil
+	}
+	right := s.operand(rightText)
+
+	leftNum, leftIsNum := playbookNumber(left)
+	rightNum, rightIsNum := playbookNumber(right)
+	switch op {
+	case "==", "!=":
+		equal := playbookValueString(left) == playbookValueString(right)
+		if leftIsNum && rightIsNum {
+			equal = leftNum == rightNum
+		}
+		return equal == (op == "=="), nil
+	case "contains":
+		if list, ok := left.([]interface{}); ok {
+			for _, item := range list {
+				if playbookValueString(item) == playbookValueString(right) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return strings.Contains(playbookValueString(left), playbookValueString(right)), nil
+	default:
+		if !leftIsNum || !rightIsNum {
+			return false, fmt.Errorf("%s compares numbers, got %q and %q", op, playbookValueString(left), playbookValueString(right))
+		}
+		switch op {
+		case ">":
+			return leftNum > rightNum, nil
+		case "<":
+			return leftNum < rightNum, nil
+		case ">=":
+			return leftNum >= rightNum, nil
+		default:
+			return leftNum <= rightNum, nil
+		}
+	}
+}
+
+// operand evaluates one side of a condition: a quoted string, a placeholder or a bare word
+func (s *playbookState) operand(text string) interface{} {
+	if len(text) >= 2 && (text[0] == '"' || text[0] == '\'') && text[len(text)-1] == text[0] {
+		return playbookValueString(s.fill(text[1 : len(text)-1]))
+	}
+	return s.fill(text)
+}
+
+// playbookNumber reads a value as a number, accepting numeric strings
+func playbookNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// playbookTruthy reports whether a value counts as true: not empty, zero, "false" or null
+func playbookTruthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		v = strings.TrimSpace(v)
+		return v != "" && v != "0" && !strings.EqualFold(v, "false") && v != "null"
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
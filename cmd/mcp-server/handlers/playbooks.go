@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const (
+	maxPlaybookSteps     = 20
+	maxPlaybookVariables = 50
+)
+
+// playbookStepIDPattern is what a step may be called; IDs appear in {{steps.<id>.output}}
+var playbookStepIDPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// errPlaybooksUnavailable is returned when playbooks are used without database storage
+var errPlaybooksUnavailable = errors.New("playbooks require database storage (DATABASE_URL)")
+
+// PlaybookHandler handles the /api/playbooks endpoints and the run_playbook tool, which
+// runs a saved list of tool calls (see playbook_runner.go)
+type PlaybookHandler struct {
+	store   storage.PlaybookStoreInterface
+	tools   map[string]bool
+	runTool func(mcp.ToolCall, string) (mcp.ToolResult, error)
+}
+
+// NewPlaybookHandler creates a new playbook handler. Playbooks cannot run until WithRunner
+// is called. store may be nil when playbooks are not supported (file-based storage).
+func NewPlaybookHandler(store storage.PlaybookStoreInterface) *PlaybookHandler {
+	return &PlaybookHandler{store: store, tools: map[string]bool{}}
+}
+
+// WithRunner lets steps call any of tools, running them through runTool as the caller
+func (h *PlaybookHandler) WithRunner(tools []mcp.Tool, runTool func(mcp.ToolCall, string) (mcp.ToolResult, error)) *PlaybookHandler {
+	h.tools = make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		// Playbooks do not run other playbooks, so a run always ends
+		if !IsPlaybookTool(tool.Name) {
+			h.tools[tool.Name] = true
+		}
+	}
+	h.runTool = runTool
+	return h
+}
+
+// IsPlaybookTool reports whether a tool is served by the playbook handler
+func IsPlaybookTool(name string) bool {
+	return name == "run_playbook"
+}
+
+// ListTools returns the playbook tools
+func (h *PlaybookHandler) ListTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "run_playbook",
+			Description: "Run one of your saved playbooks: an ordered list of tool calls with variables and conditional steps, e.g. \"triage a bug\". Returns each step's status and output.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"playbook": map[string]interface{}{
+						"type":        "string",
+						"description": "ID or name of the playbook",
+					},
+					"variables": map[string]interface{}{
+						"type":        "object",
+						"description": "Values of the playbook's variables, keyed by name",
+					},
+				},
+				"required": []string{"playbook"},
+			},
+		},
+	}
+}
+
+// HandleTool handles a run_playbook call. Each step is checked against the caller's scopes.
+func (h *PlaybookHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+	if h.store == nil {
+		return fail(errPlaybooksUnavailable.Error())
+	}
+	if call.Name != "run_playbook" {
+		return fail(fmt.Sprintf("unknown tool: %s", call.Name))
+	}
+
+	ref, _ := call.Arguments["playbook"].(string)
+	if ref == "" {
+		return fail("playbook is required")
+	}
+	playbooks, err := h.store.ListPlaybooks(userID)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to list playbooks: %v", err))
+	}
+	var playbook *models.Playbook
+	names := make([]string, 0, len(playbooks))
+	for i := range playbooks {
+		if playbooks[i].ID == ref || strings.EqualFold(playbooks[i].Name, ref) {
+			playbook = &playbooks[i]
+			break
+		}
+		names = append(names, playbooks[i].Name)
+	}
+	if playbook == nil {
+		if len(playbooks) == 0 {
+			return fail(fmt.Sprintf("playbook not found: %s (you have no playbooks)", ref))
+		}
+		return fail(fmt.Sprintf("playbook not found: %s (available: %s)", ref, strings.Join(names, ", ")))
+	}
+
+	values, _ := call.Arguments["variables"].(map[string]interface{})
+	caller := &auth.UserContext{UserID: userID, OrgID: call.OrgID, Scopes: call.Scopes}
+	run, err := h.run(*playbook, values, caller, requestID)
+	if err != nil {
+		return fail(err.Error())
+	}
+	return jsonResult(run)
+}
+
+// PlaybookRequest represents the request to create or replace a playbook
+type PlaybookRequest struct {
+	Name        string                    `json:"name"`
+	Description string                    `json:"description,omitempty"`
+	Variables   []models.PlaybookVariable `json:"variables,omitempty"`
+	Steps       []models.PlaybookStep     `json:"steps"`
+}
+
+// PlaybookRunRequest represents the request to run a playbook
+type PlaybookRunRequest struct {
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// HandlePlaybooks handles /api/playbooks, /api/playbooks/{id} and /api/playbooks/{id}/run
+func (h *PlaybookHandler) HandlePlaybooks(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		http.Error(w, "Playbooks require database storage (DATABASE_URL)", http.StatusNotImplemented)
+		return
+	}
+
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	playbookID, sub := "", ""
+	if len(r.URL.Path) > len("/api/playbooks/") {
+		playbookID, sub, _ = strings.Cut(r.URL.Path[len("/api/playbooks/"):], "/")
+	}
+
+	switch {
+	case playbookID == "" && r.Method == http.MethodGet:
+		h.handleList(w, userCtx)
+	case playbookID == "" && r.Method == http.MethodPost:
+		h.handleCreate(w, r, userCtx)
+	case sub == "" && r.Method == http.MethodGet:
+		h.handleGet(w, userCtx, playbookID)
+	case sub == "" && r.Method == http.MethodPut:
+		h.handleUpdate(w, r, userCtx, playbookID)
+	case sub == "" && r.Method == http.MethodDelete:
+		h.handleDelete(w, userCtx, playbookID)
+	case sub == "run" && r.Method == http.MethodPost:
+		h.handleRun(w, r, userCtx, playbookID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleList handles GET /api/playbooks
+func (h *PlaybookHandler) handleList(w http.ResponseWriter, userCtx *auth.UserContext) {
+	playbooks, err := h.store.ListPlaybooks(userCtx.UserID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list playbooks: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(playbooks)
+}
+
+// handleCreate handles POST /api/playbooks
+func (h *PlaybookHandler) handleCreate(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext) {
+	var req PlaybookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	playbook := models.Playbook{
+		ID:     uuid.New().String(),
+		UserID: userCtx.UserID,
+	}
+	if err := h.applyPlaybookRequest(&playbook, req, userCtx); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.nameAvailable(w, userCtx.UserID, playbook.Name, "") {
+		return
+	}
+
+	if err := h.store.CreatePlaybook(&playbook); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save playbook: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(playbook)
+}
+
+// handleGet handles GET /api/playbooks/{id}
+func (h *PlaybookHandler) handleGet(w http.ResponseWriter, userCtx *auth.UserContext, playbookID string) {
+	playbook, ok := h.getPlaybook(w, userCtx, playbookID)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(playbook)
+}
+
+// handleUpdate handles PUT /api/playbooks/{id}, which replaces the playbook
+func (h *PlaybookHandler) handleUpdate(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext, playbookID string) {
+	var req PlaybookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	playbook, ok := h.getPlaybook(w, userCtx, playbookID)
+	if !ok {
+		return
+	}
+	if err := h.applyPlaybookRequest(playbook, req, userCtx); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.nameAvailable(w, userCtx.UserID, playbook.Name, playbook.ID) {
+		return
+	}
+
+	if err := h.store.UpdatePlaybook(playbook); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Playbook not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to update playbook: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(playbook)
+}
+
+// handleDelete handles DELETE /api/playbooks/{id}
+func (h *PlaybookHandler) handleDelete(w http.ResponseWriter, userCtx *auth.UserContext, playbookID string) {
+	if err := h.store.DeletePlaybook(userCtx.UserID, playbookID); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Playbook not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete playbook: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRun handles POST /api/playbooks/{id}/run. The run completes before the response,
+// which carries every step's result.
+func (h *PlaybookHandler) handleRun(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext, playbookID string) {
+	var req PlaybookRunRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	playbook, ok := h.getPlaybook(w, userCtx, playbookID)
+	if !ok {
+		return
+	}
+
+	run, err := h.run(*playbook, req.Variables, userCtx, "playbook_"+uuid.New().String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
+
+// getPlaybook loads one of the user's playbooks, writing the error response if it cannot
+func (h *PlaybookHandler) getPlaybook(w http.ResponseWriter, userCtx *auth.UserContext, playbookID string) (*models.Playbook, bool) {
+	playbook, err := h.store.GetPlaybook(userCtx.UserID, playbookID)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Playbook not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, fmt.Sprintf("Failed to get playbook: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return playbook, true
+}
+
+// nameAvailable reports whether the user has no other playbook with the name, writing a
+// 409 response if they do
+func (h *PlaybookHandler) nameAvailable(w http.ResponseWriter, userID, name, exceptID string) bool {
+	existing, err := h.store.ListPlaybooks(userID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list playbooks: %v", err), http.StatusInternalServerError)
+		return false
+	}
+	for _, p := range existing {
+		if p.ID != exceptID && strings.EqualFold(p.Name, name) {
+			http.Error(w, fmt.Sprintf("A playbook named %q already exists", p.Name), http.StatusConflict)
+			return false
+		}
+	}
+	return true
+}
+
+// applyPlaybookRequest validates a create or replace request and copies it onto the
+// playbook. Placeholders without a declared variable are added as required variables.
+func (h *PlaybookHandler) applyPlaybookRequest(playbook *models.Playbook, req PlaybookRequest, userCtx *auth.UserContext) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("missing required field: name")
+	}
+	if len(req.Steps) == 0 {
+		return errors.New("missing required field: steps")
+	}
+	if len(req.Steps) > maxPlaybookSteps {
+		return fmt.Errorf("a playbook can have at most %d steps", maxPlaybookSteps)
+	}
+
+	declared := make(map[string]bool, len(req.Variables))
+	for _, v := range req.Variables {
+		if !templateVariableNamePattern.MatchString(v.Name) {
+			return fmt.Errorf("invalid variable name %q (use letters, digits and underscores)", v.Name)
+		}
+		if playbookReservedNames[v.Name] {
+			return fmt.Errorf("variable name %q is reserved", v.Name)
+		}
+		if declared[v.Name] {
+			return fmt.Errorf("variable %q is declared twice", v.Name)
+		}
+		declared[v.Name] = true
+	}
+	variables := append([]models.PlaybookVariable{}, req.Variables...)
+
+	// A step may only refer to the steps before it, which have run by then
+	earlier := make(map[string]bool, len(req.Steps))
+	for i, step := range req.Steps {
+		if IsPlaybookTool(step.Tool) {
+			return fmt.Errorf("step %d: playbooks cannot run other playbooks", i+1)
+		}
+		if !h.tools[step.Tool] {
+			return fmt.Errorf("step %d: unknown tool %q", i+1, step.Tool)
+		}
+		if err := userCtx.CheckToolScope(step.Tool); err != nil {
+			return fmt.Errorf("step %d: %v", i+1, err)
+		}
+		if step.If != "" {
+			if _, _, _, err := parsePlaybookCondition(step.If); err != nil {
+				return fmt.Errorf("step %d: %v", i+1, err)
+			}
+		}
+
+		refs, err := playbookStepReferences(step)
+		if err != nil {
+			return fmt.Errorf("step %d: %v", i+1, err)
+		}
+		for _, ref := range refs {
+			root, rest, _ := strings.Cut(ref, ".")
+			switch root {
+			case "previous":
+				if rest != "" {
+					return fmt.Errorf("step %d: {{previous}} has no fields; use {{steps.<id>.output...}}", i+1)
+				}
+			case "steps":
+				id, field, _ := strings.Cut(rest, ".")
+				if !earlier[id] {
+					return fmt.Errorf("step %d: {{%s}} does not refer to an earlier step", i+1, ref)
+				}
+				if field != "status" && field != "output" && !strings.HasPrefix(field, "output.") {
+					return fmt.Errorf("step %d: {{%s}} must end in .output, .output.<field> or .status", i+1, ref)
+				}
+			default:
+				if !declared[root] {
+					declared[root] = true
+					variables = append(variables, models.PlaybookVariable{Name: root, Required: true})
+				}
+			}
+		}
+
+		if step.ID != "" {
+			if !playbookStepIDPattern.MatchString(step.ID) {
+				return fmt.Errorf("step %d: invalid id %q (use letters, digits, underscores and dashes)", i+1, step.ID)
+			}
+			if earlier[step.ID] {
+				return fmt.Errorf("step %d: id %q is used by an earlier step", i+1, step.ID)
+			}
+			earlier[step.ID] = true
+		}
+	}
+	if len(variables) > maxPlaybookVariables {
+		return fmt.Errorf("a playbook can have at most %d variables", maxPlaybookVariables)
+	}
+
+	playbook.Name = req.Name
+	playbook.Description = req.Description
+	playbook.Variables = variables
+	playbook.Steps = req.Steps
+	return nil
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// PluginHandler serves the tools of third-party tool providers, e.g. a Tempo Timesheets
+// module, registered at startup
+type PluginHandler struct {
+	providers []mcp.ToolProvider
+	tools     []mcp.Tool
+	routes    map[string]mcp.ToolProvider // Tool name -> provider
+}
+
+// NewPluginHandler creates a plugin handler with no providers
+func NewPluginHandler() *PluginHandler {
+	return &PluginHandler{routes: make(map[string]mcp.ToolProvider)}
+}
+
+// Add registers a provider's tools. Tools whose name is built in or already provided are
+// skipped, so a plugin cannot take over another tool.
+func (h *PluginHandler) Add(provider mcp.ToolProvider, builtIn []mcp.Tool) {
+	taken := make(map[string]bool, len(builtIn))
+	for _, tool := range builtIn {
+		taken[tool.Name] = true
+	}
+	h.providers = append(h.providers, provider)
+	for _, tool := range provider.ListTools() {
+		if taken[tool.Name] || h.routes[tool.Name] != nil {
+			slog.Warn("plugin tool ignored: another tool has its name", "tool", tool.Name)
+			continue
+		}
+		h.tools = append(h.tools, tool)
+		h.routes[tool.Name] = provider
+	}
+}
+
+// IsPluginTool reports whether a tool is served by a plugin
+func (h *PluginHandler) IsPluginTool(name string) bool {
+	return h.routes[name] != nil
+}
+
+// ListTools returns the plugins' tools
+func (h *PluginHandler) ListTools() []mcp.Tool {
+	return h.tools
+}
+
+// HandleTool runs a call in the plugin that provides the tool
+func (h *PluginHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	provider := h.routes[call.Name]
+	if provider == nil {
+		return mcp.ToolResult{
+			Content: []mcp.ContentBlock{{Type: "text", Text: fmt.Sprintf("Unknown tool: %s", call.Name)}},
+			IsError: true,
+		}, fmt.Errorf("unknown tool: %s", call.Name)
+	}
+	return provider.HandleTool(call, userID)
+}
+
+// Close stops the plugins that run as subprocesses
+func (h *PluginHandler) Close() {
+	for _, provider := range h.providers {
+		if plugin, ok := provider.(*mcp.Plugin); ok {
+			plugin.Close()
+		}
+	}
+}
+
+// StartPluginsFromEnv starts the plugins in MCP_PLUGINS, a comma-separated list of commands
+// (e.g. "/opt/plugins/tempo --site acme,/opt/plugins/statuspage"). timeout gives how long
+// each tool's calls may take.
+func StartPluginsFromEnv(timeout func(tool string) time.Duration) ([]*mcp.Plugin, error) {
+	var plugins []*mcp.Plugin
+	for _, command := range strings.Split(os.Getenv("MCP_PLUGINS"), ",") {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			continue
+		}
+		plugin, err := mcp.StartPlugin(args, timeout)
+		if err != nil {
+			for _, started := range plugins {
+				started.Close()
+			}
+			return nil, err
+		}
+		slog.Info("plugin started", "plugin", plugin.Name(), "tools", len(plugin.ListTools()))
+		plugins = append(plugins, plugin)
+	}
+	return plugins, nil
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// workspaceRateStatusTool is workspace_rate_status, which reports how much of a
+// workspace's Atlassian rate limit budget is left
+func workspaceRateStatusTool() mcp.Tool {
+	return mcp.Tool{
+		Name: "workspace_rate_status",
+		Description: "Check how much of a workspace's Atlassian rate limit budget is left for Jira and Confluence, as Atlassian last reported it, and whether calls are being slowed down or held to stay within it. " +
+			"Use it before a large batch of calls, or when calls fail as rate limited.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"workspace_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Workspace ID",
+				},
+			},
+			"required": []string{"workspace_id"},
+		},
+	}
+}
+
+// handleWorkspaceRateStatus handles a workspace_rate_status call. The Jira and Confluence
+// services each track their own budget, so both are asked; a service that cannot answer
+// is reported with its error.
+func (h *CrossProductHandler) handleWorkspaceRateStatus(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	if workspaceID == "" {
+		message := "workspace_id is required"
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	status := func(budget interface{}, err error) interface{} {
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}
+		}
+		return budget
+	}
+
+	var jira atlassian.RateBudget
+	resp, err := h.jira.callService(models.JiraRequest{
+		Action:      atlassian.RateStatusAction,
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       call.OrgID,
+		RequestID:   requestID,
+	})
+	if err == nil && !resp.Success {
+		err = serviceError(resp.Error)
+	}
+	if err == nil {
+		err = decodeServiceData(resp.Data, &jira)
+	}
+	jiraStatus := status(jira, err)
+
+	var confluence atlassian.RateBudget
+	err = h.callConfluence(models.ConfluenceRequest{
+		Action:      atlassian.RateStatusAction,
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       call.OrgID,
+		RequestID:   requestID,
+	}, &confluence)
+
+	return jsonResult(map[string]interface{}{
+		"workspace_id": workspaceID,
+		"jira":         jiraStatus,
+		"confluence":   status(confluence, err),
+	})
+}
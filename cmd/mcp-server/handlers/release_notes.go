@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const (
+	defaultReleaseNotesLimit = 100
+	maxReleaseNotesLimit     = 100
+)
+
+// releaseNotesTypeOrder lists the issue types that lead release notes, new work before
+// fixes; other types follow alphabetically
+var releaseNotesTypeOrder = []string{"Epic", "New Feature", "Feature", "Story", "Improvement", "Bug", "Task", "Sub-task"}
+
+// releaseNotesTool is generate_release_notes, which turns the issues of a version or JQL
+// query into a Confluence page
+func releaseNotesTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "generate_release_notes",
+		Description: "Generate release notes from Jira issues, grouped by issue type, and publish them as a Confluence page. Give a fix version or a JQL query. Re-running with the same title updates the existing page. Without target_space the notes are only returned.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"workspace_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Workspace ID",
+				},
+				"version": map[string]interface{}{
+					"type":        "string",
+					"description": "Jira fix version to report on (e.g., '2.4.0')",
+				},
+				"project_key": map[string]interface{}{
+					"type":        "string",
+					"description": "Project of the version; recommended, since version names repeat across projects",
+				},
+				"jql": map[string]interface{}{
+					"type":        "string",
+					"description": "JQL selecting the issues, instead of version",
+				},
+				"target_space": map[string]interface{}{
+					"type":        "string",
+					"description": "Key of the Confluence space to publish the page in",
+				},
+				"parent_page": map[string]interface{}{
+					"type":        "string",
+					"description": "ID of the page to create the release notes under",
+				},
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "Page title (defaults to 'Release notes <version>' or 'Release notes <date>')",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"storage", "markdown"},
+					"description": "Format of the returned notes. The page itself is always written in Confluence storage format.",
+					"default":     "storage",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of issues (up to 100)",
+					"default":     defaultReleaseNotesLimit,
+				},
+			},
+			"required": []string{"workspace_id"},
+		},
+	}
+}
+
+// releaseNotesGroup is the issues of one type
+type releaseNotesGroup struct {
+	Type   string
+	Issues []models.JiraIssue
+}
+
+// handleReleaseNotes handles a generate_release_notes call: it fetches the issues from
+// Jira, renders them and creates or updates the page in Confluence
+func (h *CrossProductHandler) handleReleaseNotes(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	if workspaceID == "" {
+		return fail("workspace_id is required")
+	}
+	version, _ := call.Arguments["version"].(string)
+	jql, _ := call.Arguments["jql"].(string)
+	if (version == "") == (jql == "") {
+		return fail("give either version or jql")
+	}
+	format, _ := call.Arguments["format"].(string)
+	if format == "" {
+		format = "storage"
+	}
+	if format != "storage" && format != "markdown" {
+		return fail("format must be storage or markdown")
+	}
+	targetSpace, _ := call.Arguments["target_space"].(string)
+	parentPage, _ := call.Arguments["parent_page"].(string)
+
+	if version != "" {
+		jql = "fixVersion = " + quoteSearchText(version)
+		if projectKey, _ := call.Arguments["project_key"].(string); projectKey != "" {
+			jql = "project = " + quoteSearchText(projectKey) + " AND " + jql
+		}
+	}
+	limit := defaultReleaseNotesLimit
+	if l, ok := call.Arguments["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	if limit > maxReleaseNotesLimit {
+		limit = maxReleaseNotesLimit
+	}
+
+	title, _ := call.Arguments["title"].(string)
+	if title == "" {
+		if version != "" {
+			title = "Release notes " + version
+		} else {
+			title = "Release notes " + time.Now().UTC().Format("2006-01-02")
+		}
+	}
+
+	issues, err := h.listIssues(models.JiraRequest{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       call.OrgID,
+		Params: map[string]interface{}{
+			"jql":    jql + " ORDER BY key ASC",
+			"limit":  float64(limit),
+			"fields": []interface{}{"summary", "status", "issuetype", "assignee"},
+		},
+		RequestID: requestID,
+	})
+	if err != nil {
+		return fail(fmt.Sprintf("failed to fetch issues: %v", err))
+	}
+	groups := groupIssuesByType(issues)
+
+	result := map[string]interface{}{
+		"title":       title,
+		"jql":         jql,
+		"issue_count": len(issues),
+		"format":      format,
+	}
+	if format == "markdown" {
+		result["content"] = renderReleaseNotesMarkdown(title, jql, groups)
+	} else {
+		result["content"] = renderReleaseNotesStorage(jql, groups)
+	}
+
+	if targetSpace != "" {
+		page, action, err := h.publishPage(workspaceID, userID, call.OrgID, requestID,
+			targetSpace, parentPage, title, renderReleaseNotesStorage(jql, groups))
+		if err != nil {
+			return fail(fmt.Sprintf("failed to publish release notes: %v", err))
+		}
+		result["page"] = page
+		result["action"] = action
+	}
+
+	return jsonResult(result)
+}
+
+// publishPage updates the page with the title in the space, or creates it under
+// parentPage. It returns the page and whether it was "created" or "updated".
+func (h *CrossProductHandler) publishPage(workspaceID, userID, orgID, requestID, space, parentPage, title, body string) (map[string]interface{}, string, error) {
+	confluenceRequest := func(action string, params map[string]interface{}) models.ConfluenceRequest {
+		return models.ConfluenceRequest{
+			Action:      action,
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+			OrgID:       orgID,
+			Params:      params,
+			RequestID:   requestID,
+		}
+	}
+
+	var existing models.SearchResults
+	cql := fmt.Sprintf("space = %s AND type = page AND title = %s", quoteSearchText(space), quoteSearchText(title))
+	if err := h.callConfluence(confluenceRequest("search", map[string]interface{}{
+		"query": cql,
+		"limit": float64(1),
+	}), &existing); err != nil {
+		return nil, "", err
+	}
+
+	var page models.ConfluencePage
+	action := "created"
+	if len(existing.Results) > 0 {
+		action = "updated"
+		err := h.callConfluence(confluenceRequest("update_page", map[string]interface{}{
+			"page_id": existing.Results[0].ID,
+			"body":    body,
+		}), &page)
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		params := map[string]interface{}{
+			"space_key": space,
+			"title":     title,
+			"body":      body,
+		}
+		if parentPage != "" {
+			params["parent_id"] = parentPage
+		}
+		if err := h.callConfluence(confluenceRequest("create_page", params), &page); err != nil {
+			return nil, "", err
+		}
+	}
+
+	summary := map[string]interface{}{
+		"id":      page.ID,
+		"title":   page.Title,
+		"space":   space,
+		"version": page.Version.Number,
+	}
+	if existing.Links.Base != "" && page.Links.WebUI != "" {
+		summary["url"] = strings.TrimRight(existing.Links.Base, "/") + page.Links.WebUI
+	}
+	return summary, action, nil
+}
+
+// groupIssuesByType groups issues by type name, in releaseNotesTypeOrder and then
+// alphabetically, keeping the issues' order within each group
+func groupIssuesByType(issues []models.JiraIssue) []releaseNotesGroup {
+	byType := map[string][]models.JiraIssue{}
+	for _, issue := range issues {
+		issueType := "Other"
+		if t, ok := issue.Fields["issuetype"].(map[string]interface{}); ok {
+			if name, ok := t["name"].(string); ok && name != "" {
+				issueType = name
+			}
+		}
+		byType[issueType] = append(byType[issueType], issue)
+	}
+
+	rank := func(issueType string) int {
+		for i, t := range releaseNotesTypeOrder {
+			if strings.EqualFold(t, issueType) {
+				return i
+			}
+		}
+		return len(releaseNotesTypeOrder)
+	}
+	groups := make([]releaseNotesGroup, 0, len(byType))
+	for issueType, typeIssues := range byType {
+		groups = append(groups, releaseNotesGroup{Type: issueType, Issues: typeIssues})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		ri, rj := rank(groups[i].Type), rank(groups[j].Type)
+		if ri != rj {
+			return ri < rj
+		}
+		return groups[i].Type < groups[j].Type
+	})
+	return groups
+}
+
+// releaseNotesLine returns an issue's summary, status and assignee display name
+func releaseNotesLine(issue models.JiraIssue) (summary, status, assignee string) {
+	summary, _ = issue.Fields["summary"].(string)
+	if s, ok := issue.Fields["status"].(map[string]interface{}); ok {
+		status, _ = s["name"].(string)
+	}
+	if a, ok := issue.Fields["assignee"].(map[string]interface{}); ok {
+		assignee, _ = a["displayName"].(string)
+	}
+	return summary, status, assignee
+}
+
+// renderReleaseNotesStorage renders release notes in Confluence storage format
+func renderReleaseNotesStorage(jql string, groups []releaseNotesGroup) string {
+	var b strings.Builder
+	total := 0
+	for _, g := range groups {
+		total += len(g.Issues)
+	}
+	fmt.Fprintf(&b, "<p>%d issues from Jira (<code>%s</code>), generated %s.</p>",
+		total, html.EscapeString(jql), time.Now().UTC().Format("2006-01-02"))
+	if total == 0 {
+		b.WriteString("<p>No issues matched.</p>")
+	}
+
+	for _, g := range groups {
+		fmt.Fprintf(&b, "<h2>%s (%d)</h2><ul>", html.EscapeString(g.Type), len(g.Issues))
+		for _, issue := range g.Issues {
+			summary, status, assignee := releaseNotesLine(issue)
+			key := html.EscapeString(issue.Key)
+			if link := issueBrowseURL(issue); link != "" {
+				key = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link), key)
+			}
+			fmt.Fprintf(&b, "<li><strong>%s</strong> %s", key, html.EscapeString(summary))
+			if status != "" {
+				fmt.Fprintf(&b, " <em>(%s)</em>", html.EscapeString(status))
+			}
+			if assignee != "" {
+				fmt.Fprintf(&b, " — %s", html.EscapeString(assignee))
+			}
+			b.WriteString("</li>")
+		}
+		b.WriteString("</ul>")
+	}
+	return b.String()
+}
+
+// renderReleaseNotesMarkdown renders release notes as Markdown
+func renderReleaseNotesMarkdown(title, jql string, groups []releaseNotesGroup) string {
+	var b strings.Builder
+	total := 0
+	for _, g := range groups {
+		total += len(g.Issues)
+	}
+	fmt.Fprintf(&b, "# %s\n\n%d issues from Jira (`%s`), generated %s.\n",
+		title, total, jql, time.Now().UTC().Format("2006-01-02"))
+	if total == 0 {
+		b.WriteString("\nNo issues matched.\n")
+	}
+
+	for _, g := range groups {
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", g.Type, len(g.Issues))
+		for _, issue := range g.Issues {
+			summary, status, assignee := releaseNotesLine(issue)
+			key := issue.Key
+			if link := issueBrowseURL(issue); link != "" {
+				key = fmt.Sprintf("[%s](%s)", issue.Key, link)
+			}
+			fmt.Fprintf(&b, "- **%s** %s", key, summary)
+			if status != "" {
+				fmt.Fprintf(&b, " _(%s)_", status)
+			}
+			if assignee != "" {
+				fmt.Fprintf(&b, " — %s", assignee)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
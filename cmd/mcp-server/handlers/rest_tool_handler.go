@@ -4,33 +4,111 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/pii"
 	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
 )
 
 // RestToolHandler generic handler for exposing MCP tools via REST
 type RestToolHandler struct {
-	confluenceHandler *ConfluenceHandler
-	jiraHandler       *JiraHandler
-	managementHandler *ManagementHandler
+	confluenceHandler   *ConfluenceHandler
+	jiraHandler         *JiraHandler
+	managementHandler   *ManagementHandler
+	crossProductHandler *CrossProductHandler
+	notificationHandler *NotificationHandler
+	exportHandler       *ExportHandler
+	templateHandler     *IssueTemplateHandler
+	playbookHandler     *PlaybookHandler
+	subscriptionHandler *IssueSubscriptionHandler
+	pinnedIssueHandler  *PinnedIssueHandler
+	undoHandler         *UndoHandler
+	pluginHandler       *PluginHandler
+	settings            *config.SettingsWatcher // Tool allowlist and response size limit (nil = none)
 }
 
 // NewRestToolHandler creates a new REST tool handler
 func NewRestToolHandler(confluenceHandler *ConfluenceHandler, jiraHandler *JiraHandler, managementHandler *ManagementHandler) *RestToolHandler {
 	return &RestToolHandler{
-		confluenceHandler: confluenceHandler,
-		jiraHandler:       jiraHandler,
-		managementHandler: managementHandler,
+		confluenceHandler:   confluenceHandler,
+		jiraHandler:         jiraHandler,
+		managementHandler:   managementHandler,
+		crossProductHandler: NewCrossProductHandler(jiraHandler, confluenceHandler),
+		notificationHandler: NewNotificationHandler(nil),
+		exportHandler:       NewExportHandler(jiraHandler, confluenceHandler, nil, 0),
+		templateHandler:     NewIssueTemplateHandler(nil, jiraHandler),
+		playbookHandler:     NewPlaybookHandler(nil),
+		subscriptionHandler: NewIssueSubscriptionHandler(nil, nil, jiraHandler),
+		pinnedIssueHandler:  NewPinnedIssueHandler(nil, jiraHandler),
+		undoHandler:         NewUndoHandler(nil, 0),
+		pluginHandler:       NewPluginHandler(),
 	}
 }
 
+// WithNotifications serves notify_channel from the users' configured channels
+func (h *RestToolHandler) WithNotifications(notificationHandler *NotificationHandler) *RestToolHandler {
+	h.notificationHandler = notificationHandler
+	return h
+}
+
+// WithExports serves the export tools, whose files are downloaded from /api/exports
+func (h *RestToolHandler) WithExports(exportHandler *ExportHandler) *RestToolHandler {
+	h.exportHandler = exportHandler
+	return h
+}
+
+// WithIssueTemplates serves the issue template tools from the users' saved templates
+func (h *RestToolHandler) WithIssueTemplates(templateHandler *IssueTemplateHandler) *RestToolHandler {
+	h.templateHandler = templateHandler
+	return h
+}
+
+// WithPlaybooks serves run_playbook from the users' saved playbooks
+func (h *RestToolHandler) WithPlaybooks(playbookHandler *PlaybookHandler) *RestToolHandler {
+	h.playbookHandler = playbookHandler
+	return h
+}
+
+// WithIssueSubscriptions serves the issue update subscription tools
+func (h *RestToolHandler) WithIssueSubscriptions(subscriptionHandler *IssueSubscriptionHandler) *RestToolHandler {
+	h.subscriptionHandler = subscriptionHandler
+	return h
+}
+
+// WithPinnedIssues serves the pinned issue tools from the users' saved pins
+func (h *RestToolHandler) WithPinnedIssues(pinnedIssueHandler *PinnedIssueHandler) *RestToolHandler {
+	h.pinnedIssueHandler = pinnedIssueHandler
+	return h
+}
+
+// WithUndo serves undo_last_action from the log of the users' changes
+func (h *RestToolHandler) WithUndo(undoHandler *UndoHandler) *RestToolHandler {
+	h.undoHandler = undoHandler
+	return h
+}
+
+// WithPlugins serves the tools of the plugins registered at startup
+func (h *RestToolHandler) WithPlugins(pluginHandler *PluginHandler) *RestToolHandler {
+	h.pluginHandler = pluginHandler
+	return h
+}
+
+// WithSettings applies the server-wide tool allowlist and response size limit
+func (h *RestToolHandler) WithSettings(settings *config.SettingsWatcher) *RestToolHandler {
+	h.settings = settings
+	return h
+}
+
 // HandleToolRequest generic handler for tool execution
 func (h *RestToolHandler) HandleToolRequest(w http.ResponseWriter, r *http.Request) {
-	fmt.Printf("Incoming REST request: %s %s from %s\n", r.Method, r.URL.Path, r.RemoteAddr)
-
 	// Allow both POST and GET
 	if r.Method != http.MethodPost && r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -48,23 +126,43 @@ func (h *RestToolHandler) HandleToolRequest(w http.ResponseWriter, r *http.Reque
 	if len(parts) >= 3 {
 		toolName = parts[2]
 	} else {
-		fmt.Printf("Malformed REST path: %s\n", r.URL.Path)
+		slog.DebugContext(r.Context(), "malformed REST tool path", "path", r.URL.Path)
 		http.Error(w, "Invalid path format. Expected /api/tools/{tool_name}", http.StatusBadRequest)
 		return
 	}
 
-	// Extract user ID
+	// Extract user ID and check the token may call this tool
 	userID := ""
+	orgID := ""
+	var scopes []string
 	if userCtx, ok := auth.ExtractUserFromContext(r.Context()); ok {
 		userID = userCtx.UserID
+		orgID = userCtx.OrgID
+		scopes = userCtx.Scopes
+		if err := userCtx.CheckToolScope(toolName); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	maxResponseBytes := 0
+	piiScan := pii.ModeOff
+	budget := config.DefaultRPCTimeout
+	if h.settings != nil {
+		settings := h.settings.Current()
+		if !settings.ToolEnabled(toolName) {
+			http.Error(w, fmt.Sprintf("Tool %s is disabled on this server", toolName), http.StatusForbidden)
+			return
+		}
+		maxResponseBytes = settings.MaxResponseBytes
+		piiScan = settings.PIIScan
+		budget = settings.ToolTimeout(toolName)
 	}
 
 	// Route to correct handler
 	var result mcp.ToolResult
 	var err error
 
-	fmt.Printf("REST Tool Call (%s): %s for user %s\n", r.Method, toolName, userID)
-
 	// Parse arguments from BOTH query string and body to be as robust as possible
 	arguments := make(map[string]interface{})
 
@@ -79,9 +177,8 @@ func (h *RestToolHandler) HandleToolRequest(w http.ResponseWriter, r *http.Reque
 	if r.Method == http.MethodPost && r.Body != nil {
 		bodyBytes, err := io.ReadAll(r.Body)
 		if err != nil {
-			fmt.Printf("❌ Error reading request body: %v\n", err)
+			slog.WarnContext(r.Context(), "failed to read REST tool request body", "tool", toolName, "error", err)
 		} else {
-			fmt.Printf("📥 Raw Body (%d bytes): %s\n", len(bodyBytes), string(bodyBytes))
 			if len(bodyBytes) > 0 {
 				bodyArgs := make(map[string]interface{})
 				if err := json.Unmarshal(bodyBytes, &bodyArgs); err == nil {
@@ -89,18 +186,24 @@ func (h *RestToolHandler) HandleToolRequest(w http.ResponseWriter, r *http.Reque
 						arguments[k] = v
 					}
 				} else {
-					fmt.Printf("❌ JSON Decode Error: %v\n", err)
+					slog.DebugContext(r.Context(), "REST tool body is not a JSON object", "tool", toolName, "bytes", len(bodyBytes), "error", err)
 				}
 			}
 		}
 	}
 
-	fmt.Printf("Final arguments for %s: %v\n", toolName, arguments)
+	slog.DebugContext(r.Context(), "REST tool call", "method", r.Method, "tool", toolName, "user_id", userID, "arguments", argumentNames(arguments))
 
-	// Trusted Service Override: Extract user_id from arguments if authenticated via Service Token
-	if isService, ok := r.Context().Value("IsServiceCall").(bool); ok && isService {
-		if injectedUser, ok := arguments["user_id"].(string); ok && injectedUser != "" {
-			fmt.Printf("🔒 Service Override: Using user_id=%s from input\n", injectedUser)
+	// Trusted Service Override: Extract user_id from arguments if authenticated via Service Token.
+	// A user_id in the query string was already checked by the auth middleware.
+	if caller, ok := auth.ServiceCallerFromContext(r.Context()); ok {
+		if injectedUser, ok := arguments["user_id"].(string); ok && injectedUser != "" && injectedUser != userID {
+			allowed := caller.CanImpersonate(injectedUser)
+			auth.AuditImpersonation(r, caller, injectedUser, allowed)
+			if !allowed {
+				http.Error(w, fmt.Sprintf("Forbidden: service token %q may not act as %s", caller.Name, injectedUser), http.StatusForbidden)
+				return
+			}
 			userID = injectedUser
 		}
 	}
@@ -108,19 +211,46 @@ func (h *RestToolHandler) HandleToolRequest(w http.ResponseWriter, r *http.Reque
 	call := mcp.ToolCall{
 		Name:      toolName,
 		Arguments: arguments,
+		OrgID:     orgID,
+		RequestID: logging.RequestIDFromContext(r.Context()),
+		Scopes:    scopes,
 	}
 
-	if toolName == "list_workspaces" || toolName == "workspace_status" {
+	workspaceID, _ := arguments["workspace_id"].(string)
+	start := time.Now()
+	stopWatchdog := WatchToolCall(toolName, workspaceID, call.RequestID, budget)
+	if h.pluginHandler.IsPluginTool(toolName) {
+		result, err = h.pluginHandler.HandleTool(call, userID)
+	} else if IsManagementTool(toolName) {
 		result, err = h.managementHandler.HandleTool(call, userID)
+	} else if IsCrossProductTool(toolName) {
+		result, err = h.crossProductHandler.HandleTool(call, userID)
+	} else if IsNotificationTool(toolName) {
+		result, err = h.notificationHandler.HandleTool(call, userID)
+	} else if IsExportTool(toolName) {
+		result, err = h.exportHandler.HandleTool(call, userID)
+	} else if IsIssueTemplateTool(toolName) {
+		result, err = h.templateHandler.HandleTool(call, userID)
+	} else if IsPlaybookTool(toolName) {
+		result, err = h.playbookHandler.HandleTool(call, userID)
+	} else if IsIssueSubscriptionTool(toolName) {
+		result, err = h.subscriptionHandler.HandleTool(call, userID)
+	} else if IsPinnedIssueTool(toolName) {
+		result, err = h.pinnedIssueHandler.HandleTool(call, userID)
+	} else if IsUndoTool(toolName) {
+		result, err = h.undoHandler.HandleTool(call, userID)
 	} else if strings.HasPrefix(toolName, "confluence_") {
 		result, err = h.confluenceHandler.HandleTool(call, userID)
-	} else if strings.HasPrefix(toolName, "jira_") {
+	} else if IsJiraServiceTool(toolName) {
 		result, err = h.jiraHandler.HandleTool(call, userID)
 	} else {
-		fmt.Printf("Unknown REST tool: %s\n", toolName)
+		stopWatchdog()
 		http.Error(w, fmt.Sprintf("Unknown tool: %s", toolName), http.StatusBadRequest)
 		return
 	}
+	stopWatchdog()
+	metrics.ToolCalls.Inc(toolName, workspaceID, metrics.Status(err != nil || result.IsError))
+	metrics.ToolCallDuration.ObserveSince(start, toolName, workspaceID)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -136,8 +266,38 @@ func (h *RestToolHandler) HandleToolRequest(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Results that appear to hold personal data are withheld in strict mode and flagged
+	// in the X-PII-Warning header otherwise
+	if flagged, findings := ApplyPIIPolicy(result, piiScan); len(findings) > 0 {
+		slog.WarnContext(r.Context(), "REST tool result contains likely personal data", "tool", toolName, "workspace_id", workspaceID, "kinds", findings.Kinds(), "mode", piiScan)
+		if flagged.IsError {
+			http.Error(w, flagged.Content[0].Text, http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-PII-Warning", findings.String())
+	}
+
+	// Deprecated tool versions keep working; only the first content block becomes the body,
+	// so their notice goes in headers instead
+	if IsJiraServiceTool(toolName) {
+		if notice := h.jiraHandler.Deprecation(toolName); notice != "" {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("X-Deprecation-Warning", notice)
+		}
+	}
+
 	// Return result
 	w.Header().Set("Content-Type", "application/json")
+
+	// A truncated result is no longer valid JSON; return the partial text with the warning
+	if truncated, ok := mcp.TruncateResult(result, maxResponseBytes); ok {
+		slog.WarnContext(r.Context(), "REST tool result truncated", "tool", toolName, "workspace_id", workspaceID, "limit_bytes", maxResponseBytes)
+		json.NewEncoder(w).Encode(map[string]string{
+			"result":  truncated.Content[0].Text,
+			"warning": truncated.Content[len(truncated.Content)-1].Text,
+		})
+		return
+	}
 	
 	// The tool handlers return JSON strings wrapped in Text content
 	// We want to return actual JSON, so we try to parse it first
@@ -161,3 +321,13 @@ func (h *RestToolHandler) HandleToolRequest(w http.ResponseWriter, r *http.Reque
 		})
 	}
 }
+
+// argumentNames lists argument keys for logging without their (possibly sensitive) values
+func argumentNames(arguments map[string]interface{}) []string {
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/bus"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/cache"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// resultCachePurgeInterval is how often expired results are dropped
+const resultCachePurgeInterval = time.Minute
+
+// ResultCache serves repeated read tool calls from memory for the TTLs configured under
+// tool_cache_ttls. Results are cached per user, workspace and arguments; a user's change
+// to a workspace drops that user's cached results for it.
+type ResultCache struct {
+	settings *config.SettingsWatcher
+	cache    *cache.SimpleCache
+}
+
+// cachedResult is a successful response and when it was fetched
+type cachedResult struct {
+	data    any
+	fetched time.Time
+}
+
+// NewResultCache creates an empty result cache
+func NewResultCache(settings *config.SettingsWatcher) *ResultCache {
+	c := &ResultCache{settings: settings, cache: cache.NewSimpleCache()}
+	go func() {
+		for range time.Tick(resultCachePurgeInterval) {
+			c.cache.DeleteExpired()
+		}
+	}()
+	return c
+}
+
+// Stats reports the cache's size and hit rate for /api/admin/caches
+func (c *ResultCache) Stats() cache.Stats {
+	return c.cache.Stats()
+}
+
+// WrapJira serves cacheable Jira reads from the cache
+func (c *ResultCache) WrapJira(callService func(models.JiraRequest) (*models.JiraResponse, error)) func(models.JiraRequest) (*models.JiraResponse, error) {
+	return func(req models.JiraRequest) (*models.JiraResponse, error) {
+		var bypass bool
+		req.Params, bypass = withoutCacheBypass(req.Params)
+		if models.JiraMutatingActions[req.Action] {
+			c.cache.DeletePrefix(resultCachePrefix(req.UserID, req.WorkspaceID))
+			return callService(req)
+		}
+
+		ttl := c.ttl(bus.JiraService, req.Action, models.JiraCacheableActions)
+		key := resultCacheKey(req.UserID, req.WorkspaceID, req.Action, req.Params)
+		if ttl <= 0 || key == "" {
+			return callService(req)
+		}
+		if !bypass {
+			if cached, ok := c.cache.Get(key); ok {
+				result := cached.(cachedResult)
+				return &models.JiraResponse{Success: true, Data: result.data, RequestID: req.RequestID, Cached: cacheInfo(result, ttl)}, nil
+			}
+		}
+
+		resp, err := callService(req)
+		if err == nil && resp.Success {
+			c.cache.Set(key, cachedResult{data: resp.Data, fetched: time.Now()}, ttl)
+		}
+		return resp, err
+	}
+}
+
+// WrapConfluence serves cacheable Confluence reads from the cache
+func (c *ResultCache) WrapConfluence(callService func(models.ConfluenceRequest) (*models.ConfluenceResponse, error)) func(models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+	return func(req models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+		var bypass bool
+		req.Params, bypass = withoutCacheBypass(req.Params)
+		if models.ConfluenceMutatingActions[req.Action] {
+			c.cache.DeletePrefix(resultCachePrefix(req.UserID, req.WorkspaceID))
+			return callService(req)
+		}
+
+		ttl := c.ttl(bus.ConfluenceService, req.Action, models.ConfluenceCacheableActions)
+		key := resultCacheKey(req.UserID, req.WorkspaceID, req.Action, req.Params)
+		if ttl <= 0 || key == "" {
+			return callService(req)
+		}
+		if !bypass {
+			if cached, ok := c.cache.Get(key); ok {
+				result := cached.(cachedResult)
+				return &models.ConfluenceResponse{Success: true, Data: result.data, RequestID: req.RequestID, Cached: cacheInfo(result, ttl)}, nil
+			}
+		}
+
+		resp, err := callService(req)
+		if err == nil && resp.Success {
+			c.cache.Set(key, cachedResult{data: resp.Data, fetched: time.Now()}, ttl)
+		}
+		return resp, err
+	}
+}
+
+// ttl returns how long an action's results are cached; 0 when they are not
+func (c *ResultCache) ttl(service, action string, cacheable map[string]bool) time.Duration {
+	if !cacheable[action] {
+		return 0
+	}
+	return c.settings.Current().ToolCacheTTLs[service+"_"+action]
+}
+
+// withoutCacheBypass removes the cache_bypass argument, which the services do not take,
+// and reports whether it was set. The arguments may be shared with the caller, so a copy
+// is returned when there is anything to remove.
+func withoutCacheBypass(params map[string]interface{}) (map[string]interface{}, bool) {
+	value, ok := params[models.CacheBypassParam]
+	if !ok {
+		return params, false
+	}
+	bypass, _ := value.(bool)
+	copied := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		copied[k] = v
+	}
+	delete(copied, models.CacheBypassParam)
+	return copied, bypass
+}
+
+// resultCachePrefix starts the keys of a user's cached results for a workspace
+func resultCachePrefix(userID, workspaceID string) string {
+	return userID + "\x00" + workspaceID + "\x00"
+}
+
+// resultCacheKey identifies a call by user, workspace, action and arguments (whose JSON
+// has sorted keys). It is empty when the arguments cannot be encoded.
+func resultCacheKey(userID, workspaceID, action string, params map[string]interface{}) string {
+	args := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if k != storage.IdempotencyKeyParam {
+			args[k] = v
+		}
+	}
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return ""
+	}
+	return resultCachePrefix(userID, workspaceID) + action + "\x00" + string(encoded)
+}
+
+// cacheInfo describes a cached result for the response
+func cacheInfo(result cachedResult, ttl time.Duration) *models.CacheInfo {
+	return &models.CacheInfo{
+		AgeSeconds: int64(time.Since(result.fetched).Seconds()),
+		TTLSeconds: int64(ttl.Seconds()),
+	}
+}
+
+// cacheHint tells the model that a result came from the cache and how to refresh it
+func cacheHint(info *models.CacheInfo) mcp.ContentBlock {
+	return mcp.ContentBlock{
+		Type: "text",
+		Text: fmt.Sprintf("ℹ️ Cached result from %ds ago (results of this tool are kept for %ds). Call again with %s: true for fresh data.",
+			info.AgeSeconds, info.TTLSeconds, models.CacheBypassParam),
+	}
+}
+
+// withCacheBypass adds the optional cache_bypass argument to the tools whose results may
+// be cached
+func withCacheBypass(tools []mcp.Tool, cacheable map[string]bool, actionFor func(string) string) []mcp.Tool {
+	for _, tool := range tools {
+		if !cacheable[actionFor(tool.Name)] {
+			continue
+		}
+		if properties, ok := tool.InputSchema["properties"].(map[string]interface{}); ok {
+			properties[models.CacheBypassParam] = map[string]interface{}{
+				"type":        "boolean",
+				"description": "Set to true to skip the server's result cache and fetch fresh data",
+			}
+		}
+	}
+	return tools
+}
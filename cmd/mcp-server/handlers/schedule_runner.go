@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/cron"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/notify"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const (
+	// scheduleTickInterval is how often the runner looks for due schedules; cron
+	// expressions have minute resolution
+	scheduleTickInterval = time.Minute
+	// scheduleWebhookTimeout bounds a webhook delivery
+	scheduleWebhookTimeout = 30 * time.Second
+	// maxScheduleRunsKept is how many runs of each schedule the history keeps
+	maxScheduleRunsKept = 100
+	// maxStoredStepOutput is how much of each step's output the history keeps
+	maxStoredStepOutput = 64 * 1024
+)
+
+// previousOutputPlaceholder in a step's arguments is replaced with the previous step's output
+const previousOutputPlaceholder = "{{previous}}"
+
+// Start runs due schedules every minute for the life of the process. Replicas share the
+// schedules table, and each run is claimed by exactly one of them.
+func (h *ScheduleHandler) Start() {
+	if h.store == nil {
+		slog.Info("scheduled tool pipelines disabled; they require database storage (DATABASE_URL)")
+		return
+	}
+
+	go func() {
+		h.runDue(time.Now())
+		for now := range time.Tick(scheduleTickInterval) {
+			h.runDue(now)
+		}
+	}()
+}
+
+// runDue starts the schedules that are due. Runs missed while the server was down are
+// run once, not once per missed time.
+func (h *ScheduleHandler) runDue(now time.Time) {
+	schedules, err := h.store.DueSchedules(now)
+	if err != nil {
+		slog.Warn("failed to list due schedules", "error", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		next := time.Time{}
+		if loc, err := time.LoadLocation(schedule.Timezone); err == nil {
+			if expr, err := cron.Parse(schedule.Cron); err == nil {
+				next = expr.Next(now.In(loc))
+			}
+		}
+		// A zero next run disables the schedule until it is fixed
+		claimed, err := h.store.ClaimScheduleRun(schedule.ID, *schedule.NextRunAt, next)
+		if err != nil {
+			slog.Warn("failed to claim schedule run", "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		run, err := h.startRun(schedule, "schedule")
+		if err != nil {
+			slog.Warn("failed to start schedule run", "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+		go h.execute(schedule, run)
+	}
+}
+
+// startRun records the start of a run in the schedule's history
+func (h *ScheduleHandler) startRun(schedule models.Schedule, triggeredBy string) (*models.ScheduleRun, error) {
+	run := &models.ScheduleRun{
+		ID:          uuid.New().String(),
+		ScheduleID:  schedule.ID,
+		TriggeredBy: triggeredBy,
+		Status:      models.ScheduleRunRunning,
+		Steps:       []models.ScheduleStepResult{},
+		StartedAt:   time.Now().UTC(),
+	}
+	if err := h.store.CreateScheduleRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// execute runs a schedule's steps in order as its owner, delivers the results and
+// records the outcome. The first failing step ends the run.
+func (h *ScheduleHandler) execute(schedule models.Schedule, run *models.ScheduleRun) {
+	outputs := make([]string, 0, len(schedule.Steps))
+	previous := ""
+	for i, step := range schedule.Steps {
+		arguments, _ := substitutePrevious(step.Arguments, previous).(map[string]interface{})
+		call := mcp.ToolCall{
+			Name:      step.Tool,
+			Arguments: arguments,
+			OrgID:     schedule.OrgID,
+			RequestID: "schedule_" + run.ID,
+		}
+		result, err := h.runTool(call, schedule.UserID)
+		output := toolResultText(result)
+		if output == "" && err != nil {
+			output = err.Error()
+		}
+
+		stored := output
+		if len(stored) > maxStoredStepOutput {
+			stored = stored[:maxStoredStepOutput] + "\n[truncated]"
+		}
+		run.Steps = append(run.Steps, models.ScheduleStepResult{Tool: step.Tool, IsError: err != nil || result.IsError, Output: stored})
+		outputs = append(outputs, output)
+
+		if err != nil || result.IsError {
+			run.Status = models.ScheduleRunFailed
+			run.Error = fmt.Sprintf("step %d (%s) failed", i+1, step.Tool)
+			break
+		}
+		previous = output
+	}
+	if run.Status == models.ScheduleRunRunning {
+		run.Status = models.ScheduleRunSucceeded
+	}
+
+	link := ""
+	if schedule.Output != nil {
+		var err error
+		if link, err = h.deliver(schedule, run, outputs); err != nil {
+			message := fmt.Sprintf("failed to deliver output: %v", err)
+			if run.Error != "" {
+				message = run.Error + "; " + message
+			}
+			run.Status = models.ScheduleRunFailed
+			run.Error = message
+		}
+	}
+
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+	if err := h.store.FinishScheduleRun(run); err != nil {
+		slog.Warn("failed to record schedule run", "schedule_id", schedule.ID, "run_id", run.ID, "error", err)
+	}
+	if err := h.store.PurgeScheduleRuns(schedule.ID, maxScheduleRunsKept); err != nil {
+		slog.Warn("failed to purge old schedule runs", "schedule_id", schedule.ID, "error", err)
+	}
+	slog.Info("schedule run finished", "schedule_id", schedule.ID, "run_id", run.ID,
+		"status", run.Status, "duration", finishedAt.Sub(run.StartedAt).String())
+	h.notifier.Notify(schedule.UserID, notify.EventScheduleResult, scheduleResultMessage(schedule, run, link))
+}
+
+// scheduleResultMessage summarizes a finished run for the owner's notification channels
+func scheduleResultMessage(schedule models.Schedule, run *models.ScheduleRun, link string) notify.Message {
+	msg := notify.Message{
+		Title: fmt.Sprintf("Schedule %q succeeded", schedule.Name),
+		Level: notify.LevelSuccess,
+		URL:   link,
+		Fields: []notify.Field{
+			{Name: "Steps", Value: fmt.Sprintf("%d of %d", len(run.Steps), len(schedule.Steps))},
+			{Name: "Started", Value: run.StartedAt.Format(time.RFC3339)},
+		},
+	}
+	if run.FinishedAt != nil {
+		msg.Fields = append(msg.Fields, notify.Field{Name: "Duration", Value: run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond).String()})
+	}
+	if run.Status != models.ScheduleRunSucceeded {
+		msg.Title = fmt.Sprintf("Schedule %q failed", schedule.Name)
+		msg.Text = run.Error
+		msg.Level = notify.LevelError
+	}
+	return msg
+}
+
+// substitutePrevious copies step arguments, replacing placeholder strings with the
+// previous step's output
+func substitutePrevious(v interface{}, previous string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = substitutePrevious(value, previous)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, value := range v {
+			copied[i] = substitutePrevious(value, previous)
+		}
+		return copied
+	case string:
+		if v == previousOutputPlaceholder {
+			return previous
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// toolResultText joins the text blocks of a tool result
+func toolResultText(result mcp.ToolResult) string {
+	texts := make([]string, 0, len(result.Content))
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			texts = append(texts, block.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// deliver sends a run's results to the schedule's output and returns the published page's
+// URL, if any. Webhooks hear about every run; a Confluence page is only replaced by a
+// successful run.
+func (h *ScheduleHandler) deliver(schedule models.Schedule, run *models.ScheduleRun, outputs []string) (string, error) {
+	switch schedule.Output.Type {
+	case models.ScheduleOutputWebhook:
+		return "", h.postWebhook(schedule, run, outputs)
+	case models.ScheduleOutputConfluencePage:
+		if run.Status != models.ScheduleRunSucceeded {
+			return "", nil
+		}
+		out := schedule.Output
+		page, _, err := h.pages.publishPage(out.WorkspaceID, schedule.UserID, schedule.OrgID, "schedule_"+run.ID,
+			out.SpaceKey, out.ParentID, out.Title, renderScheduleReport(schedule, run, outputs))
+		if err != nil {
+			return "", err
+		}
+		link, _ := page["url"].(string)
+		return link, nil
+	default:
+		return "", fmt.Errorf("unknown output type %q", schedule.Output.Type)
+	}
+}
+
+// scheduleWebhookStep is a step's result in a webhook payload. Output is embedded as JSON
+// when the tool returned JSON, and as a string otherwise.
+type scheduleWebhookStep struct {
+	Tool    string          `json:"tool"`
+	IsError bool            `json:"is_error,omitempty"`
+	Output  json.RawMessage `json:"output"`
+}
+
+// postWebhook POSTs a run's results as JSON
+func (h *ScheduleHandler) postWebhook(schedule models.Schedule, run *models.ScheduleRun, outputs []string) error {
+	steps := make([]scheduleWebhookStep, 0, len(outputs))
+	for i, output := range outputs {
+		raw := json.RawMessage(output)
+		if !json.Valid(raw) {
+			raw, _ = json.Marshal(output)
+		}
+		steps = append(steps, scheduleWebhookStep{Tool: run.Steps[i].Tool, IsError: run.Steps[i].IsError, Output: raw})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"schedule_id":   schedule.ID,
+		"schedule_name": schedule.Name,
+		"run_id":        run.ID,
+		"status":        run.Status,
+		"error":         run.Error,
+		"started_at":    run.StartedAt,
+		"steps":         steps,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, schedule.Output.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Trilix-Scheduler")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("webhook returned " + resp.Status)
+	}
+	return nil
+}
+
+// renderScheduleReport renders a run's results in Confluence storage format, one section
+// per step, with JSON results in code blocks
+func renderScheduleReport(schedule models.Schedule, run *models.ScheduleRun, outputs []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<p>Generated by the schedule <strong>%s</strong> on %s.</p>",
+		html.EscapeString(schedule.Name), run.StartedAt.Format("2006-01-02 15:04 MST"))
+	for i, output := range outputs {
+		fmt.Fprintf(&b, "<h2>%s</h2>", html.EscapeString(run.Steps[i].Tool))
+		b.WriteString(`<ac:structured-macro ac:name="code">`)
+		if json.Valid([]byte(output)) {
+			b.WriteString(`<ac:parameter ac:name="language">json</ac:parameter>`)
+		}
+		// "]]>" cannot appear inside CDATA, so it is split across two sections
+		fmt.Fprintf(&b, "<ac:plain-text-body><![CDATA[%s]]></ac:plain-text-body></ac:structured-macro>",
+			strings.ReplaceAll(output, "]]>", "]]]]><![CDATA[>"))
+	}
+	return b.String()
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/cron"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/notify"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const (
+	maxScheduleSteps        = 10
+	defaultScheduleRunLimit = 20
+	maxScheduleRunLimit     = 100
+)
+
+// ScheduleHandler handles the /api/schedules endpoints and runs the saved tool pipelines
+// when they are due (see schedule_runner.go)
+type ScheduleHandler struct {
+	store    storage.ScheduleStoreInterface
+	tools    map[string]bool
+	runTool  func(mcp.ToolCall, string) (mcp.ToolResult, error)
+	pages    *CrossProductHandler
+	client   *http.Client
+	notifier *notify.Notifier // Reports run results to the owner's channels (nil = none)
+}
+
+// NewScheduleHandler creates a new schedule handler. Steps may call any of tools, and
+// run through runTool as the schedule's owner; pages publishes confluence_page outputs.
+// store may be nil when schedules are not supported (file-based storage).
+func NewScheduleHandler(store storage.ScheduleStoreInterface, tools []mcp.Tool, runTool func(mcp.ToolCall, string) (mcp.ToolResult, error), pages *CrossProductHandler) *ScheduleHandler {
+	names := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		names[tool.Name] = true
+	}
+	return &ScheduleHandler{
+		store:   store,
+		tools:   names,
+		runTool: runTool,
+		pages:   pages,
+		client:  &http.Client{Timeout: scheduleWebhookTimeout},
+	}
+}
+
+// WithNotifier reports the result of every run to the schedule owner's notification channels
+func (h *ScheduleHandler) WithNotifier(notifier *notify.Notifier) *ScheduleHandler {
+	h.notifier = notifier
+	return h
+}
+
+// ScheduleRequest represents the request to create or replace a schedule
+type ScheduleRequest struct {
+	Name     string                 `json:"name"`
+	Cron     string                 `json:"cron"`
+	Timezone string                 `json:"timezone,omitempty"` // Defaults to UTC
+	Steps    []models.ScheduleStep  `json:"steps"`
+	Output   *models.ScheduleOutput `json:"output,omitempty"`
+	Enabled  *bool                  `json:"enabled,omitempty"` // Defaults to true
+}
+
+// HandleSchedules handles /api/schedules, /api/schedules/{id} and /api/schedules/{id}/runs
+func (h *ScheduleHandler) HandleSchedules(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil {
+		http.Error(w, "Schedules require database storage (DATABASE_URL)", http.StatusNotImplemented)
+		return
+	}
+
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	scheduleID, sub := "", ""
+	if len(r.URL.Path) > len("/api/schedules/") {
+		scheduleID, sub, _ = strings.Cut(r.URL.Path[len("/api/schedules/"):], "/")
+	}
+
+	switch {
+	case scheduleID == "" && r.Method == http.MethodGet:
+		h.handleList(w, userCtx)
+	case scheduleID == "" && r.Method == http.MethodPost:
+		h.handleCreate(w, r, userCtx)
+	case sub == "" && r.Method == http.MethodGet:
+		h.handleGet(w, userCtx, scheduleID)
+	case sub == "" && r.Method == http.MethodPut:
+		h.handleUpdate(w, r, userCtx, scheduleID)
+	case sub == "" && r.Method == http.MethodDelete:
+		h.handleDelete(w, userCtx, scheduleID)
+	case sub == "runs" && r.Method == http.MethodGet:
+		h.handleListRuns(w, r, userCtx, scheduleID)
+	case sub == "runs" && r.Method == http.MethodPost:
+		h.handleRunNow(w, userCtx, scheduleID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCreate handles POST /api/schedules
+func (h *ScheduleHandler) handleCreate(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext) {
+	var req ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	schedule := models.Schedule{
+		ID:     uuid.New().String(),
+		UserID: userCtx.UserID,
+		OrgID:  userCtx.OrgID,
+	}
+	if err := h.applyScheduleRequest(&schedule, req, userCtx); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.CreateSchedule(&schedule); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save schedule: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// handleList handles GET /api/schedules
+func (h *ScheduleHandler) handleList(w http.ResponseWriter, userCtx *auth.UserContext) {
+	schedules, err := h.store.ListSchedules(userCtx.UserID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list schedules: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedules)
+}
+
+// handleGet handles GET /api/schedules/{id}
+func (h *ScheduleHandler) handleGet(w http.ResponseWriter, userCtx *auth.UserContext, scheduleID string) {
+	schedule, ok := h.getSchedule(w, userCtx, scheduleID)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// handleUpdate handles PUT /api/schedules/{id}, which replaces the schedule
+func (h *ScheduleHandler) handleUpdate(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext, scheduleID string) {
+	var req ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	schedule, ok := h.getSchedule(w, userCtx, scheduleID)
+	if !ok {
+		return
+	}
+	if err := h.applyScheduleRequest(schedule, req, userCtx); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.UpdateSchedule(schedule); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Schedule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to update schedule: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// handleDelete handles DELETE /api/schedules/{id}
+func (h *ScheduleHandler) handleDelete(w http.ResponseWriter, userCtx *auth.UserContext, scheduleID string) {
+	if err := h.store.DeleteSchedule(userCtx.UserID, scheduleID); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Schedule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete schedule: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListRuns handles GET /api/schedules/{id}/runs, the schedule's execution history
+func (h *ScheduleHandler) handleListRuns(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext, scheduleID string) {
+	if _, ok := h.getSchedule(w, userCtx, scheduleID); !ok {
+		return
+	}
+
+	limit := defaultScheduleRunLimit
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > maxScheduleRunLimit {
+		limit = maxScheduleRunLimit
+	}
+
+	runs, err := h.store.ListScheduleRuns(scheduleID, limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list schedule runs: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// handleRunNow handles POST /api/schedules/{id}/runs, which starts a run immediately.
+// The run continues in the background; poll the history for its outcome.
+func (h *ScheduleHandler) handleRunNow(w http.ResponseWriter, userCtx *auth.UserContext, scheduleID string) {
+	schedule, ok := h.getSchedule(w, userCtx, scheduleID)
+	if !ok {
+		return
+	}
+
+	run, err := h.startRun(*schedule, "manual")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to start schedule run: %v", err), http.StatusInternalServerError)
+		return
+	}
+	started := *run
+	go h.execute(*schedule, run)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(started)
+}
+
+// getSchedule loads one of the user's schedules, writing the error response if it cannot
+func (h *ScheduleHandler) getSchedule(w http.ResponseWriter, userCtx *auth.UserContext, scheduleID string) (*models.Schedule, bool) {
+	schedule, err := h.store.GetSchedule(userCtx.UserID, scheduleID)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Schedule not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, fmt.Sprintf("Failed to get schedule: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return schedule, true
+}
+
+// applyScheduleRequest validates a create or replace request and copies it onto the
+// schedule, computing its next run
+func (h *ScheduleHandler) applyScheduleRequest(schedule *models.Schedule, req ScheduleRequest, userCtx *auth.UserContext) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("missing required field: name")
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q", req.Timezone)
+	}
+	expr, err := cron.Parse(req.Cron)
+	if err != nil {
+		return err
+	}
+	next := expr.Next(time.Now().In(loc))
+	if next.IsZero() {
+		return fmt.Errorf("cron expression %q never fires", req.Cron)
+	}
+
+	if len(req.Steps) == 0 {
+		return fmt.Errorf("missing required field: steps")
+	}
+	if len(req.Steps) > maxScheduleSteps {
+		return fmt.Errorf("a schedule can have at most %d steps", maxScheduleSteps)
+	}
+	// Scheduled runs have no token, so a restricted caller may only schedule what it may call
+	for i, step := range req.Steps {
+		if !h.tools[step.Tool] {
+			return fmt.Errorf("step %d: unknown tool %q", i+1, step.Tool)
+		}
+		if err := userCtx.CheckToolScope(step.Tool); err != nil {
+			return fmt.Errorf("step %d: %v", i+1, err)
+		}
+	}
+	if err := validateScheduleOutput(req.Output, userCtx); err != nil {
+		return err
+	}
+
+	schedule.Name = strings.TrimSpace(req.Name)
+	schedule.Cron = strings.TrimSpace(req.Cron)
+	schedule.Timezone = req.Timezone
+	schedule.Steps = req.Steps
+	schedule.Output = req.Output
+	schedule.Enabled = req.Enabled == nil || *req.Enabled
+	schedule.NextRunAt = nil
+	if schedule.Enabled {
+		schedule.NextRunAt = &next
+	}
+	return nil
+}
+
+// validateScheduleOutput checks that an output has what its type needs
+func validateScheduleOutput(output *models.ScheduleOutput, userCtx *auth.UserContext) error {
+	if output == nil {
+		return nil
+	}
+	switch output.Type {
+	case models.ScheduleOutputWebhook:
+		u, err := url.Parse(output.URL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("output.url must be an http or https URL")
+		}
+	case models.ScheduleOutputConfluencePage:
+		if output.WorkspaceID == "" || output.SpaceKey == "" || output.Title == "" {
+			return fmt.Errorf("a confluence_page output needs workspace_id, space_key and title")
+		}
+		if !userCtx.HasScope(auth.ScopeConfluenceWrite) {
+			return fmt.Errorf("insufficient_scope: a confluence_page output requires the %s scope", auth.ScopeConfluenceWrite)
+		}
+	default:
+		return fmt.Errorf("output.type must be %s or %s", models.ScheduleOutputWebhook, models.ScheduleOutputConfluencePage)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+// searchTool is search_atlassian, which runs a Jira text search and a Confluence text
+// search concurrently and merges their results
+func searchTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "search_atlassian",
+		Description: "Search Jira issues and Confluence pages at once for free text (e.g., 'Q3 billing migration'). Results from both products are merged and ranked, and each is tagged with its product and type.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"workspace_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Workspace ID",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Text to search for",
+				},
+				"products": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"jira", "confluence"},
+					},
+					"description": "Products to search (defaults to both)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of results per product (up to 50)",
+					"default":     defaultSearchLimit,
+				},
+			},
+			"required": []string{"workspace_id", "query"},
+		},
+	}
+}
+
+// searchHit is one merged search result
+type searchHit struct {
+	Product string  `json:"product"` // jira or confluence
+	Type    string  `json:"type"`    // The issue type, or page / blogpost
+	Key     string  `json:"key,omitempty"`
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	URL     string  `json:"url,omitempty"`
+	Status  string  `json:"status,omitempty"`
+	Space   string  `json:"space,omitempty"`
+	Updated string  `json:"updated,omitempty"`
+	Score   float64 `json:"score"`
+}
+
+// handleSearch handles a search_atlassian call
+func (h *CrossProductHandler) handleSearch(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	query, _ := call.Arguments["query"].(string)
+	if workspaceID == "" || strings.TrimSpace(query) == "" {
+		return errorResult(requestID, "workspace_id and query are required"), errors.New("workspace_id and query are required")
+	}
+
+	limit := defaultSearchLimit
+	if l, ok := call.Arguments["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	products := map[string]bool{"jira": true, "confluence": true}
+	if p, ok := call.Arguments["products"].([]interface{}); ok && len(p) > 0 {
+		products = map[string]bool{}
+		for _, v := range p {
+			if product, ok := v.(string); ok {
+				products[product] = true
+			}
+		}
+	}
+	if !products["jira"] && !products["confluence"] {
+		return errorResult(requestID, "products must include jira or confluence"), errors.New("no products to search")
+	}
+
+	var (
+		wg       sync.WaitGroup
+		jiraHits []searchHit
+		pageHits []searchHit
+		jiraErr  error
+		pageErr  error
+		searched int
+		quoted   = quoteSearchText(query)
+	)
+	if products["jira"] {
+		searched++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			jiraHits, jiraErr = h.searchJira(workspaceID, userID, call.OrgID, requestID, quoted, limit)
+		}()
+	}
+	if products["confluence"] {
+		searched++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pageHits, pageErr = h.searchConfluence(workspaceID, userID, call.OrgID, requestID, quoted, limit)
+		}()
+	}
+	wg.Wait()
+
+	// One product failing (e.g. a site without Confluence) still returns the other's results
+	failures := map[string]string{}
+	if jiraErr != nil {
+		failures["jira"] = jiraErr.Error()
+	}
+	if pageErr != nil {
+		failures["confluence"] = pageErr.Error()
+	}
+	if len(failures) == searched {
+		err := errors.Join(jiraErr, pageErr)
+		return errorResult(requestID, err.Error()), err
+	}
+
+	result := map[string]interface{}{
+		"query":   query,
+		"results": rankSearchHits(query, jiraHits, pageHits),
+	}
+	if len(failures) > 0 {
+		result["errors"] = failures
+	}
+
+	return jsonResult(result)
+}
+
+// quoteSearchText quotes text as a JQL or CQL string, e.g. for a text ~ clause
+func quoteSearchText(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(strings.TrimSpace(text)) + `"`
+}
+
+// searchJira runs a text search through the Jira service's list_issues action. Without
+// an ORDER BY clause, Jira orders text search results by relevance.
+func (h *CrossProductHandler) searchJira(workspaceID, userID, orgID, requestID, quoted string, limit int) ([]searchHit, error) {
+	issues, err := h.listIssues(models.JiraRequest{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       orgID,
+		Params: map[string]interface{}{
+			"jql":    "text ~ " + quoted,
+			"limit":  float64(limit),
+			"fields": []interface{}{"summary", "status", "issuetype", "updated"},
+		},
+		RequestID: requestID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]searchHit, 0, len(issues))
+	for _, issue := range issues {
+		hit := searchHit{Product: "jira", Type: "issue", Key: issue.Key, ID: issue.ID}
+		hit.Title, _ = issue.Fields["summary"].(string)
+		hit.Updated, _ = issue.Fields["updated"].(string)
+		if issueType, ok := issue.Fields["issuetype"].(map[string]interface{}); ok {
+			if name, ok := issueType["name"].(string); ok && name != "" {
+				hit.Type = strings.ToLower(name)
+			}
+		}
+		if status, ok := issue.Fields["status"].(map[string]interface{}); ok {
+			hit.Status, _ = status["name"].(string)
+		}
+		hit.URL = issueBrowseURL(issue)
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// searchConfluence runs a text search for pages and blog posts through the Confluence
+// service's search action, which orders results by relevance
+func (h *CrossProductHandler) searchConfluence(workspaceID, userID, orgID, requestID, quoted string, limit int) ([]searchHit, error) {
+	var search models.SearchResults
+	err := h.callConfluence(models.ConfluenceRequest{
+		Action:      "search",
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		OrgID:       orgID,
+		Params: map[string]interface{}{
+			"query": "text ~ " + quoted + " AND type in (page, blogpost)",
+			"limit": float64(limit),
+		},
+		RequestID: requestID,
+	}, &search)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]searchHit, 0, len(search.Results))
+	for _, page := range search.Results {
+		hit := searchHit{Product: "confluence", Type: page.Type, ID: page.ID, Title: page.Title, Space: page.Space.Key}
+		if hit.Type == "" {
+			hit.Type = "page"
+		}
+		if search.Links.Base != "" && page.Links.WebUI != "" {
+			hit.URL = strings.TrimRight(search.Links.Base, "/") + page.Links.WebUI
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// rankSearchHits merges each product's relevance-ordered results. A result scores the
+// reciprocal of its rank within its product, so the top Jira issue and the top
+// Confluence page start level, plus up to 1 for how much of the query its title contains.
+func rankSearchHits(query string, productHits ...[]searchHit) []searchHit {
+	terms := strings.Fields(strings.ToLower(query))
+	phrase := strings.Join(terms, " ")
+
+	// Interleave the products rank by rank so that ties do not favor either product
+	merged := []searchHit{}
+	for rank := 0; ; rank++ {
+		added := false
+		for _, hits := range productHits {
+			if rank >= len(hits) {
+				continue
+			}
+			added = true
+			hit := hits[rank]
+			title := strings.ToLower(hit.Title)
+			score := 1 / float64(rank+1)
+			if strings.Contains(title, phrase) {
+				score++
+			} else if len(terms) > 0 {
+				matched := 0
+				for _, term := range terms {
+					if strings.Contains(title, term) {
+						matched++
+					}
+				}
+				score += 0.5 * float64(matched) / float64(len(terms))
+			}
+			// Two decimal places are enough to explain the order
+			hit.Score = float64(int(score*100+0.5)) / 100
+			merged = append(merged, hit)
+		}
+		if !added {
+			break
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Score > merged[j].Score })
+	return merged
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+)
+
+// ServiceTokenHandler handles the admin-only service token management endpoints
+type ServiceTokenHandler struct {
+	tokenStore storage.ServiceTokenStoreInterface
+}
+
+// NewServiceTokenHandler creates a new service token handler. tokenStore may be nil when
+// service tokens are not supported (file-based storage).
+func NewServiceTokenHandler(tokenStore storage.ServiceTokenStoreInterface) *ServiceTokenHandler {
+	return &ServiceTokenHandler{tokenStore: tokenStore}
+}
+
+// CreateServiceTokenRequest represents the request to create a service token
+type CreateServiceTokenRequest struct {
+	Name         string   `json:"name"`
+	AllowedUsers []string `json:"allowedUsers"` // User IDs the token may act as
+}
+
+// CreateServiceTokenResponse includes the secret, which is only ever returned here
+type CreateServiceTokenResponse struct {
+	models.ServiceToken
+	Token string `json:"token"`
+}
+
+// HandleServiceTokens handles /api/service-tokens and /api/service-tokens/{id}
+func (h *ServiceTokenHandler) HandleServiceTokens(w http.ResponseWriter, r *http.Request) {
+	if h.tokenStore == nil {
+		http.Error(w, "Service tokens require database storage (DATABASE_URL)", http.StatusNotImplemented)
+		return
+	}
+
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tokenID := ""
+	if len(r.URL.Path) > len("/api/service-tokens/") {
+		tokenID = r.URL.Path[len("/api/service-tokens/"):]
+	}
+
+	switch {
+	case tokenID == "" && r.Method == http.MethodGet:
+		h.handleList(w)
+	case tokenID == "" && r.Method == http.MethodPost:
+		h.handleCreate(w, r, userCtx)
+	case tokenID != "" && r.Method == http.MethodDelete:
+		h.handleRevoke(w, r, userCtx, tokenID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCreate handles POST /api/service-tokens
+func (h *ServiceTokenHandler) handleCreate(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext) {
+	var req CreateServiceTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "Missing required field: name", http.StatusBadRequest)
+		return
+	}
+	if len(req.AllowedUsers) == 0 {
+		http.Error(w, "Missing required field: allowedUsers", http.StatusBadRequest)
+		return
+	}
+	for _, userID := range req.AllowedUsers {
+		if userID == "" || userID == "*" {
+			http.Error(w, fmt.Sprintf("Invalid user ID in allowedUsers: %q", userID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	secret, hash, prefix, err := auth.GenerateServiceToken()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to generate service token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	token := models.ServiceToken{
+		ID:           uuid.New().String(),
+		Name:         req.Name,
+		Prefix:       prefix,
+		AllowedUsers: req.AllowedUsers,
+		CreatedBy:    userCtx.UserID,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := h.tokenStore.CreateServiceToken(&token, hash); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save service token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "AUDIT service token created", "token_id", token.ID, "name", token.Name, "created_by", userCtx.UserID, "allowed_users", len(token.AllowedUsers))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateServiceTokenResponse{ServiceToken: token, Token: secret})
+}
+
+// handleList handles GET /api/service-tokens
+func (h *ServiceTokenHandler) handleList(w http.ResponseWriter) {
+	tokens, err := h.tokenStore.ListServiceTokens()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list service tokens: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// handleRevoke handles DELETE /api/service-tokens/{id}
+func (h *ServiceTokenHandler) handleRevoke(w http.ResponseWriter, r *http.Request, userCtx *auth.UserContext, tokenID string) {
+	if err := h.tokenStore.RevokeServiceToken(tokenID); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Service token not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to revoke service token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "AUDIT service token revoked", "token_id", tokenID, "revoked_by", userCtx.UserID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,456 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/notify"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const (
+	// maxIssueSubscriptions bounds how many subscriptions each user may have
+	maxIssueSubscriptions = 20
+	// subscriptionCheckInterval is how often each subscription's query is run
+	subscriptionCheckInterval = 5 * time.Minute
+	// subscriptionTickInterval is how often the poller looks for due subscriptions
+	subscriptionTickInterval = time.Minute
+	// maxSubscriptionIssues bounds how many changed issues one delivery lists
+	maxSubscriptionIssues = 50
+	// subscriptionDeliveryTimeout bounds a notification or webhook delivery
+	subscriptionDeliveryTimeout = 30 * time.Second
+)
+
+// errIssueSubscriptionsUnavailable is returned when subscriptions are used without database storage
+var errIssueSubscriptionsUnavailable = errors.New("issue subscriptions require database storage (DATABASE_URL)")
+
+// subscriptionOrderByPattern finds an ORDER BY clause, which cannot be combined with the
+// subscription's own time restriction
+var subscriptionOrderByPattern = regexp.MustCompile(`(?i)\border\s+by\b`)
+
+// IssueSubscriptionHandler serves the tools that subscribe to Jira issue changes, and polls
+// Jira for the changes. Atlassian webhooks are not received by the server, so each
+// subscription's query is run every few minutes for the issues updated since its last check.
+type IssueSubscriptionHandler struct {
+	store    storage.IssueSubscriptionStoreInterface
+	channels storage.NotificationChannelStoreInterface
+	jira     *JiraHandler
+	client   *http.Client
+}
+
+// NewIssueSubscriptionHandler creates a new issue subscription handler. Changes are
+// delivered to the channels in channels or to webhooks. store may be nil when
+// subscriptions are not supported (file-based storage).
+func NewIssueSubscriptionHandler(store storage.IssueSubscriptionStoreInterface, channels storage.NotificationChannelStoreInterface, jira *JiraHandler) *IssueSubscriptionHandler {
+	return &IssueSubscriptionHandler{
+		store:    store,
+		channels: channels,
+		jira:     jira,
+		client:   &http.Client{Timeout: subscriptionDeliveryTimeout},
+	}
+}
+
+// IsIssueSubscriptionTool reports whether a tool is served by the issue subscription handler
+func IsIssueSubscriptionTool(name string) bool {
+	return name == "subscribe_issue_updates" || name == "list_issue_subscriptions" || name == "unsubscribe_issue_updates"
+}
+
+// ListTools returns the issue subscription tools
+func (h *IssueSubscriptionHandler) ListTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "subscribe_issue_updates",
+			Description: "Get notified when Jira issues matching a JQL query are created or change. The query is checked every few minutes, and the changed issues are posted to one of your Slack or Microsoft Teams notification channels, or POSTed as JSON to a webhook.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspace_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Workspace ID (from list_workspaces)",
+					},
+					"jql": map[string]interface{}{
+						"type":        "string",
+						"description": "JQL selecting the issues to watch, without ORDER BY (e.g., 'project = PROJ AND priority = Highest')",
+					},
+					"channel": map[string]interface{}{
+						"type":        "string",
+						"description": "Name or ID of the notification channel to post to",
+					},
+					"webhook_url": map[string]interface{}{
+						"type":        "string",
+						"description": "URL to POST the changed issues to, instead of a channel",
+					},
+				},
+				"required": []string{"workspace_id", "jql"},
+			},
+		},
+		{
+			Name:        "list_issue_subscriptions",
+			Description: "List your Jira issue update subscriptions, with when each was last checked and its last delivery error",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			Name:        "unsubscribe_issue_updates",
+			Description: "Delete one of your Jira issue update subscriptions",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"subscription_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Subscription ID (from list_issue_subscriptions)",
+					},
+				},
+				"required": []string{"subscription_id"},
+			},
+		},
+	}
+}
+
+// HandleTool handles an issue subscription tool call
+func (h *IssueSubscriptionHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+	if h.store == nil {
+		return fail(errIssueSubscriptionsUnavailable.Error())
+	}
+
+	switch call.Name {
+	case "subscribe_issue_updates":
+		return h.subscribe(call, userID, requestID)
+	case "list_issue_subscriptions":
+		subscriptions, err := h.store.ListIssueSubscriptions(userID)
+		if err != nil {
+			return fail(fmt.Sprintf("failed to list subscriptions: %v", err))
+		}
+		for i := range subscriptions {
+			subscriptions[i].WebhookURL = maskWebhookURL(subscriptions[i].WebhookURL)
+		}
+		return jsonResult(map[string]interface{}{"subscriptions": subscriptions})
+	case "unsubscribe_issue_updates":
+		subscriptionID, _ := call.Arguments["subscription_id"].(string)
+		if subscriptionID == "" {
+			return fail("subscription_id is required")
+		}
+		err := h.store.DeleteIssueSubscription(userID, subscriptionID)
+		if errors.Is(err, storage.ErrNotFound) {
+			return fail(fmt.Sprintf("subscription not found: %s", subscriptionID))
+		}
+		if err != nil {
+			return fail(fmt.Sprintf("failed to delete subscription: %v", err))
+		}
+		return jsonResult(map[string]interface{}{"subscription_id": subscriptionID, "deleted": true})
+	default:
+		return fail(fmt.Sprintf("unknown tool: %s", call.Name))
+	}
+}
+
+// subscribe handles a subscribe_issue_updates call. The query is run once so that a
+// mistake in it, or a workspace the user cannot search, is reported straight away.
+func (h *IssueSubscriptionHandler) subscribe(call mcp.ToolCall, userID, requestID string) (mcp.ToolResult, error) {
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	workspaceID, _ := call.Arguments["workspace_id"].(string)
+	jql, _ := call.Arguments["jql"].(string)
+	jql = strings.TrimSpace(jql)
+	if workspaceID == "" || jql == "" {
+		return fail("workspace_id and jql are required")
+	}
+	if subscriptionOrderByPattern.MatchString(jql) {
+		return fail("jql must not have an ORDER BY clause; changes are delivered oldest first")
+	}
+	channelName, _ := call.Arguments["channel"].(string)
+	webhookURL, _ := call.Arguments["webhook_url"].(string)
+	if (channelName == "") == (webhookURL == "") {
+		return fail("exactly one of channel and webhook_url is required")
+	}
+
+	subscription := models.IssueSubscription{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		OrgID:       call.OrgID,
+		WorkspaceID: workspaceID,
+		JQL:         jql,
+		WebhookURL:  webhookURL,
+	}
+	if webhookURL != "" {
+		u, err := url.Parse(webhookURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fail("webhook_url must be an http or https URL")
+		}
+	} else {
+		if h.channels == nil {
+			return fail("notification channels require database storage (DATABASE_URL)")
+		}
+		channels, err := h.channels.ListNotificationChannels(userID)
+		if err != nil {
+			return fail(fmt.Sprintf("failed to list notification channels: %v", err))
+		}
+		channel, err := findNotificationChannel(channels, channelName)
+		if err != nil {
+			return fail(err.Error())
+		}
+		subscription.ChannelID = channel.ID
+	}
+
+	existing, err := h.store.ListIssueSubscriptions(userID)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to list subscriptions: %v", err))
+	}
+	if len(existing) >= maxIssueSubscriptions {
+		return fail(fmt.Sprintf("you already have %d subscriptions; unsubscribe from one first", maxIssueSubscriptions))
+	}
+
+	if _, err := h.searchUpdated(subscription, requestID, jql, 1); err != nil {
+		return fail(fmt.Sprintf("failed to run jql: %v", err))
+	}
+
+	now := time.Now().UTC()
+	subscription.CheckedThrough = now
+	subscription.NextCheckAt = now.Add(subscriptionCheckInterval)
+	if err := h.store.CreateIssueSubscription(&subscription); err != nil {
+		return fail(fmt.Sprintf("failed to save subscription: %v", err))
+	}
+	subscription.WebhookURL = maskWebhookURL(subscription.WebhookURL)
+	return jsonResult(subscription)
+}
+
+// Start checks the due subscriptions every minute for the life of the process. Replicas
+// share the subscriptions table, and each check is claimed by exactly one of them.
+func (h *IssueSubscriptionHandler) Start() {
+	if h.store == nil {
+		slog.Info("issue subscriptions disabled; they require database storage (DATABASE_URL)")
+		return
+	}
+
+	go func() {
+		h.checkDue(time.Now())
+		for now := range time.Tick(subscriptionTickInterval) {
+			h.checkDue(now)
+		}
+	}()
+}
+
+// checkDue starts the checks of the subscriptions that are due
+func (h *IssueSubscriptionHandler) checkDue(now time.Time) {
+	subscriptions, err := h.store.DueIssueSubscriptions(now)
+	if err != nil {
+		slog.Warn("failed to list due issue subscriptions", "error", err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		claimed, err := h.store.ClaimIssueSubscriptionCheck(subscription.ID, subscription.NextCheckAt, now.Add(subscriptionCheckInterval))
+		if err != nil {
+			slog.Warn("failed to claim issue subscription check", "subscription_id", subscription.ID, "error", err)
+			continue
+		}
+		if claimed {
+			go h.check(subscription, now.UTC())
+		}
+	}
+}
+
+// check delivers the issues that changed since the subscription was last checked. A
+// failed check is retried from the same point, so no change is missed.
+func (h *IssueSubscriptionHandler) check(subscription models.IssueSubscription, now time.Time) {
+	requestID := "subscription_" + uuid.New().String()
+	err := h.deliverChanges(subscription, requestID, now)
+	checkedThrough, lastError := now, ""
+	if err != nil {
+		checkedThrough, lastError = subscription.CheckedThrough, err.Error()
+		slog.Warn("issue subscription check failed", "subscription_id", subscription.ID, "request_id", requestID, "error", err)
+	}
+	if err := h.store.FinishIssueSubscriptionCheck(subscription.ID, checkedThrough, lastError); err != nil {
+		slog.Warn("failed to record issue subscription check", "subscription_id", subscription.ID, "error", err)
+	}
+}
+
+// subscriptionIssue is a changed issue in a delivery
+type subscriptionIssue struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	Status  string `json:"status,omitempty"`
+	Updated string `json:"updated"`
+	URL     string `json:"url,omitempty"`
+}
+
+// deliverChanges finds the issues updated after the subscription's last check, up to now,
+// and delivers them
+func (h *IssueSubscriptionHandler) deliverChanges(subscription models.IssueSubscription, requestID string, now time.Time) error {
+	// Relative dates avoid any difference between the server's and the Jira user's time
+	// zones; the extra minutes cover the query's minute resolution
+	minutes := int(now.Sub(subscription.CheckedThrough).Minutes()) + 2
+	jql := fmt.Sprintf("(%s) AND updated >= -%dm ORDER BY updated ASC", subscription.JQL, minutes)
+	issues, err := h.searchUpdated(subscription, requestID, jql, maxSubscriptionIssues+1)
+	if err != nil {
+		return err
+	}
+
+	var changed []subscriptionIssue
+	for _, issue := range issues {
+		updated, _ := issue.Fields["updated"].(string)
+		at, err := time.Parse("2006-01-02T15:04:05.000-0700", updated)
+		if err != nil || !at.After(subscription.CheckedThrough) || at.After(now) {
+			continue
+		}
+		summary, _ := issue.Fields["summary"].(string)
+		changed = append(changed, subscriptionIssue{
+			Key:     issue.Key,
+			Summary: summary,
+			Status:  issueStatusName(issue),
+			Updated: updated,
+			URL:     issueBrowseURL(issue),
+		})
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	truncated := len(changed) > maxSubscriptionIssues
+	if truncated {
+		changed = changed[:maxSubscriptionIssues]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), subscriptionDeliveryTimeout)
+	defer cancel()
+	if subscription.WebhookURL != "" {
+		return h.postChanges(ctx, subscription, changed, truncated, now)
+	}
+	return h.notifyChanges(ctx, subscription, changed, truncated)
+}
+
+// searchUpdated runs a query in the subscription's workspace as its owner. The Jira
+// service applies the workspace's policy, as for any other search.
+func (h *IssueSubscriptionHandler) searchUpdated(subscription models.IssueSubscription, requestID, jql string, limit int) ([]models.JiraIssue, error) {
+	resp, err := h.jira.callService(models.JiraRequest{
+		Action:      "list_issues",
+		WorkspaceID: subscription.WorkspaceID,
+		UserID:      subscription.UserID,
+		OrgID:       subscription.OrgID,
+		Params: map[string]interface{}{
+			"jql":    jql,
+			"limit":  float64(limit),
+			"fields": []interface{}{"summary", "status", "updated"},
+		},
+		RequestID: requestID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, serviceError(resp.Error)
+	}
+
+	var search models.SearchResponse
+	if err := decodeServiceData(resp.Data, &search); err != nil {
+		return nil, fmt.Errorf("unexpected Jira search response: %w", err)
+	}
+	return search.Issues, nil
+}
+
+// notifyChanges posts the changed issues to the subscription's notification channel
+func (h *IssueSubscriptionHandler) notifyChanges(ctx context.Context, subscription models.IssueSubscription, changed []subscriptionIssue, truncated bool) error {
+	if h.channels == nil {
+		return errors.New("notification channels require database storage (DATABASE_URL)")
+	}
+	channels, err := h.channels.ListNotificationChannels(subscription.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to list notification channels: %w", err)
+	}
+	channel, err := findNotificationChannel(channels, subscription.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	lines := make([]string, 0, len(changed)+1)
+	for _, issue := range changed {
+		line := issue.Key + ": " + issue.Summary
+		if issue.Status != "" {
+			line += " (" + issue.Status + ")"
+		}
+		lines = append(lines, line)
+	}
+	if truncated {
+		lines = append(lines, fmt.Sprintf("Only the first %d changed issues are listed.", maxSubscriptionIssues))
+	}
+
+	title := fmt.Sprintf("%d Jira issues changed", len(changed))
+	if len(changed) == 1 {
+		title = "1 Jira issue changed"
+	}
+	msg := notify.Message{
+		Title:  title,
+		Text:   strings.Join(lines, "\n"),
+		Level:  notify.LevelInfo,
+		Fields: []notify.Field{{Name: "Query", Value: subscription.JQL}},
+		URL:    changed[0].URL,
+	}
+	return notify.Send(ctx, channel, msg)
+}
+
+// postChanges POSTs the changed issues as JSON to the subscription's webhook
+func (h *IssueSubscriptionHandler) postChanges(ctx context.Context, subscription models.IssueSubscription, changed []subscriptionIssue, truncated bool, now time.Time) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"subscription_id": subscription.ID,
+		"workspace_id":    subscription.WorkspaceID,
+		"jql":             subscription.JQL,
+		"checked_at":      now,
+		"truncated":       truncated,
+		"issues":          changed,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Trilix-Subscriptions")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("webhook returned " + resp.Status)
+	}
+	return nil
+}
+
+// issueStatusName returns the name of an issue's status, if the field was fetched
+func issueStatusName(issue models.JiraIssue) string {
+	status, _ := issue.Fields["status"].(map[string]interface{})
+	name, _ := status["name"].(string)
+	return name
+}
+
+// maskWebhookURL hides the secret part of a webhook URL, keeping its host
+func maskWebhookURL(webhookURL string) string {
+	if webhookURL == "" {
+		return ""
+	}
+	if u, err := url.Parse(webhookURL); err == nil && u.Host != "" {
+		return u.Scheme + "://" + u.Host + "/…"
+	}
+	return "…"
+}
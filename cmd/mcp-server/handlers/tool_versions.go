@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// jiraListIssuesV2 is jira_list_issues with cursor pagination: it returns a page of issues
+// and an opaque cursor instead of Jira's raw search response
+var jiraListIssuesV2 = mcp.VersionedName("jira_list_issues", "v2")
+
+// deprecatedTools indexes the deprecated tools among tools by name
+func deprecatedTools(tools []mcp.Tool) map[string]mcp.Tool {
+	deprecated := make(map[string]mcp.Tool)
+	for _, tool := range tools {
+		if tool.Deprecated != "" {
+			deprecated[tool.Name] = tool
+		}
+	}
+	return deprecated
+}
+
+// issuePage is a jira_list_issues@v2 result
+type issuePage struct {
+	Issues     json.RawMessage `json:"issues"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// listIssuesCursorParams passes jira_list_issues@v2's cursor to the Jira service as the
+// search page token it stands for
+func listIssuesCursorParams(arguments map[string]interface{}) map[string]interface{} {
+	params := make(map[string]interface{}, len(arguments))
+	for k, v := range arguments {
+		params[k] = v
+	}
+	delete(params, "next_page_token")
+	if cursor, _ := params["cursor"].(string); cursor != "" {
+		params["next_page_token"] = cursor
+	}
+	delete(params, "cursor")
+	return params
+}
+
+// listIssuesPage turns a list_issues result, full or summarized, into a page. Jira's token
+// search has no offsets or reliable total, so only the next page's token is kept.
+func listIssuesPage(data interface{}) (issuePage, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return issuePage{}, err
+	}
+	var results struct {
+		Issues        json.RawMessage `json:"issues"`
+		NextPageToken string          `json:"nextPageToken"`   // Full issues
+		SummaryToken  string          `json:"next_page_token"` // summarize
+	}
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return issuePage{}, fmt.Errorf("unexpected search results: %w", err)
+	}
+
+	page := issuePage{Issues: results.Issues, NextCursor: results.NextPageToken}
+	if page.NextCursor == "" {
+		page.NextCursor = results.SummaryToken
+	}
+	if len(page.Issues) == 0 || string(page.Issues) == "null" {
+		page.Issues = json.RawMessage("[]")
+	}
+	return page, nil
+}
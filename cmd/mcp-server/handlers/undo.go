@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/bus"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// UndoHandler keeps a log of each user's reversible changes, which the services describe
+// with the request that reverts them, and serves undo_last_action
+type UndoHandler struct {
+	store storage.UndoStoreInterface
+	ttl   time.Duration
+
+	// The service callers inside the wrappers, so that undoing is not itself logged
+	jira       func(models.JiraRequest) (*models.JiraResponse, error)
+	confluence func(models.ConfluenceRequest) (*models.ConfluenceResponse, error)
+}
+
+// NewUndoHandler creates an undo handler whose changes can be undone for ttl. store may be
+// nil when changes are not logged.
+func NewUndoHandler(store storage.UndoStoreInterface, ttl time.Duration) *UndoHandler {
+	return &UndoHandler{store: store, ttl: ttl}
+}
+
+// IsUndoTool reports whether a tool reverts logged changes
+func IsUndoTool(name string) bool {
+	return name == "undo_last_action"
+}
+
+// WrapJira logs the reversible changes of Jira calls
+func (h *UndoHandler) WrapJira(callService func(models.JiraRequest) (*models.JiraResponse, error)) func(models.JiraRequest) (*models.JiraResponse, error) {
+	h.jira = callService
+	return func(req models.JiraRequest) (*models.JiraResponse, error) {
+		resp, err := callService(req)
+		if err == nil && resp.Success && resp.Undo != nil {
+			h.record(bus.JiraService, req.UserID, req.OrgID, req.WorkspaceID, "jira_"+req.Action, resp.Undo)
+		}
+		return resp, err
+	}
+}
+
+// WrapConfluence logs the reversible changes of Confluence calls
+func (h *UndoHandler) WrapConfluence(callService func(models.ConfluenceRequest) (*models.ConfluenceResponse, error)) func(models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+	h.confluence = callService
+	return func(req models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+		resp, err := callService(req)
+		if err == nil && resp.Success && resp.Undo != nil {
+			h.record(bus.ConfluenceService, req.UserID, req.OrgID, req.WorkspaceID, "confluence_"+req.Action, resp.Undo)
+		}
+		return resp, err
+	}
+}
+
+func (h *UndoHandler) record(product, userID, orgID, workspaceID, tool string, undo *models.UndoAction) {
+	if h.store == nil || userID == "" {
+		return
+	}
+	entry := &models.UndoEntry{
+		UserID:      userID,
+		OrgID:       orgID,
+		Product:     product,
+		WorkspaceID: workspaceID,
+		Tool:        tool,
+		Undo:        *undo,
+	}
+	if err := h.store.PushUndo(entry, h.ttl); err != nil {
+		slog.Warn("failed to record undo entry", "tool", tool, "user_id", userID, "error", err)
+	}
+}
+
+// ListTools returns the undo tool
+func (h *UndoHandler) ListTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name: "undo_last_action",
+			Description: "Undo your most recent reversible change: a Confluence page edit or deletion, or a Jira issue field or description update. " +
+				"Each call undoes one change, newest first; changes can be undone for a limited time.",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+	}
+}
+
+// HandleTool reverts the caller's most recent logged change
+func (h *UndoHandler) HandleTool(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+	requestID := requestIDFor(call)
+	fail := func(message string) (mcp.ToolResult, error) {
+		return errorResult(requestID, message), errors.New(message)
+	}
+
+	if !IsUndoTool(call.Name) {
+		return fail(fmt.Sprintf("unknown tool: %s", call.Name))
+	}
+	if h.store == nil || h.jira == nil || h.confluence == nil {
+		return fail("undo is not available on this server")
+	}
+
+	entry, err := h.store.LatestUndo(userID, h.ttl)
+	if err == storage.ErrNotFound {
+		return fail("there is no recent change to undo")
+	}
+	if err != nil {
+		return fail(fmt.Sprintf("failed to read the undo log: %v", err))
+	}
+
+	// Undoing is a change made with the same tool's permissions
+	caller := &auth.UserContext{UserID: userID, OrgID: call.OrgID, Scopes: call.Scopes}
+	if err := caller.CheckToolScope(entry.Tool); err != nil {
+		return fail(err.Error())
+	}
+
+	var success bool
+	var errInfo *models.ErrorInfo
+	switch entry.Product {
+	case bus.JiraService:
+		resp, err := h.jira(models.JiraRequest{
+			Action:      entry.Undo.Action,
+			WorkspaceID: entry.WorkspaceID,
+			UserID:      userID,
+			OrgID:       entry.OrgID,
+			Params:      entry.Undo.Params,
+			RequestID:   requestID,
+		})
+		if err != nil {
+			return fail(err.Error())
+		}
+		success, errInfo = resp.Success, resp.Error
+	case bus.ConfluenceService:
+		resp, err := h.confluence(models.ConfluenceRequest{
+			Action:      entry.Undo.Action,
+			WorkspaceID: entry.WorkspaceID,
+			UserID:      userID,
+			OrgID:       entry.OrgID,
+			Params:      entry.Undo.Params,
+			RequestID:   requestID,
+		})
+		if err != nil {
+			return fail(err.Error())
+		}
+		success, errInfo = resp.Success, resp.Error
+	default:
+		return fail(fmt.Sprintf("cannot undo changes of %s", entry.Product))
+	}
+
+	// A change that Atlassian refuses to revert (e.g. the page was edited again since) is
+	// dropped, so the one before it can be undone next
+	if err := h.store.DeleteUndo(userID, entry.ID); err != nil {
+		slog.Warn("failed to delete undo entry", "id", entry.ID, "user_id", userID, "error", err)
+	}
+	if !success {
+		return fail(fmt.Sprintf("could not %s: %v (the change was removed from the undo log)", entry.Undo.Description, serviceError(errInfo)))
+	}
+
+	return jsonResult(map[string]interface{}{
+		"undone":       entry.Undo.Description,
+		"tool":         entry.Tool,
+		"workspace_id": entry.WorkspaceID,
+		"changed_at":   entry.CreatedAt,
+	})
+}
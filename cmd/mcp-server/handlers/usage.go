@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/notify"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+)
+
+// quotaWarningThreshold is the share of a daily quota at which users are warned
+const quotaWarningThreshold = 0.8
+
+// UsageHandler meters tool calls, enforces daily quotas and serves usage statistics
+type UsageHandler struct {
+	usageStore storage.UsageStoreInterface
+	quota      models.UsageQuota
+	mu         sync.RWMutex     // Guards quota, which can change on configuration reload
+	notifier   *notify.Notifier // Sends quota warnings (nil = none)
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(usageStore storage.UsageStoreInterface, quota models.UsageQuota) *UsageHandler {
+	return &UsageHandler{
+		usageStore: usageStore,
+		quota:      quota,
+	}
+}
+
+// WithNotifier warns users on their notification channels as they approach their quotas
+func (h *UsageHandler) WithNotifier(notifier *notify.Notifier) *UsageHandler {
+	h.notifier = notifier
+	return h
+}
+
+// SetQuota replaces the daily quotas
+func (h *UsageHandler) SetQuota(quota models.UsageQuota) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.quota = quota
+}
+
+// currentQuota returns the daily quotas in effect
+func (h *UsageHandler) currentQuota() models.UsageQuota {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.quota
+}
+
+// UsageResponse is returned by GET /api/usage
+type UsageResponse struct {
+	Quota models.UsageQuota    `json:"quota"`
+	Today UsageTotals          `json:"today"`
+	Daily []models.UsageRecord `json:"daily"`
+}
+
+// UsageTotals sums usage across workspaces
+type UsageTotals struct {
+	ToolCalls int64 `json:"tool_calls"`
+	APIBytes  int64 `json:"api_bytes"`
+}
+
+// WrapJira meters calls to the Jira service and rejects them once the user's quota is used up
+func (h *UsageHandler) WrapJira(callService func(models.JiraRequest) (*models.JiraResponse, error)) func(models.JiraRequest) (*models.JiraResponse, error) {
+	return func(req models.JiraRequest) (*models.JiraResponse, error) {
+		if errInfo := h.checkQuota(req.UserID); errInfo != nil {
+			return &models.JiraResponse{Success: false, Error: errInfo, RequestID: req.RequestID}, nil
+		}
+		resp, err := callService(req)
+		if err == nil {
+			h.record(req.UserID, req.WorkspaceID, resp.Usage)
+		}
+		return resp, err
+	}
+}
+
+// WrapConfluence meters calls to the Confluence service and rejects them once the user's quota is used up
+func (h *UsageHandler) WrapConfluence(callService func(models.ConfluenceRequest) (*models.ConfluenceResponse, error)) func(models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+	return func(req models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+		if errInfo := h.checkQuota(req.UserID); errInfo != nil {
+			return &models.ConfluenceResponse{Success: false, Error: errInfo, RequestID: req.RequestID}, nil
+		}
+		resp, err := callService(req)
+		if err == nil {
+			h.record(req.UserID, req.WorkspaceID, resp.Usage)
+		}
+		return resp, err
+	}
+}
+
+// checkQuota returns a QUOTA_EXCEEDED error when the user has reached today's limits
+func (h *UsageHandler) checkQuota(userID string) *models.ErrorInfo {
+	quota := h.currentQuota()
+	if quota.DailyToolCalls == 0 && quota.DailyAPIBytes == 0 {
+		return nil
+	}
+
+	today, err := h.today(userID)
+	if err != nil {
+		// Don't block tool calls because metering is unavailable
+		slog.Warn("failed to check usage quota", "user_id", userID, "error", err)
+		return nil
+	}
+	h.warnQuota(userID, quota, today)
+
+	if quota.DailyToolCalls > 0 && today.ToolCalls >= quota.DailyToolCalls {
+		return &models.ErrorInfo{
+			Code:    models.ErrCodeQuotaExceeded,
+			Message: fmt.Sprintf("daily tool call quota of %d exceeded; resets at 00:00 UTC", quota.DailyToolCalls),
+			Details: today,
+		}
+	}
+	if quota.DailyAPIBytes > 0 && today.APIBytes >= quota.DailyAPIBytes {
+		return &models.ErrorInfo{
+			Code:    models.ErrCodeQuotaExceeded,
+			Message: fmt.Sprintf("daily Atlassian API quota of %d bytes exceeded; resets at 00:00 UTC", quota.DailyAPIBytes),
+			Details: today,
+		}
+	}
+	return nil
+}
+
+// warnQuota notifies the user once a day when a quota is nearly used up, and again once it is used up
+func (h *UsageHandler) warnQuota(userID string, quota models.UsageQuota, today UsageTotals) {
+	if h.notifier == nil {
+		return
+	}
+	day := time.Now().UTC().Format("2006-01-02")
+	warn := func(name string, used, limit int64, unit string) {
+		if limit <= 0 || float64(used) < quotaWarningThreshold*float64(limit) {
+			return
+		}
+		level, state := notify.LevelWarning, "nearly used up"
+		if used >= limit {
+			level, state = notify.LevelError, "used up"
+		}
+		key := fmt.Sprintf("quota:%s:%s:%s:%s", userID, name, state, day)
+		h.notifier.NotifyOnce(key, 24*time.Hour, userID, notify.EventQuotaWarning, notify.Message{
+			Title: fmt.Sprintf("Daily %s quota %s", name, state),
+			Text:  fmt.Sprintf("You have used %d of your %d %s today. Tool calls fail once the quota is reached; it resets at 00:00 UTC.", used, limit, unit),
+			Level: level,
+		})
+	}
+	warn("tool call", today.ToolCalls, quota.DailyToolCalls, "tool calls")
+	warn("Atlassian API", today.APIBytes, quota.DailyAPIBytes, "bytes of Atlassian API traffic")
+}
+
+// record adds a completed tool call to the user's usage
+func (h *UsageHandler) record(userID, workspaceID string, usage *models.UsageInfo) {
+	var apiBytes int64
+	if usage != nil {
+		apiBytes = usage.APIBytes
+	}
+	if err := h.usageStore.RecordUsage(userID, workspaceID, 1, apiBytes); err != nil {
+		slog.Warn("failed to record usage", "user_id", userID, "workspace_id", workspaceID, "error", err)
+	}
+}
+
+// today sums the user's usage for the current UTC day
+func (h *UsageHandler) today(userID string) (UsageTotals, error) {
+	var totals UsageTotals
+	records, err := h.usageStore.ListUsage(userID, time.Now())
+	if err != nil {
+		return totals, err
+	}
+	for _, rec := range records {
+		totals.ToolCalls += rec.ToolCalls
+		totals.APIBytes += rec.APIBytes
+	}
+	return totals, nil
+}
+
+// HandleGetUsage handles GET /api/usage?days=30
+func (h *UsageHandler) HandleGetUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract user from context
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	days := 30
+	if v := r.URL.Query().Get("days"); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 1 || d > 365 {
+			http.Error(w, "days must be between 1 and 365", http.StatusBadRequest)
+			return
+		}
+		days = d
+	}
+
+	daily, err := h.usageStore.ListUsage(userCtx.UserID, time.Now().AddDate(0, 0, -(days-1)))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load usage: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := UsageResponse{
+		Quota: h.currentQuota(),
+		Daily: daily,
+	}
+	todayStart := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, rec := range daily {
+		if !rec.Day.Before(todayStart) {
+			response.Today.ToolCalls += rec.ToolCalls
+			response.Today.APIBytes += rec.APIBytes
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
+)
+
+// WatchToolCall reports a tool call that is still running once its budget (the tool's
+// timeout) has passed, in the log and in trilix_tool_call_overruns_total. The returned
+// function must be called when the call ends; it logs how long an overrunning call took.
+func WatchToolCall(tool, workspaceID, requestID string, budget time.Duration) (stop func()) {
+	start := time.Now()
+	watchdog := time.AfterFunc(budget, func() {
+		metrics.ToolCallOverruns.Inc(tool)
+		slog.Warn("tool call exceeded its time budget", "tool", tool, "workspace_id", workspaceID,
+			"budget", budget.String(), "request_id", requestID)
+	})
+	return func() {
+		if !watchdog.Stop() {
+			slog.Warn("tool call finished after its time budget", "tool", tool, "workspace_id", workspaceID,
+				"budget", budget.String(), "duration", time.Since(start).String(), "request_id", requestID)
+		}
+	}
+}
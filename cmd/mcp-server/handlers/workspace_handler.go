@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/crypto"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
 )
@@ -17,6 +20,7 @@ import (
 type WorkspaceHandler struct {
 	credStore storage.CredentialStoreInterface
 	validator *atlassian.Validator
+	health    *WorkspaceHealth // Records status checks (nil = none)
 }
 
 // NewWorkspaceHandler creates a new workspace handler
@@ -27,22 +31,144 @@ func NewWorkspaceHandler(credStore storage.CredentialStoreInterface) *WorkspaceH
 	}
 }
 
+// writeStore is the store a request's changes are written through: administrators may
+// also change shared workspaces
+func (h *WorkspaceHandler) writeStore(r *http.Request) storage.CredentialStoreInterface {
+	if auth.IsAdmin(r.Context()) {
+		return storage.AsAdmin(h.credStore)
+	}
+	return h.credStore
+}
+
+// WithHealth records the outcome of status checks, so tool calls for workspaces whose
+// credentials were rejected fail fast, and pass again once a check succeeds
+func (h *WorkspaceHandler) WithHealth(health *WorkspaceHealth) *WorkspaceHandler {
+	h.health = health
+	return h
+}
+
 // CreateWorkspaceRequest represents the request to create a workspace
 type CreateWorkspaceRequest struct {
-	WorkspaceName string `json:"workspaceName"`
-	SiteURL       string `json:"siteUrl"`
-	Email         string `json:"email"`
-	APIToken      string `json:"apiToken"`
+	WorkspaceName string                  `json:"workspaceName"`
+	SiteURL       string                  `json:"siteUrl"`
+	Email         string                  `json:"email"`
+	APIToken      string                  `json:"apiToken"`
+	Policy        *models.WorkspacePolicy `json:"policy,omitempty"`
+	Shared        bool                    `json:"shared,omitempty"` // Share with the caller's organization (org admins only)
+
+	// OpsgenieAPIKey is used by the opsgenie_* tools instead of Jira Service Management
+	// Operations. On update, omitting it keeps the current key and "" removes it.
+	OpsgenieAPIKey *string `json:"opsgenieApiKey,omitempty"`
+
+	// JiraURL and ConfluenceURL point a product at its own server when it does not live at
+	// siteUrl, e.g. Jira Cloud with Confluence Data Center. As with the Opsgenie key,
+	// omitting one on update keeps it and "" removes it.
+	JiraURL       *string `json:"jiraUrl,omitempty"`
+	ConfluenceURL *string `json:"confluenceUrl,omitempty"`
+
+	// ProxyURL sends the workspace's Atlassian calls through a proxy instead of the one in
+	// HTTPS_PROXY. It is stored encrypted and never returned; omitting it on update keeps
+	// it and "" removes it.
+	ProxyURL *string `json:"proxyUrl,omitempty"`
+}
+
+// validationURL is the URL the workspace's token is checked against: Jira's, which
+// ValidateToken falls back from to Confluence on the same site
+func (req *CreateWorkspaceRequest) validationURL() string {
+	if req.JiraURL != nil && *req.JiraURL != "" {
+		return *req.JiraURL
+	}
+	return req.SiteURL
+}
+
+// proxyURL returns the workspace's proxy, empty for none
+func (req *CreateWorkspaceRequest) proxyURL() string {
+	if req.ProxyURL == nil {
+		return ""
+	}
+	return *req.ProxyURL
+}
+
+// validatorFor returns a validator that reaches Atlassian through the workspace's proxy
+func (h *WorkspaceHandler) validatorFor(proxyURL string) *atlassian.Validator {
+	if proxyURL == "" {
+		return h.validator
+	}
+	return atlassian.NewValidatorWithProxy(proxyURL)
 }
 
 // WorkspaceResponse represents a workspace without sensitive data
 type WorkspaceResponse struct {
-	WorkspaceID   string    `json:"workspaceId"`
-	WorkspaceName string    `json:"workspaceName"`
-	SiteURL       string    `json:"siteUrl"`
-	Email         string    `json:"email"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+	WorkspaceID   string                  `json:"workspaceId"`
+	WorkspaceName string                  `json:"workspaceName"`
+	SiteURL       string                  `json:"siteUrl"`
+	JiraURL       string                  `json:"jiraUrl,omitempty"`
+	ConfluenceURL string                  `json:"confluenceUrl,omitempty"`
+	Email         string                  `json:"email"`
+	Policy        *models.WorkspacePolicy `json:"policy,omitempty"`
+	Shared        bool                    `json:"shared,omitempty"` // Owned by the caller's organization
+	CreatedAt     time.Time               `json:"createdAt"`
+	UpdatedAt     time.Time               `json:"updatedAt"`
+	DeletedAt     *time.Time              `json:"deletedAt,omitempty"`
+}
+
+// errNotOrgAdmin is returned when an organization member tries to change a shared workspace
+var errNotOrgAdmin = errors.New("only organization admins can manage shared workspaces")
+
+// resolveOwner returns the ID a workspace is stored under: the user for their own
+// workspaces, or their organization for team-shared ones. deleted selects soft-deleted
+// workspaces; manage requires the org admin role for shared workspaces.
+func (h *WorkspaceHandler) resolveOwner(userCtx *auth.UserContext, workspaceID string, deleted, manage bool) (string, error) {
+	if found, err := h.ownsWorkspace(userCtx.UserID, workspaceID, deleted); err != nil || found {
+		return userCtx.UserID, err
+	}
+	if userCtx.InOrg() {
+		found, err := h.ownsWorkspace(userCtx.OrgID, workspaceID, deleted)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			if manage && !userCtx.IsOrgAdmin() {
+				return "", errNotOrgAdmin
+			}
+			return userCtx.OrgID, nil
+		}
+	}
+	return "", storage.ErrNotFound
+}
+
+// ownsWorkspace reports whether a workspace is stored under the given user or organization
+func (h *WorkspaceHandler) ownsWorkspace(ownerID, workspaceID string, deleted bool) (bool, error) {
+	if !deleted {
+		_, err := h.credStore.GetCredentials(ownerID, workspaceID)
+		if err == storage.ErrNotFound {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	workspaces, err := h.credStore.ListDeletedWorkspaces(ownerID)
+	if err != nil {
+		return false, err
+	}
+	for _, ws := range workspaces {
+		if ws.WorkspaceID == workspaceID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// writeOwnerError maps a resolveOwner error to an HTTP response
+func writeOwnerError(w http.ResponseWriter, err error, notFound string) {
+	switch err {
+	case storage.ErrNotFound:
+		http.Error(w, notFound, http.StatusNotFound)
+	case errNotOrgAdmin:
+		http.Error(w, fmt.Sprintf("Forbidden: %v", err), http.StatusForbidden)
+	default:
+		http.Error(w, fmt.Sprintf("Failed to look up workspace: %v", err), http.StatusInternalServerError)
+	}
 }
 
 // HandleCreateWorkspace handles POST /api/workspaces
@@ -76,8 +202,37 @@ func (h *WorkspaceHandler) HandleCreateWorkspace(w http.ResponseWriter, r *http.
 		req.WorkspaceName = req.SiteURL
 	}
 
+	// Shared workspaces are stored under the organization and managed by its admins
+	ownerID := userCtx.UserID
+	if req.Shared {
+		if !userCtx.InOrg() {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": "Shared workspaces require an active organization",
+			})
+			return
+		}
+		if !userCtx.IsOrgAdmin() {
+			http.Error(w, fmt.Sprintf("Forbidden: %v", errNotOrgAdmin), http.StatusForbidden)
+			return
+		}
+		ownerID = userCtx.OrgID
+	}
+
+	if proxy := req.proxyURL(); proxy != "" {
+		if _, err := atlassian.ParseProxyURL(proxy); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
 	// Validate Atlassian token
-	if err := h.validator.ValidateToken(req.SiteURL, req.Email, req.APIToken); err != nil {
+	if err := h.validatorFor(req.proxyURL()).ValidateToken(req.validationURL(), req.Email, req.APIToken); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -91,18 +246,29 @@ func (h *WorkspaceHandler) HandleCreateWorkspace(w http.ResponseWriter, r *http.
 
 	// Create credential object
 	cred := &models.AtlassianCredential{
-		UserID:        userCtx.UserID,
+		UserID:        ownerID,
 		WorkspaceID:   workspaceID,
 		WorkspaceName: req.WorkspaceName,
 		AtlassianURL:  req.SiteURL,
 		Email:         req.Email,
 		APIToken:      req.APIToken,
+		Policy:        req.Policy,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
+	if req.OpsgenieAPIKey != nil {
+		cred.OpsgenieKey = *req.OpsgenieAPIKey
+	}
+	cred.ProxyURL = req.proxyURL()
+	if req.JiraURL != nil {
+		cred.JiraURL = *req.JiraURL
+	}
+	if req.ConfluenceURL != nil {
+		cred.ConfluenceURL = *req.ConfluenceURL
+	}
 
 	// Save credentials
-	if err := h.credStore.SaveCredentials(cred); err != nil {
+	if err := h.writeStore(r).SaveCredentials(cred); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -116,7 +282,11 @@ func (h *WorkspaceHandler) HandleCreateWorkspace(w http.ResponseWriter, r *http.
 		WorkspaceID:   workspaceID,
 		WorkspaceName: req.WorkspaceName,
 		SiteURL:       req.SiteURL,
+		JiraURL:       cred.JiraURL,
+		ConfluenceURL: cred.ConfluenceURL,
 		Email:         req.Email,
+		Policy:        cred.Policy,
+		Shared:        req.Shared,
 		CreatedAt:     cred.CreatedAt,
 		UpdatedAt:     cred.UpdatedAt,
 	}
@@ -127,6 +297,9 @@ func (h *WorkspaceHandler) HandleCreateWorkspace(w http.ResponseWriter, r *http.
 }
 
 // HandleListWorkspaces handles GET /api/workspaces
+// The caller's own workspaces are followed by those shared with their organization.
+// With ?include_deleted=true, soft-deleted workspaces that can still be restored are included
+// (for shared workspaces, only for org admins).
 func (h *WorkspaceHandler) HandleListWorkspaces(w http.ResponseWriter, r *http.Request) {
 	// Extract user from context
 	userCtx, ok := auth.ExtractUserFromContext(r.Context())
@@ -142,6 +315,32 @@ func (h *WorkspaceHandler) HandleListWorkspaces(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	if r.URL.Query().Get("include_deleted") == "true" {
+		deleted, err := h.credStore.ListDeletedWorkspaces(userCtx.UserID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to list deleted workspaces: %v", err), http.StatusInternalServerError)
+			return
+		}
+		workspaces = append(workspaces, deleted...)
+	}
+
+	if userCtx.InOrg() {
+		shared, err := h.credStore.ListWorkspaces(userCtx.OrgID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to list shared workspaces: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if r.URL.Query().Get("include_deleted") == "true" && userCtx.IsOrgAdmin() {
+			deleted, err := h.credStore.ListDeletedWorkspaces(userCtx.OrgID)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Failed to list deleted shared workspaces: %v", err), http.StatusInternalServerError)
+				return
+			}
+			shared = append(shared, deleted...)
+		}
+		workspaces = append(workspaces, shared...)
+	}
+
 	// Convert to response format (without tokens)
 	responses := []WorkspaceResponse{}
 	for _, ws := range workspaces {
@@ -149,9 +348,14 @@ func (h *WorkspaceHandler) HandleListWorkspaces(w http.ResponseWriter, r *http.R
 			WorkspaceID:   ws.WorkspaceID,
 			WorkspaceName: ws.WorkspaceName,
 			SiteURL:       ws.AtlassianURL,
+			JiraURL:       ws.JiraURL,
+			ConfluenceURL: ws.ConfluenceURL,
 			Email:         ws.Email,
+			Policy:        ws.Policy,
+			Shared:        userCtx.InOrg() && ws.UserID == userCtx.OrgID,
 			CreatedAt:     ws.CreatedAt,
 			UpdatedAt:     ws.UpdatedAt,
+			DeletedAt:     ws.DeletedAt,
 		})
 	}
 
@@ -160,6 +364,8 @@ func (h *WorkspaceHandler) HandleListWorkspaces(w http.ResponseWriter, r *http.R
 }
 
 // HandleDeleteWorkspace handles DELETE /api/workspaces/:id
+// Workspaces are soft-deleted and can be restored until they are purged. Shared
+// workspaces can only be deleted by org admins.
 func (h *WorkspaceHandler) HandleDeleteWorkspace(w http.ResponseWriter, r *http.Request) {
 	// Extract user from context
 	userCtx, ok := auth.ExtractUserFromContext(r.Context())
@@ -176,8 +382,14 @@ func (h *WorkspaceHandler) HandleDeleteWorkspace(w http.ResponseWriter, r *http.
 		return
 	}
 
+	ownerID, err := h.resolveOwner(userCtx, workspaceID, false, true)
+	if err != nil {
+		writeOwnerError(w, err, "Workspace not found")
+		return
+	}
+
 	// Delete credentials
-	if err := h.credStore.DeleteCredentials(userCtx.UserID, workspaceID); err != nil {
+	if err := h.writeStore(r).DeleteCredentials(ownerID, workspaceID); err != nil {
 		if err == storage.ErrNotFound {
 			http.Error(w, "Workspace not found", http.StatusNotFound)
 			return
@@ -189,6 +401,42 @@ func (h *WorkspaceHandler) HandleDeleteWorkspace(w http.ResponseWriter, r *http.
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// HandleRestoreWorkspace handles POST /api/workspaces/:id/restore
+func (h *WorkspaceHandler) HandleRestoreWorkspace(w http.ResponseWriter, r *http.Request) {
+	// Extract user from context
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Extract workspace ID from URL path
+	// Expected format: /api/workspaces/{id}/restore
+	path := r.URL.Path[len("/api/workspaces/"):]
+	workspaceID := path[:len(path)-len("/restore")]
+	if workspaceID == "" {
+		http.Error(w, "Missing workspace ID", http.StatusBadRequest)
+		return
+	}
+
+	ownerID, err := h.resolveOwner(userCtx, workspaceID, true, true)
+	if err != nil {
+		writeOwnerError(w, err, "Deleted workspace not found")
+		return
+	}
+
+	if err := h.writeStore(r).RestoreCredentials(ownerID, workspaceID); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Deleted workspace not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to restore workspace: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // HandleUpdateWorkspace handles PUT /api/workspaces/:id
 func (h *WorkspaceHandler) HandleUpdateWorkspace(w http.ResponseWriter, r *http.Request) {
 	// Extract user from context
@@ -221,8 +469,18 @@ func (h *WorkspaceHandler) HandleUpdateWorkspace(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Shared workspaces stay with the organization and can only be changed by its admins
+	ownerID, err := h.resolveOwner(userCtx, workspaceID, false, true)
+	if err == errNotOrgAdmin {
+		http.Error(w, fmt.Sprintf("Forbidden: %v", err), http.StatusForbidden)
+		return
+	}
+
 	// Get existing credentials to preserve token if not updated
-	existingCreds, err := h.credStore.GetCredentials(userCtx.UserID, workspaceID)
+	var existingCreds *models.WorkspaceCredentials
+	if err == nil {
+		existingCreds, err = h.credStore.GetCredentials(ownerID, workspaceID)
+	}
 	if err != nil {
 		if err == storage.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
@@ -245,6 +503,25 @@ func (h *WorkspaceHandler) HandleUpdateWorkspace(w http.ResponseWriter, r *http.
 		req.APIToken = existingCreds.Token
 	}
 
+	// Keep the existing policy unless a new one is supplied
+	if req.Policy == nil {
+		req.Policy = existingCreds.Policy
+	}
+
+	// Likewise the Opsgenie key
+	if req.OpsgenieAPIKey == nil {
+		req.OpsgenieAPIKey = &existingCreds.OpsgenieKey
+	}
+	if req.JiraURL == nil {
+		req.JiraURL = &existingCreds.JiraURL
+	}
+	if req.ConfluenceURL == nil {
+		req.ConfluenceURL = &existingCreds.ConfluenceURL
+	}
+	if req.ProxyURL == nil {
+		req.ProxyURL = &existingCreds.ProxyURL
+	}
+
 	// Validate required fields (after potential token fill)
 	if req.SiteURL == "" || req.Email == "" || req.APIToken == "" {
 		w.Header().Set("Content-Type", "application/json")
@@ -260,8 +537,19 @@ func (h *WorkspaceHandler) HandleUpdateWorkspace(w http.ResponseWriter, r *http.
 		req.WorkspaceName = req.SiteURL
 	}
 
+	if proxy := req.proxyURL(); proxy != "" {
+		if _, err := atlassian.ParseProxyURL(proxy); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
 	// Validate Atlassian token
-	if err := h.validator.ValidateToken(req.SiteURL, req.Email, req.APIToken); err != nil {
+	if err := h.validatorFor(req.proxyURL()).ValidateToken(req.validationURL(), req.Email, req.APIToken); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -272,18 +560,23 @@ func (h *WorkspaceHandler) HandleUpdateWorkspace(w http.ResponseWriter, r *http.
 
 	// Create updated credential object
 	cred := &models.AtlassianCredential{
-		UserID:        userCtx.UserID,
+		UserID:        ownerID,
 		WorkspaceID:   workspaceID, // Keep original ID
 		WorkspaceName: req.WorkspaceName,
 		AtlassianURL:  req.SiteURL,
 		Email:         req.Email,
 		APIToken:      req.APIToken,
+		Policy:        req.Policy,
+		OpsgenieKey:   *req.OpsgenieAPIKey,
+		ProxyURL:      *req.ProxyURL,
+		JiraURL:       *req.JiraURL,
+		ConfluenceURL: *req.ConfluenceURL,
 		CreatedAt:     time.Now(), // Preserving original 'CreatedAt' would require fetching full model, but 'GetCredentials' only returns minimal. Updating both for now or just UpdatedAt.
 		UpdatedAt:     time.Now(),
 	}
 
 	// Save credentials (overwrite)
-	if err := h.credStore.SaveCredentials(cred); err != nil {
+	if err := h.writeStore(r).SaveCredentials(cred); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -297,7 +590,11 @@ func (h *WorkspaceHandler) HandleUpdateWorkspace(w http.ResponseWriter, r *http.
 		WorkspaceID:   workspaceID,
 		WorkspaceName: req.WorkspaceName,
 		SiteURL:       req.SiteURL,
+		JiraURL:       cred.JiraURL,
+		ConfluenceURL: cred.ConfluenceURL,
 		Email:         req.Email,
+		Policy:        cred.Policy,
+		Shared:        ownerID != userCtx.UserID,
 		CreatedAt:     cred.CreatedAt,
 		UpdatedAt:     cred.UpdatedAt,
 	}
@@ -325,8 +622,8 @@ func (h *WorkspaceHandler) HandleWorkspaceStatus(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Get credentials
-	creds, err := h.credStore.GetCredentials(userCtx.UserID, workspaceID)
+	// Get credentials (own or shared with the user's organization)
+	creds, err := storage.GetMemberCredentials(h.credStore, userCtx.UserID, userCtx.OrgID, workspaceID)
 	if err != nil {
 		if err == storage.ErrNotFound {
 			http.Error(w, "Workspace not found", http.StatusNotFound)
@@ -337,7 +634,10 @@ func (h *WorkspaceHandler) HandleWorkspaceStatus(w http.ResponseWriter, r *http.
 	}
 
 	// Test connection
-	err = h.validator.ValidateToken(creds.Site, creds.Email, creds.Token)
+	err = h.validatorFor(creds.ProxyURL).ValidateToken(creds.JiraSite(), creds.Email, creds.Token)
+	if h.health != nil {
+		h.health.RecordValidation(userCtx.UserID, workspaceID, err)
+	}
 	
 	status := map[string]interface{}{
 		"workspaceId": workspaceID,
@@ -351,3 +651,247 @@ func (h *WorkspaceHandler) HandleWorkspaceStatus(w http.ResponseWriter, r *http.
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(status)
 }
+
+// ImportResult summarizes the outcome of a bulk workspace import
+type ImportResult struct {
+	Imported []WorkspaceResponse `json:"imported"`
+	Failed   []ImportFailure     `json:"failed"`
+}
+
+// ImportFailure describes a single workspace entry that could not be imported
+type ImportFailure struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// HandleImportWorkspaces handles POST /api/workspaces/import
+// The body is a JSON array in the same shape as workspaces.json. Entries carrying
+// apiTokenEncrypted are decrypted with the key supplied in the X-Workspace-Key header.
+// Only service calls may provision workspaces for other users via the owner field, and
+// only for users on the service token's allowlist.
+func (h *WorkspaceHandler) HandleImportWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	caller, _ := auth.ServiceCallerFromContext(r.Context())
+
+	var entries []storage.WorkspaceConfig
+	if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": fmt.Sprintf("Invalid request body: %v", err),
+		})
+		return
+	}
+
+	transferKey := r.Header.Get("X-Workspace-Key")
+	validate := r.URL.Query().Get("validate") == "true"
+
+	result := ImportResult{
+		Imported: []WorkspaceResponse{},
+		Failed:   []ImportFailure{},
+	}
+	for i, entry := range entries {
+		if caller != nil && entry.Owner != "" && entry.Owner != userCtx.UserID {
+			auth.AuditImpersonation(r, caller, entry.Owner, caller.CanImpersonate(entry.Owner))
+		}
+		cred, err := h.credentialFromImport(entry, userCtx.UserID, caller, transferKey, validate)
+		if err == nil {
+			err = h.writeStore(r).SaveCredentials(cred)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, ImportFailure{Index: i, Name: entry.Name, Error: err.Error()})
+			continue
+		}
+		result.Imported = append(result.Imported, WorkspaceResponse{
+			WorkspaceID:   cred.WorkspaceID,
+			WorkspaceName: cred.WorkspaceName,
+			SiteURL:       cred.AtlassianURL,
+			JiraURL:       cred.JiraURL,
+			ConfluenceURL: cred.ConfluenceURL,
+			Email:         cred.Email,
+			Policy:        cred.Policy,
+			CreatedAt:     cred.CreatedAt,
+			UpdatedAt:     cred.UpdatedAt,
+		})
+	}
+
+	slog.InfoContext(r.Context(), "workspace import", "user_id", userCtx.UserID, "imported", len(result.Imported), "failed", len(result.Failed))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// credentialFromImport converts an imported entry into a credential ready to be saved
+func (h *WorkspaceHandler) credentialFromImport(entry storage.WorkspaceConfig, callerID string, service *auth.ServiceCaller, transferKey string, validate bool) (*models.AtlassianCredential, error) {
+	userID := callerID
+	if entry.Owner != "" && entry.Owner != callerID {
+		if service == nil {
+			return nil, fmt.Errorf("owner may only be set by service calls")
+		}
+		if !service.CanImpersonate(entry.Owner) {
+			return nil, fmt.Errorf("service token %q may not provision workspaces for %s", service.Name, entry.Owner)
+		}
+		userID = entry.Owner
+	}
+
+	token := entry.APIToken
+	if token == "" && entry.APITokenEncrypted != "" {
+		if transferKey == "" {
+			return nil, fmt.Errorf("apiTokenEncrypted requires the X-Workspace-Key header")
+		}
+		decrypted, err := crypto.Decrypt(entry.APITokenEncrypted, transferKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt apiTokenEncrypted: %v", err)
+		}
+		token = decrypted
+	}
+
+	if entry.BaseURL == "" || entry.Email == "" || token == "" {
+		return nil, fmt.Errorf("missing required fields: baseUrl, email, apiToken")
+	}
+
+	opsgenieKey := entry.OpsgenieAPIKey
+	if opsgenieKey == "" && entry.OpsgenieAPIKeyEncrypted != "" {
+		if transferKey == "" {
+			return nil, fmt.Errorf("opsgenieApiKeyEncrypted requires the X-Workspace-Key header")
+		}
+		decrypted, err := crypto.Decrypt(entry.OpsgenieAPIKeyEncrypted, transferKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt opsgenieApiKeyEncrypted: %v", err)
+		}
+		opsgenieKey = decrypted
+	}
+
+	proxyURL := entry.ProxyURL
+	if proxyURL == "" && entry.ProxyURLEncrypted != "" {
+		if transferKey == "" {
+			return nil, fmt.Errorf("proxyUrlEncrypted requires the X-Workspace-Key header")
+		}
+		decrypted, err := crypto.Decrypt(entry.ProxyURLEncrypted, transferKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt proxyUrlEncrypted: %v", err)
+		}
+		proxyURL = decrypted
+	}
+	if proxyURL != "" {
+		if _, err := atlassian.ParseProxyURL(proxyURL); err != nil {
+			return nil, err
+		}
+	}
+
+	if validate {
+		site := entry.BaseURL
+		if entry.JiraURL != "" {
+			site = entry.JiraURL
+		}
+		if err := h.validatorFor(proxyURL).ValidateToken(site, entry.Email, token); err != nil {
+			return nil, fmt.Errorf("Atlassian Connection Failed: %v", err)
+		}
+	}
+
+	workspaceID := entry.ID
+	if workspaceID == "" {
+		workspaceID = uuid.New().String()
+	} else if storage.ForeignWorkspace(h.credStore, userID, workspaceID) {
+		return nil, fmt.Errorf("workspace ID %s belongs to a shared or another user's workspace; omit id to import it as a new workspace", workspaceID)
+	}
+	name := entry.Name
+	if name == "" {
+		name = entry.BaseURL
+	}
+
+	return &models.AtlassianCredential{
+		UserID:        userID,
+		WorkspaceID:   workspaceID,
+		WorkspaceName: name,
+		AtlassianURL:  entry.BaseURL,
+		JiraURL:       entry.JiraURL,
+		ConfluenceURL: entry.ConfluenceURL,
+		Email:         entry.Email,
+		APIToken:      token,
+		Policy:        entry.Policy,
+		OpsgenieKey:   opsgenieKey,
+		ProxyURL:      proxyURL,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}, nil
+}
+
+// HandleExportWorkspaces handles GET /api/workspaces/export
+// Tokens are omitted unless an X-Workspace-Key header is supplied, in which case each
+// token is re-encrypted with that key and returned as apiTokenEncrypted (Opsgenie keys as opsgenieApiKeyEncrypted,
+// proxy URLs as proxyUrlEncrypted).
+func (h *WorkspaceHandler) HandleExportWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userCtx, ok := auth.ExtractUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	workspaces, err := h.credStore.ListWorkspaces(userCtx.UserID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list workspaces: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	transferKey := r.Header.Get("X-Workspace-Key")
+
+	entries := []storage.WorkspaceConfig{}
+	for _, ws := range workspaces {
+		entry := storage.WorkspaceConfig{
+			ID:      ws.WorkspaceID,
+			Owner:   userCtx.UserID,
+			Name:    ws.WorkspaceName,
+			BaseURL: ws.AtlassianURL,
+			Email:   ws.Email,
+			Policy:  ws.Policy,
+
+			JiraURL:       ws.JiraURL,
+			ConfluenceURL: ws.ConfluenceURL,
+		}
+
+		if transferKey != "" {
+			// ListWorkspaces does not return tokens for every store, so fetch them explicitly
+			creds, err := h.credStore.GetCredentials(userCtx.UserID, ws.WorkspaceID)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Failed to read workspace %s: %v", ws.WorkspaceID, err), http.StatusInternalServerError)
+				return
+			}
+			encrypted, err := crypto.Encrypt(creds.Token, transferKey)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Failed to encrypt token: %v", err), http.StatusInternalServerError)
+				return
+			}
+			entry.APITokenEncrypted = encrypted
+
+			if creds.OpsgenieKey != "" {
+				encrypted, err := crypto.Encrypt(creds.OpsgenieKey, transferKey)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Failed to encrypt Opsgenie key: %v", err), http.StatusInternalServerError)
+					return
+				}
+				entry.OpsgenieAPIKeyEncrypted = encrypted
+			}
+
+			if creds.ProxyURL != "" {
+				encrypted, err := crypto.Encrypt(creds.ProxyURL, transferKey)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Failed to encrypt proxy URL: %v", err), http.StatusInternalServerError)
+					return
+				}
+				entry.ProxyURLEncrypted = encrypted
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="workspaces.json"`)
+	json.NewEncoder(w).Encode(entries)
+}
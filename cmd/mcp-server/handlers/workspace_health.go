@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// workspaceFailureTTL is how long calls for a workspace whose credentials were rejected fail
+// without reaching Atlassian. The next call after it tries Atlassian again.
+const workspaceFailureTTL = 15 * time.Minute
+
+// WorkspaceHealth remembers the workspaces whose last validation failed, i.e. whose
+// credentials Atlassian rejected in a tool call or a status check. Further calls for them
+// fail at once with an error saying where to re-authenticate, instead of a raw 401. A
+// workspace is healthy again once its credentials change or a status check passes.
+type WorkspaceHealth struct {
+	mu       sync.Mutex
+	failures map[string]workspaceFailure // Indexed by user ID and workspace ID
+}
+
+// workspaceFailure is a workspace's last failed validation
+type workspaceFailure struct {
+	err       string
+	checkedAt time.Time
+}
+
+// NewWorkspaceHealth creates an empty workspace health record
+func NewWorkspaceHealth() *WorkspaceHealth {
+	return &WorkspaceHealth{failures: make(map[string]workspaceFailure)}
+}
+
+func workspaceHealthKey(userID, workspaceID string) string {
+	return userID + ":" + workspaceID
+}
+
+// workspaceManagementURL is where users replace a workspace's credentials, absolute when
+// MCP_PUBLIC_URL is set
+func workspaceManagementURL() string {
+	return strings.TrimSuffix(os.Getenv("MCP_PUBLIC_URL"), "/") + "/workspaces.html"
+}
+
+// RecordValidation stores the outcome of validating a workspace's credentials for a user
+// (see atlassian.Validator). Failures other than rejected credentials, such as an
+// unreachable site, leave the record as it was.
+func (h *WorkspaceHealth) RecordValidation(userID, workspaceID string, err error) {
+	if err == nil {
+		h.mu.Lock()
+		delete(h.failures, workspaceHealthKey(userID, workspaceID))
+		h.mu.Unlock()
+		return
+	}
+	if strings.HasPrefix(err.Error(), "invalid credentials") {
+		h.fail(userID, workspaceID, err.Error())
+	}
+}
+
+// fail records that Atlassian rejected a workspace's credentials for a user
+func (h *WorkspaceHealth) fail(userID, workspaceID, reason string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures[workspaceHealthKey(userID, workspaceID)] = workspaceFailure{err: reason, checkedAt: time.Now()}
+}
+
+// Forget drops the failures recorded for a workspace, for every user, e.g. when its
+// credentials change
+func (h *WorkspaceHealth) Forget(workspaceID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key := range h.failures {
+		if strings.HasSuffix(key, ":"+workspaceID) {
+			delete(h.failures, key)
+		}
+	}
+}
+
+// Failure returns why a workspace's last validation failed, or "" when it did not or
+// the failure is older than workspaceFailureTTL
+func (h *WorkspaceHealth) Failure(userID, workspaceID string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := workspaceHealthKey(userID, workspaceID)
+	failure, ok := h.failures[key]
+	if !ok {
+		return ""
+	}
+	if time.Since(failure.checkedAt) > workspaceFailureTTL {
+		delete(h.failures, key)
+		return ""
+	}
+	return failure.err
+}
+
+// disconnectedError is the error of a call for a workspace whose credentials were rejected
+func disconnectedError(workspaceID, reason string) *models.ErrorInfo {
+	return &models.ErrorInfo{
+		Code:    models.ErrCodeAuthFailed,
+		Message: fmt.Sprintf("workspace %s is disconnected: Atlassian rejected its credentials (%s). Please re-authenticate at %s", workspaceID, reason, workspaceManagementURL()),
+		Suggestions: []string{
+			fmt.Sprintf("Replace the workspace's API token at %s, or add it again with connect_workspace", workspaceManagementURL()),
+			"Check the workspace with workspace_status once its credentials are replaced",
+		},
+	}
+}
+
+// check returns the error of a call for a disconnected workspace, nil for a healthy one
+func (h *WorkspaceHealth) check(userID, workspaceID string) *models.ErrorInfo {
+	if workspaceID == "" {
+		return nil
+	}
+	if reason := h.Failure(userID, workspaceID); reason != "" {
+		return disconnectedError(workspaceID, reason)
+	}
+	return nil
+}
+
+// observe records a call whose workspace credentials the site rejected, and returns the
+// error to report instead of the raw 401. Other 401s, e.g. from Opsgenie, Bitbucket or for
+// a caller's own OAuth token, are reported as they are.
+func (h *WorkspaceHealth) observe(userID, workspaceID string, info *models.ErrorInfo) *models.ErrorInfo {
+	if workspaceID == "" || info == nil || !info.CredentialsRejected {
+		return info
+	}
+	reason := fmt.Sprintf("HTTP %d from Atlassian", http.StatusUnauthorized)
+	h.fail(userID, workspaceID, reason)
+	return disconnectedError(workspaceID, reason)
+}
+
+// WrapJira fails calls for disconnected workspaces without calling the Jira service
+func (h *WorkspaceHealth) WrapJira(callService func(models.JiraRequest) (*models.JiraResponse, error)) func(models.JiraRequest) (*models.JiraResponse, error) {
+	return func(req models.JiraRequest) (*models.JiraResponse, error) {
+		if errInfo := h.check(req.UserID, req.WorkspaceID); errInfo != nil {
+			return &models.JiraResponse{Success: false, Error: errInfo, RequestID: req.RequestID}, nil
+		}
+		resp, err := callService(req)
+		if err == nil && !resp.Success {
+			resp.Error = h.observe(req.UserID, req.WorkspaceID, resp.Error)
+		}
+		return resp, err
+	}
+}
+
+// WrapConfluence fails calls for disconnected workspaces without calling the Confluence service
+func (h *WorkspaceHealth) WrapConfluence(callService func(models.ConfluenceRequest) (*models.ConfluenceResponse, error)) func(models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+	return func(req models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+		if errInfo := h.check(req.UserID, req.WorkspaceID); errInfo != nil {
+			return &models.ConfluenceResponse{Success: false, Error: errInfo, RequestID: req.RequestID}, nil
+		}
+		resp, err := callService(req)
+		if err == nil && !resp.Success {
+			resp.Error = h.observe(req.UserID, req.WorkspaceID, resp.Error)
+		}
+		return resp, err
+	}
+}
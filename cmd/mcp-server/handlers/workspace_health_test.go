@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	jiraservice "github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/handlers"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+)
+
+// TestWorkspaceHealthIgnoresOtherServices checks that only the site rejecting the
+// workspace's credentials disconnects the workspace, not a 401 from Opsgenie or Bitbucket
+func TestWorkspaceHealthIgnoresOtherServices(t *testing.T) {
+	siteRejects := false
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if siteRejects {
+			http.Error(w, `{"errorMessages":["Unauthorized"]}`, http.StatusUnauthorized)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/rest/api/3/issue/") {
+			json.NewEncoder(w).Encode(map[string]interface{}{"key": "PROJ-1", "fields": map[string]interface{}{"summary": "Login fails"}})
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer site.Close()
+	opsgenie := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Key format is not valid!"}`, http.StatusUnauthorized)
+	}))
+	defer opsgenie.Close()
+	t.Setenv("OPSGENIE_API_URL", opsgenie.URL)
+
+	path := filepath.Join(t.TempDir(), "workspaces.json")
+	workspaces, _ := json.Marshal([]storage.WorkspaceConfig{{
+		ID:             "ws1",
+		Owner:          "user1",
+		Name:           "ws1",
+		BaseURL:        site.URL,
+		Email:          "bot@example.com",
+		APIToken:       "token",
+		OpsgenieAPIKey: "bad-key",
+	}})
+	if err := os.WriteFile(path, workspaces, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := storage.NewFileCredentialStore(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jira := jiraservice.NewService(store, 5*time.Second)
+
+	health := NewWorkspaceHealth()
+	call := health.WrapJira(func(req models.JiraRequest) (*models.JiraResponse, error) {
+		body, _ := json.Marshal(req)
+		var resp models.JiraResponse
+		err := json.Unmarshal(jira.HandleMessage(body, req.RequestID), &resp)
+		return &resp, err
+	})
+	request := func(action string, params map[string]interface{}) *models.JiraResponse {
+		t.Helper()
+		resp, err := call(models.JiraRequest{Action: action, UserID: "user1", WorkspaceID: "ws1", Params: params, RequestID: action})
+		if err != nil {
+			t.Fatalf("%s: %v", action, err)
+		}
+		return resp
+	}
+
+	if resp := request("opsgenie_list_alerts", map[string]interface{}{}); resp.Success {
+		t.Fatal("opsgenie_list_alerts succeeded with a rejected Opsgenie key")
+	} else if resp.Error.CredentialsRejected {
+		t.Fatalf("an Opsgenie 401 was reported as rejected workspace credentials: %+v", resp.Error)
+	}
+	if resp := request("get_issue", map[string]interface{}{"issue_key": "PROJ-1"}); !resp.Success {
+		t.Fatalf("get_issue failed after an Opsgenie 401: %+v", resp.Error)
+	}
+
+	siteRejects = true
+	if resp := request("get_issue", map[string]interface{}{"issue_key": "PROJ-1"}); resp.Success || !strings.Contains(resp.Error.Message, "is disconnected") {
+		t.Fatalf("a 401 from the site did not disconnect the workspace: %+v", resp.Error)
+	}
+	siteRejects = false
+	if resp := request("get_issue", map[string]interface{}{"issue_key": "PROJ-1"}); resp.Success || !strings.Contains(resp.Error.Message, "is disconnected") {
+		t.Fatalf("get_issue was not gated after the site rejected the credentials: %+v", resp.Error)
+	}
+}
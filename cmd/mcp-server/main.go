@@ -3,80 +3,162 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"strings"
 	"time"
 
 	"context"
+	"errors"
+	confluenceservice "github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/handlers"
+	jiraservice "github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/handlers"
 	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/grpcapi"
 	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/handlers"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/broker"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/bus"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/events"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/health"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
 	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
 	"github.com/providentiaww/twistygo"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"os"
 	"os/signal"
 	"path/filepath"
-	"strconv"
 	"syscall"
-
-	"gopkg.in/yaml.v3"
 )
 
 const ServiceVersion = "v1.0.0"
 
 var rconn *twistygo.AmqpConnection_t
 
-type AppConfig struct {
-	Common struct {
-		App struct {
-			Port       int    `yaml:"port"`
-			RPCTimeout string `yaml:"rpc_timeout"`
-		} `yaml:"app"`
-	} `yaml:"common"`
-}
-
 func init() {
 	config.LoadEnv("../../.env")
+	logging.Setup("mcp-server")
 }
 
 func main() {
 	// Initialize TwistyGo logging
 	twistygo.LogStartService("MCPServer", ServiceVersion)
 
-	// Initialize RabbitMQ with retries
+	// Load config.yaml and the environment, refusing to start on invalid values.
+	// Timeouts, quotas, the tool allowlist and the response limit reload on change.
+	settings, err := config.NewSettingsWatcher("config.yaml")
+	if err != nil {
+		panic(fmt.Sprintf("❌ %v", err))
+	}
+	settings.Start()
+
+	// Passthrough tools operators define in tools.yaml (TOOLS_FILE); the services load
+	// the same file to serve them. Changes take effect on restart.
+	customTools, err := config.ToolDefinitionsFromEnv()
+	if err != nil {
+		panic(fmt.Sprintf("❌ %v", err))
+	}
+
+	// The Atlassian OAuth app users connect their accounts to, for user OAuth workspaces
+	oauthApp, err := atlassian.OAuthConfigFromEnv()
+	if err != nil {
+		panic(fmt.Sprintf("❌ %v", err))
+	}
+
+	// Trust the operator's CA bundle for Atlassian calls (validation, MESSAGE_BUS=memory).
+	// A changed bundle takes effect on restart.
+	if path := settings.Current().AtlassianCABundle; path != "" {
+		if err := atlassian.SetCABundle(path); err != nil {
+			panic(fmt.Sprintf("❌ %v", err))
+		}
+	}
+
+	// Select the transport to the Jira and Confluence services
+	busKind, err := bus.KindFromEnv()
+	if err != nil {
+		panic(fmt.Sprintf("❌ %v", err))
+	}
+
+	// Initialize the message bus with retries
 	maxRetries := 5
-	var err error
-	for i := 0; i < maxRetries; i++ {
-		// Capture panic from twistygo.AmqpConnect if it fails
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					err = fmt.Errorf("panic: %v", r)
+	var requester bus.Requester
+	var memoryBus *bus.Memory
+	busCheck, busStatus := "", func() (bool, error) { return true, nil }
+	switch busKind {
+	case bus.KindAMQP:
+		for i := 0; i < maxRetries; i++ {
+			// Capture panic from twistygo.AmqpConnect if it fails
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("panic: %v", r)
+					}
+				}()
+				rconn = twistygo.AmqpConnect()
+				if rconn != nil {
+					err = nil
 				}
 			}()
-			rconn = twistygo.AmqpConnect()
-			if rconn != nil {
-				err = nil
+
+			if err == nil && rconn != nil {
+				break
 			}
-		}()
 
-		if err == nil && rconn != nil {
-			break
+			if i < maxRetries-1 {
+				slog.Warn("failed to connect to RabbitMQ, retrying in 5s", "attempt", i+1, "max_attempts", maxRetries, "error", err)
+				time.Sleep(5 * time.Second)
+			}
 		}
 
-		if i < maxRetries-1 {
-			fmt.Printf("⚠️ Failed to connect to RabbitMQ (attempt %d/%d): %v. Retrying in 5s...\n", i+1, maxRetries, err)
-			time.Sleep(5 * time.Second)
+		if rconn == nil {
+			panic(fmt.Sprintf("❌ Failed to connect to RabbitMQ after %d attempts: %v", maxRetries, err))
+		}
+		rconn.AmqpLoadQueues("ConfluenceRequests", "JiraRequests")
+		requester = bus.NewAMQP(rconn)
+		busCheck = "rabbitmq"
+		// A passive declare of the Jira request queue is a round trip on the publishing channel
+		busStatus = func() (bool, error) {
+			sq := rconn.AmqpConnectQueue("JiraRequests")
+			if sq == nil || sq.Amqp == nil {
+				return false, errors.New("not connected")
+			}
+			if err := broker.Ping(sq.Amqp.Channel, queueName("JiraRequests")); err != nil {
+				return false, err
+			}
+			return true, nil
 		}
-	}
 
-	if rconn == nil {
-		panic(fmt.Sprintf("❌ Failed to connect to RabbitMQ after %d attempts: %v", maxRetries, err))
+	case bus.KindNATS:
+		var natsBus *bus.NATS
+		for i := 0; i < maxRetries; i++ {
+			if natsBus, err = bus.NewNATS(bus.NATSURLFromEnv(), "mcp-server"); err == nil {
+				break
+			}
+			if i < maxRetries-1 {
+				slog.Warn("failed to connect to NATS, retrying in 5s", "attempt", i+1, "max_attempts", maxRetries, "error", err)
+				time.Sleep(5 * time.Second)
+			}
+		}
+		if err != nil {
+			panic(fmt.Sprintf("❌ Failed to connect to NATS after %d attempts: %v", maxRetries, err))
+		}
+		defer natsBus.Close()
+		requester = natsBus
+		busCheck, busStatus = "nats", natsBus.Status
+
+	case bus.KindMemory:
+		// The Jira and Confluence handlers run in this process once the credential store is ready
+		memoryBus = bus.NewMemory()
+		requester = memoryBus
 	}
-	rconn.AmqpLoadQueues("ConfluenceRequests", "JiraRequests")
+	slog.Info("message bus selected", "bus", busKind)
 
 	// Initialize credential store (file-based or database) with retries for K8s resilience
 	var credStore storage.CredentialStoreInterface
@@ -86,7 +168,7 @@ func main() {
 			break
 		}
 		if i < maxRetries-1 {
-			fmt.Printf("⚠️ Failed to initialize credential store (attempt %d/%d): %v. Retrying in 5s...\n", i+1, maxRetries, err)
+			slog.Warn("failed to initialize credential store, retrying in 5s", "attempt", i+1, "max_attempts", maxRetries, "error", err)
 			time.Sleep(5 * time.Second)
 		}
 	}
@@ -95,55 +177,163 @@ func main() {
 	}
 	defer credStore.Close()
 
-	// Initialize Clerk authentication
-	clerkAuth := auth.NewClerkAuth()
-	if clerkAuth == nil {
-		fmt.Println("Warning: Clerk authentication not configured (CLERK_SECRET_KEY not set)")
-		fmt.Println("Running in development mode without authentication")
+	// Write-through credential cache; changes made through the HTTP API are broadcast
+	// on the CredentialEvents exchange so the services drop their cached copies
+	cachedStore := storage.NewCachedCredentialStore(credStore, storage.CredentialCacheTTLFromEnv())
+	// Without RabbitMQ there is no event channel and other replicas rely on cache TTLs
+	var eventChannel *amqp.Channel
+	if rconn != nil {
+		if sq := rconn.AmqpConnectQueue("JiraRequests"); sq != nil && sq.Amqp != nil {
+			eventChannel = sq.Amqp.Channel
+		}
 	}
+	// Calls for workspaces whose credentials Atlassian rejected fail fast until they change
+	workspaceHealth := handlers.NewWorkspaceHealth()
+	cachedStore.OnChange(func(eventType, userID, workspaceID string) {
+		workspaceHealth.Forget(workspaceID)
+		err := events.PublishCredentialEvent(eventChannel, events.CredentialEvent{
+			Type:        eventType,
+			UserID:      userID,
+			WorkspaceID: workspaceID,
+		})
+		if err != nil {
+			slog.Warn("failed to publish credential event", "workspace_id", workspaceID, "error", err)
+		}
+	})
 
-	// Load custom config
-	var appConfig AppConfig
-	if configData, err := os.ReadFile("config.yaml"); err == nil {
-		yaml.Unmarshal(configData, &appConfig)
+	// With MESSAGE_BUS=memory the services share this process and its credential cache
+	if memoryBus != nil {
+		idempotencyStore := storage.NewIdempotencyStoreFromEnv(credStore)
+		storage.StartIdempotencyPurgeLoop(idempotencyStore)
+		jiraService := jiraservice.NewService(cachedStore, settings.Current().AtlassianTimeout).
+			WithIdempotency(idempotencyStore, storage.IdempotencyTTLFromEnv()).
+			WithRedaction(settings.Current().Redaction).
+			WithCustomTools(customTools).
+			WithUserTokens(atlassian.NewUserTokens(storage.NewUserTokenStoreFromEnv(credStore), oauthApp))
+		memoryBus.Serve(bus.JiraService, func(body []byte, headers bus.Headers) []byte {
+			return jiraService.HandleMessage(body, headers[logging.RequestIDHeader])
+		})
+		confluenceService := confluenceservice.NewService(cachedStore, settings.Current().AtlassianTimeout).
+			WithIdempotency(idempotencyStore, storage.IdempotencyTTLFromEnv()).
+			WithRedaction(settings.Current().Redaction).
+			WithCustomTools(customTools).
+			WithUserTokens(atlassian.NewUserTokens(storage.NewUserTokenStoreFromEnv(credStore), oauthApp))
+		memoryBus.Serve(bus.ConfluenceService, func(body []byte, headers bus.Headers) []byte {
+			return confluenceService.HandleMessage(body, headers[logging.RequestIDHeader])
+		})
 	}
 
-	port := appConfig.Common.App.Port
-	if port == 0 {
-		port = 3000
-	}
+	// Soft-deleted workspaces stay restorable for DELETED_WORKSPACE_RETENTION_DAYS
+	storage.StartPurgeLoop(cachedStore, storage.DeletedWorkspaceRetentionFromEnv())
 
-	// Prioritize MCP_SERVER_PORT from ConfigMap, fallback to PORT
-	portSource := "config.yaml"
-	if pStr := os.Getenv("MCP_SERVER_PORT"); pStr != "" {
-		if p, err := strconv.Atoi(pStr); err == nil {
-			port = p
-			portSource = "MCP_SERVER_PORT"
-		}
-	} else if pStr := os.Getenv("PORT"); pStr != "" {
-		if p, err := strconv.Atoi(pStr); err == nil {
-			port = p
-			portSource = "PORT"
+	// Key and token lookups are cached briefly; revocations are broadcast on the
+	// RevocationEvents exchange so every replica stops accepting them at once
+	tokenCache := storage.NewTokenCache(storage.TokenCacheTTLFromEnv())
+	tokenCache.OnRevoke(func(kind, id string) {
+		err := events.PublishRevocationEvent(eventChannel, events.RevocationEvent{Kind: kind, ID: id})
+		if err != nil {
+			slog.Warn("failed to publish revocation event", "kind", kind, "id", id, "error", err)
 		}
+	})
+
+	// Long-lived API keys are stored in Postgres; nil (disabled) with file storage
+	apiKeyStore := tokenCache.WrapAPIKeys(storage.NewAPIKeyStoreFromEnv(credStore))
+
+	// Per-integration service tokens with impersonation allowlists (Postgres only)
+	serviceTokenStore := tokenCache.WrapServiceTokens(storage.NewServiceTokenStoreFromEnv(credStore))
+	if os.Getenv("MCP_SERVICE_TOKEN") != "" && os.Getenv("MCP_SERVICE_TOKEN_ALLOWED_USERS") == "" {
+		slog.Warn("MCP_SERVICE_TOKEN can act as any user; set MCP_SERVICE_TOKEN_ALLOWED_USERS or use service tokens")
 	}
-	fmt.Printf("ℹ️ Server using port %d (source: %s)\n", port, portSource)
 
-	rpcTimeout := 35 * time.Second
-	if appConfig.Common.App.RPCTimeout != "" {
-		if d, err := time.ParseDuration(appConfig.Common.App.RPCTimeout); err == nil {
-			rpcTimeout = d
-		}
+	// Initialize authentication (Clerk, or a generic OIDC provider via AUTH_PROVIDER/OIDC_ISSUER)
+	identity, err := auth.NewIdentityProviderFromEnv()
+	if err != nil {
+		panic(fmt.Sprintf("❌ Failed to initialize identity provider: %v", err))
+	}
+	if identity == nil {
+		slog.Warn("authentication not configured (neither CLERK_SECRET_KEY nor OIDC_ISSUER set); running in development mode without authentication")
+	} else {
+		slog.Info("authenticating users", "provider", identity.Name())
 	}
 
-	// Create service callers with configurable timeout
-	confluenceCaller := createConfluenceCaller(rpcTimeout)
-	jiraCaller := createJiraCaller(rpcTimeout)
+	port := settings.Current().Port
+	slog.Info("server port configured", "port", port)
+
+	// Usage metering lives next to the credentials (Postgres, or in memory for file storage)
+	usageStore, err := storage.NewUsageStoreFromEnv(credStore)
+	if err != nil {
+		panic(fmt.Sprintf("❌ Failed to initialize usage store: %v", err))
+	}
+	// Slack and Teams notification channels (Postgres only) hear about rejected
+	// credentials, schedule results and quota warnings
+	notificationHandler := handlers.NewNotificationHandler(storage.NewNotificationChannelStoreFromEnv(credStore))
+
+	usageHandler := handlers.NewUsageHandler(usageStore, settings.Current().UsageQuota).
+		WithNotifier(notificationHandler.Notifier())
+	settings.OnReload(func(s *config.Settings) { usageHandler.SetQuota(s.UsageQuota) })
+
+	// Jira searches may name a field preset from config.yaml or one the user saved (Postgres only)
+	fieldPresetHandler := handlers.NewFieldPresetHandler(storage.NewFieldPresetStoreFromEnv(credStore), settings.Current().FieldPresets)
+	settings.OnReload(func(s *config.Settings) { fieldPresetHandler.SetServerPresets(s.FieldPresets) })
+
+	// Read tools listed under tool_cache_ttls are answered from memory while fresh;
+	// cached answers are not metered
+	resultCache := handlers.NewResultCache(settings)
+
+	// Reversible changes are logged per user (Postgres, or in memory for file storage)
+	// for undo_last_action
+	undoHandler := handlers.NewUndoHandler(storage.NewUndoStoreFromEnv(credStore), storage.UndoTTLFromEnv())
+
+	// Create service callers with configurable timeout, metered against daily quotas
+	confluenceCaller := workspaceHealth.WrapConfluence(undoHandler.WrapConfluence(resultCache.WrapConfluence(notificationHandler.WrapConfluence(usageHandler.WrapConfluence(createConfluenceCaller(requester, settings))))))
+	jiraCaller := workspaceHealth.WrapJira(fieldPresetHandler.WrapJira(undoHandler.WrapJira(resultCache.WrapJira(notificationHandler.WrapJira(usageHandler.WrapJira(createJiraCaller(requester, settings)))))))
 
 	// Create handlers
-	confluenceHandler := handlers.NewConfluenceHandler(confluenceCaller)
-	jiraHandler := handlers.NewJiraHandler(jiraCaller)
-	managementHandler := handlers.NewManagementHandler(credStore)
-	workspaceHandler := handlers.NewWorkspaceHandler(credStore)
+	// Destructive tools (MCP_CONFIRM_TOOLS) run only when called again with a confirmation token
+	confirmations := handlers.ConfirmationsFromEnv()
+	confluenceHandler := handlers.NewConfluenceHandler(confluenceCaller).WithCustomTools(customTools).WithConfirmations(confirmations)
+	jiraHandler := handlers.NewJiraHandler(jiraCaller).WithCustomTools(customTools).WithConfirmations(confirmations)
+	managementHandler := handlers.NewManagementHandler(cachedStore).WithHealth(workspaceHealth)
+	crossProductHandler := handlers.NewCrossProductHandler(jiraHandler, confluenceHandler)
+	workspaceHandler := handlers.NewWorkspaceHandler(cachedStore).WithHealth(workspaceHealth)
+
+	// Issue and page exports are files in EXPORTS_DIR, downloadable until EXPORT_TTL passes
+	exportStore, err := storage.NewExportStoreFromEnv()
+	if err != nil {
+		slog.Warn("exports disabled; cannot create the export directory", "error", err)
+	} else {
+		storage.StartExportPurgeLoop(exportStore)
+	}
+	exportHandler := handlers.NewExportHandler(jiraHandler, confluenceHandler, exportStore, storage.ExportTTLFromEnv()).
+		WithDownloads()
+	// Jira issue templates are kept in Postgres, per user or shared with an organization
+	templateHandler := handlers.NewIssueTemplateHandler(storage.NewIssueTemplateStoreFromEnv(credStore), jiraHandler)
+	// Playbooks (saved tool call lists) are kept in Postgres; they run once every tool is registered
+	playbookHandler := handlers.NewPlaybookHandler(storage.NewPlaybookStoreFromEnv(credStore))
+	// Jira issue update subscriptions are kept in Postgres and polled (see Start below)
+	subscriptionHandler := handlers.NewIssueSubscriptionHandler(storage.NewIssueSubscriptionStoreFromEnv(credStore),
+		storage.NewNotificationChannelStoreFromEnv(credStore), jiraHandler)
+	// Each MCP session may set a default workspace for its calls; kept in this replica's memory
+	sessionValues := mcp.NewSessionValues(mcp.DefaultSessionTTL)
+	activeWorkspaceHandler := handlers.NewActiveWorkspaceHandler(sessionValues, credStore)
+	// Pinned issues are kept in Postgres
+	pinnedIssueHandler := handlers.NewPinnedIssueHandler(storage.NewPinnedIssueStoreFromEnv(credStore), jiraHandler)
+
+	// Other replicas may change credentials too; keep this instance's caches in sync
+	err = events.SubscribeCredentialEvents(eventChannel, func(event events.CredentialEvent) {
+		cachedStore.Invalidate(event.UserID, event.WorkspaceID)
+		managementHandler.InvalidateUser(event.UserID)
+		workspaceHealth.Forget(event.WorkspaceID)
+	})
+	if err != nil {
+		slog.Warn("credential event subscription failed, relying on cache TTL", "error", err)
+	}
+	err = events.SubscribeRevocationEvents(eventChannel, func(event events.RevocationEvent) {
+		tokenCache.Invalidate()
+	})
+	if err != nil {
+		slog.Warn("revocation event subscription failed, relying on token cache TTL", "error", err)
+	}
 
 	// Create MCP server
 	server := mcp.NewServer()
@@ -158,14 +348,74 @@ func main() {
 	for _, tool := range managementHandler.ListTools() {
 		server.RegisterTool(tool)
 	}
+	for _, tool := range crossProductHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range notificationHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range exportHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range templateHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range playbookHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range subscriptionHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range pinnedIssueHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range undoHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range activeWorkspaceHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+
+	// Third-party tool handlers run as MCP subprocesses; they cannot replace built-in tools
+	plugins, err := handlers.StartPluginsFromEnv(func(tool string) time.Duration { return settings.Current().ToolTimeout(tool) })
+	if err != nil {
+		panic(fmt.Sprintf("❌ Failed to start plugins: %v", err))
+	}
+	pluginHandler := handlers.NewPluginHandler()
+	for _, plugin := range plugins {
+		pluginHandler.Add(plugin, server.AllTools())
+	}
+	for _, tool := range pluginHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
 
 	// Create handler function with userID support
-	handler := func(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
-		if call.Name == "list_workspaces" || call.Name == "workspace_status" {
+	routeTool := func(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+		if pluginHandler.IsPluginTool(call.Name) {
+			return pluginHandler.HandleTool(call, userID)
+		} else if handlers.IsManagementTool(call.Name) {
 			return managementHandler.HandleTool(call, userID)
+		} else if handlers.IsCrossProductTool(call.Name) {
+			return crossProductHandler.HandleTool(call, userID)
+		} else if handlers.IsNotificationTool(call.Name) {
+			return notificationHandler.HandleTool(call, userID)
+		} else if handlers.IsExportTool(call.Name) {
+			return exportHandler.HandleTool(call, userID)
+		} else if handlers.IsIssueTemplateTool(call.Name) {
+			return templateHandler.HandleTool(call, userID)
+		} else if handlers.IsPlaybookTool(call.Name) {
+			return playbookHandler.HandleTool(call, userID)
+		} else if handlers.IsIssueSubscriptionTool(call.Name) {
+			return subscriptionHandler.HandleTool(call, userID)
+		} else if handlers.IsPinnedIssueTool(call.Name) {
+			return pinnedIssueHandler.HandleTool(call, userID)
+		} else if handlers.IsUndoTool(call.Name) {
+			return undoHandler.HandleTool(call, userID)
+		} else if handlers.IsActiveWorkspaceTool(call.Name) {
+			return activeWorkspaceHandler.HandleTool(call, userID)
 		} else if len(call.Name) >= 10 && call.Name[:10] == "confluence" {
 			return confluenceHandler.HandleTool(call, userID)
-		} else if len(call.Name) >= 5 && call.Name[:5] == "jira_" {
+		} else if handlers.IsJiraServiceTool(call.Name) {
 			return jiraHandler.HandleTool(call, userID)
 		}
 
@@ -177,6 +427,47 @@ func main() {
 		}, fmt.Errorf("unknown tool: %s", call.Name)
 	}
 
+	// Tools off the server-wide allowlist are neither listed nor callable
+	server.SetToolFilter(func(name string) bool { return settings.Current().ToolEnabled(name) })
+
+	// Record per-tool, per-workspace call counts and latency for /metrics. Calls that omit
+	// workspace_id use their session's active workspace.
+	handler := activeWorkspaceHandler.Wrap(server.AllTools(), func(call mcp.ToolCall, userID string) (mcp.ToolResult, error) {
+		current := settings.Current()
+		if !current.ToolEnabled(call.Name) {
+			return mcp.ToolResult{}, fmt.Errorf("tool %s is disabled on this server", call.Name)
+		}
+		start := time.Now()
+		workspaceID, _ := call.Arguments["workspace_id"].(string)
+		// Calls that outlive their timeout (e.g. tools not bound by an RPC deadline) are reported
+		stopWatchdog := handlers.WatchToolCall(call.Name, workspaceID, call.RequestID, current.ToolTimeout(call.Name))
+		result, err := routeTool(call, userID)
+		stopWatchdog()
+		metrics.ToolCalls.Inc(call.Name, workspaceID, metrics.Status(err != nil || result.IsError))
+		metrics.ToolCallDuration.ObserveSince(start, call.Name, workspaceID)
+		// Large results (e.g. broad searches) are truncated so they cannot stall SSE clients
+		if truncated, ok := mcp.TruncateResult(result, current.MaxResponseBytes); ok {
+			slog.Warn("tool result truncated", "tool", call.Name, "workspace_id", workspaceID,
+				"limit_bytes", current.MaxResponseBytes, "request_id", call.RequestID)
+			result = truncated
+		}
+		// Results that appear to hold personal data are flagged, or withheld in strict mode
+		if flagged, findings := handlers.ApplyPIIPolicy(result, current.PIIScan); len(findings) > 0 {
+			slog.Warn("tool result contains likely personal data", "tool", call.Name, "workspace_id", workspaceID,
+				"kinds", findings.Kinds(), "mode", current.PIIScan, "request_id", call.RequestID)
+			result = flagged
+		}
+		return result, err
+	})
+
+	// Saved tool pipelines run on cron schedules as their owners (Postgres only)
+	scheduleHandler := handlers.NewScheduleHandler(storage.NewScheduleStoreFromEnv(credStore), server.AllTools(), handler, crossProductHandler).
+		WithNotifier(notificationHandler.Notifier())
+	scheduleHandler.Start()
+	subscriptionHandler.Start()
+	// Playbook steps go through the same allowlist, metrics and truncation as direct calls
+	playbookHandler.WithRunner(server.AllTools(), handler)
+
 	// Setup router
 	mux := http.NewServeMux()
 
@@ -213,15 +504,37 @@ func main() {
 
 	// 2. Global Request Logger
 	mux.HandleFunc("/log", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(os.Stderr, "GLOBAL LOG: %s %s from %s\n", r.Method, r.URL.Path, r.RemoteAddr)
+		slog.DebugContext(r.Context(), "log endpoint called", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
 		http.NotFound(w, r)
 	})
 
+	// OAuth protected resource metadata (RFC 9728) for MCP client discovery
+	mux.HandleFunc(auth.ProtectedResourceMetadataPath, auth.HandleProtectedResourceMetadata)
+
+	// OpenAPI description of the REST tool API, for importing into ChatGPT Actions and the like
+	mux.HandleFunc("/api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		// ?tools=jira,confluence_search narrows the document, e.g. to fit ChatGPT's 30 operations
+		var selectors []string
+		for _, selector := range strings.Split(r.URL.Query().Get("tools"), ",") {
+			if selector = strings.TrimSpace(selector); selector != "" {
+				selectors = append(selectors, selector)
+			}
+		}
+		tools := mcp.SelectTools(server.Tools(), selectors)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mcp.OpenAPISpec(tools, auth.PublicURL(r), identity != nil))
+	})
+
 	// 3. Workspace Management API
-	if clerkAuth != nil {
-		authMiddleware := auth.RequireAuth(clerkAuth)
+	if identity != nil {
+		authMiddleware := auth.RequireAuth(identity).WithAPIKeys(apiKeyStore).WithServiceTokens(serviceTokenStore)
 
-		workspaceRouteHandler := authMiddleware.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Scoped tokens need workspaces:manage for the workspace API
+		workspaceRouteHandler := authMiddleware.HandlerFunc(auth.RequireScope(auth.ScopeWorkspacesManage, func(w http.ResponseWriter, r *http.Request) {
 			switch r.Method {
 			case http.MethodGet:
 				workspaceHandler.HandleListWorkspaces(w, r)
@@ -230,17 +543,26 @@ func main() {
 			default:
 				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			}
-		})
+		}))
 
-		mux.HandleFunc("/api/workspaces", func(w http.ResponseWriter, r *http.Request) {
-			fmt.Fprintf(os.Stderr, "GLOBAL LOG: %s %s\n", r.Method, r.URL.Path)
-			workspaceRouteHandler.ServeHTTP(w, r)
-		})
-		mux.Handle("/api/workspaces/", authMiddleware.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Handle("/api/workspaces", workspaceRouteHandler)
+		mux.Handle("/api/workspaces/", authMiddleware.HandlerFunc(auth.RequireScope(auth.ScopeWorkspacesManage, func(w http.ResponseWriter, r *http.Request) {
 			if r.URL.Path == "/api/workspaces/" {
 				workspaceRouteHandler.ServeHTTP(w, r)
 				return
 			}
+			if r.URL.Path == "/api/workspaces/import" && r.Method == http.MethodPost {
+				workspaceHandler.HandleImportWorkspaces(w, r)
+				return
+			}
+			if r.URL.Path == "/api/workspaces/export" && r.Method == http.MethodGet {
+				workspaceHandler.HandleExportWorkspaces(w, r)
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, "/restore") && r.Method == http.MethodPost {
+				workspaceHandler.HandleRestoreWorkspace(w, r)
+				return
+			}
 			if strings.HasSuffix(r.URL.Path, "/status") {
 				workspaceHandler.HandleWorkspaceStatus(w, r)
 			} else if r.Method == http.MethodDelete {
@@ -250,14 +572,79 @@ func main() {
 			} else {
 				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			}
+		})))
+
+		// API key management
+		apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyStore)
+		mux.Handle("/api/keys", authMiddleware.HandlerFunc(apiKeyHandler.HandleAPIKeys))
+		mux.Handle("/api/keys/", authMiddleware.HandlerFunc(apiKeyHandler.HandleAPIKeys))
+
+		// Scheduled tool pipelines and their run history
+		mux.Handle("/api/schedules", authMiddleware.HandlerFunc(scheduleHandler.HandleSchedules))
+		mux.Handle("/api/schedules/", authMiddleware.HandlerFunc(scheduleHandler.HandleSchedules))
+
+		// Slack and Teams notification channels
+		mux.Handle("/api/notifications/channels", authMiddleware.HandlerFunc(notificationHandler.HandleChannels))
+		mux.Handle("/api/notifications/channels/", authMiddleware.HandlerFunc(notificationHandler.HandleChannels))
+
+		// Jira issue templates
+		mux.Handle("/api/issue-templates", authMiddleware.HandlerFunc(templateHandler.HandleTemplates))
+		mux.Handle("/api/issue-templates/", authMiddleware.HandlerFunc(templateHandler.HandleTemplates))
+
+		// Jira field presets
+		mux.Handle("/api/field-presets", authMiddleware.HandlerFunc(fieldPresetHandler.HandleFieldPresets))
+		mux.Handle("/api/field-presets/", authMiddleware.HandlerFunc(fieldPresetHandler.HandleFieldPresets))
+
+		// Playbooks and their runs
+		mux.Handle("/api/playbooks", authMiddleware.HandlerFunc(playbookHandler.HandlePlaybooks))
+		mux.Handle("/api/playbooks/", authMiddleware.HandlerFunc(playbookHandler.HandlePlaybooks))
+
+		// Issue and page exports; the links the export tools return carry their own token
+		exportRoutes := authMiddleware.HandlerFunc(exportHandler.HandleExports)
+		mux.Handle("/api/exports", exportRoutes)
+		mux.Handle("/api/exports/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Has("token") {
+				exportHandler.HandleDownload(w, r)
+				return
+			}
+			exportRoutes.ServeHTTP(w, r)
 		}))
 
-		// REST Tool Execution (for ChatGPT)
-		restToolHandler := handlers.NewRestToolHandler(confluenceHandler, jiraHandler, managementHandler)
-		mux.HandleFunc("/api/tools/", func(w http.ResponseWriter, r *http.Request) {
-			fmt.Fprintf(os.Stderr, "GLOBAL LOG: %s %s\n", r.Method, r.URL.Path)
-			authMiddleware.Handler(http.HandlerFunc(restToolHandler.HandleToolRequest)).ServeHTTP(w, r)
+		// Service token management (MCP_ADMIN_USER_IDS only)
+		serviceTokenHandler := handlers.NewServiceTokenHandler(serviceTokenStore)
+		mux.Handle("/api/service-tokens", authMiddleware.HandlerFunc(auth.RequireAdmin(serviceTokenHandler.HandleServiceTokens)))
+		mux.Handle("/api/service-tokens/", authMiddleware.HandlerFunc(auth.RequireAdmin(serviceTokenHandler.HandleServiceTokens)))
+
+		// Inspect and replay requests the services dead-lettered (MCP_ADMIN_USER_IDS only)
+		deadLetterHandler := handlers.NewDeadLetterHandler(eventChannel, map[string]string{
+			"jira":       queueName("JiraRequests"),
+			"confluence": queueName("ConfluenceRequests"),
 		})
+		mux.Handle("/api/admin/dead-letters", authMiddleware.HandlerFunc(auth.RequireAdmin(deadLetterHandler.HandleDeadLetters)))
+		mux.Handle("/api/admin/dead-letters/", authMiddleware.HandlerFunc(auth.RequireAdmin(deadLetterHandler.HandleDeadLetters)))
+
+		// Users' own Atlassian accounts, for workspaces that call Atlassian as each user.
+		// Atlassian redirects to the callback, whose signed state names the user.
+		atlassianOAuthHandler := handlers.NewAtlassianOAuthHandler(storage.NewUserTokenStoreFromEnv(credStore), oauthApp)
+		mux.Handle("/api/atlassian/connect", authMiddleware.HandlerFunc(atlassianOAuthHandler.HandleConnect))
+		mux.Handle("/api/atlassian/connection", authMiddleware.HandlerFunc(atlassianOAuthHandler.HandleConnection))
+		mux.HandleFunc("/api/atlassian/callback", atlassianOAuthHandler.HandleCallback)
+
+		// Usage statistics for the dashboard
+		mux.Handle("/api/usage", authMiddleware.HandlerFunc(usageHandler.HandleGetUsage))
+
+		// REST Tool Execution (for ChatGPT)
+		restToolHandler := handlers.NewRestToolHandler(confluenceHandler, jiraHandler, managementHandler).
+			WithSettings(settings).
+			WithNotifications(notificationHandler).
+			WithExports(exportHandler).
+			WithIssueTemplates(templateHandler).
+			WithPlaybooks(playbookHandler).
+			WithIssueSubscriptions(subscriptionHandler).
+			WithPinnedIssues(pinnedIssueHandler).
+			WithUndo(undoHandler).
+			WithPlugins(pluginHandler)
+		mux.Handle("/api/tools/", gzipMiddleware(authMiddleware.HandlerFunc(restToolHandler.HandleToolRequest)))
 
 	} else {
 		// Dev mode
@@ -268,62 +655,99 @@ func main() {
 				workspaceHandler.HandleCreateWorkspace(w, r)
 			}
 		})
-		restToolHandler := handlers.NewRestToolHandler(confluenceHandler, jiraHandler, managementHandler)
-		mux.HandleFunc("/api/tools/", restToolHandler.HandleToolRequest)
+		mux.HandleFunc("/api/usage", usageHandler.HandleGetUsage)
+		mux.HandleFunc("/api/exports/", exportHandler.HandleDownload)
+		restToolHandler := handlers.NewRestToolHandler(confluenceHandler, jiraHandler, managementHandler).
+			WithSettings(settings).
+			WithNotifications(notificationHandler).
+			WithExports(exportHandler).
+			WithIssueTemplates(templateHandler).
+			WithPlaybooks(playbookHandler).
+			WithIssueSubscriptions(subscriptionHandler).
+			WithPinnedIssues(pinnedIssueHandler).
+			WithUndo(undoHandler).
+			WithPlugins(pluginHandler)
+		mux.Handle("/api/tools/", gzipMiddleware(http.HandlerFunc(restToolHandler.HandleToolRequest)))
 	}
 
 	// 3. SSE Server (Replaces port 3000)
-	sseServer := mcp.NewSSEServer(server, handler)
+	sseServer := mcp.NewSSEServer(server, handler).WithSessionValues(sessionValues)
 
 	// Create SSE handler with Auth if configured
 	var sseHandler http.Handler
-	if clerkAuth != nil {
-		authMiddleware := auth.RequireAuth(clerkAuth)
+	if identity != nil {
+		authMiddleware := auth.RequireAuth(identity).WithAPIKeys(apiKeyStore).WithServiceTokens(serviceTokenStore)
 		// SSE endpoint needs auth
 		sseHandler = authMiddleware.HandlerFunc(sseServer.HandleSSE)
 	} else {
 		sseHandler = http.HandlerFunc(sseServer.HandleSSE)
 	}
 
-	mux.Handle("/sse", sseHandler)
-	mux.Handle("/message", http.HandlerFunc(sseServer.HandleMessage)) // Message posting usually uses same auth header
-
-	// Deep Health Check Endpoint (for Kubernetes)
-	mux.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
-		status := "UP"
-		details := make(map[string]string)
-		code := http.StatusOK
-
-		// Check Database
-		if err := credStore.Ping(); err != nil {
-			status = "DOWN"
-			details["database"] = fmt.Sprintf("DOWN: %v", err)
-			code = http.StatusServiceUnavailable
-		} else {
-			details["database"] = "UP"
+	// Runtime introspection for the dashboard's ops panel (admins only)
+	if identity != nil {
+		authMiddleware := auth.RequireAuth(identity).WithAPIKeys(apiKeyStore).WithServiceTokens(serviceTokenStore)
+		adminHandler := handlers.NewAdminHandler(server, sseServer, settings).
+			WithQueues(busKind, eventChannel, map[string]string{
+				"jira":       queueName("JiraRequests"),
+				"confluence": queueName("ConfluenceRequests"),
+			}).
+			WithCache("credentials", cachedStore.CacheTTL(), cachedStore.CacheStats).
+			WithCache("tokens", tokenCache.TTL(), tokenCache.Stats).
+			WithCache("workspace_listings", managementHandler.CacheTTL(), managementHandler.CacheStats).
+			WithCache("tool_results", 0, resultCache.Stats) // TTLs vary by tool
+		if busKind != bus.KindMemory {
+			// In-process services record into this process's buffer, which is reported anyway
+			adminHandler.WithRecordings(createJiraCaller(requester, settings), createConfluenceCaller(requester, settings))
+		}
+		for _, path := range []string{"/api/admin/tools", "/api/admin/sessions", "/api/admin/queues", "/api/admin/caches", "/api/admin/errors", "/api/admin/recordings"} {
+			mux.Handle(path, authMiddleware.HandlerFunc(auth.RequireAdmin(adminHandler.HandleAdmin)))
 		}
+	}
+
+	mux.Handle("/sse", sseHandler)
+	mux.Handle("/message", gzipMiddleware(http.HandlerFunc(sseServer.HandleMessage))) // Message posting usually uses same auth header
+
+	// Prometheus metrics (unauthenticated, like the health check; restrict at the ingress)
+	mux.Handle("/metrics", metrics.Handler())
 
-		// Check RabbitMQ (TwistyGo)
-		// We use a simple nil check here as twistygo handles the internal connection state.
-		if rconn != nil {
-			details["rabbitmq"] = "UP"
-		} else {
-			status = "DOWN"
-			details["rabbitmq"] = "DOWN"
-			code = http.StatusServiceUnavailable
+	// Deep health check: the database and the message bus (not needed when the services
+	// run in-process), each with its latency
+	busPing := func() error {
+		if connected, err := busStatus(); !connected {
+			return err
 		}
+		return nil
+	}
+	healthChecks := health.NewChecks(ServiceVersion)
+	healthChecks.SetStorage(storage.Backend(credStore))
+	healthChecks.Add("database", credStore.Ping)
+	if busCheck != "" {
+		healthChecks.Add(busCheck, busPing)
+	}
+	mux.Handle("/api/health", healthChecks.Handler())
+
+	// Readiness probe: gated on startup and shutdown. With MESSAGE_BUS=memory this
+	// process calls Atlassian itself, so Atlassian reachability counts as well.
+	readiness := health.NewReadiness(ServiceVersion)
+	readiness.SetStorage(storage.Backend(credStore))
+	readiness.Add("database", credStore.Ping)
+	if busCheck != "" {
+		readiness.Add(busCheck, busPing)
+	}
+	if memoryBus != nil {
+		readiness.Add("atlassian", health.AtlassianProbeFromEnv())
+	}
+	mux.Handle("/api/ready", readiness.Handler())
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(code)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  status,
-			"details": details,
-			"version": ServiceVersion,
-		})
-	})
+	// Liveness probe: answers while the process serves HTTP, whatever its dependencies
+	mux.Handle("/api/live", health.LiveHandler(ServiceVersion))
 
-	// Apply CORS, Recovery, and Logging to everything
-	handlerWithCors := requestLogger(corsMiddleware(recoverMiddleware(mux)))
+	// Apply request IDs, CORS, Recovery, and Logging to everything (including the well-known metadata)
+	corsConfig := corsPolicyFromEnv()
+	if corsConfig.allowsAnyOrigin() {
+		slog.Warn("CORS allows any origin; set CORS_ALLOWED_ORIGINS in production")
+	}
+	handlerWithCors := requestIDMiddleware(requestLogger(corsMiddleware(corsConfig, recoverMiddleware(mux))))
 
 	// Setup Server
 	srv := &http.Server{
@@ -331,56 +755,77 @@ func main() {
 		Handler: handlerWithCors,
 	}
 
+	// gRPC API for internal services on GRPC_PORT, with mutual TLS, answered by the same routes
+	grpcServer, grpcAddr, err := grpcapi.ServerFromEnv(grpcapi.NewServer(mux, server.AllTools), requestIDInterceptor, recoverInterceptor)
+	if err != nil {
+		panic(fmt.Sprintf("❌ Invalid gRPC configuration: %v", err))
+	}
+
 	// 4. Handle Graceful Shutdown (SIGTERM/SIGINT)
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
-		fmt.Printf("🚀 Starting Unified Trilix Server on port %d...\n", port)
-		fmt.Printf("   - Dashboard:    http://localhost:%d/\n", port)
-		fmt.Printf("   - Health:       http://localhost:%d/api/health\n", port)
-		fmt.Printf("   - Test Client:  http://localhost:%d/docs/test-client.html\n", port)
-		fmt.Printf("   - Workspaces:   http://localhost:%d/workspaces.html\n", port)
-		fmt.Printf("   - API List:     http://localhost:%d/api/workspaces\n", port)
+		slog.Info("starting unified Trilix server", "port", port,
+			"dashboard", fmt.Sprintf("http://localhost:%d/", port),
+			"health", fmt.Sprintf("http://localhost:%d/api/health", port),
+			"test_client", fmt.Sprintf("http://localhost:%d/docs/test-client.html", port))
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			panic(fmt.Sprintf("❌ Failed to start server: %v", err))
 		}
 	}()
 
+	if grpcServer != nil {
+		go func() {
+			slog.Info("starting gRPC API", "addr", grpcAddr)
+			listener, err := net.Listen("tcp", grpcAddr)
+			if err == nil {
+				err = grpcServer.Serve(listener)
+			}
+			if err != nil {
+				panic(fmt.Sprintf("❌ Failed to start gRPC server: %v", err))
+			}
+		}()
+	}
+
+	readiness.MarkReady()
+
 	// Wait for termination signal
 	<-stop
-	fmt.Println("\n🛑 Shutting down server...")
+	slog.Info("shutting down server")
+	readiness.MarkNotReady()
 
 	// Create a timeout context for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		fmt.Printf("❌ Server forced to shutdown: %v\n", err)
+		slog.Error("server forced to shut down", "error", err)
 	}
-
-	fmt.Println("👋 Server exited gracefully")
-}
-
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			slog.Error("gRPC server forced to shut down", "error", ctx.Err())
+			grpcServer.Stop()
 		}
-		next.ServeHTTP(w, r)
-	})
+	}
+	pluginHandler.Close()
+
+	slog.Info("server exited gracefully")
 }
 
 func requestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Printf("🌐 [%s] %s %s from %s (User-Agent: %s)\n",
-			time.Now().Format("15:04:05"), r.Method, r.URL.String(), r.RemoteAddr, r.UserAgent())
+		// Log the path only: SSE clients may pass their token in the query string
+		slog.InfoContext(r.Context(), "http request", "method", r.Method, "path", r.URL.Path,
+			"remote_addr", r.RemoteAddr, "user_agent", r.UserAgent())
 		next.ServeHTTP(w, r)
 	})
 }
@@ -389,7 +834,7 @@ func recoverMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				fmt.Printf("❌ HTTP Handler panic recovered: %v\n", err)
+				slog.ErrorContext(r.Context(), "HTTP handler panic recovered", "panic", err, "path", r.URL.Path)
 				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			}
 		}()
@@ -397,137 +842,63 @@ func recoverMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// cloneServiceQueue creates a shallow-ish copy of a ServiceQueue_t with its own Message
-// and ResponseQueue to avoid race conditions during concurrent tool calls.
-func cloneServiceQueue(src *twistygo.ServiceQueue_t) *twistygo.ServiceQueue_t {
-	if src == nil {
-		return nil
-	}
-	dst := *src
-	dst.Message = twistygo.MessageSet_t{}
-	dst.ResponseQueue = &amqp.Queue{}
-	dst.Headers = make(amqp.Table)
-	if src.Headers != nil {
-		for k, v := range src.Headers {
-			dst.Headers[k] = v
+// recoverInterceptor does for gRPC calls what recoverMiddleware does for HTTP requests
+func recoverInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(ctx, "gRPC handler panic recovered", "panic", r, "method", info.FullMethod)
+			resp, err = nil, status.Error(codes.Internal, "Internal Server Error")
 		}
+	}()
+	return handler(ctx, req)
+}
+
+// queueName returns the RabbitMQ queue behind a twistygo queue definition
+func queueName(name string) string {
+	if rconn == nil {
+		return ""
 	}
-	// Deep copy Queue parameters because twistygo modifies sq.Queue.Args in publishRPC
-	if src.Queue != nil {
-		qCopy := *src.Queue
-		if src.Queue.Args != nil {
-			argsCopy := make(amqp.Table)
-			for k, v := range *src.Queue.Args {
-				argsCopy[k] = v
-			}
-			qCopy.Args = &argsCopy
-		}
-		dst.Queue = &qCopy
+	if sq := rconn.AmqpConnectQueue(name); sq != nil && sq.Queue != nil {
+		return sq.Queue.Name
 	}
-	return &dst
+	return ""
 }
 
-func createConfluenceCaller(rpcTimeout time.Duration) func(models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+func createConfluenceCaller(requester bus.Requester, settings *config.SettingsWatcher) func(models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
 	return func(req models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
-		// Connect to ConfluenceRequests queue
-		sqGlobal := rconn.AmqpConnectQueue("ConfluenceRequests")
-		sq := cloneServiceQueue(sqGlobal)
-		if sq == nil {
-			return nil, fmt.Errorf("confluence queue not initialized")
-		}
-		sq.SetEncoding(twistygo.EncodingJson)
+		timeout := settings.Current().ToolTimeout(bus.ConfluenceService + "_" + req.Action)
+		req.Deadline = time.Now().Add(timeout).UnixMilli()
 
-		// Marshal single request as object (not array) for the RPC payload
-		reqBytes, err := json.Marshal(req)
-		if err != nil {
-			return nil, err
-		}
-		sq.Message.ResetDataList()
-		sq.Message.AppendData(req)
-		sq.Message.Encoded = reqBytes
-
-		// Publish and wait for response (RPC) with timeout
-		type publishResult struct {
-			resp []byte
-			err  error
-		}
-		resChan := make(chan publishResult, 1)
-		go func() {
-			resp, err := sq.Publish()
-			resChan <- publishResult{resp, err}
-		}()
-
-		var responseBytes []byte
-		select {
-		case res := <-resChan:
-			if res.err != nil {
-				return nil, res.err
-			}
-			responseBytes = res.resp
-		case <-time.After(rpcTimeout):
-			return nil, fmt.Errorf("RPC timeout: confluence service did not respond within %v", rpcTimeout)
-		}
-
-		// Debug log raw response to aid troubleshooting unexpected payload shapes
-		fmt.Printf("Confluence RPC raw response: %s\n", string(responseBytes))
-
-		// Unmarshal response
 		var response models.ConfluenceResponse
-		if err := json.Unmarshal(responseBytes, &response); err != nil {
+		if err := callService(requester, bus.ConfluenceService, timeout, req.RequestID, req.Action, req, &response); err != nil {
 			return nil, err
 		}
-
 		return &response, nil
 	}
 }
 
-func createJiraCaller(rpcTimeout time.Duration) func(models.JiraRequest) (*models.JiraResponse, error) {
+func createJiraCaller(requester bus.Requester, settings *config.SettingsWatcher) func(models.JiraRequest) (*models.JiraResponse, error) {
 	return func(req models.JiraRequest) (*models.JiraResponse, error) {
-		// Connect to JiraRequests queue
-		sqGlobal := rconn.AmqpConnectQueue("JiraRequests")
-		sq := cloneServiceQueue(sqGlobal)
-		if sq == nil {
-			return nil, fmt.Errorf("jira queue not initialized")
-		}
-		sq.SetEncoding(twistygo.EncodingJson)
+		timeout := settings.Current().ToolTimeout(bus.JiraService + "_" + req.Action)
+		req.Deadline = time.Now().Add(timeout).UnixMilli()
 
-		// Marshal single request as object (not array) for the RPC payload
-		reqBytes, err := json.Marshal(req)
-		if err != nil {
-			return nil, err
-		}
-		sq.Message.ResetDataList()
-		sq.Message.AppendData(req)
-		sq.Message.Encoded = reqBytes
-
-		// Publish and wait for response (RPC) with timeout
-		type publishResult struct {
-			resp []byte
-			err  error
-		}
-		resChan := make(chan publishResult, 1)
-		go func() {
-			resp, err := sq.Publish()
-			resChan <- publishResult{resp, err}
-		}()
-
-		var responseBytes []byte
-		select {
-		case res := <-resChan:
-			if res.err != nil {
-				return nil, res.err
-			}
-			responseBytes = res.resp
-		case <-time.After(rpcTimeout):
-			return nil, fmt.Errorf("RPC timeout: jira service did not respond within %v", rpcTimeout)
-		}
-
-		// Unmarshal response
 		var response models.JiraResponse
-		if err := json.Unmarshal(responseBytes, &response); err != nil {
+		if err := callService(requester, bus.JiraService, timeout, req.RequestID, req.Action, req, &response); err != nil {
 			return nil, err
 		}
-
 		return &response, nil
 	}
 }
+
+// callService sends a request over the message bus and waits up to timeout for the reply
+func callService(requester bus.Requester, service string, timeout time.Duration, requestID, action string, req, resp interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := bus.Call(ctx, requester, service, requestID, req, resp)
+	if errors.Is(err, context.DeadlineExceeded) {
+		slog.Warn(service+" RPC timed out", "request_id", requestID, "action", action, "timeout", timeout.String())
+		return fmt.Errorf("RPC timeout: %s service did not respond within %v", service, timeout)
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// validRequestID limits client-supplied IDs to what is safe to log and forward
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestIDMiddleware assigns every request a correlation ID, honoring a valid
+// X-Request-ID from the client. The ID is echoed in the response header and stored
+// in the request context, from where it reaches logs, tool calls and the services.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(logging.RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(logging.RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// requestIDInterceptor does for gRPC calls what requestIDMiddleware does for HTTP
+// requests, with the ID in x-request-id metadata
+func requestIDInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	requestID := ""
+	if ids := metadata.ValueFromIncomingContext(ctx, strings.ToLower(logging.RequestIDHeader)); len(ids) > 0 {
+		requestID = ids[0]
+	}
+	if !validRequestID.MatchString(requestID) {
+		requestID = uuid.NewString()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(logging.RequestIDHeader, requestID))
+	return handler(logging.WithRequestID(ctx, requestID), req)
+}
@@ -1,41 +1,117 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"time"
 
+	confluenceservice "github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/handlers"
+	jiraservice "github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/handlers"
 	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/handlers"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/bus"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
 	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
 	"github.com/providentiaww/twistygo"
 )
 
-var rconn *twistygo.AmqpConnection_t
+// atlassianTimeout is the Atlassian API timeout for in-process services (MESSAGE_BUS=memory)
+const atlassianTimeout = 30 * time.Second
 
 func init() {
 	config.LoadEnv("../../.env")
+	// Logs go to stderr; stdout carries the MCP protocol
+	logging.Setup("mcp-stdio")
 	twistygo.LogStartService("MCPStdio", "1.0.0")
-	rconn = twistygo.AmqpConnect()
-	rconn.AmqpLoadQueues("ConfluenceRequests", "JiraRequests")
 }
 
 func main() {
+	busKind, err := bus.KindFromEnv()
+	if err != nil {
+		slog.Error("invalid message bus", "error", err)
+		os.Exit(1)
+	}
+
+	// Passthrough tools operators define in tools.yaml (TOOLS_FILE)
+	customTools, err := config.ToolDefinitionsFromEnv()
+	if err != nil {
+		slog.Error("invalid tools.yaml", "error", err)
+		os.Exit(1)
+	}
+
 	credStore, err := storage.NewCredentialStoreFromEnv()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize credential store: %v\n", err)
+		slog.Error("failed to initialize credential store", "error", err)
 		os.Exit(1)
 	}
 	defer credStore.Close()
 
-	confluenceCaller := createConfluenceCaller()
-	jiraCaller := createJiraCaller()
+	var requester bus.Requester
+	switch busKind {
+	case bus.KindAMQP:
+		rconn := twistygo.AmqpConnect()
+		rconn.AmqpLoadQueues("ConfluenceRequests", "JiraRequests")
+		requester = bus.NewAMQP(rconn)
+	case bus.KindNATS:
+		natsBus, err := bus.NewNATS(bus.NATSURLFromEnv(), "mcp-stdio")
+		if err != nil {
+			slog.Error("failed to connect to NATS", "error", err)
+			os.Exit(1)
+		}
+		defer natsBus.Close()
+		requester = natsBus
+	case bus.KindMemory:
+		// Run the Jira and Confluence handlers in this process
+		memoryBus := bus.NewMemory()
+		idempotencyStore := storage.NewIdempotencyStoreFromEnv(credStore)
+		jiraService := jiraservice.NewService(credStore, atlassianTimeout).
+			WithIdempotency(idempotencyStore, storage.IdempotencyTTLFromEnv()).
+			WithCustomTools(customTools)
+		memoryBus.Serve(bus.JiraService, func(body []byte, headers bus.Headers) []byte {
+			return jiraService.HandleMessage(body, headers[logging.RequestIDHeader])
+		})
+		confluenceService := confluenceservice.NewService(credStore, atlassianTimeout).
+			WithIdempotency(idempotencyStore, storage.IdempotencyTTLFromEnv()).
+			WithCustomTools(customTools)
+		memoryBus.Serve(bus.ConfluenceService, func(body []byte, headers bus.Headers) []byte {
+			return confluenceService.HandleMessage(body, headers[logging.RequestIDHeader])
+		})
+		requester = memoryBus
+	}
+
+	// Reversible changes are logged for undo_last_action
+	undoHandler := handlers.NewUndoHandler(storage.NewUndoStoreFromEnv(credStore), storage.UndoTTLFromEnv())
+	confluenceCaller := undoHandler.WrapConfluence(createConfluenceCaller(requester))
+	// Field presets belong to the MCP server's config and its users; naming one here is an error
+	jiraCaller := handlers.NewFieldPresetHandler(nil, nil).WrapJira(undoHandler.WrapJira(createJiraCaller(requester)))
 
-	confluenceHandler := handlers.NewConfluenceHandler(confluenceCaller)
-	jiraHandler := handlers.NewJiraHandler(jiraCaller)
+	// Destructive tools (MCP_CONFIRM_TOOLS) run only when called again with a confirmation token
+	confirmations := handlers.ConfirmationsFromEnv()
+	confluenceHandler := handlers.NewConfluenceHandler(confluenceCaller).WithCustomTools(customTools).WithConfirmations(confirmations)
+	jiraHandler := handlers.NewJiraHandler(jiraCaller).WithCustomTools(customTools).WithConfirmations(confirmations)
 	managementHandler := handlers.NewManagementHandler(credStore)
+	crossProductHandler := handlers.NewCrossProductHandler(jiraHandler, confluenceHandler)
+	notificationHandler := handlers.NewNotificationHandler(storage.NewNotificationChannelStoreFromEnv(credStore))
+	// Exports are written to EXPORTS_DIR on this machine and returned as file paths
+	exportStore, err := storage.NewExportStoreFromEnv()
+	if err != nil {
+		slog.Warn("exports disabled; cannot create the export directory", "error", err)
+	} else {
+		storage.StartExportPurgeLoop(exportStore)
+	}
+	exportHandler := handlers.NewExportHandler(jiraHandler, confluenceHandler, exportStore, storage.ExportTTLFromEnv())
+	templateHandler := handlers.NewIssueTemplateHandler(storage.NewIssueTemplateStoreFromEnv(credStore), jiraHandler)
+	playbookHandler := handlers.NewPlaybookHandler(storage.NewPlaybookStoreFromEnv(credStore))
+	// Subscriptions are managed here; the HTTP server polls for their changes
+	subscriptionHandler := handlers.NewIssueSubscriptionHandler(storage.NewIssueSubscriptionStoreFromEnv(credStore),
+		storage.NewNotificationChannelStoreFromEnv(credStore), jiraHandler)
+	pinnedIssueHandler := handlers.NewPinnedIssueHandler(storage.NewPinnedIssueStoreFromEnv(credStore), jiraHandler)
+	// The process serves one session, whose default workspace is kept in memory
+	activeWorkspaceHandler := handlers.NewActiveWorkspaceHandler(mcp.NewSessionValues(mcp.DefaultSessionTTL), credStore)
 
 	server := mcp.NewServer()
 
@@ -48,15 +124,77 @@ func main() {
 	for _, tool := range managementHandler.ListTools() {
 		server.RegisterTool(tool)
 	}
+	for _, tool := range crossProductHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range notificationHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range exportHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range templateHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range playbookHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range subscriptionHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range pinnedIssueHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range undoHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+	for _, tool := range activeWorkspaceHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
+
+	// Third-party tool handlers run as MCP subprocesses; they cannot replace built-in tools
+	plugins, err := handlers.StartPluginsFromEnv(func(string) time.Duration { return config.DefaultRPCTimeout })
+	if err != nil {
+		slog.Error("failed to start plugins", "error", err)
+		os.Exit(1)
+	}
+	pluginHandler := handlers.NewPluginHandler()
+	defer pluginHandler.Close()
+	for _, plugin := range plugins {
+		pluginHandler.Add(plugin, server.AllTools())
+	}
+	for _, tool := range pluginHandler.ListTools() {
+		server.RegisterTool(tool)
+	}
 
 	handler := func(call mcp.ToolCall) (mcp.ToolResult, error) {
 		userID := ""
 
-		if call.Name == "list_workspaces" || call.Name == "workspace_status" {
+		if pluginHandler.IsPluginTool(call.Name) {
+			return pluginHandler.HandleTool(call, userID)
+		} else if handlers.IsManagementTool(call.Name) {
 			return managementHandler.HandleTool(call, userID)
+		} else if handlers.IsCrossProductTool(call.Name) {
+			return crossProductHandler.HandleTool(call, userID)
+		} else if handlers.IsNotificationTool(call.Name) {
+			return notificationHandler.HandleTool(call, userID)
+		} else if handlers.IsExportTool(call.Name) {
+			return exportHandler.HandleTool(call, userID)
+		} else if handlers.IsIssueTemplateTool(call.Name) {
+			return templateHandler.HandleTool(call, userID)
+		} else if handlers.IsPlaybookTool(call.Name) {
+			return playbookHandler.HandleTool(call, userID)
+		} else if handlers.IsIssueSubscriptionTool(call.Name) {
+			return subscriptionHandler.HandleTool(call, userID)
+		} else if handlers.IsPinnedIssueTool(call.Name) {
+			return pinnedIssueHandler.HandleTool(call, userID)
+		} else if handlers.IsUndoTool(call.Name) {
+			return undoHandler.HandleTool(call, userID)
+		} else if handlers.IsActiveWorkspaceTool(call.Name) {
+			return activeWorkspaceHandler.HandleTool(call, userID)
 		} else if len(call.Name) >= 10 && call.Name[:10] == "confluence" {
 			return confluenceHandler.HandleTool(call, userID)
-		} else if len(call.Name) >= 5 && call.Name[:5] == "jira_" {
+		} else if handlers.IsJiraServiceTool(call.Name) {
 			return jiraHandler.HandleTool(call, userID)
 		}
 
@@ -68,37 +206,33 @@ func main() {
 		}, fmt.Errorf("unknown tool: %s", call.Name)
 	}
 
-	server.Start(handler)
+	playbookHandler.WithRunner(server.AllTools(), func(call mcp.ToolCall, _ string) (mcp.ToolResult, error) {
+		return handler(call)
+	})
+
+	// Calls that omit workspace_id use the one set with set_active_workspace
+	withActiveWorkspace := activeWorkspaceHandler.Wrap(server.AllTools(), func(call mcp.ToolCall, _ string) (mcp.ToolResult, error) {
+		return handler(call)
+	})
+	server.Start(func(call mcp.ToolCall) (mcp.ToolResult, error) {
+		return withActiveWorkspace(call, "")
+	})
 }
 
-func createConfluenceCaller() func(models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+func createConfluenceCaller(requester bus.Requester) func(models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
 	return func(req models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
-		sq := rconn.AmqpConnectQueue("ConfluenceRequests")
-		sq.SetEncoding(twistygo.EncodingJson)
-		sq.Message.AppendData(req)
-		responseBytes, err := sq.Publish()
-		if err != nil {
-			return nil, err
-		}
 		var response models.ConfluenceResponse
-		if err := json.Unmarshal(responseBytes, &response); err != nil {
+		if err := bus.Call(context.Background(), requester, bus.ConfluenceService, req.RequestID, req, &response); err != nil {
 			return nil, err
 		}
 		return &response, nil
 	}
 }
 
-func createJiraCaller() func(models.JiraRequest) (*models.JiraResponse, error) {
+func createJiraCaller(requester bus.Requester) func(models.JiraRequest) (*models.JiraResponse, error) {
 	return func(req models.JiraRequest) (*models.JiraResponse, error) {
-		sq := rconn.AmqpConnectQueue("JiraRequests")
-		sq.SetEncoding(twistygo.EncodingJson)
-		sq.Message.AppendData(req)
-		responseBytes, err := sq.Publish()
-		if err != nil {
-			return nil, err
-		}
 		var response models.JiraResponse
-		if err := json.Unmarshal(responseBytes, &response); err != nil {
+		if err := bus.Call(context.Background(), requester, bus.JiraService, req.RequestID, req, &response); err != nil {
 			return nil, err
 		}
 		return &response, nil
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	confluenceservice "github.com/providentiaww/trilix-atlassian-mcp/cmd/confluence-service/handlers"
+	jiraservice "github.com/providentiaww/trilix-atlassian-mcp/cmd/jira-service/handlers"
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/handlers"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/bus"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/client"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+// directBackend runs the Jira and Confluence services in this process against the
+// credential store (WORKSPACES_FILE or DATABASE_URL), like MESSAGE_BUS=memory
+type directBackend struct {
+	userID       string
+	credStore    storage.CredentialStoreInterface
+	jira         *handlers.JiraHandler
+	confluence   *handlers.ConfluenceHandler
+	management   *handlers.ManagementHandler
+	crossProduct *handlers.CrossProductHandler
+	notification *handlers.NotificationHandler
+	export       *handlers.ExportHandler
+	template     *handlers.IssueTemplateHandler
+	playbook     *handlers.PlaybookHandler
+	subscription *handlers.IssueSubscriptionHandler
+}
+
+func newDirectBackend(userID string) (*directBackend, error) {
+	credStore, err := storage.NewCredentialStoreFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if path := os.Getenv("ATLASSIAN_CA_BUNDLE"); path != "" {
+		if err := atlassian.SetCABundle(path); err != nil {
+			return nil, err
+		}
+	}
+
+	memoryBus := bus.NewMemory()
+	idempotencyStore := storage.NewIdempotencyStoreFromEnv(credStore)
+	jiraService := jiraservice.NewService(credStore, config.DefaultAtlassianTimeout).
+		WithIdempotency(idempotencyStore, storage.IdempotencyTTLFromEnv())
+	memoryBus.Serve(bus.JiraService, func(body []byte, headers bus.Headers) []byte {
+		return jiraService.HandleMessage(body, headers[logging.RequestIDHeader])
+	})
+	confluenceService := confluenceservice.NewService(credStore, config.DefaultAtlassianTimeout).
+		WithIdempotency(idempotencyStore, storage.IdempotencyTTLFromEnv())
+	memoryBus.Serve(bus.ConfluenceService, func(body []byte, headers bus.Headers) []byte {
+		return confluenceService.HandleMessage(body, headers[logging.RequestIDHeader])
+	})
+
+	backend := &directBackend{
+		userID:    userID,
+		credStore: credStore,
+		// The user's saved field presets apply; the MCP server's config.yaml presets do not
+		jira: handlers.NewJiraHandler(handlers.NewFieldPresetHandler(storage.NewFieldPresetStoreFromEnv(credStore), nil).
+			WrapJira(func(req models.JiraRequest) (*models.JiraResponse, error) {
+				var response models.JiraResponse
+				if err := bus.Call(context.Background(), memoryBus, bus.JiraService, req.RequestID, req, &response); err != nil {
+					return nil, err
+				}
+				return &response, nil
+			})),
+		confluence: handlers.NewConfluenceHandler(func(req models.ConfluenceRequest) (*models.ConfluenceResponse, error) {
+			var response models.ConfluenceResponse
+			if err := bus.Call(context.Background(), memoryBus, bus.ConfluenceService, req.RequestID, req, &response); err != nil {
+				return nil, err
+			}
+			return &response, nil
+		}),
+		management:   handlers.NewManagementHandler(credStore),
+		notification: handlers.NewNotificationHandler(storage.NewNotificationChannelStoreFromEnv(credStore)),
+	}
+	backend.crossProduct = handlers.NewCrossProductHandler(backend.jira, backend.confluence)
+	backend.template = handlers.NewIssueTemplateHandler(storage.NewIssueTemplateStoreFromEnv(credStore), backend.jira)
+	backend.subscription = handlers.NewIssueSubscriptionHandler(storage.NewIssueSubscriptionStoreFromEnv(credStore),
+		storage.NewNotificationChannelStoreFromEnv(credStore), backend.jira)
+	backend.playbook = handlers.NewPlaybookHandler(storage.NewPlaybookStoreFromEnv(credStore)).
+		WithRunner(knownTools(), func(call mcp.ToolCall, _ string) (mcp.ToolResult, error) {
+			return backend.handleTool(call)
+		})
+	// Exports are written to EXPORTS_DIR and returned as file paths
+	exportStore, err := storage.NewExportStoreFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("export directory: %w", err)
+	}
+	backend.export = handlers.NewExportHandler(backend.jira, backend.confluence, exportStore, storage.ExportTTLFromEnv())
+	return backend, nil
+}
+
+func (b *directBackend) callTool(ctx context.Context, tool string, args map[string]interface{}) (json.RawMessage, error) {
+	result, err := b.handleTool(mcp.ToolCall{Name: tool, Arguments: args, RequestID: uuid.New().String()})
+	if result.IsError && len(result.Content) > 0 {
+		return nil, fmt.Errorf("%s", result.Content[0].Text)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Content) == 0 {
+		return json.RawMessage(`{"status":"success"}`), nil
+	}
+	text := result.Content[0].Text
+	if !json.Valid([]byte(text)) {
+		return json.Marshal(map[string]string{"result": text})
+	}
+	return json.RawMessage(text), nil
+}
+
+// handleTool routes a tool call to its handler
+func (b *directBackend) handleTool(call mcp.ToolCall) (mcp.ToolResult, error) {
+	switch tool := call.Name; {
+	case handlers.IsManagementTool(tool):
+		return b.management.HandleTool(call, b.userID)
+	case handlers.IsCrossProductTool(tool):
+		return b.crossProduct.HandleTool(call, b.userID)
+	case handlers.IsNotificationTool(tool):
+		return b.notification.HandleTool(call, b.userID)
+	case handlers.IsExportTool(tool):
+		return b.export.HandleTool(call, b.userID)
+	case handlers.IsIssueTemplateTool(tool):
+		return b.template.HandleTool(call, b.userID)
+	case handlers.IsPlaybookTool(tool):
+		return b.playbook.HandleTool(call, b.userID)
+	case handlers.IsIssueSubscriptionTool(tool):
+		return b.subscription.HandleTool(call, b.userID)
+	case strings.HasPrefix(tool, "confluence_"):
+		return b.confluence.HandleTool(call, b.userID)
+	case handlers.IsJiraServiceTool(tool):
+		return b.jira.HandleTool(call, b.userID)
+	default:
+		return mcp.ToolResult{}, fmt.Errorf("unknown tool: %s", tool)
+	}
+}
+
+func (b *directBackend) listWorkspaces(ctx context.Context) ([]client.WorkspaceDetails, error) {
+	workspaces, err := b.credStore.ListWorkspaces(b.userID)
+	if err != nil {
+		return nil, err
+	}
+	details := make([]client.WorkspaceDetails, 0, len(workspaces))
+	for _, ws := range workspaces {
+		details = append(details, client.WorkspaceDetails{
+			WorkspaceID:   ws.WorkspaceID,
+			WorkspaceName: ws.WorkspaceName,
+			SiteURL:       ws.AtlassianURL,
+			JiraURL:       ws.JiraURL,
+			ConfluenceURL: ws.ConfluenceURL,
+			Email:         ws.Email,
+			Policy:        ws.Policy,
+			CreatedAt:     ws.CreatedAt,
+			UpdatedAt:     ws.UpdatedAt,
+		})
+	}
+	return details, nil
+}
+
+func (b *directBackend) addWorkspace(ctx context.Context, req client.CreateWorkspaceRequest) (*client.WorkspaceDetails, error) {
+	site := req.SiteURL
+	if req.JiraURL != "" {
+		site = req.JiraURL
+	}
+	if req.ProxyURL != "" {
+		if _, err := atlassian.ParseProxyURL(req.ProxyURL); err != nil {
+			return nil, err
+		}
+	}
+	if err := atlassian.NewValidatorWithProxy(req.ProxyURL).ValidateToken(site, req.Email, req.APIToken); err != nil {
+		return nil, fmt.Errorf("atlassian connection failed: %w", err)
+	}
+	if req.WorkspaceName == "" {
+		req.WorkspaceName = req.SiteURL
+	}
+
+	now := time.Now()
+	cred := &models.AtlassianCredential{
+		UserID:        b.userID,
+		WorkspaceID:   uuid.New().String(),
+		WorkspaceName: req.WorkspaceName,
+		AtlassianURL:  req.SiteURL,
+		JiraURL:       req.JiraURL,
+		ConfluenceURL: req.ConfluenceURL,
+		Email:         req.Email,
+		APIToken:      req.APIToken,
+		Policy:        req.Policy,
+		OpsgenieKey:   req.OpsgenieAPIKey,
+		ProxyURL:      req.ProxyURL,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := b.credStore.SaveCredentials(cred); err != nil {
+		return nil, fmt.Errorf("save credentials: %w", err)
+	}
+	return &client.WorkspaceDetails{
+		WorkspaceID:   cred.WorkspaceID,
+		WorkspaceName: cred.WorkspaceName,
+		SiteURL:       cred.AtlassianURL,
+		JiraURL:       cred.JiraURL,
+		ConfluenceURL: cred.ConfluenceURL,
+		Email:         cred.Email,
+		Policy:        cred.Policy,
+		CreatedAt:     cred.CreatedAt,
+		UpdatedAt:     cred.UpdatedAt,
+	}, nil
+}
+
+func (b *directBackend) removeWorkspace(ctx context.Context, workspaceID string) error {
+	return b.credStore.DeleteCredentials(b.userID, workspaceID)
+}
+
+func (b *directBackend) close() {
+	b.credStore.Close()
+}
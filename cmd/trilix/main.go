@@ -0,0 +1,364 @@
+// Command trilix calls MCP tools and manages workspaces from the shell, either through
+// a running MCP server's REST API or directly against Atlassian (DIRECT_MODE).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/handlers"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/config"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/client"
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/mcp"
+)
+
+const usage = `Usage: trilix [flags] <command>
+
+Commands:
+  tools list                         List the available tools
+  tools show <tool>                  Print a tool's input schema
+  call <tool> [--<arg> <value>...]   Call a tool, e.g. trilix call jira_list_issues --workspace acme --jql 'project = OPS'
+  workspaces list                    List your workspaces
+  workspaces add --url <site> --email <email> [--name <name>] [--shared]
+                 [--jira-url <url>] [--confluence-url <url>] [--proxy <url>]
+                                     Add a workspace (the API token is read from ATLASSIAN_API_TOKEN,
+                                     an optional Opsgenie API key from OPSGENIE_API_KEY)
+  workspaces remove <workspace-id>   Delete a workspace
+
+Flags:
+`
+
+// backend executes commands against the MCP server or in this process
+type backend interface {
+	callTool(ctx context.Context, tool string, args map[string]interface{}) (json.RawMessage, error)
+	listWorkspaces(ctx context.Context) ([]client.WorkspaceDetails, error)
+	addWorkspace(ctx context.Context, req client.CreateWorkspaceRequest) (*client.WorkspaceDetails, error)
+	removeWorkspace(ctx context.Context, workspaceID string) error
+	close()
+}
+
+// errUsage reports a malformed command line
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	if os.Getenv("LOG_LEVEL") == "" {
+		// Only the command's output belongs on the terminal
+		os.Setenv("LOG_LEVEL", "warn")
+	}
+	logging.Setup("trilix")
+	config.LoadEnv(".env")
+	logging.Setup("trilix")
+
+	flags := flag.NewFlagSet("trilix", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	serverURL := flags.String("server", envOr("TRILIX_SERVER_URL", "http://localhost:3000"), "MCP server URL (TRILIX_SERVER_URL)")
+	token := flags.String("token", os.Getenv("TRILIX_TOKEN"), "API key, service token or JWT (TRILIX_TOKEN)")
+	userID := flags.String("user", os.Getenv("TRILIX_USER_ID"), "act as this user: with a service token, or the workspace owner in direct mode (TRILIX_USER_ID)")
+	direct := flags.Bool("direct", envBool("DIRECT_MODE"), "call Atlassian from this process using WORKSPACES_FILE or DATABASE_URL instead of a server (DIRECT_MODE)")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, flags.Args(), func() (backend, error) {
+		if *direct {
+			return newDirectBackend(*userID)
+		}
+		if *token == "" {
+			return nil, errors.New("set TRILIX_TOKEN (or --token), or use --direct")
+		}
+		return newRemoteBackend(*serverURL, *token, *userID), nil
+	})
+	if errors.Is(err, errUsage) {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "trilix:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes a command; connect is only called by commands that need a backend
+func run(ctx context.Context, args []string, connect func() (backend, error)) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	command, args := args[0], args[1:]
+
+	if command == "tools" {
+		return runTools(os.Stdout, args)
+	}
+	if command != "call" && command != "workspaces" {
+		return errUsage
+	}
+
+	b, err := connect()
+	if err != nil {
+		return err
+	}
+	defer b.close()
+
+	if command == "call" {
+		return runCall(ctx, b, args)
+	}
+	return runWorkspaces(ctx, b, args)
+}
+
+// knownTools lists the tools compiled into this binary; a server may disable some of them
+func knownTools() []mcp.Tool {
+	var tools []mcp.Tool
+	tools = append(tools, handlers.NewJiraHandler(nil).ListTools()...)
+	tools = append(tools, handlers.NewConfluenceHandler(nil).ListTools()...)
+	tools = append(tools, handlers.NewManagementHandler(nil).ListTools()...)
+	tools = append(tools, handlers.NewCrossProductHandler(nil, nil).ListTools()...)
+	tools = append(tools, handlers.NewNotificationHandler(nil).ListTools()...)
+	tools = append(tools, handlers.NewExportHandler(nil, nil, nil, 0).ListTools()...)
+	tools = append(tools, handlers.NewIssueTemplateHandler(nil, nil).ListTools()...)
+	tools = append(tools, handlers.NewPlaybookHandler(nil).ListTools()...)
+	tools = append(tools, handlers.NewIssueSubscriptionHandler(nil, nil, nil).ListTools()...)
+	return tools
+}
+
+func findTool(name string) (mcp.Tool, bool) {
+	for _, tool := range knownTools() {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return mcp.Tool{}, false
+}
+
+func runTools(out io.Writer, args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "TOOL\tDESCRIPTION")
+		for _, tool := range knownTools() {
+			description, _, _ := strings.Cut(tool.Description, ". ")
+			fmt.Fprintf(w, "%s\t%s\n", tool.Name, description)
+		}
+		return w.Flush()
+	case len(args) == 2 && args[0] == "show":
+		tool, ok := findTool(args[1])
+		if !ok {
+			return fmt.Errorf("unknown tool: %s", args[1])
+		}
+		return printJSON(out, tool)
+	default:
+		return errUsage
+	}
+}
+
+func runCall(ctx context.Context, b backend, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	tool, ok := findTool(args[0])
+	if !ok {
+		return fmt.Errorf("unknown tool: %s (see trilix tools list)", args[0])
+	}
+	arguments, err := parseToolArgs(tool, args[1:])
+	if err != nil {
+		return err
+	}
+
+	result, err := b.callTool(ctx, tool.Name, arguments)
+	var truncated *client.TruncatedError
+	if errors.As(err, &truncated) {
+		fmt.Fprintln(os.Stdout, truncated.Partial)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err := json.Unmarshal(result, &value); err != nil {
+		_, err = os.Stdout.Write(result)
+		return err
+	}
+	return printJSON(os.Stdout, value)
+}
+
+// parseToolArgs turns --name value pairs into tool arguments, converting each value to
+// the type in the tool's input schema. --workspace is short for --workspace_id, dashes
+// may replace underscores, and --args takes a JSON object of further arguments.
+func parseToolArgs(tool mcp.Tool, args []string) (map[string]interface{}, error) {
+	properties, _ := tool.InputSchema["properties"].(map[string]interface{})
+	arguments := make(map[string]interface{})
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			return nil, fmt.Errorf("unexpected argument %q; pass tool arguments as --name value", arg)
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("--%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		name = strings.ReplaceAll(name, "-", "_")
+
+		if name == "args" {
+			var extra map[string]interface{}
+			if err := json.Unmarshal([]byte(value), &extra); err != nil {
+				return nil, fmt.Errorf("--args must be a JSON object: %w", err)
+			}
+			for k, v := range extra {
+				arguments[k] = v
+			}
+			continue
+		}
+		if _, ok := properties[name]; !ok {
+			if _, ok := properties[name+"_id"]; ok {
+				name += "_id"
+			} else {
+				return nil, fmt.Errorf("%s has no argument %q (see trilix tools show %s)", tool.Name, name, tool.Name)
+			}
+		}
+
+		schema, _ := properties[name].(map[string]interface{})
+		converted, err := convertArg(schema, value)
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %w", name, err)
+		}
+		arguments[name] = converted
+	}
+
+	var missing []string
+	required, _ := tool.InputSchema["required"].([]string)
+	for _, name := range required {
+		if _, ok := arguments[name]; !ok {
+			missing = append(missing, "--"+name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s requires %s", tool.Name, strings.Join(missing, ", "))
+	}
+	return arguments, nil
+}
+
+// convertArg converts a command-line value to the JSON type of its schema
+func convertArg(schema map[string]interface{}, value string) (interface{}, error) {
+	switch schema["type"] {
+	case "number", "integer":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	case "array":
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			var list []interface{}
+			err := json.Unmarshal([]byte(value), &list)
+			return list, err
+		}
+		var list []interface{}
+		for _, item := range strings.Split(value, ",") {
+			list = append(list, strings.TrimSpace(item))
+		}
+		return list, nil
+	case "object":
+		var object map[string]interface{}
+		err := json.Unmarshal([]byte(value), &object)
+		return object, err
+	default:
+		return value, nil
+	}
+}
+
+func runWorkspaces(ctx context.Context, b backend, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "list":
+		workspaces, err := b.listWorkspaces(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSITE\tEMAIL")
+		for _, ws := range workspaces {
+			name := ws.WorkspaceName
+			if ws.Shared {
+				name += " (shared)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ws.WorkspaceID, name, ws.SiteURL, ws.Email)
+		}
+		return w.Flush()
+
+	case "add":
+		flags := flag.NewFlagSet("workspaces add", flag.ContinueOnError)
+		var req client.CreateWorkspaceRequest
+		flags.StringVar(&req.SiteURL, "url", "", "Atlassian site URL, e.g. https://acme.atlassian.net")
+		flags.StringVar(&req.JiraURL, "jira-url", "", "Jira base URL, if Jira is not at the site URL")
+		flags.StringVar(&req.ConfluenceURL, "confluence-url", "", "Confluence base URL, if Confluence is not at the site URL + /wiki")
+		flags.StringVar(&req.ProxyURL, "proxy", "", "proxy for this workspace's Atlassian calls (default HTTPS_PROXY)")
+		flags.StringVar(&req.Email, "email", "", "Atlassian account email")
+		flags.StringVar(&req.WorkspaceName, "name", "", "display name (defaults to the site URL)")
+		flags.BoolVar(&req.Shared, "shared", false, "create the workspace for your active organization")
+		if err := flags.Parse(args[1:]); err != nil {
+			return errUsage
+		}
+		req.APIToken = os.Getenv("ATLASSIAN_API_TOKEN")
+		req.OpsgenieAPIKey = os.Getenv("OPSGENIE_API_KEY")
+		if req.SiteURL == "" || req.Email == "" || req.APIToken == "" {
+			return errors.New("workspaces add requires --url, --email and ATLASSIAN_API_TOKEN")
+		}
+		workspace, err := b.addWorkspace(ctx, req)
+		if err != nil {
+			return err
+		}
+		return printJSON(os.Stdout, workspace)
+
+	case "remove":
+		if len(args) != 2 {
+			return errUsage
+		}
+		if err := b.removeWorkspace(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "Workspace %s deleted\n", args[1])
+		return nil
+
+	default:
+		return errUsage
+	}
+}
+
+func printJSON(out io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(v)
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func envBool(key string) bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(key))
+	return enabled
+}
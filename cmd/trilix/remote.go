@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/pkg/client"
+)
+
+// remoteBackend talks to a running MCP server through its REST API
+type remoteBackend struct {
+	client *client.Client
+}
+
+func newRemoteBackend(serverURL, token, userID string) *remoteBackend {
+	return &remoteBackend{client: client.New(serverURL, client.StaticToken(token)).WithUserID(userID)}
+}
+
+func (b *remoteBackend) callTool(ctx context.Context, tool string, args map[string]interface{}) (json.RawMessage, error) {
+	return b.client.CallTool(ctx, tool, args)
+}
+
+func (b *remoteBackend) listWorkspaces(ctx context.Context) ([]client.WorkspaceDetails, error) {
+	return b.client.Workspaces(ctx)
+}
+
+func (b *remoteBackend) addWorkspace(ctx context.Context, req client.CreateWorkspaceRequest) (*client.WorkspaceDetails, error) {
+	return b.client.CreateWorkspace(ctx, req)
+}
+
+func (b *remoteBackend) removeWorkspace(ctx context.Context, workspaceID string) error {
+	return b.client.DeleteWorkspace(ctx, workspaceID)
+}
+
+func (b *remoteBackend) close() {}
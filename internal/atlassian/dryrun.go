@@ -0,0 +1,132 @@
+package atlassian
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// DryRunParam is the argument of mutating tools that previews a change instead of making it
+const DryRunParam = "dry_run"
+
+// IsDryRun reports whether a request's parameters ask for a dry run
+func IsDryRun(params map[string]interface{}) bool {
+	dryRun, _ := params[DryRunParam].(bool)
+	return dryRun
+}
+
+// dryRunReadPaths are endpoints that are requested with POST but only read
+var dryRunReadPaths = []string{
+	"/rest/api/3/search/jql",
+}
+
+// DryRunRequest is a change a dry run would have sent to Atlassian
+type DryRunRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Body   interface{} `json:"body,omitempty"` // Decoded JSON, or a description of other content
+}
+
+// DryRunTransport sends reads to Atlassian and records changes instead of sending them,
+// answering each with an empty JSON object. Lookups the change depends on (fields, users,
+// transitions, the current page version) still run, so the recorded requests are the ones
+// a real call would send.
+type DryRunTransport struct {
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	requests []DryRunRequest
+}
+
+// NewDryRunTransport wraps base
+func NewDryRunTransport(base http.RoundTripper) *DryRunTransport {
+	return &DryRunTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *DryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead || dryRunRead(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	recorded := DryRunRequest{Method: req.Method, URL: req.URL.String()}
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		recorded.Body = dryRunBody(req.Header.Get("Content-Type"), data)
+	}
+	t.mu.Lock()
+	t.requests = append(t.requests, recorded)
+	t.mu.Unlock()
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(strings.NewReader("{}")),
+		ContentLength: 2,
+		Request:       req,
+	}, nil
+}
+
+// Requests returns the changes recorded so far
+func (t *DryRunTransport) Requests() []DryRunRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]DryRunRequest(nil), t.requests...)
+}
+
+func dryRunRead(req *http.Request) bool {
+	for _, path := range dryRunReadPaths {
+		if strings.HasSuffix(req.URL.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// dryRunBody decodes a JSON body; other content, e.g. an attachment, is only described
+func dryRunBody(contentType string, data []byte) interface{} {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var body interface{}
+	if strings.HasPrefix(contentType, "application/json") && json.Unmarshal(data, &body) == nil {
+		return body
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return fmt.Sprintf("%d bytes of %s", len(data), mediaType)
+}
+
+// DryRunResponse is the result of a dry run that got as far as sending changes: what it
+// would have sent. A handler that fails after its first change is cut short by the empty
+// response, so later changes that depend on it are not listed.
+func DryRunResponse(requests []DryRunRequest, response map[string]interface{}, requestID string) map[string]interface{} {
+	succeeded, _ := response["success"].(bool)
+	if len(requests) == 0 && !succeeded {
+		// Rejected before any change, e.g. an invalid argument or a missing issue
+		return response
+	}
+	preview := map[string]interface{}{
+		"dry_run":  true,
+		"requests": requests,
+		"message":  fmt.Sprintf("Nothing was changed. Without dry_run this call would send %d request(s) to Atlassian.", len(requests)),
+	}
+	if !succeeded && len(requests) > 0 {
+		preview["complete"] = false
+		preview["message"] = fmt.Sprintf("Nothing was changed. Without dry_run this call would send at least %d request(s) to Atlassian; later steps depend on Atlassian's responses and are not shown.", len(requests))
+	}
+	return models.SuccessResponse(preview, requestID)
+}
@@ -0,0 +1,141 @@
+package atlassian
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// ErrorBody is what an Atlassian error response says went wrong
+type ErrorBody struct {
+	Messages []string          `json:"messages,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"` // Jira's errors by field, e.g. "issuetype"
+}
+
+// ParseErrorBody finds the JSON error body in an API error message (the clients quote the
+// body after what failed, e.g. "failed to create issue: {...}") and extracts its messages.
+// It understands Jira's errorMessages and errors, Confluence's message and errors, and
+// Bitbucket's and Opsgenie's error messages. ok is false when there is no such body.
+func ParseErrorBody(message string) (ErrorBody, bool) {
+	start := strings.Index(message, "{")
+	if start < 0 {
+		return ErrorBody{}, false
+	}
+	var raw struct {
+		ErrorMessages []string        `json:"errorMessages"`
+		Errors        json.RawMessage `json:"errors"`
+		Message       string          `json:"message"`
+		Error         json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(message[start:]), &raw); err != nil {
+		return ErrorBody{}, false
+	}
+
+	var body ErrorBody
+	for _, m := range raw.ErrorMessages {
+		if m = strings.TrimSpace(m); m != "" {
+			body.Messages = append(body.Messages, m)
+		}
+	}
+	if raw.Message != "" {
+		body.Messages = append(body.Messages, raw.Message)
+	}
+
+	// Jira reports field errors as an object; Confluence's v2 API as a list
+	var fields map[string]interface{}
+	var list []struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	}
+	if json.Unmarshal(raw.Errors, &fields) == nil {
+		for field, v := range fields {
+			if s, ok := v.(string); ok && s != "" {
+				if body.Fields == nil {
+					body.Fields = make(map[string]string)
+				}
+				body.Fields[field] = s
+			}
+		}
+	} else if json.Unmarshal(raw.Errors, &list) == nil {
+		for _, e := range list {
+			if m := strings.TrimSpace(e.Title + " " + e.Detail); m != "" {
+				body.Messages = append(body.Messages, m)
+			}
+		}
+	}
+
+	var nested struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(raw.Error, &nested) == nil && nested.Message != "" {
+		body.Messages = append(body.Messages, nested.Message)
+	}
+	return body, len(body.Messages) > 0 || len(body.Fields) > 0
+}
+
+// FieldNames returns the fields an error body complains about, sorted
+func (b ErrorBody) FieldNames() []string {
+	names := make([]string, 0, len(b.Fields))
+	for name := range b.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnrichError adds to a failed Atlassian call's error the status Atlassian answered with,
+// a code matching it, whether retrying may help, the parsed error body (as Details) and
+// suggestions that hold for any action. status is the client's last failed status (0 when
+// the request got no answer). Errors the services raise themselves, such as invalid
+// parameters or policy violations, are left alone.
+func EnrichError(info *models.ErrorInfo, status int) {
+	if info == nil || (info.Code != models.ErrCodeAPIError && info.Code != models.ErrCodeAuthFailed) {
+		return
+	}
+	if body, ok := ParseErrorBody(info.Message); ok && info.Details == nil {
+		info.Details = body
+	}
+
+	if status == 0 {
+		if strings.Contains(info.Message, errRateLimitMessage) {
+			// The rate limit guard refused the call before it was sent
+			info.Code = models.ErrCodeRateLimited
+			info.Retriable = true
+			info.Suggestions = append(info.Suggestions, "This workspace's Atlassian rate limit budget is used up; retry after the time given, or check workspace_rate_status")
+			return
+		}
+		lower := strings.ToLower(info.Message)
+		if strings.Contains(lower, "timeout") || strings.Contains(lower, "deadline exceeded") {
+			info.Retriable = true
+			info.Suggestions = append(info.Suggestions, "Atlassian did not answer in time; retry, or ask for less (e.g. a lower limit or a narrower query)")
+		}
+		return
+	}
+
+	info.HTTPStatus = status
+	switch {
+	case status == http.StatusBadRequest:
+		info.Code = models.ErrCodeInvalidRequest
+	case status == http.StatusUnauthorized:
+		info.Code = models.ErrCodeAuthFailed
+		info.Suggestions = append(info.Suggestions, "Atlassian rejected the workspace's API token; replace it in the dashboard")
+	case status == http.StatusForbidden:
+		info.Code = models.ErrCodeForbidden
+		info.Suggestions = append(info.Suggestions, "The workspace's Atlassian account lacks permission for this; ask an Atlassian administrator, or use another workspace")
+	case status == http.StatusNotFound:
+		info.Code = models.ErrCodeNotFound
+		info.Suggestions = append(info.Suggestions, "Check the key or ID; the item may have been deleted, or the workspace's account may not be able to see it")
+	case status == http.StatusConflict:
+		info.Code = models.ErrCodeConflict
+	case status == http.StatusTooManyRequests:
+		info.Code = models.ErrCodeRateLimited
+		info.Retriable = true
+		info.Suggestions = append(info.Suggestions, "Atlassian is rate limiting this workspace; wait a minute before retrying")
+	case status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout:
+		info.Retriable = true
+		info.Suggestions = append(info.Suggestions, "Atlassian is temporarily unavailable; retry shortly")
+	}
+}
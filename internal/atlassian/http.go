@@ -0,0 +1,107 @@
+package atlassian
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	transportMu     sync.Mutex
+	rootCAs         *x509.CertPool                     // nil trusts the system roots only
+	envTransport    *http.Transport                    // Proxied as the environment says
+	proxyTransports = make(map[string]*http.Transport) // Keyed by workspace proxy URL
+)
+
+// SetCABundle makes the Atlassian clients trust the PEM certificates in path in addition
+// to the system roots, e.g. the CA of a TLS-inspecting proxy or of an on-premises server.
+// It is meant to be called once at startup; connections opened before keep their trust.
+func SetCABundle(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("CA bundle %s contains no PEM certificates", path)
+	}
+
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	rootCAs = pool
+	envTransport = nil
+	proxyTransports = make(map[string]*http.Transport)
+	return nil
+}
+
+// Transport returns the pooled transport for calls to Atlassian. Without a proxyURL the
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables decide; with one, every call
+// goes through that proxy. Transports are shared so each proxy keeps one connection pool.
+// The mock transport replaces them all when ATLASSIAN_MOCK_MODE is on, and calls are
+// recorded when DEBUG_RECORD is.
+func Transport(proxyURL string) http.RoundTripper {
+	if mock := MockTransportFromEnv(); mock != nil {
+		return withRecording(mock)
+	}
+	return withRecording(pooledTransport(proxyURL))
+}
+
+// pooledTransport returns the shared transport for a proxy, creating it on first use
+func pooledTransport(proxyURL string) *http.Transport {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	if proxyURL == "" {
+		if envTransport == nil {
+			envTransport = newTransport(http.ProxyFromEnvironment)
+		}
+		return envTransport
+	}
+	if t, ok := proxyTransports[proxyURL]; ok {
+		return t
+	}
+
+	// An invalid proxy fails every call rather than silently going direct
+	proxy, err := ParseProxyURL(proxyURL)
+	t := newTransport(func(*http.Request) (*url.URL, error) { return proxy, err })
+	proxyTransports[proxyURL] = t
+	return t
+}
+
+// newTransport builds a pooled transport trusting rootCAs. Callers must hold transportMu.
+func newTransport(proxy func(*http.Request) (*url.URL, error)) *http.Transport {
+	return &http.Transport{
+		Proxy:               proxy,
+		TLSClientConfig:     &tls.Config{RootCAs: rootCAs},
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// ParseProxyURL checks a workspace's proxy URL, e.g. "http://proxy.internal:3128"
+func ParseProxyURL(raw string) (*url.URL, error) {
+	// The URL may carry the proxy's password, so errors do not repeat it
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL")
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL: the scheme must be http, https or socks5")
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL: missing host")
+	}
+	return u, nil
+}
@@ -0,0 +1,233 @@
+package atlassian
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed mockdata/*/*.json
+var mockFixtures embed.FS
+
+// mockRoute maps an Atlassian REST call to a canned response. The first capture
+// group of pattern replaces {{id}} in the fixture, so e.g. GET issue/OPS-7 returns OPS-7.
+type mockRoute struct {
+	method  string
+	pattern *regexp.Regexp
+	status  int
+	fixture string // Under mockdata/; empty for no body
+}
+
+func route(method, pattern string, status int, fixture string) mockRoute {
+	return mockRoute{method: method, pattern: regexp.MustCompile("^" + pattern + "$"), status: status, fixture: fixture}
+}
+
+// mockRoutes covers every call made by the Jira, Confluence, Bitbucket and alert clients
+// and the validator. Confluence paths are matched without their /wiki prefix.
+var mockRoutes = []mockRoute{
+	route("GET", `/rest/api/[23]/myself`, http.StatusOK, "jira/myself.json"),
+
+	route("POST", `/rest/api/3/search/jql`, http.StatusOK, "jira/search.json"),
+	route("GET", `/rest/api/[23]/issue/([^/]+)`, http.StatusOK, "jira/issue.json"),
+	route("POST", `/rest/api/3/issue`, http.StatusCreated, "jira/issue_created.json"),
+	route("PUT", `/rest/api/[23]/issue/([^/]+)`, http.StatusNoContent, ""),
+	route("DELETE", `/rest/api/[23]/issue/([^/]+)`, http.StatusNoContent, ""),
+	route("POST", `/rest/api/3/issue/([^/]+)/comment`, http.StatusCreated, "jira/comment.json"),
+	route("GET", `/rest/api/[23]/issue/([^/]+)/transitions`, http.StatusOK, "jira/transitions.json"),
+	route("GET", `/rest/api/3/issue/([^/]+)/changelog`, http.StatusOK, "jira/changelog.json"),
+	route("POST", `/rest/api/[23]/issue/([^/]+)/transitions`, http.StatusNoContent, ""),
+	route("GET", `/rest/api/[23]/issue/([^/]+)/worklog`, http.StatusOK, "jira/worklogs.json"),
+	route("POST", `/rest/api/[23]/issue/([^/]+)/worklog`, http.StatusCreated, "jira/worklog.json"),
+	route("GET", `/rest/api/3/project`, http.StatusOK, "jira/projects.json"),
+	route("GET", `/rest/api/2/project/([^/]+)/versions`, http.StatusOK, "jira/versions.json"),
+	route("GET", `/rest/api/3/user/search`, http.StatusOK, "jira/users.json"),
+	route("GET", `/rest/api/3/user`, http.StatusOK, "jira/user.json"),
+	route("GET", `/rest/api/3/users/search`, http.StatusOK, "jira/users.json"),
+	route("GET", `/rest/api/3/group/bulk`, http.StatusOK, "jira/groups.json"),
+	route("GET", `/rest/api/3/groups/picker`, http.StatusOK, "jira/groups_picker.json"),
+	route("GET", `/rest/api/3/group/member`, http.StatusOK, "jira/group_members.json"),
+	route("GET", `/rest/api/3/field`, http.StatusOK, "jira/fields.json"),
+	route("POST", `/rest/api/3/issueLink`, http.StatusCreated, ""),
+	route("DELETE", `/rest/api/3/issueLink/([^/]+)`, http.StatusNoContent, ""),
+	route("GET", `/rest/agile/1.0/board`, http.StatusOK, "jira/boards.json"),
+	route("GET", `/rest/agile/1.0/board/([^/]+)/issue`, http.StatusOK, "jira/search.json"),
+	route("GET", `/rest/agile/1.0/board/([^/]+)/sprint`, http.StatusOK, "jira/sprints.json"),
+	route("GET", `/rest/agile/1.0/board/([^/]+)/configuration`, http.StatusOK, "jira/board_configuration.json"),
+	route("GET", `/rest/agile/1.0/sprint/([^/]+)`, http.StatusOK, "jira/sprint_active.json"),
+	route("GET", `/rest/agile/1.0/sprint/([^/]+)/issue`, http.StatusOK, "jira/search.json"),
+	route("POST", `/rest/agile/1.0/sprint`, http.StatusCreated, "jira/sprint.json"),
+	route("PUT", `/rest/agile/1.0/sprint/([^/]+)`, http.StatusOK, "jira/sprint.json"),
+
+	route("GET", `/rest/api/content/search`, http.StatusOK, "confluence/search.json"),
+	route("GET", `/rest/api/content/([^/]+)`, http.StatusOK, "confluence/page.json"),
+	route("POST", `/rest/api/content`, http.StatusOK, "confluence/page_created.json"),
+	route("PUT", `/rest/api/content/([^/]+)`, http.StatusOK, "confluence/page.json"),
+	route("DELETE", `/rest/api/content/([^/]+)`, http.StatusNoContent, ""),
+	route("GET", `/rest/api/content/([^/]+)/child/page`, http.StatusOK, "confluence/children.json"),
+	route("GET", `/rest/api/content/([^/]+)/child/comment`, http.StatusOK, "confluence/comments.json"),
+	route("GET", `/rest/api/content/([^/]+)/child/attachment`, http.StatusOK, "confluence/attachments.json"),
+	route("PUT", `/rest/api/content/([^/]+)/child/attachment`, http.StatusOK, "confluence/attachments.json"),
+	route("GET", `/rest/api/content/([^/]+)/label`, http.StatusOK, "confluence/labels.json"),
+	route("POST", `/rest/api/content/([^/]+)/label`, http.StatusOK, "confluence/labels.json"),
+	route("GET", `/rest/api/space`, http.StatusOK, "confluence/spaces.json"),
+	route("GET", `/rest/api/space/([^/]+)`, http.StatusOK, "confluence/space.json"),
+	route("GET", `/rest/api/search/user`, http.StatusOK, "confluence/users.json"),
+
+	route("GET", `/2.0/repositories/[^/]+`, http.StatusOK, "bitbucket/repositories.json"),
+	route("GET", `/2.0/repositories/[^/]+/[^/]+/pullrequests`, http.StatusOK, "bitbucket/pullrequests.json"),
+	route("GET", `/2.0/repositories/[^/]+/[^/]+/pullrequests/([^/]+)`, http.StatusOK, "bitbucket/pullrequest.json"),
+	route("GET", `/2.0/repositories/[^/]+/[^/]+/pullrequests/([^/]+)/comments`, http.StatusOK, "bitbucket/comments.json"),
+	route("GET", `/2.0/repositories/[^/]+/[^/]+/pipelines/?`, http.StatusOK, "bitbucket/pipelines.json"),
+
+	// Opsgenie (/v2) and Jira Service Management Operations (/jsm/ops/api/{cloudId}/v1)
+	route("GET", `/_edge/tenant_info`, http.StatusOK, "opsgenie/tenant_info.json"),
+	route("GET", `(?:/v2|/jsm/ops/api/[^/]+/v1)/alerts`, http.StatusOK, "opsgenie/alerts.json"),
+	route("POST", `(?:/v2|/jsm/ops/api/[^/]+/v1)/alerts`, http.StatusAccepted, "opsgenie/request.json"),
+	route("POST", `(?:/v2|/jsm/ops/api/[^/]+/v1)/alerts/([^/]+)/(?:acknowledge|close)`, http.StatusAccepted, "opsgenie/request.json"),
+}
+
+// MockTransport serves canned Atlassian responses from embedded fixtures, with
+// optional latency and injected errors, instead of calling Atlassian
+type MockTransport struct {
+	minLatency  time.Duration
+	maxLatency  time.Duration
+	errorRate   float64 // Fraction of calls answered with errorStatus
+	errorStatus int
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+var (
+	mockOnce      sync.Once
+	mockTransport *MockTransport
+)
+
+// MockTransportFromEnv returns the mock transport when ATLASSIAN_MOCK_MODE is true,
+// otherwise nil. ATLASSIAN_MOCK_LATENCY ("100ms" or a range such as "50ms-250ms"),
+// ATLASSIAN_MOCK_ERROR_RATE (0-1) and ATLASSIAN_MOCK_ERROR_STATUS (default 503)
+// shape its responses. The environment is read once.
+func MockTransportFromEnv() *MockTransport {
+	mockOnce.Do(func() {
+		if enabled, _ := strconv.ParseBool(os.Getenv("ATLASSIAN_MOCK_MODE")); !enabled {
+			return
+		}
+		t := &MockTransport{errorStatus: http.StatusServiceUnavailable, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+		if v := os.Getenv("ATLASSIAN_MOCK_LATENCY"); v != "" {
+			low, high, _ := strings.Cut(v, "-")
+			t.minLatency, _ = time.ParseDuration(strings.TrimSpace(low))
+			t.maxLatency = t.minLatency
+			if high != "" {
+				t.maxLatency, _ = time.ParseDuration(strings.TrimSpace(high))
+			}
+			if t.maxLatency < t.minLatency {
+				t.maxLatency = t.minLatency
+			}
+		}
+		if v, err := strconv.ParseFloat(os.Getenv("ATLASSIAN_MOCK_ERROR_RATE"), 64); err == nil && v > 0 {
+			t.errorRate = v
+		}
+		if v, err := strconv.Atoi(os.Getenv("ATLASSIAN_MOCK_ERROR_STATUS")); err == nil && v >= 400 {
+			t.errorStatus = v
+		}
+		slog.Warn("ATLASSIAN_MOCK_MODE is on: Atlassian calls return canned responses",
+			"latency", latencyRange(t.minLatency, t.maxLatency), "error_rate", t.errorRate, "error_status", t.errorStatus)
+		mockTransport = t
+	})
+	return mockTransport
+}
+
+// latencyRange formats a latency range for logging
+func latencyRange(low, high time.Duration) string {
+	if low == high {
+		return low.String()
+	}
+	return low.String() + "-" + high.String()
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	delay, fail := t.sample()
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+	if fail {
+		return mockResponse(req, t.errorStatus, []byte(`{"errorMessages":["Injected error from ATLASSIAN_MOCK_MODE"],"errors":{}}`)), nil
+	}
+
+	urlPath := strings.TrimPrefix(req.URL.Path, "/wiki")
+	for _, r := range mockRoutes {
+		if r.method != req.Method {
+			continue
+		}
+		match := r.pattern.FindStringSubmatch(urlPath)
+		if match == nil {
+			continue
+		}
+		if r.fixture == "" {
+			return mockResponse(req, r.status, nil), nil
+		}
+		body, err := mockFixtures.ReadFile(path.Join("mockdata", r.fixture))
+		if err != nil {
+			return nil, err
+		}
+		if len(match) > 1 {
+			body = bytes.ReplaceAll(body, []byte("{{id}}"), []byte(match[1]))
+		}
+		return mockResponse(req, r.status, body), nil
+	}
+
+	body := fmt.Sprintf(`{"errorMessages":["No mock fixture for %s %s"],"errors":{}}`, req.Method, urlPath)
+	return mockResponse(req, http.StatusNotFound, []byte(body)), nil
+}
+
+// sample draws the latency and whether to inject an error for one call
+func (t *MockTransport) sample() (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delay := t.minLatency
+	if t.maxLatency > t.minLatency {
+		delay += time.Duration(t.rand.Int63n(int64(t.maxLatency - t.minLatency)))
+	}
+	return delay, t.errorRate > 0 && t.rand.Float64() < t.errorRate
+}
+
+func mockResponse(req *http.Request, status int, body []byte) *http.Response {
+	header := make(http.Header)
+	if body != nil {
+		header.Set("Content-Type", "application/json")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,298 @@
+package atlassian
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/storage"
+)
+
+// Atlassian's OAuth 2.0 (3LO) endpoints, and the API gateway that 3LO tokens are used on
+const (
+	oauthAuthorizeURL = "https://auth.atlassian.com/authorize"
+	oauthTokenURL     = "https://auth.atlassian.com/oauth/token"
+	gatewayURL        = "https://api.atlassian.com/ex"
+)
+
+// DefaultOAuthScopes are requested unless ATLASSIAN_OAUTH_SCOPES says otherwise.
+// offline_access is what grants a refresh token.
+const DefaultOAuthScopes = "read:jira-work write:jira-work read:jira-user " +
+	"read:confluence-content.all read:confluence-content.summary read:confluence-space.summary " +
+	"write:confluence-content search:confluence offline_access"
+
+// oauthStateTTL bounds how long a user may take to grant access
+const oauthStateTTL = 10 * time.Minute
+
+// refreshMargin is how long before they expire access tokens are refreshed
+const refreshMargin = time.Minute
+
+// ErrUserNotConnected is returned for users who have not granted the server access to
+// their Atlassian account, or whose grant was revoked
+var ErrUserNotConnected = errors.New("connect your Atlassian account first: this workspace calls Atlassian as each user (POST /api/atlassian/connect)")
+
+// OAuthConfig is the server's Atlassian OAuth 2.0 (3LO) app, which users grant access to
+// their Atlassian account so calls respect their own permissions
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string // Also signs the state of pending grants
+	RedirectURL  string // The server's /api/atlassian/callback, as registered with the app
+	Scopes       string
+}
+
+// OAuthConfigFromEnv reads the OAuth app from ATLASSIAN_OAUTH_CLIENT_ID,
+// ATLASSIAN_OAUTH_CLIENT_SECRET, ATLASSIAN_OAUTH_REDIRECT_URL and ATLASSIAN_OAUTH_SCOPES.
+// It returns nil when no client ID is set.
+func OAuthConfigFromEnv() (*OAuthConfig, error) {
+	config := &OAuthConfig{
+		ClientID:     os.Getenv("ATLASSIAN_OAUTH_CLIENT_ID"),
+		ClientSecret: os.Getenv("ATLASSIAN_OAUTH_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("ATLASSIAN_OAUTH_REDIRECT_URL"),
+		Scopes:       os.Getenv("ATLASSIAN_OAUTH_SCOPES"),
+	}
+	if config.ClientID == "" {
+		return nil, nil
+	}
+	if config.ClientSecret == "" || config.RedirectURL == "" {
+		return nil, errors.New("ATLASSIAN_OAUTH_CLIENT_SECRET and ATLASSIAN_OAUTH_REDIRECT_URL are required with ATLASSIAN_OAUTH_CLIENT_ID")
+	}
+	if config.Scopes == "" {
+		config.Scopes = DefaultOAuthScopes
+	}
+	return config, nil
+}
+
+// oauthState is what the state of a pending grant carries
+type oauthState struct {
+	UserID    string `json:"u"`
+	ExpiresAt int64  `json:"e"`
+	Nonce     string `json:"n"`
+}
+
+// AuthorizeURL returns where a user grants the app access. Its state names the user and is
+// signed, so the callback knows whose grant it receives.
+func (c *OAuthConfig) AuthorizeURL(userID string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(oauthState{
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(oauthStateTTL).Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+	})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	query := url.Values{
+		"audience":      {"api.atlassian.com"},
+		"client_id":     {c.ClientID},
+		"scope":         {c.Scopes},
+		"redirect_uri":  {c.RedirectURL},
+		"state":         {encoded + "." + c.sign(encoded)},
+		"response_type": {"code"},
+		"prompt":        {"consent"},
+	}
+	return oauthAuthorizeURL + "?" + query.Encode(), nil
+}
+
+// VerifyState checks the state a callback received and returns the user it names
+func (c *OAuthConfig) VerifyState(state string) (string, error) {
+	encoded, signature, ok := strings.Cut(state, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(c.sign(encoded))) {
+		return "", errors.New("invalid state")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.New("invalid state")
+	}
+	var s oauthState
+	if err := json.Unmarshal(payload, &s); err != nil || s.UserID == "" {
+		return "", errors.New("invalid state")
+	}
+	if time.Now().Unix() > s.ExpiresAt {
+		return "", errors.New("the authorization expired; start again")
+	}
+	return s.UserID, nil
+}
+
+func (c *OAuthConfig) sign(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(c.ClientSecret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Exchange trades the code a callback received for the user's tokens
+func (c *OAuthConfig) Exchange(ctx context.Context, userID, code string) (*models.AtlassianUserToken, error) {
+	return c.tokenRequest(ctx, userID, map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": c.RedirectURL,
+	})
+}
+
+// Refresh trades a user's refresh token for new tokens
+func (c *OAuthConfig) Refresh(ctx context.Context, userID, refreshToken string) (*models.AtlassianUserToken, error) {
+	return c.tokenRequest(ctx, userID, map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": refreshToken,
+	})
+}
+
+func (c *OAuthConfig) tokenRequest(ctx context.Context, userID string, params map[string]string) (*models.AtlassianUserToken, error) {
+	params["client_id"] = c.ClientID
+	params["client_secret"] = c.ClientSecret
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, oauthTokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := oauthClient("").Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("Atlassian token request failed (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var grant struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		Scope        string `json:"scope"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&grant); err != nil {
+		return nil, err
+	}
+	if grant.AccessToken == "" || grant.RefreshToken == "" {
+		return nil, errors.New("Atlassian granted no refresh token; the app needs the offline_access scope")
+	}
+	return &models.AtlassianUserToken{
+		UserID:       userID,
+		AccessToken:  grant.AccessToken,
+		RefreshToken: grant.RefreshToken,
+		Scope:        grant.Scope,
+		ExpiresAt:    time.Now().Add(time.Duration(grant.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func oauthClient(proxyURL string) *http.Client {
+	return &http.Client{Timeout: 30 * time.Second, Transport: Transport(proxyURL)}
+}
+
+// UserTokens gives the services the access tokens users granted, refreshing them as they
+// expire. Refreshes of one user's token are serialized, because each one rotates the
+// refresh token.
+type UserTokens struct {
+	store storage.UserTokenStoreInterface
+	oauth *OAuthConfig
+
+	locks sync.Map // User ID -> *sync.Mutex
+}
+
+// NewUserTokens creates a token source. store is nil with file-based storage and oauth is
+// nil without an OAuth app; either way, user OAuth workspaces cannot be used.
+func NewUserTokens(store storage.UserTokenStoreInterface, oauth *OAuthConfig) *UserTokens {
+	return &UserTokens{store: store, oauth: oauth}
+}
+
+// AccessToken returns a current access token of the user
+func (t *UserTokens) AccessToken(ctx context.Context, userID string) (string, error) {
+	if t == nil || t.store == nil || t.oauth == nil {
+		return "", errors.New("this workspace calls Atlassian as each user, which requires database storage (DATABASE_URL) and an Atlassian OAuth app (ATLASSIAN_OAUTH_CLIENT_ID)")
+	}
+	if userID == "" {
+		return "", ErrUserNotConnected
+	}
+
+	token, err := t.store.GetUserToken(userID)
+	if err == storage.ErrNotFound {
+		return "", ErrUserNotConnected
+	}
+	if err != nil {
+		return "", err
+	}
+	if time.Until(token.ExpiresAt) > refreshMargin {
+		return token.AccessToken, nil
+	}
+
+	lock, _ := t.locks.LoadOrStore(userID, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	// Another call may have refreshed it while this one waited
+	if token, err = t.store.GetUserToken(userID); err != nil {
+		return "", err
+	}
+	if time.Until(token.ExpiresAt) > refreshMargin {
+		return token.AccessToken, nil
+	}
+	refreshed, err := t.oauth.Refresh(ctx, userID, token.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("%w (%v)", ErrUserNotConnected, err)
+	}
+	if err := t.store.SaveUserToken(refreshed); err != nil {
+		return "", err
+	}
+	return refreshed.AccessToken, nil
+}
+
+// cloudIDs caches the Atlassian cloud ID of each site, which never changes
+var cloudIDs sync.Map
+
+// GatewayURL returns the API gateway URL through which 3LO tokens call product ("jira" or
+// "confluence") on an Atlassian Cloud site, e.g. https://api.atlassian.com/ex/jira/{cloudId}.
+// The site's cloud ID is looked up through the workspace's proxy, if it has one.
+func GatewayURL(ctx context.Context, product, site, proxyURL string) (string, error) {
+	site = strings.TrimSuffix(strings.TrimSuffix(site, "/"), "/wiki")
+	if id, ok := cloudIDs.Load(site); ok {
+		return fmt.Sprintf("%s/%s/%s", gatewayURL, product, id), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, site+"/_edge/tenant_info", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := oauthClient(proxyURL).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var tenant struct {
+		CloudID string `json:"cloudId"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&tenant) != nil || tenant.CloudID == "" {
+		return "", fmt.Errorf("failed to get the cloud ID of %s: user OAuth only works with Atlassian Cloud sites", site)
+	}
+	cloudIDs.Store(site, tenant.CloudID)
+	return fmt.Sprintf("%s/%s/%s", gatewayURL, product, tenant.CloudID), nil
+}
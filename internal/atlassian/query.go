@@ -0,0 +1,47 @@
+package atlassian
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var orderByPattern = regexp.MustCompile(`(?i)\s+order\s+by\s+`)
+
+// ScopeQuery restricts a JQL or CQL query to the given values of a field
+// (e.g., field "project" for JQL or "space" for CQL). Any trailing ORDER BY
+// clause is kept outside the grouping so the query stays valid.
+func ScopeQuery(query, field string, values []string) string {
+	if len(values) == 0 {
+		return query
+	}
+
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	scope := fmt.Sprintf("%s in (%s)", field, strings.Join(quoted, ", "))
+
+	base, orderBy := SplitOrderBy(query)
+	scoped := scope
+	if base != "" {
+		scoped = fmt.Sprintf("%s AND (%s)", scope, base)
+	}
+	if orderBy != "" {
+		scoped += " " + orderBy
+	}
+	return scoped
+}
+
+// SplitOrderBy splits a JQL or CQL query into its condition and its trailing ORDER BY
+// clause, either of which may be empty
+func SplitOrderBy(query string) (string, string) {
+	// Prefix a space so a query that is only "ORDER BY ..." still matches
+	padded := " " + strings.TrimSpace(query)
+	loc := orderByPattern.FindAllStringIndex(padded, -1)
+	if len(loc) == 0 {
+		return strings.TrimSpace(padded), ""
+	}
+	start := loc[len(loc)-1][0]
+	return strings.TrimSpace(padded[:start]), strings.TrimSpace(padded[start:])
+}
@@ -0,0 +1,277 @@
+package atlassian
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
+)
+
+// RateStatusAction asks a Jira or Confluence service for the rate limit budget it has
+// seen for a workspace
+const RateStatusAction = "rate_status"
+
+// Defaults for the rate limit guard, when the environment does not set them
+const (
+	DefaultThrottleBelow   = 0.1
+	DefaultThrottleSpacing = time.Second
+	DefaultMaxRateWait     = 30 * time.Second
+
+	// defaultRetryAfter is how long calls pause after a 429 that does not say
+	defaultRetryAfter = 10 * time.Second
+)
+
+// errRateLimitMessage starts the error of a call the guard refused, so EnrichError can
+// tell it from other failures that got no answer
+const errRateLimitMessage = "Atlassian rate limit budget exhausted"
+
+// RateBudget is what Atlassian's rate limit headers last said about a workspace, and how
+// the guard has held back calls because of it
+type RateBudget struct {
+	Limit        int        `json:"limit,omitempty"`     // X-RateLimit-Limit
+	Remaining    *int       `json:"remaining,omitempty"` // X-RateLimit-Remaining
+	ResetAt      *time.Time `json:"reset_at,omitempty"`  // X-RateLimit-Reset
+	NearLimit    bool       `json:"near_limit"`          // X-RateLimit-NearLimit, or Remaining below the throttle threshold
+	BlockedUntil *time.Time `json:"blocked_until,omitempty"`
+	Throttled    bool       `json:"throttled"` // Calls are being spaced out or held
+	RateLimited  int64      `json:"rate_limited_responses"`
+	Delayed      int64      `json:"delayed_calls"`
+	Rejected     int64      `json:"rejected_calls"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"` // When Atlassian last answered; nil when it has not yet
+}
+
+// RateLimitError is the error of a call the guard refused because the workspace's budget
+// would not allow it before the call's deadline
+type RateLimitError struct {
+	Until time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s for this workspace; retry after %s", errRateLimitMessage, e.Until.UTC().Format(time.RFC3339))
+}
+
+// rateGuardConfig is how the guard paces calls
+type rateGuardConfig struct {
+	below   float64       // Pace calls once less than this fraction of the budget is left
+	spacing time.Duration // Time between paced calls
+	maxWait time.Duration // Longest a call is held before it is refused
+}
+
+var (
+	rateConfigOnce sync.Once
+	rateConfig     rateGuardConfig
+
+	rateMu     sync.Mutex
+	rateStates = make(map[string]*rateState) // By workspace ID
+)
+
+// rateGuardFromEnv reads ATLASSIAN_THROTTLE_BELOW (the fraction of the budget left at
+// which calls start being spaced out), ATLASSIAN_THROTTLE_SPACING (the time between them)
+// and ATLASSIAN_RATE_LIMIT_MAX_WAIT (how long a call may be held before it fails). The
+// environment is read once.
+func rateGuardFromEnv() rateGuardConfig {
+	rateConfigOnce.Do(func() {
+		rateConfig = rateGuardConfig{below: DefaultThrottleBelow, spacing: DefaultThrottleSpacing, maxWait: DefaultMaxRateWait}
+		if v, err := strconv.ParseFloat(os.Getenv("ATLASSIAN_THROTTLE_BELOW"), 64); err == nil && v >= 0 && v < 1 {
+			rateConfig.below = v
+		}
+		if d, err := time.ParseDuration(os.Getenv("ATLASSIAN_THROTTLE_SPACING")); err == nil && d >= 0 {
+			rateConfig.spacing = d
+		}
+		if d, err := time.ParseDuration(os.Getenv("ATLASSIAN_RATE_LIMIT_MAX_WAIT")); err == nil && d >= 0 {
+			rateConfig.maxWait = d
+		}
+	})
+	return rateConfig
+}
+
+// rateState is the guard's view of one workspace's budget
+type rateState struct {
+	workspaceID string
+	mu          sync.Mutex
+	budget      RateBudget
+	next        time.Time // When the next paced call may be sent
+}
+
+// stateFor returns a workspace's rate state, creating it on first use
+func stateFor(workspaceID string) *rateState {
+	rateMu.Lock()
+	defer rateMu.Unlock()
+	state, ok := rateStates[workspaceID]
+	if !ok {
+		state = &rateState{workspaceID: workspaceID}
+		rateStates[workspaceID] = state
+	}
+	return state
+}
+
+// RateStatus returns the rate limit budget this process has seen for a workspace
+func RateStatus(workspaceID string) RateBudget {
+	state := stateFor(workspaceID)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	budget := state.budget
+	now := time.Now()
+	if budget.BlockedUntil != nil && !budget.BlockedUntil.After(now) {
+		budget.BlockedUntil = nil
+	}
+	if budget.ResetAt != nil && !budget.ResetAt.After(now) {
+		// The budget has been refilled since Atlassian last reported it
+		budget.Remaining, budget.ResetAt, budget.NearLimit = nil, nil, false
+	}
+	budget.Throttled = budget.BlockedUntil != nil || budget.NearLimit
+	return budget
+}
+
+// RateLimitTransport holds back a workspace's calls to Atlassian as its rate limit budget
+// runs low: once less than ATLASSIAN_THROTTLE_BELOW of it is left, calls are spaced out,
+// and while it is exhausted or Atlassian has answered 429, calls wait for it to refill.
+// A call that would wait past its deadline or ATLASSIAN_RATE_LIMIT_MAX_WAIT fails at once
+// with a RateLimitError instead. Every client of a workspace in the process shares its
+// budget.
+type RateLimitTransport struct {
+	base  http.RoundTripper
+	state *rateState
+}
+
+// NewRateLimitTransport guards a workspace's calls through base. Without a workspace ID,
+// calls are not tracked and base is returned as it is.
+func NewRateLimitTransport(workspaceID string, base http.RoundTripper) http.RoundTripper {
+	if workspaceID == "" {
+		return base
+	}
+	return &RateLimitTransport{base: base, state: stateFor(workspaceID)}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.state.wait(req); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.state.observe(resp)
+	return resp, nil
+}
+
+// wait holds a call until the budget allows it, or refuses it
+func (s *rateState) wait(req *http.Request) error {
+	config := rateGuardFromEnv()
+	now := time.Now()
+
+	s.mu.Lock()
+	var until time.Time
+	paced := false
+	switch {
+	case s.budget.BlockedUntil != nil && s.budget.BlockedUntil.After(now):
+		until = *s.budget.BlockedUntil
+	case s.budget.Remaining != nil && *s.budget.Remaining <= 0 && s.budget.ResetAt != nil && s.budget.ResetAt.After(now):
+		until = *s.budget.ResetAt
+	case s.nearLimit(now):
+		until, paced = s.next, true
+		if until.Before(now) {
+			until = now
+		}
+	}
+	if !until.After(now) {
+		if paced {
+			s.next = now.Add(config.spacing)
+		}
+		s.mu.Unlock()
+		return nil
+	}
+
+	deadline, hasDeadline := req.Context().Deadline()
+	if until.Sub(now) > config.maxWait || (hasDeadline && until.After(deadline)) {
+		s.budget.Rejected++
+		s.mu.Unlock()
+		metrics.AtlassianThrottledCalls.Inc(s.workspaceID, "rejected")
+		return &RateLimitError{Until: until}
+	}
+	if paced {
+		s.next = until.Add(config.spacing)
+	}
+	s.budget.Delayed++
+	s.mu.Unlock()
+	metrics.AtlassianThrottledCalls.Inc(s.workspaceID, "delayed")
+
+	timer := time.NewTimer(until.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// nearLimit reports whether calls should be spaced out. Callers must hold s.mu.
+func (s *rateState) nearLimit(now time.Time) bool {
+	if s.budget.ResetAt != nil && !s.budget.ResetAt.After(now) {
+		return false
+	}
+	return s.budget.NearLimit
+}
+
+// observe records the budget a response reports
+func (s *rateState) observe(resp *http.Response) {
+	config := rateGuardFromEnv()
+	now := time.Now()
+	header := resp.Header
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.budget.UpdatedAt = &now
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		s.budget.Limit = limit
+	}
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil {
+		s.budget.Remaining = &remaining
+		metrics.AtlassianRateLimitRemaining.Set(float64(remaining), s.workspaceID)
+	}
+	if reset, ok := parseRateLimitReset(header.Get("X-RateLimit-Reset")); ok {
+		s.budget.ResetAt = &reset
+	}
+	near, _ := strconv.ParseBool(header.Get("X-RateLimit-NearLimit"))
+	if s.budget.Remaining != nil && s.budget.Limit > 0 {
+		near = near || float64(*s.budget.Remaining) < config.below*float64(s.budget.Limit)
+	}
+	s.budget.NearLimit = near
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		s.budget.RateLimited++
+		blocked := now.Add(retryAfter(header.Get("Retry-After"), now))
+		s.budget.BlockedUntil = &blocked
+		slog.Warn("Atlassian rate limited a workspace; holding its calls",
+			"workspace_id", s.workspaceID, "until", blocked.UTC().Format(time.RFC3339))
+	}
+}
+
+// retryAfter reads a Retry-After header, in seconds or as an HTTP date
+func retryAfter(value string, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return defaultRetryAfter
+}
+
+// parseRateLimitReset reads X-RateLimit-Reset, an ISO 8601 time such as 2025-06-01T12:30Z
+func parseRateLimitReset(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
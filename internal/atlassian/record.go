@@ -0,0 +1,204 @@
+package atlassian
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+)
+
+// RecordingsAction asks a Jira or Confluence service for the calls it has recorded
+const RecordingsAction = "debug_recordings"
+
+// DefaultRecordLimit is how many calls are kept when DEBUG_RECORD_LIMIT is not set
+const DefaultRecordLimit = 100
+
+// maxRecordedBody bounds each recorded request and response body
+const maxRecordedBody = 64 << 10
+
+// secretHeaders are never recorded, whatever their value looks like
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// RecordedCall is a sanitized copy of one call to Atlassian, kept while DEBUG_RECORD is on
+type RecordedCall struct {
+	Time            time.Time         `json:"time"`
+	RequestID       string            `json:"request_id,omitempty"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	Status          int               `json:"status,omitempty"`
+	DurationMS      int64             `json:"duration_ms"` // Until the response headers arrived
+	Error           string            `json:"error,omitempty"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    string            `json:"response_body,omitempty"` // As far as the caller read it
+	Truncated       bool              `json:"truncated,omitempty"`     // A body was longer than 64 KiB
+}
+
+// recording is a call in the ring buffer. The response body fills in as it is read.
+type recording struct {
+	call         RecordedCall
+	responseBody bytes.Buffer
+}
+
+// recorder is the ring buffer of the latest calls; nil while recording is off
+var recorder struct {
+	once    sync.Once
+	mu      sync.Mutex
+	limit   int
+	entries []*recording
+	next    int
+}
+
+// recordLimit reads DEBUG_RECORD and DEBUG_RECORD_LIMIT once, returning 0 when recording is off
+func recordLimit() int {
+	recorder.once.Do(func() {
+		if enabled, _ := strconv.ParseBool(os.Getenv("DEBUG_RECORD")); !enabled {
+			return
+		}
+		recorder.limit = DefaultRecordLimit
+		if v := os.Getenv("DEBUG_RECORD_LIMIT"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				recorder.limit = n
+			}
+		}
+		slog.Warn("DEBUG_RECORD is on: sanitized Atlassian requests and responses are kept in memory", "limit", recorder.limit)
+	})
+	return recorder.limit
+}
+
+// Recordings returns up to limit of the latest recorded calls, newest first. It is empty
+// unless DEBUG_RECORD is on.
+func Recordings(limit int) []RecordedCall {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	n := len(recorder.entries)
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	calls := make([]RecordedCall, 0, limit)
+	for i := 0; i < limit; i++ {
+		// The newest entry sits just before next (which stays 0 until the buffer wraps)
+		entry := recorder.entries[(recorder.next-1-i+2*n)%n]
+		call := entry.call
+		call.ResponseBody = logging.Redact(entry.responseBody.String())
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// record adds a call to the ring buffer
+func record(entry *recording) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.entries) < recorder.limit {
+		recorder.entries = append(recorder.entries, entry)
+		return
+	}
+	recorder.entries[recorder.next] = entry
+	recorder.next = (recorder.next + 1) % recorder.limit
+}
+
+// recordingTransport keeps a sanitized copy of every call made through base
+type recordingTransport struct {
+	base http.RoundTripper
+}
+
+// withRecording wraps base in a recordingTransport when DEBUG_RECORD is on
+func withRecording(base http.RoundTripper) http.RoundTripper {
+	if recordLimit() == 0 {
+		return base
+	}
+	return &recordingTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := &recording{call: RecordedCall{
+		Time:           time.Now().UTC(),
+		RequestID:      logging.RequestIDFromContext(req.Context()),
+		Method:         req.Method,
+		URL:            logging.Redact(req.URL.Redacted()),
+		RequestHeaders: recordHeaders(req.Header),
+	}}
+
+	// GetBody returns a fresh copy, so the body sent is left alone
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, maxRecordedBody+1))
+			body.Close()
+			if len(data) > maxRecordedBody {
+				data, entry.call.Truncated = data[:maxRecordedBody], true
+			}
+			entry.call.RequestBody = logging.Redact(string(data))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	entry.call.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		entry.call.Error = logging.Redact(err.Error())
+		record(entry)
+		return nil, err
+	}
+
+	entry.call.Status = resp.StatusCode
+	entry.call.ResponseHeaders = recordHeaders(resp.Header)
+	resp.Body = &recordingReader{ReadCloser: resp.Body, entry: entry}
+	record(entry)
+	return resp, nil
+}
+
+// recordHeaders flattens headers for a recording, dropping credentials
+func recordHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	recorded := make(map[string]string, len(header))
+	for name, values := range header {
+		if secretHeaders[http.CanonicalHeaderKey(name)] {
+			recorded[name] = logging.Redacted
+			continue
+		}
+		recorded[name] = logging.Redact(strings.Join(values, ", "))
+	}
+	return recorded
+}
+
+// recordingReader copies the start of a response body into its recording as it is read
+type recordingReader struct {
+	io.ReadCloser
+	entry *recording
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		recorder.mu.Lock()
+		if room := maxRecordedBody - r.entry.responseBody.Len(); room > 0 {
+			chunk := p[:n]
+			if len(chunk) > room {
+				chunk, r.entry.call.Truncated = chunk[:room], true
+			}
+			r.entry.responseBody.Write(chunk)
+		} else {
+			r.entry.call.Truncated = true
+		}
+		recorder.mu.Unlock()
+	}
+	return n, err
+}
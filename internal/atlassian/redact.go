@@ -0,0 +1,100 @@
+package atlassian
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// attachmentPaths mark URLs that serve attachment content: Jira's REST and browser
+// downloads, and Confluence's download links
+var attachmentPaths = []string{"/rest/api/3/attachment/", "/rest/api/2/attachment/", "/secure/attachment/", "/download/attachments/"}
+
+// ApplyRedaction returns data with the values policy hides replaced by [REDACTED]. The
+// result is data's JSON form, so typed results come back as maps and slices. fieldName
+// returns the name of a Jira custom field such as "customfield_10042", so that patterns
+// like "Salary*" apply to it; it is nil where there are no custom fields.
+func ApplyRedaction(policy *models.RedactionPolicy, data interface{}, fieldName func(id string) (string, error)) (interface{}, error) {
+	if !policy.Enabled() || data == nil {
+		return data, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber() // Keep IDs and sizes exactly as Atlassian sent them
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	r := redactor{policy: policy, fieldName: fieldName}
+	generic = r.walk(generic)
+	return generic, r.err
+}
+
+// redactor walks a JSON value, remembering the first failed custom field lookup
+type redactor struct {
+	policy    *models.RedactionPolicy
+	fieldName func(id string) (string, error)
+	err       error
+}
+
+func (r *redactor) walk(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if child != nil && r.redactsKey(key) {
+				v[key] = logging.Redacted
+				continue
+			}
+			v[key] = r.walk(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = r.walk(child)
+		}
+	case string:
+		if r.policy.AttachmentURLs && isAttachmentURL(v) {
+			return logging.Redacted
+		}
+	}
+	return value
+}
+
+// redactsKey reports whether the policy hides the values of a key
+func (r *redactor) redactsKey(key string) bool {
+	if r.policy.RedactsField(key) {
+		return true
+	}
+	if r.fieldName == nil || len(r.policy.Fields) == 0 || !strings.HasPrefix(key, "customfield_") {
+		return false
+	}
+	name, err := r.fieldName(key)
+	if err != nil {
+		// Without the field's name a pattern may miss it, so the whole result is withheld
+		if r.err == nil {
+			r.err = err
+		}
+		return true
+	}
+	return r.policy.RedactsField(name)
+}
+
+// isAttachmentURL reports whether s is a link to attachment content rather than text
+// that merely mentions one
+func isAttachmentURL(s string) bool {
+	if strings.ContainsAny(s, " \n\t") || !(strings.HasPrefix(s, "http") || strings.HasPrefix(s, "/")) {
+		return false
+	}
+	for _, p := range attachmentPaths {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package atlassian
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
+)
+
+// CountingTransport wraps an http.RoundTripper and counts the bytes sent to and
+// received from Atlassian, so tool calls can be metered by API volume. Response
+// status codes are also recorded in metrics.AtlassianResponses.
+type CountingTransport struct {
+	base         http.RoundTripper
+	siteHost     string // Host whose 401s count as rejected credentials; empty counts every host
+	bytes        int64
+	unauthorized int32 // Set once the site answers 401 Unauthorized
+	failedStatus int32 // Status of the last 4xx or 5xx response
+}
+
+// NewCountingTransport wraps base (http.DefaultTransport when nil). Only 401s from site
+// count for Unauthorized, as other services the client calls (e.g. Opsgenie or Bitbucket)
+// take other credentials or scopes.
+func NewCountingTransport(base http.RoundTripper, site string) *CountingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &CountingTransport{base: base}
+	if u, err := url.Parse(site); err == nil {
+		t.siteHost = u.Host
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *CountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.ContentLength > 0 {
+		atomic.AddInt64(&t.bytes, req.ContentLength)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	metrics.AtlassianResponses.Inc(strconv.Itoa(resp.StatusCode))
+	if resp.StatusCode == http.StatusUnauthorized && (t.siteHost == "" || req.URL.Host == t.siteHost) {
+		atomic.StoreInt32(&t.unauthorized, 1)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		atomic.StoreInt32(&t.failedStatus, int32(resp.StatusCode))
+	}
+	resp.Body = &countingReader{ReadCloser: resp.Body, bytes: &t.bytes}
+	return resp, nil
+}
+
+// Bytes returns the total request and response body bytes seen so far
+func (t *CountingTransport) Bytes() int64 {
+	return atomic.LoadInt64(&t.bytes)
+}
+
+// Unauthorized reports whether the site rejected the credentials of any request so far
+func (t *CountingTransport) Unauthorized() bool {
+	return atomic.LoadInt32(&t.unauthorized) != 0
+}
+
+// FailedStatus returns the status of the last 4xx or 5xx response, or 0 when there was none
+func (t *CountingTransport) FailedStatus() int {
+	return int(atomic.LoadInt32(&t.failedStatus))
+}
+
+type countingReader struct {
+	io.ReadCloser
+	bytes *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(r.bytes, int64(n))
+	return n, err
+}
@@ -15,11 +15,17 @@ type Validator struct {
 
 // NewValidator creates a new Atlassian validator
 func NewValidator() *Validator {
-	return &Validator{
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+	return NewValidatorWithProxy("")
+}
+
+// NewValidatorWithProxy creates a validator whose calls go through a workspace's proxy
+// (empty follows HTTPS_PROXY, as the API clients do)
+func NewValidatorWithProxy(proxyURL string) *Validator {
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: Transport(proxyURL),
 	}
+	return &Validator{client: client}
 }
 
 // ValidateToken validates an Atlassian API token by calling the /myself endpoint
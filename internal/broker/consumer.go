@@ -0,0 +1,217 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
+	"github.com/providentiaww/twistygo"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// maxReconnectDelay caps the exponential backoff between reconnect attempts
+const maxReconnectDelay = 30 * time.Second
+
+// Consumer keeps a twistygo service queue consumed across broker restarts. The
+// services run their own consume loop (to handle deliveries concurrently), which
+// twistygo does not restart when the connection drops; Consumer notices the
+// channel closing, reconnects with backoff, re-runs OnConnect and consumes again.
+//
+// At most AMQP_WORKERS deliveries are handled at once, and the channel's prefetch
+// (AMQP_PREFETCH) keeps the rest in the queue until a worker is free.
+//
+// A delivery whose handler panics is republished for another attempt and, after
+// AMQP_MAX_ATTEMPTS attempts, moved to the queue's dead-letter queue.
+type Consumer struct {
+	service     string
+	queues      []string
+	handle      func(ch *amqp.Channel, queue string, delivery amqp.Delivery)
+	onConnect   func(ch *amqp.Channel)
+	maxAttempts int
+	workers     int
+	prefetch    int
+
+	mu        sync.RWMutex
+	connected bool
+	lastErr   error
+}
+
+// NewConsumer creates a consumer for a twistygo service. handle is called on a worker
+// goroutine for every delivery, with the channel the delivery arrived on.
+func NewConsumer(service string, queues []string, handle func(ch *amqp.Channel, queue string, delivery amqp.Delivery)) *Consumer {
+	workers := WorkersFromEnv()
+	return &Consumer{
+		service:     service,
+		queues:      queues,
+		handle:      handle,
+		maxAttempts: MaxAttemptsFromEnv(),
+		workers:     workers,
+		prefetch:    PrefetchFromEnv(workers),
+		lastErr:     errors.New("not connected yet"),
+	}
+}
+
+// OnConnect registers a callback run on every new channel before consuming starts,
+// e.g. to re-subscribe to event exchanges
+func (c *Consumer) OnConnect(fn func(ch *amqp.Channel)) {
+	c.onConnect = fn
+}
+
+// Status reports whether the consumer is connected, and the last error otherwise
+func (c *Consumer) Status() (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected, c.lastErr
+}
+
+// Run consumes svc, which must already be connected, and reconnects whenever the
+// channel closes. It does not return.
+func (c *Consumer) Run(svc *twistygo.ServiceQueue_t) {
+	for {
+		err := c.consume(svc)
+		c.setState(false, err)
+		slog.Warn("RabbitMQ consumer stopped, reconnecting", "service", c.service, "error", err)
+		metrics.AMQPReconnects.Inc(c.service)
+		svc = c.reconnect()
+	}
+}
+
+// consume handles deliveries until the channel closes
+func (c *Consumer) consume(svc *twistygo.ServiceQueue_t) error {
+	ch := svc.Amqp.Channel
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	if c.onConnect != nil {
+		c.onConnect(ch)
+	}
+	if _, err := DeclareDeadLetterQueue(ch, svc.Queue.Name); err != nil {
+		return err
+	}
+	if err := ch.Qos(c.prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	msgs, err := ch.Consume(
+		svc.Queue.Name,      // queue
+		"",                  // consumer
+		svc.Queue.AutoAck,   // auto-ack
+		svc.Queue.Exclusive, // exclusive
+		false,               // no-local
+		svc.Queue.NoWait,    // no-wait
+		nil,                 // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start consumer: %w", err)
+	}
+
+	c.setState(true, nil)
+	slog.Info("consuming RabbitMQ queue", "service", c.service, "queue", svc.Queue.Name, "workers", c.workers, "prefetch", c.prefetch)
+
+	done := make(chan struct{})
+	defer close(done)
+	go reportQueueDepth(ch, []string{svc.Queue.Name, DeadLetterQueueName(svc.Queue.Name)}, done)
+
+	// Block on a free worker slot before taking the next delivery
+	workers := make(chan struct{}, c.workers)
+	for d := range msgs {
+		workers <- struct{}{}
+		metrics.AMQPInFlight.Inc(svc.Queue.Name)
+		go func(d amqp.Delivery) {
+			defer func() {
+				metrics.AMQPInFlight.Dec(svc.Queue.Name)
+				<-workers
+			}()
+			c.dispatch(ch, svc.Queue.Name, d)
+		}(d)
+	}
+
+	// Deliveries stop when the channel or connection closes, or the broker cancels
+	// the consumer (e.g. the queue was deleted)
+	select {
+	case amqpErr := <-closed:
+		if amqpErr != nil {
+			return amqpErr
+		}
+	case <-time.After(time.Second):
+		ch.Close()
+	}
+	return errors.New("delivery channel closed")
+}
+
+// dispatch runs the handler, retrying or dead-lettering the delivery if it panics
+func (c *Consumer) dispatch(ch *amqp.Channel, queue string, d amqp.Delivery) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		requestID, _ := d.Headers[logging.RequestIDHeader].(string)
+		reason := fmt.Sprintf("panic: %v", r)
+		deadLettered, err := retryOrDeadLetter(ch, queue, d, reason, c.maxAttempts)
+		if err != nil {
+			slog.Error("failed to retry request, dropping it", "queue", queue, "request_id", requestID, "reason", reason, "error", err)
+			d.Nack(false, false)
+			return
+		}
+		if deadLettered {
+			slog.Error("request dead-lettered", "queue", queue, "request_id", requestID, "attempts", RetryCount(d)+1, "reason", reason)
+			metrics.AMQPMessages.Inc(queue, "dead_lettered")
+		} else {
+			slog.Warn("request failed, retrying", "queue", queue, "request_id", requestID, "attempt", RetryCount(d)+1, "reason", reason)
+			metrics.AMQPMessages.Inc(queue, "retried")
+		}
+		d.Ack(false)
+	}()
+	c.handle(ch, queue, d)
+}
+
+// reconnect opens a new twistygo connection, retrying with exponential backoff
+func (c *Consumer) reconnect() *twistygo.ServiceQueue_t {
+	delay := time.Second
+	for {
+		svc, err := c.connect()
+		if err == nil {
+			slog.Info("reconnected to RabbitMQ", "service", c.service)
+			return svc
+		}
+		c.setState(false, err)
+		slog.Warn("RabbitMQ reconnect failed", "service", c.service, "retry_in", delay.String(), "error", err)
+		time.Sleep(delay)
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+func (c *Consumer) connect() (svc *twistygo.ServiceQueue_t, err error) {
+	// twistygo panics when the broker is unreachable
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	conn := twistygo.AmqpConnect()
+	if conn == nil {
+		return nil, errors.New("no connection to RabbitMQ")
+	}
+	conn.AmqpLoadQueues(c.queues...)
+	conn.AmqpLoadServices(c.service)
+
+	svc = conn.AmqpConnectService(c.service)
+	if svc == nil || svc.Amqp == nil || svc.Amqp.Channel == nil {
+		return nil, fmt.Errorf("failed to connect to %s queue", c.service)
+	}
+	return svc, nil
+}
+
+func (c *Consumer) setState(connected bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connected = connected
+	c.lastErr = err
+}
@@ -0,0 +1,232 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetterExchange routes failed requests to "<queue>.dead" queues, keyed by the
+// name of the queue they were consumed from
+const DeadLetterExchange = "trilix.dead-letter"
+
+// Headers recorded on retried and dead-lettered requests
+const (
+	RetryCountHeader         = "x-retry-count"
+	DeadLetterIDHeader       = "x-dead-letter-id"
+	DeadLetterReasonHeader   = "x-dead-letter-reason"
+	DeadLetterTimeHeader     = "x-dead-lettered-at"
+	OriginalExchangeHeader   = "x-original-exchange"
+	OriginalRoutingKeyHeader = "x-original-routing-key"
+)
+
+// DefaultMaxAttempts is used when AMQP_MAX_ATTEMPTS is not set
+const DefaultMaxAttempts = 3
+
+// MaxAttemptsFromEnv reads AMQP_MAX_ATTEMPTS, the number of times a failing request
+// is processed before it is dead-lettered
+func MaxAttemptsFromEnv() int {
+	if v := os.Getenv("AMQP_MAX_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return DefaultMaxAttempts
+}
+
+// DeadLetterQueueName returns the dead-letter queue for a request queue
+func DeadLetterQueueName(queue string) string {
+	return queue + ".dead"
+}
+
+// DeclareDeadLetterQueue makes sure the dead-letter exchange and the queue's
+// dead-letter queue exist, and returns the dead-letter queue's current state
+func DeclareDeadLetterQueue(ch *amqp.Channel, queue string) (amqp.Queue, error) {
+	if err := ch.ExchangeDeclare(DeadLetterExchange, "direct", true, false, false, false, nil); err != nil {
+		return amqp.Queue{}, fmt.Errorf("failed to declare %s: %w", DeadLetterExchange, err)
+	}
+	dlq, err := ch.QueueDeclare(DeadLetterQueueName(queue), true, false, false, false, nil)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("failed to declare %s: %w", DeadLetterQueueName(queue), err)
+	}
+	if err := ch.QueueBind(dlq.Name, queue, DeadLetterExchange, false, nil); err != nil {
+		return amqp.Queue{}, fmt.Errorf("failed to bind %s: %w", dlq.Name, err)
+	}
+	return dlq, nil
+}
+
+// RetryCount returns how many times a request has already been retried
+func RetryCount(d amqp.Delivery) int {
+	switch v := d.Headers[RetryCountHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// retryOrDeadLetter republishes a failed request for another attempt, or moves it to
+// the dead-letter queue once maxAttempts is reached. It reports whether the request
+// was dead-lettered. The caller still has to ack the original delivery.
+func retryOrDeadLetter(ch *amqp.Channel, queue string, d amqp.Delivery, reason string, maxAttempts int) (bool, error) {
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	attempts := RetryCount(d) + 1
+	headers[RetryCountHeader] = int32(attempts)
+
+	msg := amqp.Publishing{
+		Headers:       headers,
+		ContentType:   d.ContentType,
+		CorrelationId: d.CorrelationId,
+		ReplyTo:       d.ReplyTo,
+		MessageId:     d.MessageId,
+		DeliveryMode:  amqp.Persistent,
+		Body:          d.Body,
+	}
+
+	if attempts < maxAttempts {
+		return false, ch.Publish(d.Exchange, d.RoutingKey, false, false, msg)
+	}
+
+	if _, err := DeclareDeadLetterQueue(ch, queue); err != nil {
+		return true, err
+	}
+	headers[DeadLetterIDHeader] = uuid.NewString()
+	headers[DeadLetterReasonHeader] = reason
+	headers[DeadLetterTimeHeader] = time.Now().UTC().Format(time.RFC3339)
+	headers[OriginalExchangeHeader] = d.Exchange
+	headers[OriginalRoutingKeyHeader] = d.RoutingKey
+	return true, ch.Publish(DeadLetterExchange, queue, false, false, msg)
+}
+
+// DeadLetter describes a dead-lettered request
+type DeadLetter struct {
+	ID             string          `json:"id"`
+	Queue          string          `json:"queue"`
+	RequestID      string          `json:"request_id,omitempty"`
+	Reason         string          `json:"reason"`
+	Attempts       int             `json:"attempts"`
+	DeadLetteredAt string          `json:"dead_lettered_at"`
+	Request        json.RawMessage `json:"request"`
+}
+
+// ListDeadLetters returns up to limit requests from a queue's dead-letter queue,
+// oldest first, leaving them in place
+func ListDeadLetters(ch *amqp.Channel, queue string, limit int) ([]DeadLetter, error) {
+	dlq, err := DeclareDeadLetterQueue(ch, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages stay unacked while we page through them so Get doesn't return them
+	// again, and are all put back afterwards
+	var held []amqp.Delivery
+	defer func() { requeue(held) }()
+
+	letters := []DeadLetter{}
+	for i := 0; i < dlq.Messages && len(letters) < limit; i++ {
+		d, ok, err := ch.Get(dlq.Name, false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		held = append(held, d)
+		letters = append(letters, deadLetterFrom(queue, d))
+	}
+	return letters, nil
+}
+
+// ReplayDeadLetters republishes dead-lettered requests to the queue they originally
+// came from, with a fresh retry count. An empty ids list replays every request.
+func ReplayDeadLetters(ch *amqp.Channel, queue string, ids []string) (int, error) {
+	dlq, err := DeclareDeadLetterQueue(ch, queue)
+	if err != nil {
+		return 0, err
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var held []amqp.Delivery
+	defer func() { requeue(held) }()
+
+	replayed := 0
+	for i := 0; i < dlq.Messages; i++ {
+		d, ok, err := ch.Get(dlq.Name, false)
+		if err != nil {
+			return replayed, err
+		}
+		if !ok {
+			break
+		}
+		id, _ := d.Headers[DeadLetterIDHeader].(string)
+		if len(wanted) > 0 && !wanted[id] {
+			held = append(held, d)
+			continue
+		}
+
+		exchange, _ := d.Headers[OriginalExchangeHeader].(string)
+		routingKey, _ := d.Headers[OriginalRoutingKeyHeader].(string)
+		headers := amqp.Table{}
+		for k, v := range d.Headers {
+			switch k {
+			case RetryCountHeader, DeadLetterIDHeader, DeadLetterReasonHeader, DeadLetterTimeHeader, OriginalExchangeHeader, OriginalRoutingKeyHeader:
+			default:
+				headers[k] = v
+			}
+		}
+		err = ch.Publish(exchange, routingKey, false, false, amqp.Publishing{
+			Headers:       headers,
+			ContentType:   d.ContentType,
+			CorrelationId: d.CorrelationId,
+			ReplyTo:       d.ReplyTo,
+			MessageId:     d.MessageId,
+			DeliveryMode:  amqp.Persistent,
+			Body:          d.Body,
+		})
+		if err != nil {
+			held = append(held, d)
+			return replayed, err
+		}
+		d.Ack(false)
+		replayed++
+	}
+	return replayed, nil
+}
+
+func deadLetterFrom(queue string, d amqp.Delivery) DeadLetter {
+	letter := DeadLetter{
+		Queue:    queue,
+		Attempts: RetryCount(d),
+		Request:  d.Body,
+	}
+	letter.ID, _ = d.Headers[DeadLetterIDHeader].(string)
+	letter.RequestID, _ = d.Headers[logging.RequestIDHeader].(string)
+	letter.Reason, _ = d.Headers[DeadLetterReasonHeader].(string)
+	letter.DeadLetteredAt, _ = d.Headers[DeadLetterTimeHeader].(string)
+	if !json.Valid(d.Body) {
+		letter.Request, _ = json.Marshal(string(d.Body))
+	}
+	return letter
+}
+
+func requeue(deliveries []amqp.Delivery) {
+	for _, d := range deliveries {
+		d.Nack(false, true)
+	}
+}
@@ -0,0 +1,98 @@
+package broker
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DefaultWorkers is used when AMQP_WORKERS is not set
+const DefaultWorkers = 16
+
+// queueDepthInterval is how often queue depths are sampled for metrics
+const queueDepthInterval = 15 * time.Second
+
+// WorkersFromEnv reads AMQP_WORKERS, the number of deliveries a service handles
+// concurrently
+func WorkersFromEnv() int {
+	if v := os.Getenv("AMQP_WORKERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return DefaultWorkers
+}
+
+// PrefetchFromEnv reads AMQP_PREFETCH, the number of unacknowledged deliveries
+// RabbitMQ sends a consumer ahead of time. It defaults to the worker count, so
+// requests beyond that stay in the queue where other replicas can pick them up.
+func PrefetchFromEnv(workers int) int {
+	if v := os.Getenv("AMQP_PREFETCH"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return workers
+}
+
+// reportQueueDepth samples the depth of the given queues until done is closed
+func reportQueueDepth(ch *amqp.Channel, queues []string, done <-chan struct{}) {
+	ticker := time.NewTicker(queueDepthInterval)
+	defer ticker.Stop()
+	for {
+		for _, queue := range queues {
+			q, err := ch.QueueDeclarePassive(queue, true, false, false, false, nil)
+			if err != nil {
+				// A failed passive declare closes the channel, which ends this consumer run
+				slog.Warn("failed to inspect queue", "queue", queue, "error", err)
+				return
+			}
+			metrics.AMQPQueueDepth.Set(float64(q.Messages), queue)
+		}
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// QueueStats is a snapshot of a request queue and its dead-letter queue
+type QueueStats struct {
+	Queue       string `json:"queue"`
+	Messages    int    `json:"messages"`
+	Consumers   int    `json:"consumers"`
+	DeadLetters int    `json:"dead_letters"`
+}
+
+// Ping checks that ch is open and that the broker answers on it by passively declaring
+// queue. Like InspectQueue, a failed declare closes ch.
+func Ping(ch *amqp.Channel, queue string) error {
+	if ch == nil {
+		return errors.New("no channel")
+	}
+	if ch.IsClosed() {
+		return errors.New("channel closed")
+	}
+	_, err := ch.QueueDeclarePassive(queue, true, false, false, false, nil)
+	return err
+}
+
+// InspectQueue reads a request queue's depth and consumer count and the depth of its
+// dead-letter queue. The queue must already exist: a failed passive declare closes ch.
+func InspectQueue(ch *amqp.Channel, queue string) (QueueStats, error) {
+	q, err := ch.QueueDeclarePassive(queue, true, false, false, false, nil)
+	if err != nil {
+		return QueueStats{}, err
+	}
+	dlq, err := DeclareDeadLetterQueue(ch, queue)
+	if err != nil {
+		return QueueStats{}, err
+	}
+	return QueueStats{Queue: q.Name, Messages: q.Messages, Consumers: q.Consumers, DeadLetters: dlq.Messages}, nil
+}
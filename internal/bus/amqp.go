@@ -0,0 +1,105 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
+	"github.com/providentiaww/twistygo"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// amqpQueues maps services to the twistygo queue definitions (settings.yaml) they consume
+var amqpQueues = map[string]string{
+	JiraService:       "JiraRequests",
+	ConfluenceService: "ConfluenceRequests",
+}
+
+// AMQPQueue returns the twistygo queue definition for a service
+func AMQPQueue(service string) string {
+	return amqpQueues[service]
+}
+
+// AMQP sends requests as twistygo RPC calls over RabbitMQ
+type AMQP struct {
+	conn *twistygo.AmqpConnection_t
+}
+
+// NewAMQP creates a requester on a connection that has loaded the request queues
+func NewAMQP(conn *twistygo.AmqpConnection_t) *AMQP {
+	return &AMQP{conn: conn}
+}
+
+// Request publishes body on the service's queue and waits for the reply
+func (a *AMQP) Request(ctx context.Context, service string, body []byte, headers Headers) ([]byte, error) {
+	queue, ok := amqpQueues[service]
+	if !ok {
+		return nil, fmt.Errorf("unknown service: %s", service)
+	}
+	sq := cloneServiceQueue(a.conn.AmqpConnectQueue(queue))
+	if sq == nil {
+		return nil, fmt.Errorf("%s queue not initialized", service)
+	}
+	sq.SetEncoding(twistygo.EncodingJson)
+
+	// Send the body as a single object (not a twistygo data array)
+	sq.Message.ResetDataList()
+	sq.Message.AppendData(json.RawMessage(body))
+	sq.Message.Encoded = body
+	for k, v := range headers {
+		sq.Headers[k] = v
+	}
+
+	type publishResult struct {
+		resp []byte
+		err  error
+	}
+	resChan := make(chan publishResult, 1)
+	metrics.AMQPMessages.Inc(queue, "published")
+	go func() {
+		resp, err := sq.Publish()
+		resChan <- publishResult{resp, err}
+	}()
+
+	select {
+	case res := <-resChan:
+		if res.err != nil {
+			return nil, res.err
+		}
+		metrics.AMQPMessages.Inc(queue, "consumed")
+		return res.resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// cloneServiceQueue creates a shallow-ish copy of a ServiceQueue_t with its own Message
+// and ResponseQueue to avoid race conditions during concurrent tool calls.
+func cloneServiceQueue(src *twistygo.ServiceQueue_t) *twistygo.ServiceQueue_t {
+	if src == nil {
+		return nil
+	}
+	dst := *src
+	dst.Message = twistygo.MessageSet_t{}
+	dst.ResponseQueue = &amqp.Queue{}
+	dst.Headers = make(amqp.Table)
+	if src.Headers != nil {
+		for k, v := range src.Headers {
+			dst.Headers[k] = v
+		}
+	}
+	// Deep copy Queue parameters because twistygo modifies sq.Queue.Args in publishRPC
+	if src.Queue != nil {
+		qCopy := *src.Queue
+		if src.Queue.Args != nil {
+			argsCopy := make(amqp.Table)
+			for k, v := range *src.Queue.Args {
+				argsCopy[k] = v
+			}
+			qCopy.Args = &argsCopy
+		}
+		dst.Queue = &qCopy
+	}
+	return &dst
+}
@@ -0,0 +1,67 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+)
+
+// Message bus implementations, selected with MESSAGE_BUS
+const (
+	KindAMQP   = "amqp"   // RabbitMQ through twistygo (default)
+	KindNATS   = "nats"   // NATS request-reply
+	KindMemory = "memory" // Jira and Confluence handled inside the MCP server process
+)
+
+// Services reachable over the bus
+const (
+	JiraService       = "jira"
+	ConfluenceService = "confluence"
+)
+
+// ErrorHeader carries a transport-level failure (e.g. the handler panicked) back to
+// the caller in place of a reply body
+const ErrorHeader = "Trilix-Error"
+
+// Headers are string message headers, such as the request ID
+type Headers map[string]string
+
+// Handler processes a request body and returns the reply body
+type Handler func(body []byte, headers Headers) []byte
+
+// Requester sends a request to a service and waits for its reply. Implementations
+// return ctx.Err() when the context ends first.
+type Requester interface {
+	Request(ctx context.Context, service string, body []byte, headers Headers) ([]byte, error)
+}
+
+// KindFromEnv reads MESSAGE_BUS (amqp, nats or memory; "rabbitmq" is accepted for amqp)
+func KindFromEnv() (string, error) {
+	switch kind := strings.ToLower(strings.TrimSpace(os.Getenv("MESSAGE_BUS"))); kind {
+	case "", KindAMQP, "rabbitmq":
+		return KindAMQP, nil
+	case KindNATS, KindMemory:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("unknown MESSAGE_BUS %q (expected amqp, nats or memory)", kind)
+	}
+}
+
+// Call marshals req as JSON, sends it to a service and decodes the reply into resp
+func Call(ctx context.Context, r Requester, service, requestID string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	reply, err := r.Request(ctx, service, body, Headers{logging.RequestIDHeader: requestID})
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(logging.WithRequestID(ctx, requestID), "service reply", "service", service, "bytes", len(reply))
+	return json.Unmarshal(reply, resp)
+}
@@ -0,0 +1,68 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/broker"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+)
+
+// Memory is an in-process bus: requests are handed straight to handlers registered
+// in the same process. Concurrency is bounded by AMQP_WORKERS like the queue consumers.
+type Memory struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	workers  chan struct{}
+}
+
+// NewMemory creates an empty in-process bus
+func NewMemory() *Memory {
+	return &Memory{
+		handlers: make(map[string]Handler),
+		workers:  make(chan struct{}, broker.WorkersFromEnv()),
+	}
+}
+
+// Serve registers the handler for a service, replacing any previous one
+func (m *Memory) Serve(service string, handler Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[service] = handler
+}
+
+// Request runs the service's handler on a worker and waits for its reply
+func (m *Memory) Request(ctx context.Context, service string, body []byte, headers Headers) ([]byte, error) {
+	m.mu.RLock()
+	handler, ok := m.handlers[service]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no %s service registered on the in-memory bus", service)
+	}
+
+	type result struct {
+		reply []byte
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		m.workers <- struct{}{}
+		defer func() { <-m.workers }()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("in-memory service handler panicked", "service", service, "request_id", headers[logging.RequestIDHeader], "panic", r)
+				done <- result{err: fmt.Errorf("%s service failed: %v", service, r)}
+			}
+		}()
+		done <- result{reply: handler(body, headers)}
+	}()
+
+	select {
+	case res := <-done:
+		return res.reply, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
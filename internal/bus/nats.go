@@ -0,0 +1,154 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/broker"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
+)
+
+// DefaultNATSURL is used when NATS_URL is not set
+const DefaultNATSURL = "nats://localhost:4222"
+
+// natsMaxReconnectDelay caps the exponential backoff between reconnect attempts
+const natsMaxReconnectDelay = 30 * time.Second
+
+// NATSURLFromEnv reads NATS_URL (nats://[user:pass@]host:port, or tls:// for TLS)
+func NATSURLFromEnv() string {
+	if v := os.Getenv("NATS_URL"); v != "" {
+		return v
+	}
+	return DefaultNATSURL
+}
+
+// NATSSubject returns the subject a service receives requests on
+func NATSSubject(service string) string {
+	return "trilix." + service + ".requests"
+}
+
+// NATS sends and serves requests over NATS request-reply. Replicas of a service share a
+// queue group, so each request is handled by exactly one of them.
+//
+// Unlike RabbitMQ, NATS does not persist requests: one sent while no replica is
+// connected fails immediately, and there is no retry or dead-lettering.
+type NATS struct {
+	conn *nats.Conn
+}
+
+// NewNATS connects to a NATS server. name identifies the client in server monitoring.
+// The client reconnects indefinitely, renewing its subscriptions.
+func NewNATS(rawURL, name string) (*NATS, error) {
+	conn, err := nats.Connect(rawURL,
+		nats.Name(name),
+		nats.MaxReconnects(-1),
+		nats.CustomReconnectDelay(func(attempts int) time.Duration {
+			return min(time.Second<<min(attempts, 5), natsMaxReconnectDelay)
+		}),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				slog.Warn("NATS connection lost, reconnecting", "error", err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			slog.Info("reconnected to NATS", "url", conn.ConnectedUrlRedacted())
+		}),
+		nats.ErrorHandler(func(_ *nats.Conn, sub *nats.Subscription, err error) {
+			slog.Warn("NATS error", "subject", subjectOf(sub), "error", err)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	return &NATS{conn: conn}, nil
+}
+
+// Status reports whether the client is connected, and the last error otherwise
+func (n *NATS) Status() (bool, error) {
+	if n.conn.IsConnected() {
+		return true, nil
+	}
+	if err := n.conn.LastError(); err != nil {
+		return false, err
+	}
+	return false, fmt.Errorf("NATS connection is %s", n.conn.Status())
+}
+
+// Close disconnects and stops reconnecting
+func (n *NATS) Close() error {
+	n.conn.Close()
+	return nil
+}
+
+// Request publishes body on the service's subject and waits for the first reply
+func (n *NATS) Request(ctx context.Context, service string, body []byte, headers Headers) ([]byte, error) {
+	msg := nats.NewMsg(NATSSubject(service))
+	msg.Data = body
+	for k, v := range headers {
+		msg.Header.Set(k, v)
+	}
+
+	reply, err := n.conn.RequestMsgWithContext(ctx, msg)
+	switch {
+	case errors.Is(err, nats.ErrNoResponders):
+		return nil, fmt.Errorf("no %s service is listening on %s", service, NATSSubject(service))
+	case err != nil && ctx.Err() != nil:
+		return nil, ctx.Err()
+	case err != nil:
+		return nil, err
+	case reply.Header.Get(ErrorHeader) != "":
+		return nil, errors.New(reply.Header.Get(ErrorHeader))
+	}
+	return reply.Data, nil
+}
+
+// Serve handles requests for a service with up to AMQP_WORKERS concurrent handlers
+func (n *NATS) Serve(service string, handler Handler) error {
+	workers := make(chan struct{}, broker.WorkersFromEnv())
+	_, err := n.conn.QueueSubscribe(NATSSubject(service), "trilix."+service, func(msg *nats.Msg) {
+		// Blocking here holds further deliveries until a worker is free
+		workers <- struct{}{}
+		go func() {
+			defer func() { <-workers }()
+			headers := make(Headers, len(msg.Header))
+			for k := range msg.Header {
+				headers[k] = msg.Header.Get(k)
+			}
+			data, replyHeaders := invokeNATS(service, handler, msg.Data, headers)
+			if msg.Reply == "" {
+				return
+			}
+			reply := &nats.Msg{Data: data, Header: nats.Header{}}
+			for k, v := range replyHeaders {
+				reply.Header.Set(k, v)
+			}
+			if err := msg.RespondMsg(reply); err != nil {
+				slog.Error("failed to publish reply", "service", service, "request_id", headers[logging.RequestIDHeader], "error", err)
+			}
+		}()
+	})
+	return err
+}
+
+// invokeNATS runs a handler, turning a panic into an ErrorHeader reply
+func invokeNATS(service string, handler Handler, data []byte, headers Headers) (reply []byte, replyHeaders Headers) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("service handler panicked", "service", service, "request_id", headers[logging.RequestIDHeader], "panic", r)
+			reply, replyHeaders = nil, Headers{ErrorHeader: fmt.Sprintf("%s service failed: %v", service, r)}
+		}
+	}()
+	return handler(data, headers), nil
+}
+
+func subjectOf(sub *nats.Subscription) string {
+	if sub == nil {
+		return ""
+	}
+	return sub.Subject
+}
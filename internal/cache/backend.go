@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Backend stores cached values in this process or, with REDIS_URL, in Redis where every
+// replica of a service shares them. Values are stored as JSON, so Get decodes into a
+// pointer the way json.Unmarshal does.
+type Backend interface {
+	// Get decodes the value stored under key into dest and reports whether there was one
+	Get(key string, dest interface{}) bool
+	// Set stores value under key for ttl
+	Set(key string, value interface{}, ttl time.Duration)
+	// Delete removes key
+	Delete(key string)
+	// Stats returns the entry counts and hit/miss totals seen by this process
+	Stats() Stats
+}
+
+// NewBackendFromEnv caches in Redis when REDIS_URL is set and reachable, and in memory
+// otherwise. namespace keeps each service's keys apart, e.g. "confluence".
+func NewBackendFromEnv(namespace string) Backend {
+	rawURL := os.Getenv("REDIS_URL")
+	if rawURL == "" {
+		return NewMemoryBackend()
+	}
+	redis, err := NewRedis(rawURL, namespace)
+	if err != nil {
+		slog.Warn("Redis cache unavailable, caching in memory", "namespace", namespace, "error", err)
+		return NewMemoryBackend()
+	}
+	slog.Info("caching in Redis", "namespace", namespace, "url", redis.url.Redacted())
+	return redis
+}
+
+// memoryPurgeInterval is how often Set drops expired entries, which may be large
+const memoryPurgeInterval = time.Minute
+
+// MemoryBackend is a Backend private to this process
+type MemoryBackend struct {
+	cache     *SimpleCache
+	lastPurge atomic.Int64 // Unix nanoseconds
+}
+
+// NewMemoryBackend creates an empty in-memory backend
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{cache: NewSimpleCache()}
+}
+
+// Get decodes a cached value into dest
+func (b *MemoryBackend) Get(key string, dest interface{}) bool {
+	value, found := b.cache.Get(key)
+	if !found {
+		return false
+	}
+	return json.Unmarshal(value.([]byte), dest) == nil
+}
+
+// Set caches value's JSON form; values that cannot be encoded are not cached
+func (b *MemoryBackend) Set(key string, value interface{}, ttl time.Duration) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	b.cache.Set(key, raw, ttl)
+
+	now := time.Now().UnixNano()
+	if last := b.lastPurge.Load(); now-last > int64(memoryPurgeInterval) && b.lastPurge.CompareAndSwap(last, now) {
+		b.cache.DeleteExpired()
+	}
+}
+
+// Delete removes key
+func (b *MemoryBackend) Delete(key string) {
+	b.cache.Delete(key)
+}
+
+// Stats returns the cache's entry counts and hit/miss totals
+func (b *MemoryBackend) Stats() Stats {
+	return b.cache.Stats()
+}
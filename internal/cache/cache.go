@@ -1,7 +1,9 @@
 package cache
 
 import (
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -13,8 +15,18 @@ type CacheEntry struct {
 
 // SimpleCache is a thread-safe in-memory cache with TTL
 type SimpleCache struct {
-	mu    sync.RWMutex
-	items map[string]CacheEntry
+	mu     sync.RWMutex
+	items  map[string]CacheEntry
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// Stats describes a cache's contents and lookups since it was created
+type Stats struct {
+	Entries int   `json:"entries"`
+	Expired int   `json:"expired"` // Entries past their TTL that have not been replaced yet
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
 }
 
 // NewSimpleCache creates a new cache instance
@@ -30,14 +42,12 @@ func (c *SimpleCache) Get(key string) (interface{}, bool) {
 	defer c.mu.RUnlock()
 
 	entry, exists := c.items[key]
-	if !exists {
-		return nil, false
-	}
-
-	if time.Now().After(entry.Expiration) {
+	if !exists || time.Now().After(entry.Expiration) {
+		c.misses.Add(1)
 		return nil, false
 	}
 
+	c.hits.Add(1)
 	return entry.Value, true
 }
 
@@ -60,6 +70,31 @@ func (c *SimpleCache) Delete(key string) {
 	delete(c.items, key)
 }
 
+// DeletePrefix removes every key that starts with prefix
+func (c *SimpleCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.items, key)
+		}
+	}
+}
+
+// DeleteExpired removes the entries past their TTL, which Get no longer returns
+func (c *SimpleCache) DeleteExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range c.items {
+		if now.After(entry.Expiration) {
+			delete(c.items, key)
+		}
+	}
+}
+
 // Clear removes all entries from cache
 func (c *SimpleCache) Clear() {
 	c.mu.Lock()
@@ -67,3 +102,18 @@ func (c *SimpleCache) Clear() {
 
 	c.items = make(map[string]CacheEntry)
 }
+
+// Stats returns the cache's entry counts and hit/miss totals
+func (c *SimpleCache) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := Stats{Entries: len(c.items), Hits: c.hits.Load(), Misses: c.misses.Load()}
+	now := time.Now()
+	for _, entry := range c.items {
+		if now.After(entry.Expiration) {
+			stats.Expired++
+		}
+	}
+	return stats
+}
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix starts every key written to Redis: trilix:<namespace>:<key>, so that a
+// shared Redis can be inspected and flushed by prefix
+const redisKeyPrefix = "trilix:"
+
+// redisTimeout bounds dialing and each command, so a slow Redis delays a request by at
+// most this long before the in-memory fallback is used
+const redisTimeout = 2 * time.Second
+
+// redisMaxIdle is how many connections are kept open between commands
+const redisMaxIdle = 8
+
+// redisRetryInterval is how long the fallback is used after a failure before Redis is
+// tried again
+const redisRetryInterval = 5 * time.Second
+
+// Redis is a Backend shared by every replica that uses the same Redis and namespace.
+// While Redis is unreachable, values are cached in this process's memory instead.
+type Redis struct {
+	url       *url.URL
+	namespace string
+	client    *redis.Client
+	fallback  *MemoryBackend
+
+	hits    atomic.Int64
+	misses  atomic.Int64
+	failing atomic.Bool  // Redis failed its last command; logged once per outage
+	retryAt atomic.Int64 // Unix nanoseconds before which Redis is not tried during an outage
+}
+
+// errRedisBackingOff is returned instead of trying Redis again too soon after a failure
+var errRedisBackingOff = errors.New("Redis is unavailable")
+
+// NewRedis connects to Redis at rawURL: redis://[[user]:password@]host[:port][/db], or
+// rediss:// for TLS
+func NewRedis(rawURL, namespace string) (*Redis, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	u, _ := url.Parse(rawURL)
+	options.DialTimeout = redisTimeout
+	options.ReadTimeout = redisTimeout
+	options.WriteTimeout = redisTimeout
+	options.MaxIdleConns = redisMaxIdle
+
+	r := &Redis{
+		url:       u,
+		namespace: namespace,
+		client:    redis.NewClient(options),
+		fallback:  NewMemoryBackend(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		r.client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return r, nil
+}
+
+// Get decodes the value stored under key into dest
+func (r *Redis) Get(key string, dest interface{}) bool {
+	raw, err := r.do(func(ctx context.Context) ([]byte, error) {
+		return r.client.Get(ctx, r.key(key)).Bytes()
+	})
+	if err != nil && err != redis.Nil {
+		r.failed("GET", err)
+		return r.fallback.Get(key, dest)
+	}
+	r.recovered()
+	if err == redis.Nil || json.Unmarshal(raw, dest) != nil {
+		r.misses.Add(1)
+		return false
+	}
+	r.hits.Add(1)
+	return true
+}
+
+// Set stores value's JSON form under key for ttl
+func (r *Redis) Set(key string, value interface{}, ttl time.Duration) {
+	raw, err := json.Marshal(value)
+	if err != nil || ttl <= 0 {
+		return
+	}
+	if _, err := r.do(func(ctx context.Context) ([]byte, error) {
+		return nil, r.client.Set(ctx, r.key(key), raw, ttl).Err()
+	}); err != nil {
+		r.failed("SET", err)
+		r.fallback.Set(key, value, ttl)
+		return
+	}
+	r.recovered()
+}
+
+// Delete removes key from Redis and from the fallback
+func (r *Redis) Delete(key string) {
+	r.fallback.Delete(key)
+	if _, err := r.do(func(ctx context.Context) ([]byte, error) {
+		return nil, r.client.Del(ctx, r.key(key)).Err()
+	}); err != nil {
+		r.failed("DEL", err)
+		return
+	}
+	r.recovered()
+}
+
+// Stats returns this process's hit/miss totals; Entries counts only the fallback's
+func (r *Redis) Stats() Stats {
+	stats := r.fallback.Stats()
+	stats.Hits += r.hits.Load()
+	stats.Misses += r.misses.Load()
+	return stats
+}
+
+// key namespaces a cache key
+func (r *Redis) key(key string) string {
+	return redisKeyPrefix + r.namespace + ":" + key
+}
+
+// failed logs the first failure of an outage and backs off
+func (r *Redis) failed(command string, err error) {
+	if err == errRedisBackingOff {
+		return
+	}
+	r.retryAt.Store(time.Now().Add(redisRetryInterval).UnixNano())
+	if !r.failing.Swap(true) {
+		slog.Warn("Redis cache command failed, caching in memory until it recovers", "namespace", r.namespace, "command", command, "error", err)
+	}
+}
+
+// recovered logs the end of an outage
+func (r *Redis) recovered() {
+	if r.failing.Swap(false) {
+		slog.Info("Redis cache recovered", "namespace", r.namespace)
+	}
+}
+
+// do runs a command unless Redis failed too recently to try again
+func (r *Redis) do(command func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	if r.failing.Load() && time.Now().UnixNano() < r.retryAt.Load() {
+		return nil, errRedisBackingOff
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return command(ctx)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
 
@@ -18,7 +19,7 @@ import (
 // supporting local development.
 func LoadEnv(defaultEnvPath string) {
 	if err := loadAWSSecretsIntoEnv(); err != nil {
-		fmt.Printf("⚠️  Skipping AWS Secrets Manager load: %v\n", err)
+		slog.Warn("skipping AWS Secrets Manager load", "error", err)
 	}
 	loadDotEnv(defaultEnvPath)
 }
@@ -34,7 +35,7 @@ func loadDotEnv(defaultEnvPath string) {
 		if err := godotenv.Load(); err != nil {
 			// Don't log if running in K8s/Docker where env is injected
 			if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
-				fmt.Printf("Note: .env file not found at %s. Using system environment variables.\n", envFile)
+				slog.Info(".env file not found, using system environment variables", "path", envFile)
 			}
 		}
 	}
@@ -46,7 +47,7 @@ func loadAWSSecretsIntoEnv() error {
 		secretID = os.Getenv("AWS_SECRET_ID")
 	}
 	if secretID == "" {
-		fmt.Println("ℹ️ AWS Secrets Manager: no secret ID provided, skipping fetch")
+		slog.Debug("AWS Secrets Manager: no secret ID provided, skipping fetch")
 		return nil
 	}
 
@@ -73,7 +74,7 @@ func loadAWSSecretsIntoEnv() error {
 
 	output, err := client.GetSecretValue(ctx, input)
 	if err != nil {
-		fmt.Printf("⚠️ AWS Secrets Manager: failed to fetch %s: %v\n", secretID, err)
+		slog.Warn("AWS Secrets Manager: failed to fetch secret", "secret_id", secretID, "error", err)
 		return fmt.Errorf("fetching secret %s: %w", secretID, err)
 	}
 
@@ -89,7 +90,7 @@ func loadAWSSecretsIntoEnv() error {
 
 	var kv map[string]interface{}
 	if err := json.Unmarshal([]byte(payload), &kv); err != nil {
-		fmt.Printf("⚠️ AWS Secrets Manager: secret %s is not valid JSON: %v\n", secretID, err)
+		slog.Warn("AWS Secrets Manager: secret is not valid JSON", "secret_id", secretID, "error", err)
 		return fmt.Errorf("parsing secret %s as JSON: %w", secretID, err)
 	}
 
@@ -106,9 +107,9 @@ func loadAWSSecretsIntoEnv() error {
 	}
 
 	if applied > 0 {
-		fmt.Printf("ℹ️ Loaded %d env vars from AWS Secrets Manager secret %s\n", applied, secretID)
+		slog.Info("loaded env vars from AWS Secrets Manager", "count", applied, "secret_id", secretID)
 	} else {
-		fmt.Printf("ℹ️ AWS Secrets Manager: no env vars applied from secret %s (overwrite=%v)\n", secretID, overwrite)
+		slog.Info("AWS Secrets Manager: no env vars applied", "secret_id", secretID, "overwrite", overwrite)
 	}
 
 	return nil
@@ -0,0 +1,333 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/pii"
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults for settings missing from config.yaml and the environment
+const (
+	DefaultPort             = 3000
+	DefaultRPCTimeout       = 35 * time.Second
+	DefaultAtlassianTimeout = 30 * time.Second
+	DefaultMaxResponseBytes = 1 << 20
+)
+
+// Settings are the tunables the binaries read from config.yaml and the environment.
+// A Settings value is never modified after loading; reloads produce a new one.
+type Settings struct {
+	Port              int                      // HTTP port of the MCP server
+	RPCTimeout        time.Duration            // How long tool calls wait for the Jira/Confluence services
+	ToolTimeouts      map[string]time.Duration // Per-tool overrides of RPCTimeout, keyed by tool name
+	ToolCacheTTLs     map[string]time.Duration // How long read tools' results are cached, keyed by tool name (unlisted = not cached)
+	MaxResponseBytes  int                      // Tool results above this are truncated (0 = unlimited)
+	EnabledTools      []string                 // Server-wide tool allowlist (empty = every tool)
+	UsageQuota        models.UsageQuota        // Daily per-user quotas (0 = unlimited)
+	AtlassianTimeout  time.Duration            // HTTP timeout of the services' Atlassian clients
+	AtlassianCABundle string                   // PEM file of extra CAs the Atlassian clients trust (empty = system roots)
+	Redaction         models.RedactionPolicy   // What the services hide from results before they reach the model
+	FieldPresets      map[string][]string      // Named field lists for jira_list_issues' preset argument
+	PIIScan           pii.Mode                 // Whether tool results with likely personal data are flagged or withheld
+}
+
+// settingsFile is the layout of config.yaml (the sections twistygo reads are ignored)
+type settingsFile struct {
+	Common struct {
+		App struct {
+			Port                int                 `yaml:"port"`
+			RPCTimeout          string              `yaml:"rpc_timeout"`
+			ToolTimeouts        map[string]string   `yaml:"tool_timeouts"`
+			ToolCacheTTLs       map[string]string   `yaml:"tool_cache_ttls"`
+			MaxResponseBytes    *int                `yaml:"max_response_bytes"`
+			EnabledTools        []string            `yaml:"enabled_tools"`
+			UsageDailyToolCalls int64               `yaml:"usage_daily_tool_calls"`
+			UsageDailyAPIBytes  int64               `yaml:"usage_daily_api_bytes"`
+			FieldPresets        map[string][]string `yaml:"field_presets"`
+			PIIScan             string              `yaml:"pii_scan"`
+		} `yaml:"app"`
+	} `yaml:"common"`
+	Atlassian struct {
+		Timeout  string `yaml:"timeout"`
+		CABundle string `yaml:"ca_bundle"`
+		Redact   struct {
+			Fields         []string `yaml:"fields"`
+			AttachmentURLs bool     `yaml:"attachment_urls"`
+		} `yaml:"redact"`
+	} `yaml:"atlassian"`
+}
+
+// LoadSettings reads path (a missing file means defaults) and applies the environment
+// overrides MCP_SERVER_PORT (or PORT), MCP_MAX_RESPONSE_BYTES, MCP_ENABLED_TOOLS,
+// USAGE_DAILY_TOOL_CALLS, USAGE_DAILY_API_BYTES, ATLASSIAN_CA_BUNDLE and MCP_PII_SCAN.
+// Every invalid value is reported in the returned error.
+func LoadSettings(path string) (*Settings, error) {
+	var file settingsFile
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	app := file.Common.App
+
+	settings := &Settings{
+		Port:             DefaultPort,
+		RPCTimeout:       DefaultRPCTimeout,
+		ToolTimeouts:     make(map[string]time.Duration),
+		ToolCacheTTLs:    make(map[string]time.Duration),
+		MaxResponseBytes: DefaultMaxResponseBytes,
+		EnabledTools:     app.EnabledTools,
+		UsageQuota: models.UsageQuota{
+			DailyToolCalls: app.UsageDailyToolCalls,
+			DailyAPIBytes:  app.UsageDailyAPIBytes,
+		},
+		AtlassianTimeout:  DefaultAtlassianTimeout,
+		AtlassianCABundle: file.Atlassian.CABundle,
+		FieldPresets:      make(map[string][]string),
+	}
+	var errs []error
+
+	if app.Port != 0 {
+		settings.Port = app.Port
+	}
+	if v := os.Getenv("MCP_SERVER_PORT"); v != "" {
+		settings.Port = parseInt("MCP_SERVER_PORT", v, &errs)
+	} else if v := os.Getenv("PORT"); v != "" {
+		settings.Port = parseInt("PORT", v, &errs)
+	}
+	if settings.Port < 1 || settings.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d is out of range", settings.Port))
+	}
+
+	if app.RPCTimeout != "" {
+		settings.RPCTimeout = parseDuration("rpc_timeout", app.RPCTimeout, &errs)
+	}
+	for tool, value := range app.ToolTimeouts {
+		settings.ToolTimeouts[tool] = parseDuration("tool_timeouts."+tool, value, &errs)
+	}
+	for tool, value := range app.ToolCacheTTLs {
+		if !models.CacheableTool(tool) {
+			errs = append(errs, fmt.Errorf("tool_cache_ttls: %s results cannot be cached", tool))
+			continue
+		}
+		settings.ToolCacheTTLs[tool] = parseDuration("tool_cache_ttls."+tool, value, &errs)
+	}
+	if file.Atlassian.Timeout != "" {
+		settings.AtlassianTimeout = parseDuration("atlassian.timeout", file.Atlassian.Timeout, &errs)
+	}
+	if v := os.Getenv("ATLASSIAN_CA_BUNDLE"); v != "" {
+		settings.AtlassianCABundle = v
+	}
+	if settings.AtlassianCABundle != "" {
+		if _, err := os.Stat(settings.AtlassianCABundle); err != nil {
+			errs = append(errs, fmt.Errorf("atlassian.ca_bundle: %w", err))
+		}
+	}
+
+	redact := file.Atlassian.Redact
+	if policy, err := models.NormalizeRedactionPolicy(redact.Fields, redact.AttachmentURLs); err != nil {
+		errs = append(errs, fmt.Errorf("atlassian.redact: %w", err))
+	} else {
+		settings.Redaction = policy
+	}
+
+	if app.MaxResponseBytes != nil {
+		settings.MaxResponseBytes = *app.MaxResponseBytes
+	}
+	if v := os.Getenv("MCP_MAX_RESPONSE_BYTES"); v != "" {
+		settings.MaxResponseBytes = parseInt("MCP_MAX_RESPONSE_BYTES", v, &errs)
+	}
+	if settings.MaxResponseBytes < 0 {
+		errs = append(errs, fmt.Errorf("max_response_bytes must not be negative"))
+	}
+
+	if v := os.Getenv("MCP_ENABLED_TOOLS"); v != "" {
+		settings.EnabledTools = strings.Split(v, ",")
+	}
+	for i, tool := range settings.EnabledTools {
+		settings.EnabledTools[i] = strings.TrimSpace(tool)
+	}
+
+	if v := os.Getenv("USAGE_DAILY_TOOL_CALLS"); v != "" {
+		settings.UsageQuota.DailyToolCalls = int64(parseInt("USAGE_DAILY_TOOL_CALLS", v, &errs))
+	}
+	if v := os.Getenv("USAGE_DAILY_API_BYTES"); v != "" {
+		settings.UsageQuota.DailyAPIBytes = int64(parseInt("USAGE_DAILY_API_BYTES", v, &errs))
+	}
+	if settings.UsageQuota.DailyToolCalls < 0 || settings.UsageQuota.DailyAPIBytes < 0 {
+		errs = append(errs, fmt.Errorf("usage quotas must not be negative"))
+	}
+
+	piiScan := app.PIIScan
+	if v := os.Getenv("MCP_PII_SCAN"); v != "" {
+		piiScan = v
+	}
+	if mode, err := pii.ParseMode(piiScan); err != nil {
+		errs = append(errs, fmt.Errorf("pii_scan: %w", err))
+	} else {
+		settings.PIIScan = mode
+	}
+
+	for name, fields := range app.FieldPresets {
+		preset, err := models.NormalizeFieldPreset(name, fields)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field_presets.%s: %w", name, err))
+			continue
+		}
+		settings.FieldPresets[preset.Name] = preset.Fields
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return settings, nil
+}
+
+// ToolTimeout returns the timeout for a tool, falling back to RPCTimeout
+func (s *Settings) ToolTimeout(tool string) time.Duration {
+	// Every version of a tool (e.g. jira_list_issues@v2) has its timeout
+	tool, _, _ = strings.Cut(tool, "@")
+	if d, ok := s.ToolTimeouts[tool]; ok {
+		return d
+	}
+	return s.RPCTimeout
+}
+
+// ToolEnabled reports whether a tool is on the server-wide allowlist
+func (s *Settings) ToolEnabled(tool string) bool {
+	if len(s.EnabledTools) == 0 {
+		return true
+	}
+	for _, enabled := range s.EnabledTools {
+		if enabled == tool {
+			return true
+		}
+	}
+	return false
+}
+
+func parseInt(name, value string, errs *[]error) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %q is not a number", name, value))
+	}
+	return n
+}
+
+func parseDuration(name, value string, errs *[]error) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || d <= 0 {
+		*errs = append(*errs, fmt.Errorf("%s: %q is not a positive duration", name, value))
+	}
+	return d
+}
+
+// SettingsWatcher holds the current settings and reloads them when the config file
+// changes or the process receives SIGHUP. An invalid file is logged and the previous
+// settings stay in effect.
+type SettingsWatcher struct {
+	path     string
+	current  atomic.Pointer[Settings]
+	mu       sync.Mutex
+	onReload []func(*Settings)
+}
+
+// NewSettingsWatcher loads the settings once; call Start to begin watching
+func NewSettingsWatcher(path string) (*SettingsWatcher, error) {
+	settings, err := LoadSettings(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &SettingsWatcher{path: filepath.Clean(path)}
+	w.current.Store(settings)
+	return w, nil
+}
+
+// Current returns the settings in effect
+func (w *SettingsWatcher) Current() *Settings {
+	return w.current.Load()
+}
+
+// OnReload registers a callback that receives the new settings after every reload
+func (w *SettingsWatcher) OnReload(fn func(*Settings)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onReload = append(w.onReload, fn)
+}
+
+// Reload re-reads the settings and, if they are valid, makes them current
+func (w *SettingsWatcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	settings, err := LoadSettings(w.path)
+	if err != nil {
+		return err
+	}
+	previous := w.current.Swap(settings)
+	if previous.Port != settings.Port {
+		slog.Warn("port change ignored until restart", "port", previous.Port, "configured_port", settings.Port)
+	}
+	for _, fn := range w.onReload {
+		fn(settings)
+	}
+	slog.Info("configuration reloaded", "path", w.path)
+	return nil
+}
+
+// Start reloads on SIGHUP and whenever the config file is written or replaced. The
+// directory is watched because editors and ConfigMap updates swap the file out.
+func (w *SettingsWatcher) Start() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	// Nil channels (no watcher) simply never fire
+	var events chan fsnotify.Event
+	var watchErrors chan error
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = watcher.Add(filepath.Dir(w.path)); err != nil {
+			watcher.Close()
+		} else {
+			events, watchErrors = watcher.Events, watcher.Errors
+		}
+	}
+	if err != nil {
+		slog.Warn("config file watcher unavailable, reload with SIGHUP", "path", w.path, "error", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-hup:
+			case event := <-events:
+				// Kubernetes updates mounted ConfigMaps by swapping the ..data symlink
+				if filepath.Clean(event.Name) != w.path && filepath.Base(event.Name) != "..data" {
+					continue
+				}
+			case err := <-watchErrors:
+				slog.Warn("config file watcher error", "error", err)
+				continue
+			}
+			if err := w.Reload(); err != nil {
+				slog.Error("configuration reload failed, keeping previous settings", "path", w.path, "error", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultToolsFile is where operators define passthrough tools unless TOOLS_FILE says otherwise
+const DefaultToolsFile = "tools.yaml"
+
+// toolNamePattern is what a passthrough tool may be called; the prefix picks the service
+var toolNamePattern = regexp.MustCompile(`^(jira|confluence)_[a-z0-9_]{1,60}$`)
+
+// pathParamPattern finds the {param} placeholders of a path template
+var pathParamPattern = regexp.MustCompile(`\{([a-z0-9_]+)\}`)
+
+// postReadPaths are the endpoints a POST tool may request, by service. Passthrough tools
+// count as reads for scopes and read-only workspaces, so POST is limited to endpoints
+// that take their query in the body without changing data.
+var postReadPaths = map[string]map[string]bool{
+	"jira": {
+		"/rest/api/3/search":                   true,
+		"/rest/api/3/search/jql":               true,
+		"/rest/api/3/search/approximate-count": true,
+		"/rest/api/3/issue/bulkfetch":          true,
+		"/rest/api/3/jql/match":                true,
+		"/rest/api/3/jql/parse":                true,
+	},
+	"confluence": {
+		"/api/v2/content/convert-ids-to-types": true,
+	},
+}
+
+// reservedActionPrefixes are the action prefixes of the Bitbucket, Opsgenie and directory
+// tools the Jira service serves, which need their own scopes
+var reservedActionPrefixes = []string{"bitbucket_", "opsgenie_", "admin_"}
+
+// ToolDefinition is a passthrough tool defined in tools.yaml. A call requests one
+// Atlassian REST endpoint with the workspace's credentials and returns its JSON as is,
+// so read-only endpoints can be exposed without code.
+type ToolDefinition struct {
+	Name        string      `yaml:"name"` // jira_* or confluence_*, served by that service
+	Description string      `yaml:"description"`
+	Method      string      `yaml:"method"` // GET (default), or POST for the searches in postReadPaths
+	Path        string      `yaml:"path"`   // Relative to the product's URL, e.g. /rest/api/3/issue/{issue_key}/votes
+	Params      []ToolParam `yaml:"params"`
+}
+
+// ToolParam is an argument of a passthrough tool
+type ToolParam struct {
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"` // string (default), number, integer or boolean
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	In          string `yaml:"in"` // path for placeholders (implied), otherwise query (default for GET) or body (default for POST)
+}
+
+// LoadToolDefinitions reads the passthrough tools in path (a missing file means none).
+// Every invalid definition is reported in the returned error.
+func LoadToolDefinitions(path string) ([]ToolDefinition, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Tools []ToolDefinition `yaml:"tools"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	for i := range file.Tools {
+		def := &file.Tools[i]
+		if err := def.normalize(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		if seen[def.Name] {
+			errs = append(errs, fmt.Errorf("%s: tool %s is defined twice", path, def.Name))
+		}
+		seen[def.Name] = true
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return file.Tools, nil
+}
+
+// ToolDefinitionsFromEnv loads the passthrough tools in TOOLS_FILE, or tools.yaml
+func ToolDefinitionsFromEnv() ([]ToolDefinition, error) {
+	path := os.Getenv("TOOLS_FILE")
+	if path == "" {
+		path = DefaultToolsFile
+	}
+	return LoadToolDefinitions(path)
+}
+
+// normalize checks a definition and fills in its defaults
+func (d *ToolDefinition) normalize() error {
+	if !toolNamePattern.MatchString(d.Name) {
+		return fmt.Errorf("tool name %q must be jira_ or confluence_ followed by lowercase letters, digits or underscores", d.Name)
+	}
+	if strings.TrimSpace(d.Description) == "" {
+		return fmt.Errorf("tool %s has no description", d.Name)
+	}
+	d.Method = strings.ToUpper(d.Method)
+	switch d.Method {
+	case "":
+		d.Method = "GET"
+	case "GET", "POST":
+	default:
+		return fmt.Errorf("tool %s: method must be GET or POST, not %s", d.Name, d.Method)
+	}
+	if !strings.HasPrefix(d.Path, "/") || strings.Contains(d.Path, "..") || strings.ContainsAny(d.Path, "?#") {
+		return fmt.Errorf("tool %s: path must be an absolute path without a query, e.g. /rest/api/3/issue/{issue_key}/votes", d.Name)
+	}
+	if d.Method == "POST" && !postReadPaths[d.Service()][d.Path] {
+		return fmt.Errorf("tool %s: POST is only allowed for read endpoints such as searches, not %s", d.Name, d.Path)
+	}
+	if err := d.checkAction(); err != nil {
+		return err
+	}
+
+	if strings.ContainsAny(pathParamPattern.ReplaceAllString(d.Path, ""), "{}") {
+		return fmt.Errorf("tool %s: path placeholders must be lowercase, e.g. {issue_key}", d.Name)
+	}
+	placeholders := make(map[string]bool)
+	for _, match := range pathParamPattern.FindAllStringSubmatch(d.Path, -1) {
+		placeholders[match[1]] = true
+	}
+	params := make(map[string]bool)
+	for i := range d.Params {
+		p := &d.Params[i]
+		if p.Name == "" || p.Name == "workspace_id" || params[p.Name] {
+			return fmt.Errorf("tool %s: parameter %q is empty, reserved or repeated", d.Name, p.Name)
+		}
+		params[p.Name] = true
+		switch p.Type {
+		case "":
+			p.Type = "string"
+		case "string", "number", "integer", "boolean":
+		default:
+			return fmt.Errorf("tool %s: parameter %s has unknown type %s", d.Name, p.Name, p.Type)
+		}
+		switch {
+		case placeholders[p.Name]:
+			// Every placeholder needs a value
+			p.In = "path"
+			p.Required = true
+		case p.In == "path":
+			return fmt.Errorf("tool %s: path has no {%s} placeholder", d.Name, p.Name)
+		case p.In == "" && d.Method == "POST":
+			p.In = "body"
+		case p.In == "":
+			p.In = "query"
+		case p.In == "body" && d.Method != "POST":
+			return fmt.Errorf("tool %s: parameter %s is sent in the body, which only POST has", d.Name, p.Name)
+		case p.In != "query" && p.In != "body":
+			return fmt.Errorf("tool %s: parameter %s must be in path, query or body", d.Name, p.Name)
+		}
+	}
+	for name := range placeholders {
+		if !params[name] {
+			return fmt.Errorf("tool %s: path placeholder {%s} is not a parameter", d.Name, name)
+		}
+	}
+	return nil
+}
+
+// checkAction rejects a tool whose action the service already serves: the service would
+// run the built-in handler, under the passthrough tool's scope and policy checks
+func (d ToolDefinition) checkAction() error {
+	action := d.Action()
+	reserved := action == atlassian.RecordingsAction
+	switch d.Service() {
+	case "jira":
+		reserved = reserved || models.JiraMutatingActions[action]
+		for _, prefix := range reservedActionPrefixes {
+			reserved = reserved || strings.HasPrefix(action, prefix)
+		}
+	case "confluence":
+		reserved = reserved || models.ConfluenceMutatingActions[action]
+	}
+	if reserved {
+		return fmt.Errorf("tool %s: %s is a built-in %s action", d.Name, action, d.Service())
+	}
+	return nil
+}
+
+// Service returns the service that serves the tool, "jira" or "confluence"
+func (d ToolDefinition) Service() string {
+	service, _, _ := strings.Cut(d.Name, "_")
+	return service
+}
+
+// Action returns the action the service knows the tool by: its name without the service
+func (d ToolDefinition) Action() string {
+	return strings.TrimPrefix(d.Name, d.Service()+"_")
+}
+
+// InputSchema returns the JSON Schema of the tool's arguments, which include workspace_id
+func (d ToolDefinition) InputSchema() map[string]interface{} {
+	properties := map[string]interface{}{
+		"workspace_id": map[string]interface{}{
+			"type":        "string",
+			"description": "Workspace ID",
+		},
+	}
+	required := []string{"workspace_id"}
+	for _, p := range d.Params {
+		properties[p.Name] = map[string]interface{}{
+			"type":        p.Type,
+			"description": p.Description,
+		}
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// Request builds the request for a call: the path with its placeholders filled in, the
+// query string and the JSON body (nil for GET). Arguments that are not parameters are ignored.
+func (d ToolDefinition) Request(args map[string]interface{}) (string, url.Values, map[string]interface{}, error) {
+	path := d.Path
+	query := url.Values{}
+	var body map[string]interface{}
+	if d.Method == "POST" {
+		body = make(map[string]interface{})
+	}
+
+	for _, p := range d.Params {
+		value, ok := args[p.Name]
+		if !ok || value == nil || value == "" {
+			if p.Required {
+				return "", nil, nil, fmt.Errorf("missing %s", p.Name)
+			}
+			continue
+		}
+		if p.In == "body" {
+			body[p.Name] = value
+			continue
+		}
+		text, err := paramText(p, value)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if p.In == "path" {
+			if text == "." || text == ".." {
+				return "", nil, nil, fmt.Errorf("invalid %s %q", p.Name, text)
+			}
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(text))
+		} else {
+			query.Set(p.Name, text)
+		}
+	}
+	return path, query, body, nil
+}
+
+// paramText formats a path or query argument
+func paramText(p ToolParam, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return "", fmt.Errorf("%s must be a %s", p.Name, p.Type)
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// CredentialEventsExchange is the fanout exchange used to broadcast workspace
+// credential changes to every service instance
+const CredentialEventsExchange = "trilix.credential.events"
+
+// Credential event types
+const (
+	CredentialUpdated = "updated"
+	CredentialDeleted = "deleted"
+)
+
+// CredentialEvent notifies subscribers that a workspace's credentials changed
+type CredentialEvent struct {
+	Type        string `json:"type"`
+	UserID      string `json:"user_id"`
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// PublishCredentialEvent broadcasts a credential change on the CredentialEvents exchange
+func PublishCredentialEvent(ch *amqp.Channel, event CredentialEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return publishFanout(ch, CredentialEventsExchange, body)
+}
+
+// SubscribeCredentialEvents binds an exclusive, auto-deleted queue to the
+// CredentialEvents exchange and calls handler for every event received
+func SubscribeCredentialEvents(ch *amqp.Channel, handler func(CredentialEvent)) error {
+	return subscribeFanout(ch, CredentialEventsExchange, func(body []byte) {
+		var event CredentialEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			slog.Warn("ignoring malformed credential event", "error", err)
+			return
+		}
+		handler(event)
+	})
+}
@@ -0,0 +1,89 @@
+package events
+
+import (
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// declareFanoutExchange makes sure a durable fanout exchange exists
+func declareFanoutExchange(ch *amqp.Channel, exchange string) error {
+	return ch.ExchangeDeclare(
+		exchange, // name
+		"fanout", // kind
+		true,     // durable
+		false,    // auto-delete
+		false,    // internal
+		false,    // no-wait
+		nil,      // args
+	)
+}
+
+// publishFanout publishes a JSON body on a fanout exchange
+func publishFanout(ch *amqp.Channel, exchange string, body []byte) error {
+	if ch == nil {
+		return fmt.Errorf("amqp channel not available")
+	}
+	if err := declareFanoutExchange(ch, exchange); err != nil {
+		return fmt.Errorf("failed to declare %s: %w", exchange, err)
+	}
+
+	return ch.Publish(
+		exchange, // exchange
+		"",       // routing key (ignored by fanout)
+		false,    // mandatory
+		false,    // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+}
+
+// subscribeFanout binds an exclusive, auto-deleted queue to a fanout exchange and
+// calls handler with the body of every message received
+func subscribeFanout(ch *amqp.Channel, exchange string, handler func([]byte)) error {
+	if ch == nil {
+		return fmt.Errorf("amqp channel not available")
+	}
+	if err := declareFanoutExchange(ch, exchange); err != nil {
+		return fmt.Errorf("failed to declare %s: %w", exchange, err)
+	}
+
+	q, err := ch.QueueDeclare(
+		"",    // name (server generated)
+		false, // durable
+		true,  // auto-delete
+		true,  // exclusive
+		false, // no-wait
+		nil,   // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare queue for %s: %w", exchange, err)
+	}
+
+	if err := ch.QueueBind(q.Name, "", exchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue to %s: %w", exchange, err)
+	}
+
+	msgs, err := ch.Consume(
+		q.Name, // queue
+		"",     // consumer
+		true,   // auto-ack
+		true,   // exclusive
+		false,  // no-local
+		false,  // no-wait
+		nil,    // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to consume %s: %w", exchange, err)
+	}
+
+	go func() {
+		for d := range msgs {
+			handler(d.Body)
+		}
+	}()
+
+	return nil
+}
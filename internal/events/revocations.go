@@ -0,0 +1,45 @@
+package events
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RevocationEventsExchange is the fanout exchange used to tell every MCP server
+// replica that an API key or service token was revoked
+const RevocationEventsExchange = "trilix.revocation.events"
+
+// Kinds of revoked credentials
+const (
+	RevokedAPIKey       = "api_key"
+	RevokedServiceToken = "service_token"
+)
+
+// RevocationEvent notifies subscribers that a bearer credential was revoked
+type RevocationEvent struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+}
+
+// PublishRevocationEvent broadcasts a revocation on the RevocationEvents exchange
+func PublishRevocationEvent(ch *amqp.Channel, event RevocationEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return publishFanout(ch, RevocationEventsExchange, body)
+}
+
+// SubscribeRevocationEvents calls handler for every revocation broadcast by any replica
+func SubscribeRevocationEvents(ch *amqp.Channel, handler func(RevocationEvent)) error {
+	return subscribeFanout(ch, RevocationEventsExchange, func(body []byte) {
+		var event RevocationEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			slog.Warn("ignoring malformed revocation event", "error", err)
+			return
+		}
+		handler(event)
+	})
+}
@@ -0,0 +1,56 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/atlassian"
+)
+
+// DefaultAtlassianProbeURL is requested when ATLASSIAN_PROBE_URL is not set
+const DefaultAtlassianProbeURL = "https://api.atlassian.com/"
+
+// atlassianProbeInterval is how long a probe result is reused, so frequent readiness
+// probes do not turn into a steady stream of requests to Atlassian
+const atlassianProbeInterval = 30 * time.Second
+
+// AtlassianProbeFromEnv returns a check that Atlassian Cloud is reachable, or nil
+// when ATLASSIAN_PROBE_URL is set to an empty value or ATLASSIAN_MOCK_MODE is on.
+// Any HTTP response counts as reachable; only network and TLS failures fail the check.
+func AtlassianProbeFromEnv() Check {
+	url, ok := os.LookupEnv("ATLASSIAN_PROBE_URL")
+	if !ok {
+		url = DefaultAtlassianProbeURL
+	}
+	if mock, _ := strconv.ParseBool(os.Getenv("ATLASSIAN_MOCK_MODE")); url == "" || mock {
+		return nil
+	}
+
+	// Probed the way the Atlassian clients connect: through HTTPS_PROXY, trusting the CA bundle
+	client := &http.Client{Timeout: 5 * time.Second, Transport: atlassian.Transport("")}
+	var (
+		mu      sync.Mutex
+		checked time.Time
+		lastErr error
+	)
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if time.Since(checked) < atlassianProbeInterval {
+			return lastErr
+		}
+		resp, err := client.Head(url)
+		if err != nil {
+			lastErr = fmt.Errorf("atlassian unreachable: %w", err)
+		} else {
+			resp.Body.Close()
+			lastErr = nil
+		}
+		checked = time.Now()
+		return lastErr
+	}
+}
@@ -0,0 +1,160 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check reports whether a dependency is usable; nil means it is
+type Check func() error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Report is the body of the health and readiness endpoints:
+// {"status": "UP", "details": {"database": "UP", ...}, "latency_ms": {"database": 2, ...},
+// "storage": "postgres", "version": "v1.0.0"}
+type Report struct {
+	Status    string            `json:"status"`
+	Details   map[string]string `json:"details"`
+	LatencyMS map[string]int64  `json:"latency_ms"`
+	Storage   string            `json:"storage,omitempty"` // Credential store backend
+	Version   string            `json:"version"`
+}
+
+// Checks is a set of dependency checks served as a health endpoint
+type Checks struct {
+	version string
+	storage string
+
+	mu     sync.Mutex
+	checks []namedCheck
+}
+
+// NewChecks creates an empty set of checks
+func NewChecks(version string) *Checks {
+	return &Checks{version: version}
+}
+
+// Add registers a check; nil checks are ignored
+func (c *Checks) Add(name string, check Check) {
+	if check == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetStorage names the credential store backend in reports, e.g. "postgres"
+func (c *Checks) SetStorage(backend string) {
+	c.storage = backend
+}
+
+// Run runs every check at once and reports each one's state and how long it took
+func (c *Checks) Run() Report {
+	c.mu.Lock()
+	checks := append([]namedCheck(nil), c.checks...)
+	c.mu.Unlock()
+
+	report := Report{
+		Status:    "UP",
+		Details:   make(map[string]string, len(checks)),
+		LatencyMS: make(map[string]int64, len(checks)),
+		Storage:   c.storage,
+		Version:   c.version,
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+			start := time.Now()
+			err := nc.check()
+			elapsed := time.Since(start).Milliseconds()
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.LatencyMS[nc.name] = elapsed
+			if err != nil {
+				report.Status = "DOWN"
+				report.Details[nc.name] = fmt.Sprintf("DOWN: %v", err)
+			} else {
+				report.Details[nc.name] = "UP"
+			}
+		}(nc)
+	}
+	wg.Wait()
+	return report
+}
+
+// Handler answers 200 when every check passes and 503 otherwise, with the Report
+func (c *Checks) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeReport(w, c.Run())
+	})
+}
+
+// Readiness serves a readiness probe. It reports not ready until MarkReady is
+// called, after MarkNotReady (e.g. while shutting down), or when any check fails.
+type Readiness struct {
+	*Checks
+	ready atomic.Bool
+}
+
+// NewReadiness creates a probe that is not ready yet
+func NewReadiness(version string) *Readiness {
+	return &Readiness{Checks: NewChecks(version)}
+}
+
+// MarkReady reports ready once startup has finished
+func (r *Readiness) MarkReady() {
+	r.ready.Store(true)
+}
+
+// MarkNotReady stops traffic from being routed here, e.g. before shutting down
+func (r *Readiness) MarkNotReady() {
+	r.ready.Store(false)
+}
+
+// Handler answers 200 when ready and 503 otherwise, with the Report
+func (r *Readiness) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := r.Run()
+		if !r.ready.Load() {
+			report.Status = "DOWN"
+			report.Details["startup"] = "not ready"
+		}
+		writeReport(w, report)
+	})
+}
+
+// LiveHandler serves a liveness probe: it answers 200 while the process can serve HTTP
+// and checks no dependencies, so an outage elsewhere does not restart the process
+func LiveHandler(version string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "UP",
+			"version": version,
+		})
+	})
+}
+
+// writeReport writes a report with 200 when it is UP and 503 otherwise
+func writeReport(w http.ResponseWriter, report Report) {
+	code := http.StatusOK
+	if report.Status != "UP" {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report)
+}
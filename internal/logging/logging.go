@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Setup installs the process-wide slog logger. LOG_LEVEL selects the minimum
+// level (debug, info, warn, error; default info) and LOG_FORMAT selects "json"
+// or "text" (default). Every record carries the service name and, when logged
+// with a context, its request ID. Secrets are redacted (see Redact).
+func Setup(service string) *slog.Logger {
+	logger := New(os.Stderr, os.Getenv("LOG_FORMAT"), ParseLevel(os.Getenv("LOG_LEVEL"))).With("service", service)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// New creates a redacting, request-ID-aware logger writing to w
+func New(w io.Writer, format string, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, "json") {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// ParseLevel converts a LOG_LEVEL value to a slog level, defaulting to info
+func ParseLevel(value string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// contextHandler adds the request ID carried by the context to every record and
+// keeps warnings and errors for RecentErrors
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	id := RequestIDFromContext(ctx)
+	if r.Level >= slog.LevelWarn {
+		recordRecent(r, id)
+	}
+	if id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
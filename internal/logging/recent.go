@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// recentLimit is how many warnings and errors are kept for RecentErrors
+const recentLimit = 200
+
+// RecentError is a warning or error logged by this process
+type RecentError struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"` // Redacted like the log output
+}
+
+// recentErrors is a ring buffer of the latest warnings and errors
+var recentErrors struct {
+	mu      sync.Mutex
+	entries []RecentError
+	next    int
+}
+
+// recordRecent keeps a redacted copy of a warning or error record
+func recordRecent(r slog.Record, requestID string) {
+	entry := RecentError{Time: r.Time, Level: r.Level.String(), Message: r.Message}
+	if r.NumAttrs() > 0 || requestID != "" {
+		entry.Attrs = make(map[string]string, r.NumAttrs()+1)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		a = redactAttr(nil, a)
+		entry.Attrs[a.Key] = a.Value.String()
+		return true
+	})
+	if requestID != "" {
+		entry.Attrs["request_id"] = requestID
+	}
+
+	recentErrors.mu.Lock()
+	defer recentErrors.mu.Unlock()
+	if len(recentErrors.entries) < recentLimit {
+		recentErrors.entries = append(recentErrors.entries, entry)
+		return
+	}
+	recentErrors.entries[recentErrors.next] = entry
+	recentErrors.next = (recentErrors.next + 1) % recentLimit
+}
+
+// RecentErrors returns up to limit of the latest warnings and errors, newest first
+func RecentErrors(limit int) []RecentError {
+	recentErrors.mu.Lock()
+	defer recentErrors.mu.Unlock()
+
+	n := len(recentErrors.entries)
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	errors := make([]RecentError, 0, limit)
+	for i := 0; i < limit; i++ {
+		// The newest entry sits just before next (which stays 0 until the buffer wraps)
+		idx := (recentErrors.next - 1 - i + 2*n) % n
+		errors = append(errors, recentErrors.entries[idx])
+	}
+	return errors
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces secret values in log output
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute names (compared case-insensitively, ignoring "-" and "_")
+// whose values are never logged
+var sensitiveKeys = []string{"authorization", "token", "apitoken", "secret", "password", "apikey", "cookie"}
+
+// authSchemePattern matches bearer/basic authorization values; the scheme is kept
+var authSchemePattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+
+// secretPatterns match credentials embedded in free-form strings: Trilix API keys
+// and service tokens, and JWTs
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\btr[xs]_[A-Za-z0-9_-]+`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+}
+
+// Redact masks credentials that appear inside s
+func Redact(s string) string {
+	s = authSchemePattern.ReplaceAllString(s, "$1 "+Redacted)
+	for _, re := range secretPatterns {
+		s = re.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// redactAttr is the slog ReplaceAttr hook that hides sensitive attributes
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if isSensitiveKey(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+	}
+	return a
+}
+
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.HasSuffix(normalized, sensitive) {
+			return true
+		}
+	}
+	return false
+}
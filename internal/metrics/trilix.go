@@ -0,0 +1,76 @@
+package metrics
+
+// Metrics shared by the MCP server and the Jira and Confluence services. Each
+// process exposes its own values on /metrics; Prometheus tells them apart by job.
+var (
+	// ToolCalls counts tool calls by tool, workspace and outcome ("ok" or "error")
+	ToolCalls = NewCounterVec("trilix_tool_calls_total",
+		"Tool calls handled, by tool, workspace and status.",
+		"tool", "workspace", "status")
+
+	// ToolCallDuration measures end-to-end tool call latency
+	ToolCallDuration = NewHistogramVec("trilix_tool_call_duration_seconds",
+		"Tool call latency in seconds, by tool and workspace.",
+		nil, "tool", "workspace")
+
+	// ToolCallOverruns counts tool calls that ran past their timeout
+	ToolCallOverruns = NewCounterVec("trilix_tool_call_overruns_total",
+		"Tool calls still running past their time budget, by tool.",
+		"tool")
+
+	// AMQPMessages counts RabbitMQ messages by queue and direction ("published" or "consumed")
+	AMQPMessages = NewCounterVec("trilix_amqp_messages_total",
+		"RabbitMQ messages published and consumed, by queue and direction.",
+		"queue", "direction")
+
+	// AMQPQueueDepth tracks messages waiting in the RabbitMQ queues a service consumes
+	// (including their dead-letter queues), sampled periodically
+	AMQPQueueDepth = NewGaugeVec("trilix_amqp_queue_depth",
+		"Messages ready in RabbitMQ queues, by queue.",
+		"queue")
+
+	// AMQPInFlight tracks deliveries currently being handled by a service's workers
+	AMQPInFlight = NewGaugeVec("trilix_amqp_in_flight",
+		"RabbitMQ deliveries currently being processed, by queue.",
+		"queue")
+
+	// AMQPReconnects counts consumer reconnects after the broker connection was lost
+	AMQPReconnects = NewCounterVec("trilix_amqp_reconnects_total",
+		"RabbitMQ consumer reconnects, by service.",
+		"service")
+
+	// AtlassianResponses counts Atlassian REST responses by HTTP status code
+	AtlassianResponses = NewCounterVec("trilix_atlassian_http_responses_total",
+		"Responses received from Atlassian Cloud, by HTTP status code.",
+		"code")
+
+	// AtlassianRateLimitRemaining tracks the rate limit budget Atlassian last reported for a
+	// workspace, from its X-RateLimit-Remaining header
+	AtlassianRateLimitRemaining = NewGaugeVec("trilix_atlassian_rate_limit_remaining",
+		"Atlassian rate limit budget left as last reported, by workspace.",
+		"workspace")
+
+	// AtlassianThrottledCalls counts Atlassian calls held back because a workspace's rate
+	// limit budget was low or exhausted, by outcome ("delayed" or "rejected")
+	AtlassianThrottledCalls = NewCounterVec("trilix_atlassian_throttled_calls_total",
+		"Atlassian calls delayed or rejected to stay within the rate limit, by workspace and outcome.",
+		"workspace", "outcome")
+
+	// CredentialStoreDuration measures credential store (database or file) latency
+	CredentialStoreDuration = NewHistogramVec("trilix_credential_store_duration_seconds",
+		"Credential store operation latency in seconds, by operation.",
+		nil, "operation")
+
+	// CredentialCacheLookups counts credential cache lookups by result ("hit" or "miss")
+	CredentialCacheLookups = NewCounterVec("trilix_credential_cache_lookups_total",
+		"Credential cache lookups, by result.",
+		"result")
+)
+
+// Status labels a tool call outcome for ToolCalls
+func Status(failed bool) string {
+	if failed {
+		return "error"
+	}
+	return "ok"
+}
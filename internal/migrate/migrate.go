@@ -0,0 +1,210 @@
+// Package migrate applies versioned SQL migrations embedded in the binary.
+//
+// Migrations are files named <version>_<description>.up.sql with an optional
+// matching .down.sql, e.g. 0002_credential_policy.up.sql. Applied versions are
+// tracked per component in the schema_migrations table, so several packages can
+// keep their own migration sets in the same database.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lockID is the Postgres advisory lock held while migrating, so services starting
+// at the same time don't race each other
+const lockID = 727100815
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version     int
+	Description string
+	Up          string
+	Down        string
+}
+
+// Load reads migrations from dir in fsys, ordered by version
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		versionStr, description, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s: %w", name, err)
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Description: description}
+			byVersion[version] = m
+		} else if m.Description != description {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s and %s", version, m.Description, description)
+		}
+		if direction == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no .up.sql file", m.Version, m.Description)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up applies every migration of the component that has not been applied yet.
+// Each migration runs in its own transaction.
+func Up(db *sql.DB, component string, migrations []Migration) error {
+	return withLock(db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(conn, component)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if applied[m.Version] {
+				continue
+			}
+			err := runInTx(conn, m.Up, `INSERT INTO schema_migrations (component, version, description) VALUES ($1, $2, $3)`,
+				component, m.Version, m.Description)
+			if err != nil {
+				return fmt.Errorf("migration %s/%04d_%s failed: %w", component, m.Version, m.Description, err)
+			}
+			slog.Info("applied migration", "component", component, "version", m.Version, "description", m.Description)
+		}
+		return nil
+	})
+}
+
+// Down rolls back the most recent applied migrations of the component, newest first
+func Down(db *sql.DB, component string, migrations []Migration, steps int) error {
+	return withLock(db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(conn, component)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			m := migrations[i]
+			if !applied[m.Version] {
+				continue
+			}
+			if m.Down == "" {
+				return fmt.Errorf("migration %s/%04d_%s cannot be rolled back: no .down.sql file", component, m.Version, m.Description)
+			}
+			err := runInTx(conn, m.Down, `DELETE FROM schema_migrations WHERE component = $1 AND version = $2`,
+				component, m.Version)
+			if err != nil {
+				return fmt.Errorf("rollback of %s/%04d_%s failed: %w", component, m.Version, m.Description, err)
+			}
+			slog.Info("rolled back migration", "component", component, "version", m.Version, "description", m.Description)
+			steps--
+		}
+		return nil
+	})
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory lock
+func withLock(db *sql.DB, fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockID)
+
+	_, err = conn.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		component VARCHAR(100) NOT NULL,
+		version INTEGER NOT NULL,
+		description VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (component, version)
+	);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// appliedVersions returns the versions already applied for a component
+func appliedVersions(conn *sql.Conn, component string) (map[int]bool, error) {
+	rows, err := conn.QueryContext(context.Background(),
+		`SELECT version FROM schema_migrations WHERE component = $1`, component)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// runInTx executes a migration script and its bookkeeping statement atomically
+func runInTx(conn *sql.Conn, script, bookkeeping string, args ...interface{}) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
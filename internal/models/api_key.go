@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// APIKey is a long-lived, user-generated credential for server-to-server integrations.
+// Only a hash of the secret is stored; the secret itself is shown once at creation.
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`           // First characters of the key, to help users tell keys apart
+	Scopes     []string   `json:"scopes,omitempty"` // Empty means unrestricted
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the key can still be used
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -10,8 +10,28 @@ type ConfluenceRequest struct {
 	Action      string         `json:"action"`       // get_page, search, create_page, update_page, list_spaces, copy_page
 	WorkspaceID string         `json:"workspace_id"` // User's workspace label (e.g., "eso", "providentia")
 	UserID      string         `json:"user_id"`      // Clerk user ID
+	OrgID       string         `json:"org_id,omitempty"` // Clerk organization whose shared workspaces are also usable
 	Params      map[string]any `json:"params"`       // Action-specific parameters
 	RequestID   string         `json:"request_id"`   // Correlation ID for tracing
+	Deadline    int64          `json:"deadline,omitempty"` // Unix milliseconds after which the caller stops waiting
+}
+
+// ConfluenceMutatingActions lists the Confluence actions that change data. They are blocked by
+// read-only workspace policies and require the confluence:write scope.
+var ConfluenceMutatingActions = map[string]bool{
+	"create_page":     true,
+	"update_page":     true,
+	"append_to_page":  true,
+	"replace_section": true,
+	"delete_page":     true,
+	"copy_page":       true,
+	"add_comment":     true,
+	"add_label":       true,
+	"insert_diagram":  true,
+	"import_url":      true,
+
+	// Restores a deleted page from the trash; used by undo_last_action
+	"restore_page": true,
 }
 
 // ConfluenceResponse represents a response from the Confluence service
@@ -19,17 +39,37 @@ type ConfluenceResponse struct {
 	Success   bool      `json:"success"`
 	Data      any       `json:"data,omitempty"`
 	Error     *ErrorInfo `json:"error,omitempty"`
-	RequestID string    `json:"request_id"`
+	RequestID string     `json:"request_id"`
+	Usage     *UsageInfo `json:"usage,omitempty"`
+	Cached    *CacheInfo `json:"cached,omitempty"` // Set when served from the MCP server's result cache
+	Undo      *UndoAction `json:"undo,omitempty"` // Reverts the change, for reversible actions
 }
 
 // ConfluencePage represents a Confluence page
 type ConfluencePage struct {
-	ID      string       `json:"id"`
-	Title   string       `json:"title"`
-	Version VersionInfo  `json:"version"`
-	Body    PageBody     `json:"body"`
-	Space   SpaceRef     `json:"space,omitempty"`
-	Links   PageLinks    `json:"_links,omitempty"`
+	ID        string        `json:"id"`
+	Type      string        `json:"type,omitempty"`   // page or blogpost
+	Status    string        `json:"status,omitempty"` // current, draft or archived
+	Title     string        `json:"title"`
+	Version   VersionInfo   `json:"version"`
+	Body      PageBody      `json:"body"`
+	Space     SpaceRef      `json:"space,omitempty"`
+	Ancestors []AncestorRef `json:"ancestors,omitempty"` // Parent pages, root first, when expanded
+	Metadata  *PageMetadata `json:"metadata,omitempty"`  // The page's labels, when expanded
+	Links     PageLinks     `json:"_links,omitempty"`
+}
+
+// PageMetadata holds a page's labels (expand=metadata.labels)
+type PageMetadata struct {
+	Labels struct {
+		Results []PageLabel `json:"results"`
+	} `json:"labels"`
+}
+
+// PageLabel is a label on a page. Global labels have the prefix "global"; personal ones "my".
+type PageLabel struct {
+	Prefix string `json:"prefix"`
+	Name   string `json:"name"`
 }
 
 // PageBody contains the page content
@@ -45,7 +85,9 @@ type StorageContent struct {
 
 // VersionInfo contains version information
 type VersionInfo struct {
-	Number int `json:"number"`
+	Number int             `json:"number"`
+	When   string          `json:"when,omitempty"` // When the version was published, e.g. "2026-01-15T09:30:00.000Z"
+	By     *ConfluenceUser `json:"by,omitempty"`   // Who published it
 }
 
 // SpaceRef references a Confluence space
@@ -82,7 +124,29 @@ func (s *SpaceRef) UnmarshalJSON(data []byte) error {
 
 // PageLinks contains navigation links
 type PageLinks struct {
-	WebUI string `json:"webui,omitempty"`
+	WebUI  string `json:"webui,omitempty"`
+	TinyUI string `json:"tinyui,omitempty"` // Short link that survives moves and renames, e.g. "/x/AbCd"
+	Base   string `json:"base,omitempty"`   // The site's Confluence URL, on single pages
+}
+
+// ReadRestriction lists the users and groups a view restriction on a page lets see it.
+// A page without one is visible to everyone who can view its space.
+type ReadRestriction struct {
+	Restrictions struct {
+		User struct {
+			Results []ConfluenceUser `json:"results"`
+		} `json:"user"`
+		Group struct {
+			Results []struct {
+				Name string `json:"name"`
+			} `json:"results"`
+		} `json:"group"`
+	} `json:"restrictions"`
+}
+
+// Restricted reports whether the restriction names anyone, i.e. limits who can view the page
+func (r *ReadRestriction) Restricted() bool {
+	return len(r.Restrictions.User.Results) > 0 || len(r.Restrictions.Group.Results) > 0
 }
 
 // CreatePageRequest represents a request to create a page
@@ -101,7 +165,8 @@ type BodyContent struct {
 
 // AncestorRef references a parent page
 type AncestorRef struct {
-	ID string `json:"id"`
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"` // When expanded
 }
 
 // ConfluenceSpace represents a Confluence space
@@ -144,6 +209,14 @@ type SearchResults struct {
 	Size    int              `json:"size"`
 	Limit   int              `json:"limit"`
 	Start   int              `json:"start"`
+	Links   SearchLinks      `json:"_links,omitempty"`
+}
+
+// SearchLinks holds the site URL that search results' webui links are relative to, and
+// the next page of results, if any
+type SearchLinks struct {
+	Base string `json:"base,omitempty"`
+	Next string `json:"next,omitempty"`
 }
 
 // UserSearchMatch represents a single match in Confluence user search
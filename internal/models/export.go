@@ -0,0 +1,65 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Export file formats
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+)
+
+// Export is a file of Jira issues, Confluence pages or page chunks written by an export
+// tool, which can be downloaded until it expires
+type Export struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Tool        string    `json:"tool"` // jira_export_issues, confluence_export_pages, confluence_chunk_space, jira_export_changes or confluence_export_changes
+	WorkspaceID string    `json:"workspace_id"`
+	Query       string    `json:"query"`  // The JQL or CQL that selected the issues or pages
+	Format      string    `json:"format"` // csv or jsonl
+	Rows        int       `json:"rows"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Truncated   bool      `json:"truncated,omitempty"` // max_rows was reached before the results ran out
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	TokenHash   string    `json:"-"` // SHA-256 of the download link's token
+}
+
+// FileName is the name the export downloads as, e.g. jira-issues-20260115-0930.csv
+func (e Export) FileName() string {
+	return strings.ReplaceAll(strings.Replace(e.Tool, "_export_", "_", 1), "_", "-") + "-" + e.CreatedAt.UTC().Format("20060102-1504") + "." + e.Format
+}
+
+// ContentType is the export's MIME type
+func (e Export) ContentType() string {
+	if e.Format == ExportFormatJSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportSnapshot is what jira_export_changes or confluence_export_changes saw of a query's
+// results in its last run: a fingerprint of each issue (when it was updated) or page (its
+// version), so the next run exports only what changed since
+type ExportSnapshot struct {
+	UserID      string            `json:"user_id"`
+	WorkspaceID string            `json:"workspace_id"`
+	Tool        string            `json:"tool"`
+	Query       string            `json:"query"`
+	Name        string            `json:"name"`                // Tells apart snapshots of the same query, e.g. a backup's and an index's
+	Items       map[string]string `json:"items"`               // Fingerprints by issue key or page ID
+	ExportID    string            `json:"export_id,omitempty"` // The last run's export
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Key identifies the snapshot of a user's query: the hex SHA-256 of its user, workspace,
+// tool, query and name
+func (s ExportSnapshot) Key() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{s.UserID, s.WorkspaceID, s.Tool, s.Query, s.Name}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultFieldPreset is the preset jira_list_issues uses when it is given neither a preset
+// nor fields
+const DefaultFieldPreset = "default"
+
+// maxFieldPresetFields bounds the fields one preset may name
+const maxFieldPresetFields = 100
+
+// fieldPresetNamePattern is what a preset may be called
+var fieldPresetNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// FieldPreset is a named list of Jira fields that searches return, e.g. "triage" with the
+// custom fields for story points, team and severity. Operators define presets in
+// config.yaml; users may save their own, which take precedence over the server's.
+type FieldPreset struct {
+	UserID    string    `json:"user_id,omitempty"` // Empty for the server's presets
+	Name      string    `json:"name"`
+	Fields    []string  `json:"fields"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// NormalizeFieldPreset checks a preset's name and fields, lowercasing the name and
+// dropping blank and repeated fields
+func NormalizeFieldPreset(name string, fields []string) (FieldPreset, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !fieldPresetNamePattern.MatchString(name) {
+		return FieldPreset{}, fmt.Errorf("preset name must be 1-64 lowercase letters, digits, dashes or underscores")
+	}
+	preset := FieldPreset{Name: name}
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		preset.Fields = append(preset.Fields, field)
+	}
+	if len(preset.Fields) == 0 {
+		return FieldPreset{}, fmt.Errorf("preset %s has no fields", name)
+	}
+	if len(preset.Fields) > maxFieldPresetFields {
+		return FieldPreset{}, fmt.Errorf("preset %s has more than %d fields", name, maxFieldPresetFields)
+	}
+	return preset, nil
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// IssueTemplate is a reusable Jira issue, e.g. a team's bug report or incident ticket.
+// Summary, Body and string values in Fields may contain {{variable}} placeholders that
+// are filled in when an issue is created from the template.
+type IssueTemplate struct {
+	ID          string                  `json:"id"`
+	OwnerID     string                  `json:"-"`      // The user, or their organization for shared templates
+	Shared      bool                    `json:"shared"` // Owned by the caller's organization
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"` // What the template is for
+	ProjectKey  string                  `json:"project_key,omitempty"` // Default project; callers may override it
+	IssueType   string                  `json:"issue_type"`
+	Summary     string                  `json:"summary"`
+	Body        string                  `json:"body,omitempty"`   // The issue description
+	Fields      map[string]interface{}  `json:"fields,omitempty"` // Additional fields, e.g. labels or priority
+	Variables   []IssueTemplateVariable `json:"variables"`
+	CreatedBy   string                  `json:"created_by"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+// IssueTemplateVariable is a placeholder of an issue template
+type IssueTemplateVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"` // Used when an optional variable is not given
+}
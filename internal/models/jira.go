@@ -5,8 +5,40 @@ type JiraRequest struct {
 	Action      string         `json:"action"`       // list_issues, get_issue, create_issue, update_issue, add_comment
 	WorkspaceID string         `json:"workspace_id"` // User's workspace label
 	UserID      string         `json:"user_id"`      // Clerk user ID
+	OrgID       string         `json:"org_id,omitempty"` // Clerk organization whose shared workspaces are also usable
 	Params      map[string]any `json:"params"`       // Action-specific parameters
 	RequestID   string         `json:"request_id"`   // Correlation ID
+	Deadline    int64          `json:"deadline,omitempty"` // Unix milliseconds after which the caller stops waiting
+}
+
+// JiraMutatingActions lists the Jira actions that change data. They are blocked by
+// read-only workspace policies and require the jira:write scope.
+var JiraMutatingActions = map[string]bool{
+	"create_issue":      true,
+	"update_issue":      true,
+	"add_comment":       true,
+	"transition_issue":  true,
+	"create_sprint":     true,
+	"update_sprint":     true,
+	"add_worklog":       true,
+	"delete_issue":      true,
+	"create_issue_link": true,
+	"remove_issue_link": true,
+	"create_component":  true,
+	"bulk_transition":   true,
+	"import_issues":     true,
+
+	// Patch part of an issue's description
+	"append_to_description":      true,
+	"update_description_section": true,
+
+	// Served by the MCP server, which creates the issue with create_issue
+	"create_issue_from_template": true,
+
+	// Opsgenie actions are served by the Jira service and named after their tools
+	"opsgenie_acknowledge_alert": true,
+	"opsgenie_close_alert":       true,
+	"opsgenie_create_alert":      true,
 }
 
 // JiraResponse represents a response from the Jira service
@@ -15,6 +47,9 @@ type JiraResponse struct {
 	Data      any        `json:"data,omitempty"`
 	Error     *ErrorInfo `json:"error,omitempty"`
 	RequestID string     `json:"request_id"`
+	Usage     *UsageInfo `json:"usage,omitempty"`
+	Cached    *CacheInfo `json:"cached,omitempty"` // Set when served from the MCP server's result cache
+	Undo      *UndoAction `json:"undo,omitempty"` // Reverts the change, for reversible actions
 }
 
 // JiraIssue represents a Jira issue
@@ -67,6 +102,19 @@ type IssueType struct {
 	Description string `json:"description,omitempty"`
 }
 
+// Priority represents an issue priority
+type Priority struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Component represents a project component
+type Component struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
 // SearchResponse represents Jira search results
 type SearchResponse struct {
 	StartAt       int         `json:"startAt"`
@@ -93,3 +141,73 @@ type Comment struct {
 	Author  *User  `json:"author,omitempty"`
 }
 
+// IssueComment is a comment as Jira's v3 API returns it, with its body in Atlassian
+// Document Format
+type IssueComment struct {
+	ID      string      `json:"id"`
+	Author  *User       `json:"author,omitempty"`
+	Body    interface{} `json:"body"`
+	Created string      `json:"created"`
+	Updated string      `json:"updated,omitempty"`
+}
+
+
+// Sprint is a Jira Software sprint
+type Sprint struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	State        string `json:"state"` // future, active or closed
+	StartDate    string `json:"startDate,omitempty"`
+	EndDate      string `json:"endDate,omitempty"`
+	CompleteDate string `json:"completeDate,omitempty"`
+	Goal         string `json:"goal,omitempty"`
+}
+
+// Board is a Jira Software board
+type Board struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // scrum, kanban or simple
+}
+
+// BoardConfiguration holds the parts of a board's configuration that describe how its
+// sprints are measured
+type BoardConfiguration struct {
+	ColumnConfig struct {
+		Columns []BoardColumn `json:"columns"`
+	} `json:"columnConfig"`
+	Estimation struct {
+		Type  string `json:"type"` // field, or none to count issues
+		Field struct {
+			FieldID     string `json:"fieldId"`
+			DisplayName string `json:"displayName"`
+		} `json:"field"`
+	} `json:"estimation"`
+}
+
+// BoardColumn is a column of a board and the statuses mapped to it
+type BoardColumn struct {
+	Name     string `json:"name"`
+	Statuses []struct {
+		ID string `json:"id"`
+	} `json:"statuses"`
+	Min *int `json:"min,omitempty"` // Column constraints (WIP limits), when set
+	Max *int `json:"max,omitempty"`
+}
+
+// ChangelogEntry is one change to an issue, which may touch several fields
+type ChangelogEntry struct {
+	ID      string          `json:"id"`
+	Created string          `json:"created"`
+	Items   []ChangelogItem `json:"items"`
+}
+
+// ChangelogItem is the change of one field
+type ChangelogItem struct {
+	Field      string `json:"field"`
+	FieldID    string `json:"fieldId,omitempty"`
+	From       string `json:"from"`
+	FromString string `json:"fromString"`
+	To         string `json:"to"`
+	ToString   string `json:"toString"`
+}
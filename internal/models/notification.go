@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Notification channel kinds
+const (
+	NotificationKindSlack = "slack"
+	NotificationKindTeams = "teams"
+)
+
+// NotificationChannel is a Slack or Microsoft Teams incoming webhook a user has
+// configured for notifications. The webhook URL is a secret and is stored encrypted.
+type NotificationChannel struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"` // slack or teams
+	WebhookURL string    `json:"webhook_url"`
+	Events     []string  `json:"events"` // Server events to deliver; empty means all
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Subscribes reports whether the channel receives a server event
+func (c NotificationChannel) Subscribes(event string) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PinnedIssue is a Jira issue a user keeps on their watch list, so that recurring
+// conversations (e.g. standups) can ask for its current state without naming it again
+type PinnedIssue struct {
+	UserID      string    `json:"user_id"`
+	OrgID       string    `json:"org_id,omitempty"` // Organization the issue is fetched in, for team-shared workspaces
+	WorkspaceID string    `json:"workspace_id"`
+	IssueKey    string    `json:"issue_key"`
+	PinnedAt    time.Time `json:"pinned_at"`
+}
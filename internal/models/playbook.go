@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Playbook is a saved, ordered list of tool calls that runs as one call, e.g. "triage a
+// bug": search for duplicates, create the issue if there are none, then notify the team
+type Playbook struct {
+	ID          string             `json:"id"`
+	UserID      string             `json:"user_id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Variables   []PlaybookVariable `json:"variables"`
+	Steps       []PlaybookStep     `json:"steps"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// PlaybookVariable is an input of a playbook, given when it is run
+type PlaybookVariable struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Default     interface{} `json:"default,omitempty"` // Used when an optional variable is not given
+}
+
+// PlaybookStep is one tool call of a playbook. String arguments may contain {{variable}},
+// {{previous}} and {{steps.<id>.output}} placeholders; If skips the step when false.
+type PlaybookStep struct {
+	ID              string                 `json:"id,omitempty"` // Names the step for later placeholders
+	Tool            string                 `json:"tool"`
+	Arguments       map[string]interface{} `json:"arguments"`
+	If              string                 `json:"if,omitempty"`                // e.g. "{{steps.search.output.total}} == 0"
+	ContinueOnError bool                   `json:"continue_on_error,omitempty"` // Run the next steps even if this one fails
+}
+
+// Playbook run and step statuses
+const (
+	PlaybookSucceeded = "succeeded"
+	PlaybookFailed    = "failed"
+	PlaybookSkipped   = "skipped" // Steps only
+)
+
+// PlaybookRun is the outcome of running a playbook
+type PlaybookRun struct {
+	PlaybookID   string               `json:"playbook_id"`
+	PlaybookName string               `json:"playbook_name"`
+	Status       string               `json:"status"` // succeeded or failed
+	Error        string               `json:"error,omitempty"`
+	Steps        []PlaybookStepResult `json:"steps"`
+	StartedAt    time.Time            `json:"started_at"`
+	DurationMs   int64                `json:"duration_ms"`
+}
+
+// PlaybookStepResult is the outcome of one step. Output is embedded as JSON when the
+// tool returned JSON, and as a string otherwise.
+type PlaybookStepResult struct {
+	ID         string          `json:"id,omitempty"`
+	Tool       string          `json:"tool"`
+	Status     string          `json:"status"` // succeeded, failed or skipped
+	Output     json.RawMessage `json:"output,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+}
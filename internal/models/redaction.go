@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// RedactionPolicy hides privacy-sensitive data in the results of the Jira and Confluence
+// services before they reach the model. Operators set it in config.yaml.
+type RedactionPolicy struct {
+	Fields         []string // Case-insensitive glob patterns of keys or Jira custom field names, e.g. "emailAddress", "Salary*"
+	AttachmentURLs bool     // Hide links to attachments' content
+}
+
+// NormalizeRedactionPolicy checks a policy's patterns, dropping blank and repeated ones
+func NormalizeRedactionPolicy(fields []string, attachmentURLs bool) (RedactionPolicy, error) {
+	policy := RedactionPolicy{AttachmentURLs: attachmentURLs}
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" || seen[field] {
+			continue
+		}
+		if _, err := path.Match(field, ""); err != nil {
+			return RedactionPolicy{}, fmt.Errorf("invalid field pattern %q", field)
+		}
+		seen[field] = true
+		policy.Fields = append(policy.Fields, field)
+	}
+	return policy, nil
+}
+
+// Enabled reports whether the policy hides anything
+func (p *RedactionPolicy) Enabled() bool {
+	return p != nil && (len(p.Fields) > 0 || p.AttachmentURLs)
+}
+
+// RedactsField reports whether the values of a key (or of the custom field with that name)
+// are hidden
+func (p *RedactionPolicy) RedactsField(name string) bool {
+	if p == nil || name == "" {
+		return false
+	}
+	name = strings.ToLower(name)
+	for _, pattern := range p.Fields {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
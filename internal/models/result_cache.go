@@ -0,0 +1,52 @@
+package models
+
+import "strings"
+
+// CacheBypassParam is the tool argument that skips the MCP server's result cache
+const CacheBypassParam = "cache_bypass"
+
+// JiraCacheableActions lists the Jira reads whose results the MCP server may cache
+var JiraCacheableActions = map[string]bool{
+	"get_issue":            true,
+	"hydrate_issues":       true,
+	"list_issues":          true,
+	"list_projects":        true,
+	"get_project_issues":   true,
+	"get_project_versions": true,
+	"get_transitions":      true,
+	"get_worklog":          true,
+	"get_agile_boards":     true,
+	"search_fields":        true,
+	"search_users":         true,
+	"get_user_profile":     true,
+}
+
+// ConfluenceCacheableActions lists the Confluence reads whose results the MCP server may cache
+var ConfluenceCacheableActions = map[string]bool{
+	"get_page":          true,
+	"search":            true,
+	"list_spaces":       true,
+	"get_space":         true,
+	"get_page_children": true,
+	"get_comments":      true,
+	"get_labels":        true,
+	"get_attachments":   true,
+	"search_user":       true,
+}
+
+// CacheableTool reports whether results of the tool (e.g. "jira_get_issue") may be cached
+func CacheableTool(name string) bool {
+	if action, ok := strings.CutPrefix(name, "jira_"); ok {
+		return JiraCacheableActions[action]
+	}
+	if action, ok := strings.CutPrefix(name, "confluence_"); ok {
+		return ConfluenceCacheableActions[action]
+	}
+	return false
+}
+
+// CacheInfo is attached to responses served from the MCP server's result cache
+type CacheInfo struct {
+	AgeSeconds int64 `json:"age_seconds"` // Since the result was fetched
+	TTLSeconds int64 `json:"ttl_seconds"` // How long results of the tool are kept
+}
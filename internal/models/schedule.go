@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Schedule is a saved pipeline of tool calls that the server runs on a cron schedule,
+// e.g. a weekly sprint summary published to Confluence
+type Schedule struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"user_id"`
+	OrgID     string          `json:"org_id,omitempty"` // Organization the tools run in, for team-shared workspaces
+	Name      string          `json:"name"`
+	Cron      string          `json:"cron"`     // Five-field cron expression, e.g. "0 9 * * MON"
+	Timezone  string          `json:"timezone"` // IANA time zone the cron expression is evaluated in
+	Steps     []ScheduleStep  `json:"steps"`
+	Output    *ScheduleOutput `json:"output,omitempty"` // Nil keeps the results in the run history only
+	Enabled   bool            `json:"enabled"`
+	NextRunAt *time.Time      `json:"next_run_at,omitempty"`
+	LastRunAt *time.Time      `json:"last_run_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// ScheduleStep is one tool call of a pipeline. String arguments equal to "{{previous}}"
+// are replaced with the previous step's output.
+type ScheduleStep struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// Schedule output types
+const (
+	ScheduleOutputWebhook        = "webhook"
+	ScheduleOutputConfluencePage = "confluence_page"
+)
+
+// ScheduleOutput is where a run's results are delivered
+type ScheduleOutput struct {
+	Type string `json:"type"` // webhook or confluence_page
+
+	// webhook: the results are POSTed as JSON to URL
+	URL string `json:"url,omitempty"`
+
+	// confluence_page: the page titled Title in SpaceKey is created or updated
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	SpaceKey    string `json:"space_key,omitempty"`
+	Title       string `json:"title,omitempty"`
+	ParentID    string `json:"parent_id,omitempty"`
+}
+
+// Schedule run statuses
+const (
+	ScheduleRunRunning   = "running"
+	ScheduleRunSucceeded = "succeeded"
+	ScheduleRunFailed    = "failed"
+)
+
+// ScheduleRun is one execution of a schedule, kept as its history
+type ScheduleRun struct {
+	ID          string               `json:"id"`
+	ScheduleID  string               `json:"schedule_id"`
+	TriggeredBy string               `json:"triggered_by"` // schedule, or manual for runs started through the API
+	Status      string               `json:"status"`
+	Error       string               `json:"error,omitempty"`
+	Steps       []ScheduleStepResult `json:"steps"`
+	StartedAt   time.Time            `json:"started_at"`
+	FinishedAt  *time.Time           `json:"finished_at,omitempty"`
+}
+
+// ScheduleStepResult is the outcome of one step of a run
+type ScheduleStepResult struct {
+	Tool    string `json:"tool"`
+	IsError bool   `json:"is_error,omitempty"`
+	Output  string `json:"output"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ServiceToken authenticates a backend integration (e.g. n8n) that acts on behalf of
+// users. It may only impersonate the user IDs on its allowlist. Only a hash of the
+// secret is stored; the secret itself is shown once at creation.
+type ServiceToken struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Prefix       string     `json:"prefix"`        // First characters of the token, to help admins tell tokens apart
+	AllowedUsers []string   `json:"allowed_users"` // Clerk user IDs the token may act as
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// IssueSubscription watches the Jira issues matching a JQL query and tells its owner when
+// they change, through a notification channel or by POSTing the changes to a webhook
+type IssueSubscription struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	OrgID       string `json:"org_id,omitempty"` // Organization the query runs in, for team-shared workspaces
+	WorkspaceID string `json:"workspace_id"`
+	JQL         string `json:"jql"`
+	ChannelID   string `json:"channel_id,omitempty"`  // Notification channel, or
+	WebhookURL  string `json:"webhook_url,omitempty"` // a URL the changed issues are POSTed to
+
+	CheckedThrough time.Time `json:"checked_through"` // Changes up to this time have been delivered
+	NextCheckAt    time.Time `json:"next_check_at"`
+	LastError      string    `json:"last_error,omitempty"` // Why the last check failed; it is retried
+	CreatedAt      time.Time `json:"created_at"`
+}
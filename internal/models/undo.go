@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UndoAction is the request that reverts a change. The services return it with the
+// result of reversible actions, e.g. a page update carries the update that restores the
+// previous body.
+type UndoAction struct {
+	Action      string                 `json:"action"`
+	Params      map[string]interface{} `json:"params"`
+	Description string                 `json:"description"` // What undoing does, e.g. "restore the previous body of page 123"
+}
+
+// UndoEntry is a change a user made that can still be undone
+type UndoEntry struct {
+	ID          int64      `json:"id"`
+	UserID      string     `json:"user_id"`
+	OrgID       string     `json:"-"`
+	Product     string     `json:"product"` // "jira" or "confluence"
+	WorkspaceID string     `json:"workspace_id"`
+	Tool        string     `json:"tool"` // The tool that made the change
+	Undo        UndoAction `json:"undo"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
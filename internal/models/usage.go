@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// UsageInfo is attached to service responses so the MCP server can meter API volume
+type UsageInfo struct {
+	APIBytes int64 `json:"api_bytes"` // Request + response bytes exchanged with Atlassian
+}
+
+// UsageRecord is one day of metered usage for a user's workspace
+type UsageRecord struct {
+	UserID      string    `json:"user_id"`
+	WorkspaceID string    `json:"workspace_id"`
+	Day         time.Time `json:"day"` // UTC midnight
+	ToolCalls   int64     `json:"tool_calls"`
+	APIBytes    int64     `json:"api_bytes"`
+}
+
+// UsageQuota limits daily usage per user across all workspaces (0 = unlimited)
+type UsageQuota struct {
+	DailyToolCalls int64 `json:"daily_tool_calls"`
+	DailyAPIBytes  int64 `json:"daily_api_bytes"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// AtlassianUserToken is the OAuth 2.0 (3LO) grant a user gave the server, used in
+// workspaces whose policy has UserOAuth so calls run with the user's own permissions
+type AtlassianUserToken struct {
+	UserID       string    `json:"user_id"`
+	AccessToken  string    `json:"-"` // Decrypted
+	RefreshToken string    `json:"-"` // Decrypted; Atlassian rotates it on every refresh
+	Scope        string    `json:"scope"`
+	ExpiresAt    time.Time `json:"expires_at"` // When the access token expires
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -1,31 +1,132 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // AtlassianCredential represents stored credentials for an Atlassian workspace
 type AtlassianCredential struct {
-	UserID        string    `json:"user_id"`        // Clerk user ID
-	WorkspaceID   string    `json:"workspace_id"`   // User-defined label (e.g., "eso", "providentia")
-	WorkspaceName string    `json:"workspace_name"` // Display name
-	AtlassianURL string    `json:"atlassian_url"`  // e.g., "https://providentia.atlassian.net"
-	Email         string    `json:"email"`          // Atlassian account email
-	APIToken      string    `json:"api_token"`     // Encrypted Atlassian API token
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	UserID        string           `json:"user_id"`                  // Clerk user ID
+	WorkspaceID   string           `json:"workspace_id"`             // User-defined label (e.g., "eso", "providentia")
+	WorkspaceName string           `json:"workspace_name"`           // Display name
+	AtlassianURL  string           `json:"atlassian_url"`            // e.g., "https://providentia.atlassian.net"
+	JiraURL       string           `json:"jira_url,omitempty"`       // Overrides AtlassianURL for Jira, e.g. a Data Center server
+	ConfluenceURL string           `json:"confluence_url,omitempty"` // Overrides AtlassianURL for Confluence, e.g. "https://wiki.example.com"
+	Email         string           `json:"email"`                    // Atlassian account email
+	APIToken      string           `json:"api_token"`                // Encrypted Atlassian API token
+	Policy        *WorkspacePolicy `json:"policy,omitempty"`         // Optional tool/data restrictions
+	OpsgenieKey   string           `json:"-"`                        // Optional Opsgenie API key; never listed
+	ProxyURL      string           `json:"-"`                        // Optional proxy for the workspace's Atlassian calls; never listed
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	DeletedAt     *time.Time       `json:"deleted_at,omitempty"` // Set while soft-deleted; purged after the retention period
 }
 
 // WorkspaceCredentials is used for API client creation
 type WorkspaceCredentials struct {
-	Site  string // e.g., "https://eso.atlassian.net/wiki" or "https://eso.atlassian.net"
-	Email string // e.g., "service@eso.com"
-	Token string // Decrypted API token
+	Site   string           // e.g., "https://eso.atlassian.net/wiki" or "https://eso.atlassian.net"
+	Email  string           // e.g., "service@eso.com"
+	Token  string           // Decrypted API token
+	Policy *WorkspacePolicy // Restrictions enforced by the jira/confluence services (nil = unrestricted)
+
+	OpsgenieKey string // Decrypted Opsgenie API key; empty uses Jira Service Management Operations
+
+	JiraURL       string // Jira base URL when it is not Site, e.g. "https://jira.example.com"
+	ConfluenceURL string // Confluence base URL when it is not Site + "/wiki", e.g. "https://wiki.example.com"
+	ProxyURL      string // Proxy for Atlassian calls, e.g. "http://proxy.internal:3128"; empty follows HTTPS_PROXY
+}
+
+// JiraSite returns the base URL of the workspace's Jira
+func (c *WorkspaceCredentials) JiraSite() string {
+	if c.JiraURL != "" {
+		return strings.TrimSuffix(c.JiraURL, "/")
+	}
+	return c.Site
+}
+
+// ConfluenceSite returns the base URL of the workspace's Confluence. Without an override
+// it is the site's /wiki path, as on Atlassian Cloud.
+func (c *WorkspaceCredentials) ConfluenceSite() string {
+	if c.ConfluenceURL != "" {
+		return strings.TrimSuffix(c.ConfluenceURL, "/")
+	}
+	if c.Site == "" || strings.HasSuffix(c.Site, "/wiki") {
+		return c.Site
+	}
+	return strings.TrimSuffix(c.Site, "/") + "/wiki"
+}
+
+// WorkspacePolicy restricts what tools and data a workspace exposes.
+// Empty lists mean "no restriction" for that dimension.
+type WorkspacePolicy struct {
+	AllowedTools        []string `json:"allowed_tools,omitempty"`         // e.g., ["jira_get_issue", "jira_list_issues"]
+	ReadOnly            bool     `json:"read_only,omitempty"`             // Reject mutating tools
+	JQLProjectAllowlist []string `json:"jql_project_allowlist,omitempty"` // Jira project keys
+	SpaceAllowlist      []string `json:"space_allowlist,omitempty"`       // Confluence space keys
+	UserOAuth           bool     `json:"user_oauth,omitempty"`            // Call Atlassian as each caller with their own OAuth token, not the workspace's
+}
+
+// UsesUserOAuth reports whether calls run with the caller's own Atlassian permissions
+func (p *WorkspacePolicy) UsesUserOAuth() bool {
+	return p != nil && p.UserOAuth
+}
+
+// AllowsTool reports whether the policy permits the given MCP tool name
+func (p *WorkspacePolicy) AllowsTool(toolName string) bool {
+	if p == nil || len(p.AllowedTools) == 0 {
+		return true
+	}
+	return containsFold(p.AllowedTools, toolName)
+}
+
+// AllowsProject reports whether the policy permits the given Jira project key
+func (p *WorkspacePolicy) AllowsProject(projectKey string) bool {
+	if p == nil || len(p.JQLProjectAllowlist) == 0 {
+		return true
+	}
+	return containsFold(p.JQLProjectAllowlist, projectKey)
+}
+
+// AllowsSpace reports whether the policy permits the given Confluence space key
+func (p *WorkspacePolicy) AllowsSpace(spaceKey string) bool {
+	if p == nil || len(p.SpaceAllowlist) == 0 {
+		return true
+	}
+	return containsFold(p.SpaceAllowlist, spaceKey)
+}
+
+// RestrictsProjects reports whether a Jira project allowlist is in effect
+func (p *WorkspacePolicy) RestrictsProjects() bool {
+	return p != nil && len(p.JQLProjectAllowlist) > 0
+}
+
+// RestrictsSpaces reports whether a Confluence space allowlist is in effect
+func (p *WorkspacePolicy) RestrictsSpaces() bool {
+	return p != nil && len(p.SpaceAllowlist) > 0
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
 }
 
 // ErrorInfo represents error information in responses
 type ErrorInfo struct {
-	Code    string `json:"code"`    // e.g., "AUTH_FAILED", "NOT_FOUND", "RATE_LIMITED"
-	Message string `json:"message"` // Human-readable message
-	Details any    `json:"details,omitempty"` // Additional context
+	Code        string   `json:"code"`                  // e.g., "AUTH_FAILED", "NOT_FOUND", "RATE_LIMITED"
+	Message     string   `json:"message"`               // Human-readable message
+	Details     any      `json:"details,omitempty"`     // Additional context
+	HTTPStatus  int      `json:"http_status,omitempty"` // Status of the failed Atlassian response, if any
+	Retriable   bool     `json:"retriable"`             // Whether the same call may succeed later
+	Suggestions []string `json:"suggestions,omitempty"` // How to correct the call, e.g. the valid values of a field
+
+	// CredentialsRejected is set when the workspace's site rejected the workspace's own
+	// credentials, as opposed to a 401 from another service or for a caller's OAuth token
+	CredentialsRejected bool `json:"credentials_rejected,omitempty"`
 }
 
 // Standard error codes
@@ -34,8 +135,18 @@ const (
 	ErrCodeNotFound       = "NOT_FOUND"
 	ErrCodeRateLimited    = "RATE_LIMITED"
 	ErrCodeInvalidRequest = "INVALID_REQUEST"
+	ErrCodeForbidden      = "FORBIDDEN"
+	ErrCodeQuotaExceeded  = "QUOTA_EXCEEDED"
 	ErrCodeAPIError       = "API_ERROR"
 	ErrCodeInternal       = "INTERNAL_ERROR"
+	ErrCodeTimeout        = "TIMEOUT"
+	ErrCodeConflict       = "CONFLICT"
+
+	// Uploads refused by the operator's upload policy
+	ErrCodeUploadTooLarge       = "UPLOAD_TOO_LARGE"
+	ErrCodeUploadTypeNotAllowed = "UPLOAD_TYPE_NOT_ALLOWED"
+	ErrCodeUploadInfected       = "UPLOAD_INFECTED"
+	ErrCodeUploadScanFailed     = "UPLOAD_SCAN_FAILED"
 )
 
 // ErrorResponse creates an error response
@@ -43,8 +154,9 @@ func ErrorResponse(code, message string, requestID string) map[string]interface{
 	return map[string]interface{}{
 		"success": false,
 		"error": &ErrorInfo{
-			Code:    code,
-			Message: message,
+			Code:      code,
+			Message:   message,
+			Retriable: code == ErrCodeRateLimited || code == ErrCodeTimeout,
 		},
 		"request_id": requestID,
 	}
@@ -58,4 +170,3 @@ func SuccessResponse(data interface{}, requestID string) map[string]interface{}
 		"request_id": requestID,
 	}
 }
-
@@ -0,0 +1,140 @@
+package notify
+
+import "strings"
+
+// Colors by level, for the Slack attachment bar
+var slackColors = map[string]string{
+	LevelInfo:    "#0052CC",
+	LevelSuccess: "#36B37E",
+	LevelWarning: "#FFAB00",
+	LevelError:   "#DE350B",
+}
+
+// Adaptive Card text colors by level
+var teamsColors = map[string]string{
+	LevelInfo:    "Accent",
+	LevelSuccess: "Good",
+	LevelWarning: "Warning",
+	LevelError:   "Attention",
+}
+
+// slackEscape escapes the characters Slack's mrkdwn treats as markup
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// slackPayload renders a message with Block Kit, inside an attachment colored by level.
+// The top-level text is the fallback shown in notifications.
+func slackPayload(msg Message) map[string]interface{} {
+	blocks := []interface{}{}
+	if msg.Title != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": truncate(msg.Title, 150)},
+		})
+	}
+	if msg.Text != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": truncate(slackEscape(msg.Text), 3000)},
+		})
+	}
+	// A section holds at most 10 fields
+	for start := 0; start < len(msg.Fields); start += 10 {
+		end := start + 10
+		if end > len(msg.Fields) {
+			end = len(msg.Fields)
+		}
+		fields := []interface{}{}
+		for _, f := range msg.Fields[start:end] {
+			fields = append(fields, map[string]interface{}{
+				"type": "mrkdwn",
+				"text": truncate("*"+slackEscape(f.Name)+"*\n"+slackEscape(f.Value), 2000),
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+	if msg.URL != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "context",
+			"elements": []interface{}{
+				map[string]interface{}{"type": "mrkdwn", "text": "<" + msg.URL + "|Open>"},
+			},
+		})
+	}
+
+	fallback := msg.Title
+	if fallback == "" {
+		fallback = msg.Text
+	} else if msg.Text != "" {
+		fallback += ": " + msg.Text
+	}
+	return map[string]interface{}{
+		"text": truncate(slackEscape(fallback), 3000),
+		"attachments": []interface{}{
+			map[string]interface{}{"color": levelValue(slackColors, msg.Level), "blocks": blocks},
+		},
+	}
+}
+
+// teamsPayload renders a message as an Adaptive Card, which both Teams workflow webhooks
+// and the older incoming webhook connectors accept
+func teamsPayload(msg Message) map[string]interface{} {
+	body := []interface{}{}
+	if msg.Title != "" {
+		body = append(body, map[string]interface{}{
+			"type":   "TextBlock",
+			"text":   msg.Title,
+			"size":   "Medium",
+			"weight": "Bolder",
+			"color":  levelValue(teamsColors, msg.Level),
+			"wrap":   true,
+		})
+	}
+	if msg.Text != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": msg.Text, "wrap": true})
+	}
+	if len(msg.Fields) > 0 {
+		facts := []interface{}{}
+		for _, f := range msg.Fields {
+			facts = append(facts, map[string]interface{}{"title": f.Name, "value": f.Value})
+		}
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if msg.URL != "" {
+		card["actions"] = []interface{}{
+			map[string]interface{}{"type": "Action.OpenUrl", "title": "Open", "url": msg.URL},
+		}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{
+			map[string]interface{}{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     card,
+			},
+		},
+	}
+}
+
+func levelValue(values map[string]string, level string) string {
+	if v, ok := values[level]; ok {
+		return v
+	}
+	return values[LevelInfo]
+}
+
+// truncate shortens s to at most max runes, the limit Slack puts on the field
+func truncate(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max-1]) + "…"
+	}
+	return s
+}
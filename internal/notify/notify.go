@@ -0,0 +1,158 @@
+// Package notify posts formatted messages to Slack and Microsoft Teams incoming webhooks,
+// and delivers server events (credential failures, schedule results, quota warnings) to
+// the channels users have configured
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// Server events a channel can subscribe to
+const (
+	EventCredentialFailure = "credential_failure" // Atlassian rejected a workspace's API token
+	EventScheduleResult    = "schedule_result"    // A scheduled pipeline finished
+	EventQuotaWarning      = "quota_warning"      // A daily usage quota is nearly or fully used
+)
+
+// Events lists every server event
+var Events = []string{EventCredentialFailure, EventScheduleResult, EventQuotaWarning}
+
+// Message levels, which set the message's color
+const (
+	LevelInfo    = "info"
+	LevelSuccess = "success"
+	LevelWarning = "warning"
+	LevelError   = "error"
+)
+
+// Message is a notification, rendered as a Slack message or a Teams card
+type Message struct {
+	Title  string
+	Text   string
+	Level  string  // info (default), success, warning or error
+	Fields []Field // Shown as a list of facts
+	URL    string  // Optional link, e.g. to the page a schedule published
+}
+
+// Field is a labelled value in a message
+type Field struct {
+	Name  string
+	Value string
+}
+
+// sendTimeout bounds a webhook delivery
+const sendTimeout = 10 * time.Second
+
+var httpClient = &http.Client{Timeout: sendTimeout}
+
+// Send posts a message to a channel's webhook
+func Send(ctx context.Context, channel models.NotificationChannel, msg Message) error {
+	var payload interface{}
+	switch channel.Kind {
+	case models.NotificationKindSlack:
+		payload = slackPayload(msg)
+	case models.NotificationKindTeams:
+		payload = teamsPayload(msg)
+	default:
+		return fmt.Errorf("unknown channel kind %q", channel.Kind)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s webhook returned %s", channel.Kind, resp.Status)
+	}
+	return nil
+}
+
+// ChannelLister returns a user's notification channels
+type ChannelLister interface {
+	ListNotificationChannels(userID string) ([]models.NotificationChannel, error)
+}
+
+// Notifier delivers server events to the channels that subscribe to them. A nil
+// Notifier, or one without channels (file-based storage), drops every event.
+type Notifier struct {
+	channels ChannelLister
+
+	mu   sync.Mutex
+	sent map[string]time.Time // Throttle keys and when they last sent
+}
+
+// NewNotifier creates a notifier over the users' configured channels
+func NewNotifier(channels ChannelLister) *Notifier {
+	return &Notifier{channels: channels, sent: make(map[string]time.Time)}
+}
+
+// Notify posts an event to each of the user's channels that subscribes to it. Delivery
+// happens in the background; failures are logged.
+func (n *Notifier) Notify(userID, event string, msg Message) {
+	if n == nil || n.channels == nil || userID == "" {
+		return
+	}
+	go n.deliver(userID, event, msg)
+}
+
+// NotifyOnce is Notify, except that events sharing a key are sent at most once per window
+func (n *Notifier) NotifyOnce(key string, window time.Duration, userID, event string, msg Message) {
+	if n == nil || n.channels == nil || userID == "" {
+		return
+	}
+
+	n.mu.Lock()
+	now := time.Now()
+	if last, ok := n.sent[key]; ok && now.Sub(last) < window {
+		n.mu.Unlock()
+		return
+	}
+	n.sent[key] = now
+	// Forget keys whose window has long passed so the map stays small
+	for k, t := range n.sent {
+		if now.Sub(t) > 24*time.Hour {
+			delete(n.sent, k)
+		}
+	}
+	n.mu.Unlock()
+
+	go n.deliver(userID, event, msg)
+}
+
+func (n *Notifier) deliver(userID, event string, msg Message) {
+	channels, err := n.channels.ListNotificationChannels(userID)
+	if err != nil {
+		slog.Warn("failed to list notification channels", "user_id", userID, "error", err)
+		return
+	}
+	for _, channel := range channels {
+		if !channel.Subscribes(event) {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		if err := Send(ctx, channel, msg); err != nil {
+			slog.Warn("failed to send notification", "user_id", userID, "channel_id", channel.ID,
+				"event", event, "error", err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,144 @@
+// Package pii flags text that likely contains personal data: email addresses, phone
+// numbers and credit card numbers. It is a heuristic warning layer, not a guarantee.
+package pii
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Mode is what the MCP server does with tool results that contain likely personal data
+type Mode string
+
+const (
+	ModeOff    Mode = "off"    // Results are not scanned
+	ModeWarn   Mode = "warn"   // A warning is appended to the result
+	ModeStrict Mode = "strict" // The result is withheld
+)
+
+// ParseMode reads a mode from config.yaml or the environment; empty means off
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return ModeOff, nil
+	case ModeOff, ModeWarn, ModeStrict:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown mode %q (want off, warn or strict)", s)
+	}
+}
+
+// Kind is a kind of personal data
+type Kind string
+
+const (
+	Email      Kind = "email address"
+	Phone      Kind = "phone number"
+	CreditCard Kind = "credit card number"
+)
+
+// plurals of each kind, for messages
+var plurals = map[Kind]string{
+	Email:      "email addresses",
+	Phone:      "phone numbers",
+	CreditCard: "credit card numbers",
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	// Grouped digits such as "+44 20 7946 0958" or "(555) 123-4567", or "+" and the digits
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?)?\d{2,4}(?:[ .-]\d{2,4}){1,4}\b|\+\d{10,15}\b`)
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+)
+
+// Findings counts the likely personal data found, by kind
+type Findings map[Kind]int
+
+// String lists the findings, e.g. "2 email addresses, 1 phone number"
+func (f Findings) String() string {
+	parts := make([]string, 0, len(f))
+	for kind, n := range f {
+		label := string(kind)
+		if n != 1 {
+			label = plurals[kind]
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", n, label))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// Kinds lists the kinds found, for logs
+func (f Findings) Kinds() []string {
+	kinds := make([]string, 0, len(f))
+	for kind := range f {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Scan counts the email addresses, phone numbers and credit card numbers in texts
+func Scan(texts ...string) Findings {
+	findings := make(Findings)
+	for _, text := range texts {
+		if n := len(emailPattern.FindAllStringIndex(text, -1)); n > 0 {
+			findings[Email] += n
+		}
+		// Card numbers are counted first so their digits are not taken for phone numbers
+		for _, match := range cardPattern.FindAllString(text, -1) {
+			if isCardNumber(match) {
+				findings[CreditCard]++
+				text = strings.Replace(text, match, "", 1)
+			}
+		}
+		for _, match := range phonePattern.FindAllString(text, -1) {
+			if isPhoneNumber(match) {
+				findings[Phone]++
+			}
+		}
+	}
+	return findings
+}
+
+// isPhoneNumber rules out dates, versions and IP addresses that look like grouped digits
+func isPhoneNumber(match string) bool {
+	digits := onlyDigits(match)
+	if len(digits) < 10 || len(digits) > 15 {
+		return false
+	}
+	return net.ParseIP(match) == nil
+}
+
+// isCardNumber checks the length, issuer prefix and Luhn checksum of a card number
+func isCardNumber(match string) bool {
+	digits := onlyDigits(match)
+	if len(digits) < 13 || len(digits) > 19 || !strings.ContainsAny(digits[:1], "3456") {
+		return false
+	}
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 0 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// onlyDigits drops everything from s but its digits
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
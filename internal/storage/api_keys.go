@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// APIKeyStoreInterface stores hashed API keys
+type APIKeyStoreInterface interface {
+	CreateAPIKey(key *models.APIKey, keyHash string) error
+	GetAPIKeyByHash(keyHash string) (*models.APIKey, error)
+	ListAPIKeys(userID string) ([]models.APIKey, error)
+	RevokeAPIKey(userID, keyID string) error
+	TouchAPIKey(keyID string) error
+}
+
+// APIKeyStore keeps API keys in PostgreSQL
+type APIKeyStore struct {
+	db *sql.DB
+}
+
+// NewAPIKeyStore creates an API key store on an existing database connection.
+// The api_keys table is created by the storage migrations.
+func NewAPIKeyStore(db *sql.DB) *APIKeyStore {
+	return &APIKeyStore{db: db}
+}
+
+// NewAPIKeyStoreFromEnv returns a database-backed key store, or nil with file-based
+// credential storage, where API keys are not supported
+func NewAPIKeyStoreFromEnv(credStore CredentialStoreInterface) APIKeyStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewAPIKeyStore(pg.db)
+	}
+	return nil
+}
+
+// CreateAPIKey stores a new key
+func (s *APIKeyStore) CreateAPIKey(key *models.APIKey, keyHash string) error {
+	query := `
+		INSERT INTO api_keys (id, user_id, name, key_hash, prefix, scopes, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	_, err := s.db.Exec(query,
+		key.ID,
+		key.UserID,
+		key.Name,
+		keyHash,
+		key.Prefix,
+		strings.Join(key.Scopes, " "),
+		key.CreatedAt,
+		key.ExpiresAt,
+	)
+	return err
+}
+
+// GetAPIKeyByHash looks up a key by the hash of its secret, including revoked and expired keys
+func (s *APIKeyStore) GetAPIKeyByHash(keyHash string) (*models.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	key, err := scanAPIKey(s.db.QueryRow(query, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return key, err
+}
+
+// ListAPIKeys returns a user's keys, newest first
+func (s *APIKeyStore) ListAPIKeys(userID string) ([]models.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey revokes one of the user's keys
+func (s *APIKeyStore) RevokeAPIKey(userID, keyID string) error {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := s.db.Exec(query, keyID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// TouchAPIKey records that a key was just used
+func (s *APIKeyStore) TouchAPIKey(keyID string) error {
+	_, err := s.db.Exec(`UPDATE api_keys SET last_used_at = NOW() WHERE id = $1`, keyID)
+	return err
+}
+
+// scanAPIKey reads one api_keys row
+func scanAPIKey(row interface{ Scan(...interface{}) error }) (*models.APIKey, error) {
+	var key models.APIKey
+	var scopes string
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&scopes,
+		&key.CreatedAt,
+		&expiresAt,
+		&lastUsedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = strings.Fields(scopes)
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return &key, nil
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"os"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/cache"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/metrics"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// DefaultCredentialCacheTTL is used when CREDENTIAL_CACHE_TTL is not set
+const DefaultCredentialCacheTTL = 30 * time.Second
+
+// CachedCredentialStore is a write-through cache in front of another credential store.
+// GetCredentials is served from memory for a short TTL so that tool calls don't hit
+// the database on every request; writes go straight to the underlying store and
+// invalidate the cached entry.
+type CachedCredentialStore struct {
+	inner    CredentialStoreInterface
+	cache    *cache.SimpleCache
+	ttl      time.Duration
+	onChange func(eventType, userID, workspaceID string)
+}
+
+// NewCachedCredentialStore wraps a credential store with a short-TTL cache
+func NewCachedCredentialStore(inner CredentialStoreInterface, ttl time.Duration) *CachedCredentialStore {
+	return &CachedCredentialStore{
+		inner: inner,
+		cache: cache.NewSimpleCache(),
+		ttl:   ttl,
+	}
+}
+
+// CredentialCacheTTLFromEnv reads CREDENTIAL_CACHE_TTL (e.g., "30s"); "0" disables caching
+func CredentialCacheTTLFromEnv() time.Duration {
+	if v := os.Getenv("CREDENTIAL_CACHE_TTL"); v != "" {
+		if v == "0" {
+			return 0
+		}
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return DefaultCredentialCacheTTL
+}
+
+// OnChange registers a callback invoked after credentials are saved or deleted.
+// The MCP server uses it to broadcast invalidation events to the services.
+func (s *CachedCredentialStore) OnChange(fn func(eventType, userID, workspaceID string)) {
+	s.onChange = fn
+}
+
+// Invalidate drops the cached credentials for a user/workspace
+func (s *CachedCredentialStore) Invalidate(userID, workspaceID string) {
+	s.cache.Delete(credentialCacheKey(userID, workspaceID))
+}
+
+// CacheStats reports the credential cache's size and hit rate
+func (s *CachedCredentialStore) CacheStats() cache.Stats {
+	return s.cache.Stats()
+}
+
+// CacheTTL returns how long credentials are cached (0 = caching disabled)
+func (s *CachedCredentialStore) CacheTTL() time.Duration {
+	return s.ttl
+}
+
+// GetCredentials returns cached credentials when available, otherwise reads through
+func (s *CachedCredentialStore) GetCredentials(userID, workspaceID string) (*models.WorkspaceCredentials, error) {
+	key := credentialCacheKey(userID, workspaceID)
+	if s.ttl > 0 {
+		if cached, found := s.cache.Get(key); found {
+			if creds, ok := cached.(*models.WorkspaceCredentials); ok {
+				metrics.CredentialCacheLookups.Inc("hit")
+				return creds, nil
+			}
+		}
+		metrics.CredentialCacheLookups.Inc("miss")
+	}
+
+	start := time.Now()
+	creds, err := s.inner.GetCredentials(userID, workspaceID)
+	metrics.CredentialStoreDuration.ObserveSince(start, "get")
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ttl > 0 {
+		s.cache.Set(key, creds, s.ttl)
+	}
+	return creds, nil
+}
+
+// SaveCredentials writes through to the underlying store and invalidates the cache
+func (s *CachedCredentialStore) SaveCredentials(cred *models.AtlassianCredential) error {
+	start := time.Now()
+	err := s.inner.SaveCredentials(cred)
+	metrics.CredentialStoreDuration.ObserveSince(start, "save")
+	if err != nil {
+		return err
+	}
+	s.Invalidate(cred.UserID, cred.WorkspaceID)
+	s.notify("updated", cred.UserID, cred.WorkspaceID)
+	return nil
+}
+
+// DeleteCredentials deletes from the underlying store and invalidates the cache
+func (s *CachedCredentialStore) DeleteCredentials(userID, workspaceID string) error {
+	start := time.Now()
+	err := s.inner.DeleteCredentials(userID, workspaceID)
+	metrics.CredentialStoreDuration.ObserveSince(start, "delete")
+	if err != nil {
+		return err
+	}
+	s.Invalidate(userID, workspaceID)
+	s.notify("deleted", userID, workspaceID)
+	return nil
+}
+
+// RestoreCredentials restores a soft-deleted workspace and announces it like an update
+func (s *CachedCredentialStore) RestoreCredentials(userID, workspaceID string) error {
+	if err := s.inner.RestoreCredentials(userID, workspaceID); err != nil {
+		return err
+	}
+	s.Invalidate(userID, workspaceID)
+	s.notify("updated", userID, workspaceID)
+	return nil
+}
+
+// PurgeDeletedCredentials passes through; purged entries were already evicted on delete
+func (s *CachedCredentialStore) PurgeDeletedCredentials(deletedBefore time.Time) (int, error) {
+	return s.inner.PurgeDeletedCredentials(deletedBefore)
+}
+
+// ListWorkspaces is not cached; callers cache listings themselves
+func (s *CachedCredentialStore) ListWorkspaces(userID string) ([]models.AtlassianCredential, error) {
+	defer metrics.CredentialStoreDuration.ObserveSince(time.Now(), "list")
+	return s.inner.ListWorkspaces(userID)
+}
+
+// ListDeletedWorkspaces is not cached
+func (s *CachedCredentialStore) ListDeletedWorkspaces(userID string) ([]models.AtlassianCredential, error) {
+	return s.inner.ListDeletedWorkspaces(userID)
+}
+
+// Ping checks the underlying store
+func (s *CachedCredentialStore) Ping() error {
+	return s.inner.Ping()
+}
+
+// Close closes the underlying store
+func (s *CachedCredentialStore) Close() error {
+	return s.inner.Close()
+}
+
+func (s *CachedCredentialStore) notify(eventType, userID, workspaceID string) {
+	if s.onChange != nil {
+		s.onChange(eventType, userID, workspaceID)
+	}
+}
+
+func credentialCacheKey(userID, workspaceID string) string {
+	return "creds:" + userID + ":" + workspaceID
+}
@@ -0,0 +1,354 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// DefaultExportTTL is how long exports can be downloaded when EXPORT_TTL is not set
+const DefaultExportTTL = 24 * time.Hour
+
+// exportSnapshotDir is the subdirectory of the export directory that keeps snapshots
+const exportSnapshotDir = "snapshots"
+
+// ExportStoreInterface keeps export files and their metadata
+type ExportStoreInterface interface {
+	// WriteExport stores an export whose contents write produces. Nothing is stored
+	// when write fails.
+	WriteExport(export *models.Export, write func(w io.Writer) error) error
+	// GetExport returns an export, or ErrNotFound once it has expired
+	GetExport(id string) (*models.Export, error)
+	// OpenExport opens an export's file
+	OpenExport(id string) (*os.File, error)
+	// ListExports returns a user's unexpired exports, newest first
+	ListExports(userID string) ([]models.Export, error)
+	// DeleteExport deletes one of the user's exports
+	DeleteExport(userID, id string) error
+	// PurgeExpiredExports deletes exports that expired before now
+	PurgeExpiredExports(now time.Time) (int, error)
+	// GetExportSnapshot returns the snapshot with a key (see models.ExportSnapshot.Key),
+	// or ErrNotFound
+	GetExportSnapshot(key string) (*models.ExportSnapshot, error)
+	// SaveExportSnapshot creates or replaces a snapshot
+	SaveExportSnapshot(snapshot *models.ExportSnapshot) error
+}
+
+// ExportStore keeps exports in a directory: each export is a data file and a JSON
+// metadata file named after its ID. Snapshots, which do not expire, are JSON files named
+// after their key in its snapshots subdirectory. Replicas serving downloads must share
+// the directory.
+type ExportStore struct {
+	dir string
+}
+
+// exportRecord is an export's metadata file, which unlike the API keeps the token hash
+type exportRecord struct {
+	models.Export
+	TokenHash string `json:"token_hash"`
+}
+
+// NewExportStore creates an export store in dir, creating the directory if needed
+func NewExportStore(dir string) (*ExportStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, exportSnapshotDir), 0700); err != nil {
+		return nil, err
+	}
+	return &ExportStore{dir: dir}, nil
+}
+
+// NewExportStoreFromEnv creates an export store in EXPORTS_DIR, defaulting to a
+// trilix-exports directory under the system temp directory
+func NewExportStoreFromEnv() (ExportStoreInterface, error) {
+	dir := os.Getenv("EXPORTS_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "trilix-exports")
+	}
+	store, err := NewExportStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// ExportTTLFromEnv reads EXPORT_TTL (a Go duration, e.g. "168h"), defaulting to
+// DefaultExportTTL
+func ExportTTLFromEnv() time.Duration {
+	if v := os.Getenv("EXPORT_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultExportTTL
+}
+
+// paths returns an export's data and metadata file paths. IDs are UUIDs, which keeps
+// them from naming files outside the directory.
+func (s *ExportStore) paths(id string) (string, string, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return "", "", ErrNotFound
+	}
+	return filepath.Join(s.dir, id+".data"), filepath.Join(s.dir, id+".json"), nil
+}
+
+// WriteExport writes the data file, then the metadata file, each through a temporary
+// file, so a partly written export is never visible
+func (s *ExportStore) WriteExport(export *models.Export, write func(w io.Writer) error) error {
+	dataPath, metaPath, err := s.paths(export.ID)
+	if err != nil {
+		return errors.New("export ID must be a UUID")
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "."+export.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once the rename has succeeded
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, dataPath); err != nil {
+		return err
+	}
+	export.SizeBytes = info.Size()
+
+	meta, err := json.MarshalIndent(exportRecord{Export: *export, TokenHash: export.TokenHash}, "", "  ")
+	if err != nil {
+		os.Remove(dataPath)
+		return err
+	}
+	metaTmp := metaPath + ".tmp"
+	if err := os.WriteFile(metaTmp, meta, 0600); err != nil {
+		os.Remove(dataPath)
+		return err
+	}
+	if err := os.Rename(metaTmp, metaPath); err != nil {
+		os.Remove(metaTmp)
+		os.Remove(dataPath)
+		return err
+	}
+	return nil
+}
+
+// readExport reads an export's metadata file, expired or not
+func (s *ExportStore) readExport(metaPath string) (*models.Export, error) {
+	data, err := os.ReadFile(metaPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record exportRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	export := record.Export
+	export.TokenHash = record.TokenHash
+	return &export, nil
+}
+
+// GetExport returns an unexpired export
+func (s *ExportStore) GetExport(id string) (*models.Export, error) {
+	_, metaPath, err := s.paths(id)
+	if err != nil {
+		return nil, err
+	}
+	export, err := s.readExport(metaPath)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(export.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	return export, nil
+}
+
+// OpenExport opens an export's data file
+func (s *ExportStore) OpenExport(id string) (*os.File, error) {
+	dataPath, _, err := s.paths(id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(dataPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// all returns the metadata of every export in the directory
+func (s *ExportStore) all() ([]models.Export, error) {
+	metaPaths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	exports := make([]models.Export, 0, len(metaPaths))
+	for _, metaPath := range metaPaths {
+		export, err := s.readExport(metaPath)
+		if errors.Is(err, ErrNotFound) {
+			continue // Deleted while listing
+		}
+		if err != nil {
+			slog.Warn("skipping unreadable export metadata", "file", metaPath, "error", err)
+			continue
+		}
+		exports = append(exports, *export)
+	}
+	return exports, nil
+}
+
+// ListExports returns a user's unexpired exports, newest first
+func (s *ExportStore) ListExports(userID string) ([]models.Export, error) {
+	all, err := s.all()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	exports := []models.Export{}
+	for _, export := range all {
+		if export.UserID == userID && now.Before(export.ExpiresAt) {
+			exports = append(exports, export)
+		}
+	}
+	sort.Slice(exports, func(i, j int) bool { return exports[i].CreatedAt.After(exports[j].CreatedAt) })
+	return exports, nil
+}
+
+// DeleteExport deletes one of the user's exports
+func (s *ExportStore) DeleteExport(userID, id string) error {
+	export, err := s.GetExport(id)
+	if err != nil {
+		return err
+	}
+	if export.UserID != userID {
+		return ErrNotFound
+	}
+	return s.remove(id)
+}
+
+// remove deletes an export's files, metadata first so it disappears before its data
+func (s *ExportStore) remove(id string) error {
+	dataPath, metaPath, err := s.paths(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(metaPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(dataPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// PurgeExpiredExports deletes expired exports, and the temporary and data files an
+// interrupted export left behind
+func (s *ExportStore) PurgeExpiredExports(now time.Time) (int, error) {
+	all, err := s.all()
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, export := range all {
+		if now.Before(export.ExpiresAt) {
+			continue
+		}
+		if err := s.remove(export.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return purged, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		orphaned := strings.HasSuffix(name, ".tmp")
+		if id, ok := strings.CutSuffix(name, ".data"); ok {
+			_, err := os.Stat(filepath.Join(s.dir, id+".json"))
+			orphaned = errors.Is(err, os.ErrNotExist)
+		}
+		if !orphaned {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && now.Sub(info.ModTime()) > DefaultExportTTL {
+			os.Remove(filepath.Join(s.dir, name))
+		}
+	}
+	return purged, nil
+}
+
+// snapshotPath returns a snapshot's file path. Keys are hex SHA-256 hashes, which keeps
+// them from naming files outside the directory.
+func (s *ExportStore) snapshotPath(key string) (string, error) {
+	if _, err := hex.DecodeString(key); err != nil || len(key) != sha256.Size*2 {
+		return "", ErrNotFound
+	}
+	return filepath.Join(s.dir, exportSnapshotDir, key+".json"), nil
+}
+
+// GetExportSnapshot reads a snapshot's file
+func (s *ExportStore) GetExportSnapshot(key string) (*models.ExportSnapshot, error) {
+	path, err := s.snapshotPath(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot models.ExportSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// SaveExportSnapshot writes a snapshot's file through a temporary file, so a partly
+// written snapshot is never read
+func (s *ExportStore) SaveExportSnapshot(snapshot *models.ExportSnapshot) error {
+	path, err := s.snapshotPath(snapshot.Key())
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// FieldPresetStoreInterface stores users' Jira field presets
+type FieldPresetStoreInterface interface {
+	SaveFieldPreset(preset *models.FieldPreset) error
+	GetFieldPreset(userID, name string) (*models.FieldPreset, error)
+	ListFieldPresets(userID string) ([]models.FieldPreset, error)
+	DeleteFieldPreset(userID, name string) error
+}
+
+// FieldPresetStore keeps field presets in PostgreSQL
+type FieldPresetStore struct {
+	db *sql.DB
+}
+
+// NewFieldPresetStore creates a field preset store on an existing database connection.
+// The field_presets table is created by the storage migrations.
+func NewFieldPresetStore(db *sql.DB) *FieldPresetStore {
+	return &FieldPresetStore{db: db}
+}
+
+// NewFieldPresetStoreFromEnv returns a database-backed field preset store, or nil with
+// file-based credential storage, where only the server's presets are available
+func NewFieldPresetStoreFromEnv(credStore CredentialStoreInterface) FieldPresetStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewFieldPresetStore(pg.db)
+	}
+	return nil
+}
+
+// SaveFieldPreset creates or replaces one of the user's presets
+func (s *FieldPresetStore) SaveFieldPreset(preset *models.FieldPreset) error {
+	query := `
+		INSERT INTO field_presets (user_id, name, fields, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, name) DO UPDATE SET fields = EXCLUDED.fields, updated_at = EXCLUDED.updated_at
+	`
+
+	preset.UpdatedAt = time.Now().UTC()
+	fields, err := json.Marshal(preset.Fields)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(query, preset.UserID, preset.Name, string(fields), preset.UpdatedAt)
+	return err
+}
+
+// GetFieldPreset returns one of the user's presets
+func (s *FieldPresetStore) GetFieldPreset(userID, name string) (*models.FieldPreset, error) {
+	query := `SELECT user_id, name, fields, updated_at FROM field_presets WHERE user_id = $1 AND name = $2`
+
+	preset, err := scanFieldPreset(s.db.QueryRow(query, userID, name))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return preset, err
+}
+
+// ListFieldPresets returns a user's presets by name
+func (s *FieldPresetStore) ListFieldPresets(userID string) ([]models.FieldPreset, error) {
+	query := `SELECT user_id, name, fields, updated_at FROM field_presets WHERE user_id = $1 ORDER BY name`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	presets := []models.FieldPreset{}
+	for rows.Next() {
+		preset, err := scanFieldPreset(rows)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, *preset)
+	}
+	return presets, rows.Err()
+}
+
+// DeleteFieldPreset deletes one of the user's presets
+func (s *FieldPresetStore) DeleteFieldPreset(userID, name string) error {
+	result, err := s.db.Exec(`DELETE FROM field_presets WHERE user_id = $1 AND name = $2`, userID, name)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// scanFieldPreset reads one field_presets row
+func scanFieldPreset(row interface{ Scan(...interface{}) error }) (*models.FieldPreset, error) {
+	var preset models.FieldPreset
+	var fields []byte
+	if err := row.Scan(&preset.UserID, &preset.Name, &fields, &preset.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(fields, &preset.Fields); err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
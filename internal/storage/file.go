@@ -2,13 +2,17 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
 )
 
@@ -18,33 +22,60 @@ type CredentialStoreInterface interface {
 	SaveCredentials(cred *models.AtlassianCredential) error
 	DeleteCredentials(userID, workspaceID string) error
 	ListWorkspaces(userID string) ([]models.AtlassianCredential, error)
+	ListDeletedWorkspaces(userID string) ([]models.AtlassianCredential, error)
+	RestoreCredentials(userID, workspaceID string) error
+	PurgeDeletedCredentials(deletedBefore time.Time) (int, error)
 	Ping() error
 	Close() error
 }
 
 // WorkspaceConfig represents the structure of workspaces.json
 type WorkspaceConfig struct {
-	ID       string `json:"id,omitempty"` // Added for UUID support
-	Name     string `json:"name"`
-	BaseURL  string `json:"baseUrl"`
-	Email    string `json:"email"`
-	APIToken string `json:"apiToken"`
+	ID                      string                  `json:"id,omitempty"`    // Added for UUID support
+	Owner                   string                  `json:"owner,omitempty"` // Clerk user ID; empty means shared with all users
+	Name                    string                  `json:"name"`
+	BaseURL                 string                  `json:"baseUrl"`
+	JiraURL                 string                  `json:"jiraUrl,omitempty"`       // Overrides baseUrl for Jira
+	ConfluenceURL           string                  `json:"confluenceUrl,omitempty"` // Overrides baseUrl + "/wiki" for Confluence
+	Email                   string                  `json:"email"`
+	APIToken                string                  `json:"apiToken,omitempty"`
+	APITokenEncrypted       string                  `json:"apiTokenEncrypted,omitempty"` // Only used by workspace import/export
+	Policy                  *models.WorkspacePolicy `json:"policy,omitempty"`
+	OpsgenieAPIKey          string                  `json:"opsgenieApiKey,omitempty"`
+	OpsgenieAPIKeyEncrypted string                  `json:"opsgenieApiKeyEncrypted,omitempty"` // Only used by workspace import/export
+	ProxyURL                string                  `json:"proxyUrl,omitempty"`                // Proxy for this workspace's Atlassian calls
+	ProxyURLEncrypted       string                  `json:"proxyUrlEncrypted,omitempty"`       // Only used by workspace import/export
+	DeletedAt               *time.Time              `json:"deletedAt,omitempty"`               // Soft-deleted entries are hidden until restored or purged
 }
 
+// ErrSharedWorkspace means a user other than an admin tried to change a shared workspace
+var ErrSharedWorkspace = errors.New("shared workspaces can only be changed by an administrator")
+
 // FileCredentialStore handles storage and retrieval of Atlassian credentials from a JSON file
-// Supports multiple workspaces simultaneously
+// Supports multiple workspaces simultaneously. Entries with an owner are only visible to
+// that user; entries without one are shared. In shared mode owners are ignored entirely.
+// The file may be shared by several processes (mcp-server, jira-service, confluence-service),
+// so writes are serialized with a lock file and changes made elsewhere are picked up via fsnotify.
 type FileCredentialStore struct {
 	filePath    string
-	workspaces  map[string]WorkspaceConfig // Indexed by workspace ID (or name if ID missing)
-	lastModTime time.Time                  // Track file modification time
+	workspaces  map[string]WorkspaceConfig // Indexed by fileKey(owner, workspace ID or name)
+	shared      bool                       // Legacy mode: every user sees every workspace
+	lastModTime time.Time                  // Track file modification time (used when fsnotify is unavailable)
+	watcher     *fsnotify.Watcher          // Reloads the file when another process changes it
 	mu          sync.RWMutex               // Thread safety
 }
 
 // NewFileCredentialStore creates a new file-based credential store
-func NewFileCredentialStore(filePath string) (*FileCredentialStore, error) {
+func NewFileCredentialStore(filePath string, shared bool) (*FileCredentialStore, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+
 	store := &FileCredentialStore{
-		filePath:   filePath,
+		filePath:   absPath,
 		workspaces: make(map[string]WorkspaceConfig),
+		shared:     shared,
 	}
 
 	// Load workspaces from file
@@ -52,25 +83,31 @@ func NewFileCredentialStore(filePath string) (*FileCredentialStore, error) {
 		return nil, fmt.Errorf("failed to load workspaces: %w", err)
 	}
 
+	// Watch for changes made by other processes; fall back to stat polling if that fails
+	if err := store.watch(); err != nil {
+		slog.Warn("could not watch workspaces file, falling back to polling", "path", absPath, "error", err)
+	}
+
 	return store, nil
 }
 
-// loadWorkspaces reads and parses the workspaces.json file
+// loadWorkspaces reads the workspaces.json file while holding a shared lock
 func (s *FileCredentialStore) loadWorkspaces() error {
-	// Resolve absolute path
-	absPath, err := filepath.Abs(s.filePath)
-	if err != nil {
-		return err
-	}
+	return s.withFileLock(false, s.readFile)
+}
 
+// readFile reads and parses the workspaces.json file. Callers must hold the file lock.
+func (s *FileCredentialStore) readFile() error {
 	// Check if file exists
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+	if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
+		s.mu.Lock()
 		s.workspaces = make(map[string]WorkspaceConfig)
+		s.mu.Unlock()
 		return nil
 	}
 
 	// Read file
-	data, err := os.ReadFile(absPath)
+	data, err := os.ReadFile(s.filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read workspaces file: %w", err)
 	}
@@ -87,25 +124,26 @@ func (s *FileCredentialStore) loadWorkspaces() error {
 	defer s.mu.Unlock()
 
 	s.workspaces = make(map[string]WorkspaceConfig)
-	// Index by ID if present, else Name
+	// Index by owner and ID if present, else Name
 	for _, ws := range workspaces {
 		id := ws.ID
 		if id == "" {
 			id = ws.Name
 		}
-		s.workspaces[id] = ws
+		s.workspaces[fileKey(ws.Owner, id)] = ws
 	}
 
 	// Update last modification time
-	if stat, err := os.Stat(absPath); err == nil {
+	if stat, err := os.Stat(s.filePath); err == nil {
 		s.lastModTime = stat.ModTime()
 	}
 
 	return nil
 }
 
-// saveToFile writes the current workspaces to the JSON file
-func (s *FileCredentialStore) saveToFile() error {
+// writeFile atomically replaces the JSON file with the current workspaces by writing
+// to a temp file in the same directory and renaming it. Callers must hold the file lock.
+func (s *FileCredentialStore) writeFile() error {
 	s.mu.RLock()
 	var list []WorkspaceConfig
 	for _, ws := range s.workspaces {
@@ -123,25 +161,127 @@ func (s *FileCredentialStore) saveToFile() error {
 		return err
 	}
 
-	absPath, err := filepath.Abs(s.filePath)
+	dir := filepath.Dir(s.filePath)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.filePath)+".*.tmp")
 	if err != nil {
 		return err
 	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once the rename has succeeded
 
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	// Flush to disk before the rename so a crash never leaves a truncated file behind
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, s.filePath)
+}
+
+// errNoChange lets an update callback skip rewriting the file
+var errNoChange = errors.New("no change")
+
+// update runs a read-modify-write cycle under an exclusive file lock. The file is
+// re-read first so changes made by other processes are not overwritten.
+func (s *FileCredentialStore) update(modify func() error) error {
+	return s.withFileLock(true, func() error {
+		if err := s.readFile(); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		err := modify()
+		s.mu.Unlock()
+		if err == errNoChange {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.writeFile()
+	})
+}
+
+// withFileLock runs fn while holding a lock on the companion .lock file. The lock is
+// taken on a separate file because writeFile replaces the data file via rename.
+func (s *FileCredentialStore) withFileLock(exclusive bool, fn func() error) error {
 	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return err
+	}
+
+	lock, err := os.OpenFile(s.filePath+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
+	}
+	defer lock.Close()
+
+	if err := lockFile(lock, exclusive); err != nil {
+		return fmt.Errorf("failed to lock workspaces file: %w", err)
+	}
+	defer unlockFile(lock)
+
+	return fn()
+}
+
+// watch reloads the workspaces whenever the file is written or replaced. The directory
+// is watched rather than the file itself because atomic writes swap out the inode.
+func (s *FileCredentialStore) watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(s.filePath)); err != nil {
+		watcher.Close()
 		return err
 	}
+	s.watcher = watcher
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != s.filePath {
+					continue
+				}
+				if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) || event.Has(fsnotify.Remove) {
+					if err := s.loadWorkspaces(); err != nil {
+						slog.Warn("failed to reload workspaces file", "path", s.filePath, "error", err)
+					}
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Warn("workspaces file watcher error", "error", err)
+			}
+		}
+	}()
 
-	return os.WriteFile(absPath, data, 0644)
+	return nil
 }
 
 // GetCredentials retrieves credentials for a user/workspace
 func (s *FileCredentialStore) GetCredentials(userID, workspaceID string) (*models.WorkspaceCredentials, error) {
 	s.checkAndReload()
-	
+
 	s.mu.RLock()
-	ws, exists := s.workspaces[workspaceID]
+	_, ws, exists := s.lookup(userID, workspaceID, false)
 	s.mu.RUnlock()
 
 	if !exists {
@@ -149,95 +289,241 @@ func (s *FileCredentialStore) GetCredentials(userID, workspaceID string) (*model
 	}
 
 	return &models.WorkspaceCredentials{
-		Site:  ws.BaseURL,
-		Email: ws.Email,
-		Token: ws.APIToken,
+		Site:        ws.BaseURL,
+		Email:       ws.Email,
+		Token:       ws.APIToken,
+		Policy:      ws.Policy,
+		OpsgenieKey: ws.OpsgenieAPIKey,
+
+		JiraURL:       ws.JiraURL,
+		ConfluenceURL: ws.ConfluenceURL,
+		ProxyURL:      ws.ProxyURL,
 	}, nil
 }
 
 // SaveCredentials saves credentials to the file
 func (s *FileCredentialStore) SaveCredentials(cred *models.AtlassianCredential) error {
-	s.checkAndReload()
+	return s.saveCredentials(cred, false)
+}
 
-	s.mu.Lock()
-	// Use generated ID as key
-	id := cred.WorkspaceID
-	if id == "" {
-		id = cred.WorkspaceName // Fallback, though WorkspaceID should be set by handler
-	}
+// saveCredentials saves credentials, as an administrator who may change shared entries
+// when asAdmin is set
+func (s *FileCredentialStore) saveCredentials(cred *models.AtlassianCredential, asAdmin bool) error {
+	return s.update(func() error {
+		// Use generated ID as key
+		id := cred.WorkspaceID
+		if id == "" {
+			id = cred.WorkspaceName // Fallback, though WorkspaceID should be set by handler
+		}
 
-	s.workspaces[id] = WorkspaceConfig{
-		ID:       id,
-		Name:     cred.WorkspaceName,
-		BaseURL:  cred.AtlassianURL,
-		Email:    cred.Email,
-		APIToken: cred.APIToken,
-	}
-	s.mu.Unlock()
+		// Updates keep the existing entry's ownership; new entries belong to the caller. A
+		// shared entry the caller may not change is shadowed by a new one of their own.
+		key, existing, exists := s.writable(cred.UserID, id, false, asAdmin)
+		owner := existing.Owner
+		if !exists {
+			owner = cred.UserID
+			if s.shared {
+				owner = ""
+			}
+			key = fileKey(owner, id)
+			if _, taken := s.workspaces[key]; taken && owner == "" && !s.shared {
+				return ErrSharedWorkspace
+			}
+		}
 
-	return s.saveToFile()
+		s.workspaces[key] = WorkspaceConfig{
+			ID:             id,
+			Owner:          owner,
+			Name:           cred.WorkspaceName,
+			BaseURL:        cred.AtlassianURL,
+			JiraURL:        cred.JiraURL,
+			ConfluenceURL:  cred.ConfluenceURL,
+			Email:          cred.Email,
+			APIToken:       cred.APIToken,
+			Policy:         cred.Policy,
+			OpsgenieAPIKey: cred.OpsgenieKey,
+			ProxyURL:       cred.ProxyURL,
+		}
+		return nil
+	})
 }
 
-// DeleteCredentials removes credentials from the file
+// DeleteCredentials soft-deletes credentials in the file; they can be restored until purged
 func (s *FileCredentialStore) DeleteCredentials(userID, workspaceID string) error {
-	s.checkAndReload()
+	return s.deleteCredentials(userID, workspaceID, false)
+}
 
-	s.mu.Lock()
-	if _, exists := s.workspaces[workspaceID]; !exists {
-		s.mu.Unlock()
-		return ErrNotFound
-	}
-	delete(s.workspaces, workspaceID)
-	s.mu.Unlock()
+func (s *FileCredentialStore) deleteCredentials(userID, workspaceID string, asAdmin bool) error {
+	return s.update(func() error {
+		key, ws, exists := s.writable(userID, workspaceID, false, asAdmin)
+		if !exists {
+			return ErrNotFound
+		}
+		now := time.Now()
+		ws.DeletedAt = &now
+		s.workspaces[key] = ws
+		return nil
+	})
+}
 
-	return s.saveToFile()
+// RestoreCredentials undoes a soft delete
+func (s *FileCredentialStore) RestoreCredentials(userID, workspaceID string) error {
+	return s.restoreCredentials(userID, workspaceID, false)
+}
+
+func (s *FileCredentialStore) restoreCredentials(userID, workspaceID string, asAdmin bool) error {
+	return s.update(func() error {
+		key, ws, exists := s.writable(userID, workspaceID, true, asAdmin)
+		if !exists {
+			return ErrNotFound
+		}
+		ws.DeletedAt = nil
+		s.workspaces[key] = ws
+		return nil
+	})
+}
+
+// PurgeDeletedCredentials permanently removes entries soft-deleted before the given time
+func (s *FileCredentialStore) PurgeDeletedCredentials(deletedBefore time.Time) (int, error) {
+	purged := 0
+	err := s.update(func() error {
+		for key, ws := range s.workspaces {
+			if ws.DeletedAt != nil && ws.DeletedAt.Before(deletedBefore) {
+				delete(s.workspaces, key)
+				purged++
+			}
+		}
+		if purged == 0 {
+			return errNoChange
+		}
+		return nil
+	})
+	return purged, err
 }
 
 // ListWorkspaces returns all workspaces from the file
 func (s *FileCredentialStore) ListWorkspaces(userID string) ([]models.AtlassianCredential, error) {
+	return s.listWorkspaces(userID, false)
+}
+
+// ListDeletedWorkspaces returns soft-deleted workspaces that have not been purged yet
+func (s *FileCredentialStore) ListDeletedWorkspaces(userID string) ([]models.AtlassianCredential, error) {
+	return s.listWorkspaces(userID, true)
+}
+
+func (s *FileCredentialStore) listWorkspaces(userID string, deleted bool) ([]models.AtlassianCredential, error) {
 	s.checkAndReload()
-	
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	var credentials []models.AtlassianCredential
-	for id, ws := range s.workspaces {
+	for _, ws := range s.workspaces {
+		if !s.visibleTo(ws, userID) || (ws.DeletedAt != nil) != deleted {
+			continue
+		}
+		id := ws.ID
+		if id == "" {
+			id = ws.Name
+		}
 		credentials = append(credentials, models.AtlassianCredential{
 			UserID:        userID,
 			WorkspaceID:   id, // This is either UUID or Name
 			WorkspaceName: ws.Name,
 			AtlassianURL:  ws.BaseURL,
+			JiraURL:       ws.JiraURL,
+			ConfluenceURL: ws.ConfluenceURL,
 			Email:         ws.Email,
 			APIToken:      ws.APIToken,
+			Policy:        ws.Policy,
 			CreatedAt:     time.Now(),
 			UpdatedAt:     time.Now(),
+			DeletedAt:     ws.DeletedAt,
 		})
 	}
 	return credentials, nil
 }
 
+// lookup finds the entry a user may access for a workspace ID, preferring one they own.
+// deleted selects between live and soft-deleted entries. Callers must hold s.mu.
+func (s *FileCredentialStore) lookup(userID, workspaceID string, deleted bool) (string, WorkspaceConfig, bool) {
+	if s.shared {
+		for key, ws := range s.workspaces {
+			if (ws.ID == workspaceID || (ws.ID == "" && ws.Name == workspaceID)) && (ws.DeletedAt != nil) == deleted {
+				return key, ws, true
+			}
+		}
+		return "", WorkspaceConfig{}, false
+	}
+
+	if userID != "" {
+		key := fileKey(userID, workspaceID)
+		if ws, ok := s.workspaces[key]; ok && (ws.DeletedAt != nil) == deleted {
+			return key, ws, true
+		}
+	}
+	key := fileKey("", workspaceID)
+	if ws, ok := s.workspaces[key]; ok && (ws.DeletedAt != nil) == deleted {
+		return key, ws, true
+	}
+	return "", WorkspaceConfig{}, false
+}
+
+// writable finds the entry a user may change for a workspace ID. Outside shared mode that
+// is one they own; shared entries can only be changed asAdmin, as repointing one would send
+// every user's calls elsewhere. Callers must hold s.mu.
+func (s *FileCredentialStore) writable(userID, workspaceID string, deleted, asAdmin bool) (string, WorkspaceConfig, bool) {
+	if s.shared || asAdmin {
+		return s.lookup(userID, workspaceID, deleted)
+	}
+	if userID == "" {
+		return "", WorkspaceConfig{}, false
+	}
+	key := fileKey(userID, workspaceID)
+	if ws, ok := s.workspaces[key]; ok && (ws.DeletedAt != nil) == deleted {
+		return key, ws, true
+	}
+	return "", WorkspaceConfig{}, false
+}
+
+// visibleTo reports whether a user may see a workspace entry
+func (s *FileCredentialStore) visibleTo(ws WorkspaceConfig, userID string) bool {
+	return s.shared || ws.Owner == "" || ws.Owner == userID
+}
+
+// fileKey builds the in-memory index key for an entry
+func fileKey(owner, workspaceID string) string {
+	if owner == "" {
+		return workspaceID
+	}
+	return owner + "/" + workspaceID
+}
+
 // Ping is a no-op for file-based storage
 func (s *FileCredentialStore) Ping() error {
 	return nil
 }
 
-// Close is a no-op for file-based storage
+// Close stops watching the workspaces file
 func (s *FileCredentialStore) Close() error {
+	if s.watcher != nil {
+		return s.watcher.Close()
+	}
 	return nil
 }
 
-// checkAndReload checks if the file has been modified and reloads if necessary
+// checkAndReload checks if the file has been modified and reloads if necessary.
+// It is only needed when the fsnotify watcher could not be started.
 func (s *FileCredentialStore) checkAndReload() {
-	absPath, err := filepath.Abs(s.filePath)
-	if err != nil {
+	if s.watcher != nil {
 		return
 	}
 
-	stat, err := os.Stat(absPath)
+	stat, err := os.Stat(s.filePath)
 	if err != nil {
 		return
 	}
-	
+
 	s.mu.RLock()
 	lastMod := s.lastModTime
 	s.mu.RUnlock()
@@ -247,14 +533,86 @@ func (s *FileCredentialStore) checkAndReload() {
 	}
 }
 
+// ForeignWorkspace reports whether a workspace ID is taken by an entry the user does not
+// own, a shared one or another user's. Imports must not reuse such IDs, which would
+// replace that entry's credentials or hide it from the user. Stores that key entries by
+// user, and the file store in shared mode, have none.
+func ForeignWorkspace(store CredentialStoreInterface, userID, workspaceID string) bool {
+	switch s := store.(type) {
+	case *CachedCredentialStore:
+		return ForeignWorkspace(s.inner, userID, workspaceID)
+	case *FileCredentialStore:
+		if s.shared {
+			return false
+		}
+		s.checkAndReload()
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		for _, ws := range s.workspaces {
+			if (ws.ID == workspaceID || (ws.ID == "" && ws.Name == workspaceID)) && ws.Owner != userID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// adminFileStore is a FileCredentialStore whose writes may change shared entries
+type adminFileStore struct {
+	*FileCredentialStore
+}
+
+func (s adminFileStore) SaveCredentials(cred *models.AtlassianCredential) error {
+	return s.saveCredentials(cred, true)
+}
+
+func (s adminFileStore) DeleteCredentials(userID, workspaceID string) error {
+	return s.deleteCredentials(userID, workspaceID, true)
+}
+
+func (s adminFileStore) RestoreCredentials(userID, workspaceID string) error {
+	return s.restoreCredentials(userID, workspaceID, true)
+}
+
+// AsAdmin returns the store to write through for a caller the HTTP layer has verified to be
+// an administrator. With the file store, such writes may also change shared workspaces;
+// other stores key entries by user and are returned as they are.
+func AsAdmin(store CredentialStoreInterface) CredentialStoreInterface {
+	switch s := store.(type) {
+	case *CachedCredentialStore:
+		admin := *s
+		admin.inner = AsAdmin(s.inner)
+		return &admin
+	case *FileCredentialStore:
+		return adminFileStore{s}
+	}
+	return store
+}
+
+// Backend names the kind of credential store, "postgres" or "file", for health reports
+func Backend(store CredentialStoreInterface) string {
+	switch s := store.(type) {
+	case *CachedCredentialStore:
+		return Backend(s.inner)
+	case *CredentialStore:
+		return "postgres"
+	case *FileCredentialStore:
+		return "file"
+	default:
+		return "unknown"
+	}
+}
+
 // NewCredentialStoreFromEnv creates a credential store based on environment variables
-// If WORKSPACES_FILE is set, uses file-based storage
+// If WORKSPACES_FILE is set, uses file-based storage (WORKSPACES_FILE_SHARED=true restores
+// the legacy behaviour where every user sees every workspace)
 // Otherwise, uses PostgreSQL storage (requires DATABASE_URL and API_KEY_ENCRYPTION_KEY)
 func NewCredentialStoreFromEnv() (CredentialStoreInterface, error) {
 	workspacesFile := os.Getenv("WORKSPACES_FILE")
 	if workspacesFile != "" {
 		// Use file-based storage
-		return NewFileCredentialStore(workspacesFile)
+		shared := strings.EqualFold(os.Getenv("WORKSPACES_FILE_SHARED"), "true")
+		return NewFileCredentialStore(workspacesFile, shared)
 	}
 
 	// Use PostgreSQL storage
@@ -270,4 +628,3 @@ func NewCredentialStoreFromEnv() (CredentialStoreInterface, error) {
 
 	return NewCredentialStore(databaseURL, encryptionKey)
 }
-
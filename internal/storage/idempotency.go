@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// IdempotencyKeyParam is the tool argument carrying a client-chosen idempotency key
+const IdempotencyKeyParam = "idempotency_key"
+
+// DefaultIdempotencyTTL is how long results are kept when IDEMPOTENCY_TTL is not set
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// maxIdempotencyKeyLength matches the idempotency_keys.idempotency_key column
+const maxIdempotencyKeyLength = 255
+
+var (
+	// ErrIdempotencyInProgress means another request with the same key is still running
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still in progress")
+	// ErrIdempotencyMismatch means the key was already used for a different request
+	ErrIdempotencyMismatch = errors.New("idempotency key was already used with different parameters")
+)
+
+// IdempotencyStoreInterface remembers the results of mutating requests by idempotency key
+type IdempotencyStoreInterface interface {
+	// Begin reserves a key for a request. It returns the stored response when the key
+	// has already completed, ErrIdempotencyInProgress while it is reserved, and
+	// ErrIdempotencyMismatch when the key belongs to a different request.
+	Begin(userID, key, fingerprint string, ttl time.Duration) ([]byte, error)
+	// Complete stores the response for a reserved key
+	Complete(userID, key string, response []byte) error
+	// Abort releases a reserved key so the request can be retried
+	Abort(userID, key string) error
+	// PurgeExpired deletes keys past their TTL
+	PurgeExpired() (int64, error)
+}
+
+// IdempotencyStore keeps idempotency keys in PostgreSQL
+type IdempotencyStore struct {
+	db *sql.DB
+}
+
+// NewIdempotencyStore creates an idempotency store on an existing database connection.
+// The idempotency_keys table is created by the storage migrations.
+func NewIdempotencyStore(db *sql.DB) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+// Begin reserves a key, or reports what an earlier request with the key did
+func (s *IdempotencyStore) Begin(userID, key, fingerprint string, ttl time.Duration) ([]byte, error) {
+	// Expired keys can be reused straight away
+	if _, err := s.db.Exec(`DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2 AND expires_at < NOW()`, userID, key); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, fingerprint, expires_at)
+		VALUES ($1, $2, $3, NOW() + $4 * INTERVAL '1 second')
+		ON CONFLICT (user_id, idempotency_key) DO NOTHING
+	`
+	result, err := s.db.Exec(query, userID, key, fingerprint, int64(ttl.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	if inserted, _ := result.RowsAffected(); inserted == 1 {
+		return nil, nil
+	}
+
+	var storedFingerprint string
+	var response []byte
+	err = s.db.QueryRow(`SELECT fingerprint, response FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2`,
+		userID, key).Scan(&storedFingerprint, &response)
+	if err != nil {
+		return nil, err
+	}
+	return checkIdempotencyEntry(storedFingerprint, fingerprint, response)
+}
+
+// Complete stores the response for a reserved key
+func (s *IdempotencyStore) Complete(userID, key string, response []byte) error {
+	_, err := s.db.Exec(`UPDATE idempotency_keys SET response = $3 WHERE user_id = $1 AND idempotency_key = $2`, userID, key, response)
+	return err
+}
+
+// Abort releases a reserved key
+func (s *IdempotencyStore) Abort(userID, key string) error {
+	_, err := s.db.Exec(`DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2 AND response IS NULL`, userID, key)
+	return err
+}
+
+// PurgeExpired deletes keys past their TTL
+func (s *IdempotencyStore) PurgeExpired() (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// MemoryIdempotencyStore keeps idempotency keys in memory; used with file-based
+// credential storage. Keys are only shared by requests handled by the same process.
+type MemoryIdempotencyStore struct {
+	entries map[string]*idempotencyEntry // Indexed by user/key
+	mu      sync.Mutex
+}
+
+type idempotencyEntry struct {
+	fingerprint string
+	response    []byte
+	expiresAt   time.Time
+}
+
+// NewMemoryIdempotencyStore creates an in-memory idempotency store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]*idempotencyEntry)}
+}
+
+// Begin reserves a key, or reports what an earlier request with the key did
+func (s *MemoryIdempotencyStore) Begin(userID, key, fingerprint string, ttl time.Duration) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[userID+"/"+key]; ok && time.Now().Before(entry.expiresAt) {
+		return checkIdempotencyEntry(entry.fingerprint, fingerprint, entry.response)
+	}
+	s.entries[userID+"/"+key] = &idempotencyEntry{fingerprint: fingerprint, expiresAt: time.Now().Add(ttl)}
+	return nil, nil
+}
+
+// Complete stores the response for a reserved key
+func (s *MemoryIdempotencyStore) Complete(userID, key string, response []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[userID+"/"+key]; ok {
+		entry.response = response
+	}
+	return nil
+}
+
+// Abort releases a reserved key
+func (s *MemoryIdempotencyStore) Abort(userID, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[userID+"/"+key]; ok && entry.response == nil {
+		delete(s.entries, userID+"/"+key)
+	}
+	return nil
+}
+
+// PurgeExpired deletes keys past their TTL
+func (s *MemoryIdempotencyStore) PurgeExpired() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var purged int64
+	for k, entry := range s.entries {
+		if time.Now().After(entry.expiresAt) {
+			delete(s.entries, k)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// checkIdempotencyEntry compares an existing entry with a new request
+func checkIdempotencyEntry(storedFingerprint, fingerprint string, response []byte) ([]byte, error) {
+	if storedFingerprint != fingerprint {
+		return nil, ErrIdempotencyMismatch
+	}
+	if response == nil {
+		return nil, ErrIdempotencyInProgress
+	}
+	return response, nil
+}
+
+// NewIdempotencyStoreFromEnv keeps idempotency keys next to the credentials: in
+// PostgreSQL when the credential store is database-backed, otherwise in memory
+func NewIdempotencyStoreFromEnv(credStore CredentialStoreInterface) IdempotencyStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewIdempotencyStore(pg.db)
+	}
+	return NewMemoryIdempotencyStore()
+}
+
+// IdempotencyTTLFromEnv reads IDEMPOTENCY_TTL (a Go duration such as "24h")
+func IdempotencyTTLFromEnv() time.Duration {
+	if v := os.Getenv("IDEMPOTENCY_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultIdempotencyTTL
+}
+
+// IdempotencyKey returns the idempotency key among a request's params, if any
+func IdempotencyKey(params map[string]any) string {
+	key, _ := params[IdempotencyKeyParam].(string)
+	return strings.TrimSpace(key)
+}
+
+// RunIdempotent runs a mutating request at most once per user and idempotency key.
+// A replay with the same key and parameters returns the first successful response;
+// failed responses are not remembered, so the request can be retried.
+func RunIdempotent(store IdempotencyStoreInterface, ttl time.Duration, userID, key, action, workspaceID string,
+	params map[string]any, requestID string, run func() map[string]interface{}) map[string]interface{} {
+	if len(key) > maxIdempotencyKeyLength {
+		return models.ErrorResponse(models.ErrCodeInvalidRequest,
+			fmt.Sprintf("idempotency_key must be at most %d characters", maxIdempotencyKeyLength), requestID)
+	}
+
+	stored, err := store.Begin(userID, key, requestFingerprint(action, workspaceID, params), ttl)
+	switch {
+	case errors.Is(err, ErrIdempotencyInProgress), errors.Is(err, ErrIdempotencyMismatch):
+		return models.ErrorResponse(models.ErrCodeConflict, err.Error(), requestID)
+	case err != nil:
+		return models.ErrorResponse(models.ErrCodeInternal, fmt.Sprintf("idempotency check failed: %v", err), requestID)
+	case stored != nil:
+		var response map[string]interface{}
+		if err := json.Unmarshal(stored, &response); err == nil {
+			slog.Info("replayed idempotent request", "action", action, "workspace_id", workspaceID, "request_id", requestID)
+			response["request_id"] = requestID
+			return response
+		}
+	}
+
+	response := run()
+	if succeeded, _ := response["success"].(bool); !succeeded {
+		if err := store.Abort(userID, key); err != nil {
+			slog.Warn("failed to release idempotency key", "request_id", requestID, "error", err)
+		}
+		return response
+	}
+	responseBytes, _ := json.Marshal(response)
+	if err := store.Complete(userID, key, responseBytes); err != nil {
+		slog.Warn("failed to store idempotent response", "request_id", requestID, "error", err)
+	}
+	return response
+}
+
+// requestFingerprint identifies a request's action, workspace and parameters (other
+// than the idempotency key itself)
+func requestFingerprint(action, workspaceID string, params map[string]any) string {
+	rest := make(map[string]any, len(params))
+	for k, v := range params {
+		if k != IdempotencyKeyParam {
+			rest[k] = v
+		}
+	}
+	paramsJSON, _ := json.Marshal(rest)
+	sum := sha256.Sum256([]byte(action + "\x00" + workspaceID + "\x00" + string(paramsJSON)))
+	return hex.EncodeToString(sum[:])
+}
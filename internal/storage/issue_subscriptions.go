@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// IssueSubscriptionStoreInterface stores subscriptions to Jira issue changes
+type IssueSubscriptionStoreInterface interface {
+	CreateIssueSubscription(subscription *models.IssueSubscription) error
+	ListIssueSubscriptions(userID string) ([]models.IssueSubscription, error)
+	DeleteIssueSubscription(userID, subscriptionID string) error
+
+	// DueIssueSubscriptions returns the subscriptions whose next check is at or before now
+	DueIssueSubscriptions(now time.Time) ([]models.IssueSubscription, error)
+	// ClaimIssueSubscriptionCheck moves a due subscription's next check from due to next.
+	// It reports false when another replica claimed the check first.
+	ClaimIssueSubscriptionCheck(subscriptionID string, due, next time.Time) (bool, error)
+	// FinishIssueSubscriptionCheck records the outcome of a check. checkedThrough only
+	// moves forward when the changes were delivered.
+	FinishIssueSubscriptionCheck(subscriptionID string, checkedThrough time.Time, lastError string) error
+}
+
+// IssueSubscriptionStore keeps issue subscriptions in PostgreSQL
+type IssueSubscriptionStore struct {
+	db *sql.DB
+}
+
+// NewIssueSubscriptionStore creates an issue subscription store on an existing database
+// connection. The issue_subscriptions table is created by the storage migrations.
+func NewIssueSubscriptionStore(db *sql.DB) *IssueSubscriptionStore {
+	return &IssueSubscriptionStore{db: db}
+}
+
+// NewIssueSubscriptionStoreFromEnv returns a database-backed issue subscription store, or
+// nil with file-based credential storage, where subscriptions are not supported
+func NewIssueSubscriptionStoreFromEnv(credStore CredentialStoreInterface) IssueSubscriptionStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewIssueSubscriptionStore(pg.db)
+	}
+	return nil
+}
+
+const issueSubscriptionColumns = `id, user_id, org_id, workspace_id, jql, channel_id, webhook_url, checked_through, next_check_at, last_error, created_at`
+
+// CreateIssueSubscription stores a new subscription
+func (s *IssueSubscriptionStore) CreateIssueSubscription(subscription *models.IssueSubscription) error {
+	query := `
+		INSERT INTO issue_subscriptions (` + issueSubscriptionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	if subscription.CreatedAt.IsZero() {
+		subscription.CreatedAt = time.Now().UTC()
+	}
+	_, err := s.db.Exec(query,
+		subscription.ID,
+		subscription.UserID,
+		subscription.OrgID,
+		subscription.WorkspaceID,
+		subscription.JQL,
+		subscription.ChannelID,
+		subscription.WebhookURL,
+		subscription.CheckedThrough.UTC(),
+		subscription.NextCheckAt.UTC(),
+		subscription.LastError,
+		subscription.CreatedAt.UTC(),
+	)
+	return err
+}
+
+// ListIssueSubscriptions returns the user's subscriptions, oldest first
+func (s *IssueSubscriptionStore) ListIssueSubscriptions(userID string) ([]models.IssueSubscription, error) {
+	query := `SELECT ` + issueSubscriptionColumns + ` FROM issue_subscriptions WHERE user_id = $1 ORDER BY created_at`
+	return s.querySubscriptions(query, userID)
+}
+
+// DeleteIssueSubscription deletes one of the user's subscriptions
+func (s *IssueSubscriptionStore) DeleteIssueSubscription(userID, subscriptionID string) error {
+	result, err := s.db.Exec(`DELETE FROM issue_subscriptions WHERE id = $1 AND user_id = $2`, subscriptionID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// DueIssueSubscriptions returns the subscriptions whose next check is at or before now
+func (s *IssueSubscriptionStore) DueIssueSubscriptions(now time.Time) ([]models.IssueSubscription, error) {
+	query := `SELECT ` + issueSubscriptionColumns + ` FROM issue_subscriptions WHERE next_check_at <= $1 ORDER BY next_check_at`
+	return s.querySubscriptions(query, now.UTC())
+}
+
+// ClaimIssueSubscriptionCheck moves a due subscription's next check from due to next,
+// unless another replica already has
+func (s *IssueSubscriptionStore) ClaimIssueSubscriptionCheck(subscriptionID string, due, next time.Time) (bool, error) {
+	query := `UPDATE issue_subscriptions SET next_check_at = $3 WHERE id = $1 AND next_check_at = $2`
+	result, err := s.db.Exec(query, subscriptionID, due.UTC(), next.UTC())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// FinishIssueSubscriptionCheck records the outcome of a check
+func (s *IssueSubscriptionStore) FinishIssueSubscriptionCheck(subscriptionID string, checkedThrough time.Time, lastError string) error {
+	query := `
+		UPDATE issue_subscriptions
+		SET checked_through = GREATEST(checked_through, $2), last_error = $3
+		WHERE id = $1
+	`
+	_, err := s.db.Exec(query, subscriptionID, checkedThrough.UTC(), lastError)
+	return err
+}
+
+func (s *IssueSubscriptionStore) querySubscriptions(query string, args ...interface{}) ([]models.IssueSubscription, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []models.IssueSubscription{}
+	for rows.Next() {
+		var subscription models.IssueSubscription
+		err := rows.Scan(
+			&subscription.ID,
+			&subscription.UserID,
+			&subscription.OrgID,
+			&subscription.WorkspaceID,
+			&subscription.JQL,
+			&subscription.ChannelID,
+			&subscription.WebhookURL,
+			&subscription.CheckedThrough,
+			&subscription.NextCheckAt,
+			&subscription.LastError,
+			&subscription.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// IssueTemplateStoreInterface stores Jira issue templates. Templates are owned by a user,
+// or by an organization when shared with its members.
+type IssueTemplateStoreInterface interface {
+	CreateIssueTemplate(template *models.IssueTemplate) error
+	GetIssueTemplate(ownerID, templateID string) (*models.IssueTemplate, error)
+	ListIssueTemplates(ownerID string) ([]models.IssueTemplate, error)
+	UpdateIssueTemplate(template *models.IssueTemplate) error
+	DeleteIssueTemplate(ownerID, templateID string) error
+}
+
+// IssueTemplateStore keeps issue templates in PostgreSQL
+type IssueTemplateStore struct {
+	db *sql.DB
+}
+
+// NewIssueTemplateStore creates an issue template store on an existing database
+// connection. The issue_templates table is created by the storage migrations.
+func NewIssueTemplateStore(db *sql.DB) *IssueTemplateStore {
+	return &IssueTemplateStore{db: db}
+}
+
+// NewIssueTemplateStoreFromEnv returns a database-backed template store, or nil with
+// file-based credential storage, where issue templates are not supported
+func NewIssueTemplateStoreFromEnv(credStore CredentialStoreInterface) IssueTemplateStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewIssueTemplateStore(pg.db)
+	}
+	return nil
+}
+
+const issueTemplateColumns = `id, owner_id, name, description, project_key, issue_type, summary, body, fields, variables, created_by, created_at, updated_at`
+
+// CreateIssueTemplate stores a new template
+func (s *IssueTemplateStore) CreateIssueTemplate(template *models.IssueTemplate) error {
+	query := `
+		INSERT INTO issue_templates (` + issueTemplateColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	now := time.Now().UTC()
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = now
+	}
+	template.UpdatedAt = now
+
+	fields, variables, err := encodeIssueTemplate(template)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(query,
+		template.ID,
+		template.OwnerID,
+		template.Name,
+		template.Description,
+		template.ProjectKey,
+		template.IssueType,
+		template.Summary,
+		template.Body,
+		fields,
+		variables,
+		template.CreatedBy,
+		template.CreatedAt,
+		template.UpdatedAt,
+	)
+	return err
+}
+
+// GetIssueTemplate returns one of the owner's templates
+func (s *IssueTemplateStore) GetIssueTemplate(ownerID, templateID string) (*models.IssueTemplate, error) {
+	query := `SELECT ` + issueTemplateColumns + ` FROM issue_templates WHERE id = $1 AND owner_id = $2`
+
+	template, err := scanIssueTemplate(s.db.QueryRow(query, templateID, ownerID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return template, err
+}
+
+// ListIssueTemplates returns the owner's templates by name
+func (s *IssueTemplateStore) ListIssueTemplates(ownerID string) ([]models.IssueTemplate, error) {
+	query := `SELECT ` + issueTemplateColumns + ` FROM issue_templates WHERE owner_id = $1 ORDER BY name`
+
+	rows, err := s.db.Query(query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.IssueTemplate{}
+	for rows.Next() {
+		template, err := scanIssueTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+	return templates, rows.Err()
+}
+
+// UpdateIssueTemplate replaces one of the owner's templates
+func (s *IssueTemplateStore) UpdateIssueTemplate(template *models.IssueTemplate) error {
+	query := `
+		UPDATE issue_templates
+		SET name = $3, description = $4, project_key = $5, issue_type = $6, summary = $7, body = $8,
+			fields = $9, variables = $10, updated_at = $11
+		WHERE id = $1 AND owner_id = $2
+	`
+
+	template.UpdatedAt = time.Now().UTC()
+	fields, variables, err := encodeIssueTemplate(template)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.Exec(query,
+		template.ID,
+		template.OwnerID,
+		template.Name,
+		template.Description,
+		template.ProjectKey,
+		template.IssueType,
+		template.Summary,
+		template.Body,
+		fields,
+		variables,
+		template.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// DeleteIssueTemplate deletes one of the owner's templates
+func (s *IssueTemplateStore) DeleteIssueTemplate(ownerID, templateID string) error {
+	result, err := s.db.Exec(`DELETE FROM issue_templates WHERE id = $1 AND owner_id = $2`, templateID, ownerID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// encodeIssueTemplate serializes a template's fields and variables for their JSONB columns
+func encodeIssueTemplate(template *models.IssueTemplate) (string, string, error) {
+	fields := template.Fields
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	encodedFields, err := json.Marshal(fields)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode template fields: %v", err)
+	}
+	variables := template.Variables
+	if variables == nil {
+		variables = []models.IssueTemplateVariable{}
+	}
+	encodedVariables, err := json.Marshal(variables)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode template variables: %v", err)
+	}
+	return string(encodedFields), string(encodedVariables), nil
+}
+
+// scanIssueTemplate reads one issue_templates row
+func scanIssueTemplate(row interface{ Scan(...interface{}) error }) (*models.IssueTemplate, error) {
+	var template models.IssueTemplate
+	var fields, variables []byte
+	err := row.Scan(
+		&template.ID,
+		&template.OwnerID,
+		&template.Name,
+		&template.Description,
+		&template.ProjectKey,
+		&template.IssueType,
+		&template.Summary,
+		&template.Body,
+		&fields,
+		&variables,
+		&template.CreatedBy,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(fields, &template.Fields); err != nil {
+		return nil, fmt.Errorf("failed to decode template fields: %v", err)
+	}
+	if err := json.Unmarshal(variables, &template.Variables); err != nil {
+		return nil, fmt.Errorf("failed to decode template variables: %v", err)
+	}
+	return &template, nil
+}
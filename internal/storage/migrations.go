@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"database/sql"
+	"embed"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/migrate"
+)
+
+// migrationsComponent identifies this package's migrations in schema_migrations
+const migrationsComponent = "storage"
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrate applies any pending storage migrations
+func Migrate(db *sql.DB) error {
+	migrations, err := migrate.Load(migrationFiles, "migrations")
+	if err != nil {
+		return err
+	}
+	return migrate.Up(db, migrationsComponent, migrations)
+}
+
+// RollbackMigrations reverts the most recent storage migrations
+func RollbackMigrations(db *sql.DB, steps int) error {
+	migrations, err := migrate.Load(migrationFiles, "migrations")
+	if err != nil {
+		return err
+	}
+	return migrate.Down(db, migrationsComponent, migrations, steps)
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS atlassian_credentials;
@@ -0,0 +1,14 @@
+-- Baseline schema; IF NOT EXISTS keeps this safe on databases created by the old initSchema
+CREATE TABLE IF NOT EXISTS atlassian_credentials (
+	user_id VARCHAR(255) NOT NULL,
+	workspace_id VARCHAR(255) NOT NULL,
+	workspace_name VARCHAR(255) NOT NULL,
+	atlassian_url VARCHAR(500) NOT NULL,
+	email VARCHAR(255) NOT NULL,
+	api_token_encrypted TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (user_id, workspace_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_id ON atlassian_credentials(user_id);
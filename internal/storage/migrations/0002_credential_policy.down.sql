@@ -0,0 +1 @@
+ALTER TABLE atlassian_credentials DROP COLUMN IF EXISTS policy;
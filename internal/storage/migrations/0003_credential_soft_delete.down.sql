@@ -0,0 +1,3 @@
+-- Soft-deleted rows would become visible again, so drop them first
+DELETE FROM atlassian_credentials WHERE deleted_at IS NOT NULL;
+ALTER TABLE atlassian_credentials DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS usage_daily (
+	user_id VARCHAR(255) NOT NULL,
+	workspace_id VARCHAR(255) NOT NULL,
+	day DATE NOT NULL,
+	tool_calls BIGINT NOT NULL DEFAULT 0,
+	api_bytes BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY (user_id, workspace_id, day)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS service_tokens (
+	id VARCHAR(64) PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	token_hash CHAR(64) NOT NULL UNIQUE,
+	prefix VARCHAR(16) NOT NULL,
+	allowed_users TEXT NOT NULL DEFAULT '',
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	last_used_at TIMESTAMP,
+	revoked_at TIMESTAMP
+);
@@ -0,0 +1 @@
+ALTER TABLE atlassian_credentials DROP COLUMN IF EXISTS opsgenie_api_key_encrypted;
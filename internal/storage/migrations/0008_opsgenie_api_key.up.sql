@@ -0,0 +1 @@
+ALTER TABLE atlassian_credentials ADD COLUMN IF NOT EXISTS opsgenie_api_key_encrypted TEXT;
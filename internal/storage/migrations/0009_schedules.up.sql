@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS schedules (
+	id VARCHAR(64) PRIMARY KEY,
+	user_id VARCHAR(255) NOT NULL,
+	org_id VARCHAR(255) NOT NULL DEFAULT '',
+	name VARCHAR(255) NOT NULL,
+	cron VARCHAR(255) NOT NULL,
+	timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+	steps JSONB NOT NULL,
+	output JSONB,
+	enabled BOOLEAN NOT NULL DEFAULT TRUE,
+	next_run_at TIMESTAMP,
+	last_run_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedules_user_id ON schedules(user_id);
+CREATE INDEX IF NOT EXISTS idx_schedules_next_run_at ON schedules(next_run_at) WHERE enabled;
+
+CREATE TABLE IF NOT EXISTS schedule_runs (
+	id VARCHAR(64) PRIMARY KEY,
+	schedule_id VARCHAR(64) NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+	triggered_by VARCHAR(16) NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	steps JSONB NOT NULL DEFAULT '[]',
+	started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_runs_schedule_id ON schedule_runs(schedule_id, started_at DESC);
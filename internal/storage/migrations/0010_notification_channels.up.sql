@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS notification_channels (
+	id VARCHAR(64) PRIMARY KEY,
+	user_id VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	kind VARCHAR(16) NOT NULL,
+	webhook_url_encrypted TEXT NOT NULL,
+	events TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (user_id, name)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS issue_templates (
+	id VARCHAR(64) PRIMARY KEY,
+	owner_id VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	project_key VARCHAR(255) NOT NULL DEFAULT '',
+	issue_type VARCHAR(255) NOT NULL,
+	summary TEXT NOT NULL,
+	body TEXT NOT NULL DEFAULT '',
+	fields JSONB NOT NULL DEFAULT '{}',
+	variables JSONB NOT NULL DEFAULT '[]',
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (owner_id, name)
+);
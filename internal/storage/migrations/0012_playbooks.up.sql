@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS playbooks (
+	id VARCHAR(64) PRIMARY KEY,
+	user_id VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	variables JSONB NOT NULL DEFAULT '[]',
+	steps JSONB NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (user_id, name)
+);
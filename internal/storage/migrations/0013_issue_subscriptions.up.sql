@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS issue_subscriptions (
+	id VARCHAR(64) PRIMARY KEY,
+	user_id VARCHAR(255) NOT NULL,
+	org_id VARCHAR(255) NOT NULL DEFAULT '',
+	workspace_id VARCHAR(255) NOT NULL,
+	jql TEXT NOT NULL,
+	channel_id VARCHAR(64) NOT NULL DEFAULT '',
+	webhook_url TEXT NOT NULL DEFAULT '',
+	checked_through TIMESTAMP NOT NULL,
+	next_check_at TIMESTAMP NOT NULL,
+	last_error TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_subscriptions_user_id ON issue_subscriptions(user_id);
+CREATE INDEX IF NOT EXISTS idx_issue_subscriptions_next_check_at ON issue_subscriptions(next_check_at);
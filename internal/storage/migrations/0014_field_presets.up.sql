@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS field_presets (
+	user_id VARCHAR(255) NOT NULL,
+	name VARCHAR(64) NOT NULL,
+	fields JSONB NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (user_id, name)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE atlassian_credentials DROP COLUMN IF EXISTS confluence_url;
+ALTER TABLE atlassian_credentials DROP COLUMN IF EXISTS jira_url;
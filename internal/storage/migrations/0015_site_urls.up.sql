@@ -0,0 +1,2 @@
+ALTER TABLE atlassian_credentials ADD COLUMN IF NOT EXISTS jira_url TEXT;
+ALTER TABLE atlassian_credentials ADD COLUMN IF NOT EXISTS confluence_url TEXT;
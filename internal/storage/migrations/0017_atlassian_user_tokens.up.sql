@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS atlassian_user_tokens (
+	user_id VARCHAR(255) PRIMARY KEY,
+	access_token_encrypted TEXT NOT NULL,
+	refresh_token_encrypted TEXT NOT NULL,
+	scope TEXT NOT NULL DEFAULT '',
+	expires_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
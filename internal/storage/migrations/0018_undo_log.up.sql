@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS undo_log (
+	id BIGSERIAL PRIMARY KEY,
+	user_id VARCHAR(255) NOT NULL,
+	org_id VARCHAR(255) NOT NULL DEFAULT '',
+	product VARCHAR(32) NOT NULL,
+	workspace_id VARCHAR(255) NOT NULL,
+	tool VARCHAR(255) NOT NULL,
+	undo JSONB NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_undo_log_user_id ON undo_log (user_id, id);
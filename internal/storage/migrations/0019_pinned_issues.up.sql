@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS pinned_issues (
+	user_id VARCHAR(255) NOT NULL,
+	workspace_id VARCHAR(255) NOT NULL,
+	issue_key VARCHAR(255) NOT NULL,
+	org_id VARCHAR(255) NOT NULL DEFAULT '',
+	pinned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (user_id, workspace_id, issue_key)
+);
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/crypto"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// NotificationChannelStoreInterface stores users' Slack and Teams webhooks
+type NotificationChannelStoreInterface interface {
+	CreateNotificationChannel(channel *models.NotificationChannel) error
+	ListNotificationChannels(userID string) ([]models.NotificationChannel, error)
+	DeleteNotificationChannel(userID, channelID string) error
+}
+
+// NotificationChannelStore keeps notification channels in PostgreSQL, with the webhook
+// URLs encrypted like API tokens
+type NotificationChannelStore struct {
+	db            *sql.DB
+	encryptionKey string
+}
+
+// NewNotificationChannelStore creates a notification channel store on an existing database
+// connection. The notification_channels table is created by the storage migrations.
+func NewNotificationChannelStore(db *sql.DB, encryptionKey string) *NotificationChannelStore {
+	return &NotificationChannelStore{db: db, encryptionKey: encryptionKey}
+}
+
+// NewNotificationChannelStoreFromEnv returns a database-backed channel store, or nil with
+// file-based credential storage, where notification channels are not supported
+func NewNotificationChannelStoreFromEnv(credStore CredentialStoreInterface) NotificationChannelStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewNotificationChannelStore(pg.db, pg.encryptionKey)
+	}
+	return nil
+}
+
+// CreateNotificationChannel stores a new channel
+func (s *NotificationChannelStore) CreateNotificationChannel(channel *models.NotificationChannel) error {
+	query := `
+		INSERT INTO notification_channels (id, user_id, name, kind, webhook_url_encrypted, events, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	encryptedURL, err := crypto.Encrypt(channel.WebhookURL, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook URL: %v", err)
+	}
+	if channel.CreatedAt.IsZero() {
+		channel.CreatedAt = time.Now()
+	}
+
+	_, err = s.db.Exec(query,
+		channel.ID,
+		channel.UserID,
+		channel.Name,
+		channel.Kind,
+		encryptedURL,
+		strings.Join(channel.Events, " "),
+		channel.CreatedAt,
+	)
+	return err
+}
+
+// ListNotificationChannels returns a user's channels with their webhook URLs decrypted,
+// oldest first
+func (s *NotificationChannelStore) ListNotificationChannels(userID string) ([]models.NotificationChannel, error) {
+	query := `
+		SELECT id, user_id, name, kind, webhook_url_encrypted, events, created_at
+		FROM notification_channels
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := []models.NotificationChannel{}
+	for rows.Next() {
+		var channel models.NotificationChannel
+		var encryptedURL, events string
+		err := rows.Scan(
+			&channel.ID,
+			&channel.UserID,
+			&channel.Name,
+			&channel.Kind,
+			&encryptedURL,
+			&events,
+			&channel.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if channel.WebhookURL, err = crypto.Decrypt(encryptedURL, s.encryptionKey); err != nil {
+			return nil, fmt.Errorf("failed to decrypt webhook URL: %v", err)
+		}
+		channel.Events = strings.Fields(events)
+		channels = append(channels, channel)
+	}
+	return channels, rows.Err()
+}
+
+// DeleteNotificationChannel deletes one of the user's channels
+func (s *NotificationChannelStore) DeleteNotificationChannel(userID, channelID string) error {
+	result, err := s.db.Exec(`DELETE FROM notification_channels WHERE id = $1 AND user_id = $2`, channelID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,13 @@
+package storage
+
+import "github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+
+// GetMemberCredentials looks up a workspace the user owns, falling back to the
+// team-shared workspaces of their organization (stored with the org ID as owner).
+func GetMemberCredentials(store CredentialStoreInterface, userID, orgID, workspaceID string) (*models.WorkspaceCredentials, error) {
+	creds, err := store.GetCredentials(userID, workspaceID)
+	if err == ErrNotFound && orgID != "" {
+		return store.GetCredentials(orgID, workspaceID)
+	}
+	return creds, err
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// PinnedIssueStoreInterface stores users' pinned Jira issues
+type PinnedIssueStoreInterface interface {
+	// PinIssue adds an issue to the user's pins; pinning it again keeps the original time
+	PinIssue(pin *models.PinnedIssue) error
+	ListPinnedIssues(userID string) ([]models.PinnedIssue, error)
+	UnpinIssue(userID, workspaceID, issueKey string) error
+}
+
+// PinnedIssueStore keeps pinned issues in PostgreSQL
+type PinnedIssueStore struct {
+	db *sql.DB
+}
+
+// NewPinnedIssueStore creates a pinned issue store on an existing database connection.
+// The pinned_issues table is created by the storage migrations.
+func NewPinnedIssueStore(db *sql.DB) *PinnedIssueStore {
+	return &PinnedIssueStore{db: db}
+}
+
+// NewPinnedIssueStoreFromEnv returns a database-backed pinned issue store, or nil with
+// file-based credential storage, where pins are not supported
+func NewPinnedIssueStoreFromEnv(credStore CredentialStoreInterface) PinnedIssueStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewPinnedIssueStore(pg.db)
+	}
+	return nil
+}
+
+// PinIssue adds an issue to the user's pins
+func (s *PinnedIssueStore) PinIssue(pin *models.PinnedIssue) error {
+	query := `
+		INSERT INTO pinned_issues (user_id, workspace_id, issue_key, org_id, pinned_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, workspace_id, issue_key) DO UPDATE SET org_id = EXCLUDED.org_id
+		RETURNING pinned_at
+	`
+
+	return s.db.QueryRow(query, pin.UserID, pin.WorkspaceID, pin.IssueKey, pin.OrgID, time.Now().UTC()).
+		Scan(&pin.PinnedAt)
+}
+
+// ListPinnedIssues returns the user's pins, oldest first
+func (s *PinnedIssueStore) ListPinnedIssues(userID string) ([]models.PinnedIssue, error) {
+	query := `
+		SELECT user_id, org_id, workspace_id, issue_key, pinned_at
+		FROM pinned_issues
+		WHERE user_id = $1
+		ORDER BY pinned_at, workspace_id, issue_key
+	`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pins := []models.PinnedIssue{}
+	for rows.Next() {
+		var pin models.PinnedIssue
+		if err := rows.Scan(&pin.UserID, &pin.OrgID, &pin.WorkspaceID, &pin.IssueKey, &pin.PinnedAt); err != nil {
+			return nil, err
+		}
+		pins = append(pins, pin)
+	}
+	return pins, rows.Err()
+}
+
+// UnpinIssue removes an issue from the user's pins
+func (s *PinnedIssueStore) UnpinIssue(userID, workspaceID, issueKey string) error {
+	result, err := s.db.Exec(`DELETE FROM pinned_issues WHERE user_id = $1 AND workspace_id = $2 AND issue_key = $3`,
+		userID, workspaceID, issueKey)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// PlaybookStoreInterface stores users' playbooks
+type PlaybookStoreInterface interface {
+	CreatePlaybook(playbook *models.Playbook) error
+	GetPlaybook(userID, playbookID string) (*models.Playbook, error)
+	ListPlaybooks(userID string) ([]models.Playbook, error)
+	UpdatePlaybook(playbook *models.Playbook) error
+	DeletePlaybook(userID, playbookID string) error
+}
+
+// PlaybookStore keeps playbooks in PostgreSQL
+type PlaybookStore struct {
+	db *sql.DB
+}
+
+// NewPlaybookStore creates a playbook store on an existing database connection.
+// The playbooks table is created by the storage migrations.
+func NewPlaybookStore(db *sql.DB) *PlaybookStore {
+	return &PlaybookStore{db: db}
+}
+
+// NewPlaybookStoreFromEnv returns a database-backed playbook store, or nil with file-based
+// credential storage, where playbooks are not supported
+func NewPlaybookStoreFromEnv(credStore CredentialStoreInterface) PlaybookStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewPlaybookStore(pg.db)
+	}
+	return nil
+}
+
+const playbookColumns = `id, user_id, name, description, variables, steps, created_at, updated_at`
+
+// CreatePlaybook stores a new playbook
+func (s *PlaybookStore) CreatePlaybook(playbook *models.Playbook) error {
+	query := `
+		INSERT INTO playbooks (` + playbookColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	now := time.Now().UTC()
+	if playbook.CreatedAt.IsZero() {
+		playbook.CreatedAt = now
+	}
+	playbook.UpdatedAt = now
+
+	variables, steps, err := encodePlaybook(playbook)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(query,
+		playbook.ID,
+		playbook.UserID,
+		playbook.Name,
+		playbook.Description,
+		variables,
+		steps,
+		playbook.CreatedAt,
+		playbook.UpdatedAt,
+	)
+	return err
+}
+
+// GetPlaybook returns one of the user's playbooks
+func (s *PlaybookStore) GetPlaybook(userID, playbookID string) (*models.Playbook, error) {
+	query := `SELECT ` + playbookColumns + ` FROM playbooks WHERE id = $1 AND user_id = $2`
+
+	playbook, err := scanPlaybook(s.db.QueryRow(query, playbookID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return playbook, err
+}
+
+// ListPlaybooks returns a user's playbooks by name
+func (s *PlaybookStore) ListPlaybooks(userID string) ([]models.Playbook, error) {
+	query := `SELECT ` + playbookColumns + ` FROM playbooks WHERE user_id = $1 ORDER BY name`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	playbooks := []models.Playbook{}
+	for rows.Next() {
+		playbook, err := scanPlaybook(rows)
+		if err != nil {
+			return nil, err
+		}
+		playbooks = append(playbooks, *playbook)
+	}
+	return playbooks, rows.Err()
+}
+
+// UpdatePlaybook replaces one of the user's playbooks
+func (s *PlaybookStore) UpdatePlaybook(playbook *models.Playbook) error {
+	query := `
+		UPDATE playbooks
+		SET name = $3, description = $4, variables = $5, steps = $6, updated_at = $7
+		WHERE id = $1 AND user_id = $2
+	`
+
+	playbook.UpdatedAt = time.Now().UTC()
+	variables, steps, err := encodePlaybook(playbook)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.Exec(query,
+		playbook.ID,
+		playbook.UserID,
+		playbook.Name,
+		playbook.Description,
+		variables,
+		steps,
+		playbook.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// DeletePlaybook deletes one of the user's playbooks
+func (s *PlaybookStore) DeletePlaybook(userID, playbookID string) error {
+	result, err := s.db.Exec(`DELETE FROM playbooks WHERE id = $1 AND user_id = $2`, playbookID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// encodePlaybook serializes a playbook's variables and steps for their JSONB columns
+func encodePlaybook(playbook *models.Playbook) (string, string, error) {
+	vars := playbook.Variables
+	if vars == nil {
+		vars = []models.PlaybookVariable{}
+	}
+	variables, err := json.Marshal(vars)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode playbook variables: %v", err)
+	}
+	steps, err := json.Marshal(playbook.Steps)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode playbook steps: %v", err)
+	}
+	return string(variables), string(steps), nil
+}
+
+// scanPlaybook reads one playbooks row
+func scanPlaybook(row interface{ Scan(...interface{}) error }) (*models.Playbook, error) {
+	var playbook models.Playbook
+	var variables, steps []byte
+	err := row.Scan(
+		&playbook.ID,
+		&playbook.UserID,
+		&playbook.Name,
+		&playbook.Description,
+		&variables,
+		&steps,
+		&playbook.CreatedAt,
+		&playbook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(variables, &playbook.Variables); err != nil {
+		return nil, fmt.Errorf("failed to decode playbook variables: %v", err)
+	}
+	if err := json.Unmarshal(steps, &playbook.Steps); err != nil {
+		return nil, fmt.Errorf("failed to decode playbook steps: %v", err)
+	}
+	return &playbook, nil
+}
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/providentiaww/trilix-atlassian-mcp/internal/crypto"
@@ -33,54 +35,34 @@ func NewCredentialStore(connectionString, encryptionKey string) (*CredentialStor
 		return nil, fmt.Errorf("failed to ping postgres: %v", err)
 	}
 
-	fmt.Println("✅ Successfully connected to PostgreSQL/Supabase")
+	slog.Info("connected to PostgreSQL")
 
 	store := &CredentialStore{
 		db:            db,
 		encryptionKey: encryptionKey,
 	}
 
-	// Initialize schema
-	if err := store.initSchema(); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %v", err)
+	// Bring the schema up to date
+	if err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate schema: %v", err)
 	}
 
 	return store, nil
 }
 
-// initSchema creates the necessary database tables
-func (s *CredentialStore) initSchema() error {
-	query := `
-	CREATE TABLE IF NOT EXISTS atlassian_credentials (
-		user_id VARCHAR(255) NOT NULL,
-		workspace_id VARCHAR(255) NOT NULL,
-		workspace_name VARCHAR(255) NOT NULL,
-		atlassian_url VARCHAR(500) NOT NULL,
-		email VARCHAR(255) NOT NULL,
-		api_token_encrypted TEXT NOT NULL,
-		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		PRIMARY KEY (user_id, workspace_id)
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_user_id ON atlassian_credentials(user_id);
-	`
-
-	_, err := s.db.Exec(query)
-	return err
-}
-
 // GetCredentials retrieves and decrypts credentials for a user/workspace
 func (s *CredentialStore) GetCredentials(userID, workspaceID string) (*models.WorkspaceCredentials, error) {
 	var encryptedToken, atlassianURL, email string
+	var encryptedOpsgenieKey, encryptedProxyURL, jiraURL, confluenceURL sql.NullString
+	var policyJSON []byte
 
 	query := `
-		SELECT atlassian_url, email, api_token_encrypted
+		SELECT atlassian_url, email, api_token_encrypted, policy, opsgenie_api_key_encrypted, jira_url, confluence_url, proxy_url_encrypted
 		FROM atlassian_credentials
-		WHERE user_id = $1 AND workspace_id = $2
+		WHERE user_id = $1 AND workspace_id = $2 AND deleted_at IS NULL
 	`
 
-	err := s.db.QueryRow(query, userID, workspaceID).Scan(&atlassianURL, &email, &encryptedToken)
+	err := s.db.QueryRow(query, userID, workspaceID).Scan(&atlassianURL, &email, &encryptedToken, &policyJSON, &encryptedOpsgenieKey, &jiraURL, &confluenceURL, &encryptedProxyURL)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
@@ -94,10 +76,35 @@ func (s *CredentialStore) GetCredentials(userID, workspaceID string) (*models.Wo
 		return nil, err
 	}
 
+	var opsgenieKey string
+	if encryptedOpsgenieKey.String != "" {
+		if opsgenieKey, err = crypto.Decrypt(encryptedOpsgenieKey.String, s.encryptionKey); err != nil {
+			return nil, err
+		}
+	}
+
+	var proxyURL string
+	if encryptedProxyURL.String != "" {
+		if proxyURL, err = crypto.Decrypt(encryptedProxyURL.String, s.encryptionKey); err != nil {
+			return nil, err
+		}
+	}
+
+	policy, err := decodePolicy(policyJSON)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.WorkspaceCredentials{
-		Site:  atlassianURL,
-		Email: email,
-		Token: token,
+		Site:        atlassianURL,
+		Email:       email,
+		Token:       token,
+		Policy:      policy,
+		OpsgenieKey: opsgenieKey,
+
+		JiraURL:       jiraURL.String,
+		ConfluenceURL: confluenceURL.String,
+		ProxyURL:      proxyURL,
 	}, nil
 }
 
@@ -109,17 +116,48 @@ func (s *CredentialStore) SaveCredentials(cred *models.AtlassianCredential) erro
 		return err
 	}
 
+	// The Opsgenie key is optional and stays NULL when unset
+	var encryptedOpsgenieKey sql.NullString
+	if cred.OpsgenieKey != "" {
+		encrypted, err := crypto.Encrypt(cred.OpsgenieKey, s.encryptionKey)
+		if err != nil {
+			return err
+		}
+		encryptedOpsgenieKey = sql.NullString{String: encrypted, Valid: true}
+	}
+
+	// So is the proxy, whose URL may carry a password
+	var encryptedProxyURL sql.NullString
+	if cred.ProxyURL != "" {
+		encrypted, err := crypto.Encrypt(cred.ProxyURL, s.encryptionKey)
+		if err != nil {
+			return err
+		}
+		encryptedProxyURL = sql.NullString{String: encrypted, Valid: true}
+	}
+
+	policyJSON, err := encodePolicy(cred.Policy)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO atlassian_credentials 
-			(user_id, workspace_id, workspace_name, atlassian_url, email, api_token_encrypted, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			(user_id, workspace_id, workspace_name, atlassian_url, email, api_token_encrypted, policy, created_at, updated_at, opsgenie_api_key_encrypted, jira_url, confluence_url, proxy_url_encrypted)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		ON CONFLICT (user_id, workspace_id)
 		DO UPDATE SET
 			workspace_name = EXCLUDED.workspace_name,
 			atlassian_url = EXCLUDED.atlassian_url,
 			email = EXCLUDED.email,
 			api_token_encrypted = EXCLUDED.api_token_encrypted,
-			updated_at = EXCLUDED.updated_at
+			policy = EXCLUDED.policy,
+			opsgenie_api_key_encrypted = EXCLUDED.opsgenie_api_key_encrypted,
+			jira_url = EXCLUDED.jira_url,
+			confluence_url = EXCLUDED.confluence_url,
+			proxy_url_encrypted = EXCLUDED.proxy_url_encrypted,
+			updated_at = EXCLUDED.updated_at,
+			deleted_at = NULL
 	`
 
 	now := time.Now()
@@ -135,30 +173,71 @@ func (s *CredentialStore) SaveCredentials(cred *models.AtlassianCredential) erro
 		cred.AtlassianURL,
 		cred.Email,
 		encryptedToken,
+		policyJSON,
 		cred.CreatedAt,
 		cred.UpdatedAt,
+		encryptedOpsgenieKey,
+		nullString(cred.JiraURL),
+		nullString(cred.ConfluenceURL),
+		encryptedProxyURL,
 	)
 
 	return err
 }
 
-// DeleteCredentials removes credentials for a user/workspace
+// DeleteCredentials soft-deletes credentials for a user/workspace; they can be
+// restored until PurgeDeletedCredentials removes them
 func (s *CredentialStore) DeleteCredentials(userID, workspaceID string) error {
+	query := `
+		UPDATE atlassian_credentials
+		SET deleted_at = NOW()
+		WHERE user_id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+	`
+
+	return s.execOne(query, userID, workspaceID)
+}
+
+// RestoreCredentials undoes a soft delete
+func (s *CredentialStore) RestoreCredentials(userID, workspaceID string) error {
+	query := `
+		UPDATE atlassian_credentials
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND workspace_id = $2 AND deleted_at IS NOT NULL
+	`
+
+	return s.execOne(query, userID, workspaceID)
+}
+
+// PurgeDeletedCredentials permanently removes credentials soft-deleted before the given time
+func (s *CredentialStore) PurgeDeletedCredentials(deletedBefore time.Time) (int, error) {
 	query := `
 		DELETE FROM atlassian_credentials
-		WHERE user_id = $1 AND workspace_id = $2
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
 	`
 
-	_, err := s.db.Exec(query, userID, workspaceID)
-	return err
+	result, err := s.db.Exec(query, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := result.RowsAffected()
+	return int(purged), err
 }
 
 // ListWorkspaces returns all workspaces for a user
 func (s *CredentialStore) ListWorkspaces(userID string) ([]models.AtlassianCredential, error) {
+	return s.listWorkspaces(userID, "deleted_at IS NULL")
+}
+
+// ListDeletedWorkspaces returns soft-deleted workspaces that have not been purged yet
+func (s *CredentialStore) ListDeletedWorkspaces(userID string) ([]models.AtlassianCredential, error) {
+	return s.listWorkspaces(userID, "deleted_at IS NOT NULL")
+}
+
+func (s *CredentialStore) listWorkspaces(userID, deletedFilter string) ([]models.AtlassianCredential, error) {
 	query := `
-		SELECT user_id, workspace_id, workspace_name, atlassian_url, email, created_at, updated_at
+		SELECT user_id, workspace_id, workspace_name, atlassian_url, jira_url, confluence_url, email, policy, created_at, updated_at, deleted_at
 		FROM atlassian_credentials
-		WHERE user_id = $1
+		WHERE user_id = $1 AND ` + deletedFilter + `
 		ORDER BY workspace_name
 	`
 
@@ -171,24 +250,54 @@ func (s *CredentialStore) ListWorkspaces(userID string) ([]models.AtlassianCrede
 	var credentials []models.AtlassianCredential
 	for rows.Next() {
 		var cred models.AtlassianCredential
+		var policyJSON []byte
+		var deletedAt sql.NullTime
+		var jiraURL, confluenceURL sql.NullString
 		err := rows.Scan(
 			&cred.UserID,
 			&cred.WorkspaceID,
 			&cred.WorkspaceName,
 			&cred.AtlassianURL,
+			&jiraURL,
+			&confluenceURL,
 			&cred.Email,
+			&policyJSON,
 			&cred.CreatedAt,
 			&cred.UpdatedAt,
+			&deletedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if cred.Policy, err = decodePolicy(policyJSON); err != nil {
+			return nil, err
+		}
+		if deletedAt.Valid {
+			cred.DeletedAt = &deletedAt.Time
+		}
+		cred.JiraURL, cred.ConfluenceURL = jiraURL.String, confluenceURL.String
 		credentials = append(credentials, cred)
 	}
 
 	return credentials, rows.Err()
 }
 
+// execOne runs an update that must affect exactly one workspace, returning ErrNotFound otherwise
+func (s *CredentialStore) execOne(query string, args ...interface{}) error {
+	result, err := s.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // Ping tests the database connection
 func (s *CredentialStore) Ping() error {
 	return s.db.Ping()
@@ -199,6 +308,35 @@ func (s *CredentialStore) Close() error {
 	return s.db.Close()
 }
 
+// encodePolicy serializes a workspace policy for the JSONB column (nil stays NULL)
+func encodePolicy(policy *models.WorkspacePolicy) (interface{}, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode policy: %v", err)
+	}
+	return string(data), nil
+}
+
+// nullString stores an empty optional column as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// decodePolicy parses a workspace policy read from the JSONB column
+func decodePolicy(data []byte) (*models.WorkspacePolicy, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var policy models.WorkspacePolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to decode policy: %v", err)
+	}
+	return &policy, nil
+}
+
 var ErrNotFound = &NotFoundError{}
 
 type NotFoundError struct{}
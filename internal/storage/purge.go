@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultDeletedWorkspaceRetention is how long soft-deleted workspaces can be restored
+// when DELETED_WORKSPACE_RETENTION_DAYS is not set
+const DefaultDeletedWorkspaceRetention = 30 * 24 * time.Hour
+
+// purgeInterval is how often the purge loop looks for expired workspaces
+const purgeInterval = time.Hour
+
+// DeletedWorkspaceRetentionFromEnv reads DELETED_WORKSPACE_RETENTION_DAYS; "0" disables purging
+func DeletedWorkspaceRetentionFromEnv() time.Duration {
+	if v := os.Getenv("DELETED_WORKSPACE_RETENTION_DAYS"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days >= 0 {
+			return time.Duration(days) * 24 * time.Hour
+		}
+	}
+	return DefaultDeletedWorkspaceRetention
+}
+
+// StartPurgeLoop permanently removes soft-deleted workspaces once they are older than
+// the retention period. It runs immediately and then hourly for the life of the process.
+func StartPurgeLoop(store CredentialStoreInterface, retention time.Duration) {
+	if retention <= 0 {
+		slog.Info("deleted workspace purge disabled; soft-deleted workspaces are kept indefinitely")
+		return
+	}
+
+	purge := func() {
+		purged, err := store.PurgeDeletedCredentials(time.Now().Add(-retention))
+		if err != nil {
+			slog.Warn("failed to purge deleted workspaces", "error", err)
+			return
+		}
+		if purged > 0 {
+			slog.Info("purged deleted workspaces", "count", purged, "retention", retention.String())
+		}
+	}
+
+	go func() {
+		purge()
+		for range time.Tick(purgeInterval) {
+			purge()
+		}
+	}()
+}
+
+// StartIdempotencyPurgeLoop deletes expired idempotency keys hourly for the life of the process
+func StartIdempotencyPurgeLoop(store IdempotencyStoreInterface) {
+	go func() {
+		for range time.Tick(purgeInterval) {
+			purged, err := store.PurgeExpired()
+			if err != nil {
+				slog.Warn("failed to purge expired idempotency keys", "error", err)
+			} else if purged > 0 {
+				slog.Debug("purged expired idempotency keys", "count", purged)
+			}
+		}
+	}()
+}
+
+// StartExportPurgeLoop deletes expired exports hourly for the life of the process
+func StartExportPurgeLoop(store ExportStoreInterface) {
+	go func() {
+		for now := range time.Tick(purgeInterval) {
+			purged, err := store.PurgeExpiredExports(now)
+			if err != nil {
+				slog.Warn("failed to purge expired exports", "error", err)
+			} else if purged > 0 {
+				slog.Debug("purged expired exports", "count", purged)
+			}
+		}
+	}()
+}
@@ -0,0 +1,363 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// ScheduleStoreInterface stores scheduled tool pipelines and their run history
+type ScheduleStoreInterface interface {
+	CreateSchedule(schedule *models.Schedule) error
+	GetSchedule(userID, scheduleID string) (*models.Schedule, error)
+	ListSchedules(userID string) ([]models.Schedule, error)
+	UpdateSchedule(schedule *models.Schedule) error
+	DeleteSchedule(userID, scheduleID string) error
+
+	// DueSchedules returns the enabled schedules whose next run is at or before now
+	DueSchedules(now time.Time) ([]models.Schedule, error)
+	// ClaimScheduleRun moves a due schedule's next run from due to next. It reports false
+	// when another replica claimed the run first.
+	ClaimScheduleRun(scheduleID string, due, next time.Time) (bool, error)
+
+	CreateScheduleRun(run *models.ScheduleRun) error
+	FinishScheduleRun(run *models.ScheduleRun) error
+	ListScheduleRuns(scheduleID string, limit int) ([]models.ScheduleRun, error)
+	// PurgeScheduleRuns deletes all but the newest keep runs of a schedule
+	PurgeScheduleRuns(scheduleID string, keep int) error
+}
+
+// ScheduleStore keeps schedules in PostgreSQL
+type ScheduleStore struct {
+	db *sql.DB
+}
+
+// NewScheduleStore creates a schedule store on an existing database connection.
+// The schedules and schedule_runs tables are created by the storage migrations.
+func NewScheduleStore(db *sql.DB) *ScheduleStore {
+	return &ScheduleStore{db: db}
+}
+
+// NewScheduleStoreFromEnv returns a database-backed schedule store, or nil with file-based
+// credential storage, where schedules are not supported
+func NewScheduleStoreFromEnv(credStore CredentialStoreInterface) ScheduleStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewScheduleStore(pg.db)
+	}
+	return nil
+}
+
+const scheduleColumns = `id, user_id, org_id, name, cron, timezone, steps, output, enabled, next_run_at, last_run_at, created_at, updated_at`
+
+// CreateSchedule stores a new schedule
+func (s *ScheduleStore) CreateSchedule(schedule *models.Schedule) error {
+	query := `
+		INSERT INTO schedules (id, user_id, org_id, name, cron, timezone, steps, output, enabled, next_run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	now := time.Now().UTC()
+	if schedule.CreatedAt.IsZero() {
+		schedule.CreatedAt = now
+	}
+	schedule.UpdatedAt = now
+
+	steps, output, err := encodeSchedule(schedule)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(query,
+		schedule.ID,
+		schedule.UserID,
+		schedule.OrgID,
+		schedule.Name,
+		schedule.Cron,
+		schedule.Timezone,
+		steps,
+		output,
+		schedule.Enabled,
+		utcTime(schedule.NextRunAt),
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	)
+	return err
+}
+
+// GetSchedule returns one of the user's schedules
+func (s *ScheduleStore) GetSchedule(userID, scheduleID string) (*models.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE id = $1 AND user_id = $2`
+
+	schedule, err := scanSchedule(s.db.QueryRow(query, scheduleID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return schedule, err
+}
+
+// ListSchedules returns a user's schedules, oldest first
+func (s *ScheduleStore) ListSchedules(userID string) ([]models.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE user_id = $1 ORDER BY created_at`
+	return s.querySchedules(query, userID)
+}
+
+// UpdateSchedule replaces one of the user's schedules
+func (s *ScheduleStore) UpdateSchedule(schedule *models.Schedule) error {
+	query := `
+		UPDATE schedules
+		SET name = $3, cron = $4, timezone = $5, steps = $6, output = $7, enabled = $8, next_run_at = $9, updated_at = $10
+		WHERE id = $1 AND user_id = $2
+	`
+
+	schedule.UpdatedAt = time.Now().UTC()
+	steps, output, err := encodeSchedule(schedule)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.Exec(query,
+		schedule.ID,
+		schedule.UserID,
+		schedule.Name,
+		schedule.Cron,
+		schedule.Timezone,
+		steps,
+		output,
+		schedule.Enabled,
+		utcTime(schedule.NextRunAt),
+		schedule.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// DeleteSchedule deletes one of the user's schedules and its run history
+func (s *ScheduleStore) DeleteSchedule(userID, scheduleID string) error {
+	result, err := s.db.Exec(`DELETE FROM schedules WHERE id = $1 AND user_id = $2`, scheduleID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// DueSchedules returns the enabled schedules whose next run is at or before now
+func (s *ScheduleStore) DueSchedules(now time.Time) ([]models.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE enabled AND next_run_at <= $1 ORDER BY next_run_at`
+	return s.querySchedules(query, now.UTC())
+}
+
+// ClaimScheduleRun moves a due schedule's next run from due to next, unless another
+// replica already has
+func (s *ScheduleStore) ClaimScheduleRun(scheduleID string, due, next time.Time) (bool, error) {
+	query := `
+		UPDATE schedules
+		SET next_run_at = $3, last_run_at = NOW() AT TIME ZONE 'UTC'
+		WHERE id = $1 AND next_run_at = $2
+	`
+
+	var nextRun interface{}
+	if !next.IsZero() {
+		nextRun = next.UTC()
+	}
+	result, err := s.db.Exec(query, scheduleID, due.UTC(), nextRun)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// CreateScheduleRun records the start of a run
+func (s *ScheduleStore) CreateScheduleRun(run *models.ScheduleRun) error {
+	query := `
+		INSERT INTO schedule_runs (id, schedule_id, triggered_by, status, error, steps, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now().UTC()
+	}
+	steps, err := json.Marshal(run.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to encode run steps: %v", err)
+	}
+	_, err = s.db.Exec(query,
+		run.ID,
+		run.ScheduleID,
+		run.TriggeredBy,
+		run.Status,
+		run.Error,
+		string(steps),
+		run.StartedAt,
+		utcTime(run.FinishedAt),
+	)
+	return err
+}
+
+// FinishScheduleRun records a run's outcome
+func (s *ScheduleStore) FinishScheduleRun(run *models.ScheduleRun) error {
+	query := `
+		UPDATE schedule_runs
+		SET status = $2, error = $3, steps = $4, finished_at = $5
+		WHERE id = $1
+	`
+
+	steps, err := json.Marshal(run.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to encode run steps: %v", err)
+	}
+	_, err = s.db.Exec(query, run.ID, run.Status, run.Error, string(steps), utcTime(run.FinishedAt))
+	return err
+}
+
+// ListScheduleRuns returns a schedule's most recent runs, newest first
+func (s *ScheduleStore) ListScheduleRuns(scheduleID string, limit int) ([]models.ScheduleRun, error) {
+	query := `
+		SELECT id, schedule_id, triggered_by, status, error, steps, started_at, finished_at
+		FROM schedule_runs
+		WHERE schedule_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2
+	`
+
+	rows, err := s.db.Query(query, scheduleID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []models.ScheduleRun{}
+	for rows.Next() {
+		var run models.ScheduleRun
+		var steps []byte
+		var finishedAt sql.NullTime
+		err := rows.Scan(
+			&run.ID,
+			&run.ScheduleID,
+			&run.TriggeredBy,
+			&run.Status,
+			&run.Error,
+			&steps,
+			&run.StartedAt,
+			&finishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(steps, &run.Steps); err != nil {
+			return nil, fmt.Errorf("failed to decode run steps: %v", err)
+		}
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// PurgeScheduleRuns deletes all but the newest keep runs of a schedule
+func (s *ScheduleStore) PurgeScheduleRuns(scheduleID string, keep int) error {
+	query := `
+		DELETE FROM schedule_runs
+		WHERE schedule_id = $1 AND id NOT IN (
+			SELECT id FROM schedule_runs WHERE schedule_id = $1 ORDER BY started_at DESC LIMIT $2
+		)
+	`
+
+	_, err := s.db.Exec(query, scheduleID, keep)
+	return err
+}
+
+func (s *ScheduleStore) querySchedules(query string, args ...interface{}) ([]models.Schedule, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []models.Schedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// encodeSchedule serializes a schedule's steps and output for their JSONB columns
+// (a nil output stays NULL)
+func encodeSchedule(schedule *models.Schedule) (string, interface{}, error) {
+	steps, err := json.Marshal(schedule.Steps)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode schedule steps: %v", err)
+	}
+	if schedule.Output == nil {
+		return string(steps), nil, nil
+	}
+	output, err := json.Marshal(schedule.Output)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode schedule output: %v", err)
+	}
+	return string(steps), string(output), nil
+}
+
+// utcTime converts an optional time for a TIMESTAMP column, which has no time zone
+func utcTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// scanSchedule reads one schedules row
+func scanSchedule(row interface{ Scan(...interface{}) error }) (*models.Schedule, error) {
+	var schedule models.Schedule
+	var steps, output []byte
+	var nextRunAt, lastRunAt sql.NullTime
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.UserID,
+		&schedule.OrgID,
+		&schedule.Name,
+		&schedule.Cron,
+		&schedule.Timezone,
+		&steps,
+		&output,
+		&schedule.Enabled,
+		&nextRunAt,
+		&lastRunAt,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(steps, &schedule.Steps); err != nil {
+		return nil, fmt.Errorf("failed to decode schedule steps: %v", err)
+	}
+	if len(output) > 0 {
+		schedule.Output = &models.ScheduleOutput{}
+		if err := json.Unmarshal(output, schedule.Output); err != nil {
+			return nil, fmt.Errorf("failed to decode schedule output: %v", err)
+		}
+	}
+	if nextRunAt.Valid {
+		schedule.NextRunAt = &nextRunAt.Time
+	}
+	if lastRunAt.Valid {
+		schedule.LastRunAt = &lastRunAt.Time
+	}
+	return &schedule, nil
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// ServiceTokenStoreInterface stores hashed service tokens
+type ServiceTokenStoreInterface interface {
+	CreateServiceToken(token *models.ServiceToken, tokenHash string) error
+	GetServiceTokenByHash(tokenHash string) (*models.ServiceToken, error)
+	ListServiceTokens() ([]models.ServiceToken, error)
+	RevokeServiceToken(tokenID string) error
+	TouchServiceToken(tokenID string) error
+}
+
+// ServiceTokenStore keeps service tokens in PostgreSQL
+type ServiceTokenStore struct {
+	db *sql.DB
+}
+
+// NewServiceTokenStore creates a service token store on an existing database connection.
+// The service_tokens table is created by the storage migrations.
+func NewServiceTokenStore(db *sql.DB) *ServiceTokenStore {
+	return &ServiceTokenStore{db: db}
+}
+
+// NewServiceTokenStoreFromEnv returns a database-backed token store, or nil with
+// file-based credential storage, where service tokens are not supported
+func NewServiceTokenStoreFromEnv(credStore CredentialStoreInterface) ServiceTokenStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewServiceTokenStore(pg.db)
+	}
+	return nil
+}
+
+// CreateServiceToken stores a new token
+func (s *ServiceTokenStore) CreateServiceToken(token *models.ServiceToken, tokenHash string) error {
+	query := `
+		INSERT INTO service_tokens (id, name, token_hash, prefix, allowed_users, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	_, err := s.db.Exec(query,
+		token.ID,
+		token.Name,
+		tokenHash,
+		token.Prefix,
+		strings.Join(token.AllowedUsers, " "),
+		token.CreatedBy,
+		token.CreatedAt,
+	)
+	return err
+}
+
+// GetServiceTokenByHash looks up a token by the hash of its secret, including revoked tokens
+func (s *ServiceTokenStore) GetServiceTokenByHash(tokenHash string) (*models.ServiceToken, error) {
+	query := `
+		SELECT id, name, prefix, allowed_users, created_by, created_at, last_used_at, revoked_at
+		FROM service_tokens
+		WHERE token_hash = $1
+	`
+
+	token, err := scanServiceToken(s.db.QueryRow(query, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return token, err
+}
+
+// ListServiceTokens returns all tokens, newest first
+func (s *ServiceTokenStore) ListServiceTokens() ([]models.ServiceToken, error) {
+	query := `
+		SELECT id, name, prefix, allowed_users, created_by, created_at, last_used_at, revoked_at
+		FROM service_tokens
+		ORDER BY created_at DESC
+	`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []models.ServiceToken{}
+	for rows.Next() {
+		token, err := scanServiceToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// RevokeServiceToken revokes a token
+func (s *ServiceTokenStore) RevokeServiceToken(tokenID string) error {
+	query := `
+		UPDATE service_tokens
+		SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := s.db.Exec(query, tokenID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// TouchServiceToken records that a token was just used
+func (s *ServiceTokenStore) TouchServiceToken(tokenID string) error {
+	_, err := s.db.Exec(`UPDATE service_tokens SET last_used_at = NOW() WHERE id = $1`, tokenID)
+	return err
+}
+
+// scanServiceToken reads one service_tokens row
+func scanServiceToken(row interface{ Scan(...interface{}) error }) (*models.ServiceToken, error) {
+	var token models.ServiceToken
+	var allowedUsers string
+	var lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(
+		&token.ID,
+		&token.Name,
+		&token.Prefix,
+		&allowedUsers,
+		&token.CreatedBy,
+		&token.CreatedAt,
+		&lastUsedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token.AllowedUsers = strings.Fields(allowedUsers)
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return &token, nil
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"os"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/cache"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// DefaultTokenCacheTTL is used when TOKEN_CACHE_TTL is not set
+const DefaultTokenCacheTTL = 30 * time.Second
+
+// touchInterval limits how often last_used_at is written for the same key or token
+const touchInterval = time.Minute
+
+// Kinds of credentials held in a TokenCache, reported to OnRevoke
+const (
+	TokenKindAPIKey       = "api_key"
+	TokenKindServiceToken = "service_token"
+)
+
+// TokenCache keeps API key and service token lookups in memory for a short TTL so
+// that authenticating a request does not query the database every time. A
+// revocation through either wrapped store clears the whole cache; OnRevoke lets the
+// caller broadcast the revocation so other replicas clear theirs too.
+type TokenCache struct {
+	cache    *cache.SimpleCache
+	ttl      time.Duration
+	onRevoke func(kind, id string)
+}
+
+// NewTokenCache creates a token lookup cache; a zero TTL disables caching
+func NewTokenCache(ttl time.Duration) *TokenCache {
+	return &TokenCache{
+		cache: cache.NewSimpleCache(),
+		ttl:   ttl,
+	}
+}
+
+// TokenCacheTTLFromEnv reads TOKEN_CACHE_TTL (e.g., "30s"); "0" disables caching
+func TokenCacheTTLFromEnv() time.Duration {
+	if v := os.Getenv("TOKEN_CACHE_TTL"); v != "" {
+		if v == "0" {
+			return 0
+		}
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return DefaultTokenCacheTTL
+}
+
+// OnRevoke registers a callback invoked after an API key or service token is revoked
+func (c *TokenCache) OnRevoke(fn func(kind, id string)) {
+	c.onRevoke = fn
+}
+
+// Invalidate drops every cached lookup. Revocations are rare, so clearing
+// everything is simpler than tracking which hash belongs to which ID.
+func (c *TokenCache) Invalidate() {
+	c.cache.Clear()
+}
+
+// Stats reports the token cache's size and hit rate
+func (c *TokenCache) Stats() cache.Stats {
+	return c.cache.Stats()
+}
+
+// TTL returns how long lookups are cached (0 = caching disabled)
+func (c *TokenCache) TTL() time.Duration {
+	return c.ttl
+}
+
+// WrapAPIKeys puts the cache in front of an API key store; nil stays nil
+func (c *TokenCache) WrapAPIKeys(inner APIKeyStoreInterface) APIKeyStoreInterface {
+	if inner == nil {
+		return nil
+	}
+	return &cachedAPIKeyStore{inner: inner, tokens: c}
+}
+
+// WrapServiceTokens puts the cache in front of a service token store; nil stays nil
+func (c *TokenCache) WrapServiceTokens(inner ServiceTokenStoreInterface) ServiceTokenStoreInterface {
+	if inner == nil {
+		return nil
+	}
+	return &cachedServiceTokenStore{inner: inner, tokens: c}
+}
+
+func (c *TokenCache) get(key string) (interface{}, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+	return c.cache.Get(key)
+}
+
+func (c *TokenCache) set(key string, value interface{}) {
+	if c.ttl > 0 {
+		c.cache.Set(key, value, c.ttl)
+	}
+}
+
+// shouldTouch reports whether last_used_at is due for an update, recording the touch
+func (c *TokenCache) shouldTouch(kind, id string) bool {
+	key := "touched:" + kind + ":" + id
+	if _, found := c.cache.Get(key); found {
+		return false
+	}
+	c.cache.Set(key, true, touchInterval)
+	return true
+}
+
+func (c *TokenCache) revoked(kind, id string) {
+	c.Invalidate()
+	if c.onRevoke != nil {
+		c.onRevoke(kind, id)
+	}
+}
+
+// cachedAPIKeyStore serves GetAPIKeyByHash from a TokenCache. Unknown hashes are not
+// cached, so guessing keys cannot grow the cache.
+type cachedAPIKeyStore struct {
+	inner  APIKeyStoreInterface
+	tokens *TokenCache
+}
+
+func (s *cachedAPIKeyStore) CreateAPIKey(key *models.APIKey, keyHash string) error {
+	return s.inner.CreateAPIKey(key, keyHash)
+}
+
+func (s *cachedAPIKeyStore) GetAPIKeyByHash(keyHash string) (*models.APIKey, error) {
+	cacheKey := TokenKindAPIKey + ":" + keyHash
+	if cached, found := s.tokens.get(cacheKey); found {
+		if key, ok := cached.(*models.APIKey); ok {
+			return key, nil
+		}
+	}
+
+	key, err := s.inner.GetAPIKeyByHash(keyHash)
+	if err != nil {
+		return nil, err
+	}
+	s.tokens.set(cacheKey, key)
+	return key, nil
+}
+
+func (s *cachedAPIKeyStore) ListAPIKeys(userID string) ([]models.APIKey, error) {
+	return s.inner.ListAPIKeys(userID)
+}
+
+func (s *cachedAPIKeyStore) RevokeAPIKey(userID, keyID string) error {
+	if err := s.inner.RevokeAPIKey(userID, keyID); err != nil {
+		return err
+	}
+	s.tokens.revoked(TokenKindAPIKey, keyID)
+	return nil
+}
+
+func (s *cachedAPIKeyStore) TouchAPIKey(keyID string) error {
+	if !s.tokens.shouldTouch(TokenKindAPIKey, keyID) {
+		return nil
+	}
+	return s.inner.TouchAPIKey(keyID)
+}
+
+// cachedServiceTokenStore serves GetServiceTokenByHash from a TokenCache
+type cachedServiceTokenStore struct {
+	inner  ServiceTokenStoreInterface
+	tokens *TokenCache
+}
+
+func (s *cachedServiceTokenStore) CreateServiceToken(token *models.ServiceToken, tokenHash string) error {
+	return s.inner.CreateServiceToken(token, tokenHash)
+}
+
+func (s *cachedServiceTokenStore) GetServiceTokenByHash(tokenHash string) (*models.ServiceToken, error) {
+	cacheKey := TokenKindServiceToken + ":" + tokenHash
+	if cached, found := s.tokens.get(cacheKey); found {
+		if token, ok := cached.(*models.ServiceToken); ok {
+			return token, nil
+		}
+	}
+
+	token, err := s.inner.GetServiceTokenByHash(tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	s.tokens.set(cacheKey, token)
+	return token, nil
+}
+
+func (s *cachedServiceTokenStore) ListServiceTokens() ([]models.ServiceToken, error) {
+	return s.inner.ListServiceTokens()
+}
+
+func (s *cachedServiceTokenStore) RevokeServiceToken(tokenID string) error {
+	if err := s.inner.RevokeServiceToken(tokenID); err != nil {
+		return err
+	}
+	s.tokens.revoked(TokenKindServiceToken, tokenID)
+	return nil
+}
+
+func (s *cachedServiceTokenStore) TouchServiceToken(tokenID string) error {
+	if !s.tokens.shouldTouch(TokenKindServiceToken, tokenID) {
+		return nil
+	}
+	return s.inner.TouchServiceToken(tokenID)
+}
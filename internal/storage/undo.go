@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// DefaultUndoTTL is how long changes can be undone when UNDO_TTL is not set
+const DefaultUndoTTL = 24 * time.Hour
+
+// maxUndoEntries is how many changes are kept per user; older ones can no longer be undone
+const maxUndoEntries = 50
+
+// UndoStoreInterface keeps each user's recent reversible changes, newest last
+type UndoStoreInterface interface {
+	// PushUndo records a change, dropping the user's changes past the TTL or the limit
+	PushUndo(entry *models.UndoEntry, ttl time.Duration) error
+	// LatestUndo returns the user's most recent change within the TTL, or ErrNotFound
+	LatestUndo(userID string, ttl time.Duration) (*models.UndoEntry, error)
+	// DeleteUndo forgets a change once it was undone
+	DeleteUndo(userID string, id int64) error
+}
+
+// UndoStore keeps the undo log in PostgreSQL
+type UndoStore struct {
+	db *sql.DB
+}
+
+// NewUndoStore creates an undo store on an existing database connection.
+// The undo_log table is created by the storage migrations.
+func NewUndoStore(db *sql.DB) *UndoStore {
+	return &UndoStore{db: db}
+}
+
+// PushUndo records a change
+func (s *UndoStore) PushUndo(entry *models.UndoEntry, ttl time.Duration) error {
+	undo, err := json.Marshal(entry.Undo)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO undo_log (user_id, org_id, product, workspace_id, tool, undo)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err = s.db.QueryRow(query, entry.UserID, entry.OrgID, entry.Product, entry.WorkspaceID, entry.Tool, undo).
+		Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		DELETE FROM undo_log
+		WHERE user_id = $1 AND (created_at < NOW() - $2 * INTERVAL '1 second' OR id NOT IN (
+			SELECT id FROM undo_log WHERE user_id = $1 ORDER BY id DESC LIMIT $3
+		))
+	`, entry.UserID, int64(ttl.Seconds()), maxUndoEntries)
+	return err
+}
+
+// LatestUndo returns the user's most recent change within the TTL
+func (s *UndoStore) LatestUndo(userID string, ttl time.Duration) (*models.UndoEntry, error) {
+	query := `
+		SELECT id, user_id, org_id, product, workspace_id, tool, undo, created_at
+		FROM undo_log
+		WHERE user_id = $1 AND created_at >= NOW() - $2 * INTERVAL '1 second'
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	var entry models.UndoEntry
+	var undo []byte
+	err := s.db.QueryRow(query, userID, int64(ttl.Seconds())).Scan(&entry.ID, &entry.UserID, &entry.OrgID,
+		&entry.Product, &entry.WorkspaceID, &entry.Tool, &undo, &entry.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(undo, &entry.Undo); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// DeleteUndo forgets a change
+func (s *UndoStore) DeleteUndo(userID string, id int64) error {
+	_, err := s.db.Exec(`DELETE FROM undo_log WHERE user_id = $1 AND id = $2`, userID, id)
+	return err
+}
+
+// MemoryUndoStore keeps the undo log in memory; used with file-based credential storage
+type MemoryUndoStore struct {
+	entries map[string][]*models.UndoEntry // By user, oldest first
+	nextID  int64
+	mu      sync.Mutex
+}
+
+// NewMemoryUndoStore creates an in-memory undo store
+func NewMemoryUndoStore() *MemoryUndoStore {
+	return &MemoryUndoStore{entries: make(map[string][]*models.UndoEntry)}
+}
+
+// PushUndo records a change
+func (s *MemoryUndoStore) PushUndo(entry *models.UndoEntry, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	entry.ID = s.nextID
+	entry.CreatedAt = time.Now().UTC()
+	stored := *entry
+
+	var kept []*models.UndoEntry
+	for _, e := range s.entries[entry.UserID] {
+		if time.Since(e.CreatedAt) <= ttl {
+			kept = append(kept, e)
+		}
+	}
+	kept = append(kept, &stored)
+	if len(kept) > maxUndoEntries {
+		kept = kept[len(kept)-maxUndoEntries:]
+	}
+	s.entries[entry.UserID] = kept
+	return nil
+}
+
+// LatestUndo returns the user's most recent change within the TTL
+func (s *MemoryUndoStore) LatestUndo(userID string, ttl time.Duration) (*models.UndoEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.entries[userID]
+	if len(entries) == 0 || time.Since(entries[len(entries)-1].CreatedAt) > ttl {
+		return nil, ErrNotFound
+	}
+	latest := *entries[len(entries)-1]
+	return &latest, nil
+}
+
+// DeleteUndo forgets a change
+func (s *MemoryUndoStore) DeleteUndo(userID string, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.entries[userID]
+	for i, e := range entries {
+		if e.ID == id {
+			s.entries[userID] = append(entries[:i:i], entries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// NewUndoStoreFromEnv keeps the undo log next to the credentials: in PostgreSQL when the
+// credential store is database-backed, otherwise in memory
+func NewUndoStoreFromEnv(credStore CredentialStoreInterface) UndoStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewUndoStore(pg.db)
+	}
+	return NewMemoryUndoStore()
+}
+
+// UndoTTLFromEnv reads UNDO_TTL (a Go duration such as "24h")
+func UndoTTLFromEnv() time.Duration {
+	if v := os.Getenv("UNDO_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultUndoTTL
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// UsageStoreInterface records metered tool calls and Atlassian API volume per user/workspace/day
+type UsageStoreInterface interface {
+	RecordUsage(userID, workspaceID string, toolCalls, apiBytes int64) error
+	ListUsage(userID string, since time.Time) ([]models.UsageRecord, error)
+}
+
+// UsageStore keeps daily usage counters in PostgreSQL
+type UsageStore struct {
+	db *sql.DB
+}
+
+// NewUsageStore creates a usage store on an existing database connection.
+// The usage_daily table is created by the storage migrations.
+func NewUsageStore(db *sql.DB) *UsageStore {
+	return &UsageStore{db: db}
+}
+
+// RecordUsage adds to today's counters for a user/workspace
+func (s *UsageStore) RecordUsage(userID, workspaceID string, toolCalls, apiBytes int64) error {
+	query := `
+		INSERT INTO usage_daily (user_id, workspace_id, day, tool_calls, api_bytes)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, workspace_id, day)
+		DO UPDATE SET
+			tool_calls = usage_daily.tool_calls + EXCLUDED.tool_calls,
+			api_bytes = usage_daily.api_bytes + EXCLUDED.api_bytes
+	`
+
+	_, err := s.db.Exec(query, userID, workspaceID, usageDay(time.Now()), toolCalls, apiBytes)
+	return err
+}
+
+// ListUsage returns a user's daily usage since the given time, newest first
+func (s *UsageStore) ListUsage(userID string, since time.Time) ([]models.UsageRecord, error) {
+	query := `
+		SELECT user_id, workspace_id, day, tool_calls, api_bytes
+		FROM usage_daily
+		WHERE user_id = $1 AND day >= $2
+		ORDER BY day DESC, workspace_id
+	`
+
+	rows, err := s.db.Query(query, userID, usageDay(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []models.UsageRecord{}
+	for rows.Next() {
+		var rec models.UsageRecord
+		if err := rows.Scan(&rec.UserID, &rec.WorkspaceID, &rec.Day, &rec.ToolCalls, &rec.APIBytes); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+
+	return records, rows.Err()
+}
+
+// MemoryUsageStore keeps usage in memory; used with file-based credential storage
+// where there is no database. Counters reset when the process restarts.
+type MemoryUsageStore struct {
+	records map[string]*models.UsageRecord // Indexed by user/workspace/day
+	mu      sync.Mutex
+}
+
+// NewMemoryUsageStore creates an in-memory usage store
+func NewMemoryUsageStore() *MemoryUsageStore {
+	return &MemoryUsageStore{records: make(map[string]*models.UsageRecord)}
+}
+
+// RecordUsage adds to today's counters for a user/workspace
+func (s *MemoryUsageStore) RecordUsage(userID, workspaceID string, toolCalls, apiBytes int64) error {
+	day := usageDay(time.Now())
+	key := userID + "/" + workspaceID + "/" + day.Format("2006-01-02")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[key]
+	if !ok {
+		rec = &models.UsageRecord{UserID: userID, WorkspaceID: workspaceID, Day: day}
+		s.records[key] = rec
+	}
+	rec.ToolCalls += toolCalls
+	rec.APIBytes += apiBytes
+	return nil
+}
+
+// ListUsage returns a user's daily usage since the given time, newest first
+func (s *MemoryUsageStore) ListUsage(userID string, since time.Time) ([]models.UsageRecord, error) {
+	since = usageDay(since)
+
+	s.mu.Lock()
+	records := []models.UsageRecord{}
+	for _, rec := range s.records {
+		if rec.UserID == userID && !rec.Day.Before(since) {
+			records = append(records, *rec)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].Day.Equal(records[j].Day) {
+			return records[i].Day.After(records[j].Day)
+		}
+		return records[i].WorkspaceID < records[j].WorkspaceID
+	})
+	return records, nil
+}
+
+// NewUsageStoreFromEnv stores usage next to the credentials: in PostgreSQL when the
+// credential store is database-backed, otherwise in memory
+func NewUsageStoreFromEnv(credStore CredentialStoreInterface) (UsageStoreInterface, error) {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewUsageStore(pg.db), nil
+	}
+	return NewMemoryUsageStore(), nil
+}
+
+// usageDay truncates a time to its UTC day
+func usageDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/crypto"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// UserTokenStoreInterface stores the Atlassian OAuth tokens users granted
+type UserTokenStoreInterface interface {
+	SaveUserToken(token *models.AtlassianUserToken) error
+	GetUserToken(userID string) (*models.AtlassianUserToken, error)
+	DeleteUserToken(userID string) error
+}
+
+// UserTokenStore keeps users' Atlassian OAuth tokens in PostgreSQL, encrypted like API tokens
+type UserTokenStore struct {
+	db            *sql.DB
+	encryptionKey string
+}
+
+// NewUserTokenStore creates a user token store on an existing database connection.
+// The atlassian_user_tokens table is created by the storage migrations.
+func NewUserTokenStore(db *sql.DB, encryptionKey string) *UserTokenStore {
+	return &UserTokenStore{db: db, encryptionKey: encryptionKey}
+}
+
+// NewUserTokenStoreFromEnv returns a database-backed user token store, or nil with
+// file-based credential storage, where users cannot connect their Atlassian accounts
+func NewUserTokenStoreFromEnv(credStore CredentialStoreInterface) UserTokenStoreInterface {
+	if pg, ok := credStore.(*CredentialStore); ok {
+		return NewUserTokenStore(pg.db, pg.encryptionKey)
+	}
+	return nil
+}
+
+// SaveUserToken creates or replaces a user's tokens
+func (s *UserTokenStore) SaveUserToken(token *models.AtlassianUserToken) error {
+	query := `
+		INSERT INTO atlassian_user_tokens (user_id, access_token_encrypted, refresh_token_encrypted, scope, expires_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			access_token_encrypted = EXCLUDED.access_token_encrypted,
+			refresh_token_encrypted = EXCLUDED.refresh_token_encrypted,
+			scope = EXCLUDED.scope,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	accessToken, err := crypto.Encrypt(token.AccessToken, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt access token: %v", err)
+	}
+	refreshToken, err := crypto.Encrypt(token.RefreshToken, s.encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt refresh token: %v", err)
+	}
+	token.UpdatedAt = time.Now().UTC()
+
+	_, err = s.db.Exec(query, token.UserID, accessToken, refreshToken, token.Scope, token.ExpiresAt.UTC(), token.UpdatedAt)
+	return err
+}
+
+// GetUserToken returns a user's tokens, decrypted
+func (s *UserTokenStore) GetUserToken(userID string) (*models.AtlassianUserToken, error) {
+	query := `
+		SELECT user_id, access_token_encrypted, refresh_token_encrypted, scope, expires_at, updated_at
+		FROM atlassian_user_tokens
+		WHERE user_id = $1
+	`
+
+	var token models.AtlassianUserToken
+	var accessToken, refreshToken string
+	err := s.db.QueryRow(query, userID).Scan(&token.UserID, &accessToken, &refreshToken, &token.Scope, &token.ExpiresAt, &token.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken, err = crypto.Decrypt(accessToken, s.encryptionKey); err != nil {
+		return nil, err
+	}
+	if token.RefreshToken, err = crypto.Decrypt(refreshToken, s.encryptionKey); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// DeleteUserToken forgets a user's tokens
+func (s *UserTokenStore) DeleteUserToken(userID string) error {
+	result, err := s.db.Exec(`DELETE FROM atlassian_user_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return err
+}
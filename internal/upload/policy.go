@@ -0,0 +1,124 @@
+// Package upload enforces the operator's policy on files uploaded to Atlassian: a size
+// limit, an allowlist of MIME types and an optional ClamAV scan
+package upload
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+const (
+	// DefaultMaxBytes is the upload size limit when UPLOAD_MAX_BYTES is not set
+	DefaultMaxBytes = 10 * 1024 * 1024
+	// defaultScanTimeout bounds a ClamAV scan
+	defaultScanTimeout = 30 * time.Second
+)
+
+// Policy decides whether a file may be uploaded
+type Policy struct {
+	MaxBytes     int64
+	AllowedTypes []string // MIME types, or "image/*" for a whole type; empty allows any
+	ClamAVAddr   string   // clamd address (host:port, or a Unix socket path); empty skips scanning
+	ScanTimeout  time.Duration
+}
+
+// Violation is an upload the policy refuses. Code is one of the models.ErrCodeUpload* codes.
+type Violation struct {
+	Code    string
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return v.Message
+}
+
+// PolicyFromEnv reads the policy from UPLOAD_MAX_BYTES, UPLOAD_ALLOWED_TYPES (comma
+// separated) and CLAMAV_ADDR
+func PolicyFromEnv() Policy {
+	policy := Policy{
+		MaxBytes:    DefaultMaxBytes,
+		ClamAVAddr:  strings.TrimSpace(os.Getenv("CLAMAV_ADDR")),
+		ScanTimeout: defaultScanTimeout,
+	}
+	if v := os.Getenv("UPLOAD_MAX_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			policy.MaxBytes = n
+		} else {
+			slog.Warn("ignoring invalid UPLOAD_MAX_BYTES", "value", v, "default", DefaultMaxBytes)
+		}
+	}
+	for _, t := range strings.Split(os.Getenv("UPLOAD_ALLOWED_TYPES"), ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			policy.AllowedTypes = append(policy.AllowedTypes, t)
+		}
+	}
+	return policy
+}
+
+// Check returns the reason a file may not be uploaded, or nil. Files are scanned last, so
+// that oversized or unexpected files are refused without being sent to ClamAV. When the
+// scan cannot be completed the upload is refused.
+func (p Policy) Check(ctx context.Context, fileName, contentType string, data []byte) *Violation {
+	if p.MaxBytes > 0 && int64(len(data)) > p.MaxBytes {
+		return &Violation{
+			Code:    models.ErrCodeUploadTooLarge,
+			Message: fmt.Sprintf("%s is %d bytes; uploads are limited to %d bytes", fileName, len(data), p.MaxBytes),
+		}
+	}
+	if !p.typeAllowed(contentType) {
+		return &Violation{
+			Code:    models.ErrCodeUploadTypeNotAllowed,
+			Message: fmt.Sprintf("%s files may not be uploaded; allowed types: %s", contentType, strings.Join(p.AllowedTypes, ", ")),
+		}
+	}
+	if p.ClamAVAddr == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.ScanTimeout)
+	defer cancel()
+	signature, err := scanClamAV(ctx, p.ClamAVAddr, data)
+	if err != nil {
+		slog.Warn("upload virus scan failed", "file_name", fileName, "error", err)
+		return &Violation{
+			Code:    models.ErrCodeUploadScanFailed,
+			Message: fmt.Sprintf("%s could not be scanned for viruses: %v", fileName, err),
+		}
+	}
+	if signature != "" {
+		slog.Warn("upload refused by virus scan", "file_name", fileName, "signature", signature)
+		return &Violation{
+			Code:    models.ErrCodeUploadInfected,
+			Message: fmt.Sprintf("%s was refused by the virus scan (%s)", fileName, signature),
+		}
+	}
+	return nil
+}
+
+// typeAllowed reports whether a MIME type, ignoring its parameters, is on the allowlist
+func (p Policy) typeAllowed(contentType string) bool {
+	if len(p.AllowedTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range p.AllowedTypes {
+		if allowed == mediaType || allowed == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
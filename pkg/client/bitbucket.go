@@ -0,0 +1,73 @@
+package client
+
+import "context"
+
+// Arguments of the bitbucket_* tools. BitbucketWorkspace defaults to the Atlassian site
+// name of the workspace. Pull requests carry a jira_issue_keys list.
+
+// BitbucketListRepositoriesRequest holds the arguments of bitbucket_list_repositories
+type BitbucketListRepositoriesRequest struct {
+	WorkspaceID        string `json:"workspace_id"`
+	BitbucketWorkspace string `json:"bitbucket_workspace,omitempty"`
+	Query              string `json:"query,omitempty"`
+	Limit              int    `json:"limit,omitempty"`
+}
+
+// BitbucketListPullRequestsRequest holds the arguments of bitbucket_list_pull_requests
+type BitbucketListPullRequestsRequest struct {
+	WorkspaceID        string `json:"workspace_id"`
+	BitbucketWorkspace string `json:"bitbucket_workspace,omitempty"`
+	RepoSlug           string `json:"repo_slug"`
+	State              string `json:"state,omitempty"`
+	Limit              int    `json:"limit,omitempty"`
+}
+
+// BitbucketGetPullRequestRequest holds the arguments of bitbucket_get_pull_request
+type BitbucketGetPullRequestRequest struct {
+	WorkspaceID        string `json:"workspace_id"`
+	BitbucketWorkspace string `json:"bitbucket_workspace,omitempty"`
+	RepoSlug           string `json:"repo_slug"`
+	PullRequestID      int    `json:"pull_request_id"`
+}
+
+// BitbucketGetPullRequestCommentsRequest holds the arguments of bitbucket_get_pull_request_comments
+type BitbucketGetPullRequestCommentsRequest struct {
+	WorkspaceID        string `json:"workspace_id"`
+	BitbucketWorkspace string `json:"bitbucket_workspace,omitempty"`
+	RepoSlug           string `json:"repo_slug"`
+	PullRequestID      int    `json:"pull_request_id"`
+	Limit              int    `json:"limit,omitempty"`
+}
+
+// BitbucketListPipelinesRequest holds the arguments of bitbucket_list_pipelines
+type BitbucketListPipelinesRequest struct {
+	WorkspaceID        string `json:"workspace_id"`
+	BitbucketWorkspace string `json:"bitbucket_workspace,omitempty"`
+	RepoSlug           string `json:"repo_slug"`
+	Limit              int    `json:"limit,omitempty"`
+}
+
+// BitbucketListRepositories calls bitbucket_list_repositories
+func (c *Client) BitbucketListRepositories(ctx context.Context, req BitbucketListRepositoriesRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "bitbucket_list_repositories", req)
+}
+
+// BitbucketListPullRequests calls bitbucket_list_pull_requests
+func (c *Client) BitbucketListPullRequests(ctx context.Context, req BitbucketListPullRequestsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "bitbucket_list_pull_requests", req)
+}
+
+// BitbucketGetPullRequest calls bitbucket_get_pull_request
+func (c *Client) BitbucketGetPullRequest(ctx context.Context, req BitbucketGetPullRequestRequest) (Object, error) {
+	return call[Object](ctx, c, "bitbucket_get_pull_request", req)
+}
+
+// BitbucketGetPullRequestComments calls bitbucket_get_pull_request_comments
+func (c *Client) BitbucketGetPullRequestComments(ctx context.Context, req BitbucketGetPullRequestCommentsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "bitbucket_get_pull_request_comments", req)
+}
+
+// BitbucketListPipelines calls bitbucket_list_pipelines
+func (c *Client) BitbucketListPipelines(ctx context.Context, req BitbucketListPipelinesRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "bitbucket_list_pipelines", req)
+}
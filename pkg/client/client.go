@@ -0,0 +1,174 @@
+// Package client calls the MCP server's REST tool API (/api/tools/{tool_name}) from Go.
+//
+//	c := client.New("https://mcp.example.com", client.StaticToken(os.Getenv("TRILIX_API_KEY")))
+//	issue, err := c.JiraGetIssue(ctx, client.JiraGetIssueRequest{WorkspaceID: "acme", IssueKey: "OPS-7"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client calls tools on an MCP server. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	tokens     TokenSource
+	httpClient *http.Client
+	userID     string // Acts on behalf of this user (service tokens only)
+}
+
+// Error is a tool call the server rejected or that failed
+type Error struct {
+	StatusCode int    // HTTP status returned by the server
+	Message    string // Error text returned by the server
+	RequestID  string // X-Request-ID of the call, for correlating with server logs
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("mcp server returned %d: %s", e.StatusCode, e.Message)
+}
+
+// TruncatedError is returned when a result exceeded the server's response size limit.
+// Partial holds the text that was returned.
+type TruncatedError struct {
+	Partial string
+	Warning string
+}
+
+func (e *TruncatedError) Error() string {
+	return e.Warning
+}
+
+// New creates a client for the server at baseURL (e.g. "https://mcp.example.com")
+// that authenticates with tokens
+func New(baseURL string, tokens TokenSource) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		tokens:     tokens,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client (60s timeout)
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	return c
+}
+
+// WithUserID makes every call act on behalf of userID. The token must be a service
+// token allowed to impersonate that user.
+func (c *Client) WithUserID(userID string) *Client {
+	c.userID = userID
+	return c
+}
+
+// CallTool calls any tool by name and returns its raw JSON result. args is encoded
+// as the tool's arguments; a nil args sends none.
+func (c *Client) CallTool(ctx context.Context, tool string, args interface{}) (json.RawMessage, error) {
+	body := []byte("{}")
+	if args != nil {
+		var err error
+		if body, err = json.Marshal(args); err != nil {
+			return nil, fmt.Errorf("encode arguments: %w", err)
+		}
+	}
+
+	data, err := c.send(ctx, http.MethodPost, c.endpoint("/api/tools/"+url.PathEscape(tool)), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var truncated struct {
+		Result  *string `json:"result"`
+		Warning string  `json:"warning"`
+	}
+	if json.Unmarshal(data, &truncated) == nil && truncated.Result != nil && truncated.Warning != "" {
+		return nil, &TruncatedError{Partial: *truncated.Result, Warning: truncated.Warning}
+	}
+	return data, nil
+}
+
+// endpoint builds the URL of an API path, adding the impersonated user if any
+func (c *Client) endpoint(path string) string {
+	if c.userID == "" {
+		return c.baseURL + path
+	}
+	return c.baseURL + path + "?user_id=" + url.QueryEscape(c.userID)
+}
+
+// send makes an authenticated request and returns the body of a 2xx response
+func (c *Client) send(ctx context.Context, method, endpoint string, body []byte) ([]byte, error) {
+	resp, err := c.do(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &Error{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(data)),
+			RequestID:  resp.Header.Get("X-Request-ID"),
+		}
+	}
+	return data, nil
+}
+
+// do sends a request, fetching a fresh token and retrying once if the server
+// rejects the cached one
+func (c *Client) do(ctx context.Context, method, endpoint string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get token: %w", err)
+		}
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		refresher, ok := c.tokens.(interface{ Invalidate() })
+		if resp.StatusCode != http.StatusUnauthorized || !ok || attempt > 0 {
+			return resp, nil
+		}
+		resp.Body.Close()
+		refresher.Invalidate()
+	}
+}
+
+// call calls a tool and decodes its result into T
+func call[T any](ctx context.Context, c *Client, tool string, args interface{}) (T, error) {
+	var result T
+	data, err := c.CallTool(ctx, tool, args)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("decode %s result: %w", tool, err)
+	}
+	return result, nil
+}
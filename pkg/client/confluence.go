@@ -0,0 +1,317 @@
+package client
+
+import "context"
+
+// Arguments of the confluence_* tools. Page bodies use the Confluence storage format.
+
+// ConfluenceGetPageRequest holds the arguments of confluence_get_page
+type ConfluenceGetPageRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	PageID      string `json:"page_id"`
+}
+
+// ConfluenceSearchRequest holds the arguments of confluence_search
+type ConfluenceSearchRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Query       string `json:"query"` // CQL
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// ConfluenceSearchAllWorkspacesRequest holds the arguments of confluence_search_all_workspaces
+type ConfluenceSearchAllWorkspacesRequest struct {
+	Query string `json:"query"`           // CQL
+	Limit int    `json:"limit,omitempty"` // Per workspace
+}
+
+// ConfluenceCreatePageRequest holds the arguments of confluence_create_page
+type ConfluenceCreatePageRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	SpaceKey       string `json:"space_key"`
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	ParentID       string `json:"parent_id,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceCopyPageRequest holds the arguments of confluence_copy_page
+type ConfluenceCopyPageRequest struct {
+	SrcWorkspace   string `json:"src_workspace"`
+	DstWorkspace   string `json:"dst_workspace"`
+	SrcPageID      string `json:"src_page_id"`
+	DstSpaceKey    string `json:"dst_space_key"`
+	DstParentID    string `json:"dst_parent_id,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceListSpacesRequest holds the arguments of confluence_list_spaces
+type ConfluenceListSpacesRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// ConfluenceUpdatePageRequest holds the arguments of confluence_update_page
+type ConfluenceUpdatePageRequest struct {
+	WorkspaceID     string `json:"workspace_id"`
+	PageID          string `json:"page_id"`
+	Body            string `json:"body"`
+	Title           string `json:"title,omitempty"`            // Keeps the current title when empty
+	Mode            string `json:"mode,omitempty"`             // "replace" (the default), "append_section" or "replace_section"
+	Section         string `json:"section,omitempty"`          // Heading of the section a section mode changes
+	ExpectedVersion int    `json:"expected_version,omitempty"` // Fails with CONFLICT if the page is at another version
+	IdempotencyKey  string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceAppendToPageRequest holds the arguments of confluence_append_to_page
+type ConfluenceAppendToPageRequest struct {
+	WorkspaceID     string `json:"workspace_id"`
+	PageID          string `json:"page_id"`
+	Heading         string `json:"heading,omitempty"` // Appends to the end of the page when empty
+	Content         string `json:"content"`
+	ExpectedVersion int    `json:"expected_version,omitempty"`
+	IdempotencyKey  string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceReplaceSectionRequest holds the arguments of confluence_replace_section
+type ConfluenceReplaceSectionRequest struct {
+	WorkspaceID     string `json:"workspace_id"`
+	PageID          string `json:"page_id"`
+	Heading         string `json:"heading"`
+	Content         string `json:"content"`
+	ExpectedVersion int    `json:"expected_version,omitempty"`
+	IdempotencyKey  string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceDeletePageRequest holds the arguments of confluence_delete_page
+type ConfluenceDeletePageRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	PageID         string `json:"page_id"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceGetPageChildrenRequest holds the arguments of confluence_get_page_children
+type ConfluenceGetPageChildrenRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	PageID      string `json:"page_id"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// ConfluenceAddCommentRequest holds the arguments of confluence_add_comment
+type ConfluenceAddCommentRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	PageID         string `json:"page_id"`
+	Body           string `json:"body"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceGetCommentsRequest holds the arguments of confluence_get_comments
+type ConfluenceGetCommentsRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	PageID      string `json:"page_id"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// ConfluenceAddLabelRequest holds the arguments of confluence_add_label
+type ConfluenceAddLabelRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	PageID         string `json:"page_id"`
+	Label          string `json:"label"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceGetLabelsRequest holds the arguments of confluence_get_labels
+type ConfluenceGetLabelsRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	PageID      string `json:"page_id"`
+}
+
+// ConfluenceSearchUserRequest holds the arguments of confluence_search_user
+type ConfluenceSearchUserRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Query       string `json:"query"`
+}
+
+// ConfluenceGetSpaceRequest holds the arguments of confluence_get_space
+type ConfluenceGetSpaceRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	SpaceKey    string `json:"space_key"`
+}
+
+// ConfluenceGetAttachmentsRequest holds the arguments of confluence_get_attachments
+type ConfluenceGetAttachmentsRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	PageID      string `json:"page_id"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// ConfluenceInsertDiagramRequest holds the arguments of confluence_insert_diagram
+type ConfluenceInsertDiagramRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	PageID         string `json:"page_id"`
+	Source         string `json:"source"`
+	Language       string `json:"language"`            // mermaid or plantuml
+	Format         string `json:"format,omitempty"`    // svg (default) or png
+	FileName       string `json:"file_name,omitempty"` // Derived from the source when empty
+	Position       string `json:"position,omitempty"`  // start or end (default)
+	Alt            string `json:"alt,omitempty"`
+	Width          int    `json:"width,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceImportURLRequest holds the arguments of confluence_import_url
+type ConfluenceImportURLRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	SourceURL      string `json:"source_url"`
+	SpaceKey       string `json:"space_key"`
+	Title          string `json:"title,omitempty"` // The source's title when empty
+	ParentID       string `json:"parent_id,omitempty"`
+	Format         string `json:"format,omitempty"`          // auto (default), html or markdown
+	DownloadImages *bool  `json:"download_images,omitempty"` // Default true
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// ConfluenceContentAuditRequest holds the arguments of confluence_content_audit
+type ConfluenceContentAuditRequest struct {
+	WorkspaceID     string  `json:"workspace_id"`
+	SpaceKey        string  `json:"space_key"`
+	StaleMonths     int     `json:"stale_months,omitempty"`     // Default 12
+	TitleSimilarity float64 `json:"title_similarity,omitempty"` // Default 0.85
+	Limit           int     `json:"limit,omitempty"`
+}
+
+// ConfluenceGetPageLinksRequest holds the arguments of confluence_get_page_links
+type ConfluenceGetPageLinksRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	PageID      string `json:"page_id"`
+	Direction   string `json:"direction,omitempty"` // outgoing, incoming or both (default)
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// ConfluenceGetShareLinkRequest holds the arguments of confluence_get_share_link
+type ConfluenceGetShareLinkRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	PageID      string `json:"page_id"`
+}
+
+// ConfluenceGetPage calls confluence_get_page
+func (c *Client) ConfluenceGetPage(ctx context.Context, req ConfluenceGetPageRequest) (*ConfluencePage, error) {
+	return call[*ConfluencePage](ctx, c, "confluence_get_page", req)
+}
+
+// ConfluenceSearch calls confluence_search
+func (c *Client) ConfluenceSearch(ctx context.Context, req ConfluenceSearchRequest) (*ConfluenceSearchResults, error) {
+	return call[*ConfluenceSearchResults](ctx, c, "confluence_search", req)
+}
+
+// ConfluenceSearchSummaries calls confluence_search with summarize, returning one line per page
+func (c *Client) ConfluenceSearchSummaries(ctx context.Context, req ConfluenceSearchRequest) (*ConfluencePageSummaries, error) {
+	return call[*ConfluencePageSummaries](ctx, c, "confluence_search", struct {
+		ConfluenceSearchRequest
+		Summarize bool `json:"summarize"`
+	}{req, true})
+}
+
+// ConfluenceSearchAllWorkspaces calls confluence_search_all_workspaces and returns the
+// merged results with a summary per workspace
+func (c *Client) ConfluenceSearchAllWorkspaces(ctx context.Context, req ConfluenceSearchAllWorkspacesRequest) (Object, error) {
+	return call[Object](ctx, c, "confluence_search_all_workspaces", req)
+}
+
+// ConfluenceCreatePage calls confluence_create_page
+func (c *Client) ConfluenceCreatePage(ctx context.Context, req ConfluenceCreatePageRequest) (*ConfluencePage, error) {
+	return call[*ConfluencePage](ctx, c, "confluence_create_page", req)
+}
+
+// ConfluenceCopyPage calls confluence_copy_page and returns the new page
+func (c *Client) ConfluenceCopyPage(ctx context.Context, req ConfluenceCopyPageRequest) (*ConfluencePage, error) {
+	return call[*ConfluencePage](ctx, c, "confluence_copy_page", req)
+}
+
+// ConfluenceListSpaces calls confluence_list_spaces
+func (c *Client) ConfluenceListSpaces(ctx context.Context, req ConfluenceListSpacesRequest) ([]ConfluenceSpace, error) {
+	return call[[]ConfluenceSpace](ctx, c, "confluence_list_spaces", req)
+}
+
+// ConfluenceUpdatePage calls confluence_update_page
+func (c *Client) ConfluenceUpdatePage(ctx context.Context, req ConfluenceUpdatePageRequest) (*ConfluencePage, error) {
+	return call[*ConfluencePage](ctx, c, "confluence_update_page", req)
+}
+
+// ConfluenceAppendToPage calls confluence_append_to_page
+func (c *Client) ConfluenceAppendToPage(ctx context.Context, req ConfluenceAppendToPageRequest) (*ConfluencePage, error) {
+	return call[*ConfluencePage](ctx, c, "confluence_append_to_page", req)
+}
+
+// ConfluenceReplaceSection calls confluence_replace_section
+func (c *Client) ConfluenceReplaceSection(ctx context.Context, req ConfluenceReplaceSectionRequest) (*ConfluencePage, error) {
+	return call[*ConfluencePage](ctx, c, "confluence_replace_section", req)
+}
+
+// ConfluenceDeletePage calls confluence_delete_page
+func (c *Client) ConfluenceDeletePage(ctx context.Context, req ConfluenceDeletePageRequest) (ActionResult, error) {
+	return call[ActionResult](ctx, c, "confluence_delete_page", req)
+}
+
+// ConfluenceGetPageChildren calls confluence_get_page_children
+func (c *Client) ConfluenceGetPageChildren(ctx context.Context, req ConfluenceGetPageChildrenRequest) ([]ConfluencePage, error) {
+	return call[[]ConfluencePage](ctx, c, "confluence_get_page_children", req)
+}
+
+// ConfluenceAddComment calls confluence_add_comment
+func (c *Client) ConfluenceAddComment(ctx context.Context, req ConfluenceAddCommentRequest) (Object, error) {
+	return call[Object](ctx, c, "confluence_add_comment", req)
+}
+
+// ConfluenceGetComments calls confluence_get_comments
+func (c *Client) ConfluenceGetComments(ctx context.Context, req ConfluenceGetCommentsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "confluence_get_comments", req)
+}
+
+// ConfluenceAddLabel calls confluence_add_label
+func (c *Client) ConfluenceAddLabel(ctx context.Context, req ConfluenceAddLabelRequest) (Object, error) {
+	return call[Object](ctx, c, "confluence_add_label", req)
+}
+
+// ConfluenceGetLabels calls confluence_get_labels
+func (c *Client) ConfluenceGetLabels(ctx context.Context, req ConfluenceGetLabelsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "confluence_get_labels", req)
+}
+
+// ConfluenceSearchUser calls confluence_search_user
+func (c *Client) ConfluenceSearchUser(ctx context.Context, req ConfluenceSearchUserRequest) ([]ConfluenceUser, error) {
+	return call[[]ConfluenceUser](ctx, c, "confluence_search_user", req)
+}
+
+// ConfluenceGetSpace calls confluence_get_space
+func (c *Client) ConfluenceGetSpace(ctx context.Context, req ConfluenceGetSpaceRequest) (*ConfluenceSpace, error) {
+	return call[*ConfluenceSpace](ctx, c, "confluence_get_space", req)
+}
+
+// ConfluenceGetAttachments calls confluence_get_attachments
+func (c *Client) ConfluenceGetAttachments(ctx context.Context, req ConfluenceGetAttachmentsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "confluence_get_attachments", req)
+}
+
+// ConfluenceInsertDiagram calls confluence_insert_diagram
+func (c *Client) ConfluenceInsertDiagram(ctx context.Context, req ConfluenceInsertDiagramRequest) (Object, error) {
+	return call[Object](ctx, c, "confluence_insert_diagram", req)
+}
+
+// ConfluenceImportURL calls confluence_import_url
+func (c *Client) ConfluenceImportURL(ctx context.Context, req ConfluenceImportURLRequest) (Object, error) {
+	return call[Object](ctx, c, "confluence_import_url", req)
+}
+
+// ConfluenceContentAudit calls confluence_content_audit
+func (c *Client) ConfluenceContentAudit(ctx context.Context, req ConfluenceContentAuditRequest) (Object, error) {
+	return call[Object](ctx, c, "confluence_content_audit", req)
+}
+
+// ConfluenceGetPageLinks calls confluence_get_page_links
+func (c *Client) ConfluenceGetPageLinks(ctx context.Context, req ConfluenceGetPageLinksRequest) (Object, error) {
+	return call[Object](ctx, c, "confluence_get_page_links", req)
+}
+
+// ConfluenceGetShareLink calls confluence_get_share_link
+func (c *Client) ConfluenceGetShareLink(ctx context.Context, req ConfluenceGetShareLinkRequest) (Object, error) {
+	return call[Object](ctx, c, "confluence_get_share_link", req)
+}
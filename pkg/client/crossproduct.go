@@ -0,0 +1,119 @@
+package client
+
+import "context"
+
+// Arguments and results of the tools that use both Jira and Confluence
+
+// SearchAtlassianRequest holds the arguments of search_atlassian
+type SearchAtlassianRequest struct {
+	WorkspaceID string   `json:"workspace_id"`
+	Query       string   `json:"query"`              // Free text, not JQL or CQL
+	Products    []string `json:"products,omitempty"` // "jira" and/or "confluence"; defaults to both
+	Limit       int      `json:"limit,omitempty"`    // Per product
+}
+
+// SearchHit is one Jira issue or Confluence page found by search_atlassian
+type SearchHit struct {
+	Product string  `json:"product"` // "jira" or "confluence"
+	Type    string  `json:"type"`    // The lowercased issue type, or "page" / "blogpost"
+	Key     string  `json:"key,omitempty"`
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	URL     string  `json:"url,omitempty"`
+	Status  string  `json:"status,omitempty"`
+	Space   string  `json:"space,omitempty"`
+	Updated string  `json:"updated,omitempty"`
+	Score   float64 `json:"score"`
+}
+
+// SearchAtlassianResults is returned by search_atlassian, best match first. Errors holds
+// the products that failed while others succeeded.
+type SearchAtlassianResults struct {
+	Query   string            `json:"query"`
+	Results []SearchHit       `json:"results"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// SearchAtlassian calls search_atlassian
+func (c *Client) SearchAtlassian(ctx context.Context, req SearchAtlassianRequest) (SearchAtlassianResults, error) {
+	return call[SearchAtlassianResults](ctx, c, "search_atlassian", req)
+}
+
+// GenerateReleaseNotesRequest holds the arguments of generate_release_notes. Give Version
+// (with ProjectKey) or JQL. Without TargetSpace the notes are returned but not published.
+type GenerateReleaseNotesRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Version     string `json:"version,omitempty"`
+	ProjectKey  string `json:"project_key,omitempty"`
+	JQL         string `json:"jql,omitempty"`
+	TargetSpace string `json:"target_space,omitempty"`
+	ParentPage  string `json:"parent_page,omitempty"` // Page ID
+	Title       string `json:"title,omitempty"`
+	Format      string `json:"format,omitempty"` // "storage" (default) or "markdown"
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// ReleaseNotes is returned by generate_release_notes. Page and Action ("created" or
+// "updated") are set when the notes were published.
+type ReleaseNotes struct {
+	Title      string `json:"title"`
+	JQL        string `json:"jql"`
+	IssueCount int    `json:"issue_count"`
+	Format     string `json:"format"`
+	Content    string `json:"content"`
+	Page       Object `json:"page,omitempty"`
+	Action     string `json:"action,omitempty"`
+}
+
+// GenerateReleaseNotes calls generate_release_notes
+func (c *Client) GenerateReleaseNotes(ctx context.Context, req GenerateReleaseNotesRequest) (ReleaseNotes, error) {
+	return call[ReleaseNotes](ctx, c, "generate_release_notes", req)
+}
+
+// CreateIssuesFromPageRequest holds the arguments of create_issues_from_page
+type CreateIssuesFromPageRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	PageID      string `json:"page_id"`
+	ProjectKey  string `json:"project_key"`
+	IssueType   string `json:"issue_type,omitempty"` // Defaults to "Task"
+	DryRun      bool   `json:"dry_run,omitempty"`
+}
+
+// PageTask is an open task found on a Confluence page, with its new issue once created
+type PageTask struct {
+	Summary    string `json:"summary"`
+	AssigneeID string `json:"assignee_account_id,omitempty"`
+	DueDate    string `json:"due_date,omitempty"`
+	IssueKey   string `json:"issue_key,omitempty"`
+	IssueURL   string `json:"issue_url,omitempty"`
+}
+
+// PageIssues is returned by create_issues_from_page. Created and PageVersion are empty
+// for dry runs and pages without open tasks.
+type PageIssues struct {
+	PageID      string     `json:"page_id"`
+	PageTitle   string     `json:"page_title"`
+	Tasks       []PageTask `json:"tasks"`
+	Created     []string   `json:"created,omitempty"`
+	PageVersion int        `json:"page_version,omitempty"`
+}
+
+// CreateIssuesFromPage calls create_issues_from_page
+func (c *Client) CreateIssuesFromPage(ctx context.Context, req CreateIssuesFromPageRequest) (PageIssues, error) {
+	return call[PageIssues](ctx, c, "create_issues_from_page", req)
+}
+
+// SyncLabelsRequest holds the arguments of sync_labels
+type SyncLabelsRequest struct {
+	Product           string `json:"product"` // jira or confluence
+	SourceWorkspaceID string `json:"source_workspace_id"`
+	TargetWorkspaceID string `json:"target_workspace_id"`
+	Scope             string `json:"scope"`                  // Project or space key
+	TargetScope       string `json:"target_scope,omitempty"` // Defaults to Scope
+	DryRun            *bool  `json:"dry_run,omitempty"`      // Defaults to true
+}
+
+// SyncLabels calls sync_labels
+func (c *Client) SyncLabels(ctx context.Context, req SyncLabelsRequest) (Object, error) {
+	return call[Object](ctx, c, "sync_labels", req)
+}
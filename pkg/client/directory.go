@@ -0,0 +1,33 @@
+package client
+
+import "context"
+
+// Arguments of the admin_* directory tools. Without a WorkspaceID they query every
+// workspace, and each result is a workspace entry (workspace_id, workspace_name, site)
+// holding that workspace's results or error.
+
+// AdminListUsersRequest holds the arguments of admin_list_users
+type AdminListUsersRequest struct {
+	WorkspaceID   string `json:"workspace_id,omitempty"`
+	Query         string `json:"query,omitempty"`
+	IncludeAccess *bool  `json:"include_access,omitempty"` // Defaults to true
+	Limit         int    `json:"limit,omitempty"`
+}
+
+// AdminListGroupsRequest holds the arguments of admin_list_groups
+type AdminListGroupsRequest struct {
+	WorkspaceID    string `json:"workspace_id,omitempty"`
+	Query          string `json:"query,omitempty"`
+	IncludeMembers bool   `json:"include_members,omitempty"`
+	Limit          int    `json:"limit,omitempty"`
+}
+
+// AdminListUsers calls admin_list_users
+func (c *Client) AdminListUsers(ctx context.Context, req AdminListUsersRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "admin_list_users", req)
+}
+
+// AdminListGroups calls admin_list_groups
+func (c *Client) AdminListGroups(ctx context.Context, req AdminListGroupsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "admin_list_groups", req)
+}
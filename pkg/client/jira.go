@@ -0,0 +1,621 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// Arguments of the jira_* tools. IdempotencyKey makes retries of a mutating call safe
+// (see "Idempotency Keys" in docs/API.md).
+
+// JiraListProjectsRequest holds the arguments of jira_list_projects
+type JiraListProjectsRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// JiraListIssuesRequest holds the arguments of jira_list_issues
+type JiraListIssuesRequest struct {
+	WorkspaceID string   `json:"workspace_id"`
+	JQL         string   `json:"jql"`
+	Limit       int      `json:"limit,omitempty"`
+	Fields      []string `json:"fields,omitempty"`
+	Preset      string   `json:"preset,omitempty"` // Field preset whose fields are added to Fields
+}
+
+// JiraListIssuesPageRequest holds the arguments of jira_list_issues@v2
+type JiraListIssuesPageRequest struct {
+	WorkspaceID string   `json:"workspace_id"`
+	JQL         string   `json:"jql"`
+	Limit       int      `json:"limit,omitempty"`  // Issues per page
+	Cursor      string   `json:"cursor,omitempty"` // NextCursor of the previous page
+	Fields      []string `json:"fields,omitempty"`
+	Preset      string   `json:"preset,omitempty"`
+}
+
+// JiraIssuePage is a page of jira_list_issues@v2 results
+type JiraIssuePage struct {
+	Issues     []JiraIssue `json:"issues"`
+	NextCursor string      `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// JiraGetIssueRequest holds the arguments of jira_get_issue
+type JiraGetIssueRequest struct {
+	WorkspaceID string   `json:"workspace_id"`
+	IssueKey    string   `json:"issue_key"`
+	Expand      []string `json:"expand,omitempty"`
+}
+
+// JiraHydrateIssuesRequest holds the arguments of jira_hydrate_issues
+type JiraHydrateIssuesRequest struct {
+	WorkspaceID string   `json:"workspace_id"`
+	IssueKeys   []string `json:"issue_keys"` // Up to 50
+	Expand      []string `json:"expand,omitempty"`
+}
+
+// JiraHydratedIssues is the result of jira_hydrate_issues
+type JiraHydratedIssues struct {
+	Issues []JiraIssue `json:"issues"`
+	Errors []struct {
+		IssueKey string `json:"issue_key"`
+		Error    string `json:"error"`
+	} `json:"errors"` // Issues that could not be fetched
+}
+
+// JiraCreateIssueRequest holds the arguments of jira_create_issue
+type JiraCreateIssueRequest struct {
+	WorkspaceID      string                 `json:"workspace_id"`
+	ProjectKey       string                 `json:"project_key"`
+	IssueType        string                 `json:"issue_type"`
+	Summary          string                 `json:"summary"`
+	Description      string                 `json:"description,omitempty"`
+	AdditionalFields map[string]interface{} `json:"additional_fields,omitempty"`
+	IdempotencyKey   string                 `json:"idempotency_key,omitempty"`
+}
+
+// JiraCreateIssueFromTemplateRequest holds the arguments of jira_create_issue_from_template
+type JiraCreateIssueFromTemplateRequest struct {
+	WorkspaceID    string                 `json:"workspace_id"`
+	TemplateID     string                 `json:"template_id"` // ID or name
+	Variables      map[string]interface{} `json:"variables,omitempty"`
+	ProjectKey     string                 `json:"project_key,omitempty"` // Overrides the template's project
+	DryRun         bool                   `json:"dry_run,omitempty"`
+	IdempotencyKey string                 `json:"idempotency_key,omitempty"`
+}
+
+// JiraUpdateIssueRequest holds the arguments of jira_update_issue
+type JiraUpdateIssueRequest struct {
+	WorkspaceID    string                 `json:"workspace_id"`
+	IssueKey       string                 `json:"issue_key"`
+	Fields         map[string]interface{} `json:"fields"`
+	IdempotencyKey string                 `json:"idempotency_key,omitempty"`
+}
+
+// JiraAddCommentRequest holds the arguments of jira_add_comment
+type JiraAddCommentRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	IssueKey       string `json:"issue_key"`
+	Body           string `json:"body"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraAppendToDescriptionRequest holds the arguments of jira_append_to_description.
+// Give the content as plain text or as ADF.
+type JiraAppendToDescriptionRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	IssueKey       string `json:"issue_key"`
+	Heading        string `json:"heading,omitempty"` // Appends to the end of the description when empty
+	Content        string `json:"content,omitempty"`
+	ADF            Object `json:"adf,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraUpdateDescriptionSectionRequest holds the arguments of jira_update_description_section.
+// Give the content as plain text or as ADF; neither clears the section.
+type JiraUpdateDescriptionSectionRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	IssueKey       string `json:"issue_key"`
+	Heading        string `json:"heading"`
+	Content        string `json:"content,omitempty"`
+	ADF            Object `json:"adf,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraTransitionIssueRequest holds the arguments of jira_transition_issue
+type JiraTransitionIssueRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	IssueKey       string `json:"issue_key"`
+	TransitionID   string `json:"transition_id"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraBulkTransitionRequest holds the arguments of jira_bulk_transition. The tool always
+// needs confirmation: call it without ConfirmationToken for a preview and a token, then
+// again with the same arguments and the token.
+type JiraBulkTransitionRequest struct {
+	WorkspaceID       string `json:"workspace_id"`
+	JQL               string `json:"jql"`
+	TransitionName    string `json:"transition_name"`
+	Comment           string `json:"comment,omitempty"`
+	MaxIssues         int    `json:"max_issues,omitempty"`
+	DryRun            bool   `json:"dry_run,omitempty"`
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+	IdempotencyKey    string `json:"idempotency_key,omitempty"`
+}
+
+// JiraImportIssuesRequest holds the arguments of jira_import_issues
+type JiraImportIssuesRequest struct {
+	WorkspaceID    string            `json:"workspace_id"`
+	ProjectKey     string            `json:"project_key"`
+	Format         string            `json:"format,omitempty"`  // csv or json; detected when empty
+	Data           string            `json:"data"`              // CSV text or a JSON array of objects
+	Mapping        map[string]string `json:"mapping,omitempty"` // Column to Jira field ID or name; "" ignores the column
+	IssueType      string            `json:"issue_type,omitempty"`
+	DryRun         bool              `json:"dry_run,omitempty"`
+	IdempotencyKey string            `json:"idempotency_key,omitempty"`
+}
+
+// JiraGetAgileBoardsRequest holds the arguments of jira_get_agile_boards
+type JiraGetAgileBoardsRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	ProjectKey  string `json:"project_key,omitempty"`
+	Type        string `json:"type,omitempty"` // "scrum" or "kanban"
+}
+
+// JiraGetBoardIssuesRequest holds the arguments of jira_get_board_issues
+type JiraGetBoardIssuesRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	BoardID     string `json:"board_id"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// JiraGetSprintsFromBoardRequest holds the arguments of jira_get_sprints_from_board
+type JiraGetSprintsFromBoardRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	BoardID     string `json:"board_id"`
+	State       string `json:"state,omitempty"` // "active", "future" or "closed"
+}
+
+// JiraGetSprintIssuesRequest holds the arguments of jira_get_sprint_issues
+type JiraGetSprintIssuesRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	SprintID    string `json:"sprint_id"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// JiraCreateSprintRequest holds the arguments of jira_create_sprint
+type JiraCreateSprintRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	BoardID        string `json:"board_id"`
+	Name           string `json:"name"`
+	StartDate      string `json:"start_date,omitempty"`
+	EndDate        string `json:"end_date,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraUpdateSprintRequest holds the arguments of jira_update_sprint
+type JiraUpdateSprintRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	SprintID       string `json:"sprint_id"`
+	Name           string `json:"name,omitempty"`
+	State          string `json:"state,omitempty"`
+	StartDate      string `json:"start_date,omitempty"`
+	EndDate        string `json:"end_date,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraGetWorklogRequest holds the arguments of jira_get_worklog
+type JiraGetWorklogRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	IssueKey    string `json:"issue_key"`
+}
+
+// JiraAddWorklogRequest holds the arguments of jira_add_worklog
+type JiraAddWorklogRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	IssueKey       string `json:"issue_key"`
+	TimeSpent      string `json:"time_spent"` // e.g. "2h 30m"
+	Comment        string `json:"comment,omitempty"`
+	Started        string `json:"started,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraGetTransitionsRequest holds the arguments of jira_get_transitions
+type JiraGetTransitionsRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	IssueKey    string `json:"issue_key"`
+}
+
+// JiraDeleteIssueRequest holds the arguments of jira_delete_issue
+type JiraDeleteIssueRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	IssueKey       string `json:"issue_key"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraGetProjectIssuesRequest holds the arguments of jira_get_project_issues
+type JiraGetProjectIssuesRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	ProjectKey  string `json:"project_key"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// JiraGetProjectVersionsRequest holds the arguments of jira_get_project_versions
+type JiraGetProjectVersionsRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	ProjectKey  string `json:"project_key"`
+}
+
+// JiraSearchUsersRequest holds the arguments of jira_search_users
+type JiraSearchUsersRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Query       string `json:"query"`
+}
+
+// JiraGetUserProfileRequest holds the arguments of jira_get_user_profile
+type JiraGetUserProfileRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	AccountID   string `json:"account_id"`
+}
+
+// JiraSearchFieldsRequest holds the arguments of jira_search_fields
+type JiraSearchFieldsRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// JiraCreateIssueLinkRequest holds the arguments of jira_create_issue_link
+type JiraCreateIssueLinkRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	Type           string `json:"type"` // e.g. "Blocks"
+	InwardKey      string `json:"inward_key"`
+	OutwardKey     string `json:"outward_key"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraRemoveIssueLinkRequest holds the arguments of jira_remove_issue_link
+type JiraRemoveIssueLinkRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	LinkID         string `json:"link_id"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// JiraFindSimilarIssuesRequest holds the arguments of jira_find_similar_issues
+type JiraFindSimilarIssuesRequest struct {
+	WorkspaceID     string  `json:"workspace_id"`
+	ProjectKey      string  `json:"project_key"`
+	Summary         string  `json:"summary"`
+	Description     string  `json:"description,omitempty"`
+	IncludeResolved *bool   `json:"include_resolved,omitempty"` // Defaults to true
+	MinScore        float64 `json:"min_score,omitempty"`        // Defaults to 0.2
+	Limit           int     `json:"limit,omitempty"`
+}
+
+// JiraSprintBurndownRequest holds the arguments of jira_sprint_burndown
+type JiraSprintBurndownRequest struct {
+	WorkspaceID   string `json:"workspace_id"`
+	BoardID       string `json:"board_id"`
+	SprintID      string `json:"sprint_id"`
+	EstimateField string `json:"estimate_field,omitempty"` // Defaults to the board's estimation statistic
+}
+
+// JiraBoardSnapshotRequest holds the arguments of jira_board_snapshot
+type JiraBoardSnapshotRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	BoardID     string `json:"board_id"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// JiraSuggestAssigneeRequest holds the arguments of jira_suggest_assignee
+type JiraSuggestAssigneeRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	ProjectKey     string `json:"project_key"`
+	IssueType      string `json:"issue_type,omitempty"`
+	Limit          int    `json:"limit,omitempty"`
+	AssignIssueKey string `json:"assign_issue_key,omitempty"` // Assigns the issue to the top suggestion
+	DryRun         bool   `json:"dry_run,omitempty"`
+}
+
+// JiraIssueAgingRequest holds the arguments of jira_issue_aging
+type JiraIssueAgingRequest struct {
+	WorkspaceID   string   `json:"workspace_id"`
+	JQL           string   `json:"jql"`
+	Statuses      []string `json:"statuses,omitempty"`       // All statuses when empty
+	IncludeIssues *bool    `json:"include_issues,omitempty"` // Defaults to true
+	Limit         int      `json:"limit,omitempty"`
+}
+
+// JiraCommentDigestRequest holds the arguments of jira_comment_digest. Exactly one of
+// IssueKey and JQL is set.
+type JiraCommentDigestRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	IssueKey    string `json:"issue_key,omitempty"`
+	JQL         string `json:"jql,omitempty"`
+	Days        int    `json:"days,omitempty"`         // Defaults to 7
+	MaxComments int    `json:"max_comments,omitempty"` // Per issue; defaults to 10
+	Limit       int    `json:"limit,omitempty"`        // Issues; defaults to 20
+}
+
+// JiraRenderIssueRequest holds the arguments of jira_render_issue
+type JiraRenderIssueRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	IssueKey    string `json:"issue_key"`
+	Format      string `json:"format,omitempty"`   // markdown (the default) or html
+	Comments    *int   `json:"comments,omitempty"` // Defaults to 5
+}
+
+// SubscribeIssueUpdatesRequest holds the arguments of subscribe_issue_updates. Exactly
+// one of Channel and WebhookURL is set.
+type SubscribeIssueUpdatesRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	JQL         string `json:"jql"`
+	Channel     string `json:"channel,omitempty"` // Notification channel name or ID
+	WebhookURL  string `json:"webhook_url,omitempty"`
+}
+
+// JiraPinnedIssueState is a pinned issue with its current state, from list_pinned_issues
+type JiraPinnedIssueState struct {
+	WorkspaceID string    `json:"workspace_id"`
+	IssueKey    string    `json:"issue_key"`
+	PinnedAt    time.Time `json:"pinned_at"`
+	Summary     string    `json:"summary,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Assignee    string    `json:"assignee,omitempty"` // Empty when unassigned
+	Updated     string    `json:"updated,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	Error       string    `json:"error,omitempty"` // Why the issue could not be fetched
+}
+
+// JiraListProjects calls jira_list_projects
+func (c *Client) JiraListProjects(ctx context.Context, req JiraListProjectsRequest) ([]JiraProject, error) {
+	return call[[]JiraProject](ctx, c, "jira_list_projects", req)
+}
+
+// JiraListIssues calls jira_list_issues
+//
+// Deprecated: jira_list_issues is deprecated; use JiraListIssuesPage.
+func (c *Client) JiraListIssues(ctx context.Context, req JiraListIssuesRequest) (*JiraSearchResults, error) {
+	return call[*JiraSearchResults](ctx, c, "jira_list_issues", req)
+}
+
+// JiraListIssueSummaries calls jira_list_issues with summarize, returning one line per issue
+func (c *Client) JiraListIssueSummaries(ctx context.Context, req JiraListIssuesRequest) (*JiraIssueSummaries, error) {
+	return call[*JiraIssueSummaries](ctx, c, "jira_list_issues", struct {
+		JiraListIssuesRequest
+		Summarize bool `json:"summarize"`
+	}{req, true})
+}
+
+// JiraListIssuesPage calls jira_list_issues@v2, returning one page of issues. Pass the
+// page's NextCursor as Cursor to get the next one.
+func (c *Client) JiraListIssuesPage(ctx context.Context, req JiraListIssuesPageRequest) (*JiraIssuePage, error) {
+	return call[*JiraIssuePage](ctx, c, "jira_list_issues@v2", req)
+}
+
+// JiraGetIssue calls jira_get_issue
+func (c *Client) JiraGetIssue(ctx context.Context, req JiraGetIssueRequest) (*JiraIssue, error) {
+	return call[*JiraIssue](ctx, c, "jira_get_issue", req)
+}
+
+// JiraHydrateIssues calls jira_hydrate_issues and returns the issues in the order asked
+// for, along with those that could not be fetched
+func (c *Client) JiraHydrateIssues(ctx context.Context, req JiraHydrateIssuesRequest) (*JiraHydratedIssues, error) {
+	return call[*JiraHydratedIssues](ctx, c, "jira_hydrate_issues", req)
+}
+
+// JiraCreateIssue calls jira_create_issue and returns the new issue's ID and key
+func (c *Client) JiraCreateIssue(ctx context.Context, req JiraCreateIssueRequest) (*JiraIssue, error) {
+	return call[*JiraIssue](ctx, c, "jira_create_issue", req)
+}
+
+// JiraUpdateIssue calls jira_update_issue
+func (c *Client) JiraUpdateIssue(ctx context.Context, req JiraUpdateIssueRequest) (ActionResult, error) {
+	return call[ActionResult](ctx, c, "jira_update_issue", req)
+}
+
+// JiraAddComment calls jira_add_comment
+func (c *Client) JiraAddComment(ctx context.Context, req JiraAddCommentRequest) (*JiraComment, error) {
+	return call[*JiraComment](ctx, c, "jira_add_comment", req)
+}
+
+// JiraAppendToDescription calls jira_append_to_description
+func (c *Client) JiraAppendToDescription(ctx context.Context, req JiraAppendToDescriptionRequest) (ActionResult, error) {
+	return call[ActionResult](ctx, c, "jira_append_to_description", req)
+}
+
+// JiraUpdateDescriptionSection calls jira_update_description_section
+func (c *Client) JiraUpdateDescriptionSection(ctx context.Context, req JiraUpdateDescriptionSectionRequest) (ActionResult, error) {
+	return call[ActionResult](ctx, c, "jira_update_description_section", req)
+}
+
+// JiraTransitionIssue calls jira_transition_issue
+func (c *Client) JiraTransitionIssue(ctx context.Context, req JiraTransitionIssueRequest) (ActionResult, error) {
+	return call[ActionResult](ctx, c, "jira_transition_issue", req)
+}
+
+// JiraBulkTransition calls jira_bulk_transition
+func (c *Client) JiraBulkTransition(ctx context.Context, req JiraBulkTransitionRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_bulk_transition", req)
+}
+
+// JiraImportIssues calls jira_import_issues
+func (c *Client) JiraImportIssues(ctx context.Context, req JiraImportIssuesRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_import_issues", req)
+}
+
+// JiraSuggestAssignee calls jira_suggest_assignee
+func (c *Client) JiraSuggestAssignee(ctx context.Context, req JiraSuggestAssigneeRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_suggest_assignee", req)
+}
+
+// JiraGetAgileBoards calls jira_get_agile_boards
+func (c *Client) JiraGetAgileBoards(ctx context.Context, req JiraGetAgileBoardsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "jira_get_agile_boards", req)
+}
+
+// JiraGetBoardIssues calls jira_get_board_issues
+func (c *Client) JiraGetBoardIssues(ctx context.Context, req JiraGetBoardIssuesRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "jira_get_board_issues", req)
+}
+
+// JiraGetSprintsFromBoard calls jira_get_sprints_from_board
+func (c *Client) JiraGetSprintsFromBoard(ctx context.Context, req JiraGetSprintsFromBoardRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "jira_get_sprints_from_board", req)
+}
+
+// JiraGetSprintIssues calls jira_get_sprint_issues
+func (c *Client) JiraGetSprintIssues(ctx context.Context, req JiraGetSprintIssuesRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "jira_get_sprint_issues", req)
+}
+
+// JiraCreateSprint calls jira_create_sprint
+func (c *Client) JiraCreateSprint(ctx context.Context, req JiraCreateSprintRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_create_sprint", req)
+}
+
+// JiraUpdateSprint calls jira_update_sprint
+func (c *Client) JiraUpdateSprint(ctx context.Context, req JiraUpdateSprintRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_update_sprint", req)
+}
+
+// JiraGetWorklog calls jira_get_worklog
+func (c *Client) JiraGetWorklog(ctx context.Context, req JiraGetWorklogRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "jira_get_worklog", req)
+}
+
+// JiraAddWorklog calls jira_add_worklog
+func (c *Client) JiraAddWorklog(ctx context.Context, req JiraAddWorklogRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_add_worklog", req)
+}
+
+// JiraGetTransitions calls jira_get_transitions
+func (c *Client) JiraGetTransitions(ctx context.Context, req JiraGetTransitionsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "jira_get_transitions", req)
+}
+
+// JiraDeleteIssue calls jira_delete_issue
+func (c *Client) JiraDeleteIssue(ctx context.Context, req JiraDeleteIssueRequest) (ActionResult, error) {
+	return call[ActionResult](ctx, c, "jira_delete_issue", req)
+}
+
+// JiraGetProjectIssues calls jira_get_project_issues
+func (c *Client) JiraGetProjectIssues(ctx context.Context, req JiraGetProjectIssuesRequest) (*JiraSearchResults, error) {
+	return call[*JiraSearchResults](ctx, c, "jira_get_project_issues", req)
+}
+
+// JiraGetProjectVersions calls jira_get_project_versions
+func (c *Client) JiraGetProjectVersions(ctx context.Context, req JiraGetProjectVersionsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "jira_get_project_versions", req)
+}
+
+// JiraSearchUsers calls jira_search_users
+func (c *Client) JiraSearchUsers(ctx context.Context, req JiraSearchUsersRequest) ([]JiraUser, error) {
+	return call[[]JiraUser](ctx, c, "jira_search_users", req)
+}
+
+// JiraGetUserProfile calls jira_get_user_profile
+func (c *Client) JiraGetUserProfile(ctx context.Context, req JiraGetUserProfileRequest) (*JiraUser, error) {
+	return call[*JiraUser](ctx, c, "jira_get_user_profile", req)
+}
+
+// JiraSearchFields calls jira_search_fields
+func (c *Client) JiraSearchFields(ctx context.Context, req JiraSearchFieldsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "jira_search_fields", req)
+}
+
+// JiraCreateIssueLink calls jira_create_issue_link
+func (c *Client) JiraCreateIssueLink(ctx context.Context, req JiraCreateIssueLinkRequest) (ActionResult, error) {
+	return call[ActionResult](ctx, c, "jira_create_issue_link", req)
+}
+
+// JiraRemoveIssueLink calls jira_remove_issue_link
+func (c *Client) JiraRemoveIssueLink(ctx context.Context, req JiraRemoveIssueLinkRequest) (ActionResult, error) {
+	return call[ActionResult](ctx, c, "jira_remove_issue_link", req)
+}
+
+// JiraFindSimilarIssues calls jira_find_similar_issues and returns the potential
+// duplicates, best match first
+func (c *Client) JiraFindSimilarIssues(ctx context.Context, req JiraFindSimilarIssuesRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_find_similar_issues", req)
+}
+
+// JiraSprintBurndown calls jira_sprint_burndown and returns the sprint's day-by-day
+// burndown
+func (c *Client) JiraSprintBurndown(ctx context.Context, req JiraSprintBurndownRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_sprint_burndown", req)
+}
+
+// JiraBoardSnapshot calls jira_board_snapshot and returns the board's columns with their
+// issues
+func (c *Client) JiraBoardSnapshot(ctx context.Context, req JiraBoardSnapshotRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_board_snapshot", req)
+}
+
+// JiraIssueAging calls jira_issue_aging and returns the time the issues spent in each
+// status
+func (c *Client) JiraIssueAging(ctx context.Context, req JiraIssueAgingRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_issue_aging", req)
+}
+
+// JiraCommentDigest calls jira_comment_digest and returns the recent comments of the
+// issues, grouped by issue
+func (c *Client) JiraCommentDigest(ctx context.Context, req JiraCommentDigestRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_comment_digest", req)
+}
+
+// JiraRenderIssue calls jira_render_issue and returns the issue rendered as one document
+func (c *Client) JiraRenderIssue(ctx context.Context, req JiraRenderIssueRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_render_issue", req)
+}
+
+// JiraListIssueTemplates calls jira_list_issue_templates and returns your templates
+// followed by those shared with your organization
+func (c *Client) JiraListIssueTemplates(ctx context.Context) ([]JiraIssueTemplate, error) {
+	result, err := call[struct {
+		Templates []JiraIssueTemplate `json:"templates"`
+	}](ctx, c, "jira_list_issue_templates", struct{}{})
+	return result.Templates, err
+}
+
+// JiraCreateIssueFromTemplate calls jira_create_issue_from_template and returns the new
+// issue's key and URL, or with DryRun the issue it would create
+func (c *Client) JiraCreateIssueFromTemplate(ctx context.Context, req JiraCreateIssueFromTemplateRequest) (Object, error) {
+	return call[Object](ctx, c, "jira_create_issue_from_template", req)
+}
+
+// SubscribeIssueUpdates calls subscribe_issue_updates and returns the new subscription
+func (c *Client) SubscribeIssueUpdates(ctx context.Context, req SubscribeIssueUpdatesRequest) (*JiraIssueSubscription, error) {
+	return call[*JiraIssueSubscription](ctx, c, "subscribe_issue_updates", req)
+}
+
+// ListIssueSubscriptions calls list_issue_subscriptions and returns your subscriptions
+func (c *Client) ListIssueSubscriptions(ctx context.Context) ([]JiraIssueSubscription, error) {
+	result, err := call[struct {
+		Subscriptions []JiraIssueSubscription `json:"subscriptions"`
+	}](ctx, c, "list_issue_subscriptions", struct{}{})
+	return result.Subscriptions, err
+}
+
+// UnsubscribeIssueUpdates calls unsubscribe_issue_updates
+func (c *Client) UnsubscribeIssueUpdates(ctx context.Context, subscriptionID string) error {
+	_, err := call[Object](ctx, c, "unsubscribe_issue_updates", map[string]string{"subscription_id": subscriptionID})
+	return err
+}
+
+// PinIssue calls pin_issue and returns the pin
+func (c *Client) PinIssue(ctx context.Context, workspaceID, issueKey string) (*JiraPinnedIssue, error) {
+	return call[*JiraPinnedIssue](ctx, c, "pin_issue", map[string]string{"workspace_id": workspaceID, "issue_key": issueKey})
+}
+
+// UnpinIssue calls unpin_issue
+func (c *Client) UnpinIssue(ctx context.Context, workspaceID, issueKey string) error {
+	_, err := call[Object](ctx, c, "unpin_issue", map[string]string{"workspace_id": workspaceID, "issue_key": issueKey})
+	return err
+}
+
+// ListPinnedIssues calls list_pinned_issues and returns your pinned issues with their
+// current state, in the order they were pinned. An empty workspaceID lists every workspace.
+func (c *Client) ListPinnedIssues(ctx context.Context, workspaceID string) ([]JiraPinnedIssueState, error) {
+	result, err := call[struct {
+		PinnedIssues []JiraPinnedIssueState `json:"pinned_issues"`
+	}](ctx, c, "list_pinned_issues", map[string]string{"workspace_id": workspaceID})
+	return result.PinnedIssues, err
+}
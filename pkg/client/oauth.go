@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource supplies the bearer token sent with every call. Sources that can fetch
+// a new token also implement Invalidate, which the client calls after a 401.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a fixed API key (trx_...), service token (trs_...) or JWT
+type StaticToken string
+
+// Token implements TokenSource
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// tokenExpiryMargin is how long before expiry a cached OAuth token is replaced
+const tokenExpiryMargin = 30 * time.Second
+
+// OAuthConfig describes how to obtain access tokens from the server's authorization server
+type OAuthConfig struct {
+	TokenURL     string // Token endpoint; see DiscoverTokenURL
+	ClientID     string
+	ClientSecret string
+	RefreshToken string   // Uses the refresh_token grant when set, client_credentials otherwise
+	Scopes       []string // e.g. "jira:read"; empty requests the client's default scopes
+}
+
+// OAuthTokenSource fetches access tokens from an OAuth 2.0 token endpoint and caches
+// them until shortly before they expire. Rotated refresh tokens are kept.
+type OAuthTokenSource struct {
+	cfg        OAuthConfig
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewOAuthTokenSource creates a token source for cfg
+func NewOAuthTokenSource(cfg OAuthConfig) *OAuthTokenSource {
+	return &OAuthTokenSource{cfg: cfg, httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Token implements TokenSource
+func (s *OAuthTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Before(s.expiry) {
+		return s.token, nil
+	}
+	if err := s.fetch(ctx); err != nil {
+		return "", err
+	}
+	return s.token, nil
+}
+
+// Invalidate drops the cached token so the next call fetches a new one
+func (s *OAuthTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+// fetch requests a new token; the caller holds s.mu
+func (s *OAuthTokenSource) fetch(ctx context.Context) error {
+	form := url.Values{}
+	if s.cfg.RefreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", s.cfg.RefreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+	if len(s.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.cfg.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(s.cfg.ClientID), url.QueryEscape(s.cfg.ClientSecret))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("token response: %w", err)
+	}
+
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		RefreshToken     string `json:"refresh_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if resp.StatusCode != http.StatusOK || result.AccessToken == "" {
+		if result.Error != "" {
+			return fmt.Errorf("token endpoint returned %s: %s", result.Error, result.ErrorDescription)
+		}
+		return fmt.Errorf("token endpoint returned %d without an access token", resp.StatusCode)
+	}
+
+	s.token = result.AccessToken
+	lifetime := time.Hour // Assumed when the endpoint does not say
+	if result.ExpiresIn > 0 {
+		lifetime = time.Duration(result.ExpiresIn) * time.Second
+	}
+	if lifetime > 2*tokenExpiryMargin {
+		lifetime -= tokenExpiryMargin
+	}
+	s.expiry = time.Now().Add(lifetime)
+	if result.RefreshToken != "" {
+		s.cfg.RefreshToken = result.RefreshToken
+	}
+	return nil
+}
+
+// DiscoverTokenURL finds the token endpoint of the server's first authorization server
+// via its protected resource metadata (/.well-known/oauth-protected-resource) and the
+// authorization server's own metadata (RFC 8414, falling back to OpenID discovery)
+func DiscoverTokenURL(ctx context.Context, baseURL string) (string, error) {
+	var resource struct {
+		AuthorizationServers []string `json:"authorization_servers"`
+	}
+	if err := getJSON(ctx, strings.TrimRight(baseURL, "/")+"/.well-known/oauth-protected-resource", &resource); err != nil {
+		return "", err
+	}
+	if len(resource.AuthorizationServers) == 0 {
+		return "", errors.New("server does not advertise an authorization server")
+	}
+	issuer := strings.TrimRight(resource.AuthorizationServers[0], "/")
+
+	var errs []error
+	for _, path := range []string{"/.well-known/oauth-authorization-server", "/.well-known/openid-configuration"} {
+		var metadata struct {
+			TokenEndpoint string `json:"token_endpoint"`
+		}
+		err := getJSON(ctx, issuer+path, &metadata)
+		if err == nil && metadata.TokenEndpoint != "" {
+			return metadata.TokenEndpoint, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("%s%s has no token_endpoint", issuer, path)
+		}
+		errs = append(errs, err)
+	}
+	return "", errors.Join(errs...)
+}
+
+func getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
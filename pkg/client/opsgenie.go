@@ -0,0 +1,54 @@
+package client
+
+import "context"
+
+// Arguments of the opsgenie_* tools. Changes are processed asynchronously, so their
+// results carry the alert API's request ID.
+
+// OpsgenieListAlertsRequest holds the arguments of opsgenie_list_alerts
+type OpsgenieListAlertsRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Query       string `json:"query,omitempty"` // Defaults to "status:open"
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// OpsgenieAlertActionRequest holds the arguments of opsgenie_acknowledge_alert and opsgenie_close_alert
+type OpsgenieAlertActionRequest struct {
+	WorkspaceID    string `json:"workspace_id"`
+	AlertID        string `json:"alert_id"`
+	Note           string `json:"note,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// OpsgenieCreateAlertRequest holds the arguments of opsgenie_create_alert. Message or
+// IssueKey is required; IssueKey links the alert to a Jira issue.
+type OpsgenieCreateAlertRequest struct {
+	WorkspaceID    string   `json:"workspace_id"`
+	Message        string   `json:"message,omitempty"`
+	IssueKey       string   `json:"issue_key,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Priority       string   `json:"priority,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Alias          string   `json:"alias,omitempty"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty"`
+}
+
+// OpsgenieListAlerts calls opsgenie_list_alerts
+func (c *Client) OpsgenieListAlerts(ctx context.Context, req OpsgenieListAlertsRequest) ([]Object, error) {
+	return call[[]Object](ctx, c, "opsgenie_list_alerts", req)
+}
+
+// OpsgenieAcknowledgeAlert calls opsgenie_acknowledge_alert
+func (c *Client) OpsgenieAcknowledgeAlert(ctx context.Context, req OpsgenieAlertActionRequest) (Object, error) {
+	return call[Object](ctx, c, "opsgenie_acknowledge_alert", req)
+}
+
+// OpsgenieCloseAlert calls opsgenie_close_alert
+func (c *Client) OpsgenieCloseAlert(ctx context.Context, req OpsgenieAlertActionRequest) (Object, error) {
+	return call[Object](ctx, c, "opsgenie_close_alert", req)
+}
+
+// OpsgenieCreateAlert calls opsgenie_create_alert
+func (c *Client) OpsgenieCreateAlert(ctx context.Context, req OpsgenieCreateAlertRequest) (Object, error) {
+	return call[Object](ctx, c, "opsgenie_create_alert", req)
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"time"
+
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/models"
+)
+
+// Results shared with the server. They are aliases so callers outside this module
+// can name them.
+type (
+	JiraIssue               = models.JiraIssue
+	JiraSearchResults       = models.SearchResponse
+	JiraProject             = models.ProjectRef
+	JiraComment             = models.Comment
+	JiraUser                = models.User
+	JiraIssueTemplate       = models.IssueTemplate
+	JiraIssueSubscription   = models.IssueSubscription
+	JiraPinnedIssue         = models.PinnedIssue
+	ConfluencePage          = models.ConfluencePage
+	ConfluenceSpace         = models.ConfluenceSpace
+	ConfluenceSearchResults = models.SearchResults
+	ConfluenceUser          = models.ConfluenceUser
+	Workspace               = models.AtlassianCredential
+	WorkspacePolicy         = models.WorkspacePolicy
+)
+
+// Object is a result passed through from the Atlassian API as is (boards, sprints,
+// worklogs, labels, ...)
+type Object = map[string]interface{}
+
+// ActionResult is returned by tools that change data without returning it
+type ActionResult struct {
+	Success bool   `json:"success,omitempty"`
+	Status  string `json:"status,omitempty"` // "updated" or "transitioned"
+	Message string `json:"message,omitempty"`
+}
+
+// JiraIssueSummaries is returned by jira_list_issues with summarize: one
+// "KEY | summary | status | assignee | updated" line per issue
+type JiraIssueSummaries struct {
+	Total         int      `json:"total"`
+	Issues        []string `json:"issues"`
+	NextPageToken string   `json:"next_page_token,omitempty"`
+}
+
+// ConfluencePageSummaries is returned by confluence_search with summarize: one
+// "ID | title | space | status | last editor | updated" line per page
+type ConfluencePageSummaries struct {
+	Start     int      `json:"start"`
+	Results   []string `json:"results"`
+	NextStart int      `json:"next_start,omitempty"` // Set when there are more results
+}
+
+// WorkspaceStatus is returned by workspace_status
+type WorkspaceStatus struct {
+	WorkspaceID string `json:"workspace_id"`
+	Status      string `json:"status"`
+}
+
+// RateBudget is how much of a workspace's Atlassian rate limit budget one product has
+// left, as Atlassian last reported it
+type RateBudget struct {
+	Limit        int        `json:"limit,omitempty"`
+	Remaining    *int       `json:"remaining,omitempty"`
+	ResetAt      *time.Time `json:"reset_at,omitempty"`
+	NearLimit    bool       `json:"near_limit"`
+	BlockedUntil *time.Time `json:"blocked_until,omitempty"` // Calls are held until then after a 429
+	Throttled    bool       `json:"throttled"`
+	RateLimited  int64      `json:"rate_limited_responses"`
+	Delayed      int64      `json:"delayed_calls"`
+	Rejected     int64      `json:"rejected_calls"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	Error        string     `json:"error,omitempty"` // Set when the product's service could not answer
+}
+
+// WorkspaceRateStatus is returned by workspace_rate_status
+type WorkspaceRateStatus struct {
+	WorkspaceID string     `json:"workspace_id"`
+	Jira        RateBudget `json:"jira"`
+	Confluence  RateBudget `json:"confluence"`
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WorkspaceStatusRequest holds the arguments of workspace_status
+type WorkspaceStatusRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// ListWorkspaces calls list_workspaces. API tokens in the result are encrypted.
+func (c *Client) ListWorkspaces(ctx context.Context) ([]Workspace, error) {
+	return call[[]Workspace](ctx, c, "list_workspaces", nil)
+}
+
+// WorkspaceStatus calls workspace_status
+func (c *Client) WorkspaceStatus(ctx context.Context, req WorkspaceStatusRequest) (WorkspaceStatus, error) {
+	return call[WorkspaceStatus](ctx, c, "workspace_status", req)
+}
+
+// WorkspaceRateStatus calls workspace_rate_status
+func (c *Client) WorkspaceRateStatus(ctx context.Context, req WorkspaceStatusRequest) (WorkspaceRateStatus, error) {
+	return call[WorkspaceRateStatus](ctx, c, "workspace_rate_status", req)
+}
+
+// WorkspaceDetails is a workspace as returned by the management API (without its token)
+type WorkspaceDetails struct {
+	WorkspaceID   string           `json:"workspaceId"`
+	WorkspaceName string           `json:"workspaceName"`
+	SiteURL       string           `json:"siteUrl"`
+	JiraURL       string           `json:"jiraUrl,omitempty"`
+	ConfluenceURL string           `json:"confluenceUrl,omitempty"`
+	Email         string           `json:"email"`
+	Policy        *WorkspacePolicy `json:"policy,omitempty"`
+	Shared        bool             `json:"shared,omitempty"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+	DeletedAt     *time.Time       `json:"deletedAt,omitempty"`
+}
+
+// CreateWorkspaceRequest is the body of POST /api/workspaces
+type CreateWorkspaceRequest struct {
+	WorkspaceName string           `json:"workspaceName,omitempty"` // Defaults to the site URL
+	SiteURL       string           `json:"siteUrl"`
+	Email         string           `json:"email"`
+	APIToken      string           `json:"apiToken"`
+	Policy        *WorkspacePolicy `json:"policy,omitempty"`
+	Shared        bool             `json:"shared,omitempty"` // Create for the caller's organization
+
+	OpsgenieAPIKey string `json:"opsgenieApiKey,omitempty"` // Used by the opsgenie_* tools
+	JiraURL        string `json:"jiraUrl,omitempty"`        // Jira's base URL when it is not the site URL
+	ConfluenceURL  string `json:"confluenceUrl,omitempty"`  // Confluence's base URL when it is not the site URL + /wiki
+	ProxyURL       string `json:"proxyUrl,omitempty"`       // Proxy for the workspace's Atlassian calls, instead of HTTPS_PROXY
+}
+
+// CreateWorkspace adds a workspace after the server has validated its credentials
+func (c *Client) CreateWorkspace(ctx context.Context, req CreateWorkspaceRequest) (*WorkspaceDetails, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.send(ctx, http.MethodPost, c.endpoint("/api/workspaces"), body)
+	if err != nil {
+		return nil, err
+	}
+	var workspace WorkspaceDetails
+	if err := json.Unmarshal(data, &workspace); err != nil {
+		return nil, fmt.Errorf("decode workspace: %w", err)
+	}
+	return &workspace, nil
+}
+
+// Workspaces lists the caller's workspaces followed by their organization's shared ones
+func (c *Client) Workspaces(ctx context.Context) ([]WorkspaceDetails, error) {
+	data, err := c.send(ctx, http.MethodGet, c.endpoint("/api/workspaces"), nil)
+	if err != nil {
+		return nil, err
+	}
+	var workspaces []WorkspaceDetails
+	if err := json.Unmarshal(data, &workspaces); err != nil {
+		return nil, fmt.Errorf("decode workspaces: %w", err)
+	}
+	return workspaces, nil
+}
+
+// DeleteWorkspace soft-deletes a workspace; it can be restored until it is purged
+func (c *Client) DeleteWorkspace(ctx context.Context, workspaceID string) error {
+	_, err := c.send(ctx, http.MethodDelete, c.endpoint("/api/workspaces/"+url.PathEscape(workspaceID)), nil)
+	return err
+}
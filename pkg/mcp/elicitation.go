@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// elicitTimeout bounds how long a tool call waits for the user to answer a form
+const elicitTimeout = 10 * time.Minute
+
+// supportedProtocolVersions lists the MCP revisions the servers speak; elicitation needs 2025-06-18
+var supportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// ErrElicitationClosed is returned by an Elicit call whose connection closed before the user answered
+var ErrElicitationClosed = errors.New("the client disconnected before answering")
+
+// ElicitFunc asks the user to fill in a form. The schema is a flat JSON Schema object whose
+// properties are strings, numbers, booleans or enums.
+type ElicitFunc func(message string, schema map[string]interface{}) (ElicitResult, error)
+
+// ElicitResult is the user's answer to a form
+type ElicitResult struct {
+	Action  string                 `json:"action"`            // "accept", "decline" or "cancel"
+	Content map[string]interface{} `json:"content,omitempty"` // Form values, when accepted
+}
+
+// Accepted reports whether the user submitted the form
+func (r ElicitResult) Accepted() bool {
+	return r.Action == "accept"
+}
+
+// negotiateProtocolVersion answers an initialize request with the client's protocol
+// version when it is supported, or the oldest one otherwise
+func negotiateProtocolVersion(request map[string]interface{}) string {
+	params, _ := request["params"].(map[string]interface{})
+	requested, _ := params["protocolVersion"].(string)
+	for _, version := range supportedProtocolVersions {
+		if version == requested {
+			return version
+		}
+	}
+	return supportedProtocolVersions[len(supportedProtocolVersions)-1]
+}
+
+// clientElicits reports whether an initialize request declares the elicitation capability
+// at a protocol version that has it
+func clientElicits(request map[string]interface{}) bool {
+	if negotiateProtocolVersion(request) < "2025-06-18" {
+		return false
+	}
+	params, _ := request["params"].(map[string]interface{})
+	capabilities, _ := params["capabilities"].(map[string]interface{})
+	_, ok := capabilities["elicitation"]
+	return ok
+}
+
+// isResponse reports whether a message from the client answers a request of the server's
+func isResponse(message map[string]interface{}) bool {
+	if _, ok := message["method"]; ok {
+		return false
+	}
+	_, hasResult := message["result"]
+	_, hasError := message["error"]
+	return message["id"] != nil && (hasResult || hasError)
+}
+
+// elicitor sends elicitation/create requests to a client and matches its responses
+type elicitor struct {
+	send func(message []byte) error
+
+	mu      sync.Mutex // Guards the fields below
+	nextID  int64
+	pending map[string]chan map[string]interface{}
+}
+
+func newElicitor(send func(message []byte) error) *elicitor {
+	return &elicitor{
+		send:    send,
+		pending: make(map[string]chan map[string]interface{}),
+	}
+}
+
+// elicit sends a form to the client and waits for the user's answer
+func (e *elicitor) elicit(message string, schema map[string]interface{}) (ElicitResult, error) {
+	e.mu.Lock()
+	e.nextID++
+	id := fmt.Sprintf("elicit-%d", e.nextID)
+	reply := make(chan map[string]interface{}, 1)
+	e.pending[id] = reply
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		delete(e.pending, id)
+		e.mu.Unlock()
+	}()
+
+	request, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  "elicitation/create",
+		"params": map[string]interface{}{
+			"message":         message,
+			"requestedSchema": schema,
+		},
+	})
+	if err := e.send(request); err != nil {
+		return ElicitResult{}, err
+	}
+
+	var response map[string]interface{}
+	select {
+	case response = <-reply:
+	case <-time.After(elicitTimeout):
+		return ElicitResult{}, fmt.Errorf("no answer to the form after %s", elicitTimeout)
+	}
+	if response == nil {
+		return ElicitResult{}, ErrElicitationClosed
+	}
+	if rpcErr, ok := response["error"].(map[string]interface{}); ok {
+		return ElicitResult{}, fmt.Errorf("client rejected the form: %v", rpcErr["message"])
+	}
+
+	var result ElicitResult
+	raw, _ := json.Marshal(response["result"])
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return ElicitResult{}, fmt.Errorf("invalid elicitation result: %w", err)
+	}
+	return result, nil
+}
+
+// deliver hands a client's response to the call waiting for it, and reports whether one was
+func (e *elicitor) deliver(response map[string]interface{}) bool {
+	id := fmt.Sprint(response["id"])
+	e.mu.Lock()
+	reply, ok := e.pending[id]
+	delete(e.pending, id)
+	e.mu.Unlock()
+	if ok {
+		reply <- response
+	}
+	return ok
+}
+
+// close fails the calls still waiting for an answer
+func (e *elicitor) close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id, reply := range e.pending {
+		reply <- nil
+		delete(e.pending, id)
+	}
+}
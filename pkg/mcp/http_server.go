@@ -3,6 +3,7 @@ package mcp
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 )
 
@@ -27,7 +28,7 @@ func (h *HTTPServer) StartHTTP(port int) error {
 	http.HandleFunc("/tools/call", h.handleToolCall)
 
 	addr := fmt.Sprintf(":%d", port)
-	fmt.Printf("MCP HTTP Server listening on %s\n", addr)
+	slog.Info("MCP HTTP server listening", "addr", addr)
 	return http.ListenAndServe(addr, nil)
 }
 
@@ -39,7 +40,7 @@ func (h *HTTPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 func (h *HTTPServer) handleListTools(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"tools": h.server.tools,
+		"tools": h.server.Tools(),
 	})
 }
 
@@ -0,0 +1,115 @@
+package mcp
+
+import "strings"
+
+// OpenAPISpec describes the REST tool API (POST /api/tools/{name}) as an OpenAPI 3.1
+// document, with one path per tool whose request body is the tool's input schema.
+// serverURL is the public base URL; bearerAuth declares the Authorization header.
+func OpenAPISpec(tools []Tool, serverURL string, bearerAuth bool) map[string]interface{} {
+	paths := make(map[string]interface{}, len(tools))
+	for _, tool := range tools {
+		schema := tool.InputSchema
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object"}
+		}
+		operation := map[string]interface{}{
+			// Clients such as ChatGPT Actions only accept letters, digits, _ and - here
+			"operationId": strings.ReplaceAll(tool.Name, ToolVersionSeparator, "_"),
+			"summary":     firstSentence(tool.Description),
+			"description": tool.Description,
+			"tags":        []string{toolTag(tool.Name)},
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schema},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "The tool result as JSON, or {\"result\": \"...\"} for plain text results",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": map[string]interface{}{}},
+					},
+				},
+				"400": errorResponse("Invalid arguments, unknown tool or a failed tool call"),
+				"401": errorResponse("Missing or invalid token"),
+				"403": errorResponse("The token's scopes or the server's tool allowlist do not permit this tool"),
+				"500": errorResponse("The Jira or Confluence service failed"),
+			},
+		}
+		if tool.Deprecated != "" {
+			operation["deprecated"] = true
+		}
+		paths["/api/tools/"+tool.Name] = map[string]interface{}{"post": operation}
+	}
+
+	spec := map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":       "Trilix Atlassian MCP Server",
+			"version":     "1.0.0",
+			"description": "Jira, Confluence, Bitbucket and Opsgenie tools of the Trilix Atlassian MCP server, callable over REST.",
+		},
+		"servers": []map[string]interface{}{{"url": serverURL}},
+		"paths":   paths,
+	}
+	if bearerAuth {
+		spec["components"] = map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "Session JWT, API key (trx_...) or service token (trs_...)",
+				},
+			},
+		}
+		spec["security"] = []map[string][]string{{"bearerAuth": {}}}
+	}
+	return spec
+}
+
+// SelectTools keeps the tools matching any selector: a tool name or a service tag
+// ("jira", "confluence", "bitbucket", "opsgenie", "admin", "search", "generate", "create", "notify", "run", "workspaces"). No selectors keeps every tool.
+func SelectTools(tools []Tool, selectors []string) []Tool {
+	if len(selectors) == 0 {
+		return tools
+	}
+	selected := make([]Tool, 0, len(tools))
+	for _, tool := range tools {
+		for _, selector := range selectors {
+			if selector == tool.Name || selector == toolTag(tool.Name) {
+				selected = append(selected, tool)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func errorResponse(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+		},
+	}
+}
+
+// toolTag groups tools by service: "jira", "confluence", "bitbucket", "opsgenie", "admin",
+// "search" (search_atlassian), "generate" (generate_release_notes), "create"
+// (create_issues_from_page), "notify" (notify_channel), "run" (run_playbook) or "workspaces"
+func toolTag(name string) string {
+	switch service, _, _ := strings.Cut(name, "_"); service {
+	case "jira", "confluence", "bitbucket", "opsgenie", "admin", "search", "generate", "create", "notify", "run":
+		return service
+	}
+	return "workspaces"
+}
+
+// firstSentence shortens a tool description to an operation summary
+func firstSentence(description string) string {
+	if i := strings.Index(description, ". "); i >= 0 {
+		return description[:i]
+	}
+	return strings.TrimSuffix(description, ".")
+}
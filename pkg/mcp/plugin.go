@@ -0,0 +1,303 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ToolProvider contributes tools to a server. The built-in handlers have this shape, and
+// third-party tool handlers implement it to be registered at startup.
+type ToolProvider interface {
+	ListTools() []Tool
+	HandleTool(call ToolCall, userID string) (ToolResult, error)
+}
+
+// maxPluginMessageBytes bounds one message from a plugin
+const maxPluginMessageBytes = 16 << 20
+
+// pluginStartTimeout bounds how long a plugin may take to initialize and list its tools
+const pluginStartTimeout = 30 * time.Second
+
+// Plugin is a ToolProvider run as a subprocess that speaks MCP over stdio, e.g. a server
+// built with Server.Start, in any language. Calls carry the caller's user, organization
+// and request ID in params._meta. A plugin that exits is restarted on its next call.
+type Plugin struct {
+	name    string
+	command []string
+	timeout func(tool string) time.Duration
+
+	mu    sync.Mutex // Guards proc
+	proc  *pluginProcess
+	tools []Tool
+}
+
+// pluginProcess is one run of a plugin
+type pluginProcess struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex // Serializes requests on stdin
+
+	mu      sync.Mutex // Guards the fields below
+	nextID  int64
+	pending map[int64]chan pluginReply
+	err     error // Why the process stopped; set once it has
+}
+
+// pluginReply is a JSON-RPC response from a plugin
+type pluginReply struct {
+	ID     *int64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// StartPlugin runs command and lists its tools. timeout gives how long each tool's calls
+// may take.
+func StartPlugin(command []string, timeout func(tool string) time.Duration) (*Plugin, error) {
+	if len(command) == 0 {
+		return nil, errors.New("empty plugin command")
+	}
+	p := &Plugin{name: filepath.Base(command[0]), command: command, timeout: timeout}
+
+	proc, err := p.start()
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := proc.call("tools/list", map[string]interface{}{}, pluginStartTimeout, &list); err != nil {
+		proc.kill()
+		return nil, fmt.Errorf("plugin %s: tools/list: %w", p.name, err)
+	}
+	p.proc = proc
+	p.tools = list.Tools
+	return p, nil
+}
+
+// Name identifies the plugin in logs: its executable's name
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// ListTools returns the tools the plugin listed when it started
+func (p *Plugin) ListTools() []Tool {
+	return p.tools
+}
+
+// HandleTool runs a call in the plugin, restarting it first if it has exited
+func (p *Plugin) HandleTool(call ToolCall, userID string) (ToolResult, error) {
+	proc, err := p.process()
+	if err != nil {
+		return pluginError(err), err
+	}
+
+	params := map[string]interface{}{
+		"name":      call.Name,
+		"arguments": call.Arguments,
+		"_meta": map[string]string{
+			"user_id":    userID,
+			"org_id":     call.OrgID,
+			"request_id": call.RequestID,
+		},
+	}
+	var result ToolResult
+	if err := proc.call("tools/call", params, p.timeout(call.Name), &result); err != nil {
+		err = fmt.Errorf("plugin %s: %w", p.name, err)
+		return pluginError(err), err
+	}
+	return result, nil
+}
+
+// Close stops the plugin
+func (p *Plugin) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proc != nil {
+		p.proc.kill()
+		p.proc = nil
+	}
+}
+
+// process returns the running process, restarting the plugin if it has exited
+func (p *Plugin) process() (*pluginProcess, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proc != nil && p.proc.stopped() == nil {
+		return p.proc, nil
+	}
+	if p.proc != nil {
+		slog.Warn("restarting plugin", "plugin", p.name, "error", p.proc.stopped())
+	}
+	proc, err := p.start()
+	if err != nil {
+		return nil, err
+	}
+	p.proc = proc
+	return proc, nil
+}
+
+// start runs the plugin's command and initializes the MCP session
+func (p *Plugin) start() (*pluginProcess, error) {
+	cmd := exec.Command(p.command[0], p.command[1:]...)
+	cmd.Stderr = os.Stderr // The plugin's logs join the server's
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+
+	proc := &pluginProcess{cmd: cmd, stdin: stdin, pending: make(map[int64]chan pluginReply)}
+	go proc.readReplies(stdout)
+
+	initialize := map[string]interface{}{
+		"protocolVersion": "2024-11-05",
+		"capabilities":    map[string]interface{}{},
+		"clientInfo": map[string]interface{}{
+			"name":    "trilix-atlassian-mcp-server",
+			"version": "1.0.0",
+		},
+	}
+	if err := proc.call("initialize", initialize, pluginStartTimeout, nil); err != nil {
+		proc.kill()
+		return nil, fmt.Errorf("plugin %s: initialize: %w", p.name, err)
+	}
+	proc.notify("notifications/initialized")
+	return proc, nil
+}
+
+// call sends a request and decodes its result into v (nil discards it)
+func (proc *pluginProcess) call(method string, params interface{}, timeout time.Duration, v interface{}) error {
+	proc.mu.Lock()
+	if proc.err != nil {
+		proc.mu.Unlock()
+		return proc.err
+	}
+	proc.nextID++
+	id := proc.nextID
+	replies := make(chan pluginReply, 1)
+	proc.pending[id] = replies
+	proc.mu.Unlock()
+
+	err := proc.send(map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+	if err != nil {
+		proc.forget(id)
+		return err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case reply, ok := <-replies:
+		if !ok {
+			return proc.stopped()
+		}
+		if reply.Error != nil {
+			return errors.New(reply.Error.Message)
+		}
+		if v == nil {
+			return nil
+		}
+		return json.Unmarshal(reply.Result, v)
+	case <-timer.C:
+		proc.forget(id)
+		return fmt.Errorf("%s timed out after %s", method, timeout)
+	}
+}
+
+// notify sends a notification, which has no reply
+func (proc *pluginProcess) notify(method string) {
+	if err := proc.send(map[string]interface{}{"jsonrpc": "2.0", "method": method}); err != nil {
+		slog.Warn("plugin notification failed", "method", method, "error", err)
+	}
+}
+
+func (proc *pluginProcess) send(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	proc.writeMu.Lock()
+	defer proc.writeMu.Unlock()
+	_, err = proc.stdin.Write(append(data, '\n'))
+	return err
+}
+
+func (proc *pluginProcess) forget(id int64) {
+	proc.mu.Lock()
+	delete(proc.pending, id)
+	proc.mu.Unlock()
+}
+
+// readReplies hands each response to the call waiting for it, until the plugin exits
+func (proc *pluginProcess) readReplies(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), maxPluginMessageBytes)
+	for scanner.Scan() {
+		var reply pluginReply
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil || reply.ID == nil {
+			// Notifications, and replies to ours, need no answer
+			continue
+		}
+		proc.mu.Lock()
+		replies, ok := proc.pending[*reply.ID]
+		delete(proc.pending, *reply.ID)
+		proc.mu.Unlock()
+		if ok {
+			replies <- reply
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = errors.New("plugin exited")
+	}
+	// A plugin whose output cannot be read is stopped too
+	proc.kill()
+	proc.cmd.Wait()
+	proc.mu.Lock()
+	proc.err = err
+	for id, replies := range proc.pending {
+		close(replies)
+		delete(proc.pending, id)
+	}
+	proc.mu.Unlock()
+}
+
+// stopped returns why the process stopped, or nil while it runs
+func (proc *pluginProcess) stopped() error {
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	return proc.err
+}
+
+func (proc *pluginProcess) kill() {
+	proc.stdin.Close()
+	if proc.cmd.Process != nil {
+		proc.cmd.Process.Kill()
+	}
+}
+
+func pluginError(err error) ToolResult {
+	return ToolResult{
+		Content: []ContentBlock{{Type: "text", Text: fmt.Sprintf("Error: %v", err)}},
+		IsError: true,
+	}
+}
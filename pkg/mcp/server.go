@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 )
 
 // Server handles MCP protocol communication over stdio
 type Server struct {
-	tools []Tool
+	tools      []Tool
+	toolFilter func(name string) bool // Hides tools from tools/list when it returns false
 }
 
 // NewServer creates a new MCP server
@@ -25,10 +27,52 @@ func (s *Server) RegisterTool(tool Tool) {
 	s.tools = append(s.tools, tool)
 }
 
-// Start starts the MCP server on stdio
+// SetToolFilter hides tools from tools/list unless filter returns true for their name.
+// The filter is consulted on every listing, so it may change its answer at runtime.
+func (s *Server) SetToolFilter(filter func(name string) bool) {
+	s.toolFilter = filter
+}
+
+// AllTools returns every registered tool, ignoring the tool filter
+func (s *Server) AllTools() []Tool {
+	return s.tools
+}
+
+// Tools returns the registered tools that pass the tool filter
+func (s *Server) Tools() []Tool {
+	if s.toolFilter == nil {
+		return s.tools
+	}
+	tools := make([]Tool, 0, len(s.tools))
+	for _, tool := range s.tools {
+		if s.toolFilter(tool.Name) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// Start starts the MCP server on stdio. Clients that declare the elicitation capability
+// have their tool calls run concurrently, so the calls can wait for the user's answers.
 func (s *Server) Start(handler func(ToolCall) (ToolResult, error)) error {
 	scanner := bufio.NewScanner(os.Stdin)
 	writer := os.Stdout
+	var writeMu sync.Mutex // Serializes messages on stdout
+	write := func(message []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if _, err := writer.Write(append(message, '\n')); err != nil {
+			return err
+		}
+		writer.Sync()
+		return nil
+	}
+	var elicit *elicitor // Set once the client declares elicitation
+	defer func() {
+		if elicit != nil {
+			elicit.close()
+		}
+	}()
 
 	for scanner.Scan() {
 		line := scanner.Bytes()
@@ -41,38 +85,47 @@ func (s *Server) Start(handler func(ToolCall) (ToolResult, error)) error {
 			continue
 		}
 
+		if elicit != nil && isResponse(request) {
+			elicit.deliver(request)
+			continue
+		}
 		method, ok := request["method"].(string)
 		if !ok {
 			continue
 		}
 
-		var response map[string]interface{}
+		respond := func(response map[string]interface{}) {
+			response["jsonrpc"] = "2.0"
+			if id, ok := request["id"]; ok {
+				response["id"] = id
+			}
+
+			responseBytes, _ := json.Marshal(response)
+			write(responseBytes)
+		}
 
 		switch method {
 		case "initialize":
-			response = s.handleInitialize(request)
+			if elicit == nil && clientElicits(request) {
+				elicit = newElicitor(write)
+			}
+			respond(s.handleInitialize(request))
 		case "tools/list":
-			response = s.handleListTools()
+			respond(s.handleListTools())
 		case "tools/call":
-			response = s.handleToolCall(request, handler)
+			if elicit != nil {
+				go func() { respond(s.handleToolCall(request, handler, elicit)) }()
+			} else {
+				respond(s.handleToolCall(request, handler, nil))
+			}
 		default:
-			response = map[string]interface{}{
+			respond(map[string]interface{}{
 				"error": map[string]string{
 					"code":    "-32601",
 					"message": fmt.Sprintf("Method not found: %s", method),
 				},
-			}
+			})
 		}
-
-		response["jsonrpc"] = "2.0"
-		if id, ok := request["id"]; ok {
-			response["id"] = id
-		}
-
-		responseBytes, _ := json.Marshal(response)
-		writer.Write(responseBytes)
-		writer.WriteString("\n")
-		writer.Sync()
 	}
 
 	return scanner.Err()
@@ -81,7 +134,7 @@ func (s *Server) Start(handler func(ToolCall) (ToolResult, error)) error {
 func (s *Server) handleInitialize(request map[string]interface{}) map[string]interface{} {
 	return map[string]interface{}{
 		"result": map[string]interface{}{
-			"protocolVersion": "2024-11-05",
+			"protocolVersion": negotiateProtocolVersion(request),
 			"capabilities": map[string]interface{}{
 				"tools": map[string]interface{}{},
 			},
@@ -96,12 +149,12 @@ func (s *Server) handleInitialize(request map[string]interface{}) map[string]int
 func (s *Server) handleListTools() map[string]interface{} {
 	return map[string]interface{}{
 		"result": map[string]interface{}{
-			"tools": s.tools,
+			"tools": s.Tools(),
 		},
 	}
 }
 
-func (s *Server) handleToolCall(request map[string]interface{}, handler func(ToolCall) (ToolResult, error)) map[string]interface{} {
+func (s *Server) handleToolCall(request map[string]interface{}, handler func(ToolCall) (ToolResult, error), elicit *elicitor) map[string]interface{} {
 	params, ok := request["params"].(map[string]interface{})
 	if !ok {
 		return map[string]interface{}{
@@ -118,6 +171,16 @@ func (s *Server) handleToolCall(request map[string]interface{}, handler func(Too
 	toolCall := ToolCall{
 		Name:      name,
 		Arguments: arguments,
+		SessionID: StdioSessionID,
+	}
+	if elicit != nil {
+		toolCall.Elicit = elicit.elicit
+	}
+	// A server run as a plugin learns its caller from the MCP server's _meta
+	if meta, ok := params["_meta"].(map[string]interface{}); ok {
+		toolCall.UserID, _ = meta["user_id"].(string)
+		toolCall.OrgID, _ = meta["org_id"].(string)
+		toolCall.RequestID, _ = meta["request_id"].(string)
 	}
 
 	result, err := handler(toolCall)
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// SessionHeader carries the session ID of POST /message clients that do not keep an SSE
+	// connection open. The server sends it in the response to initialize.
+	SessionHeader = "Mcp-Session-Id"
+	// SessionQueryParam carries the session ID in the message endpoint an SSE connection
+	// announces, e.g. /message?sessionId=...
+	SessionQueryParam = "sessionId"
+	// StdioSessionID is the session of a server run on stdio, which serves one client
+	StdioSessionID = "stdio"
+
+	// DefaultSessionTTL is how long an idle session's values are kept
+	DefaultSessionTTL = 12 * time.Hour
+	// maxSessionIDLength bounds the session IDs clients may send
+	maxSessionIDLength = 128
+)
+
+// errSessionOwner is returned when a session's values are changed by another user
+var errSessionOwner = errors.New("the session belongs to another user")
+
+// SessionValues keeps per-session state that tools set for later calls in the same MCP
+// session, e.g. the conversation's active workspace. The values belong to the user who set
+// the first of them, and are dropped when the session ends or after ttl without use. They
+// are kept in memory, so they live on the replica the session's calls reach.
+type SessionValues struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*sessionValues
+}
+
+// sessionValues are one session's values
+type sessionValues struct {
+	userID string
+	values map[string]string
+	usedAt time.Time
+}
+
+// NewSessionValues creates an empty session value store whose idle sessions expire after ttl
+func NewSessionValues(ttl time.Duration) *SessionValues {
+	return &SessionValues{ttl: ttl, sessions: make(map[string]*sessionValues)}
+}
+
+// Get returns one of the session's values, if the user set it
+func (s *SessionValues) Get(sessionID, userID, key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok || session.userID != userID || time.Since(session.usedAt) > s.ttl {
+		return "", false
+	}
+	session.usedAt = time.Now()
+	value, ok := session.values[key]
+	return value, ok
+}
+
+// Set stores one of the session's values; an empty value removes it
+func (s *SessionValues) Set(sessionID, userID, key, value string) error {
+	if sessionID == "" {
+		return errors.New("no MCP session; connect over SSE, or send the Mcp-Session-Id header from initialize")
+	}
+	if len(sessionID) > maxSessionIDLength {
+		return errors.New("session ID too long")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, session := range s.sessions {
+		if now.Sub(session.usedAt) > s.ttl {
+			delete(s.sessions, id)
+		}
+	}
+
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		session = &sessionValues{userID: userID, values: make(map[string]string)}
+		s.sessions[sessionID] = session
+	}
+	if session.userID != userID {
+		return errSessionOwner
+	}
+	session.usedAt = now
+	if value == "" {
+		delete(session.values, key)
+	} else {
+		session.values[key] = value
+	}
+	return nil
+}
+
+// End drops a session's values
+func (s *SessionValues) End(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+}
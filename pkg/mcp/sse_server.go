@@ -5,23 +5,112 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/providentiaww/trilix-atlassian-mcp/cmd/mcp-server/auth"
+	"github.com/providentiaww/trilix-atlassian-mcp/internal/logging"
 )
 
 // SSEServer implements MCP protocol over Server-Sent Events
 type SSEServer struct {
-	server  *Server
-	handler func(ToolCall, string) (ToolResult, error) // Updated to accept userID
-	mu      sync.Mutex
+	server   *Server
+	handler  func(ToolCall, string) (ToolResult, error) // Updated to accept userID
+	mu       sync.Mutex
+	sessions map[string]Session    // Open SSE connections, indexed by session ID
+	streams  map[string]*sseStream // Their event streams, indexed by session ID
+	values   *SessionValues        // Per-session state of tools (nil = none)
+}
+
+// sseStream carries a session's requests to its client, e.g. elicitation forms
+type sseStream struct {
+	out      chan []byte
+	closed   chan struct{}
+	elicitor *elicitor
+	elicits  bool // Client declared elicitation at initialize; guarded by SSEServer.mu
+}
+
+// Session describes an open SSE connection
+type Session struct {
+	ID          string    `json:"id"`         // Session ID, sent back with each message
+	RequestID   string    `json:"request_id"` // Request ID of the connecting request
+	UserID      string    `json:"user_id,omitempty"`
+	RemoteAddr  string    `json:"remote_addr"`
+	UserAgent   string    `json:"user_agent,omitempty"`
+	ConnectedAt time.Time `json:"connected_at"`
 }
 
 // NewSSEServer creates a new SSE-based MCP server
 func NewSSEServer(server *Server, handler func(ToolCall, string) (ToolResult, error)) *SSEServer {
 	return &SSEServer{
-		server:  server,
-		handler: handler,
+		server:   server,
+		handler:  handler,
+		sessions: make(map[string]Session),
+		streams:  make(map[string]*sseStream),
+	}
+}
+
+// WithSessionValues keeps the tools' per-session state in values, and drops a session's
+// state when its SSE connection closes
+func (s *SSEServer) WithSessionValues(values *SessionValues) *SSEServer {
+	s.values = values
+	return s
+}
+
+// Sessions returns the open SSE connections, oldest first
+func (s *SSEServer) Sessions() []Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessions := make([]Session, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ConnectedAt.Before(sessions[j].ConnectedAt) })
+	return sessions
+}
+
+// trackSession records an open connection and its stream until the returned function is called
+func (s *SSEServer) trackSession(r *http.Request) (Session, *sseStream, func()) {
+	session := Session{
+		ID:          uuid.New().String(),
+		RequestID:   logging.RequestIDFromContext(r.Context()),
+		RemoteAddr:  r.RemoteAddr,
+		UserAgent:   r.UserAgent(),
+		ConnectedAt: time.Now(),
+	}
+	if userCtx, ok := auth.ExtractUserFromContext(r.Context()); ok {
+		session.UserID = userCtx.UserID
+	}
+
+	stream := &sseStream{
+		out:    make(chan []byte),
+		closed: make(chan struct{}),
+	}
+	stream.elicitor = newElicitor(func(message []byte) error {
+		select {
+		case stream.out <- message:
+			return nil
+		case <-stream.closed:
+			return ErrElicitationClosed
+		}
+	})
+
+	s.mu.Lock()
+	s.sessions[session.ID] = session
+	s.streams[session.ID] = stream
+	s.mu.Unlock()
+	return session, stream, func() {
+		s.mu.Lock()
+		delete(s.sessions, session.ID)
+		delete(s.streams, session.ID)
+		s.mu.Unlock()
+		close(stream.closed)
+		stream.elicitor.close()
+		if s.values != nil {
+			s.values.End(session.ID)
+		}
 	}
 }
 
@@ -37,16 +126,53 @@ func (s *SSEServer) HandleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Send initial connection message
-	fmt.Fprintf(w, "event: endpoint\ndata: /message\n\n")
+	// Send initial connection message; messages name the session in the endpoint's query
+	session, stream, untrack := s.trackSession(r)
+	defer untrack()
+	fmt.Fprintf(w, "event: endpoint\ndata: /message?%s=%s\n\n", SessionQueryParam, session.ID)
 	flusher.Flush()
 
-	// Keep connection alive until client disconnects
-	<-r.Context().Done()
+	// Relay the server's requests until the client disconnects
+	for {
+		select {
+		case message := <-stream.out:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", message)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// sessionStream returns the event stream of a session opened by the same user, or nil
+func (s *SSEServer) sessionStream(sessionID string, r *http.Request) *sseStream {
+	userID := ""
+	if userCtx, ok := auth.ExtractUserFromContext(r.Context()); ok {
+		userID = userCtx.UserID
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session, ok := s.sessions[sessionID]; !ok || session.UserID != userID {
+		return nil
+	}
+	return s.streams[sessionID]
 }
 
-// HandleMessage handles MCP protocol messages
+// HandleMessage handles MCP protocol messages. Clients without an SSE connection get a
+// session ID in the Mcp-Session-Id header of the initialize response, send it with later
+// messages, and may end the session with DELETE.
 func (s *SSEServer) HandleMessage(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get(SessionQueryParam)
+	if sessionID == "" {
+		sessionID = r.Header.Get(SessionHeader)
+	}
+	if r.Method == http.MethodDelete && sessionID != "" {
+		if s.values != nil {
+			s.values.End(sessionID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -64,16 +190,34 @@ func (s *SSEServer) HandleMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Answers to the server's requests (elicitation forms) are routed to the waiting call
+	if isResponse(request) {
+		if stream := s.sessionStream(sessionID, r); stream != nil && stream.elicitor.deliver(request) {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		http.Error(w, "No request awaits this response", http.StatusNotFound)
+		return
+	}
+
 	method, _ := request["method"].(string)
 	var response map[string]interface{}
 
 	switch method {
 	case "initialize":
 		response = s.handleInitialize(request)
+		if sessionID == "" {
+			w.Header().Set(SessionHeader, uuid.New().String())
+		} else if stream := s.sessionStream(sessionID, r); stream != nil {
+			// Forms can only be sent to clients with an SSE connection
+			s.mu.Lock()
+			stream.elicits = clientElicits(request)
+			s.mu.Unlock()
+		}
 	case "tools/list":
 		response = s.handleListTools()
 	case "tools/call":
-		response = s.handleToolCall(request, r)
+		response = s.handleToolCall(request, r, sessionID)
 	default:
 		response = map[string]interface{}{
 			"jsonrpc": "2.0",
@@ -96,7 +240,7 @@ func (s *SSEServer) HandleMessage(w http.ResponseWriter, r *http.Request) {
 func (s *SSEServer) handleInitialize(request map[string]interface{}) map[string]interface{} {
 	return map[string]interface{}{
 		"result": map[string]interface{}{
-			"protocolVersion": "2024-11-05",
+			"protocolVersion": negotiateProtocolVersion(request),
 			"capabilities": map[string]interface{}{
 				"tools": map[string]interface{}{},
 			},
@@ -111,12 +255,12 @@ func (s *SSEServer) handleInitialize(request map[string]interface{}) map[string]
 func (s *SSEServer) handleListTools() map[string]interface{} {
 	return map[string]interface{}{
 		"result": map[string]interface{}{
-			"tools": s.server.tools,
+			"tools": s.server.Tools(),
 		},
 	}
 }
 
-func (s *SSEServer) handleToolCall(request map[string]interface{}, r *http.Request) map[string]interface{} {
+func (s *SSEServer) handleToolCall(request map[string]interface{}, r *http.Request, sessionID string) map[string]interface{} {
 	params, ok := request["params"].(map[string]interface{})
 	if !ok {
 		return map[string]interface{}{
@@ -133,12 +277,32 @@ func (s *SSEServer) handleToolCall(request map[string]interface{}, r *http.Reque
 	toolCall := ToolCall{
 		Name:      name,
 		Arguments: arguments,
+		RequestID: logging.RequestIDFromContext(r.Context()),
+		SessionID: sessionID,
 	}
 
 	// Extract userID from request context (set by auth middleware)
 	userID := ""
 	if userCtx, ok := auth.ExtractUserFromContext(r.Context()); ok {
 		userID = userCtx.UserID
+		toolCall.OrgID = userCtx.OrgID
+		toolCall.Scopes = userCtx.Scopes
+		if err := userCtx.CheckToolScope(name); err != nil {
+			return map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":    -32001,
+					"message": err.Error(),
+				},
+			}
+		}
+	}
+
+	if stream := s.sessionStream(sessionID, r); stream != nil {
+		s.mu.Lock()
+		if stream.elicits {
+			toolCall.Elicit = stream.elicitor.elicit
+		}
+		s.mu.Unlock()
 	}
 
 	result, err := s.handler(toolCall, userID)
@@ -147,6 +311,7 @@ func (s *SSEServer) handleToolCall(request map[string]interface{}, r *http.Reque
 			"error": map[string]interface{}{
 				"code":    -32000,
 				"message": err.Error(),
+				"data":    map[string]interface{}{"request_id": toolCall.RequestID},
 			},
 		}
 	}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// TruncateResult trims the text blocks of a result to maxBytes in total and appends a
+// warning block when anything was cut. maxBytes <= 0 disables the limit. It reports
+// whether the result was truncated.
+func TruncateResult(result ToolResult, maxBytes int) (ToolResult, bool) {
+	if maxBytes <= 0 {
+		return result, false
+	}
+	total := 0
+	for _, block := range result.Content {
+		total += len(block.Text)
+	}
+	if total <= maxBytes {
+		return result, false
+	}
+
+	remaining := maxBytes
+	content := make([]ContentBlock, 0, len(result.Content)+1)
+	for _, block := range result.Content {
+		if remaining <= 0 {
+			break
+		}
+		if len(block.Text) > remaining {
+			block.Text = truncateUTF8(block.Text, remaining)
+		}
+		remaining -= len(block.Text)
+		content = append(content, block)
+	}
+	content = append(content, ContentBlock{
+		Type: "text",
+		Text: fmt.Sprintf("⚠️ Response truncated: %d of %d bytes shown. Narrow the request (fewer results, more specific filters) to see the rest.",
+			maxBytes-remaining, total),
+	})
+	return ToolResult{Content: content, IsError: result.IsError}, true
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a multi-byte character
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
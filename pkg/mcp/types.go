@@ -6,12 +6,25 @@ type Tool struct {
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
 	InputType   string                 `json:"inputType,omitempty"`
+
+	// Version is the schema version of a tool registered under VersionedName, e.g. "v2".
+	// Versions after the first are separate tools, so existing callers keep their schema.
+	Version string `json:"version,omitempty"`
+	// Deprecated tells callers of an outdated version what to use instead; it is added
+	// to every result
+	Deprecated string `json:"deprecated,omitempty"`
 }
 
 // ToolCall represents a tool invocation request
 type ToolCall struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments"`
+	OrgID     string                 `json:"-"` // Caller's active organization, for team-shared workspaces
+	RequestID string                 `json:"-"` // Correlation ID of the HTTP request that carried the call
+	Scopes    []string               `json:"-"` // Caller's granted scopes, for tools that call other tools; nil = unrestricted
+	SessionID string                 `json:"-"` // MCP session the call was made in (see SessionValues); empty over REST
+	UserID    string                 `json:"-"` // Caller, in plugins served by Server.Start (see Plugin)
+	Elicit    ElicitFunc             `json:"-"` // Asks the user for input mid-call; nil when the client cannot
 }
 
 // ToolResult represents the result of a tool call
@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToolVersionSeparator separates a tool's name from its version in the names later
+// versions are registered under, e.g. jira_list_issues@v2
+const ToolVersionSeparator = "@"
+
+// VersionedName returns the name a version of a tool is registered under. The first
+// version ("") keeps the plain name.
+func VersionedName(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + ToolVersionSeparator + version
+}
+
+// BaseToolName returns a tool name without its version, e.g. jira_list_issues for
+// jira_list_issues@v2
+func BaseToolName(name string) string {
+	base, _, _ := strings.Cut(name, ToolVersionSeparator)
+	return base
+}
+
+// WithDeprecationWarning appends a deprecated tool's notice to a result
+func WithDeprecationWarning(result ToolResult, tool Tool) ToolResult {
+	if tool.Deprecated == "" {
+		return result
+	}
+	content := append(append([]ContentBlock{}, result.Content...), ContentBlock{
+		Type: "text",
+		Text: fmt.Sprintf("⚠️ %s is deprecated: %s", tool.Name, tool.Deprecated),
+	})
+	return ToolResult{Content: content, IsError: result.IsError}
+}
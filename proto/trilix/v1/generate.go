@@ -0,0 +1,4 @@
+// Package trilixv1 holds the code generated from trilix.proto
+package trilixv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative trilix/v1/trilix.proto
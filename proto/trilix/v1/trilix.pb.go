@@ -0,0 +1,964 @@
+// gRPC API of the Trilix MCP server, for internal platform services. It is served on
+// GRPC_PORT with mutual TLS and mirrors the REST endpoints: each call runs through the
+// same handlers, authentication and limits as the REST request noted next to it.
+//
+// Every call needs an "authorization: Bearer <token>" metadata entry, as on the REST
+// API. Service tokens act as the user in user_id when their allowlist permits it.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: trilix/v1/trilix.proto
+
+package trilixv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListToolsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListToolsRequest) Reset() {
+	*x = ListToolsRequest{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListToolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListToolsRequest) ProtoMessage() {}
+
+func (x *ListToolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListToolsRequest.ProtoReflect.Descriptor instead.
+func (*ListToolsRequest) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{0}
+}
+
+type ListToolsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tools         []*Tool                `protobuf:"bytes,1,rep,name=tools,proto3" json:"tools,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListToolsResponse) Reset() {
+	*x = ListToolsResponse{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListToolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListToolsResponse) ProtoMessage() {}
+
+func (x *ListToolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListToolsResponse.ProtoReflect.Descriptor instead.
+func (*ListToolsResponse) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListToolsResponse) GetTools() []*Tool {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
+type Tool struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description     string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	InputSchemaJson string                 `protobuf:"bytes,3,opt,name=input_schema_json,json=inputSchemaJson,proto3" json:"input_schema_json,omitempty"` // JSON Schema of the arguments
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Tool) Reset() {
+	*x = Tool{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tool) ProtoMessage() {}
+
+func (x *Tool) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tool.ProtoReflect.Descriptor instead.
+func (*Tool) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Tool) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tool) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Tool) GetInputSchemaJson() string {
+	if x != nil {
+		return x.InputSchemaJson
+	}
+	return ""
+}
+
+type CallToolRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                        // e.g. "jira_get_issue"
+	ArgumentsJson string                 `protobuf:"bytes,2,opt,name=arguments_json,json=argumentsJson,proto3" json:"arguments_json,omitempty"` // JSON object, e.g. {"workspace_id": "...", "issue_key": "PROJ-1"}
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                      // User a service token acts as
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CallToolRequest) Reset() {
+	*x = CallToolRequest{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallToolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallToolRequest) ProtoMessage() {}
+
+func (x *CallToolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallToolRequest.ProtoReflect.Descriptor instead.
+func (*CallToolRequest) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CallToolRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CallToolRequest) GetArgumentsJson() string {
+	if x != nil {
+		return x.ArgumentsJson
+	}
+	return ""
+}
+
+func (x *CallToolRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type CallToolResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ResultJson    string                 `protobuf:"bytes,1,opt,name=result_json,json=resultJson,proto3" json:"result_json,omitempty"` // The REST response body
+	PiiWarning    string                 `protobuf:"bytes,2,opt,name=pii_warning,json=piiWarning,proto3" json:"pii_warning,omitempty"` // Likely personal data found in the result (pii_scan: warn)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CallToolResponse) Reset() {
+	*x = CallToolResponse{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallToolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallToolResponse) ProtoMessage() {}
+
+func (x *CallToolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallToolResponse.ProtoReflect.Descriptor instead.
+func (*CallToolResponse) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CallToolResponse) GetResultJson() string {
+	if x != nil {
+		return x.ResultJson
+	}
+	return ""
+}
+
+func (x *CallToolResponse) GetPiiWarning() string {
+	if x != nil {
+		return x.PiiWarning
+	}
+	return ""
+}
+
+type ListWorkspacesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"` // Include soft-deleted workspaces that can be restored
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListWorkspacesRequest) Reset() {
+	*x = ListWorkspacesRequest{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspacesRequest) ProtoMessage() {}
+
+func (x *ListWorkspacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkspacesRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkspacesRequest) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListWorkspacesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListWorkspacesRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type ListWorkspacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workspaces    []*Workspace           `protobuf:"bytes,1,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkspacesResponse) Reset() {
+	*x = ListWorkspacesResponse{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspacesResponse) ProtoMessage() {}
+
+func (x *ListWorkspacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkspacesResponse.ProtoReflect.Descriptor instead.
+func (*ListWorkspacesResponse) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListWorkspacesResponse) GetWorkspaces() []*Workspace {
+	if x != nil {
+		return x.Workspaces
+	}
+	return nil
+}
+
+type Workspace struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	WorkspaceName string                 `protobuf:"bytes,2,opt,name=workspace_name,json=workspaceName,proto3" json:"workspace_name,omitempty"`
+	SiteUrl       string                 `protobuf:"bytes,3,opt,name=site_url,json=siteUrl,proto3" json:"site_url,omitempty"`
+	JiraUrl       string                 `protobuf:"bytes,4,opt,name=jira_url,json=jiraUrl,proto3" json:"jira_url,omitempty"`                   // Set when Jira does not live at site_url
+	ConfluenceUrl string                 `protobuf:"bytes,5,opt,name=confluence_url,json=confluenceUrl,proto3" json:"confluence_url,omitempty"` // Set when Confluence does not live at site_url
+	Email         string                 `protobuf:"bytes,6,opt,name=email,proto3" json:"email,omitempty"`
+	Shared        bool                   `protobuf:"varint,7,opt,name=shared,proto3" json:"shared,omitempty"`                       // Owned by the caller's organization
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC 3339
+	UpdatedAt     string                 `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DeletedAt     string                 `protobuf:"bytes,10,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`    // Set for soft-deleted workspaces
+	PolicyJson    string                 `protobuf:"bytes,11,opt,name=policy_json,json=policyJson,proto3" json:"policy_json,omitempty"` // Workspace policy, as in the REST API
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Workspace) Reset() {
+	*x = Workspace{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Workspace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workspace) ProtoMessage() {}
+
+func (x *Workspace) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workspace.ProtoReflect.Descriptor instead.
+func (*Workspace) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Workspace) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *Workspace) GetWorkspaceName() string {
+	if x != nil {
+		return x.WorkspaceName
+	}
+	return ""
+}
+
+func (x *Workspace) GetSiteUrl() string {
+	if x != nil {
+		return x.SiteUrl
+	}
+	return ""
+}
+
+func (x *Workspace) GetJiraUrl() string {
+	if x != nil {
+		return x.JiraUrl
+	}
+	return ""
+}
+
+func (x *Workspace) GetConfluenceUrl() string {
+	if x != nil {
+		return x.ConfluenceUrl
+	}
+	return ""
+}
+
+func (x *Workspace) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Workspace) GetShared() bool {
+	if x != nil {
+		return x.Shared
+	}
+	return false
+}
+
+func (x *Workspace) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Workspace) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Workspace) GetDeletedAt() string {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return ""
+}
+
+func (x *Workspace) GetPolicyJson() string {
+	if x != nil {
+		return x.PolicyJson
+	}
+	return ""
+}
+
+type CreateWorkspaceRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WorkspaceName  string                 `protobuf:"bytes,2,opt,name=workspace_name,json=workspaceName,proto3" json:"workspace_name,omitempty"` // Defaults to site_url
+	SiteUrl        string                 `protobuf:"bytes,3,opt,name=site_url,json=siteUrl,proto3" json:"site_url,omitempty"`
+	Email          string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	ApiToken       string                 `protobuf:"bytes,5,opt,name=api_token,json=apiToken,proto3" json:"api_token,omitempty"`
+	Shared         bool                   `protobuf:"varint,6,opt,name=shared,proto3" json:"shared,omitempty"` // Share with the caller's organization (org admins only)
+	PolicyJson     string                 `protobuf:"bytes,7,opt,name=policy_json,json=policyJson,proto3" json:"policy_json,omitempty"`
+	JiraUrl        string                 `protobuf:"bytes,8,opt,name=jira_url,json=jiraUrl,proto3" json:"jira_url,omitempty"`
+	ConfluenceUrl  string                 `protobuf:"bytes,9,opt,name=confluence_url,json=confluenceUrl,proto3" json:"confluence_url,omitempty"`
+	OpsgenieApiKey string                 `protobuf:"bytes,10,opt,name=opsgenie_api_key,json=opsgenieApiKey,proto3" json:"opsgenie_api_key,omitempty"`
+	ProxyUrl       string                 `protobuf:"bytes,11,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateWorkspaceRequest) Reset() {
+	*x = CreateWorkspaceRequest{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorkspaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkspaceRequest) ProtoMessage() {}
+
+func (x *CreateWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*CreateWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateWorkspaceRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetWorkspaceName() string {
+	if x != nil {
+		return x.WorkspaceName
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetSiteUrl() string {
+	if x != nil {
+		return x.SiteUrl
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetApiToken() string {
+	if x != nil {
+		return x.ApiToken
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetShared() bool {
+	if x != nil {
+		return x.Shared
+	}
+	return false
+}
+
+func (x *CreateWorkspaceRequest) GetPolicyJson() string {
+	if x != nil {
+		return x.PolicyJson
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetJiraUrl() string {
+	if x != nil {
+		return x.JiraUrl
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetConfluenceUrl() string {
+	if x != nil {
+		return x.ConfluenceUrl
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetOpsgenieApiKey() string {
+	if x != nil {
+		return x.OpsgenieApiKey
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
+type WorkspaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WorkspaceId   string                 `protobuf:"bytes,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkspaceRequest) Reset() {
+	*x = WorkspaceRequest{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceRequest) ProtoMessage() {}
+
+func (x *WorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*WorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WorkspaceRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *WorkspaceRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+type DeleteWorkspaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWorkspaceResponse) Reset() {
+	*x = DeleteWorkspaceResponse{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWorkspaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWorkspaceResponse) ProtoMessage() {}
+
+func (x *DeleteWorkspaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWorkspaceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{10}
+}
+
+type RestoreWorkspaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreWorkspaceResponse) Reset() {
+	*x = RestoreWorkspaceResponse{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreWorkspaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreWorkspaceResponse) ProtoMessage() {}
+
+func (x *RestoreWorkspaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreWorkspaceResponse.ProtoReflect.Descriptor instead.
+func (*RestoreWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{11}
+}
+
+type WorkspaceStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	Connected     bool                   `protobuf:"varint,2,opt,name=connected,proto3" json:"connected,omitempty"` // The workspace's credentials work
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`          // Why they do not
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkspaceStatus) Reset() {
+	*x = WorkspaceStatus{}
+	mi := &file_trilix_v1_trilix_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceStatus) ProtoMessage() {}
+
+func (x *WorkspaceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_trilix_v1_trilix_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceStatus.ProtoReflect.Descriptor instead.
+func (*WorkspaceStatus) Descriptor() ([]byte, []int) {
+	return file_trilix_v1_trilix_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WorkspaceStatus) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *WorkspaceStatus) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *WorkspaceStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_trilix_v1_trilix_proto protoreflect.FileDescriptor
+
+const file_trilix_v1_trilix_proto_rawDesc = "" +
+	"\n" +
+	"\x16trilix/v1/trilix.proto\x12\ttrilix.v1\"\x12\n" +
+	"\x10ListToolsRequest\":\n" +
+	"\x11ListToolsResponse\x12%\n" +
+	"\x05tools\x18\x01 \x03(\v2\x0f.trilix.v1.ToolR\x05tools\"h\n" +
+	"\x04Tool\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12*\n" +
+	"\x11input_schema_json\x18\x03 \x01(\tR\x0finputSchemaJson\"e\n" +
+	"\x0fCallToolRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12%\n" +
+	"\x0earguments_json\x18\x02 \x01(\tR\rargumentsJson\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"T\n" +
+	"\x10CallToolResponse\x12\x1f\n" +
+	"\vresult_json\x18\x01 \x01(\tR\n" +
+	"resultJson\x12\x1f\n" +
+	"\vpii_warning\x18\x02 \x01(\tR\n" +
+	"piiWarning\"Y\n" +
+	"\x15ListWorkspacesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"N\n" +
+	"\x16ListWorkspacesResponse\x124\n" +
+	"\n" +
+	"workspaces\x18\x01 \x03(\v2\x14.trilix.v1.WorkspaceR\n" +
+	"workspaces\"\xde\x02\n" +
+	"\tWorkspace\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12%\n" +
+	"\x0eworkspace_name\x18\x02 \x01(\tR\rworkspaceName\x12\x19\n" +
+	"\bsite_url\x18\x03 \x01(\tR\asiteUrl\x12\x19\n" +
+	"\bjira_url\x18\x04 \x01(\tR\ajiraUrl\x12%\n" +
+	"\x0econfluence_url\x18\x05 \x01(\tR\rconfluenceUrl\x12\x14\n" +
+	"\x05email\x18\x06 \x01(\tR\x05email\x12\x16\n" +
+	"\x06shared\x18\a \x01(\bR\x06shared\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\tR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"deleted_at\x18\n" +
+	" \x01(\tR\tdeletedAt\x12\x1f\n" +
+	"\vpolicy_json\x18\v \x01(\tR\n" +
+	"policyJson\"\xe8\x02\n" +
+	"\x16CreateWorkspaceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0eworkspace_name\x18\x02 \x01(\tR\rworkspaceName\x12\x19\n" +
+	"\bsite_url\x18\x03 \x01(\tR\asiteUrl\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x1b\n" +
+	"\tapi_token\x18\x05 \x01(\tR\bapiToken\x12\x16\n" +
+	"\x06shared\x18\x06 \x01(\bR\x06shared\x12\x1f\n" +
+	"\vpolicy_json\x18\a \x01(\tR\n" +
+	"policyJson\x12\x19\n" +
+	"\bjira_url\x18\b \x01(\tR\ajiraUrl\x12%\n" +
+	"\x0econfluence_url\x18\t \x01(\tR\rconfluenceUrl\x12(\n" +
+	"\x10opsgenie_api_key\x18\n" +
+	" \x01(\tR\x0eopsgenieApiKey\x12\x1b\n" +
+	"\tproxy_url\x18\v \x01(\tR\bproxyUrl\"N\n" +
+	"\x10WorkspaceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fworkspace_id\x18\x02 \x01(\tR\vworkspaceId\"\x19\n" +
+	"\x17DeleteWorkspaceResponse\"\x1a\n" +
+	"\x18RestoreWorkspaceResponse\"h\n" +
+	"\x0fWorkspaceStatus\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x1c\n" +
+	"\tconnected\x18\x02 \x01(\bR\tconnected\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error2\x94\x01\n" +
+	"\x05Tools\x12F\n" +
+	"\tListTools\x12\x1b.trilix.v1.ListToolsRequest\x1a\x1c.trilix.v1.ListToolsResponse\x12C\n" +
+	"\bCallTool\x12\x1a.trilix.v1.CallToolRequest\x1a\x1b.trilix.v1.CallToolResponse2\xa8\x03\n" +
+	"\n" +
+	"Workspaces\x12U\n" +
+	"\x0eListWorkspaces\x12 .trilix.v1.ListWorkspacesRequest\x1a!.trilix.v1.ListWorkspacesResponse\x12J\n" +
+	"\x0fCreateWorkspace\x12!.trilix.v1.CreateWorkspaceRequest\x1a\x14.trilix.v1.Workspace\x12R\n" +
+	"\x0fDeleteWorkspace\x12\x1b.trilix.v1.WorkspaceRequest\x1a\".trilix.v1.DeleteWorkspaceResponse\x12T\n" +
+	"\x10RestoreWorkspace\x12\x1b.trilix.v1.WorkspaceRequest\x1a#.trilix.v1.RestoreWorkspaceResponse\x12M\n" +
+	"\x12GetWorkspaceStatus\x12\x1b.trilix.v1.WorkspaceRequest\x1a\x1a.trilix.v1.WorkspaceStatusBHZFgithub.com/providentiaww/trilix-atlassian-mcp/proto/trilix/v1;trilixv1b\x06proto3"
+
+var (
+	file_trilix_v1_trilix_proto_rawDescOnce sync.Once
+	file_trilix_v1_trilix_proto_rawDescData []byte
+)
+
+func file_trilix_v1_trilix_proto_rawDescGZIP() []byte {
+	file_trilix_v1_trilix_proto_rawDescOnce.Do(func() {
+		file_trilix_v1_trilix_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_trilix_v1_trilix_proto_rawDesc), len(file_trilix_v1_trilix_proto_rawDesc)))
+	})
+	return file_trilix_v1_trilix_proto_rawDescData
+}
+
+var file_trilix_v1_trilix_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_trilix_v1_trilix_proto_goTypes = []any{
+	(*ListToolsRequest)(nil),         // 0: trilix.v1.ListToolsRequest
+	(*ListToolsResponse)(nil),        // 1: trilix.v1.ListToolsResponse
+	(*Tool)(nil),                     // 2: trilix.v1.Tool
+	(*CallToolRequest)(nil),          // 3: trilix.v1.CallToolRequest
+	(*CallToolResponse)(nil),         // 4: trilix.v1.CallToolResponse
+	(*ListWorkspacesRequest)(nil),    // 5: trilix.v1.ListWorkspacesRequest
+	(*ListWorkspacesResponse)(nil),   // 6: trilix.v1.ListWorkspacesResponse
+	(*Workspace)(nil),                // 7: trilix.v1.Workspace
+	(*CreateWorkspaceRequest)(nil),   // 8: trilix.v1.CreateWorkspaceRequest
+	(*WorkspaceRequest)(nil),         // 9: trilix.v1.WorkspaceRequest
+	(*DeleteWorkspaceResponse)(nil),  // 10: trilix.v1.DeleteWorkspaceResponse
+	(*RestoreWorkspaceResponse)(nil), // 11: trilix.v1.RestoreWorkspaceResponse
+	(*WorkspaceStatus)(nil),          // 12: trilix.v1.WorkspaceStatus
+}
+var file_trilix_v1_trilix_proto_depIdxs = []int32{
+	2,  // 0: trilix.v1.ListToolsResponse.tools:type_name -> trilix.v1.Tool
+	7,  // 1: trilix.v1.ListWorkspacesResponse.workspaces:type_name -> trilix.v1.Workspace
+	0,  // 2: trilix.v1.Tools.ListTools:input_type -> trilix.v1.ListToolsRequest
+	3,  // 3: trilix.v1.Tools.CallTool:input_type -> trilix.v1.CallToolRequest
+	5,  // 4: trilix.v1.Workspaces.ListWorkspaces:input_type -> trilix.v1.ListWorkspacesRequest
+	8,  // 5: trilix.v1.Workspaces.CreateWorkspace:input_type -> trilix.v1.CreateWorkspaceRequest
+	9,  // 6: trilix.v1.Workspaces.DeleteWorkspace:input_type -> trilix.v1.WorkspaceRequest
+	9,  // 7: trilix.v1.Workspaces.RestoreWorkspace:input_type -> trilix.v1.WorkspaceRequest
+	9,  // 8: trilix.v1.Workspaces.GetWorkspaceStatus:input_type -> trilix.v1.WorkspaceRequest
+	1,  // 9: trilix.v1.Tools.ListTools:output_type -> trilix.v1.ListToolsResponse
+	4,  // 10: trilix.v1.Tools.CallTool:output_type -> trilix.v1.CallToolResponse
+	6,  // 11: trilix.v1.Workspaces.ListWorkspaces:output_type -> trilix.v1.ListWorkspacesResponse
+	7,  // 12: trilix.v1.Workspaces.CreateWorkspace:output_type -> trilix.v1.Workspace
+	10, // 13: trilix.v1.Workspaces.DeleteWorkspace:output_type -> trilix.v1.DeleteWorkspaceResponse
+	11, // 14: trilix.v1.Workspaces.RestoreWorkspace:output_type -> trilix.v1.RestoreWorkspaceResponse
+	12, // 15: trilix.v1.Workspaces.GetWorkspaceStatus:output_type -> trilix.v1.WorkspaceStatus
+	9,  // [9:16] is the sub-list for method output_type
+	2,  // [2:9] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_trilix_v1_trilix_proto_init() }
+func file_trilix_v1_trilix_proto_init() {
+	if File_trilix_v1_trilix_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_trilix_v1_trilix_proto_rawDesc), len(file_trilix_v1_trilix_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_trilix_v1_trilix_proto_goTypes,
+		DependencyIndexes: file_trilix_v1_trilix_proto_depIdxs,
+		MessageInfos:      file_trilix_v1_trilix_proto_msgTypes,
+	}.Build()
+	File_trilix_v1_trilix_proto = out.File
+	file_trilix_v1_trilix_proto_goTypes = nil
+	file_trilix_v1_trilix_proto_depIdxs = nil
+}
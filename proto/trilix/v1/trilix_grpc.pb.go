@@ -0,0 +1,444 @@
+// gRPC API of the Trilix MCP server, for internal platform services. It is served on
+// GRPC_PORT with mutual TLS and mirrors the REST endpoints: each call runs through the
+// same handlers, authentication and limits as the REST request noted next to it.
+//
+// Every call needs an "authorization: Bearer <token>" metadata entry, as on the REST
+// API. Service tokens act as the user in user_id when their allowlist permits it.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: trilix/v1/trilix.proto
+
+package trilixv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Tools_ListTools_FullMethodName = "/trilix.v1.Tools/ListTools"
+	Tools_CallTool_FullMethodName  = "/trilix.v1.Tools/CallTool"
+)
+
+// ToolsClient is the client API for Tools service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Tools runs the MCP tools
+type ToolsClient interface {
+	// The tools this server offers (tools/list)
+	ListTools(ctx context.Context, in *ListToolsRequest, opts ...grpc.CallOption) (*ListToolsResponse, error)
+	// POST /api/tools/{name}
+	CallTool(ctx context.Context, in *CallToolRequest, opts ...grpc.CallOption) (*CallToolResponse, error)
+}
+
+type toolsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewToolsClient(cc grpc.ClientConnInterface) ToolsClient {
+	return &toolsClient{cc}
+}
+
+func (c *toolsClient) ListTools(ctx context.Context, in *ListToolsRequest, opts ...grpc.CallOption) (*ListToolsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListToolsResponse)
+	err := c.cc.Invoke(ctx, Tools_ListTools_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toolsClient) CallTool(ctx context.Context, in *CallToolRequest, opts ...grpc.CallOption) (*CallToolResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CallToolResponse)
+	err := c.cc.Invoke(ctx, Tools_CallTool_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ToolsServer is the server API for Tools service.
+// All implementations must embed UnimplementedToolsServer
+// for forward compatibility.
+//
+// Tools runs the MCP tools
+type ToolsServer interface {
+	// The tools this server offers (tools/list)
+	ListTools(context.Context, *ListToolsRequest) (*ListToolsResponse, error)
+	// POST /api/tools/{name}
+	CallTool(context.Context, *CallToolRequest) (*CallToolResponse, error)
+	mustEmbedUnimplementedToolsServer()
+}
+
+// UnimplementedToolsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedToolsServer struct{}
+
+func (UnimplementedToolsServer) ListTools(context.Context, *ListToolsRequest) (*ListToolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTools not implemented")
+}
+func (UnimplementedToolsServer) CallTool(context.Context, *CallToolRequest) (*CallToolResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CallTool not implemented")
+}
+func (UnimplementedToolsServer) mustEmbedUnimplementedToolsServer() {}
+func (UnimplementedToolsServer) testEmbeddedByValue()               {}
+
+// UnsafeToolsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ToolsServer will
+// result in compilation errors.
+type UnsafeToolsServer interface {
+	mustEmbedUnimplementedToolsServer()
+}
+
+func RegisterToolsServer(s grpc.ServiceRegistrar, srv ToolsServer) {
+	// If the following call pancis, it indicates UnimplementedToolsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Tools_ServiceDesc, srv)
+}
+
+func _Tools_ListTools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListToolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToolsServer).ListTools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tools_ListTools_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToolsServer).ListTools(ctx, req.(*ListToolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tools_CallTool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallToolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToolsServer).CallTool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tools_CallTool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToolsServer).CallTool(ctx, req.(*CallToolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Tools_ServiceDesc is the grpc.ServiceDesc for Tools service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Tools_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "trilix.v1.Tools",
+	HandlerType: (*ToolsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTools",
+			Handler:    _Tools_ListTools_Handler,
+		},
+		{
+			MethodName: "CallTool",
+			Handler:    _Tools_CallTool_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "trilix/v1/trilix.proto",
+}
+
+const (
+	Workspaces_ListWorkspaces_FullMethodName     = "/trilix.v1.Workspaces/ListWorkspaces"
+	Workspaces_CreateWorkspace_FullMethodName    = "/trilix.v1.Workspaces/CreateWorkspace"
+	Workspaces_DeleteWorkspace_FullMethodName    = "/trilix.v1.Workspaces/DeleteWorkspace"
+	Workspaces_RestoreWorkspace_FullMethodName   = "/trilix.v1.Workspaces/RestoreWorkspace"
+	Workspaces_GetWorkspaceStatus_FullMethodName = "/trilix.v1.Workspaces/GetWorkspaceStatus"
+)
+
+// WorkspacesClient is the client API for Workspaces service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Workspaces manages the caller's Atlassian workspaces. It needs the
+// workspaces:manage scope.
+type WorkspacesClient interface {
+	// GET /api/workspaces
+	ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest, opts ...grpc.CallOption) (*ListWorkspacesResponse, error)
+	// POST /api/workspaces; the API token is checked against Atlassian first
+	CreateWorkspace(ctx context.Context, in *CreateWorkspaceRequest, opts ...grpc.CallOption) (*Workspace, error)
+	// DELETE /api/workspaces/{id}; workspaces can be restored until they are purged
+	DeleteWorkspace(ctx context.Context, in *WorkspaceRequest, opts ...grpc.CallOption) (*DeleteWorkspaceResponse, error)
+	// POST /api/workspaces/{id}/restore
+	RestoreWorkspace(ctx context.Context, in *WorkspaceRequest, opts ...grpc.CallOption) (*RestoreWorkspaceResponse, error)
+	// GET /api/workspaces/{id}/status
+	GetWorkspaceStatus(ctx context.Context, in *WorkspaceRequest, opts ...grpc.CallOption) (*WorkspaceStatus, error)
+}
+
+type workspacesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkspacesClient(cc grpc.ClientConnInterface) WorkspacesClient {
+	return &workspacesClient{cc}
+}
+
+func (c *workspacesClient) ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest, opts ...grpc.CallOption) (*ListWorkspacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkspacesResponse)
+	err := c.cc.Invoke(ctx, Workspaces_ListWorkspaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspacesClient) CreateWorkspace(ctx context.Context, in *CreateWorkspaceRequest, opts ...grpc.CallOption) (*Workspace, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Workspace)
+	err := c.cc.Invoke(ctx, Workspaces_CreateWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspacesClient) DeleteWorkspace(ctx context.Context, in *WorkspaceRequest, opts ...grpc.CallOption) (*DeleteWorkspaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWorkspaceResponse)
+	err := c.cc.Invoke(ctx, Workspaces_DeleteWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspacesClient) RestoreWorkspace(ctx context.Context, in *WorkspaceRequest, opts ...grpc.CallOption) (*RestoreWorkspaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreWorkspaceResponse)
+	err := c.cc.Invoke(ctx, Workspaces_RestoreWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspacesClient) GetWorkspaceStatus(ctx context.Context, in *WorkspaceRequest, opts ...grpc.CallOption) (*WorkspaceStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkspaceStatus)
+	err := c.cc.Invoke(ctx, Workspaces_GetWorkspaceStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorkspacesServer is the server API for Workspaces service.
+// All implementations must embed UnimplementedWorkspacesServer
+// for forward compatibility.
+//
+// Workspaces manages the caller's Atlassian workspaces. It needs the
+// workspaces:manage scope.
+type WorkspacesServer interface {
+	// GET /api/workspaces
+	ListWorkspaces(context.Context, *ListWorkspacesRequest) (*ListWorkspacesResponse, error)
+	// POST /api/workspaces; the API token is checked against Atlassian first
+	CreateWorkspace(context.Context, *CreateWorkspaceRequest) (*Workspace, error)
+	// DELETE /api/workspaces/{id}; workspaces can be restored until they are purged
+	DeleteWorkspace(context.Context, *WorkspaceRequest) (*DeleteWorkspaceResponse, error)
+	// POST /api/workspaces/{id}/restore
+	RestoreWorkspace(context.Context, *WorkspaceRequest) (*RestoreWorkspaceResponse, error)
+	// GET /api/workspaces/{id}/status
+	GetWorkspaceStatus(context.Context, *WorkspaceRequest) (*WorkspaceStatus, error)
+	mustEmbedUnimplementedWorkspacesServer()
+}
+
+// UnimplementedWorkspacesServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWorkspacesServer struct{}
+
+func (UnimplementedWorkspacesServer) ListWorkspaces(context.Context, *ListWorkspacesRequest) (*ListWorkspacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkspaces not implemented")
+}
+func (UnimplementedWorkspacesServer) CreateWorkspace(context.Context, *CreateWorkspaceRequest) (*Workspace, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorkspace not implemented")
+}
+func (UnimplementedWorkspacesServer) DeleteWorkspace(context.Context, *WorkspaceRequest) (*DeleteWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWorkspace not implemented")
+}
+func (UnimplementedWorkspacesServer) RestoreWorkspace(context.Context, *WorkspaceRequest) (*RestoreWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreWorkspace not implemented")
+}
+func (UnimplementedWorkspacesServer) GetWorkspaceStatus(context.Context, *WorkspaceRequest) (*WorkspaceStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaceStatus not implemented")
+}
+func (UnimplementedWorkspacesServer) mustEmbedUnimplementedWorkspacesServer() {}
+func (UnimplementedWorkspacesServer) testEmbeddedByValue()                    {}
+
+// UnsafeWorkspacesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WorkspacesServer will
+// result in compilation errors.
+type UnsafeWorkspacesServer interface {
+	mustEmbedUnimplementedWorkspacesServer()
+}
+
+func RegisterWorkspacesServer(s grpc.ServiceRegistrar, srv WorkspacesServer) {
+	// If the following call pancis, it indicates UnimplementedWorkspacesServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Workspaces_ServiceDesc, srv)
+}
+
+func _Workspaces_ListWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkspacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspacesServer).ListWorkspaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Workspaces_ListWorkspaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspacesServer).ListWorkspaces(ctx, req.(*ListWorkspacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workspaces_CreateWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspacesServer).CreateWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Workspaces_CreateWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspacesServer).CreateWorkspace(ctx, req.(*CreateWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workspaces_DeleteWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspacesServer).DeleteWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Workspaces_DeleteWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspacesServer).DeleteWorkspace(ctx, req.(*WorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workspaces_RestoreWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspacesServer).RestoreWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Workspaces_RestoreWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspacesServer).RestoreWorkspace(ctx, req.(*WorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workspaces_GetWorkspaceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspacesServer).GetWorkspaceStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Workspaces_GetWorkspaceStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspacesServer).GetWorkspaceStatus(ctx, req.(*WorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Workspaces_ServiceDesc is the grpc.ServiceDesc for Workspaces service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Workspaces_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "trilix.v1.Workspaces",
+	HandlerType: (*WorkspacesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListWorkspaces",
+			Handler:    _Workspaces_ListWorkspaces_Handler,
+		},
+		{
+			MethodName: "CreateWorkspace",
+			Handler:    _Workspaces_CreateWorkspace_Handler,
+		},
+		{
+			MethodName: "DeleteWorkspace",
+			Handler:    _Workspaces_DeleteWorkspace_Handler,
+		},
+		{
+			MethodName: "RestoreWorkspace",
+			Handler:    _Workspaces_RestoreWorkspace_Handler,
+		},
+		{
+			MethodName: "GetWorkspaceStatus",
+			Handler:    _Workspaces_GetWorkspaceStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "trilix/v1/trilix.proto",
+}